@@ -209,6 +209,7 @@ type AllocationMetric struct {
 	AllocationTime    time.Duration
 	CoalescedFailures int
 	ScoreMetaData     []*NodeScoreMeta
+	GangFailures      []string
 }
 
 // NodeScoreMeta is used to serialize node scoring metadata
@@ -317,10 +318,12 @@ type AllocatedResources struct {
 }
 
 type AllocatedTaskResources struct {
-	Cpu      AllocatedCpuResources
-	Memory   AllocatedMemoryResources
-	Networks []*NetworkResource
-	Devices  []*AllocatedDeviceResource
+	Cpu       AllocatedCpuResources
+	Memory    AllocatedMemoryResources
+	Networks  []*NetworkResource
+	Devices   []*AllocatedDeviceResource
+	DiskIO    AllocatedDiskIOResources
+	Bandwidth AllocatedBandwidthResources
 }
 
 type AllocatedSharedResources struct {
@@ -345,6 +348,18 @@ type AllocatedMemoryResources struct {
 	MemoryMaxMB int64
 }
 
+type AllocatedDiskIOResources struct {
+	Device    string
+	ReadIOPS  int64
+	WriteIOPS int64
+	ReadMBps  int64
+	WriteMBps int64
+}
+
+type AllocatedBandwidthResources struct {
+	EgressMbits int64
+}
+
 type AllocatedDeviceResource struct {
 	Vendor    string
 	Type      string
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// PortForward forwards a TCP connection to a port of a running allocation.
+//
+// The parameters are:
+//   - ctx: context to set deadlines or timeout
+//   - allocation: the allocation to forward the connection to
+//   - port: the label of the allocation's port to forward the connection to
+//   - conn: the connection to forward. Data read from it is sent to the port and
+//     data received from the port is written to it.
+//
+// The call blocks until the port closes the connection (or an error occurs).
+// The caller is responsible for closing conn once the call returns.
+func (a *Allocations) PortForward(ctx context.Context,
+	alloc *Allocation, port string, conn io.ReadWriter, q *QueryOptions) error {
+
+	s := &portForwardSession{
+		client: a.client,
+		alloc:  alloc,
+		port:   port,
+		conn:   conn,
+		q:      q,
+	}
+
+	return s.run(ctx)
+}
+
+type portForwardSession struct {
+	client *Client
+	alloc  *Allocation
+	port   string
+	conn   io.ReadWriter
+
+	q *QueryOptions
+}
+
+func (s *portForwardSession) run(ctx context.Context) error {
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+
+	ws, err := s.startConnection()
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	sendErrCh := s.startTransmit(ctx, ws)
+	recvErrCh := s.startReceiving(ctx, ws)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case recvErr := <-recvErrCh:
+		if websocket.IsCloseError(recvErr, websocket.CloseNormalClosure) {
+			return nil
+		}
+
+		// drop websocket code, not relevant to user
+		if wsErr, ok := recvErr.(*websocket.CloseError); ok && wsErr.Text != "" {
+			return errors.New(wsErr.Text)
+		}
+
+		return recvErr
+	case sendErr := <-sendErrCh:
+		return fmt.Errorf("failed to send data: %w", sendErr)
+	}
+}
+
+func (s *portForwardSession) startConnection() (*websocket.Conn, error) {
+	// First, attempt to connect to the node directly, but may fail due to network isolation
+	// and network errors.  Fallback to using server-side forwarding instead.
+	nodeClient, err := s.client.GetNodeClientWithTimeout(s.alloc.NodeID, ClientConnTimeout, s.q)
+	if err == NodeDownErr {
+		return nil, NodeDownErr
+	}
+
+	q := s.q
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+
+	q.Params["port"] = s.port
+
+	reqPath := fmt.Sprintf("/v1/client/allocation/%s/port-forward", s.alloc.ID)
+
+	var conn *websocket.Conn
+
+	if nodeClient != nil {
+		conn, _, _ = nodeClient.websocket(reqPath, q)
+	}
+
+	if conn == nil {
+		conn, _, err = s.client.websocket(reqPath, q)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (s *portForwardSession) startTransmit(ctx context.Context, ws *websocket.Conn) <-chan error {
+	errCh := make(chan error, 1)
+
+	// propagate the connection's data, an empty message signals that no more
+	// data will be sent
+	go func() {
+		bytes := make([]byte, 32*1024)
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			n, err := s.conn.Read(bytes)
+
+			// always send data if we read some
+			if n != 0 {
+				if err := ws.WriteMessage(websocket.BinaryMessage, bytes[:n]); err != nil {
+					errCh <- err
+					return
+				}
+			}
+
+			// then handle error
+			if err == io.EOF {
+				ws.WriteMessage(websocket.BinaryMessage, []byte{})
+				return
+			} else if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	// send a heartbeat every 10 seconds, as control messages so they aren't
+	// mistaken for data
+	go func() {
+		t := time.NewTimer(heartbeatInterval)
+		defer t.Stop()
+
+		for {
+			t.Reset(heartbeatInterval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(heartbeatInterval))
+			}
+		}
+	}()
+
+	return errCh
+}
+
+func (s *portForwardSession) startReceiving(ctx context.Context, ws *websocket.Conn) <-chan error {
+	errCh := make(chan error, 1)
+
+	go func() {
+		for ctx.Err() == nil {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				errCh <- err
+				return
+			}
+
+			if _, err := s.conn.Write(data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	return errCh
+}
@@ -11,6 +11,8 @@ const (
 	ConstraintSetContainsAny    = "set_contains_any"
 	ConstraintAttributeIsSet    = "is_set"
 	ConstraintAttributeIsNotSet = "is_not_set"
+	ConstraintJobAffinity       = "job_affinity"
+	ConstraintJobAntiAffinity   = "job_anti_affinity"
 )
 
 // Constraint is used to serialize a job placement constraint.
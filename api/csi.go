@@ -316,6 +316,10 @@ type CSIVolume struct {
 	// Allocations is a combined list of readers and writers
 	Allocations []*AllocationListStub
 
+	// Stats is a map of node IDs to the usage and condition of the volume
+	// most recently reported by the node plugin on that node.
+	Stats map[string]*CSIVolumeStats
+
 	// Schedulable is true if all the denormalized plugin health fields are true
 	Schedulable         bool
 	PluginID            string `mapstructure:"plugin_id" hcl:"plugin_id"`
@@ -335,6 +339,30 @@ type CSIVolume struct {
 	ExtraKeysHCL []string `hcl1:",unusedKeys" json:"-"`
 }
 
+// CSIVolumeStats is the usage and condition of a volume as reported by the
+// node plugin on a node where the volume is mounted. Usage values are zero if
+// they were not reported by the plugin.
+type CSIVolumeStats struct {
+	VolumeID  string
+	Namespace string
+	PluginID  string
+	NodeID    string
+
+	AvailableBytes  int64
+	TotalBytes      int64
+	UsedBytes       int64
+	AvailableInodes int64
+	TotalInodes     int64
+	UsedInodes      int64
+
+	// Abnormal and Message are the volume condition, which is only
+	// reported by plugins that support it
+	Abnormal bool
+	Message  string
+
+	UpdateTime time.Time
+}
+
 // CSIVolumeCapability is a requested attachment and access mode for a
 // volume
 type CSIVolumeCapability struct {
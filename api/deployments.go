@@ -107,6 +107,24 @@ func (d *Deployments) PromoteGroups(deploymentID string, groups []string, q *Wri
 	return &resp, wm, nil
 }
 
+// PromoteStep is used to promote the canaries in the passed groups in the
+// given deployment, or their current rollout step if their canaries are
+// already promoted. All the groups are promoted if none are passed.
+func (d *Deployments) PromoteStep(deploymentID string, groups []string, q *WriteOptions) (*DeploymentUpdateResponse, *WriteMeta, error) {
+	var resp DeploymentUpdateResponse
+	req := &DeploymentPromoteRequest{
+		DeploymentID: deploymentID,
+		All:          len(groups) == 0,
+		Groups:       groups,
+		Step:         true,
+	}
+	wm, err := d.client.write("/v1/deployment/promote/"+deploymentID, req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Unblock is used to unblock the given deployment.
 func (d *Deployments) Unblock(deploymentID string, q *WriteOptions) (*DeploymentUpdateResponse, *WriteMeta, error) {
 	var resp DeploymentUpdateResponse
@@ -193,6 +211,8 @@ type DeploymentState struct {
 	PlacedAllocs      int
 	HealthyAllocs     int
 	UnhealthyAllocs   int
+	RolloutSteps      []*RolloutStep
+	CurrentStep       int
 }
 
 // DeploymentIndexSort is a wrapper to sort deployments by CreateIndex. We
@@ -247,6 +267,10 @@ type DeploymentPromoteRequest struct {
 	// Groups is used to set the promotion status per task group
 	Groups []string
 
+	// Step is used to promote the current rollout step of the task groups
+	// whose canaries are promoted
+	Step bool
+
 	WriteRequest
 }
 
@@ -52,6 +52,17 @@ func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*Allocation
 	return resp, qm, nil
 }
 
+// Queues is used to query the share and backlog of the fair-share queues
+// evaluations are dequeued from.
+func (e *Evaluations) Queues(q *QueryOptions) (*EvalQueuesResponse, *QueryMeta, error) {
+	var resp EvalQueuesResponse
+	qm, err := e.client.query("/v1/evaluations/queues", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Evaluation is used to serialize an evaluation.
 type Evaluation struct {
 	ID                   string
@@ -123,3 +134,24 @@ func (e EvalIndexSort) Less(i, j int) bool {
 func (e EvalIndexSort) Swap(i, j int) {
 	e[i], e[j] = e[j], e[i]
 }
+
+// EvalQueuesResponse is used to serialize the fair-share queues of the
+// evaluation broker.
+type EvalQueuesResponse struct {
+	// Enabled is whether evaluations are dequeued by fair share
+	Enabled bool
+	Queues  []*EvalQueueStats
+
+	QueryMeta
+}
+
+// EvalQueueStats is the share and backlog of a fair-share queue.
+type EvalQueueStats struct {
+	Name     string
+	Weight   int
+	Share    float64
+	Ready    int
+	Unacked  int
+	Blocked  int
+	Dequeued uint64
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -11,15 +12,56 @@ import (
 )
 
 const (
-	TopicDeployment Topic = "Deployment"
-	TopicEvaluation Topic = "Evaluation"
-	TopicAllocation Topic = "Allocation"
-	TopicJob        Topic = "Job"
-	TopicNode       Topic = "Node"
-	TopicService    Topic = "Service"
-	TopicAll        Topic = "*"
+	TopicDeployment    Topic = "Deployment"
+	TopicEvaluation    Topic = "Evaluation"
+	TopicAllocation    Topic = "Allocation"
+	TopicJob           Topic = "Job"
+	TopicNode          Topic = "Node"
+	TopicService       Topic = "Service"
+	TopicCSIVolume     Topic = "CSIVolume"
+	TopicCSIPlugin     Topic = "CSIPlugin"
+	TopicNamespace     Topic = "Namespace"
+	TopicScalingPolicy Topic = "ScalingPolicy"
+	TopicAll           Topic = "*"
 )
 
+// ErrEventStreamResubscribe is matched, using errors.Is, by the error set on
+// the final Events of a stream when the events following the requested index
+// are no longer available on the server. The subscriber should resubscribe,
+// reconciling any state it derives from events.
+var ErrEventStreamResubscribe = errors.New("event stream resubscribe required")
+
+// EventStreamResubscribeError is the error set on the final Events of a
+// stream when the events following the requested index are no longer
+// available on the server.
+type EventStreamResubscribeError struct {
+	// Message is the reason given by the server.
+	Message string
+
+	// TruncatedIndex is the highest index for which events may be missing.
+	TruncatedIndex uint64
+}
+
+func (e *EventStreamResubscribeError) Error() string {
+	return e.Message
+}
+
+func (e *EventStreamResubscribeError) Is(target error) bool {
+	return target == ErrEventStreamResubscribe
+}
+
+// eventStreamFrame is an entry of the newline delimited JSON event stream.
+// An entry either holds the events for an index, or tells the subscriber to
+// resubscribe.
+type eventStreamFrame struct {
+	Index  uint64
+	Events []Event
+
+	Resubscribe    bool
+	Error          string
+	TruncatedIndex uint64
+}
+
 // Events is a set of events for a corresponding index. Events returned for the
 // index depend on which topics are subscribed to when a request is made.
 type Events struct {
@@ -102,13 +144,70 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// CSIVolume returns a CSIVolume struct from a given event payload. If the
+// Event Topic is CSIVolume this will return a valid CSIVolume. The volume
+// secrets are not included, and the allocations of any claims are nil.
+func (e *Event) CSIVolume() (*CSIVolume, error) {
+	// CSIVolume carries mapstructure tags for parsing volume specifications,
+	// so it can't be decoded by decodePayload and is round-tripped through
+	// JSON instead.
+	raw, ok := e.Payload["Volume"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var out CSIVolume
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CSIPlugin returns a CSIPlugin struct from a given event payload. If the
+// Event Topic is CSIPlugin this will return a valid CSIPlugin.
+func (e *Event) CSIPlugin() (*CSIPlugin, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Plugin, nil
+}
+
+// Namespace returns a Namespace struct from a given event payload. If the
+// Event Topic is Namespace this will return a valid Namespace.
+func (e *Event) Namespace() (*Namespace, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Namespace, nil
+}
+
+// ScalingPolicy returns a ScalingPolicy struct from a given event payload. If
+// the Event Topic is ScalingPolicy this will return a valid ScalingPolicy.
+func (e *Event) ScalingPolicy() (*ScalingPolicy, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.ScalingPolicy, nil
+}
+
 type eventPayload struct {
-	Allocation *Allocation          `mapstructure:"Allocation"`
-	Deployment *Deployment          `mapstructure:"Deployment"`
-	Evaluation *Evaluation          `mapstructure:"Evaluation"`
-	Job        *Job                 `mapstructure:"Job"`
-	Node       *Node                `mapstructure:"Node"`
-	Service    *ServiceRegistration `mapstructure:"Service"`
+	Allocation    *Allocation          `mapstructure:"Allocation"`
+	Deployment    *Deployment          `mapstructure:"Deployment"`
+	Evaluation    *Evaluation          `mapstructure:"Evaluation"`
+	Job           *Job                 `mapstructure:"Job"`
+	Node          *Node                `mapstructure:"Node"`
+	Service       *ServiceRegistration `mapstructure:"Service"`
+	Plugin        *CSIPlugin           `mapstructure:"Plugin"`
+	Namespace     *Namespace           `mapstructure:"Namespace"`
+	ScalingPolicy *ScalingPolicy       `mapstructure:"ScalingPolicy"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
@@ -147,7 +246,9 @@ func (c *Client) EventStream() *EventStream {
 }
 
 // Stream establishes a new subscription to Nomad's event stream and streams
-// results back to the returned channel.
+// results back to the returned channel. If the events following the index are
+// no longer available on the server, the final Events sent to the channel has
+// an Err matching ErrEventStreamResubscribe and the channel is closed.
 func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, index uint64, q *QueryOptions) (<-chan *Events, error) {
 	r, err := e.client.newRequest("GET", "/v1/event/stream")
 	if err != nil {
@@ -182,11 +283,19 @@ func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, ind
 
 		for ctx.Err() == nil {
 			// Decode next newline delimited json of events
+			var frame eventStreamFrame
 			var events Events
-			if err := dec.Decode(&events); err != nil {
+			if err := dec.Decode(&frame); err != nil {
 				// set error and fallthrough to
 				// select eventsCh
 				events = Events{Err: err}
+			} else if frame.Resubscribe {
+				events = Events{Err: &EventStreamResubscribeError{
+					Message:        frame.Error,
+					TruncatedIndex: frame.TruncatedIndex,
+				}}
+			} else {
+				events = Events{Index: frame.Index, Events: frame.Events}
 			}
 			if events.Err == nil && events.IsHeartbeat() {
 				continue
@@ -197,6 +306,12 @@ func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, ind
 				return
 			case eventsCh <- &events:
 			}
+
+			// The server ends the stream after asking the subscriber to
+			// resubscribe.
+			if frame.Resubscribe {
+				return
+			}
 		}
 	}()
 
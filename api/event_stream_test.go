@@ -3,6 +3,10 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -115,6 +119,58 @@ func TestEvent_Stream_CloseCtx(t *testing.T) {
 	}
 }
 
+func TestEvent_Stream_Resubscribe(t *testing.T) {
+	testutil.Parallel(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "10", r.URL.Query().Get("index"))
+		fmt.Fprintln(w, `{}`)
+		fmt.Fprintln(w, `{"Index":11,"Events":[{"Topic":"Job","Type":"JobRegistered","Key":"example","Index":11}]}`)
+		fmt.Fprintln(w, `{"Resubscribe":true,"Error":"client should resubscribe","TruncatedIndex":20}`)
+	}))
+	defer srv.Close()
+
+	conf := DefaultConfig()
+	conf.Address = srv.URL
+	c, err := NewClient(conf)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	streamCh, err := c.EventStream().Stream(ctx, map[Topic][]string{TopicAll: {"*"}}, 10, nil)
+	require.NoError(t, err)
+
+	var received []*Events
+	timeout := time.After(5 * time.Second)
+OUTER:
+	for {
+		select {
+		case events, ok := <-streamCh:
+			if !ok {
+				break OUTER
+			}
+			received = append(received, events)
+		case <-timeout:
+			require.Fail(t, "failed waiting for event stream to close")
+		}
+	}
+
+	// The events sent before the resubscribe entry are received, followed by
+	// the resubscribe error, after which the channel is closed.
+	require.Len(t, received, 2)
+	require.NoError(t, received[0].Err)
+	require.Equal(t, uint64(11), received[0].Index)
+	require.Len(t, received[0].Events, 1)
+
+	require.Error(t, received[1].Err)
+	require.True(t, errors.Is(received[1].Err, ErrEventStreamResubscribe))
+	var resubErr *EventStreamResubscribeError
+	require.True(t, errors.As(received[1].Err, &resubErr))
+	require.Equal(t, uint64(20), resubErr.TruncatedIndex)
+	require.Equal(t, "client should resubscribe", resubErr.Error())
+}
+
 func TestEventStream_PayloadValue(t *testing.T) {
 	testutil.Parallel(t)
 
@@ -270,6 +326,55 @@ func TestEventStream_PayloadValueHelpers(t *testing.T) {
 				require.Equal(t, "some-service-namespace-id", a.Namespace)
 			},
 		},
+		{
+			desc:  "csi volume",
+			input: []byte(`{"Topic": "CSIVolume", "Payload": {"Volume":{"ID":"some-volume-id","Namespace":"some-namespace-id","PluginID":"some-plugin-id","ReadAllocs":{"some-alloc-id":null}}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicCSIVolume, event.Topic)
+				v, err := event.CSIVolume()
+				require.NoError(t, err)
+				require.Equal(t, "some-volume-id", v.ID)
+				require.Equal(t, "some-namespace-id", v.Namespace)
+				require.Equal(t, "some-plugin-id", v.PluginID)
+				require.Contains(t, v.ReadAllocs, "some-alloc-id")
+			},
+		},
+		{
+			desc:  "csi plugin",
+			input: []byte(`{"Topic": "CSIPlugin", "Payload": {"Plugin":{"ID":"some-plugin-id","Provider":"some-provider"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicCSIPlugin, event.Topic)
+				p, err := event.CSIPlugin()
+				require.NoError(t, err)
+				require.Equal(t, "some-plugin-id", p.ID)
+				require.Equal(t, "some-provider", p.Provider)
+			},
+		},
+		{
+			desc:  "namespace",
+			input: []byte(`{"Topic": "Namespace", "Payload": {"Namespace":{"Name":"some-namespace","Description":"some description"}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicNamespace, event.Topic)
+				ns, err := event.Namespace()
+				require.NoError(t, err)
+				require.Equal(t, &Namespace{
+					Name:        "some-namespace",
+					Description: "some description",
+				}, ns)
+			},
+		},
+		{
+			desc:  "scaling policy",
+			input: []byte(`{"Topic": "ScalingPolicy", "Payload": {"ScalingPolicy":{"ID":"some-policy-id","Namespace":"some-namespace-id","Target":{"Job":"some-job-id"}}}}`),
+			expectFn: func(t *testing.T, event Event) {
+				require.Equal(t, TopicScalingPolicy, event.Topic)
+				p, err := event.ScalingPolicy()
+				require.NoError(t, err)
+				require.Equal(t, "some-policy-id", p.ID)
+				require.Equal(t, "some-namespace-id", p.Namespace)
+				require.Equal(t, "some-job-id", p.Target["Job"])
+			},
+		},
 	}
 
 	for _, tc := range testCases {
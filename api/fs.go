@@ -112,6 +112,39 @@ func (a *AllocFS) Cat(alloc *Allocation, path string, q *QueryOptions) (io.ReadC
 		})
 }
 
+// Download is used to read a tar archive of the file or directory at the given
+// path in an allocation directory. The entries of the archive are named
+// relative to the parent directory of the path.
+func (a *AllocFS) Download(alloc *Allocation, path string, q *QueryOptions) (io.ReadCloser, error) {
+	reqPath := fmt.Sprintf("/v1/client/fs/download/%s", alloc.ID)
+	return queryClientNode(a.client, alloc, reqPath, q,
+		func(q *QueryOptions) {
+			q.Params["path"] = path
+		})
+}
+
+// Upload is used to extract the tar archive read from the reader into the
+// directory at the given path in an allocation directory.
+func (a *AllocFS) Upload(alloc *Allocation, path string, archive io.Reader, q *WriteOptions) (*WriteMeta, error) {
+	r, err := a.client.newRequest("PUT", fmt.Sprintf("/v1/client/fs/upload/%s", alloc.ID))
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(q)
+	r.params.Set("path", path)
+	r.body = archive
+
+	rtt, resp, err := requireOK(a.client.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+	return wm, nil
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
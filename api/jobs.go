@@ -415,6 +415,16 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 	return &resp, qm, nil
 }
 
+// Dependencies is used to retrieve the status of the dependencies of a job.
+func (j *Jobs) Dependencies(jobID string, q *QueryOptions) ([]*JobDependencyStatus, *QueryMeta, error) {
+	var resp []*JobDependencyStatus
+	qm, err := j.client.query("/v1/job/"+url.PathEscape(jobID)+"/dependencies", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
 	var resp JobDispatchResponse
@@ -483,15 +493,17 @@ type periodicForceResponse struct {
 
 // UpdateStrategy defines a task groups update strategy.
 type UpdateStrategy struct {
-	Stagger          *time.Duration `mapstructure:"stagger" hcl:"stagger,optional"`
-	MaxParallel      *int           `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
-	HealthCheck      *string        `mapstructure:"health_check" hcl:"health_check,optional"`
-	MinHealthyTime   *time.Duration `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
-	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
-	ProgressDeadline *time.Duration `mapstructure:"progress_deadline" hcl:"progress_deadline,optional"`
-	Canary           *int           `mapstructure:"canary" hcl:"canary,optional"`
-	AutoRevert       *bool          `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
-	AutoPromote      *bool          `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	Stagger          *time.Duration    `mapstructure:"stagger" hcl:"stagger,optional"`
+	MaxParallel      *int              `mapstructure:"max_parallel" hcl:"max_parallel,optional"`
+	HealthCheck      *string           `mapstructure:"health_check" hcl:"health_check,optional"`
+	MinHealthyTime   *time.Duration    `mapstructure:"min_healthy_time" hcl:"min_healthy_time,optional"`
+	HealthyDeadline  *time.Duration    `mapstructure:"healthy_deadline" hcl:"healthy_deadline,optional"`
+	ProgressDeadline *time.Duration    `mapstructure:"progress_deadline" hcl:"progress_deadline,optional"`
+	Canary           *int              `mapstructure:"canary" hcl:"canary,optional"`
+	AutoRevert       *bool             `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
+	AutoPromote      *bool             `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	Analysis         *AnalysisStrategy `mapstructure:"analysis" hcl:"analysis,block"`
+	Steps            []*RolloutStep    `mapstructure:"step" hcl:"step,block"`
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -553,6 +565,15 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.AutoPromote = boolToPtr(*u.AutoPromote)
 	}
 
+	copy.Analysis = u.Analysis.Copy()
+
+	if u.Steps != nil {
+		copy.Steps = make([]*RolloutStep, len(u.Steps))
+		for i, s := range u.Steps {
+			copy.Steps[i] = s.Copy()
+		}
+	}
+
 	return copy
 }
 
@@ -596,6 +617,14 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 	if o.AutoPromote != nil {
 		u.AutoPromote = boolToPtr(*o.AutoPromote)
 	}
+
+	if o.Analysis != nil {
+		u.Analysis = o.Analysis.Copy()
+	}
+
+	if o.Steps != nil {
+		u.Steps = o.Copy().Steps
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -636,6 +665,14 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.AutoPromote == nil {
 		u.AutoPromote = d.AutoPromote
 	}
+
+	if u.Analysis != nil {
+		u.Analysis.Canonicalize()
+	}
+
+	for _, s := range u.Steps {
+		s.Canonicalize()
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -680,9 +717,170 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.Analysis != nil {
+		return false
+	}
+
+	if len(u.Steps) != 0 {
+		return false
+	}
+
 	return true
 }
 
+const (
+	// AnalysisSourcePrometheus queries the metrics of the allocations from a
+	// Prometheus compatible HTTP API.
+	AnalysisSourcePrometheus = "prometheus"
+
+	// AnalysisSourceNomad uses the resource usage of the allocations reported
+	// by the Nomad clients.
+	AnalysisSourceNomad = "nomad"
+)
+
+// AnalysisStrategy configures the analysis of the metrics of the canaries of
+// a deployment before they can be promoted.
+type AnalysisStrategy struct {
+	Source       *string           `mapstructure:"source" hcl:"source,optional"`
+	Address      *string           `mapstructure:"address" hcl:"address,optional"`
+	Interval     *time.Duration    `mapstructure:"interval" hcl:"interval,optional"`
+	BakeTime     *time.Duration    `mapstructure:"bake_time" hcl:"bake_time,optional"`
+	FailureLimit *int              `mapstructure:"failure_limit" hcl:"failure_limit,optional"`
+	Metrics      []*AnalysisMetric `mapstructure:"metric" hcl:"metric,block"`
+}
+
+func (a *AnalysisStrategy) Copy() *AnalysisStrategy {
+	if a == nil {
+		return nil
+	}
+
+	copy := new(AnalysisStrategy)
+	*copy = *a
+
+	if a.Source != nil {
+		copy.Source = stringToPtr(*a.Source)
+	}
+
+	if a.Address != nil {
+		copy.Address = stringToPtr(*a.Address)
+	}
+
+	if a.Interval != nil {
+		copy.Interval = timeToPtr(*a.Interval)
+	}
+
+	if a.BakeTime != nil {
+		copy.BakeTime = timeToPtr(*a.BakeTime)
+	}
+
+	if a.FailureLimit != nil {
+		copy.FailureLimit = intToPtr(*a.FailureLimit)
+	}
+
+	if a.Metrics != nil {
+		copy.Metrics = make([]*AnalysisMetric, len(a.Metrics))
+		for i, m := range a.Metrics {
+			copy.Metrics[i] = m.Copy()
+		}
+	}
+
+	return copy
+}
+
+func (a *AnalysisStrategy) Canonicalize() {
+	if a.Source == nil {
+		a.Source = stringToPtr(AnalysisSourcePrometheus)
+	}
+
+	if a.Address == nil {
+		a.Address = stringToPtr("")
+	}
+
+	if a.Interval == nil {
+		a.Interval = timeToPtr(30 * time.Second)
+	}
+
+	if a.BakeTime == nil {
+		a.BakeTime = timeToPtr(5 * time.Minute)
+	}
+
+	if a.FailureLimit == nil {
+		a.FailureLimit = intToPtr(0)
+	}
+}
+
+// AnalysisMetric is a metric compared between the canaries and the stable
+// allocations of a deployment.
+type AnalysisMetric struct {
+	Name        string   `hcl:",label"`
+	Query       string   `mapstructure:"query" hcl:"query,optional"`
+	Max         *float64 `mapstructure:"max" hcl:"max,optional"`
+	MaxIncrease *float64 `mapstructure:"max_increase" hcl:"max_increase,optional"`
+}
+
+func (m *AnalysisMetric) Copy() *AnalysisMetric {
+	if m == nil {
+		return nil
+	}
+
+	copy := new(AnalysisMetric)
+	*copy = *m
+
+	if m.Max != nil {
+		copy.Max = float64ToPtr(*m.Max)
+	}
+
+	if m.MaxIncrease != nil {
+		copy.MaxIncrease = float64ToPtr(*m.MaxIncrease)
+	}
+
+	return copy
+}
+
+// RolloutStep is a step of a progressive rollout, updating a percentage of
+// the allocations of a task group before pausing.
+type RolloutStep struct {
+	Percent *int           `mapstructure:"percent" hcl:"percent,optional"`
+	Pause   *time.Duration `mapstructure:"pause" hcl:"pause,optional"`
+	Manual  *bool          `mapstructure:"manual" hcl:"manual,optional"`
+}
+
+func (s *RolloutStep) Copy() *RolloutStep {
+	if s == nil {
+		return nil
+	}
+
+	copy := new(RolloutStep)
+
+	if s.Percent != nil {
+		copy.Percent = intToPtr(*s.Percent)
+	}
+
+	if s.Pause != nil {
+		copy.Pause = timeToPtr(*s.Pause)
+	}
+
+	if s.Manual != nil {
+		copy.Manual = boolToPtr(*s.Manual)
+	}
+
+	return copy
+}
+
+func (s *RolloutStep) Canonicalize() {
+	if s.Percent == nil {
+		s.Percent = intToPtr(0)
+	}
+
+	if s.Pause == nil {
+		s.Pause = timeToPtr(0)
+	}
+
+	if s.Manual == nil {
+		s.Manual = boolToPtr(false)
+	}
+}
+
 type Multiregion struct {
 	Strategy *MultiregionStrategy `hcl:"strategy,block"`
 	Regions  []*MultiregionRegion `hcl:"region,block"`
@@ -819,6 +1017,37 @@ func (p *PeriodicConfig) GetLocation() (*time.Location, error) {
 	return time.LoadLocation(*p.TimeZone)
 }
 
+const (
+	// JobDependencyConditionSuccess requires the upstream job to complete
+	// successfully.
+	JobDependencyConditionSuccess = "success"
+
+	// JobDependencyConditionComplete requires the upstream job to complete
+	// regardless of its outcome.
+	JobDependencyConditionComplete = "complete"
+)
+
+// JobDependency is a dependency of a job on the completion of another job.
+type JobDependency struct {
+	JobID     string `mapstructure:"job" hcl:"job,optional"`
+	Condition string `hcl:"condition,optional"`
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = JobDependencyConditionSuccess
+	}
+}
+
+// JobDependencyStatus is the status of a dependency of a job.
+type JobDependencyStatus struct {
+	JobID       string
+	Condition   string
+	Status      string
+	Satisfied   bool
+	Description string
+}
+
 // ParameterizedJobConfig is used to configure the parameterized job.
 type ParameterizedJobConfig struct {
 	Payload      string   `hcl:"payload,optional"`
@@ -837,6 +1066,8 @@ type Job struct {
 	Type             *string                 `hcl:"type,optional"`
 	Priority         *int                    `hcl:"priority,optional"`
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
+	Gang             *bool                   `hcl:"gang,optional"`
+	DependsOn        []*JobDependency        `hcl:"depends_on,block"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
 	Constraints      []*Constraint           `hcl:"constraint,block"`
 	Affinities       []*Affinity             `hcl:"affinity,block"`
@@ -957,6 +1188,9 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+	for _, d := range j.DependsOn {
+		d.Canonicalize()
+	}
 	if j.Update != nil {
 		j.Update.Canonicalize()
 	} else if *j.Type == JobTypeService {
@@ -427,6 +427,36 @@ func (n *Nodes) ToggleEligibility(nodeID string, eligible bool, q *WriteOptions)
 	return &resp, nil
 }
 
+// NodeMaintenanceRequest is used to update the maintenance windows and
+// capacity reservations of a node or of all the nodes of a node class.
+type NodeMaintenanceRequest struct {
+	NodeID             string
+	NodeClass          string
+	Windows            []*NodeMaintenanceWindow
+	Reservations       []*NodeCapacityReservation
+	DeleteWindows      []string
+	DeleteReservations []string
+}
+
+// NodeMaintenanceResponse is used to respond to a maintenance update
+type NodeMaintenanceResponse struct {
+	NodeIDs         []string
+	NodeModifyIndex uint64
+	WriteMeta
+}
+
+// UpdateMaintenance is used to update the maintenance windows and capacity
+// reservations of a node or of all the nodes of a node class.
+func (n *Nodes) UpdateMaintenance(req *NodeMaintenanceRequest, q *WriteOptions) (*NodeMaintenanceResponse, error) {
+	var resp NodeMaintenanceResponse
+	wm, err := n.client.write("/v1/nodes/maintenance", req, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
 // Allocations is used to return the allocations associated with a node.
 func (n *Nodes) Allocations(nodeID string, q *QueryOptions) ([]*Allocation, *QueryMeta, error) {
 	var resp []*Allocation
@@ -563,16 +593,49 @@ type Node struct {
 	CSIControllerPlugins  map[string]*CSIInfo
 	CSINodePlugins        map[string]*CSIInfo
 	LastDrain             *DrainMetadata
+	MaintenanceWindows    []*NodeMaintenanceWindow
+	CapacityReservations  []*NodeCapacityReservation
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
 
+const (
+	NodeMaintenanceWindowPending = "pending"
+	NodeMaintenanceWindowActive  = "active"
+)
+
+// NodeMaintenanceWindow is a scheduled maintenance of a node, during which
+// the node is drained.
+type NodeMaintenanceWindow struct {
+	ID               string
+	Start            time.Time
+	End              time.Time
+	Deadline         time.Duration
+	IgnoreSystemJobs bool
+	Description      string
+	Status           string
+	DrainStarted     bool
+	MarkEligible     bool
+}
+
+// NodeCapacityReservation holds resources of a node for an upcoming job.
+type NodeCapacityReservation struct {
+	ID          string
+	Namespace   string
+	JobID       string
+	CPU         int64
+	MemoryMB    int64
+	Expires     time.Time
+	Description string
+}
+
 type NodeResources struct {
 	Cpu      NodeCpuResources
 	Memory   NodeMemoryResources
 	Disk     NodeDiskResources
-	Networks []*NetworkResource
-	Devices  []*NodeDeviceResource
+	Networks  []*NetworkResource
+	Devices   []*NodeDeviceResource
+	Bandwidth NodeBandwidthResources
 
 	MinDynamicPort int
 	MaxDynamicPort int
@@ -582,6 +645,14 @@ type NodeCpuResources struct {
 	CpuShares          int64
 	TotalCpuCores      uint16
 	ReservableCpuCores []uint16
+	NumaNodes          []*NodeNumaNode
+}
+
+type NodeNumaNode struct {
+	ID       uint16
+	Cores    []uint16
+	Siblings [][]uint16
+	MemoryMB int64
 }
 
 type NodeMemoryResources struct {
@@ -590,6 +661,13 @@ type NodeMemoryResources struct {
 
 type NodeDiskResources struct {
 	DiskMB int64
+	IOPS   int64
+	MBps   int64
+	Device string
+}
+
+type NodeBandwidthResources struct {
+	EgressMbits int64
 }
 
 type NodeReservedResources struct {
@@ -779,6 +857,7 @@ type HostStats struct {
 	CPU              []*HostCPUStats
 	DiskStats        []*HostDiskStats
 	DeviceStats      []*DeviceGroupStats
+	CSIVolumeStats   []*CSIVolumeStats
 	Uptime           uint64
 	CPUTicksConsumed float64
 }
@@ -134,6 +134,10 @@ type SchedulerConfiguration struct {
 	// management ACL token
 	RejectJobRegistration bool
 
+	// RebalancerConfig specifies whether the rebalancer migrates allocations
+	// violating their spread or running on underutilized nodes.
+	RebalancerConfig RebalancerConfig
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -175,6 +179,12 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled  bool
 }
 
+// RebalancerConfig specifies whether the rebalancer is run
+type RebalancerConfig struct {
+	Enabled bool
+	DryRun  bool
+}
+
 // SchedulerGetConfiguration is used to query the current Scheduler configuration.
 func (op *Operator) SchedulerGetConfiguration(q *QueryOptions) (*SchedulerConfigurationResponse, *QueryMeta, error) {
 	var resp SchedulerConfigurationResponse
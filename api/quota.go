@@ -122,8 +122,9 @@ type QuotaLimit struct {
 
 	// RegionLimit is the quota limit that applies to any allocation within a
 	// referencing namespace in the region. A value of zero is treated as
-	// unlimited and a negative value is treated as fully disallowed. This is
-	// useful for once we support GPUs
+	// unlimited and a negative value is treated as fully disallowed. The CPU,
+	// MemoryMB, MemoryMaxMB and Devices resources may be limited, with device
+	// limits specified by device name and count.
 	RegionLimit *Resources
 
 	// Hash is the hash of the object and is used to make replication efficient.
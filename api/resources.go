@@ -14,6 +14,9 @@ type Resources struct {
 	DiskMB      *int               `mapstructure:"disk" hcl:"disk,optional"`
 	Networks    []*NetworkResource `hcl:"network,block"`
 	Devices     []*RequestedDevice `hcl:"device,block"`
+	NUMA        *NUMA              `hcl:"numa,block"`
+	DiskIO      *DiskIO            `mapstructure:"disk_io" hcl:"disk_io,block"`
+	EgressMbits *int               `mapstructure:"egress_mbits" hcl:"egress_mbits,optional"`
 
 	// COMPAT(0.10)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
@@ -47,6 +50,9 @@ func (r *Resources) Canonicalize() {
 	for _, d := range r.Devices {
 		d.Canonicalize()
 	}
+	if r.NUMA != nil {
+		r.NUMA.Canonicalize()
+	}
 }
 
 // DefaultResources is a small resources object that contains the
@@ -94,6 +100,15 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+	if other.DiskIO != nil {
+		r.DiskIO = other.DiskIO
+	}
+	if other.EgressMbits != nil {
+		r.EgressMbits = other.EgressMbits
+	}
 }
 
 type Port struct {
@@ -266,6 +281,28 @@ type RequestedDevice struct {
 	Affinities []*Affinity `hcl:"affinity,block"`
 }
 
+// NUMA configures how the reserved cores of a task are placed with regard to
+// the NUMA topology of the node.
+type NUMA struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string `hcl:"affinity,optional"`
+}
+
+func (n *NUMA) Canonicalize() {
+	if n.Affinity == "" {
+		n.Affinity = "prefer"
+	}
+}
+
+// DiskIO is the disk IO a task requires of the disk backing the allocation
+// directory of the node.
+type DiskIO struct {
+	ReadIOPS  *int `mapstructure:"read_iops" hcl:"read_iops,optional"`
+	WriteIOPS *int `mapstructure:"write_iops" hcl:"write_iops,optional"`
+	ReadMBps  *int `mapstructure:"read_mbps" hcl:"read_mbps,optional"`
+	WriteMBps *int `mapstructure:"write_mbps" hcl:"write_mbps,optional"`
+}
+
 func (d *RequestedDevice) Canonicalize() {
 	if d.Count == nil {
 		d.Count = uint64ToPtr(1)
@@ -56,10 +56,24 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the current status of the health checks defined within
+	// the service block, as executed by the Nomad client.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// ServiceRegistrationCheck is the status of an individual health check which
+// is part of a service registration.
+type ServiceRegistrationCheck struct {
+	ID     string
+	Name   string
+	Type   string
+	Status string
+	Output string
+}
+
 // ServiceRegistrationListStub represents all service registrations held within a
 // single namespace.
 type ServiceRegistrationListStub struct {
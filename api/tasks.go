@@ -257,6 +257,7 @@ func (p *ReschedulePolicy) String() string {
 type Spread struct {
 	Attribute    string          `hcl:"attribute,optional"`
 	Weight       *int8           `hcl:"weight,optional"`
+	MaxSkew      *int            `mapstructure:"max_skew" hcl:"max_skew,optional"`
 	SpreadTarget []*SpreadTarget `hcl:"target,block"`
 }
 
@@ -416,6 +417,7 @@ func (vm *VolumeMount) Canonicalize() {
 type TaskGroup struct {
 	Name                      *string                   `hcl:"name,label"`
 	Count                     *int                      `hcl:"count,optional"`
+	Gang                      *bool                     `hcl:"gang,optional"`
 	Constraints               []*Constraint             `hcl:"constraint,block"`
 	Affinities                []*Affinity               `hcl:"affinity,block"`
 	Tasks                     []*Task                   `hcl:"task,block"`
@@ -617,8 +619,9 @@ func (g *TaskGroup) AddSpread(s *Spread) *TaskGroup {
 
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
-	MaxFiles      *int `mapstructure:"max_files" hcl:"max_files,optional"`
-	MaxFileSizeMB *int `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	MaxFiles      *int       `mapstructure:"max_files" hcl:"max_files,optional"`
+	MaxFileSizeMB *int       `mapstructure:"max_file_size" hcl:"max_file_size,optional"`
+	Sinks         []*LogSink `mapstructure:"sink" hcl:"sink,block"`
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -635,6 +638,29 @@ func (l *LogConfig) Canonicalize() {
 	if l.MaxFileSizeMB == nil {
 		l.MaxFileSizeMB = intToPtr(10)
 	}
+	for _, s := range l.Sinks {
+		s.Canonicalize()
+	}
+}
+
+// LogSink is a destination a task's logs are forwarded to in addition to the
+// rotated log files.
+type LogSink struct {
+	Name       string `hcl:"name,label"`
+	Type       string `hcl:"type,optional"`
+	Address    string `hcl:"address,optional"`
+	Facility   string `hcl:"facility,optional"`
+	Tag        string `hcl:"tag,optional"`
+	BufferSize *int   `mapstructure:"buffer_size" hcl:"buffer_size,optional"`
+}
+
+func (s *LogSink) Canonicalize() {
+	if s.BufferSize == nil {
+		s.BufferSize = intToPtr(1024)
+	}
+	if s.Type == "syslog" && s.Facility == "" {
+		s.Facility = "user"
+	}
 }
 
 // DispatchPayloadConfig configures how a task gets its input from a job dispatch
@@ -969,6 +995,9 @@ const (
 	TaskLeaderDead             = "Leader Task Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
 	TaskClientReconnected      = "Reconnected"
+	TaskCheckpointed           = "Checkpointed"
+	TaskCheckpointRestored     = "Checkpoint Restored"
+	TaskResized                = "Resized"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -408,6 +408,29 @@ func TestTask_Canonicalize_TaskLifecycle(t *testing.T) {
 	}
 }
 
+func TestTask_Canonicalize_LogConfig(t *testing.T) {
+	testutil.Parallel(t)
+
+	task := &Task{
+		LogConfig: &LogConfig{
+			Sinks: []*LogSink{
+				{Name: "syslog", Type: "syslog"},
+				{Name: "collector", Type: "tcp", Address: "127.0.0.1:5170", BufferSize: intToPtr(10)},
+			},
+		},
+	}
+	task.Canonicalize(&TaskGroup{Name: stringToPtr("foo")}, &Job{ID: stringToPtr("test")})
+
+	require.Equal(t, &LogConfig{
+		MaxFiles:      intToPtr(10),
+		MaxFileSizeMB: intToPtr(10),
+		Sinks: []*LogSink{
+			{Name: "syslog", Type: "syslog", Facility: "user", BufferSize: intToPtr(1024)},
+			{Name: "collector", Type: "tcp", Address: "127.0.0.1:5170", BufferSize: intToPtr(10)},
+		},
+	}, task.LogConfig)
+}
+
 func TestTask_Template_WaitConfig_Canonicalize_and_Copy(t *testing.T) {
 	testutil.Parallel(t)
 	taskWithWait := func(wc *WaitConfig) *Task {
@@ -31,6 +31,11 @@ func int64ToPtr(i int64) *int64 {
 	return &i
 }
 
+// float64ToPtr returns the pointer to a float64
+func float64ToPtr(f float64) *float64 {
+	return &f
+}
+
 // stringToPtr returns the pointer to a string
 func stringToPtr(str string) *string {
 	return &str
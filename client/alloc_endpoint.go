@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
+	"strconv"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -27,6 +29,7 @@ type Allocations struct {
 func NewAllocationsEndpoint(c *Client) *Allocations {
 	a := &Allocations{c: c}
 	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	a.c.streamingRpcs.Register("Allocations.PortForward", a.portForward)
 	return a
 }
 
@@ -271,6 +274,232 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	return nil, nil
 }
 
+// portForwardDialTimeout is the maximum amount of time to wait when connecting
+// to the port of an allocation
+const portForwardDialTimeout = 10 * time.Second
+
+// portForwardSession describes a port forward session, which is recorded by
+// node events when it starts and ends for auditing.
+type portForwardSession struct {
+	id        string
+	allocID   string
+	port      string
+	tokenName string
+	tokenID   string
+
+	// started is set once the start of the session was recorded
+	started bool
+}
+
+// event returns a node event recording the port forward session
+func (s *portForwardSession) event(msg string) *nstructs.NodeEvent {
+	return nstructs.NewNodeEvent().
+		SetSubsystem(nstructs.NodeEventSubsystemPortForward).
+		SetMessage(msg).
+		AddDetail("session_id", s.id).
+		AddDetail("alloc_id", s.allocID).
+		AddDetail("port", s.port).
+		AddDetail("access_token_name", s.tokenName).
+		AddDetail("access_token_id", s.tokenID)
+}
+
+// portForward is used to forward a TCP connection to a port of a running
+// allocation
+func (a *Allocations) portForward(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "port_forward"}, time.Now())
+	defer conn.Close()
+
+	session := &portForwardSession{id: uuid.Generate()}
+	decoder := codec.NewDecoder(conn, nstructs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+
+	code, err := a.portForwardImpl(encoder, decoder, session)
+	if err != nil {
+		a.c.logger.Info("port forward session ended with an error",
+			"session_id", session.id, "error", err, "code", code)
+		if session.started {
+			a.c.triggerNodeEvent(session.event("Port forward session ended with an error").
+				AddDetail("error", err.Error()))
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	a.c.logger.Info("port forward session ended", "session_id", session.id)
+	if session.started {
+		a.c.triggerNodeEvent(session.event("Port forward session ended"))
+	}
+}
+
+func (a *Allocations) portForwardImpl(encoder *codec.Encoder, decoder *codec.Decoder, session *portForwardSession) (code *int64, err error) {
+
+	// Decode the arguments
+	var req cstructs.AllocPortForwardRequest
+	if err := decoder.Decode(&req); err != nil {
+		return helper.Int64ToPtr(500), err
+	}
+
+	if req.AllocID == "" {
+		return helper.Int64ToPtr(400), allocIDNotPresentErr
+	}
+	ar, err := a.c.getAllocRunner(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if nstructs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		return code, err
+	}
+	alloc := ar.Alloc()
+
+	aclObj, token, err := a.c.resolveTokenAndACL(req.QueryOptions.AuthToken)
+	{
+		// log and record access
+		session.allocID, session.port = req.AllocID, req.Port
+		if token != nil {
+			session.tokenName, session.tokenID = token.Name, token.AccessorID
+		}
+
+		a.c.logger.Info("port forward session starting",
+			"session_id", session.id,
+			"alloc_id", session.allocID,
+			"port", session.port,
+			"access_token_name", session.tokenName,
+			"access_token_id", session.tokenID,
+		)
+		a.c.triggerNodeEvent(session.event("Port forward session started"))
+		session.started = true
+	}
+
+	// Check alloc-port-forward permission.
+	if err != nil {
+		return nil, err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocPortForward) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	if req.Port == "" {
+		return helper.Int64ToPtr(400), errors.New("port label is not present")
+	}
+
+	allocState := ar.AllocState()
+	if allocState.ClientStatus != nstructs.AllocClientStatusRunning {
+		return helper.Int64ToPtr(404), fmt.Errorf("allocation %q is not running", req.AllocID)
+	}
+
+	addr, err := portForwardAddr(alloc, allocState.NetworkStatus, req.Port)
+	if err != nil {
+		return helper.Int64ToPtr(400), err
+	}
+
+	target, err := net.DialTimeout("tcp", addr, portForwardDialTimeout)
+	if err != nil {
+		return helper.Int64ToPtr(502), fmt.Errorf("failed to connect to port %q: %v", req.Port, err)
+	}
+	defer target.Close()
+
+	outCh := make(chan error, 1)
+	go func() {
+		outCh <- forwardPortOutput(target, encoder)
+	}()
+
+	inCh := make(chan error, 1)
+	go func() {
+		inCh <- forwardPortInput(decoder, target)
+	}()
+
+	select {
+	case err := <-outCh:
+		// The task closed the connection
+		return nil, err
+	case err := <-inCh:
+		// The caller closed the connection. Stop forwarding output before
+		// returning, so errors aren't encoded concurrently with it.
+		target.Close()
+		if outErr := <-outCh; err == nil {
+			err = outErr
+		}
+		return nil, err
+	}
+}
+
+// portForwardAddr returns the address to connect to for the port of the
+// allocation with the given label. Allocations in their own network namespace
+// are reached through the address of the namespace and the port the task
+// listens on, others through the host address of the port.
+func portForwardAddr(alloc *nstructs.Allocation, status *nstructs.AllocNetworkStatus, label string) (string, error) {
+	if alloc.AllocatedResources == nil {
+		return "", fmt.Errorf("unknown port %q", label)
+	}
+	port, ok := alloc.AllocatedResources.Shared.Ports.Get(label)
+	if !ok {
+		return "", fmt.Errorf("unknown port %q", label)
+	}
+
+	if status != nil && status.Address != "" {
+		to := port.To
+		if to <= 0 {
+			to = port.Value
+		}
+		return net.JoinHostPort(status.Address, strconv.Itoa(to)), nil
+	}
+
+	return net.JoinHostPort(port.HostIP, strconv.Itoa(port.Value)), nil
+}
+
+// forwardPortInput writes the payload of the frames read from the decoder to
+// the connection, until the decoder's stream is closed. A frame with an empty
+// payload closes the writing side of the connection.
+func forwardPortInput(decoder *codec.Decoder, conn net.Conn) error {
+	for {
+		var frame cstructs.StreamErrWrapper
+		if err := decoder.Decode(&frame); err != nil {
+			if err == io.EOF || err == io.ErrClosedPipe || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		if frame.Error != nil {
+			return frame.Error
+		}
+
+		if len(frame.Payload) == 0 {
+			if tcpConn, ok := conn.(*net.TCPConn); ok {
+				if err := tcpConn.CloseWrite(); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if _, err := conn.Write(frame.Payload); err != nil {
+			return err
+		}
+	}
+}
+
+// forwardPortOutput encodes the data read from the connection as frames,
+// until the connection is closed.
+func forwardPortOutput(conn net.Conn, encoder *codec.Encoder) error {
+	buf := make([]byte, streamFrameSize)
+	for {
+		n, err := conn.Read(buf)
+		if n > 0 {
+			if err := encoder.Encode(cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF || errors.Is(err, net.ErrClosed) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
 // newExecStream returns a new exec stream as expected by drivers that interpolate with RPC streaming format
 func newExecStream(decoder *codec.Decoder, encoder *codec.Encoder) drivers.ExecTaskStream {
 	buf := new(bytes.Buffer)
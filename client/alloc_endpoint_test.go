@@ -1283,6 +1283,244 @@ func TestAlloc_ExecStreaming_ACL_WithIsolation_None(t *testing.T) {
 	}
 }
 
+func TestAlloc_PortForward(t *testing.T) {
+	ci.Parallel(t)
+
+	client, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	// Start a listener echoing the data it receives
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	a.AllocatedResources.Shared.Ports = nstructs.AllocatedPorts{
+		{Label: "echo", Value: ln.Addr().(*net.TCPAddr).Port, HostIP: "127.0.0.1"},
+	}
+	require.NoError(t, client.addAlloc(a, ""))
+
+	testutil.WaitForResult(func() (bool, error) {
+		state, err := client.GetAllocState(a.ID)
+		if err != nil {
+			return false, err
+		}
+		if state.ClientStatus != nstructs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc is %s", state.ClientStatus)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	handler, err := client.StreamingRpcHandler("Allocations.PortForward")
+	require.NoError(t, err)
+
+	forward := func(port string) (*codec.Encoder, *codec.Decoder) {
+		p1, p2 := net.Pipe()
+		t.Cleanup(func() {
+			p1.Close()
+			p2.Close()
+		})
+		go handler(p2)
+
+		encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+		require.NoError(t, encoder.Encode(&cstructs.AllocPortForwardRequest{
+			AllocID:      a.ID,
+			Port:         port,
+			QueryOptions: nstructs.QueryOptions{Region: "global"},
+		}))
+		return encoder, codec.NewDecoder(p1, nstructs.MsgpackHandle)
+	}
+
+	// Unknown ports are rejected
+	_, decoder := forward("missing")
+	var frame cstructs.StreamErrWrapper
+	require.NoError(t, decoder.Decode(&frame))
+	require.NotNil(t, frame.Error)
+	require.Contains(t, frame.Error.Error(), `unknown port "missing"`)
+
+	// Data sent to the port is echoed back
+	encoder, decoder := forward("echo")
+	require.NoError(t, encoder.Encode(cstructs.StreamErrWrapper{Payload: []byte("hello")}))
+
+	var received []byte
+	for len(received) < len("hello") {
+		var frame cstructs.StreamErrWrapper
+		require.NoError(t, decoder.Decode(&frame))
+		require.Nil(t, frame.Error)
+		received = append(received, frame.Payload...)
+	}
+	require.Equal(t, "hello", string(received))
+
+	// Closing the writing side ends the session once the task closes the
+	// connection
+	require.NoError(t, encoder.Encode(cstructs.StreamErrWrapper{}))
+	require.Error(t, decoder.Decode(&frame))
+
+	// The start and end of the sessions are recorded by node events. The
+	// client isn't registered, so the events are left queued.
+	var messages []string
+	var sessionIDs []string
+	timeout := time.After(5 * time.Second)
+	for len(messages) < 4 {
+		select {
+		case event := <-client.triggerEmitNodeEvent:
+			if event.Subsystem != nstructs.NodeEventSubsystemPortForward {
+				continue
+			}
+			require.Equal(t, a.ID, event.Details["alloc_id"])
+			require.NotEmpty(t, event.Details["session_id"])
+			messages = append(messages, event.Message+" "+event.Details["port"])
+			sessionIDs = append(sessionIDs, event.Details["session_id"])
+		case <-timeout:
+			t.Fatalf("expected port forward events, got %v", messages)
+		}
+	}
+	require.Equal(t, []string{
+		"Port forward session started missing",
+		"Port forward session ended with an error missing",
+		"Port forward session started echo",
+		"Port forward session ended echo",
+	}, messages)
+	require.Equal(t, sessionIDs[0], sessionIDs[1])
+	require.Equal(t, sessionIDs[2], sessionIDs[3])
+	require.NotEqual(t, sessionIDs[0], sessionIDs[2])
+}
+
+func TestAlloc_PortForward_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanupC := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Create a bad token
+	policyBad := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocExec, acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(nstructs.DefaultNamespace, "",
+		[]string{acl.NamespaceCapabilityAllocPortForward})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid2", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: `unknown port "missing"`,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: `unknown port "missing"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+
+			// Make the request
+			req := &cstructs.AllocPortForwardRequest{
+				AllocID: alloc.ID,
+				Port:    "missing",
+				QueryOptions: nstructs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+					Namespace: nstructs.DefaultNamespace,
+				},
+			}
+
+			// Get the handler
+			handler, err := client.StreamingRpcHandler("Allocations.PortForward")
+			require.Nil(t, err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			// Start the handler
+			go handler(p2)
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+			require.Nil(t, encoder.Encode(req))
+
+			decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+			var frame cstructs.StreamErrWrapper
+			require.NoError(t, decoder.Decode(&frame))
+			require.NotNil(t, frame.Error)
+			require.Contains(t, frame.Error.Error(), c.ExpectedError)
+		})
+	}
+}
+
+func TestAlloc_PortForwardAddr(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.AllocatedResources.Shared.Ports = nstructs.AllocatedPorts{
+		{Label: "http", Value: 25000, To: 8080, HostIP: "10.0.0.1"},
+		{Label: "admin", Value: 25001, HostIP: "10.0.0.1"},
+	}
+
+	// Host networking uses the host address
+	addr, err := portForwardAddr(alloc, nil, "http")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1:25000", addr)
+
+	// Network namespaces use the namespace address and mapped port
+	status := &nstructs.AllocNetworkStatus{Address: "172.26.64.2"}
+	addr, err = portForwardAddr(alloc, status, "http")
+	require.NoError(t, err)
+	require.Equal(t, "172.26.64.2:8080", addr)
+
+	addr, err = portForwardAddr(alloc, status, "admin")
+	require.NoError(t, err)
+	require.Equal(t, "172.26.64.2:25001", addr)
+
+	_, err = portForwardAddr(alloc, status, "missing")
+	require.EqualError(t, err, `unknown port "missing"`)
+}
+
 func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStreamingResponseMsg, errCh chan<- error) {
 	// Start the decoder
 	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
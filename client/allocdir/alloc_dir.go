@@ -17,6 +17,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/escapingfs"
+	"github.com/hashicorp/nomad/helper/tarfs"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hpcloud/tail/watch"
 	tomb "gopkg.in/tomb.v1"
@@ -58,6 +59,10 @@ var (
 	// directory
 	TaskSecrets = "secrets"
 
+	// CheckpointDirName is the name of the directory inside each alloc
+	// directory that tasks are checkpointed to. It isn't exposed to tasks.
+	CheckpointDirName = "checkpoints"
+
 	// TaskDirs is the set of directories created in each tasks directory.
 	TaskDirs = map[string]os.FileMode{TmpDirName: os.ModeSticky | 0777}
 
@@ -101,6 +106,8 @@ type AllocDirFS interface {
 	List(path string) ([]*cstructs.AllocFileInfo, error)
 	Stat(path string) (*cstructs.AllocFileInfo, error)
 	ReadAt(path string, offset int64) (io.ReadCloser, error)
+	Archive(path string, w io.Writer) error
+	Extract(path string, r io.Reader) error
 	Snapshot(w io.Writer) error
 	BlockUntilExists(ctx context.Context, path string) (chan error, error)
 	ChangeEvents(ctx context.Context, path string, curOffset int64) (*watch.FileChanges, error)
@@ -144,6 +151,11 @@ func (d *AllocDir) Snapshot(w io.Writer) error {
 	rootPaths := []string{allocDataDir}
 	for _, taskdir := range d.TaskDirs {
 		rootPaths = append(rootPaths, taskdir.LocalDir)
+
+		// Checkpoints only exist for tasks checkpointed when stopped
+		if pathExists(taskdir.CheckpointDir) {
+			rootPaths = append(rootPaths, taskdir.CheckpointDir)
+		}
 	}
 
 	tw := tar.NewWriter(w)
@@ -215,7 +227,8 @@ func (d *AllocDir) Snapshot(w io.Writer) error {
 	return nil
 }
 
-// Move other alloc directory's shared path and local dir to this alloc dir.
+// Move other alloc directory's shared path, local dirs and task checkpoints to
+// this alloc dir.
 func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 	d.mu.RLock()
 	if !d.built {
@@ -255,6 +268,19 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 				return fmt.Errorf("error moving task %q local dir: %v", task.Name, err)
 			}
 		}
+
+		// Move the task's checkpoint if it was checkpointed when stopped
+		otherCheckpoint := filepath.Join(other.AllocDir, CheckpointDirName, task.Name)
+		if fileInfo, err := os.Stat(otherCheckpoint); fileInfo != nil && err == nil {
+			checkpointDir := filepath.Join(d.AllocDir, CheckpointDirName, task.Name)
+			if err := os.MkdirAll(filepath.Dir(checkpointDir), 0700); err != nil {
+				return fmt.Errorf("error creating checkpoint dir: %v", err)
+			}
+			os.RemoveAll(checkpointDir) // remove a stale checkpoint if it exists
+			if err := os.Rename(otherCheckpoint, checkpointDir); err != nil {
+				return fmt.Errorf("error moving task %q checkpoint: %v", task.Name, err)
+			}
+		}
 	}
 
 	return nil
@@ -435,14 +461,9 @@ func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
 	p := filepath.Join(d.AllocDir, path)
 
 	// Check if it is trying to read into a secret directory
-	d.mu.RLock()
-	for _, dir := range d.TaskDirs {
-		if filepath.HasPrefix(p, dir.SecretsDir) {
-			d.mu.RUnlock()
-			return nil, fmt.Errorf("Reading secret file prohibited: %s", path)
-		}
+	if d.isSecretPath(p) {
+		return nil, fmt.Errorf("Reading secret file prohibited: %s", path)
 	}
-	d.mu.RUnlock()
 
 	f, err := os.Open(p)
 	if err != nil {
@@ -454,6 +475,75 @@ func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
 	return f, nil
 }
 
+// Archive writes a tar archive of the file or directory at the path relative to
+// the alloc dir. The secret directories of the tasks are left out.
+func (d *AllocDir) Archive(path string, w io.Writer) error {
+	if escapes, err := escapingfs.PathEscapesAllocDir(d.AllocDir, "", path); err != nil {
+		return fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+	if d.isSecretPath(p) {
+		return fmt.Errorf("Reading secret file prohibited: %s", path)
+	}
+
+	return tarfs.Archive(w, p, filepath.Base(p), d.isSecretPath)
+}
+
+// Extract extracts a tar archive into the existing directory at the path
+// relative to the alloc dir. Archives writing outside of the directory or into
+// the secret directories of the tasks are rejected.
+func (d *AllocDir) Extract(path string, r io.Reader) error {
+	if escapes, err := escapingfs.PathEscapesAllocDir(d.AllocDir, "", path); err != nil {
+		return fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+	if d.isSecretPath(p) {
+		return fmt.Errorf("Writing secret file prohibited: %s", path)
+	}
+
+	return tarfs.Extract(r, p, "", d.isSecretPath)
+}
+
+// isSecretPath returns if the absolute path is in the secret directory of a
+// task. Symlinks in the path are resolved first, so that secret directories
+// can't be reached through links to them.
+func (d *AllocDir) isSecretPath(path string) bool {
+	resolved := resolveSymlinks(path)
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, dir := range d.TaskDirs {
+		if filepath.HasPrefix(path, dir.SecretsDir) ||
+			filepath.HasPrefix(resolved, resolveSymlinks(dir.SecretsDir)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveSymlinks returns the absolute path with its symlinks resolved. Only
+// the longest existing prefix of the path is resolved, so that paths that are
+// about to be created can be checked too.
+func resolveSymlinks(path string) string {
+	rest := ""
+	for p := path; ; p = filepath.Dir(p) {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if p == filepath.Dir(p) {
+			return path
+		}
+		rest = filepath.Join(filepath.Base(p), rest)
+	}
+}
+
 // BlockUntilExists blocks until the passed file relative the allocation
 // directory exists. The block can be cancelled with the passed context.
 func (d *AllocDir) BlockUntilExists(ctx context.Context, path string) (chan error, error) {
@@ -277,6 +277,54 @@ func TestAllocDir_Move(t *testing.T) {
 	}
 }
 
+// TestAllocDir_Checkpoint asserts task checkpoints are included in snapshots
+// and moved along with the rest of the alloc dir.
+func TestAllocDir_Checkpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	d1 := NewAllocDir(testlog.HCLogger(t), t.TempDir(), "test")
+	require.NoError(t, d1.Build())
+	defer d1.Destroy()
+
+	d2 := NewAllocDir(testlog.HCLogger(t), t.TempDir(), "test")
+	require.NoError(t, d2.Build())
+	defer d2.Destroy()
+
+	td1 := d1.NewTaskDir(t1.Name)
+	require.NoError(t, td1.Build(false, nil))
+	require.Equal(t, filepath.Join(d1.AllocDir, CheckpointDirName, t1.Name), td1.CheckpointDir)
+
+	// Tasks without checkpoints are snapshotted
+	td2 := d1.NewTaskDir(t2.Name)
+	require.NoError(t, td2.Build(false, nil))
+	require.NoDirExists(t, td2.CheckpointDir)
+
+	require.NoError(t, os.MkdirAll(td1.CheckpointDir, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(td1.CheckpointDir, "pages-1.img"), []byte("foo"), 0600))
+
+	var b bytes.Buffer
+	require.NoError(t, d1.Snapshot(&b))
+
+	var files []string
+	tr := tar.NewReader(&b)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if hdr.Typeflag == tar.TypeReg {
+			files = append(files, hdr.Name)
+		}
+	}
+	require.Equal(t, []string{filepath.Join(CheckpointDirName, t1.Name, "pages-1.img")}, files)
+
+	d2.NewTaskDir(t1.Name)
+	require.NoError(t, d2.Move(d1, []*structs.Task{t1, t2}))
+	require.FileExists(t, filepath.Join(d2.TaskDirs[t1.Name].CheckpointDir, "pages-1.img"))
+	require.NoDirExists(t, td1.CheckpointDir)
+}
+
 func TestAllocDir_EscapeChecking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -304,6 +352,16 @@ func TestAllocDir_EscapeChecking(t *testing.T) {
 		t.Fatalf("ReadAt of escaping path didn't error: %v", err)
 	}
 
+	// Archive
+	if err := d.Archive("../foo", ioutil.Discard); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("Archive of escaping path didn't error: %v", err)
+	}
+
+	// Extract
+	if err := d.Extract("../foo", strings.NewReader("")); err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("Extract of escaping path didn't error: %v", err)
+	}
+
 	// BlockUntilExists
 	if _, err := d.BlockUntilExists(context.Background(), "../foo"); err == nil || !strings.Contains(err.Error(), "escapes") {
 		t.Fatalf("BlockUntilExists of escaping path didn't error: %v", err)
@@ -342,6 +400,68 @@ func TestAllocDir_ReadAt_SecretDir(t *testing.T) {
 	// ReadAt of a file in the task secrets dir should fail
 	_, err = d.ReadAt(target, 0)
 	require.EqualError(t, err, "Reading secret file prohibited: web/secrets/test_file")
+
+	// Archiving the task dir should leave out the secrets dir
+	var buf bytes.Buffer
+	require.NoError(t, d.Archive(t1.Name, &buf))
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.NotContains(t, hdr.Name, TaskSecrets)
+	}
+
+	// Extracting into the task secrets dir should fail
+	err = d.Extract(filepath.Join(t1.Name, TaskSecrets), &buf)
+	require.EqualError(t, err, "Writing secret file prohibited: web/secrets")
+}
+
+func TestAllocDir_SecretDir_Links(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, "test")
+	require.NoError(t, d.Build())
+	defer func() {
+		_ = d.Destroy()
+	}()
+
+	td := d.NewTaskDir(t1.Name)
+	require.NoError(t, td.Build(false, nil))
+	secret := filepath.Join(t1.Name, TaskSecrets, "vault_token")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(d.AllocDir, secret), []byte("hi"), 0600))
+
+	archive := func(hdr *tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		hdr.Mode = 0644
+		require.NoError(t, tw.WriteHeader(hdr))
+		require.NoError(t, tw.Close())
+		return &buf
+	}
+
+	// Uploading links into the task secrets dir should fail
+	err := d.Extract(".", archive(&tar.Header{Name: "x", Linkname: secret, Typeflag: tar.TypeLink}))
+	require.EqualError(t, err, "linking x to web/secrets/vault_token prohibited")
+	err = d.Extract(".", archive(&tar.Header{Name: "x", Linkname: "web/secrets", Typeflag: tar.TypeSymlink}))
+	require.EqualError(t, err, "linking x to web/secrets prohibited")
+	require.NoFileExists(t, filepath.Join(d.AllocDir, "x"))
+
+	// Secrets can't be read through existing symlinks to the secrets dir
+	require.NoError(t, os.Symlink(filepath.Join(t1.Name, TaskSecrets), filepath.Join(d.AllocDir, "x")))
+	_, err = d.ReadAt("x/vault_token", 0)
+	require.EqualError(t, err, "Reading secret file prohibited: x/vault_token")
+	err = d.Archive("x/vault_token", ioutil.Discard)
+	require.EqualError(t, err, "Reading secret file prohibited: x/vault_token")
+	err = d.Archive("x", ioutil.Discard)
+	require.EqualError(t, err, "Reading secret file prohibited: x")
+
+	// Nor written through them
+	err = d.Extract("x", archive(&tar.Header{Name: "vault_token", Typeflag: tar.TypeReg}))
+	require.EqualError(t, err, "Writing secret file prohibited: x")
 }
 
 func TestAllocDir_SplitPath(t *testing.T) {
@@ -17,6 +17,7 @@ import (
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/stretchr/testify/require"
 )
@@ -320,6 +321,11 @@ func (vm mockVolumeMounter) UnmountVolume(ctx context.Context, volID, remoteID,
 	return nil
 }
 
+func (vm mockVolumeMounter) ExpandVolume(ctx context.Context, volID, remoteID, allocID string, usageOpts *csimanager.UsageOptions, capacity *csi.CapacityRange) (int64, error) {
+	vm.callCounts["expand"]++
+	return capacity.RequiredBytes, nil
+}
+
 type mockPluginManager struct {
 	mounter mockVolumeMounter
 }
@@ -330,6 +336,7 @@ func (mgr mockPluginManager) MounterForPlugin(ctx context.Context, pluginID stri
 
 // no-op methods to fulfill the interface
 func (mgr mockPluginManager) PluginManager() pluginmanager.PluginManager { return nil }
+func (mgr mockPluginManager) VolumeStats() []*structs.CSIVolumeStats     { return nil }
 func (mgr mockPluginManager) Shutdown()                                  {}
 
 type mockAllocRunner struct {
@@ -145,11 +145,14 @@ func (h *networkHook) Prerun() error {
 			return fmt.Errorf("failed to configure networking for alloc: %v", err)
 		}
 
-		// If the driver set the sandbox hostname label, then we will use that
-		// to set the HostsConfig.Hostname. Otherwise, identify the sandbox
-		// container ID which will have been used to set the network namespace
-		// hostname.
-		if hostname, ok := spec.Labels[dockerNetSpecHostnameKey]; ok {
+		// If the driver set the HostsConfig itself, then it only lacks the
+		// address assigned by the network configurator. If the driver set the
+		// sandbox hostname label, then we will use that to set the
+		// HostsConfig.Hostname. Otherwise, identify the sandbox container ID
+		// which will have been used to set the network namespace hostname.
+		if spec.HostsConfig != nil {
+			h.spec.HostsConfig.Address = status.Address
+		} else if hostname, ok := spec.Labels[dockerNetSpecHostnameKey]; ok {
 			h.spec.HostsConfig = &drivers.HostsConfig{
 				Address:  status.Address,
 				Hostname: hostname,
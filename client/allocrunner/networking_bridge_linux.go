@@ -3,7 +3,9 @@ package allocrunner
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
+	"github.com/containernetworking/cni/pkg/invoke"
 	"github.com/coreos/go-iptables/iptables"
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -54,7 +56,15 @@ func newBridgeNetworkConfigurator(log hclog.Logger, bridgeName, ipRange, cniPath
 		b.allocSubnet = defaultNomadAllocSubnet
 	}
 
-	c, err := newCNINetworkConfiguratorWithConf(log, cniPath, bridgeNetworkAllocIfPrefix, ignorePortMappingHostIP, buildNomadBridgeNetConfig(b.bridgeName, b.allocSubnet))
+	// Egress bandwidth is shaped by the bandwidth plugin, which is only
+	// chained if installed as it isn't part of older CNI plugin releases
+	_, err := invoke.FindInPath("bandwidth", filepath.SplitList(getCNIPath(cniPath)))
+	bandwidth := err == nil
+	if !bandwidth {
+		log.Debug("bandwidth CNI plugin not found, egress bandwidth will not be limited", "error", err)
+	}
+
+	c, err := newCNINetworkConfiguratorWithConf(log, cniPath, bridgeNetworkAllocIfPrefix, ignorePortMappingHostIP, buildNomadBridgeNetConfig(b.bridgeName, b.allocSubnet, bandwidth))
 	if err != nil {
 		return nil, err
 	}
@@ -134,8 +144,12 @@ func (b *bridgeNetworkConfigurator) Teardown(ctx context.Context, alloc *structs
 	return b.cni.Teardown(ctx, alloc, spec)
 }
 
-func buildNomadBridgeNetConfig(bridgeName, subnet string) []byte {
-	return []byte(fmt.Sprintf(nomadCNIConfigTemplate, bridgeName, subnet, cniAdminChainName))
+func buildNomadBridgeNetConfig(bridgeName, subnet string, bandwidth bool) []byte {
+	var plugins string
+	if bandwidth {
+		plugins = nomadCNIBandwidthPlugin
+	}
+	return []byte(fmt.Sprintf(nomadCNIConfigTemplate, bridgeName, subnet, cniAdminChainName, plugins))
 }
 
 const nomadCNIConfigTemplate = `{
@@ -171,7 +185,13 @@ const nomadCNIConfigTemplate = `{
 			"type": "portmap",
 			"capabilities": {"portMappings": true},
 			"snat": true
-		}
+		}%s
 	]
 }
 `
+
+const nomadCNIBandwidthPlugin = `,
+		{
+			"type": "bandwidth",
+			"capabilities": {"bandwidth": true}
+		}`
@@ -63,11 +63,7 @@ func newCNINetworkConfiguratorWithConf(logger log.Logger, cniPath, cniInterfaceP
 		logger:                  logger,
 		ignorePortMappingHostIP: ignorePortMappingHostIP,
 	}
-	if cniPath == "" {
-		if cniPath = os.Getenv(envCNIPath); cniPath == "" {
-			cniPath = defaultCNIPath
-		}
-	}
+	cniPath = getCNIPath(cniPath)
 
 	if cniInterfacePrefix == "" {
 		cniInterfacePrefix = defaultCNIInterfacePrefix
@@ -97,7 +93,7 @@ func (c *cniNetworkConfigurator) Setup(ctx context.Context, alloc *structs.Alloc
 	var res *cni.CNIResult
 	for attempt := 1; ; attempt++ {
 		var err error
-		if res, err = c.cni.Setup(ctx, alloc.ID, spec.Path, cni.WithCapabilityPortMap(getPortMapping(alloc, c.ignorePortMappingHostIP))); err != nil {
+		if res, err = c.cni.Setup(ctx, alloc.ID, spec.Path, c.namespaceOpts(alloc)...); err != nil {
 			c.logger.Warn("failed to configure network", "err", err, "attempt", attempt)
 			switch attempt {
 			case 1:
@@ -226,7 +222,19 @@ func (c *cniNetworkConfigurator) Teardown(ctx context.Context, alloc *structs.Al
 		return err
 	}
 
-	return c.cni.Remove(ctx, alloc.ID, spec.Path, cni.WithCapabilityPortMap(getPortMapping(alloc, c.ignorePortMappingHostIP)))
+	return c.cni.Remove(ctx, alloc.ID, spec.Path, c.namespaceOpts(alloc)...)
+}
+
+// namespaceOpts returns the capability arguments of the CNI plugins for the
+// allocation. Plugins only receive the capabilities they declare.
+func (c *cniNetworkConfigurator) namespaceOpts(alloc *structs.Allocation) []cni.NamespaceOpts {
+	opts := []cni.NamespaceOpts{
+		cni.WithCapabilityPortMap(getPortMapping(alloc, c.ignorePortMappingHostIP)),
+	}
+	if bandwidth := getBandwidth(alloc); bandwidth.EgressRate > 0 {
+		opts = append(opts, cni.WithCapabilityBandWidth(bandwidth))
+	}
+	return opts
 }
 
 func (c *cniNetworkConfigurator) ensureCNIInitialized() error {
@@ -237,6 +245,34 @@ func (c *cniNetworkConfigurator) ensureCNIInitialized() error {
 	}
 }
 
+// getCNIPath returns the CNI plugin search path, which defaults to the
+// CNI_PATH environment variable and then to the default path.
+func getCNIPath(cniPath string) string {
+	if cniPath == "" {
+		if cniPath = os.Getenv(envCNIPath); cniPath == "" {
+			cniPath = defaultCNIPath
+		}
+	}
+	return cniPath
+}
+
+// getBandwidth builds the bandwidth capability arguments for the bandwidth
+// CNI plugin, which shapes the egress traffic of the allocation to the sum of
+// the egress bandwidth of its tasks. Rates are in bits per second and the
+// burst allows 100ms of traffic at the full rate.
+func getBandwidth(alloc *structs.Allocation) cni.BandWidth {
+	var mbits uint64
+	for _, resources := range alloc.AllocatedResources.Tasks {
+		mbits += uint64(resources.Bandwidth.EgressMbits)
+	}
+
+	rate := mbits * 1000 * 1000
+	return cni.BandWidth{
+		EgressRate:  rate,
+		EgressBurst: rate / 10,
+	}
+}
+
 // getPortMapping builds a list of portMapping structs that are used as the
 // portmapping capability arguments for the portmap CNI plugin
 func getPortMapping(alloc *structs.Allocation, ignoreHostIP bool) []cni.PortMapping {
@@ -10,6 +10,8 @@ import (
 	cni "github.com/containerd/go-cni"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -69,3 +71,19 @@ func TestCNI_cniToAllocNet_Invalid(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, allocNet)
 }
+
+func TestCNI_getBandwidth(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	require.Zero(t, getBandwidth(alloc).EgressRate)
+
+	alloc.AllocatedResources.Tasks["web"].Bandwidth.EgressMbits = 10
+	alloc.AllocatedResources.Tasks["sidecar"] = &structs.AllocatedTaskResources{
+		Bandwidth: structs.AllocatedBandwidthResources{EgressMbits: 5},
+	}
+	require.Equal(t, cni.BandWidth{
+		EgressRate:  15 * 1000 * 1000,
+		EgressBurst: 1500 * 1000,
+	}, getBandwidth(alloc))
+}
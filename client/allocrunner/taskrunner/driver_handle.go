@@ -106,6 +106,24 @@ func (h *DriverHandle) ExecStreaming(ctx context.Context,
 	return stream.Send(drivers.NewExecStreamingResponseExit(result.ExitCode))
 }
 
+// Checkpoint dumps the state of the task to dir, stopping the task.
+func (h *DriverHandle) Checkpoint(dir string) error {
+	d, ok := h.driver.(drivers.CheckpointDriver)
+	if !ok {
+		return fmt.Errorf("task driver does not support checkpointing")
+	}
+	return d.CheckpointTask(h.taskID, dir)
+}
+
+// Resize updates the resources enforced for the running task.
+func (h *DriverHandle) Resize(resources *drivers.Resources) error {
+	d, ok := h.driver.(drivers.ResizeTaskDriver)
+	if !ok {
+		return fmt.Errorf("task driver does not support resizing tasks")
+	}
+	return d.ResizeTask(h.taskID, resources)
+}
+
 func (h *DriverHandle) Network() *drivers.DriverNetwork {
 	return h.net
 }
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"sync"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	hclog "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
@@ -37,6 +39,10 @@ type logmonHook struct {
 
 	config *logmonHookConfig
 
+	// sinkStatsCancel stops the collection of the log sinks' stats
+	sinkStatsCancel context.CancelFunc
+	sinkStatsLock   sync.Mutex
+
 	logger hclog.Logger
 }
 
@@ -176,7 +182,7 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		}
 	}
 
-	err := h.logmon.Start(&logmon.LogConfig{
+	cfg := &logmon.LogConfig{
 		LogDir:        h.config.logDir,
 		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
 		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
@@ -184,15 +190,165 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 		StderrFifo:    h.config.stderrFifo,
 		MaxFiles:      req.Task.LogConfig.MaxFiles,
 		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
-	})
+	}
+	if sinks := req.Task.LogConfig.Sinks; len(sinks) > 0 {
+		cfg.Sinks = logSinks(sinks)
+		if err := h.checkSinksAllowed(sinks); err != nil {
+			return structs.NewRecoverableError(err, false)
+		}
+		cfg.Metadata = h.logMetadata(req.Task)
+	}
+
+	err := h.logmon.Start(cfg)
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
 		return err
 	}
 
+	if len(cfg.Sinks) > 0 {
+		h.startSinkStats(h.logmon)
+	}
+
 	return nil
 }
 
+// logSinks converts the task's log sinks to their logmon configuration
+func logSinks(sinks []*structs.LogSink) []*logmon.SinkConfig {
+	out := make([]*logmon.SinkConfig, 0, len(sinks))
+	for _, s := range sinks {
+		out = append(out, &logmon.SinkConfig{
+			Name:       s.Name,
+			Type:       s.Type,
+			Address:    s.Address,
+			Facility:   s.Facility,
+			Tag:        s.Tag,
+			BufferSize: s.BufferSize,
+		})
+	}
+	return out
+}
+
+// checkSinksAllowed returns an error if the client's configuration doesn't
+// allow the task to forward its logs to any of the sinks.
+func (h *logmonHook) checkSinksAllowed(sinks []*structs.LogSink) error {
+	policy := h.runner.clientConfig.LogSinks
+	if policy == nil || !policy.Enabled {
+		return fmt.Errorf("log sinks are not enabled on this client")
+	}
+
+	for _, s := range sinks {
+		if err := s.CheckAllowed(policy.AllowedAddresses, policy.AllowedSockets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logMetadata returns the metadata identifying the task in records forwarded
+// to log sinks.
+func (h *logmonHook) logMetadata(task *structs.Task) *logmon.LogMetadata {
+	alloc := h.runner.Alloc()
+	meta := &logmon.LogMetadata{
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		TaskGroup: alloc.TaskGroup,
+		AllocID:   alloc.ID,
+		AllocName: alloc.Name,
+		TaskName:  task.Name,
+	}
+	if node := h.runner.clientConfig.Node; node != nil {
+		meta.NodeID = node.ID
+	}
+	return meta
+}
+
+// startSinkStats starts collecting the stats of the log sinks from logmon,
+// replacing the collection from a previous logmon process if any.
+func (h *logmonHook) startSinkStats(lm logmon.LogMon) {
+	if !h.runner.clientConfig.PublishAllocationMetrics {
+		return
+	}
+
+	h.sinkStatsLock.Lock()
+	defer h.sinkStatsLock.Unlock()
+	if h.sinkStatsCancel != nil {
+		h.sinkStatsCancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.sinkStatsCancel = cancel
+	go h.collectSinkStats(ctx, lm)
+}
+
+// stopSinkStats stops collecting the stats of the log sinks
+func (h *logmonHook) stopSinkStats() {
+	h.sinkStatsLock.Lock()
+	defer h.sinkStatsLock.Unlock()
+	if h.sinkStatsCancel != nil {
+		h.sinkStatsCancel()
+		h.sinkStatsCancel = nil
+	}
+}
+
+// collectSinkStats periodically emits the number of records sent to and
+// dropped by each log sink, and the number of records buffered by the sink.
+func (h *logmonHook) collectSinkStats(ctx context.Context, lm logmon.LogMon) {
+	interval := h.runner.clientConfig.StatsCollectionInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Counters are cumulative in logmon so only the change since the
+	// previous collection is emitted
+	last := map[string]*logmon.SinkStats{}
+	delta := func(cur, prev uint64) float32 {
+		// logmon resets its counters when the task is restarted
+		if cur < prev {
+			return float32(cur)
+		}
+		return float32(cur - prev)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stats, err := lm.Stats()
+		if err != nil {
+			h.logger.Trace("failed to collect log sink stats", "error", err)
+			continue
+		}
+
+		for _, s := range stats {
+			labels := append([]metrics.Label{
+				{Name: "sink", Value: s.Name},
+				{Name: "sink_type", Value: s.Type},
+			}, h.runner.baseLabels...)
+
+			prev := last[s.Name]
+			if prev == nil {
+				prev = &logmon.SinkStats{}
+			}
+			metrics.IncrCounterWithLabels([]string{"client", "allocs", "logs", "sink", "sent"},
+				delta(s.Sent, prev.Sent), labels)
+			metrics.IncrCounterWithLabels([]string{"client", "allocs", "logs", "sink", "dropped"},
+				delta(s.Dropped, prev.Dropped), labels)
+			metrics.IncrCounterWithLabels([]string{"client", "allocs", "logs", "sink", "errors"},
+				delta(s.Errors, prev.Errors), labels)
+			metrics.SetGaugeWithLabels([]string{"client", "allocs", "logs", "sink", "buffered"},
+				float32(s.Buffered), labels)
+			metrics.SetGaugeWithLabels([]string{"client", "allocs", "logs", "sink", "buffer_size"},
+				float32(s.BufferSize), labels)
+			last[s.Name] = s
+		}
+	}
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
 
 	// It's possible that Stop was called without calling Prestart on agent
@@ -203,6 +359,8 @@ func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _
 		}
 	}
 
+	h.stopSinkStats()
+
 	if h.logmon != nil {
 		h.logmon.Stop()
 	}
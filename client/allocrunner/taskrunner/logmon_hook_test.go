@@ -9,9 +9,12 @@ import (
 	plugin "github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/logmon"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"github.com/stretchr/testify/require"
 )
@@ -100,3 +103,67 @@ func TestTaskRunner_LogmonHook_StartStop(t *testing.T) {
 	}
 	require.NoError(t, hook.Stop(context.Background(), &stopReq, nil))
 }
+
+// TestTaskRunner_LogmonHook_Sinks asserts the task's log sinks and the
+// metadata identifying the task are converted to their logmon configuration.
+func TestTaskRunner_LogmonHook_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	node := mock.Node()
+
+	runner := &TaskRunner{
+		alloc:        alloc,
+		clientConfig: &config.Config{Node: node},
+	}
+	hook := newLogMonHook(runner, testlog.HCLogger(t))
+
+	require.Equal(t, &logmon.LogMetadata{
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		TaskGroup: alloc.TaskGroup,
+		AllocID:   alloc.ID,
+		AllocName: alloc.Name,
+		TaskName:  task.Name,
+		NodeID:    node.ID,
+	}, hook.logMetadata(task))
+
+	require.Equal(t, []*logmon.SinkConfig{{
+		Name:       "syslog",
+		Type:       structs.LogSinkTypeSyslog,
+		Facility:   "local0",
+		Tag:        "web",
+		BufferSize: 128,
+	}}, logSinks([]*structs.LogSink{{
+		Name:       "syslog",
+		Type:       structs.LogSinkTypeSyslog,
+		Facility:   "local0",
+		Tag:        "web",
+		BufferSize: 128,
+	}}))
+}
+
+// TestTaskRunner_LogmonHook_SinksAllowed asserts tasks may only forward their
+// logs to the sinks allowed by the client's configuration.
+func TestTaskRunner_LogmonHook_SinksAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	runner := &TaskRunner{clientConfig: &config.Config{}}
+	hook := newLogMonHook(runner, testlog.HCLogger(t))
+
+	sinks := []*structs.LogSink{{
+		Name:    "collector",
+		Type:    structs.LogSinkTypeTCP,
+		Address: "10.0.0.5:5170",
+	}}
+
+	// Log sinks are disabled by default
+	require.EqualError(t, hook.checkSinksAllowed(sinks), "log sinks are not enabled on this client")
+
+	runner.clientConfig.LogSinks = &config.LogSinkConfig{Enabled: true}
+	require.Error(t, hook.checkSinksAllowed(sinks))
+
+	runner.clientConfig.LogSinks.AllowedAddresses = []string{"10.0.0.5:*"}
+	require.NoError(t, hook.checkSinksAllowed(sinks))
+}
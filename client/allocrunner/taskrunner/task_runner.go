@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -65,15 +66,17 @@ const (
 )
 
 type TaskRunner struct {
-	// allocID, taskName, taskLeader, and taskResources are immutable so these fields may
+	// allocID, taskName, and taskLeader are immutable so these fields may
 	// be accessed without locks
-	allocID       string
-	taskName      string
-	taskLeader    bool
-	taskResources *structs.AllocatedTaskResources
+	allocID    string
+	taskName   string
+	taskLeader bool
 
-	alloc     *structs.Allocation
-	allocLock sync.Mutex
+	// alloc and taskResources are guarded by allocLock. The task resources
+	// are updated along with the allocation when they're updated in-place.
+	alloc         *structs.Allocation
+	taskResources *structs.AllocatedTaskResources
+	allocLock     sync.Mutex
 
 	clientConfig *config.Config
 
@@ -146,6 +149,12 @@ type TaskRunner struct {
 	// handle to the running driver
 	handle *DriverHandle
 
+	// runningResources are the resources enforced for the running task. They
+	// differ from taskResources after the resources of the task are updated
+	// in-place, until the task is resized or restarted.
+	runningResources     *structs.AllocatedTaskResources
+	runningResourcesLock sync.Mutex
+
 	// task is the task being run
 	task     *structs.Task
 	taskLock sync.RWMutex
@@ -746,6 +755,9 @@ func (tr *TaskRunner) handleUpdates() {
 
 		// Non-terminal update; run hooks
 		tr.updateHooks()
+
+		// Enforce resources updated in-place
+		tr.resizeTask()
 	}
 }
 
@@ -838,8 +850,13 @@ func (tr *TaskRunner) runDriver() error {
 		return nil
 	}
 
-	// Start the job if there's no existing handle (or if RecoverTask failed)
-	handle, net, err := tr.driver.StartTask(taskConfig)
+	// Restore the task if it was checkpointed by the allocation it replaces,
+	// otherwise start the job if there's no existing handle (or if
+	// RecoverTask failed)
+	handle, net := tr.restoreTask(taskConfig)
+	if handle == nil {
+		handle, net, err = tr.driver.StartTask(taskConfig)
+	}
 	if err != nil {
 		// The plugin has died, try relaunching it
 		if err == bstructs.ErrPluginShutdown {
@@ -877,6 +894,7 @@ func (tr *TaskRunner) runDriver() error {
 	tr.stateLock.Unlock()
 
 	tr.setDriverHandle(NewDriverHandle(tr.driver, taskConfig.ID, tr.Task(), net))
+	tr.setRunningResources(taskConfig.Resources.NomadResources)
 
 	// Emit an event that we started
 	tr.UpdateState(structs.TaskStateRunning, structs.NewTaskEvent(structs.TaskStarted))
@@ -948,8 +966,14 @@ func (tr *TaskRunner) handleKill(resultCh <-chan *drivers.ExitResult) *drivers.E
 		return nil
 	}
 
-	// Kill the task using an exponential backoff in-case of failures.
-	result, killErr := tr.killTask(handle, resultCh)
+	// Checkpoint the task instead of killing it if it's being migrated, so
+	// its replacement can restore it.
+	var result *drivers.ExitResult
+	var killErr error
+	if !tr.checkpointTask(handle) {
+		// Kill the task using an exponential backoff in-case of failures.
+		result, killErr = tr.killTask(handle, resultCh)
+	}
 	if killErr != nil {
 		// We couldn't successfully destroy the resource created.
 		tr.logger.Error("failed to kill task. Resources may have been leaked", "error", killErr)
@@ -1017,6 +1041,124 @@ func (tr *TaskRunner) killTask(handle *DriverHandle, resultCh <-chan *drivers.Ex
 	return nil, err
 }
 
+// checkpointTask checkpoints the task into its checkpoint directory, stopping
+// it, if its allocation is being migrated along with its ephemeral disk and
+// the driver supports checkpointing. The checkpoint is migrated with the
+// ephemeral disk and restored by the replacement allocation. Returns false if
+// the task wasn't checkpointed and must be killed instead.
+func (tr *TaskRunner) checkpointTask(handle *DriverHandle) bool {
+	if tr.driverCapabilities == nil || !tr.driverCapabilities.Checkpoint {
+		return false
+	}
+
+	alloc := tr.Alloc()
+	if !alloc.DesiredTransition.ShouldMigrate() {
+		return false
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || tg.EphemeralDisk == nil || !tg.EphemeralDisk.Migrate {
+		return false
+	}
+
+	dir := tr.taskDir.CheckpointDir
+	tr.logger.Info("checkpointing task for migration", "dir", dir)
+	if err := handle.Checkpoint(dir); err != nil {
+		tr.logger.Warn("failed to checkpoint task; killing task", "error", err)
+
+		// Don't leave a partial checkpoint to be restored
+		if err := os.RemoveAll(dir); err != nil {
+			tr.logger.Warn("failed to remove task checkpoint", "error", err)
+		}
+		return false
+	}
+
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointed))
+	return true
+}
+
+// restoreTask restores the task from the checkpoint migrated from the
+// allocation it replaces, if any. The checkpoint is removed afterwards as it
+// can't be restored again. Returns a nil handle if the task wasn't restored
+// and must be started instead.
+func (tr *TaskRunner) restoreTask(taskConfig *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork) {
+	dir := tr.taskDir.CheckpointDir
+	if _, err := os.Stat(dir); err != nil {
+		return nil, nil
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			tr.logger.Warn("failed to remove task checkpoint", "error", err)
+		}
+	}()
+
+	cd, ok := tr.driver.(drivers.CheckpointDriver)
+	if !ok || tr.driverCapabilities == nil || !tr.driverCapabilities.Checkpoint {
+		tr.logger.Warn("task driver does not support restoring checkpoints; starting task")
+		return nil, nil
+	}
+
+	tr.logger.Info("restoring task from checkpoint", "dir", dir)
+	handle, net, err := cd.RestoreTask(taskConfig, dir)
+	if err != nil {
+		tr.logger.Warn("failed to restore task from checkpoint; starting task", "error", err)
+		return nil, nil
+	}
+
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskCheckpointRestored))
+	return handle, net
+}
+
+// resizeTask enforces the resources of the task for the running task if they
+// were updated in-place. The task is restarted to enforce them if its driver
+// can't resize running tasks or fails to.
+func (tr *TaskRunner) resizeTask() {
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return
+	}
+
+	tr.runningResourcesLock.Lock()
+	running := tr.runningResources
+	tr.runningResourcesLock.Unlock()
+
+	taskResources := tr.TaskResources()
+	if running == nil || !resourcesResized(running, taskResources) {
+		return
+	}
+
+	if tr.driverCapabilities != nil && tr.driverCapabilities.Resize {
+		resources := tr.buildTaskResources(tr.Alloc(), taskResources)
+		err := handle.Resize(resources)
+		if err == nil {
+			tr.setRunningResources(taskResources)
+			tr.EmitEvent(structs.NewTaskEvent(structs.TaskResized))
+			return
+		}
+		tr.logger.Warn("failed to resize task; restarting task", "error", err)
+	}
+
+	event := structs.NewTaskEvent(structs.TaskRestartSignal).
+		SetRestartReason("Restarting task to update its resources")
+	if err := tr.Restart(context.Background(), event, false); err != nil {
+		tr.logger.Warn("failed to restart task to update its resources", "error", err)
+	}
+}
+
+// setRunningResources sets the resources enforced for the running task.
+func (tr *TaskRunner) setRunningResources(resources *structs.AllocatedTaskResources) {
+	tr.runningResourcesLock.Lock()
+	defer tr.runningResourcesLock.Unlock()
+	tr.runningResources = resources
+}
+
+// resourcesResized returns true if the CPU or memory resources of the task,
+// which can be updated in-place, differ.
+func resourcesResized(a, b *structs.AllocatedTaskResources) bool {
+	return a.Cpu.CpuShares != b.Cpu.CpuShares ||
+		a.Memory.MemoryMB != b.Memory.MemoryMB ||
+		a.Memory.MemoryMaxMB != b.Memory.MemoryMaxMB
+}
+
 // persistLocalState persists local state to disk synchronously.
 func (tr *TaskRunner) persistLocalState() error {
 	tr.stateLock.RLock()
@@ -1032,8 +1174,6 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 	task := tr.Task()
 	alloc := tr.Alloc()
 	invocationid := uuid.Generate()[:8]
-	taskResources := tr.taskResources
-	ports := tr.Alloc().AllocatedResources.Shared.Ports
 	env := tr.envBuilder.Build()
 	tr.networkIsolationLock.Lock()
 	defer tr.networkIsolationLock.Unlock()
@@ -1051,35 +1191,16 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 		}
 	}
 
-	memoryLimit := taskResources.Memory.MemoryMB
-	if max := taskResources.Memory.MemoryMaxMB; max > memoryLimit {
-		memoryLimit = max
-	}
-
-	cpusetCpus := make([]string, len(taskResources.Cpu.ReservedCores))
-	for i, v := range taskResources.Cpu.ReservedCores {
-		cpusetCpus[i] = fmt.Sprintf("%d", v)
-	}
-
 	return &drivers.TaskConfig{
-		ID:            fmt.Sprintf("%s/%s/%s", alloc.ID, task.Name, invocationid),
-		Name:          task.Name,
-		JobName:       alloc.Job.Name,
-		JobID:         alloc.Job.ID,
-		TaskGroupName: alloc.TaskGroup,
-		Namespace:     alloc.Namespace,
-		NodeName:      alloc.NodeName,
-		NodeID:        alloc.NodeID,
-		Resources: &drivers.Resources{
-			NomadResources: taskResources,
-			LinuxResources: &drivers.LinuxResources{
-				MemoryLimitBytes: memoryLimit * 1024 * 1024,
-				CPUShares:        taskResources.Cpu.CpuShares,
-				CpusetCpus:       strings.Join(cpusetCpus, ","),
-				PercentTicks:     float64(taskResources.Cpu.CpuShares) / float64(tr.clientConfig.Node.NodeResources.Cpu.CpuShares),
-			},
-			Ports: &ports,
-		},
+		ID:               fmt.Sprintf("%s/%s/%s", alloc.ID, task.Name, invocationid),
+		Name:             task.Name,
+		JobName:          alloc.Job.Name,
+		JobID:            alloc.Job.ID,
+		TaskGroupName:    alloc.TaskGroup,
+		Namespace:        alloc.Namespace,
+		NodeName:         alloc.NodeName,
+		NodeID:           alloc.NodeID,
+		Resources:        tr.buildTaskResources(alloc, tr.TaskResources()),
 		Devices:          tr.hookResources.getDevices(),
 		Mounts:           tr.hookResources.getMounts(),
 		Env:              env.Map(),
@@ -1094,6 +1215,33 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 	}
 }
 
+// buildTaskResources builds the drivers.Resources enforced for the task from
+// its allocated resources.
+func (tr *TaskRunner) buildTaskResources(alloc *structs.Allocation, taskResources *structs.AllocatedTaskResources) *drivers.Resources {
+	ports := alloc.AllocatedResources.Shared.Ports
+
+	memoryLimit := taskResources.Memory.MemoryMB
+	if max := taskResources.Memory.MemoryMaxMB; max > memoryLimit {
+		memoryLimit = max
+	}
+
+	cpusetCpus := make([]string, len(taskResources.Cpu.ReservedCores))
+	for i, v := range taskResources.Cpu.ReservedCores {
+		cpusetCpus[i] = fmt.Sprintf("%d", v)
+	}
+
+	return &drivers.Resources{
+		NomadResources: taskResources,
+		LinuxResources: &drivers.LinuxResources{
+			MemoryLimitBytes: memoryLimit * 1024 * 1024,
+			CPUShares:        taskResources.Cpu.CpuShares,
+			CpusetCpus:       strings.Join(cpusetCpus, ","),
+			PercentTicks:     float64(taskResources.Cpu.CpuShares) / float64(tr.clientConfig.Node.NodeResources.Cpu.CpuShares),
+		},
+		Ports: &ports,
+	}
+}
+
 // Restore task runner state. Called by AllocRunner.Restore after NewTaskRunner
 // but before Run so no locks need to be acquired.
 func (tr *TaskRunner) Restore() error {
@@ -1176,6 +1324,7 @@ func (tr *TaskRunner) restoreHandle(taskHandle *drivers.TaskHandle, net *drivers
 
 	// Update driver handle on task runner
 	tr.setDriverHandle(NewDriverHandle(tr.driver, taskHandle.Config.ID, tr.Task(), net))
+	tr.setRunningResources(tr.TaskResources())
 	return true
 }
 
@@ -1385,7 +1534,7 @@ func (tr *TaskRunner) LatestResourceUsage() *cstructs.TaskResourceUsage {
 
 	// Look up device statistics lazily when fetched, as currently we do not emit any stats for them yet
 	if ru != nil && tr.deviceStatsReporter != nil {
-		deviceResources := tr.taskResources.Devices
+		deviceResources := tr.TaskResources().Devices
 		ru.ResourceUsage.DeviceStats = tr.deviceStatsReporter.LatestDeviceResourceStats(deviceResources)
 	}
 	return ru
@@ -293,6 +293,106 @@ func TestTaskRunner_Stop_ExitCode(t *testing.T) {
 
 }
 
+// TestTaskRunner_Checkpoint_NotSupported asserts tasks of drivers without the
+// checkpoint capability are started and killed normally when migrated.
+func TestTaskRunner_Checkpoint_NotSupported(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	alloc.Job.TaskGroups[0].EphemeralDisk.Migrate = true
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+
+	// Write a checkpoint as if it was migrated from a previous alloc
+	checkpoint := conf.TaskDir.CheckpointDir
+	require.NoError(t, os.MkdirAll(checkpoint, 0700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(checkpoint, "pages-1.img"), []byte("foo"), 0600))
+
+	tr, err := NewTaskRunner(conf)
+	require.NoError(t, err)
+	go tr.Run()
+	defer tr.Kill(context.Background(), structs.NewTaskEvent("cleanup"))
+
+	testWaitForTaskToStart(t, tr)
+
+	// The checkpoint can't be restored so the task is started instead
+	require.NoDirExists(t, checkpoint)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, tr.Kill(ctx, structs.NewTaskEvent("migrating")))
+
+	for _, e := range tr.TaskState().Events {
+		require.NotEqual(t, structs.TaskCheckpointRestored, e.Type)
+		require.NotEqual(t, structs.TaskCheckpointed, e.Type)
+	}
+	require.NoDirExists(t, checkpoint)
+}
+
+// TestTaskRunner_Resize_NotSupported asserts tasks of drivers without the
+// resize capability are restarted when their resources are updated in-place.
+func TestTaskRunner_Resize_NotSupported(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	conf, cleanup := testTaskRunnerConfig(t, alloc, task.Name)
+	defer cleanup()
+
+	tr, err := NewTaskRunner(conf)
+	require.NoError(t, err)
+	go tr.Run()
+	defer tr.Kill(context.Background(), structs.NewTaskEvent("cleanup"))
+
+	testWaitForTaskToStart(t, tr)
+
+	// Updating resources other than CPU and memory doesn't restart the task
+	update := alloc.Copy()
+	tr.Update(update)
+
+	// Update the CPU of the task in-place
+	update = alloc.Copy()
+	update.AllocatedResources.Tasks[task.Name].Cpu.CpuShares = 1000
+	tr.Update(update)
+
+	testutil.WaitForResult(func() (bool, error) {
+		tr.runningResourcesLock.Lock()
+		running := tr.runningResources
+		tr.runningResourcesLock.Unlock()
+		if running.Cpu.CpuShares != 1000 {
+			return false, fmt.Errorf("expected running CPU of 1000 but found %d", running.Cpu.CpuShares)
+		}
+		if state := tr.TaskState().State; state != structs.TaskStateRunning {
+			return false, fmt.Errorf("expected task to be running but found %q", state)
+		}
+		return true, nil
+	}, func(err error) {
+		require.NoError(t, err)
+	})
+
+	var restarts int
+	for _, e := range tr.TaskState().Events {
+		require.NotEqual(t, structs.TaskResized, e.Type)
+		if e.Type == structs.TaskRestartSignal {
+			restarts++
+			require.Equal(t, "Restarting task to update its resources", e.RestartReason)
+		}
+	}
+	require.Equal(t, 1, restarts)
+}
+
 // TestTaskRunner_Restore_Running asserts restoring a running task does not
 // rerun the task.
 func TestTaskRunner_Restore_Running(t *testing.T) {
@@ -445,10 +445,11 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 
 	// Setup the csi manager
 	csiConfig := &csimanager.Config{
-		Logger:                c.logger,
-		DynamicRegistry:       c.dynamicRegistry,
-		UpdateNodeCSIInfoFunc: c.batchNodeUpdates.updateNodeFromCSI,
-		TriggerNodeEvent:      c.triggerNodeEvent,
+		Logger:                   c.logger,
+		DynamicRegistry:          c.dynamicRegistry,
+		UpdateNodeCSIInfoFunc:    c.batchNodeUpdates.updateNodeFromCSI,
+		UpdateCSIVolumeStatsFunc: c.updateCSIVolumeStats,
+		TriggerNodeEvent:         c.triggerNodeEvent,
 	}
 	csiManager := csimanager.New(csiConfig)
 	c.csimanager = csiManager
@@ -501,7 +502,7 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	go c.heartbeatStop.watch()
 
 	// Add the stats collector
-	statsCollector := stats.NewHostStatsCollector(c.logger, c.config.AllocDir, c.devicemanager.AllStats)
+	statsCollector := stats.NewHostStatsCollector(c.logger, c.config.AllocDir, c.devicemanager.AllStats, c.csimanager.VolumeStats)
 	c.hostStatsCollector = statsCollector
 
 	// Add the garbage collector
@@ -1770,6 +1771,24 @@ func (c *Client) submitNodeEvents(events []*structs.NodeEvent) error {
 	return nil
 }
 
+// updateCSIVolumeStats submits the stats of the CSI volumes mounted on the
+// node to the servers
+func (c *Client) updateCSIVolumeStats(volStats []*structs.CSIVolumeStats) error {
+	req := structs.CSIVolumeUpdateStatsRequest{
+		NodeID: c.NodeID(),
+		Stats:  volStats,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.Region(),
+			AuthToken: c.secretNodeID(),
+		},
+	}
+	var resp structs.CSIVolumeUpdateStatsResponse
+	if err := c.RPC("CSIVolume.UpdateStats", &req, &resp); err != nil {
+		return fmt.Errorf("Updating CSI volume stats failed: %v", err)
+	}
+	return nil
+}
+
 // watchNodeEvents is a handler which receives node events and on a interval
 // and submits them in batch format to the server
 func (c *Client) watchNodeEvents() {
@@ -2999,6 +3018,35 @@ func (c *Client) setGaugeForAllocationStats(nodeID string, baseLabels []metrics.
 	}
 }
 
+// setGaugeForCSIVolumeStats proxies metrics for the CSI volumes mounted on
+// the host
+func (c *Client) setGaugeForCSIVolumeStats(hStats *stats.HostStats, baseLabels []metrics.Label) {
+
+	labels := make([]metrics.Label, len(baseLabels))
+	copy(labels, baseLabels)
+
+	for _, vol := range hStats.CSIVolumeStats {
+		labels := append(labels,
+			metrics.Label{Name: "volume_id", Value: vol.VolumeID},
+			metrics.Label{Name: "namespace", Value: vol.Namespace},
+			metrics.Label{Name: "plugin_id", Value: vol.PluginID},
+		)
+
+		var abnormal float32
+		if vol.Abnormal {
+			abnormal = 1
+		}
+
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "total_bytes"}, float32(vol.TotalBytes), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "used_bytes"}, float32(vol.UsedBytes), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "available_bytes"}, float32(vol.AvailableBytes), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "used_percent"}, float32(vol.UsedPercent()), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "total_inodes"}, float32(vol.TotalInodes), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "used_inodes"}, float32(vol.UsedInodes), labels)
+		metrics.SetGaugeWithLabels([]string{"client", "csi", "volume", "abnormal"}, abnormal, labels)
+	}
+}
+
 // No labels are required so we emit with only a key/value syntax
 func (c *Client) setGaugeForUptime(hStats *stats.HostStats, baseLabels []metrics.Label) {
 	metrics.SetGaugeWithLabels([]string{"client", "uptime"}, float32(hStats.Uptime), baseLabels)
@@ -3014,6 +3062,7 @@ func (c *Client) emitHostStats() {
 	c.setGaugeForUptime(hStats, labels)
 	c.setGaugeForCPUStats(nodeID, hStats, labels)
 	c.setGaugeForDiskStats(nodeID, hStats, labels)
+	c.setGaugeForCSIVolumeStats(hStats, labels)
 }
 
 // emitClientMetrics emits lower volume client metrics
@@ -1120,12 +1120,14 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 		Networks:     client.configCopy.Node.NodeResources.Networks,
 		NodeNetworks: client.configCopy.Node.NodeResources.NodeNetworks,
 		Disk:         client.configCopy.Node.NodeResources.Disk,
+		Bandwidth:    client.configCopy.Node.NodeResources.Bandwidth,
 
 		// injected
 		Cpu: structs.NodeCpuResources{
 			CpuShares:          123,
 			ReservableCpuCores: client.configCopy.Node.NodeResources.Cpu.ReservableCpuCores,
 			TotalCpuCores:      client.configCopy.Node.NodeResources.Cpu.TotalCpuCores,
+			NumaNodes:          client.configCopy.Node.NodeResources.Cpu.NumaNodes,
 		},
 		Memory: structs.NodeMemoryResources{MemoryMB: 1024},
 		Devices: []*structs.NodeDeviceResource{
@@ -1162,12 +1164,14 @@ func TestClient_UpdateNodeFromDevicesAccumulates(t *testing.T) {
 		Networks:     client.configCopy.Node.NodeResources.Networks,
 		NodeNetworks: client.configCopy.Node.NodeResources.NodeNetworks,
 		Disk:         client.configCopy.Node.NodeResources.Disk,
+		Bandwidth:    client.configCopy.Node.NodeResources.Bandwidth,
 
 		// injected
 		Cpu: structs.NodeCpuResources{
 			CpuShares:          123,
 			ReservableCpuCores: client.configCopy.Node.NodeResources.Cpu.ReservableCpuCores,
 			TotalCpuCores:      client.configCopy.Node.NodeResources.Cpu.TotalCpuCores,
+			NumaNodes:          client.configCopy.Node.NodeResources.Cpu.NumaNodes,
 		},
 		Memory: structs.NodeMemoryResources{MemoryMB: 2048},
 		Devices: []*structs.NodeDeviceResource{
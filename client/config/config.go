@@ -116,6 +116,11 @@ type Config struct {
 	// determined dynamically.
 	MemoryMB int
 
+	// DiskIOPS and DiskMBps are the IO capacity of the disk backing the
+	// allocation directory. Disk IO can't be scheduled on the node if unset.
+	DiskIOPS int
+	DiskMBps int
+
 	// MaxKillTimeout allows capping the user-specifiable KillTimeout. If the
 	// task's KillTimeout is greater than the MaxKillTimeout, MaxKillTimeout is
 	// used.
@@ -221,6 +226,10 @@ type Config struct {
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig
 
+	// LogSinks restricts the destinations tasks may forward their logs to.
+	// Tasks may not use log sinks if it is nil.
+	LogSinks *LogSinkConfig
+
 	// RPCHoldTimeout is how long an RPC can be "held" before it is errored.
 	// This is used to paper over a loss of leadership by instead holding RPCs,
 	// so that the caller experiences a slow response rather than an error.
@@ -357,6 +366,36 @@ type ClientTemplateConfig struct {
 	VaultRetry *RetryConfig `hcl:"vault_retry,optional"`
 }
 
+// LogSinkConfig is configuration on the client restricting the destinations
+// tasks may forward their logs to with log sinks
+type LogSinkConfig struct {
+	// Enabled allows tasks to forward their logs to sinks. Tasks with log
+	// sinks fail to start on the client if it isn't set.
+	Enabled bool `hcl:"enabled"`
+
+	// AllowedAddresses are the host:port addresses of tcp, udp and remote
+	// syslog sinks tasks may forward their logs to. Addresses may be glob
+	// patterns.
+	AllowedAddresses []string `hcl:"allowed_addresses"`
+
+	// AllowedSockets are the paths of the unix sockets tasks may forward
+	// their logs to. Paths may be glob patterns.
+	AllowedSockets []string `hcl:"allowed_sockets"`
+}
+
+// Copy returns a deep copy of a LogSinkConfig
+func (c *LogSinkConfig) Copy() *LogSinkConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(LogSinkConfig)
+	*nc = *c
+	nc.AllowedAddresses = helper.CopySliceString(c.AllowedAddresses)
+	nc.AllowedSockets = helper.CopySliceString(c.AllowedSockets)
+	return nc
+}
+
 // Copy returns a deep copy of a ClientTemplateConfig
 func (c *ClientTemplateConfig) Copy() *ClientTemplateConfig {
 	if c == nil {
@@ -691,6 +730,7 @@ func (c *Config) Copy() *Config {
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
 	nc.TemplateConfig = c.TemplateConfig.Copy()
+	nc.LogSinks = c.LogSinks.Copy()
 	if c.ReservableCores != nil {
 		nc.ReservableCores = make([]uint16, len(c.ReservableCores))
 		copy(nc.ReservableCores, c.ReservableCores)
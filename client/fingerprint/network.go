@@ -109,6 +109,9 @@ func (f *NetworkFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 
 	resp.NodeResources = &structs.NodeResources{
 		Networks: nwResources,
+		Bandwidth: structs.NodeBandwidthResources{
+			EgressMbits: int64(mbits),
+		},
 	}
 
 	for _, nwResource := range nwResources {
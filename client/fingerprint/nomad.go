@@ -2,8 +2,10 @@ package fingerprint
 
 import (
 	"strconv"
+	"strings"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // NomadFingerprint is used to fingerprint the Nomad version
@@ -23,6 +25,31 @@ func (f *NomadFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerprin
 	resp.AddAttribute("nomad.version", req.Config.Version.VersionNumber())
 	resp.AddAttribute("nomad.revision", req.Config.Version.Revision)
 	resp.AddAttribute("nomad.service_discovery", strconv.FormatBool(req.Config.NomadServiceDiscovery))
+	f.fingerprintLogSinks(req, resp)
 	resp.Detected = true
 	return nil
 }
+
+// fingerprintLogSinks adds the attributes of the log sink types the client
+// allows, along with the destinations it allows, so that tasks forwarding
+// their logs are only placed on clients allowing their sinks.
+func (f *NomadFingerprint) fingerprintLogSinks(req *FingerprintRequest, resp *FingerprintResponse) {
+	policy := req.Config.LogSinks
+	if policy == nil || !policy.Enabled {
+		return
+	}
+
+	// Syslog sinks may always send to the local syslog daemon
+	types := []string{structs.LogSinkTypeSyslog}
+	if len(policy.AllowedAddresses) > 0 {
+		types = append(types, structs.LogSinkTypeTCP, structs.LogSinkTypeUDP)
+		resp.AddAttribute(structs.NodeAttrLogSinksAllowedAddresses, strings.Join(policy.AllowedAddresses, ","))
+	}
+	if len(policy.AllowedSockets) > 0 {
+		types = append(types, structs.LogSinkTypeUnix)
+		resp.AddAttribute(structs.NodeAttrLogSinksAllowedSockets, strings.Join(policy.AllowedSockets, ","))
+	}
+	for _, t := range types {
+		resp.AddAttribute(structs.NodeAttrLogSinksPrefix+t, "true")
+	}
+}
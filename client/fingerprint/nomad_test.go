@@ -61,3 +61,41 @@ func TestNomadFingerprint(t *testing.T) {
 	serviceDisco := response.Attributes["nomad.service_discovery"]
 	require.Equal(t, "true", serviceDisco, "service_discovery attr incorrect")
 }
+
+func TestNomadFingerprint_LogSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	f := NewNomadFingerprint(testlog.HCLogger(t))
+	fingerprint := func(c *config.Config) map[string]string {
+		c.Version = &version.VersionInfo{Version: "foo"}
+		request := &FingerprintRequest{Config: c, Node: &structs.Node{}}
+		var response FingerprintResponse
+		require.NoError(t, f.Fingerprint(request, &response))
+		return response.Attributes
+	}
+
+	// Nothing is fingerprinted unless log sinks are enabled
+	attrs := fingerprint(&config.Config{LogSinks: &config.LogSinkConfig{
+		AllowedAddresses: []string{"10.0.0.5:514"},
+	}})
+	for k := range attrs {
+		require.NotContains(t, k, "log_sinks")
+	}
+
+	// Only local syslog is allowed without allowed destinations
+	attrs = fingerprint(&config.Config{LogSinks: &config.LogSinkConfig{Enabled: true}})
+	require.Equal(t, "true", attrs["nomad.log_sinks.syslog"])
+	require.NotContains(t, attrs, "nomad.log_sinks.tcp")
+	require.NotContains(t, attrs, "nomad.log_sinks.unix")
+
+	attrs = fingerprint(&config.Config{LogSinks: &config.LogSinkConfig{
+		Enabled:          true,
+		AllowedAddresses: []string{"10.0.0.5:514", "logs.example.com:*"},
+		AllowedSockets:   []string{"/run/collector/*.sock"},
+	}})
+	for _, typ := range []string{"syslog", "tcp", "udp", "unix"} {
+		require.Equal(t, "true", attrs["nomad.log_sinks."+typ])
+	}
+	require.Equal(t, "10.0.0.5:514,logs.example.com:*", attrs["nomad.log_sinks.allowed_addresses"])
+	require.Equal(t, "/run/collector/*.sock", attrs["nomad.log_sinks.allowed_sockets"])
+}
@@ -53,8 +53,19 @@ func (f *StorageFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 	resp.NodeResources = &structs.NodeResources{
 		Disk: structs.NodeDiskResources{
 			DiskMB: int64(free / bytesPerMegabyte),
+			IOPS:   int64(cfg.DiskIOPS),
+			MBps:   int64(cfg.DiskMBps),
 		},
 	}
+
+	// Detect the disk used to limit the disk IO of tasks
+	if device, err := diskDevice(storageDir); err != nil {
+		f.logger.Warn("failed to detect disk device", "path", storageDir, "error", err)
+	} else if device != "" {
+		resp.AddAttribute("unique.storage.device", device)
+		resp.NodeResources.Disk.Device = device
+	}
+
 	resp.Detected = true
 
 	return nil
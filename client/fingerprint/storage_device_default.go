@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package fingerprint
+
+// diskDevice returns no device as disk IO is only limited on Linux.
+func diskDevice(path string) (string, error) {
+	return "", nil
+}
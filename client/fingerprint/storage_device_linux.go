@@ -0,0 +1,46 @@
+package fingerprint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// sysDevBlock is the sysfs directory of block devices by major:minor.
+const sysDevBlock = "/sys/dev/block"
+
+// diskDevice returns the major:minor of the disk holding the path. Disk IO
+// can only be limited for whole disks, so partitions are resolved to their
+// disk. An empty device is returned for virtual filesystems such as tmpfs
+// and overlay, which don't have a backing block device.
+func diskDevice(path string) (string, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return "", err
+	}
+
+	major, minor := unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev))
+	if major == 0 {
+		return "", nil
+	}
+	device := fmt.Sprintf("%d:%d", major, minor)
+
+	dir := filepath.Join(sysDevBlock, device)
+	if _, err := os.Stat(filepath.Join(dir, "partition")); err != nil {
+		return device, nil
+	}
+
+	// The device directory of a partition is nested in the one of its disk
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(filepath.Join(filepath.Dir(real), "dev"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -79,6 +79,8 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 	f := &FileSystem{c}
 	f.c.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.c.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.c.streamingRpcs.Register("FileSystem.Download", f.download)
+	f.c.streamingRpcs.Register("FileSystem.Upload", f.upload)
 	return f
 }
 
@@ -330,6 +332,193 @@ OUTER:
 	}
 }
 
+// download is used to stream a tar archive of a file or directory in an
+// allocation's directory.
+func (f *FileSystem) download(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "download"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsDownloadRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	if req.AllocID == "" {
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	alloc, err := f.c.GetAlloc(req.AllocID)
+	if err != nil {
+		handleStreamResultError(structs.NewErrUnknownAllocation(req.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+
+	// Check read permissions
+	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(403), encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+		handleStreamResultError(structs.ErrPermissionDenied, helper.Int64ToPtr(403), encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.Path == "" {
+		handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	fs, err := f.c.GetAllocFS(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	if _, err := fs.Stat(req.Path); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Archive the path in the background and send the archive in frames
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(fs.Archive(req.Path, pw))
+	}()
+
+	buf := make([]byte, streamFrameSize)
+	for {
+		n, err := io.ReadFull(pr, buf)
+		if n > 0 {
+			if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+				handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+				return
+			}
+		}
+
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			return
+		default:
+			handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		}
+	}
+}
+
+// upload is used to extract a tar archive into a directory in an
+// allocation's directory.
+func (f *FileSystem) upload(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "upload"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.FsUploadRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	if req.AllocID == "" {
+		handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	alloc, err := f.c.GetAlloc(req.AllocID)
+	if err != nil {
+		handleStreamResultError(structs.NewErrUnknownAllocation(req.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+
+	// Check write permissions
+	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(403), encoder)
+		return
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityWriteFS) {
+		handleStreamResultError(structs.ErrPermissionDenied, helper.Int64ToPtr(403), encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.Path == "" {
+		handleStreamResultError(pathNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	fs, err := f.c.GetAllocFS(req.AllocID)
+	if err != nil {
+		code := helper.Int64ToPtr(500)
+		if structs.IsErrUnknownAllocation(err) {
+			code = helper.Int64ToPtr(404)
+		}
+
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	fileInfo, err := fs.Stat(req.Path)
+	if err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if !fileInfo.IsDir {
+		handleStreamResultError(
+			fmt.Errorf("file %q is not a directory", req.Path),
+			helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Receive the frames of the archive in the background, until the empty
+	// frame marking its end
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			var frame cstructs.StreamErrWrapper
+			if err := decoder.Decode(&frame); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if frame.Error != nil {
+				pw.CloseWithError(frame.Error)
+				return
+			}
+			if len(frame.Payload) == 0 {
+				pw.Close()
+				return
+			}
+			if _, err := pw.Write(frame.Payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := fs.Extract(req.Path, pr); err != nil {
+		pr.CloseWithError(err)
+		handleStreamResultError(err, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	// Consume any padding after the end of the archive before replying
+	if _, err := io.Copy(io.Discard, pr); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+	encoder.Encode(&cstructs.StreamErrWrapper{})
+}
+
 // logs is is used to stream a task's logs.
 func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	defer metrics.MeasureSince([]string{"client", "file_system", "logs"}, time.Now())
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -827,6 +829,205 @@ OUTER:
 	}
 }
 
+// testArchive returns a tar archive holding a conf directory with a file.
+func testArchive(t *testing.T, contents string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "conf/", Mode: 0755, Typeflag: tar.TypeDir}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "conf/app.conf", Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+// testUpload uploads the archive with the FileSystem.Upload handler of the
+// client and returns the resulting error.
+func testUpload(t *testing.T, c *Client, req *cstructs.FsUploadRequest, archive []byte) *cstructs.RpcError {
+	handler, err := c.StreamingRpcHandler("FileSystem.Upload")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	// Send the request and the archive in the background as the handler
+	// may reply with an error before consuming it
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	go func() {
+		if err := encoder.Encode(req); err != nil {
+			return
+		}
+		if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: archive}); err != nil {
+			return
+		}
+		encoder.Encode(&cstructs.StreamErrWrapper{})
+	}()
+
+	var msg cstructs.StreamErrWrapper
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	require.NoError(t, decoder.Decode(&msg))
+	return msg.Error
+}
+
+func TestFS_Upload_Download(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for alloc to be running
+	alloc := testutil.WaitForRunning(t, s.RPC, job)[0]
+	qo := structs.QueryOptions{Region: "global"}
+
+	// Upload the archive
+	rpcErr := testUpload(t, c, &cstructs.FsUploadRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/data",
+		QueryOptions: qo,
+	}, testArchive(t, "hello"))
+	require.Nil(t, rpcErr)
+
+	// Archives can't write into the secrets directory
+	rpcErr = testUpload(t, c, &cstructs.FsUploadRequest{
+		AllocID:      alloc.ID,
+		Path:         "web/secrets",
+		QueryOptions: qo,
+	}, testArchive(t, "hello"))
+	require.NotNil(t, rpcErr)
+	require.Contains(t, rpcErr.Error(), "prohibited")
+
+	// Download the uploaded directory
+	handler, err := c.StreamingRpcHandler("FileSystem.Download")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(&cstructs.FsDownloadRequest{
+		AllocID:      alloc.ID,
+		Path:         "alloc/data/conf",
+		QueryOptions: qo,
+	}))
+
+	var archive bytes.Buffer
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	for {
+		var msg cstructs.StreamErrWrapper
+		if err := decoder.Decode(&msg); err != nil {
+			require.True(t, err == io.EOF || strings.Contains(err.Error(), "closed"), err)
+			break
+		}
+		require.Nil(t, msg.Error)
+		archive.Write(msg.Payload)
+	}
+
+	files := map[string]string{}
+	tr := tar.NewReader(&archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = string(data)
+	}
+	require.Equal(t, map[string]string{"conf/": "", "conf/app.conf": "hello"}, files)
+}
+
+func TestFS_Upload_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server
+	s, root, cleanupS := nomad.TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.ACLEnabled = true
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Reading the filesystem doesn't allow writing to it
+	policyBad := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadFS})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityWriteFS})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid", policyGood)
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	alloc := testutil.WaitForRunningWithToken(t, s.RPC, job, root.SecretID)[0]
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:  "good token",
+			Token: tokenGood.SecretID,
+		},
+		{
+			Name:  "root token",
+			Token: root.SecretID,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.FsUploadRequest{
+				AllocID: alloc.ID,
+				Path:    "alloc/data",
+				QueryOptions: structs.QueryOptions{
+					Namespace: structs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			rpcErr := testUpload(t, client, req, testArchive(t, c.Name))
+			if c.ExpectedError == "" {
+				require.Nil(t, rpcErr)
+			} else {
+				require.NotNil(t, rpcErr)
+				require.Contains(t, rpcErr.Error(), c.ExpectedError)
+			}
+		})
+	}
+}
+
 func TestFS_Logs_NoAlloc(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/numa"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/lib/cpuset"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -52,6 +53,10 @@ type cpusetManagerV1 struct {
 
 	parentCpuset cpuset.CPUSet
 
+	// numaNodes is the NUMA topology used to bind the memory of tasks with
+	// reserved cores to the NUMA nodes of their cores.
+	numaNodes []*structs.NodeNumaNode
+
 	// all exported functions are synchronized
 	mu sync.Mutex
 
@@ -62,6 +67,9 @@ type cpusetManagerV1 struct {
 	logger   hclog.Logger
 }
 
+// AddAlloc tracks the cpusets of the tasks of alloc. On cgroups v1 only the
+// cpuset controller is managed here, so the disk IO limits of tasks are not
+// enforced unlike on v2.
 func (c *cpusetManagerV1) AddAlloc(alloc *structs.Allocation) {
 	if alloc == nil || alloc.AllocatedResources == nil {
 		return
@@ -175,6 +183,10 @@ func (c *cpusetManagerV1) Init(_ []uint16) error {
 		return err
 	}
 
+	if c.numaNodes, err = numa.Scan(); err != nil {
+		c.logger.Warn("failed to detect NUMA topology, task memory will not be bound to NUMA nodes", "error", err)
+	}
+
 	c.doneCh = make(chan struct{})
 	c.signalCh = make(chan struct{})
 
@@ -255,15 +267,17 @@ func (c *cpusetManagerV1) reconcileCpusets() {
 			continue
 		}
 
-		// copy cpuset.mems from parent
+		// bind cpuset.mems to the NUMA nodes of the task's cores, falling back
+		// to the parent's
 		_, parentMems, err := getCpusetSubsystemSettingsV1(filepath.Dir(info.CgroupPath))
 		if err != nil {
 			c.logger.Error("failed to read parent cgroup settings for task", "path", info.CgroupPath, "error", err)
 			info.Error = err
 			continue
 		}
-		if err := cgroups.WriteFile(info.CgroupPath, "cpuset.mems", parentMems); err != nil {
-			c.logger.Error("failed to write cgroup cpuset.mems setting for task", "path", info.CgroupPath, "mems", parentMems, "error", err)
+		mems := c.taskMems(info.Cpuset, parentMems)
+		if err := cgroups.WriteFile(info.CgroupPath, "cpuset.mems", mems); err != nil {
+			c.logger.Error("failed to write cgroup cpuset.mems setting for task", "path", info.CgroupPath, "mems", mems, "error", err)
 			info.Error = err
 			continue
 		}
@@ -275,6 +289,21 @@ func (c *cpusetManagerV1) reconcileCpusets() {
 	}
 }
 
+// taskMems returns the cpuset.mems setting of a task with the given reserved
+// cores, which are the NUMA nodes local to the cores if they're allowed by the
+// parent's cpuset.mems and otherwise the parent's setting.
+func (c *cpusetManagerV1) taskMems(cores cpuset.CPUSet, parentMems string) string {
+	allowed, err := cpuset.Parse(parentMems)
+	if err != nil {
+		return parentMems
+	}
+	mems := numa.Mems(c.numaNodes, cores)
+	if mems.Size() == 0 || !mems.IsSubsetOf(allowed) {
+		return parentMems
+	}
+	return mems.String()
+}
+
 // setCgroupCpusetCPUs will compare an existing cpuset.cpus value with an expected value, overwriting the existing if different
 // must hold a lock on cpusetManagerV1.mu before calling
 func (_ *cpusetManagerV1) setCgroupCpusetCPUs(path, cpus string) error {
@@ -4,6 +4,7 @@ package cgutil
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/numa"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/lib/cpuset"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -50,10 +52,14 @@ type cpusetManagerV2 struct {
 	parentAbs string        // absolute path (e.g. "/sys/fs/cgroup/nomad.slice")
 	initial   cpuset.CPUSet // set of initial cores (never changes)
 
+	numaNodes []*structs.NodeNumaNode // NUMA topology used to bind the memory of isolating tasks
+
 	lock      sync.Mutex                 // hold this when managing pool / sharing / isolating
 	pool      cpuset.CPUSet              // pool of cores being shared among all tasks
 	sharing   map[identity]nothing       // sharing tasks using cores only from the pool
 	isolating map[identity]cpuset.CPUSet // isolating tasks using cores from the pool + reserved cores
+
+	diskIO map[identity]structs.AllocatedDiskIOResources // disk IO limits of tasks, written to io.max
 }
 
 func NewCpusetManagerV2(parent string, logger hclog.Logger) CpusetManager {
@@ -63,6 +69,7 @@ func NewCpusetManagerV2(parent string, logger hclog.Logger) CpusetManager {
 		logger:    logger,
 		sharing:   make(map[identity]nothing),
 		isolating: make(map[identity]cpuset.CPUSet),
+		diskIO:    make(map[identity]structs.AllocatedDiskIOResources),
 	}
 }
 
@@ -73,6 +80,12 @@ func (c *cpusetManagerV2) Init(cores []uint16) error {
 		return err
 	}
 	c.initial = cpuset.New(cores...)
+
+	numaNodes, err := numa.Scan()
+	if err != nil {
+		c.logger.Warn("failed to detect NUMA topology, task memory will not be bound to NUMA nodes", "err", err)
+	}
+	c.numaNodes = numaNodes
 	return nil
 }
 
@@ -94,6 +107,9 @@ func (c *cpusetManagerV2) AddAlloc(alloc *structs.Allocation) {
 		} else {
 			c.sharing[id] = present
 		}
+		if resources.DiskIO.Device != "" && !resources.DiskIO.IsZero() {
+			c.diskIO[id] = resources.DiskIO
+		}
 	}
 
 	// recompute the available sharable cpu cores
@@ -126,6 +142,13 @@ func (c *cpusetManagerV2) RemoveAlloc(allocID string) {
 		}
 	}
 
+	// remove the disk IO limits of tasks of allocID
+	for id := range c.diskIO {
+		if strings.HasPrefix(string(id), allocID) {
+			delete(c.diskIO, id)
+		}
+	}
+
 	// recompute available sharable cpu cores
 	c.recalculate()
 
@@ -180,11 +203,11 @@ func (c *cpusetManagerV2) recalculate() {
 // must be called while holding c.lock
 func (c *cpusetManagerV2) reconcile() {
 	for id := range c.sharing {
-		c.write(id, c.pool)
+		c.write(id, c.pool, cpuset.New())
 	}
 
 	for id, set := range c.isolating {
-		c.write(id, c.pool.Union(set))
+		c.write(id, c.pool.Union(set), numa.Mems(c.numaNodes, set))
 	}
 }
 
@@ -262,8 +285,9 @@ func (c *cpusetManagerV2) remove(path string) {
 	}
 }
 
-// write does the actual write of cpuset set for cgroup id
-func (c *cpusetManagerV2) write(id identity, set cpuset.CPUSet) {
+// write does the actual write of cpuset set for cgroup id, along with the set
+// of memory nodes if not empty and the disk IO limits of the task if any
+func (c *cpusetManagerV2) write(id identity, set, mems cpuset.CPUSet) {
 	path := c.pathOf(id)
 
 	// make a manager for the cgroup
@@ -277,14 +301,55 @@ func (c *cpusetManagerV2) write(id identity, set cpuset.CPUSet) {
 		c.logger.Error("failed to apply cgroup", "path", path, "err", err)
 	}
 
-	// set the cpuset value for the cgroup
-	if err = m.Set(&configs.Resources{
+	// set the cpuset values for the cgroup
+	resources := &configs.Resources{
 		CpusetCpus: set.String(),
-	}); err != nil {
+	}
+	if mems.Size() > 0 {
+		resources.CpusetMems = mems.String()
+	}
+	if diskIO, ok := c.diskIO[id]; ok {
+		if err = setDiskIO(resources, diskIO); err != nil {
+			c.logger.Error("failed to set disk IO limits", "path", path, "err", err)
+		}
+	}
+	if err = m.Set(resources); err != nil {
 		c.logger.Error("failed to set cgroup", "path", path, "err", err)
 	}
 }
 
+// setDiskIO sets the throttling of the disk of diskIO in resources, which is
+// written to the io.max interface file of the cgroup.
+func setDiskIO(resources *configs.Resources, diskIO structs.AllocatedDiskIOResources) error {
+	var major, minor int64
+	if _, err := fmt.Sscanf(diskIO.Device, "%d:%d", &major, &minor); err != nil {
+		return fmt.Errorf("invalid disk device %q: %v", diskIO.Device, err)
+	}
+
+	const bytesPerMB = 1024 * 1024
+	if diskIO.ReadIOPS > 0 {
+		resources.BlkioThrottleReadIOPSDevice = []*configs.ThrottleDevice{
+			configs.NewThrottleDevice(major, minor, uint64(diskIO.ReadIOPS)),
+		}
+	}
+	if diskIO.WriteIOPS > 0 {
+		resources.BlkioThrottleWriteIOPSDevice = []*configs.ThrottleDevice{
+			configs.NewThrottleDevice(major, minor, uint64(diskIO.WriteIOPS)),
+		}
+	}
+	if diskIO.ReadMBps > 0 {
+		resources.BlkioThrottleReadBpsDevice = []*configs.ThrottleDevice{
+			configs.NewThrottleDevice(major, minor, uint64(diskIO.ReadMBps)*bytesPerMB),
+		}
+	}
+	if diskIO.WriteMBps > 0 {
+		resources.BlkioThrottleWriteBpsDevice = []*configs.ThrottleDevice{
+			configs.NewThrottleDevice(major, minor, uint64(diskIO.WriteMBps)*bytesPerMB),
+		}
+	}
+	return nil
+}
+
 // ensureParentCgroup will create parent cgroup for the manager if it does not
 // exist yet. No PIDs are added to any cgroup yet.
 func (c *cpusetManagerV2) ensureParent() error {
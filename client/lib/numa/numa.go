@@ -0,0 +1,19 @@
+// Package numa discovers the NUMA topology of the host.
+package numa
+
+import (
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Mems returns the set of memory nodes local to the given cpus, which is the
+// set of IDs of the NUMA nodes containing any of them.
+func Mems(nodes []*structs.NodeNumaNode, cpus cpuset.CPUSet) cpuset.CPUSet {
+	mems := cpuset.New()
+	for _, node := range nodes {
+		if cpus.ContainsAny(cpuset.New(node.Cores...)) {
+			mems = mems.Union(cpuset.New(node.ID))
+		}
+	}
+	return mems
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package numa
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Scan returns the NUMA topology of the host, which is only discovered on
+// Linux.
+func Scan() ([]*structs.NodeNumaNode, error) {
+	return nil, nil
+}
@@ -0,0 +1,128 @@
+//go:build linux
+
+package numa
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// sysfsRoot is the mount point of sysfs.
+const sysfsRoot = "/sys"
+
+// Scan returns the NUMA topology of the host read from sysfs. It returns no
+// NUMA nodes if the kernel doesn't expose the topology.
+func Scan() ([]*structs.NodeNumaNode, error) {
+	return scan(sysfsRoot)
+}
+
+func scan(root string) ([]*structs.NodeNumaNode, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "devices/system/node/node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*structs.NodeNumaNode, 0, len(dirs))
+	for _, dir := range dirs {
+		id, err := strconv.ParseUint(strings.TrimPrefix(filepath.Base(dir), "node"), 10, 16)
+		if err != nil {
+			continue
+		}
+
+		node, err := scanNode(root, uint16(id), dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read NUMA node %d: %v", id, err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+func scanNode(root string, id uint16, dir string) (*structs.NodeNumaNode, error) {
+	cpulist, err := os.ReadFile(filepath.Join(dir, "cpulist"))
+	if err != nil {
+		return nil, err
+	}
+	cores, err := cpuset.Parse(string(cpulist))
+	if err != nil {
+		return nil, err
+	}
+
+	memoryMB, err := scanMemory(filepath.Join(dir, "meminfo"))
+	if err != nil {
+		return nil, err
+	}
+
+	siblings, err := scanSiblings(root, cores)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.NodeNumaNode{
+		ID:       id,
+		Cores:    cores.ToSlice(),
+		Siblings: siblings,
+		MemoryMB: memoryMB,
+	}, nil
+}
+
+// scanMemory returns the total memory of a NUMA node from its meminfo, which
+// has lines of the form "Node 0 MemTotal:       32768000 kB".
+func scanMemory(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[2] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MemTotal %q: %v", fields[3], err)
+		}
+		return kb / 1024, nil
+	}
+	return 0, scanner.Err()
+}
+
+// scanSiblings groups the cpus by physical core using the thread siblings of
+// each cpu.
+func scanSiblings(root string, cores cpuset.CPUSet) ([][]uint16, error) {
+	var siblings [][]uint16
+	seen := cpuset.New()
+	for _, cpu := range cores.ToSlice() {
+		if seen.ContainsAny(cpuset.New(cpu)) {
+			continue
+		}
+
+		path := filepath.Join(root, fmt.Sprintf("devices/system/cpu/cpu%d/topology/thread_siblings_list", cpu))
+		list, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		group, err := cpuset.Parse(string(list))
+		if err != nil {
+			return nil, err
+		}
+
+		group = group.Intersection(cores).Union(cpuset.New(cpu))
+		seen = seen.Union(group)
+		siblings = append(siblings, group.ToSlice())
+	}
+	return siblings, nil
+}
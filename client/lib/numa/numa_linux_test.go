@@ -0,0 +1,71 @@
+//go:build linux
+
+package numa
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSysfs writes a fake sysfs of two NUMA nodes with two physical cores of
+// two hyperthreads each.
+func writeSysfs(t *testing.T) string {
+	root := t.TempDir()
+
+	write := func(path, content string) {
+		path = filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("devices/system/node/node0/cpulist", "0-1,4-5\n")
+	write("devices/system/node/node0/meminfo", "Node 0 MemTotal:       16777216 kB\nNode 0 MemFree:        8388608 kB\n")
+	write("devices/system/node/node1/cpulist", "2-3,6-7\n")
+	write("devices/system/node/node1/meminfo", "Node 1 MemTotal:       8388608 kB\n")
+	write("devices/system/node/possible", "0-1\n")
+
+	for cpu := 0; cpu < 8; cpu++ {
+		write(fmt.Sprintf("devices/system/cpu/cpu%d/topology/thread_siblings_list", cpu), fmt.Sprintf("%d,%d\n", cpu%4, cpu%4+4))
+	}
+	return root
+}
+
+func TestScan(t *testing.T) {
+	ci.Parallel(t)
+
+	nodes, err := scan(writeSysfs(t))
+	require.NoError(t, err)
+	require.Equal(t, []*structs.NodeNumaNode{
+		{
+			ID:       0,
+			Cores:    []uint16{0, 1, 4, 5},
+			Siblings: [][]uint16{{0, 4}, {1, 5}},
+			MemoryMB: 16384,
+		},
+		{
+			ID:       1,
+			Cores:    []uint16{2, 3, 6, 7},
+			Siblings: [][]uint16{{2, 6}, {3, 7}},
+			MemoryMB: 8192,
+		},
+	}, nodes)
+
+	require.Equal(t, []uint16{1}, Mems(nodes, cpuset.New(2, 6)).ToSlice())
+	require.Equal(t, []uint16{0, 1}, Mems(nodes, cpuset.New(0, 3)).ToSlice())
+	require.Empty(t, Mems(nodes, cpuset.New()).ToSlice())
+}
+
+func TestScan_NoTopology(t *testing.T) {
+	ci.Parallel(t)
+
+	nodes, err := scan(t.TempDir())
+	require.NoError(t, err)
+	require.Empty(t, nodes)
+}
@@ -26,6 +26,8 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
 		StdoutFifo:     cfg.StdoutFifo,
 		StderrFifo:     cfg.StderrFifo,
+		Sinks:          sinksToProto(cfg.Sinks),
+		Metadata:       metadataToProto(cfg.Metadata),
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -42,3 +44,61 @@ func (c *logmonClient) Stop() error {
 	_, err := c.client.Stop(ctx, req)
 	return grpcutils.HandleGrpcErr(err, c.doneCtx)
 }
+
+func (c *logmonClient) Stats() ([]*SinkStats, error) {
+	req := &proto.StatsRequest{}
+	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
+	defer cancel()
+
+	resp, err := c.client.Stats(ctx, req)
+	if err != nil {
+		return nil, grpcutils.HandleGrpcErr(err, c.doneCtx)
+	}
+
+	stats := make([]*SinkStats, 0, len(resp.Sinks))
+	for _, s := range resp.Sinks {
+		stats = append(stats, &SinkStats{
+			Name:       s.Name,
+			Type:       s.Type,
+			Sent:       s.Sent,
+			Dropped:    s.Dropped,
+			Errors:     s.Errors,
+			Buffered:   int(s.Buffered),
+			BufferSize: int(s.BufferSize),
+		})
+	}
+	return stats, nil
+}
+
+func sinksToProto(sinks []*SinkConfig) []*proto.LogSink {
+	if len(sinks) == 0 {
+		return nil
+	}
+	out := make([]*proto.LogSink, 0, len(sinks))
+	for _, s := range sinks {
+		out = append(out, &proto.LogSink{
+			Name:       s.Name,
+			Type:       s.Type,
+			Address:    s.Address,
+			Facility:   s.Facility,
+			Tag:        s.Tag,
+			BufferSize: uint32(s.BufferSize),
+		})
+	}
+	return out
+}
+
+func metadataToProto(m *LogMetadata) *proto.LogMetadata {
+	if m == nil {
+		return nil
+	}
+	return &proto.LogMetadata{
+		Namespace: m.Namespace,
+		JobId:     m.JobID,
+		TaskGroup: m.TaskGroup,
+		AllocId:   m.AllocID,
+		AllocName: m.AllocName,
+		TaskName:  m.TaskName,
+		NodeId:    m.NodeID,
+	}
+}
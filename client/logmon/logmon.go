@@ -41,11 +41,19 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Sinks are destinations stdout and stderr are forwarded to in addition
+	// to the log files
+	Sinks []*SinkConfig
+
+	// Metadata identifies the task in records forwarded to sinks
+	Metadata *LogMetadata
 }
 
 type LogMon interface {
 	Start(*LogConfig) error
 	Stop() error
+	Stats() ([]*SinkStats, error)
 }
 
 func NewLogMon(logger hclog.Logger) LogMon {
@@ -98,6 +106,15 @@ func (l *logmonImpl) Stop() error {
 	return nil
 }
 
+func (l *logmonImpl) Stats() ([]*SinkStats, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.tl == nil {
+		return nil, nil
+	}
+	return l.tl.Stats(), nil
+}
+
 type TaskLogger struct {
 	config *LogConfig
 
@@ -106,6 +123,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// sinks stdout and stderr are forwarded to
+	sinks []*logSink
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -133,20 +153,68 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Close the sinks once the rotators have stopped writing to them
+	tl.closeSinks()
+}
+
+// closeSinks closes the task's sinks, flushing their buffered records
+func (tl *TaskLogger) closeSinks() {
+	var wg sync.WaitGroup
+	for _, s := range tl.sinks {
+		wg.Add(1)
+		go func(s *logSink) {
+			s.Close()
+			wg.Done()
+		}(s)
+	}
+	wg.Wait()
+}
+
+// Stats returns the stats of the task's sinks
+func (tl *TaskLogger) Stats() []*SinkStats {
+	stats := make([]*SinkStats, 0, len(tl.sinks))
+	for _, s := range tl.sinks {
+		stats = append(stats, s.Stats())
+	}
+	return stats
+}
+
+// withSinks returns a writer forwarding the stream's output to the task's
+// sinks in addition to the rotator
+func (tl *TaskLogger) withSinks(stream string, rotator io.WriteCloser) io.WriteCloser {
+	if len(tl.sinks) == 0 {
+		return rotator
+	}
+	return &sinkWriter{
+		rotator:   rotator,
+		forwarder: &lineForwarder{stream: stream, sinks: tl.sinks},
+	}
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
+	for _, sc := range cfg.Sinks {
+		s, err := newLogSink(sc, cfg.Metadata, logger)
+		if err != nil {
+			tl.closeSinks()
+			return nil, fmt.Errorf("failed to create log sink %q: %v", sc.Name, err)
+		}
+		tl.sinks = append(tl.sinks, s)
+	}
+
 	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
 	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSinks()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, tl.withSinks("stdout", lro))
 	if err != nil {
+		tl.closeSinks()
 		return nil, err
 	}
 
@@ -155,11 +223,13 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
 		cfg.MaxFiles, logFileSize, logger)
 	if err != nil {
+		tl.closeSinks()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, tl.withSinks("stderr", lre))
 	if err != nil {
+		tl.closeSinks()
 		return nil, err
 	}
 
@@ -25,16 +25,18 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string       `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string       `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string       `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32       `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32       `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string       `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string       `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Sinks                []*LogSink   `protobuf:"bytes,8,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	Metadata             *LogMetadata `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -111,6 +113,20 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetMetadata() *LogMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -204,11 +220,344 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+// LogSink is a destination logmon forwards task logs to in addition to the
+// rotated log files.
+type LogSink struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Address              string   `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Facility             string   `protobuf:"bytes,4,opt,name=facility,proto3" json:"facility,omitempty"`
+	Tag                  string   `protobuf:"bytes,5,opt,name=tag,proto3" json:"tag,omitempty"`
+	BufferSize           uint32   `protobuf:"varint,6,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LogSink) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *LogSink) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *LogSink) GetFacility() string {
+	if m != nil {
+		return m.Facility
+	}
+	return ""
+}
+
+func (m *LogSink) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *LogSink) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
+// LogMetadata identifies the task whose logs are forwarded to sinks.
+type LogMetadata struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	JobId                string   `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskGroup            string   `protobuf:"bytes,3,opt,name=task_group,json=taskGroup,proto3" json:"task_group,omitempty"`
+	AllocId              string   `protobuf:"bytes,4,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	AllocName            string   `protobuf:"bytes,5,opt,name=alloc_name,json=allocName,proto3" json:"alloc_name,omitempty"`
+	TaskName             string   `protobuf:"bytes,6,opt,name=task_name,json=taskName,proto3" json:"task_name,omitempty"`
+	NodeId               string   `protobuf:"bytes,7,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogMetadata) Reset()         { *m = LogMetadata{} }
+func (m *LogMetadata) String() string { return proto.CompactTextString(m) }
+func (*LogMetadata) ProtoMessage()    {}
+func (*LogMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{5}
+}
+
+func (m *LogMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogMetadata.Unmarshal(m, b)
+}
+func (m *LogMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogMetadata.Marshal(b, m, deterministic)
+}
+func (m *LogMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogMetadata.Merge(m, src)
+}
+func (m *LogMetadata) XXX_Size() int {
+	return xxx_messageInfo_LogMetadata.Size(m)
+}
+func (m *LogMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogMetadata proto.InternalMessageInfo
+
+func (m *LogMetadata) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *LogMetadata) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *LogMetadata) GetTaskGroup() string {
+	if m != nil {
+		return m.TaskGroup
+	}
+	return ""
+}
+
+func (m *LogMetadata) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *LogMetadata) GetAllocName() string {
+	if m != nil {
+		return m.AllocName
+	}
+	return ""
+}
+
+func (m *LogMetadata) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *LogMetadata) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+type StatsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatsRequest) Reset()         { *m = StatsRequest{} }
+func (m *StatsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatsRequest) ProtoMessage()    {}
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{6}
+}
+
+func (m *StatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsRequest.Unmarshal(m, b)
+}
+func (m *StatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsRequest.Merge(m, src)
+}
+func (m *StatsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatsRequest.Size(m)
+}
+func (m *StatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsRequest proto.InternalMessageInfo
+
+type StatsResponse struct {
+	Sinks                []*SinkStats `protobuf:"bytes,1,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
+func (m *StatsResponse) String() string { return proto.CompactTextString(m) }
+func (*StatsResponse) ProtoMessage()    {}
+func (*StatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{7}
+}
+
+func (m *StatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatsResponse.Unmarshal(m, b)
+}
+func (m *StatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatsResponse.Marshal(b, m, deterministic)
+}
+func (m *StatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatsResponse.Merge(m, src)
+}
+func (m *StatsResponse) XXX_Size() int {
+	return xxx_messageInfo_StatsResponse.Size(m)
+}
+func (m *StatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatsResponse proto.InternalMessageInfo
+
+func (m *StatsResponse) GetSinks() []*SinkStats {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+// SinkStats reports the records forwarded to a sink and the state of its
+// buffer.
+type SinkStats struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Sent                 uint64   `protobuf:"varint,3,opt,name=sent,proto3" json:"sent,omitempty"`
+	Dropped              uint64   `protobuf:"varint,4,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Errors               uint64   `protobuf:"varint,5,opt,name=errors,proto3" json:"errors,omitempty"`
+	Buffered             uint32   `protobuf:"varint,6,opt,name=buffered,proto3" json:"buffered,omitempty"`
+	BufferSize           uint32   `protobuf:"varint,7,opt,name=buffer_size,json=bufferSize,proto3" json:"buffer_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SinkStats) Reset()         { *m = SinkStats{} }
+func (m *SinkStats) String() string { return proto.CompactTextString(m) }
+func (*SinkStats) ProtoMessage()    {}
+func (*SinkStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{8}
+}
+
+func (m *SinkStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SinkStats.Unmarshal(m, b)
+}
+func (m *SinkStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SinkStats.Marshal(b, m, deterministic)
+}
+func (m *SinkStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SinkStats.Merge(m, src)
+}
+func (m *SinkStats) XXX_Size() int {
+	return xxx_messageInfo_SinkStats.Size(m)
+}
+func (m *SinkStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_SinkStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SinkStats proto.InternalMessageInfo
+
+func (m *SinkStats) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SinkStats) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SinkStats) GetSent() uint64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+func (m *SinkStats) GetDropped() uint64 {
+	if m != nil {
+		return m.Dropped
+	}
+	return 0
+}
+
+func (m *SinkStats) GetErrors() uint64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *SinkStats) GetBuffered() uint32 {
+	if m != nil {
+		return m.Buffered
+	}
+	return 0
+}
+
+func (m *SinkStats) GetBufferSize() uint32 {
+	if m != nil {
+		return m.BufferSize
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
+	proto.RegisterType((*LogMetadata)(nil), "hashicorp.nomad.client.logmon.proto.LogMetadata")
+	proto.RegisterType((*StatsRequest)(nil), "hashicorp.nomad.client.logmon.proto.StatsRequest")
+	proto.RegisterType((*StatsResponse)(nil), "hashicorp.nomad.client.logmon.proto.StatsResponse")
+	proto.RegisterType((*SinkStats)(nil), "hashicorp.nomad.client.logmon.proto.SinkStats")
 }
 
 func init() {
@@ -216,27 +565,48 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 644 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xad, 0x1b, 0xc7, 0x8e, 0x27, 0x4d, 0xa9, 0x56, 0x82, 0x9a, 0x02, 0x22, 0x32, 0x07, 0x72,
+	0x40, 0x6e, 0x1b, 0xfe, 0xa0, 0xaa, 0x40, 0x95, 0x5a, 0x0e, 0x8e, 0xb8, 0x70, 0xb1, 0x36, 0xf1,
+	0x3a, 0x75, 0x6b, 0x7b, 0xcd, 0xee, 0x46, 0x6a, 0xfb, 0x1f, 0x5c, 0xf8, 0x10, 0xfe, 0x83, 0x33,
+	0x3f, 0x83, 0x76, 0x76, 0xed, 0x46, 0x3d, 0xb9, 0xa7, 0xec, 0x9b, 0x79, 0xb3, 0x7e, 0x33, 0x6f,
+	0x36, 0x30, 0x5d, 0x95, 0x05, 0xab, 0xd5, 0x71, 0xc9, 0xd7, 0x15, 0xaf, 0x8f, 0x1b, 0xc1, 0x15,
+	0xb7, 0x20, 0x46, 0x40, 0x3e, 0x5c, 0x53, 0x79, 0x5d, 0xac, 0xb8, 0x68, 0xe2, 0x9a, 0x57, 0x34,
+	0x8b, 0x4d, 0x45, 0xbc, 0x4d, 0x8a, 0x7e, 0x0d, 0x60, 0x6f, 0xa1, 0xa8, 0x50, 0x09, 0xfb, 0xb9,
+	0x61, 0x52, 0x91, 0x43, 0xf0, 0x4b, 0xbe, 0x4e, 0xb3, 0x42, 0x84, 0xce, 0xd4, 0x99, 0x05, 0x89,
+	0x57, 0xf2, 0xf5, 0x79, 0x21, 0xc8, 0x0c, 0x0e, 0xa4, 0xca, 0xf8, 0x46, 0xa5, 0x79, 0x51, 0xb2,
+	0xb4, 0xa6, 0x15, 0x0b, 0x77, 0x91, 0xb1, 0x6f, 0xe2, 0x5f, 0x8a, 0x92, 0x7d, 0xa3, 0x15, 0xb3,
+	0x4c, 0x26, 0xc4, 0x16, 0x73, 0xd0, 0x31, 0x99, 0x10, 0x1d, 0xf3, 0x0d, 0x04, 0x15, 0xbd, 0x43,
+	0x9a, 0x0c, 0xdd, 0xa9, 0x33, 0x9b, 0x24, 0xa3, 0x8a, 0xde, 0xe9, 0xbc, 0x24, 0x1f, 0xe1, 0xa0,
+	0x4d, 0xa6, 0xb2, 0x78, 0x60, 0x69, 0xb5, 0x0c, 0x87, 0xc8, 0x99, 0x58, 0xce, 0xa2, 0x78, 0x60,
+	0x57, 0x4b, 0xf2, 0x1e, 0xc6, 0x9d, 0xb2, 0x9c, 0x87, 0x1e, 0x7e, 0x0a, 0x5a, 0x51, 0x39, 0xb7,
+	0x04, 0x23, 0x28, 0xe7, 0xa1, 0xdf, 0x11, 0x50, 0x4b, 0xce, 0xc9, 0x19, 0x0c, 0x65, 0x51, 0xdf,
+	0xca, 0x70, 0x34, 0x1d, 0xcc, 0xc6, 0xf3, 0x4f, 0x71, 0x8f, 0xd1, 0xc5, 0x97, 0x7c, 0xbd, 0x28,
+	0xea, 0xdb, 0xc4, 0x94, 0x92, 0x4b, 0x18, 0x55, 0x4c, 0xd1, 0x8c, 0x2a, 0x1a, 0x06, 0x53, 0x67,
+	0x36, 0x9e, 0x9f, 0xf4, 0xbd, 0xe6, 0xca, 0xd6, 0x25, 0xdd, 0x0d, 0xd1, 0x0b, 0x98, 0x58, 0x5b,
+	0x64, 0xc3, 0x6b, 0xc9, 0xa2, 0x09, 0x8c, 0x17, 0x8a, 0x37, 0xd6, 0xa6, 0x68, 0x1f, 0xf6, 0x0c,
+	0xb4, 0xe9, 0xdf, 0x0e, 0xf8, 0x56, 0x10, 0x21, 0xe0, 0xe2, 0xcc, 0x8d, 0x7f, 0x78, 0xd6, 0x31,
+	0x75, 0xdf, 0xb4, 0x8e, 0xe1, 0x99, 0x84, 0xe0, 0xd3, 0x2c, 0x13, 0x4c, 0x4a, 0x6b, 0x4f, 0x0b,
+	0xc9, 0x11, 0x8c, 0x72, 0xba, 0x2a, 0xca, 0x42, 0xdd, 0xa3, 0x2d, 0x41, 0xd2, 0x61, 0x72, 0x00,
+	0x03, 0x45, 0xd7, 0xe8, 0x44, 0x90, 0xe8, 0xa3, 0x1e, 0xef, 0x72, 0x93, 0xe7, 0x4c, 0xa0, 0x4d,
+	0x38, 0xff, 0x49, 0x02, 0x26, 0xa4, 0x2d, 0x8a, 0xfe, 0x3a, 0x30, 0xde, 0x6a, 0x93, 0xbc, 0x85,
+	0x40, 0x8b, 0x92, 0x0d, 0x5d, 0xb5, 0x2a, 0x1f, 0x03, 0xe4, 0x25, 0x78, 0x37, 0x7c, 0x99, 0x16,
+	0x99, 0x15, 0x3b, 0xbc, 0xe1, 0xcb, 0x8b, 0x8c, 0xbc, 0x03, 0x50, 0x54, 0xde, 0xa6, 0x6b, 0xc1,
+	0x37, 0x8d, 0x15, 0x1c, 0xe8, 0xc8, 0x57, 0x1d, 0x20, 0xaf, 0x61, 0x44, 0xcb, 0x92, 0xaf, 0x74,
+	0x9d, 0x6b, 0xbb, 0xd1, 0xd8, 0x54, 0x9a, 0x14, 0x4e, 0xc5, 0x08, 0x0f, 0x30, 0xd2, 0x2e, 0x21,
+	0x5e, 0x8c, 0x59, 0xb3, 0x3c, 0x23, 0x1d, 0xc0, 0xe4, 0x21, 0xf8, 0x35, 0xcf, 0x98, 0xbe, 0xd5,
+	0xac, 0x8d, 0xa7, 0xe1, 0x45, 0x66, 0x0c, 0xa0, 0x4a, 0xb6, 0x86, 0x7c, 0x87, 0x89, 0xc5, 0xc6,
+	0x11, 0x72, 0xde, 0xee, 0x94, 0x83, 0x3b, 0x15, 0xf7, 0x5a, 0x06, 0xed, 0x9f, 0xb9, 0xc6, 0x14,
+	0x47, 0x7f, 0x1c, 0x08, 0xba, 0x60, 0x6f, 0x67, 0x09, 0xb8, 0x92, 0xd5, 0x0a, 0xa7, 0xe4, 0x26,
+	0x78, 0xd6, 0x6e, 0x67, 0x82, 0x37, 0x0d, 0x33, 0xf3, 0x71, 0x93, 0x16, 0x92, 0x57, 0xe0, 0x31,
+	0x21, 0xb8, 0x90, 0x38, 0x1b, 0x37, 0xb1, 0x48, 0x6f, 0x81, 0x31, 0x91, 0x65, 0xd6, 0xd4, 0x0e,
+	0x3f, 0xf5, 0xdc, 0x7f, 0xea, 0xf9, 0xfc, 0xdf, 0x2e, 0x78, 0xda, 0x73, 0x5e, 0x93, 0x06, 0x86,
+	0xb8, 0xcb, 0xe4, 0xb4, 0xdf, 0x0c, 0xb6, 0xfe, 0x8e, 0x8e, 0xe6, 0xcf, 0x29, 0xb1, 0x6f, 0x61,
+	0x87, 0x54, 0xe0, 0xea, 0xd7, 0x41, 0x4e, 0x7a, 0x56, 0x77, 0xef, 0xea, 0xe8, 0xf4, 0x19, 0x15,
+	0xdd, 0xe7, 0x4c, 0x83, 0x4a, 0xf6, 0x6f, 0x50, 0xc9, 0x67, 0x37, 0xf8, 0xb8, 0x5a, 0xd1, 0xce,
+	0x99, 0xff, 0x63, 0x88, 0x89, 0xa5, 0x87, 0x3f, 0x9f, 0xff, 0x0f, 0x00, 0xc4, 0x56, 0xd4, 0x0e,
+	0x0f, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -253,6 +623,7 @@ const _ = grpc.SupportPackageIsVersion6
 type LogMonClient interface {
 	Start(ctx context.Context, in *StartRequest, opts ...grpc.CallOption) (*StartResponse, error)
 	Stop(ctx context.Context, in *StopRequest, opts ...grpc.CallOption) (*StopResponse, error)
+	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 }
 
 type logMonClient struct {
@@ -281,10 +652,20 @@ func (c *logMonClient) Stop(ctx context.Context, in *StopRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *logMonClient) Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.client.logmon.proto.LogMon/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogMonServer is the server API for LogMon service.
 type LogMonServer interface {
 	Start(context.Context, *StartRequest) (*StartResponse, error)
 	Stop(context.Context, *StopRequest) (*StopResponse, error)
+	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 }
 
 // UnimplementedLogMonServer can be embedded to have forward compatible implementations.
@@ -297,6 +678,9 @@ func (*UnimplementedLogMonServer) Start(ctx context.Context, req *StartRequest)
 func (*UnimplementedLogMonServer) Stop(ctx context.Context, req *StopRequest) (*StopResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Stop not implemented")
 }
+func (*UnimplementedLogMonServer) Stats(ctx context.Context, req *StatsRequest) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
 
 func RegisterLogMonServer(s *grpc.Server, srv LogMonServer) {
 	s.RegisterService(&_LogMon_serviceDesc, srv)
@@ -338,6 +722,24 @@ func _LogMon_Stop_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogMon_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogMonServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.client.logmon.proto.LogMon/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogMonServer).Stats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LogMon_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "hashicorp.nomad.client.logmon.proto.LogMon",
 	HandlerType: (*LogMonServer)(nil),
@@ -350,6 +752,10 @@ var _LogMon_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stop",
 			Handler:    _LogMon_Stop_Handler,
 		},
+		{
+			MethodName: "Stats",
+			Handler:    _LogMon_Stats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "client/logmon/proto/logmon.proto",
@@ -21,6 +21,8 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 		MaxFileSizeMB: int(req.MaxFileSizeMb),
 		StdoutFifo:    req.StdoutFifo,
 		StderrFifo:    req.StderrFifo,
+		Sinks:         sinksFromProto(req.Sinks),
+		Metadata:      metadataFromProto(req.Metadata),
 	}
 
 	err := s.impl.Start(cfg)
@@ -34,3 +36,57 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 func (s *logmonServer) Stop(ctx context.Context, req *proto.StopRequest) (*proto.StopResponse, error) {
 	return &proto.StopResponse{}, s.impl.Stop()
 }
+
+func (s *logmonServer) Stats(ctx context.Context, req *proto.StatsRequest) (*proto.StatsResponse, error) {
+	stats, err := s.impl.Stats()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.StatsResponse{}
+	for _, st := range stats {
+		resp.Sinks = append(resp.Sinks, &proto.SinkStats{
+			Name:       st.Name,
+			Type:       st.Type,
+			Sent:       st.Sent,
+			Dropped:    st.Dropped,
+			Errors:     st.Errors,
+			Buffered:   uint32(st.Buffered),
+			BufferSize: uint32(st.BufferSize),
+		})
+	}
+	return resp, nil
+}
+
+func sinksFromProto(sinks []*proto.LogSink) []*SinkConfig {
+	if len(sinks) == 0 {
+		return nil
+	}
+	out := make([]*SinkConfig, 0, len(sinks))
+	for _, s := range sinks {
+		out = append(out, &SinkConfig{
+			Name:       s.Name,
+			Type:       s.Type,
+			Address:    s.Address,
+			Facility:   s.Facility,
+			Tag:        s.Tag,
+			BufferSize: int(s.BufferSize),
+		})
+	}
+	return out
+}
+
+func metadataFromProto(m *proto.LogMetadata) *LogMetadata {
+	if m == nil {
+		return nil
+	}
+	return &LogMetadata{
+		Namespace: m.Namespace,
+		JobID:     m.JobId,
+		TaskGroup: m.TaskGroup,
+		AllocID:   m.AllocId,
+		AllocName: m.AllocName,
+		TaskName:  m.TaskName,
+		NodeID:    m.NodeId,
+	}
+}
@@ -0,0 +1,525 @@
+package logmon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// sinkDialTimeout is the timeout for connecting to a sink
+	sinkDialTimeout = 5 * time.Second
+
+	// sinkWriteTimeout is the timeout for writing a single record to a sink
+	sinkWriteTimeout = 5 * time.Second
+
+	// sinkMinBackoff and sinkMaxBackoff bound the time waited before
+	// reconnecting to an unavailable sink
+	sinkMinBackoff = 250 * time.Millisecond
+	sinkMaxBackoff = 30 * time.Second
+
+	// sinkDrainTimeout is the length of time buffered records are flushed
+	// for when the sink is closed
+	sinkDrainTimeout = 5 * time.Second
+
+	// defaultSinkBufferSize is the number of records buffered by sinks
+	// whose configuration doesn't set a buffer size
+	defaultSinkBufferSize = 1024
+
+	// maxLineSize is the length after which a line without a newline is
+	// forwarded as a record of its own
+	maxLineSize = 64 * 1024
+)
+
+// syslogFacilities maps syslog facility names to their codes
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+const (
+	syslogSeverityErr  = 3
+	syslogSeverityInfo = 6
+)
+
+// SinkConfig configures a destination the task's logs are forwarded to in
+// addition to the rotated log files.
+type SinkConfig struct {
+	// Name uniquely identifies the sink within the task
+	Name string
+
+	// Type is one of syslog, tcp, udp or unix
+	Type string
+
+	// Address is the host:port of tcp and udp sinks, the socket path of unix
+	// sinks and the URL of syslog sinks
+	Address string
+
+	// Facility and Tag set the facility and APP-NAME of syslog messages
+	Facility string
+	Tag      string
+
+	// BufferSize is the number of records buffered while the sink is
+	// unavailable before records are dropped
+	BufferSize int
+}
+
+// LogMetadata identifies the task whose logs are forwarded to sinks. It is
+// included in every record.
+type LogMetadata struct {
+	Namespace string
+	JobID     string
+	TaskGroup string
+	AllocID   string
+	AllocName string
+	TaskName  string
+	NodeID    string
+}
+
+// SinkStats reports the records forwarded to a sink and the state of its
+// buffer.
+type SinkStats struct {
+	Name string
+	Type string
+
+	// Sent is the number of records written to the sink
+	Sent uint64
+
+	// Dropped is the number of records dropped because the sink's buffer
+	// was full
+	Dropped uint64
+
+	// Errors is the number of failed attempts to connect or write to the
+	// sink
+	Errors uint64
+
+	// Buffered is the number of records waiting to be written and
+	// BufferSize the capacity of the buffer
+	Buffered   int
+	BufferSize int
+}
+
+// logRecord is a line of a task's output
+type logRecord struct {
+	time   time.Time
+	stream string
+	line   []byte
+}
+
+// jsonRecord is the encoding of records sent to tcp, udp and unix sinks
+type jsonRecord struct {
+	Time      string `json:"time"`
+	Stream    string `json:"stream"`
+	Message   string `json:"message"`
+	Namespace string `json:"namespace,omitempty"`
+	JobID     string `json:"job_id,omitempty"`
+	TaskGroup string `json:"task_group,omitempty"`
+	AllocID   string `json:"alloc_id,omitempty"`
+	AllocName string `json:"alloc_name,omitempty"`
+	TaskName  string `json:"task_name,omitempty"`
+	NodeID    string `json:"node_id,omitempty"`
+}
+
+// logSink forwards records to a destination. Records are queued in a buffer
+// of fixed size, written by a single goroutine, and dropped when the buffer
+// is full so that an unavailable sink never blocks the task's output.
+type logSink struct {
+	config *SinkConfig
+	meta   *LogMetadata
+	logger hclog.Logger
+
+	network  string
+	address  string
+	hostname string
+
+	// framed is true for syslog sinks over a stream connection, whose
+	// messages are prefixed by their length
+	framed bool
+
+	records chan *logRecord
+	conn    net.Conn
+
+	sent    uint64
+	dropped uint64
+	errors  uint64
+
+	shutdownCh chan struct{}
+	doneCh     chan struct{}
+	closeOnce  sync.Once
+}
+
+// newLogSink returns a sink forwarding records to the configured destination.
+// The sink connects lazily so a destination that is not yet available doesn't
+// prevent the task from starting.
+func newLogSink(cfg *SinkConfig, meta *LogMetadata, logger hclog.Logger) (*logSink, error) {
+	if meta == nil {
+		meta = &LogMetadata{}
+	}
+	size := cfg.BufferSize
+	if size <= 0 {
+		size = defaultSinkBufferSize
+	}
+
+	s := &logSink{
+		config:     cfg,
+		meta:       meta,
+		logger:     logger.With("sink", cfg.Name, "type", cfg.Type),
+		records:    make(chan *logRecord, size),
+		shutdownCh: make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+
+	var err error
+	s.network, s.address, err = sinkDestination(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Type == "syslog" {
+		if _, ok := syslogFacilities[s.facility()]; !ok {
+			return nil, fmt.Errorf("invalid syslog facility %q", cfg.Facility)
+		}
+		s.framed = s.network == "tcp" || s.network == "unix"
+		s.hostname, _ = os.Hostname()
+		if s.hostname == "" {
+			s.hostname = "-"
+		}
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// sinkDestination returns the network and address the sink connects to. Both
+// are empty for syslog sinks connecting to the local syslog daemon.
+func sinkDestination(cfg *SinkConfig) (string, string, error) {
+	sink := &structs.LogSink{Name: cfg.Name, Type: cfg.Type, Address: cfg.Address}
+	return sink.Destination()
+}
+
+// enqueue queues a record to be written to the sink, dropping it if the
+// sink's buffer is full.
+func (s *logSink) enqueue(rec *logRecord) {
+	select {
+	case s.records <- rec:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Stats returns the sink's counters
+func (s *logSink) Stats() *SinkStats {
+	return &SinkStats{
+		Name:       s.config.Name,
+		Type:       s.config.Type,
+		Sent:       atomic.LoadUint64(&s.sent),
+		Dropped:    atomic.LoadUint64(&s.dropped),
+		Errors:     atomic.LoadUint64(&s.errors),
+		Buffered:   len(s.records),
+		BufferSize: cap(s.records),
+	}
+}
+
+// Close stops the sink after flushing buffered records for up to
+// sinkDrainTimeout.
+func (s *logSink) Close() {
+	s.closeOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+	<-s.doneCh
+}
+
+func (s *logSink) run() {
+	defer close(s.doneCh)
+	defer func() {
+		if s.conn != nil {
+			s.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case rec := <-s.records:
+			s.send(rec)
+		case <-s.shutdownCh:
+			s.drain()
+			return
+		}
+	}
+}
+
+// send writes the record to the sink, reconnecting with backoff until it is
+// written or the sink is closed. While the sink is unavailable its buffer
+// fills up and new records are dropped by enqueue.
+func (s *logSink) send(rec *logRecord) {
+	backoff := sinkMinBackoff
+	for {
+		err := s.write(rec)
+		if err == nil {
+			atomic.AddUint64(&s.sent, 1)
+			return
+		}
+
+		// Only log the first failure of an outage to avoid flooding the logs
+		if backoff == sinkMinBackoff {
+			s.logger.Warn("failed to write to log sink", "error", err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.shutdownCh:
+			atomic.AddUint64(&s.dropped, 1)
+			return
+		}
+
+		backoff *= 2
+		if backoff > sinkMaxBackoff {
+			backoff = sinkMaxBackoff
+		}
+	}
+}
+
+// drain writes the buffered records once the sink is closed, giving up on
+// the first failure or after sinkDrainTimeout.
+func (s *logSink) drain() {
+	deadline := time.Now().Add(sinkDrainTimeout)
+	for {
+		select {
+		case rec := <-s.records:
+			if time.Now().After(deadline) || s.write(rec) != nil {
+				atomic.AddUint64(&s.dropped, uint64(len(s.records)+1))
+				return
+			}
+			atomic.AddUint64(&s.sent, 1)
+		default:
+			return
+		}
+	}
+}
+
+// write encodes and writes a record, connecting to the sink if needed
+func (s *logSink) write(rec *logRecord) error {
+	if s.conn == nil {
+		conn, err := s.dial()
+		if err != nil {
+			atomic.AddUint64(&s.errors, 1)
+			return err
+		}
+		s.conn = conn
+	}
+
+	s.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+	if _, err := s.conn.Write(s.encode(rec)); err != nil {
+		atomic.AddUint64(&s.errors, 1)
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// dial connects to the sink. Syslog sinks without an address connect to the
+// local syslog daemon.
+func (s *logSink) dial() (net.Conn, error) {
+	if s.network != "" {
+		return net.DialTimeout(s.network, s.address, sinkDialTimeout)
+	}
+
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, path := range []string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+			conn, err := net.DialTimeout(network, path, sinkDialTimeout)
+			if err == nil {
+				s.framed = network == "unix"
+				return conn, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unable to connect to the local syslog daemon")
+}
+
+// encode returns the record as written to the sink
+func (s *logSink) encode(rec *logRecord) []byte {
+	if s.config.Type == "syslog" {
+		return s.encodeSyslog(rec)
+	}
+
+	buf, _ := json.Marshal(&jsonRecord{
+		Time:      rec.time.UTC().Format(time.RFC3339Nano),
+		Stream:    rec.stream,
+		Message:   string(rec.line),
+		Namespace: s.meta.Namespace,
+		JobID:     s.meta.JobID,
+		TaskGroup: s.meta.TaskGroup,
+		AllocID:   s.meta.AllocID,
+		AllocName: s.meta.AllocName,
+		TaskName:  s.meta.TaskName,
+		NodeID:    s.meta.NodeID,
+	})
+	return append(buf, '\n')
+}
+
+// encodeSyslog encodes the record as an RFC 5424 message. The APP-NAME is
+// the sink's tag or the task's name, the PROCID the allocation's ID and the
+// MSGID the stream the line was written to. Lines written to stderr are
+// sent with the err severity and those written to stdout with info.
+func (s *logSink) encodeSyslog(rec *logRecord) []byte {
+	severity := syslogSeverityInfo
+	if rec.stream == "stderr" {
+		severity = syslogSeverityErr
+	}
+	pri := syslogFacilities[s.facility()]*8 + severity
+
+	appName := s.config.Tag
+	if appName == "" {
+		appName = s.meta.TaskName
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s - ",
+		pri,
+		rec.time.UTC().Format(time.RFC3339Nano),
+		syslogField(s.hostname, 255),
+		syslogField(appName, 48),
+		syslogField(s.meta.AllocID, 128),
+		rec.stream,
+	)
+	buf.Write(rec.line)
+
+	if !s.framed {
+		return buf.Bytes()
+	}
+
+	// Messages sent over stream connections use octet counting framing as
+	// described in RFC 6587
+	msg := buf.Bytes()
+	return append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+}
+
+func (s *logSink) facility() string {
+	if s.config.Facility == "" {
+		return "user"
+	}
+	return s.config.Facility
+}
+
+// syslogField returns the value truncated to the maximum length of a syslog
+// header field, or the nil value if it is empty
+func syslogField(v string, max int) string {
+	if v == "" {
+		return "-"
+	}
+	if len(v) > max {
+		return v[:max]
+	}
+	return v
+}
+
+// lineForwarder splits a stream of the task's output into lines which are
+// forwarded to sinks
+type lineForwarder struct {
+	stream string
+	sinks  []*logSink
+
+	buf []byte
+
+	// split is true if the last record forwarded was the start of a line
+	// longer than maxLineSize, so the newline ending it doesn't end an
+	// empty line
+	split bool
+
+	lock sync.Mutex
+}
+
+func (f *lineForwarder) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			f.buf = append(f.buf, p...)
+			if len(f.buf) >= maxLineSize {
+				f.flushLocked()
+				f.split = true
+			}
+			break
+		}
+
+		f.buf = append(f.buf, p[:i]...)
+		if len(f.buf) > 0 || !f.split {
+			f.flushLocked()
+		}
+		f.split = false
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Flush forwards any partial line that has been written
+func (f *lineForwarder) Flush() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if len(f.buf) > 0 {
+		f.flushLocked()
+	}
+}
+
+// flushLocked forwards the buffered line, which may be empty
+func (f *lineForwarder) flushLocked() {
+	rec := &logRecord{
+		time:   time.Now(),
+		stream: f.stream,
+		line:   bytes.TrimSuffix(f.buf, []byte{'\r'}),
+	}
+	for _, s := range f.sinks {
+		s.enqueue(rec)
+	}
+	f.buf = nil
+}
+
+// sinkWriter writes the task's output to the log rotator and forwards it to
+// the task's sinks
+type sinkWriter struct {
+	rotator   io.WriteCloser
+	forwarder *lineForwarder
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	n, err := w.rotator.Write(p)
+	w.forwarder.Write(p[:n])
+	return n, err
+}
+
+func (w *sinkWriter) Close() error {
+	w.forwarder.Flush()
+	return w.rotator.Close()
+}
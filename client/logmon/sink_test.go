@@ -0,0 +1,164 @@
+package logmon
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSink_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	meta := &LogMetadata{
+		Namespace: "default",
+		JobID:     "web",
+		TaskGroup: "frontend",
+		AllocID:   "8a6b5a4e-3d2c-4b1a-9f8e-7d6c5b4a3f2e",
+		TaskName:  "server",
+	}
+	sink, err := newLogSink(&SinkConfig{
+		Name:    "collector",
+		Type:    "tcp",
+		Address: ln.Addr().String(),
+	}, meta, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	// Lines split across writes are forwarded as a single record, empty
+	// lines are forwarded, and partial lines are forwarded when flushed
+	fwd := &lineForwarder{stream: "stderr", sinks: []*logSink{sink}}
+	fwd.Write([]byte("hello\n\nwor"))
+	fwd.Write([]byte("ld\r\npartial"))
+	fwd.Flush()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	scanner := bufio.NewScanner(conn)
+	var records []jsonRecord
+	for len(records) < 4 && scanner.Scan() {
+		var rec jsonRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		records = append(records, rec)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 4)
+
+	require.Equal(t, "hello", records[0].Message)
+	require.Equal(t, "", records[1].Message)
+	require.Equal(t, "world", records[2].Message)
+	require.Equal(t, "partial", records[3].Message)
+	for _, rec := range records {
+		require.Equal(t, "stderr", rec.Stream)
+		require.Equal(t, meta.JobID, rec.JobID)
+		require.Equal(t, meta.AllocID, rec.AllocID)
+		require.Equal(t, meta.TaskName, rec.TaskName)
+	}
+
+	stats := sink.Stats()
+	require.Equal(t, uint64(4), stats.Sent)
+	require.Zero(t, stats.Dropped)
+}
+
+func TestLogSink_Syslog(t *testing.T) {
+	ci.Parallel(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := newLogSink(&SinkConfig{
+		Name:     "syslog",
+		Type:     "syslog",
+		Address:  "udp://" + conn.LocalAddr().String(),
+		Facility: "local0",
+		Tag:      "web",
+	}, &LogMetadata{AllocID: "8a6b5a4e-3d2c-4b1a-9f8e-7d6c5b4a3f2e"}, testlog.HCLogger(t))
+	require.NoError(t, err)
+	defer sink.Close()
+
+	sink.enqueue(&logRecord{time: time.Now(), stream: "stderr", line: []byte("failed")})
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	// local0 (16) * 8 + err (3)
+	msg := string(buf[:n])
+	require.True(t, strings.HasPrefix(msg, "<131>1 "), msg)
+	require.True(t, strings.HasSuffix(msg, " web 8a6b5a4e-3d2c-4b1a-9f8e-7d6c5b4a3f2e stderr - failed"), msg)
+}
+
+func TestLogSink_Backpressure(t *testing.T) {
+	ci.Parallel(t)
+
+	// The sink's socket doesn't exist so records are buffered until the
+	// buffer is full and then dropped
+	sink, err := newLogSink(&SinkConfig{
+		Name:       "unavailable",
+		Type:       "unix",
+		Address:    filepath.Join(t.TempDir(), "missing.sock"),
+		BufferSize: 2,
+	}, nil, testlog.HCLogger(t))
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		sink.enqueue(&logRecord{time: time.Now(), stream: "stdout", line: []byte("line")})
+	}
+
+	stats := sink.Stats()
+	require.Zero(t, stats.Sent)
+	require.Equal(t, 2, stats.BufferSize)
+	require.GreaterOrEqual(t, stats.Dropped, uint64(7))
+
+	sink.Close()
+	stats = sink.Stats()
+	require.Zero(t, stats.Sent)
+	require.Equal(t, uint64(10), stats.Dropped)
+	require.NotZero(t, stats.Errors)
+}
+
+func TestNewLogSink_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	_, err := newLogSink(&SinkConfig{Name: "bad", Type: "kafka"}, nil, testlog.HCLogger(t))
+	require.EqualError(t, err, `unsupported log sink type "kafka"`)
+
+	_, err = newLogSink(&SinkConfig{Name: "bad", Type: "syslog", Address: "http://localhost"}, nil, testlog.HCLogger(t))
+	require.EqualError(t, err, `unsupported syslog address scheme "http"`)
+
+	_, err = newLogSink(&SinkConfig{Name: "bad", Type: "syslog", Facility: "bogus"}, nil, testlog.HCLogger(t))
+	require.EqualError(t, err, `invalid syslog facility "bogus"`)
+}
+
+func TestLineForwarder_LongLine(t *testing.T) {
+	ci.Parallel(t)
+
+	sink := &logSink{records: make(chan *logRecord, 10)}
+	fwd := &lineForwarder{stream: "stdout", sinks: []*logSink{sink}}
+
+	// A line longer than maxLineSize is forwarded in parts, and the newline
+	// ending it doesn't forward an empty line
+	long := strings.Repeat("a", maxLineSize)
+	fwd.Write([]byte(long))
+	fwd.Write([]byte("\nnext\n"))
+
+	require.Len(t, sink.records, 2)
+	require.Equal(t, long, string((<-sink.records).line))
+	require.Equal(t, "next", string((<-sink.records).line))
+}
@@ -36,6 +36,11 @@ type pluginFingerprinter struct {
 	// is started. Removing this bool will require storing a cache of recent successful
 	// results that can be used by subscribers of the `hadFirstSuccessfulFingerprintCh`.
 	requiresStaging bool
+
+	// supportsStats is set on a first successful fingerprint, in the same
+	// way as requiresStaging, and shows whether the plugin implements the
+	// NodeGetVolumeStats RPC.
+	supportsStats bool
 }
 
 func (p *pluginFingerprinter) fingerprint(ctx context.Context) *structs.CSIInfo {
@@ -74,6 +79,7 @@ func (p *pluginFingerprinter) fingerprint(ctx context.Context) *structs.CSIInfo
 			p.hadFirstSuccessfulFingerprint = true
 			if p.fingerprintNode {
 				p.requiresStaging = info.NodeInfo.RequiresNodeStageVolume
+				p.supportsStats = info.NodeInfo.SupportsStats
 			}
 			close(p.hadFirstSuccessfulFingerprintCh)
 		}
@@ -2,14 +2,27 @@ package csimanager
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/csi"
 )
 
-const managerFingerprintInterval = 30 * time.Second
+const (
+	managerFingerprintInterval = 30 * time.Second
+
+	// managerVolumeStatsInterval is the interval at which the stats of
+	// mounted volumes are collected from node plugins
+	managerVolumeStatsInterval = 30 * time.Second
+
+	// volumeStatsReportThreshold is the change in the fraction of a volume's
+	// capacity in use that causes the stats to be reported to the servers.
+	// Changes to the volume condition are always reported.
+	volumeStatsReportThreshold = 0.01
+)
 
 // instanceManager is used to manage the fingerprinting and supervision of a
 // single CSI Plugin.
@@ -20,6 +33,15 @@ type instanceManager struct {
 
 	updater UpdateNodeCSIInfoFunc
 
+	// statsUpdater reports the stats of mounted volumes to the servers.
+	// reportedStats holds the stats most recently reported for each
+	// volume, so that we only report stats that have changed.
+	statsUpdater  UpdateCSIVolumeStatsFunc
+	reportedStats map[volumeStatsKey]*structs.CSIVolumeStats
+
+	volumeStats     []*structs.CSIVolumeStats
+	volumeStatsLock sync.RWMutex
+
 	shutdownCtx         context.Context
 	shutdownCtxCancelFn context.CancelFunc
 	shutdownCh          chan struct{}
@@ -43,7 +65,7 @@ type instanceManager struct {
 	client csi.CSIPlugin
 }
 
-func newInstanceManager(logger hclog.Logger, eventer TriggerNodeEvent, updater UpdateNodeCSIInfoFunc, p *dynamicplugins.PluginInfo) *instanceManager {
+func newInstanceManager(logger hclog.Logger, eventer TriggerNodeEvent, updater UpdateNodeCSIInfoFunc, statsUpdater UpdateCSIVolumeStatsFunc, p *dynamicplugins.PluginInfo) *instanceManager {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	logger = logger.Named(p.Name)
 	return &instanceManager{
@@ -52,6 +74,9 @@ func newInstanceManager(logger hclog.Logger, eventer TriggerNodeEvent, updater U
 		info:    p,
 		updater: updater,
 
+		statsUpdater:  statsUpdater,
+		reportedStats: make(map[volumeStatsKey]*structs.CSIVolumeStats),
+
 		fp: &pluginFingerprinter{
 			logger:                          logger.Named("fingerprinter"),
 			info:                            p,
@@ -94,6 +119,9 @@ func (i *instanceManager) setupVolumeManager() {
 		i.volumeManager = newVolumeManager(i.logger, i.eventer, i.client, i.mountPoint, i.containerMountPoint, i.fp.requiresStaging)
 		i.logger.Debug("volume manager setup complete")
 		close(i.volumeManagerSetupCh)
+		if i.fp.supportsStats {
+			go i.runVolumeStatsLoop()
+		}
 		return
 	}
 }
@@ -152,3 +180,108 @@ func (i *instanceManager) shutdown() {
 	i.shutdownCtxCancelFn()
 	<-i.shutdownCh
 }
+
+// VolumeStats returns the most recently collected stats for the volumes
+// mounted by the plugin.
+func (i *instanceManager) VolumeStats() []*structs.CSIVolumeStats {
+	i.volumeStatsLock.RLock()
+	defer i.volumeStatsLock.RUnlock()
+
+	out := make([]*structs.CSIVolumeStats, 0, len(i.volumeStats))
+	for _, stats := range i.volumeStats {
+		out = append(out, stats.Copy())
+	}
+	return out
+}
+
+// runVolumeStatsLoop periodically collects the stats of the volumes mounted
+// by the plugin and reports any significant changes to the servers.
+func (i *instanceManager) runVolumeStatsLoop() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-i.shutdownCtx.Done():
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancelFn := i.requestCtxWithTimeout(managerVolumeStatsInterval)
+		stats := i.volumeManager.VolumeStats(ctx)
+		cancelFn()
+
+		i.volumeStatsLock.Lock()
+		i.volumeStats = stats
+		i.volumeStatsLock.Unlock()
+
+		i.reportVolumeStats(stats)
+		timer.Reset(managerVolumeStatsInterval)
+	}
+}
+
+type volumeStatsKey struct {
+	namespace string
+	volID     string
+}
+
+// reportVolumeStats sends the stats that have changed since they were last
+// reported to the servers. Stats that fail to be reported are retried on
+// the next collection.
+func (i *instanceManager) reportVolumeStats(stats []*structs.CSIVolumeStats) {
+	if i.statsUpdater == nil {
+		return
+	}
+
+	current := make(map[volumeStatsKey]struct{}, len(stats))
+	var changed []*structs.CSIVolumeStats
+	for _, s := range stats {
+		key := volumeStatsKey{namespace: s.Namespace, volID: s.VolumeID}
+		current[key] = struct{}{}
+		if volumeStatsChanged(i.reportedStats[key], s) {
+			changed = append(changed, s)
+		}
+	}
+
+	// forget volumes that are no longer mounted, so that their stats are
+	// reported again if they are remounted
+	for key := range i.reportedStats {
+		if _, ok := current[key]; !ok {
+			delete(i.reportedStats, key)
+		}
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+	if err := i.statsUpdater(changed); err != nil {
+		i.logger.Warn("failed to report volume stats", "error", err)
+		return
+	}
+	for _, s := range changed {
+		i.reportedStats[volumeStatsKey{namespace: s.Namespace, volID: s.VolumeID}] = s
+	}
+}
+
+// volumeStatsChanged returns true if the new stats differ enough from the
+// stats previously reported for a volume that they should be reported
+func volumeStatsChanged(prev, next *structs.CSIVolumeStats) bool {
+	if prev == nil {
+		return true
+	}
+	if prev.Abnormal != next.Abnormal || prev.Message != next.Message {
+		return true
+	}
+	if prev.TotalBytes != next.TotalBytes {
+		return true
+	}
+	if next.TotalBytes <= 0 {
+		return prev.UsedBytes != next.UsedBytes
+	}
+
+	delta := next.UsedBytes - prev.UsedBytes
+	if delta < 0 {
+		delta = -delta
+	}
+	return float64(delta)/float64(next.TotalBytes) >= volumeStatsReportThreshold
+}
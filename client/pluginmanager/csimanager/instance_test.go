@@ -2,10 +2,12 @@ package csimanager
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/dynamicplugins"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -78,3 +80,55 @@ func TestInstanceManager_Shutdown(t *testing.T) {
 	}, 1*time.Second, 10*time.Millisecond)
 
 }
+
+func TestInstanceManager_reportVolumeStats(t *testing.T) {
+	ci.Parallel(t)
+
+	_, im := setupTestNodeInstanceManager(t)
+	im.reportedStats = make(map[volumeStatsKey]*structs.CSIVolumeStats)
+
+	var reported []*structs.CSIVolumeStats
+	var reportErr error
+	im.statsUpdater = func(stats []*structs.CSIVolumeStats) error {
+		reported = stats
+		return reportErr
+	}
+
+	report := func(stats ...*structs.CSIVolumeStats) []*structs.CSIVolumeStats {
+		reported = nil
+		im.reportVolumeStats(stats)
+		return reported
+	}
+
+	vol1 := &structs.CSIVolumeStats{VolumeID: "vol1", Namespace: "default", TotalBytes: 1000, UsedBytes: 100}
+	vol2 := &structs.CSIVolumeStats{VolumeID: "vol2", Namespace: "default"}
+
+	// new volumes are always reported
+	require.Len(t, report(vol1, vol2), 2)
+
+	// unchanged and insignificant changes are not reported
+	require.Empty(t, report(vol1, vol2))
+	vol1 = vol1.Copy()
+	vol1.UsedBytes = 105
+	require.Empty(t, report(vol1, vol2))
+
+	// significant usage changes and condition changes are reported
+	vol1 = vol1.Copy()
+	vol1.UsedBytes = 200
+	vol2 = vol2.Copy()
+	vol2.Abnormal = true
+	require.Len(t, report(vol1, vol2), 2)
+
+	// failed reports are retried on the next collection
+	vol2 = vol2.Copy()
+	vol2.Abnormal = false
+	reportErr = errors.New("no servers")
+	require.Len(t, report(vol1, vol2), 1)
+	reportErr = nil
+	require.Len(t, report(vol1, vol2), 1)
+	require.Empty(t, report(vol1, vol2))
+
+	// volumes that are unmounted and remounted are reported again
+	require.Empty(t, report(vol1))
+	require.Len(t, report(vol1, vol2), 1)
+}
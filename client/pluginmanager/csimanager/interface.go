@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/nomad/client/pluginmanager"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/csi"
 )
 
 type MountInfo struct {
@@ -43,6 +44,7 @@ func (u *UsageOptions) ToFS() string {
 type VolumeMounter interface {
 	MountVolume(ctx context.Context, vol *structs.CSIVolume, alloc *structs.Allocation, usageOpts *UsageOptions, publishContext map[string]string) (*MountInfo, error)
 	UnmountVolume(ctx context.Context, volID, remoteID, allocID string, usageOpts *UsageOptions) error
+	ExpandVolume(ctx context.Context, volID, remoteID, allocID string, usageOpts *UsageOptions, capacity *csi.CapacityRange) (int64, error)
 }
 
 type Manager interface {
@@ -53,6 +55,10 @@ type Manager interface {
 	// with the volume.	Returns an error if this plugin isn't registered.
 	MounterForPlugin(ctx context.Context, pluginID string) (VolumeMounter, error)
 
+	// VolumeStats returns the most recently collected usage and condition
+	// of the volumes mounted on the node, for plugins that support it.
+	VolumeStats() []*structs.CSIVolumeStats
+
 	// Shutdown shuts down the Manager and unmounts any locally attached volumes.
 	Shutdown()
 }
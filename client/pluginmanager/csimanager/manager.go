@@ -21,12 +21,17 @@ const defaultPluginResyncPeriod = 30 * time.Second
 type UpdateNodeCSIInfoFunc func(string, *structs.CSIInfo)
 type TriggerNodeEvent func(*structs.NodeEvent)
 
+// UpdateCSIVolumeStatsFunc is the callback used to report the stats of
+// mounted volumes to the servers
+type UpdateCSIVolumeStatsFunc func([]*structs.CSIVolumeStats) error
+
 type Config struct {
-	Logger                hclog.Logger
-	DynamicRegistry       dynamicplugins.Registry
-	UpdateNodeCSIInfoFunc UpdateNodeCSIInfoFunc
-	PluginResyncPeriod    time.Duration
-	TriggerNodeEvent      TriggerNodeEvent
+	Logger                   hclog.Logger
+	DynamicRegistry          dynamicplugins.Registry
+	UpdateNodeCSIInfoFunc    UpdateNodeCSIInfoFunc
+	UpdateCSIVolumeStatsFunc UpdateCSIVolumeStatsFunc
+	PluginResyncPeriod       time.Duration
+	TriggerNodeEvent         TriggerNodeEvent
 }
 
 // New returns a new PluginManager that will handle managing CSI plugins from
@@ -44,8 +49,9 @@ func New(config *Config) Manager {
 		registry:  config.DynamicRegistry,
 		instances: make(map[string]map[string]*instanceManager),
 
-		updateNodeCSIInfoFunc: config.UpdateNodeCSIInfoFunc,
-		pluginResyncPeriod:    config.PluginResyncPeriod,
+		updateNodeCSIInfoFunc:    config.UpdateNodeCSIInfoFunc,
+		updateCSIVolumeStatsFunc: config.UpdateCSIVolumeStatsFunc,
+		pluginResyncPeriod:       config.PluginResyncPeriod,
 
 		shutdownCtx:         ctx,
 		shutdownCtxCancelFn: cancelFn,
@@ -64,7 +70,8 @@ type csiManager struct {
 	eventer            TriggerNodeEvent
 	pluginResyncPeriod time.Duration
 
-	updateNodeCSIInfoFunc UpdateNodeCSIInfoFunc
+	updateNodeCSIInfoFunc    UpdateNodeCSIInfoFunc
+	updateCSIVolumeStatsFunc UpdateCSIVolumeStatsFunc
 
 	shutdownCtx         context.Context
 	shutdownCtxCancelFn context.CancelFunc
@@ -91,6 +98,19 @@ func (c *csiManager) MounterForPlugin(ctx context.Context, pluginID string) (Vol
 	return mgr.VolumeMounter(ctx)
 }
 
+// VolumeStats returns the most recently collected stats for the volumes
+// mounted by each of the node plugins.
+func (c *csiManager) VolumeStats() []*structs.CSIVolumeStats {
+	c.instancesLock.RLock()
+	defer c.instancesLock.RUnlock()
+
+	var out []*structs.CSIVolumeStats
+	for _, mgr := range c.instances["csi-node"] {
+		out = append(out, mgr.VolumeStats()...)
+	}
+	return out
+}
+
 // Run starts a plugin manager and should return early
 func (c *csiManager) Run() {
 	go c.runLoop()
@@ -181,13 +201,13 @@ func (c *csiManager) ensureInstance(plugin *dynamicplugins.PluginInfo) {
 	mgr, ok := instances[name]
 	if !ok {
 		c.logger.Debug("detected new CSI plugin", "name", name, "type", ptype, "alloc", plugin.AllocID)
-		mgr := newInstanceManager(c.logger, c.eventer, c.updateNodeCSIInfoFunc, plugin)
+		mgr := newInstanceManager(c.logger, c.eventer, c.updateNodeCSIInfoFunc, c.updateCSIVolumeStatsFunc, plugin)
 		instances[name] = mgr
 		mgr.run()
 	} else if mgr.allocID != plugin.AllocID {
 		mgr.shutdown()
 		c.logger.Debug("detected update for CSI plugin", "name", name, "type", ptype, "alloc", plugin.AllocID)
-		mgr := newInstanceManager(c.logger, c.eventer, c.updateNodeCSIInfoFunc, plugin)
+		mgr := newInstanceManager(c.logger, c.eventer, c.updateNodeCSIInfoFunc, c.updateCSIVolumeStatsFunc, plugin)
 		instances[name] = mgr
 		mgr.run()
 
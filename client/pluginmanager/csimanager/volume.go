@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
@@ -39,6 +40,11 @@ type volumeManager struct {
 
 	usageTracker *volumeUsageTracker
 
+	// mounted tracks the volumes that have been published for each
+	// allocation, so that their stats can be queried from the plugin
+	mounted     map[mountedVolumeKey]*mountedVolume
+	mountedLock sync.Mutex
+
 	// mountRoot is the root of where plugin directories and mounts may be created
 	// e.g /opt/nomad.d/statedir/csi/my-csi-plugin/
 	mountRoot string
@@ -61,9 +67,25 @@ func newVolumeManager(logger hclog.Logger, eventer TriggerNodeEvent, plugin csi.
 		containerMountPoint: containerRootDir,
 		requiresStaging:     requiresStaging,
 		usageTracker:        newVolumeUsageTracker(),
+		mounted:             make(map[mountedVolumeKey]*mountedVolume),
 	}
 }
 
+type mountedVolumeKey struct {
+	volID   string
+	allocID string
+}
+
+// mountedVolume is a volume that has been published for an allocation
+type mountedVolume struct {
+	namespace string
+	volID     string
+	pluginID  string
+	remoteID  string
+	allocID   string
+	usage     UsageOptions
+}
+
 func (v *volumeManager) stagingDirForVolume(root string, volID string, usage *UsageOptions) string {
 	return filepath.Join(root, StagingDirName, volID, usage.ToFS())
 }
@@ -253,6 +275,7 @@ func (v *volumeManager) MountVolume(ctx context.Context, vol *structs.CSIVolume,
 
 	if err == nil {
 		v.usageTracker.Claim(alloc.ID, vol.ID, usage)
+		v.trackMount(vol, alloc.ID, usage)
 	}
 
 	event := structs.NewNodeEvent().
@@ -348,6 +371,7 @@ func (v *volumeManager) UnmountVolume(ctx context.Context, volID, remoteID, allo
 
 	if err == nil || errors.Is(err, structs.ErrCSIClientRPCIgnorable) {
 		canRelease := v.usageTracker.Free(allocID, volID, usage)
+		v.untrackMount(volID, allocID)
 		if v.requiresStaging && canRelease {
 			err = v.unstageVolume(ctx, volID, remoteID, usage)
 		}
@@ -373,3 +397,157 @@ func (v *volumeManager) UnmountVolume(ctx context.Context, volID, remoteID, allo
 
 	return err
 }
+
+// ExpandVolume expands the filesystem of a volume that has been published
+// for the given allocation, after the volume has been expanded by the
+// controller plugin. It returns the new capacity of the volume in bytes, or
+// zero if the plugin did not report the new capacity.
+func (v *volumeManager) ExpandVolume(ctx context.Context, volID, remoteID, allocID string, usage *UsageOptions, capacity *csi.CapacityRange) (int64, error) {
+	logger := v.logger.With("volume_id", volID, "alloc_id", allocID)
+	ctx = hclog.WithContext(ctx, logger)
+
+	capability, err := csi.VolumeCapabilityFromStructs(usage.AttachmentMode, usage.AccessMode, usage.MountOptions)
+	if err != nil {
+		return 0, err
+	}
+
+	req := &csi.NodeExpandVolumeRequest{
+		ExternalVolumeID: remoteID,
+		VolumePath:       v.targetForVolume(v.containerMountPoint, volID, allocID, usage),
+		CapacityRange:    capacity,
+		VolumeCapability: capability,
+	}
+	if v.requiresStaging {
+		req.StagingTargetPath = v.stagingDirForVolume(v.containerMountPoint, volID, usage)
+	}
+
+	// CSI NodeExpandVolume errors for timeout, codes.Unavailable and
+	// codes.ResourceExhausted are retried; all other errors are fatal.
+	resp, err := v.plugin.NodeExpandVolume(ctx, req,
+		grpc_retry.WithPerRetryTimeout(DefaultMountActionTimeout),
+		grpc_retry.WithMax(3),
+		grpc_retry.WithBackoff(grpc_retry.BackoffExponential(100*time.Millisecond)),
+	)
+
+	event := structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemStorage).
+		SetMessage("Expand volume").
+		AddDetail("volume_id", volID)
+	if err == nil {
+		event.AddDetail("success", "true")
+	} else {
+		event.AddDetail("success", "false")
+		event.AddDetail("error", err.Error())
+	}
+
+	v.eventer(event)
+
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil {
+		return 0, nil
+	}
+	return resp.CapacityBytes, nil
+}
+
+func (v *volumeManager) trackMount(vol *structs.CSIVolume, allocID string, usage *UsageOptions) {
+	v.mountedLock.Lock()
+	defer v.mountedLock.Unlock()
+
+	v.mounted[mountedVolumeKey{volID: vol.ID, allocID: allocID}] = &mountedVolume{
+		namespace: vol.Namespace,
+		volID:     vol.ID,
+		pluginID:  vol.PluginID,
+		remoteID:  vol.RemoteID(),
+		allocID:   allocID,
+		usage:     *usage,
+	}
+}
+
+func (v *volumeManager) untrackMount(volID, allocID string) {
+	v.mountedLock.Lock()
+	defer v.mountedLock.Unlock()
+
+	delete(v.mounted, mountedVolumeKey{volID: volID, allocID: allocID})
+}
+
+// mountedVolumes returns one published mount for each volume mounted on
+// the node. Volumes shared by several allocations only need to be queried
+// once, as the plugin reports stats for the volume and not for the mount.
+func (v *volumeManager) mountedVolumes() []*mountedVolume {
+	v.mountedLock.Lock()
+	defer v.mountedLock.Unlock()
+
+	type nsVolID struct{ namespace, volID string }
+	seen := make(map[nsVolID]struct{}, len(v.mounted))
+	mounts := make([]*mountedVolume, 0, len(v.mounted))
+	for _, mv := range v.mounted {
+		id := nsVolID{mv.namespace, mv.volID}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		mounts = append(mounts, mv)
+	}
+	return mounts
+}
+
+// VolumeStats queries the plugin for the usage and condition of each of the
+// volumes mounted on the node. Volumes for which the plugin returns an error
+// are omitted from the results.
+func (v *volumeManager) VolumeStats(ctx context.Context) []*structs.CSIVolumeStats {
+	mounts := v.mountedVolumes()
+	out := make([]*structs.CSIVolumeStats, 0, len(mounts))
+
+	for _, mv := range mounts {
+		req := &csi.NodeGetVolumeStatsRequest{
+			ExternalVolumeID: mv.remoteID,
+			VolumePath:       v.targetForVolume(v.containerMountPoint, mv.volID, mv.allocID, &mv.usage),
+		}
+		if v.requiresStaging {
+			req.StagingTargetPath = v.stagingDirForVolume(v.containerMountPoint, mv.volID, &mv.usage)
+		}
+
+		resp, err := v.plugin.NodeGetVolumeStats(ctx, req)
+		if err != nil {
+			v.logger.Debug("failed to get volume stats", "volume_id", mv.volID, "error", err)
+			continue
+		}
+
+		out = append(out, volumeStatsFromCSI(mv, resp))
+	}
+
+	return out
+}
+
+func volumeStatsFromCSI(mv *mountedVolume, resp *csi.NodeGetVolumeStatsResponse) *structs.CSIVolumeStats {
+	stats := &structs.CSIVolumeStats{
+		VolumeID:   mv.volID,
+		Namespace:  mv.namespace,
+		PluginID:   mv.pluginID,
+		UpdateTime: time.Now().UTC(),
+	}
+	if resp == nil {
+		return stats
+	}
+
+	for _, usage := range resp.Usage {
+		switch usage.Unit {
+		case csi.VolumeUsageUnitBytes:
+			stats.AvailableBytes = usage.Available
+			stats.TotalBytes = usage.Total
+			stats.UsedBytes = usage.Used
+		case csi.VolumeUsageUnitInodes:
+			stats.AvailableInodes = usage.Available
+			stats.TotalInodes = usage.Total
+			stats.UsedInodes = usage.Used
+		}
+	}
+	if resp.VolumeCondition != nil {
+		stats.Abnormal = resp.VolumeCondition.Abnormal
+		stats.Message = resp.VolumeCondition.Message
+	}
+
+	return stats
+}
@@ -515,3 +515,123 @@ func TestVolumeManager_MountVolumeEvents(t *testing.T) {
 	require.Equal(t, "vol", e.Details["volume_id"])
 	require.Equal(t, "true", e.Details["success"])
 }
+
+func TestVolumeManager_ExpandVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name             string
+		UsageOptions     *UsageOptions
+		PluginResponse   *csi.NodeExpandVolumeResponse
+		PluginErr        error
+		ExpectedErr      error
+		ExpectedCapacity int64
+	}{
+		{
+			Name: "Returns an error when the plugin returns an error",
+			UsageOptions: &UsageOptions{
+				AccessMode:     structs.CSIVolumeAccessModeSingleNodeWriter,
+				AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+			},
+			PluginErr:   errors.New("Some Unknown Error"),
+			ExpectedErr: errors.New("Some Unknown Error"),
+		},
+		{
+			Name: "Returns an error for an invalid usage",
+			UsageOptions: &UsageOptions{
+				AccessMode: structs.CSIVolumeAccessModeSingleNodeWriter,
+			},
+			ExpectedErr: errors.New("unknown volume attachment mode: "),
+		},
+		{
+			Name: "Happy Path",
+			UsageOptions: &UsageOptions{
+				AccessMode:     structs.CSIVolumeAccessModeSingleNodeWriter,
+				AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+			},
+			PluginResponse:   &csi.NodeExpandVolumeResponse{CapacityBytes: 2048},
+			ExpectedCapacity: 2048,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			tmpPath := t.TempDir()
+
+			csiFake := &csifake.Client{}
+			csiFake.NextNodeExpandVolumeResponse = tc.PluginResponse
+			csiFake.NextNodeExpandVolumeErr = tc.PluginErr
+
+			events := []*structs.NodeEvent{}
+			eventer := func(e *structs.NodeEvent) {
+				events = append(events, e)
+			}
+			manager := newVolumeManager(testlog.HCLogger(t), eventer, csiFake, tmpPath, tmpPath, true)
+			ctx := context.Background()
+
+			capacity, err := manager.ExpandVolume(ctx, "foo", "foo",
+				"alloc-id", tc.UsageOptions, &csi.CapacityRange{RequiredBytes: 2000})
+
+			if tc.ExpectedErr != nil {
+				require.EqualError(t, err, tc.ExpectedErr.Error())
+			} else {
+				require.NoError(t, err)
+				require.Len(t, events, 1)
+				require.Equal(t, "Expand volume", events[0].Message)
+				require.Equal(t, "true", events[0].Details["success"])
+			}
+			require.Equal(t, tc.ExpectedCapacity, capacity)
+		})
+	}
+}
+
+func TestVolumeManager_VolumeStats(t *testing.T) {
+	ci.Parallel(t)
+
+	tmpPath := t.TempDir()
+	csiFake := &csifake.Client{}
+	csiFake.NextNodeGetVolumeStatsResponse = &csi.NodeGetVolumeStatsResponse{
+		Usage: []*csi.VolumeUsage{
+			{Available: 1024, Total: 4096, Used: 3072, Unit: csi.VolumeUsageUnitBytes},
+			{Available: 10, Total: 100, Used: 90, Unit: csi.VolumeUsageUnitInodes},
+		},
+		VolumeCondition: &csi.VolumeCondition{Abnormal: true, Message: "degraded"},
+	}
+
+	eventer := func(e *structs.NodeEvent) {}
+	manager := newVolumeManager(testlog.HCLogger(t), eventer, csiFake, tmpPath, tmpPath, true)
+	ctx := context.Background()
+
+	// no mounted volumes means no plugin calls
+	require.Empty(t, manager.VolumeStats(ctx))
+	require.Equal(t, int64(0), csiFake.NodeGetVolumeStatsCallCount)
+
+	vol := &structs.CSIVolume{ID: "foo", Namespace: "default", PluginID: "plugin"}
+	usage := &UsageOptions{
+		AccessMode:     structs.CSIVolumeAccessModeMultiNodeMultiWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+	}
+	manager.trackMount(vol, "alloc1", usage)
+	manager.trackMount(vol, "alloc2", usage)
+
+	// volumes shared by allocations are only queried once
+	stats := manager.VolumeStats(ctx)
+	require.Len(t, stats, 1)
+	require.Equal(t, int64(1), csiFake.NodeGetVolumeStatsCallCount)
+	require.Equal(t, "foo", stats[0].VolumeID)
+	require.Equal(t, "default", stats[0].Namespace)
+	require.Equal(t, "plugin", stats[0].PluginID)
+	require.Equal(t, int64(3072), stats[0].UsedBytes)
+	require.Equal(t, int64(4096), stats[0].TotalBytes)
+	require.Equal(t, int64(90), stats[0].UsedInodes)
+	require.True(t, stats[0].Abnormal)
+	require.Equal(t, "degraded", stats[0].Message)
+
+	// volumes for which the plugin returns an error are omitted
+	csiFake.NextNodeGetVolumeStatsErr = errors.New("not found")
+	require.Empty(t, manager.VolumeStats(ctx))
+
+	manager.untrackMount("foo", "alloc1")
+	manager.untrackMount("foo", "alloc2")
+	require.Empty(t, manager.mountedVolumes())
+}
@@ -0,0 +1,216 @@
+package nsd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// checkOutputMaxBytes is the maximum number of bytes of check output which
+	// will be stored within the service registration check status. This
+	// protects the Nomad state from large HTTP response bodies.
+	checkOutputMaxBytes = 4 * 1024
+)
+
+// checkStatusUpdateFn is the function called by a check runner to update the
+// status of the check it is executing. Any error is logged by the callee.
+type checkStatusUpdateFn func(checkID, status, output string) error
+
+// checkRunner periodically executes a single HTTP or TCP check which is part
+// of a service registration using the Nomad provider. Script checks are not
+// executed by the runner, as they need the task driver; they are instead run
+// by the task runner script check hook, which calls UpdateTTL.
+type checkRunner struct {
+	id       string
+	check    *structs.ServiceCheck
+	address  string
+	port     int
+	updateFn checkStatusUpdateFn
+
+	// client is used to perform HTTP checks.
+	client *http.Client
+}
+
+// newCheckRunner returns a checkRunner for the check. The check address is
+// resolved in the same manner as checks registered with Consul.
+func newCheckRunner(serviceID string, service *structs.Service, check *structs.ServiceCheck,
+	workload *serviceregistration.WorkloadServices, updateFn checkStatusUpdateFn) (*checkRunner, error) {
+
+	portLabel := check.PortLabel
+	if portLabel == "" {
+		portLabel = service.PortLabel
+	}
+
+	addrMode := check.AddressMode
+	if addrMode == "" {
+		if service.Address != "" {
+			addrMode = structs.AddressModeAuto
+		} else {
+			addrMode = structs.AddressModeHost
+		}
+	}
+
+	ip, port, err := serviceregistration.GetAddress(
+		service.Address, addrMode, portLabel, workload.Networks,
+		workload.DriverNetwork, workload.Ports, workload.NetworkStatus)
+	if err != nil {
+		return nil, fmt.Errorf("error getting address for check %q: %v", check.Name, err)
+	}
+	if port == 0 {
+		return nil, fmt.Errorf("%s checks require an address", check.Type)
+	}
+
+	return &checkRunner{
+		id:       agentconsul.MakeCheckID(serviceID, check),
+		check:    check,
+		address:  ip,
+		port:     port,
+		updateFn: updateFn,
+		client: &http.Client{
+			Timeout: check.Timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: check.TLSSkipVerify},
+			},
+			// Follow the Consul behaviour of not following redirects, so the
+			// check is performed against the configured endpoint only.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// run executes the check on its interval until the context is cancelled.
+func (c *checkRunner) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		status, output := c.execute(ctx)
+
+		// Do not update the status if the check was cancelled whilst running,
+		// as the registration is being removed.
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		_ = c.updateFn(c.id, status, output)
+		timer.Reset(c.check.Interval)
+	}
+}
+
+// execute performs a single execution of the check, returning the resulting
+// status and output.
+func (c *checkRunner) execute(ctx context.Context) (string, string) {
+	switch c.check.Type {
+	case structs.ServiceCheckHTTP:
+		return c.executeHTTP(ctx)
+	case structs.ServiceCheckTCP:
+		return c.executeTCP(ctx)
+	default:
+		return structs.ServiceCheckStatusCritical,
+			fmt.Sprintf("check type %q not supported", c.check.Type)
+	}
+}
+
+func (c *checkRunner) executeTCP(ctx context.Context) (string, string) {
+	addr := net.JoinHostPort(c.address, strconv.Itoa(c.port))
+
+	dialer := net.Dialer{Timeout: c.check.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, fmt.Sprintf("TCP connect %s: %v", addr, err)
+	}
+	_ = conn.Close()
+	return structs.ServiceCheckStatusPassing, fmt.Sprintf("TCP connect %s: Success", addr)
+}
+
+func (c *checkRunner) executeHTTP(ctx context.Context) (string, string) {
+	proto := c.check.Protocol
+	if proto == "" {
+		proto = "http"
+	}
+	base := url.URL{
+		Scheme: proto,
+		Host:   net.JoinHostPort(c.address, strconv.Itoa(c.port)),
+	}
+	relative, err := url.Parse(c.check.Path)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, err.Error()
+	}
+	checkURL := base.ResolveReference(relative).String()
+
+	method := c.check.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if c.check.Body != "" {
+		body = strings.NewReader(c.check.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, checkURL, body)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, err.Error()
+	}
+	for header, values := range c.check.Header {
+		for _, v := range values {
+			req.Header.Add(header, v)
+		}
+	}
+
+	// The Host header must be set on the request itself, otherwise it is
+	// ignored by the HTTP client.
+	if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return structs.ServiceCheckStatusCritical, fmt.Sprintf("HTTP %s %s: %v", method, checkURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, checkOutputMaxBytes))
+	output := fmt.Sprintf("HTTP %s %s: %s Output: %s", method, checkURL, resp.Status, respBody)
+
+	// Map the response code to a status in the same manner as Consul; 2xx is
+	// passing, 429 is warning and everything else is critical.
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode <= 299:
+		return structs.ServiceCheckStatusPassing, output
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return structs.ServiceCheckStatusWarning, output
+	default:
+		return structs.ServiceCheckStatusCritical, output
+	}
+}
+
+// truncateCheckOutput limits the output stored alongside the check status.
+func truncateCheckOutput(output string) string {
+	if len(output) > checkOutputMaxBytes {
+		return output[:checkOutputMaxBytes]
+	}
+	return output
+}
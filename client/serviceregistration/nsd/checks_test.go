@@ -0,0 +1,90 @@
+package nsd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRunner_execute(t *testing.T) {
+
+	// Start a HTTP server which responds according to the request path, and
+	// identify its address, so it can be used for both HTTP and TCP checks.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthy":
+			w.WriteHeader(http.StatusOK)
+		case "/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	host, portStr, err := net.SplitHostPort(srv.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		inputCheck     *structs.ServiceCheck
+		expectedStatus string
+		name           string
+	}{
+		{
+			inputCheck:     &structs.ServiceCheck{Name: "http", Type: "http", Path: "/healthy"},
+			expectedStatus: structs.ServiceCheckStatusPassing,
+			name:           "http passing",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Name: "http", Type: "http", Path: "/busy"},
+			expectedStatus: structs.ServiceCheckStatusWarning,
+			name:           "http warning",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Name: "http", Type: "http", Path: "/broken"},
+			expectedStatus: structs.ServiceCheckStatusCritical,
+			name:           "http critical",
+		},
+		{
+			inputCheck:     &structs.ServiceCheck{Name: "tcp", Type: "tcp"},
+			expectedStatus: structs.ServiceCheckStatusPassing,
+			name:           "tcp passing",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.inputCheck.Timeout = time.Second
+
+			workload := &serviceregistration.WorkloadServices{
+				Ports: []structs.AllocatedPortMapping{{Label: "http", HostIP: host, Value: port}},
+			}
+			service := &structs.Service{Name: "web", PortLabel: "http"}
+
+			runner, err := newCheckRunner("service-id", service, tc.inputCheck, workload, nil)
+			require.NoError(t, err)
+
+			actualStatus, _ := runner.execute(context.Background())
+			require.Equal(t, tc.expectedStatus, actualStatus)
+		})
+	}
+}
+
+func TestCheckRunner_missingPort(t *testing.T) {
+	workload := &serviceregistration.WorkloadServices{}
+	service := &structs.Service{Name: "web", PortLabel: "http"}
+	check := &structs.ServiceCheck{Name: "tcp", Type: "tcp"}
+
+	_, err := newCheckRunner("service-id", service, check, workload, nil)
+	require.Error(t, err)
+}
@@ -1,13 +1,17 @@
 package nsd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -25,6 +29,40 @@ type ServiceRegistrationHandler struct {
 	// shutDownCh coordinates shutting down the handler and any long-running
 	// processes, such as the RPC retry.
 	shutDownCh chan struct{}
+
+	// checksLock protects the services and checks mappings.
+	checksLock sync.Mutex
+
+	// services tracks the registrations which include checks, keyed by the
+	// service registration ID. It is used to update the registration whenever
+	// the status of one of its checks changes.
+	services map[string]*checkedService
+
+	// checks maps check IDs to the ID of the service registration the check
+	// belongs to.
+	checks map[string]string
+}
+
+// checkedService is a service registration which includes checks that are
+// being executed by the client.
+type checkedService struct {
+	allocID  string
+	taskName string
+
+	// registration is the latest version of the service registration,
+	// including the current check statuses.
+	registration *structs.ServiceRegistration
+
+	// onUpdate maps check IDs to the ServiceCheck.OnUpdate value, which is
+	// used by the allocation health tracker.
+	onUpdate map[string]string
+
+	// cancelFn stops the check runners of the service.
+	cancelFn context.CancelFunc
+
+	// syncLock serializes the upserting of check status changes, ensuring an
+	// older status cannot overwrite a newer one.
+	syncLock sync.Mutex
 }
 
 // ServiceRegistrationHandlerCfg holds critical information used during the
@@ -61,6 +99,8 @@ func NewServiceRegistrationHandler(
 		log:                 log.Named("service_registration.nomad"),
 		registrationEnabled: cfg.Enabled,
 		shutDownCh:          make(chan struct{}),
+		services:            make(map[string]*checkedService),
+		checks:              make(map[string]string),
 	}
 }
 
@@ -105,7 +145,153 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 
 	var resp structs.ServiceRegistrationUpsertResponse
 
-	return s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp)
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		return err
+	}
+
+	// Now the registrations are stored, start running any checks they
+	// include.
+	for i, serviceSpec := range workload.Services {
+		s.trackChecks(workload, serviceSpec, registrations[i])
+	}
+	return nil
+}
+
+// trackChecks starts the check runners for the service, replacing any which
+// were previously running for the same service registration.
+func (s *ServiceRegistrationHandler) trackChecks(
+	workload *serviceregistration.WorkloadServices, serviceSpec *structs.Service, reg *structs.ServiceRegistration) {
+
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+
+	s.untrackChecksLocked(reg.ID)
+
+	if len(serviceSpec.Checks) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	checked := &checkedService{
+		allocID:      workload.AllocID,
+		taskName:     workload.Name(),
+		registration: reg.Copy(),
+		onUpdate:     make(map[string]string, len(serviceSpec.Checks)),
+		cancelFn:     cancel,
+	}
+	s.services[reg.ID] = checked
+
+	for _, check := range serviceSpec.Checks {
+		checkID := agentconsul.MakeCheckID(reg.ID, check)
+		checked.onUpdate[checkID] = check.OnUpdate
+		s.checks[checkID] = reg.ID
+
+		// Script checks are executed by the task runner and their status is
+		// provided via UpdateTTL.
+		if check.Type == structs.ServiceCheckScript {
+			continue
+		}
+
+		runner, err := newCheckRunner(reg.ID, serviceSpec, check, workload, s.updateCheckStatus)
+		if err != nil {
+			s.log.Error("failed to create check runner",
+				"error", err, "service_id", reg.ID, "check", check.Name)
+			continue
+		}
+		go runner.run(ctx)
+	}
+}
+
+// untrackChecks stops the check runners for the service registration and
+// removes it from tracking. It returns the removed entry, which is nil if the
+// service was not tracked.
+func (s *ServiceRegistrationHandler) untrackChecks(serviceID string) *checkedService {
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+	return s.untrackChecksLocked(serviceID)
+}
+
+// untrackChecksLocked performs the work of untrackChecks and must be called
+// whilst holding checksLock.
+func (s *ServiceRegistrationHandler) untrackChecksLocked(serviceID string) *checkedService {
+	checked, ok := s.services[serviceID]
+	if !ok {
+		return nil
+	}
+
+	checked.cancelFn()
+	for checkID := range checked.onUpdate {
+		delete(s.checks, checkID)
+	}
+	delete(s.services, serviceID)
+	return checked
+}
+
+// updateCheckStatus updates the status of the check. If the status has
+// changed, the service registration is upserted, so the new status is visible
+// to consumers of the Nomad API. An error is returned if the check is unknown,
+// which can occur when a script check runs before its service is registered.
+func (s *ServiceRegistrationHandler) updateCheckStatus(checkID, status, output string) error {
+	s.checksLock.Lock()
+
+	serviceID, ok := s.checks[checkID]
+	if !ok {
+		s.checksLock.Unlock()
+		return fmt.Errorf("check %q not found", checkID)
+	}
+	checked := s.services[serviceID]
+
+	var changed bool
+	reg := checked.registration.Copy()
+	for _, check := range reg.Checks {
+		if check.ID == checkID {
+			changed = check.Status != status
+			check.Status = status
+			check.Output = truncateCheckOutput(output)
+		}
+	}
+	checked.registration = reg
+	s.checksLock.Unlock()
+
+	if !changed {
+		return nil
+	}
+	return s.syncCheckedService(checked)
+}
+
+// syncCheckedService upserts the latest version of the tracked service
+// registration.
+func (s *ServiceRegistrationHandler) syncCheckedService(checked *checkedService) error {
+	checked.syncLock.Lock()
+	defer checked.syncLock.Unlock()
+
+	// Ensure the service has not been removed whilst waiting for the lock, as
+	// we do not want to recreate a deleted registration.
+	s.checksLock.Lock()
+	if s.services[checked.registration.ID] != checked {
+		s.checksLock.Unlock()
+		return nil
+	}
+	reg := checked.registration.Copy()
+	s.checksLock.Unlock()
+
+	args := structs.ServiceRegistrationUpsertRequest{
+		Services: []*structs.ServiceRegistration{reg},
+		WriteRequest: structs.WriteRequest{
+			Region:    s.cfg.Region,
+			AuthToken: s.cfg.NodeSecret,
+		},
+	}
+
+	var resp structs.ServiceRegistrationUpsertResponse
+
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		s.log.Error("failed to update service registration check status",
+			"error", err, "service_id", reg.ID)
+		return err
+	}
+	return nil
 }
 
 // RemoveWorkload iterates the services and removes them from the service
@@ -126,6 +312,14 @@ func (s *ServiceRegistrationHandler) removeWorkload(
 	// Generate the consistent ID for this service, so we know what to remove.
 	id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
 
+	// Stop any checks being run for the service. Holding the sync lock during
+	// the deletion ensures an in-flight check status update cannot recreate
+	// the registration.
+	if checked := s.untrackChecks(id); checked != nil {
+		checked.syncLock.Lock()
+		defer checked.syncLock.Unlock()
+	}
+
 	deleteArgs := structs.ServiceRegistrationDeleteByIDRequest{
 		ID: id,
 		WriteRequest: structs.WriteRequest{
@@ -240,23 +434,91 @@ func (s *ServiceRegistrationHandler) dedupUpdatedWorkload(
 	return oldCopy, newCopy
 }
 
-// AllocRegistrations is currently a noop implementation as the Nomad provider
-// does not support health check which is the sole subsystem caller of this
-// function.
-func (s *ServiceRegistrationHandler) AllocRegistrations(_ string) (*serviceregistration.AllocRegistration, error) {
-	return nil, nil
+// AllocRegistrations returns the registrations, along with their check
+// statuses, for the allocation. The check statuses are formatted as Consul
+// agent checks, so the allocation health tracker can process them regardless
+// of the provider. If the allocation has no services with checks, nil is
+// returned.
+func (s *ServiceRegistrationHandler) AllocRegistrations(allocID string) (*serviceregistration.AllocRegistration, error) {
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+
+	var allocReg *serviceregistration.AllocRegistration
+
+	for serviceID, checked := range s.services {
+		if checked.allocID != allocID {
+			continue
+		}
+
+		if allocReg == nil {
+			allocReg = &serviceregistration.AllocRegistration{
+				Tasks: make(map[string]*serviceregistration.ServiceRegistrations),
+			}
+		}
+
+		taskReg, ok := allocReg.Tasks[checked.taskName]
+		if !ok {
+			taskReg = &serviceregistration.ServiceRegistrations{
+				Services: make(map[string]*serviceregistration.ServiceRegistration),
+			}
+			allocReg.Tasks[checked.taskName] = taskReg
+		}
+
+		sreg := &serviceregistration.ServiceRegistration{
+			ServiceID:     serviceID,
+			CheckIDs:      make(map[string]struct{}, len(checked.registration.Checks)),
+			CheckOnUpdate: make(map[string]string, len(checked.onUpdate)),
+			Service: &api.AgentService{
+				ID:      serviceID,
+				Service: checked.registration.ServiceName,
+				Tags:    checked.registration.Tags,
+				Address: checked.registration.Address,
+				Port:    checked.registration.Port,
+			},
+			Checks: make([]*api.AgentCheck, 0, len(checked.registration.Checks)),
+		}
+
+		for checkID, onUpdate := range checked.onUpdate {
+			sreg.CheckOnUpdate[checkID] = onUpdate
+		}
+
+		for _, check := range checked.registration.Checks {
+			sreg.CheckIDs[check.ID] = struct{}{}
+			sreg.Checks = append(sreg.Checks, &api.AgentCheck{
+				CheckID:     check.ID,
+				Name:        check.Name,
+				Type:        check.Type,
+				Status:      check.Status,
+				Output:      check.Output,
+				ServiceID:   serviceID,
+				ServiceName: checked.registration.ServiceName,
+			})
+		}
+
+		taskReg.Services[serviceID] = sreg
+	}
+
+	return allocReg, nil
 }
 
-// UpdateTTL is currently a noop implementation as the Nomad provider does not
-// support health check which is the sole subsystem caller of this function.
-func (s *ServiceRegistrationHandler) UpdateTTL(_, _, _, _ string) error {
-	return nil
+// UpdateTTL updates the status of a script check. The namespace is not used,
+// as check IDs are unique within the client.
+func (s *ServiceRegistrationHandler) UpdateTTL(id, _, output, status string) error {
+	return s.updateCheckStatus(id, status, output)
 }
 
 // Shutdown is used to initiate shutdown of the handler. This is specifically
-// used to exit any routines running retry functions without leaving them
-// orphaned.
-func (s *ServiceRegistrationHandler) Shutdown() { close(s.shutDownCh) }
+// used to exit any routines running retry functions or checks without leaving
+// them orphaned.
+func (s *ServiceRegistrationHandler) Shutdown() {
+	s.checksLock.Lock()
+	for _, checked := range s.services {
+		checked.cancelFn()
+	}
+	s.checksLock.Unlock()
+
+	close(s.shutDownCh)
+}
 
 // generateNomadServiceRegistration is a helper to build the Nomad specific
 // registration object on a per-service basis.
@@ -289,8 +551,10 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		copy(tags, serviceSpec.Tags)
 	}
 
+	id := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec)
+
 	return &structs.ServiceRegistration{
-		ID:          serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), serviceSpec),
+		ID:          id,
 		ServiceName: serviceSpec.Name,
 		NodeID:      s.cfg.NodeID,
 		JobID:       workload.JobID,
@@ -300,5 +564,53 @@ func (s *ServiceRegistrationHandler) generateNomadServiceRegistration(
 		Tags:        tags,
 		Address:     ip,
 		Port:        port,
+		Checks:      s.generateCheckStatuses(id, serviceSpec),
 	}, nil
 }
+
+// generateCheckStatuses builds the check status objects for the service. If a
+// check is already being tracked, its current status is retained, so that
+// updating a workload does not reset the health of its checks.
+func (s *ServiceRegistrationHandler) generateCheckStatuses(
+	serviceID string, serviceSpec *structs.Service) []*structs.ServiceRegistrationCheck {
+
+	if len(serviceSpec.Checks) == 0 {
+		return nil
+	}
+
+	s.checksLock.Lock()
+	defer s.checksLock.Unlock()
+
+	existing := make(map[string]*structs.ServiceRegistrationCheck)
+	if checked, ok := s.services[serviceID]; ok {
+		for _, check := range checked.registration.Checks {
+			existing[check.ID] = check
+		}
+	}
+
+	checks := make([]*structs.ServiceRegistrationCheck, len(serviceSpec.Checks))
+
+	for i, check := range serviceSpec.Checks {
+		checkID := agentconsul.MakeCheckID(serviceID, check)
+
+		if current, ok := existing[checkID]; ok {
+			checks[i] = current.Copy()
+			continue
+		}
+
+		// Checks default to critical until they have been executed, which
+		// matches the behaviour of Consul.
+		status := check.InitialStatus
+		if status == "" {
+			status = structs.ServiceCheckStatusCritical
+		}
+
+		checks[i] = &structs.ServiceRegistrationCheck{
+			ID:     checkID,
+			Name:   check.Name,
+			Type:   check.Type,
+			Status: status,
+		}
+	}
+	return checks
+}
@@ -3,12 +3,14 @@ package nsd
 import (
 	"errors"
 	"fmt"
+	"net"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -551,3 +553,87 @@ func (mr *mockRPC) RPC(method string, _, _ interface{}) error {
 		return fmt.Errorf("unexpected RPC method: %v", method)
 	}
 }
+
+func TestServiceRegistrationHandler_Checks(t *testing.T) {
+
+	// Start a TCP listener which the check will connect to.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	mockRPC := mockRPC{callCounts: map[string]int{}}
+	h := NewServiceRegistrationHandler(hclog.NewNullLogger(), &ServiceRegistrationHandlerCfg{
+		Enabled: true,
+		RPCFn:   mockRPC.RPC,
+	}).(*ServiceRegistrationHandler)
+	defer h.Shutdown()
+
+	workload := mockWorkload()
+	workload.Ports = []structs.AllocatedPortMapping{{Label: "db", HostIP: "127.0.0.1", Value: port}}
+	workload.Services = []*structs.Service{
+		{
+			Name:        "redis-db",
+			AddressMode: structs.AddressModeHost,
+			PortLabel:   "db",
+			Checks: []*structs.ServiceCheck{
+				{
+					Name:     "redis-tcp",
+					Type:     structs.ServiceCheckTCP,
+					Interval: 100 * time.Millisecond,
+					Timeout:  time.Second,
+				},
+				{
+					Name:     "redis-script",
+					Type:     structs.ServiceCheckScript,
+					Command:  "/bin/true",
+					Interval: time.Second,
+					Timeout:  time.Second,
+				},
+			},
+		},
+	}
+	require.NoError(t, h.RegisterWorkload(workload))
+
+	serviceID := serviceregistration.MakeAllocServiceID(workload.AllocID, workload.Name(), workload.Services[0])
+	scriptCheckID := agentconsul.MakeCheckID(serviceID, workload.Services[0].Checks[1])
+
+	// Both checks start as critical, with the TCP check becoming passing once
+	// the runner has executed.
+	checkStatuses := func() map[string]string {
+		allocReg, err := h.AllocRegistrations(workload.AllocID)
+		require.NoError(t, err)
+		require.NotNil(t, allocReg)
+		statuses := make(map[string]string)
+		for _, check := range allocReg.Tasks[workload.Name()].Services[serviceID].Checks {
+			statuses[check.Name] = check.Status
+		}
+		return statuses
+	}
+	require.Eventually(t, func() bool {
+		return checkStatuses()["redis-tcp"] == structs.ServiceCheckStatusPassing
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, structs.ServiceCheckStatusCritical, checkStatuses()["redis-script"])
+
+	// Update the script check and ensure the status is tracked.
+	require.NoError(t, h.UpdateTTL(scriptCheckID, "", "OK", structs.ServiceCheckStatusPassing))
+	require.Equal(t, structs.ServiceCheckStatusPassing, checkStatuses()["redis-script"])
+
+	// Unknown checks should return an error, so callers can retry.
+	require.Error(t, h.UpdateTTL("unknown", "", "", structs.ServiceCheckStatusPassing))
+
+	// Removing the workload should stop tracking the checks.
+	h.RemoveWorkload(workload)
+	allocReg, err := h.AllocRegistrations(workload.AllocID)
+	require.NoError(t, err)
+	require.Nil(t, allocReg)
+}
@@ -137,3 +137,18 @@ func (h *HandlerWrapper) UpdateWorkload(old, new *serviceregistration.WorkloadSe
 
 	return nil
 }
+
+// ProviderHandler returns the handler implementation for the passed provider,
+// or nil if the provider is unknown. It is used by hooks which need to call
+// handler functions where the provider cannot be identified from the
+// arguments, such as health check lookups and TTL updates.
+func (h *HandlerWrapper) ProviderHandler(provider string) serviceregistration.Handler {
+	switch provider {
+	case structs.ServiceProviderNomad:
+		return h.nomadServiceProvider
+	case structs.ServiceProviderConsul, "":
+		return h.consulServiceProvider
+	default:
+		return nil
+	}
+}
@@ -382,3 +382,12 @@ func setupTestWrapper() (*HandlerWrapper, *regMock.ServiceRegistrationHandler, *
 	wrapper := NewHandlerWrapper(log, consulMock, nomadMock)
 	return wrapper, consulMock, nomadMock
 }
+
+func TestHandlerWrapper_ProviderHandler(t *testing.T) {
+	wrapper, consul, nomad := setupTestWrapper()
+
+	require.Same(t, nomad, wrapper.ProviderHandler(structs.ServiceProviderNomad))
+	require.Same(t, consul, wrapper.ProviderHandler(structs.ServiceProviderConsul))
+	require.Same(t, consul, wrapper.ProviderHandler(""))
+	require.Nil(t, wrapper.ProviderHandler("istio"))
+}
@@ -34,7 +34,7 @@ func TestHostStats_CPU(t *testing.T) {
 	logger := testlog.HCLogger(t)
 	cwd, err := os.Getwd()
 	assert.Nil(err)
-	hs := NewHostStatsCollector(logger, cwd, nil)
+	hs := NewHostStatsCollector(logger, cwd, nil, nil)
 
 	// Collect twice so we can calculate percents we need to generate some work
 	// so that the cpu values change
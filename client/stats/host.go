@@ -7,6 +7,7 @@ import (
 	"time"
 
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
@@ -21,6 +22,7 @@ type HostStats struct {
 	DiskStats        []*DiskStats
 	AllocDirStats    *DiskStats
 	DeviceStats      []*DeviceGroupStats
+	CSIVolumeStats   []*CSIVolumeStats
 	Uptime           uint64
 	Timestamp        int64
 	CPUTicksConsumed float64
@@ -60,6 +62,14 @@ type DeviceGroupStats = device.DeviceGroupStats
 // DeviceStatsCollector is used to retrieve all the latest statistics for all devices.
 type DeviceStatsCollector func() []*DeviceGroupStats
 
+// CSIVolumeStats represents the usage and condition of a CSI volume mounted
+// on the host
+type CSIVolumeStats = structs.CSIVolumeStats
+
+// CSIVolumeStatsCollector is used to retrieve the latest statistics for all
+// mounted CSI volumes.
+type CSIVolumeStatsCollector func() []*CSIVolumeStats
+
 // NodeStatsCollector is an interface which is used for the purposes of mocking
 // the HostStatsCollector in the tests
 type NodeStatsCollector interface {
@@ -75,6 +85,7 @@ type HostStatsCollector struct {
 	hostStatsLock        sync.RWMutex
 	allocDir             string
 	deviceStatsCollector DeviceStatsCollector
+	csiStatsCollector    CSIVolumeStatsCollector
 
 	// badParts is a set of partitions whose usage cannot be read; used to
 	// squelch logspam.
@@ -86,7 +97,7 @@ type HostStatsCollector struct {
 // NewHostStatsCollector returns a HostStatsCollector. The allocDir is passed in
 // so that we can present the disk related statistics for the mountpoint where
 // the allocation directory lives
-func NewHostStatsCollector(logger hclog.Logger, allocDir string, deviceStatsCollector DeviceStatsCollector) *HostStatsCollector {
+func NewHostStatsCollector(logger hclog.Logger, allocDir string, deviceStatsCollector DeviceStatsCollector, csiStatsCollector CSIVolumeStatsCollector) *HostStatsCollector {
 	logger = logger.Named("host_stats")
 	numCores := runtime.NumCPU()
 	statsCalculator := make(map[string]*HostCpuStatsCalculator)
@@ -97,6 +108,7 @@ func NewHostStatsCollector(logger hclog.Logger, allocDir string, deviceStatsColl
 		allocDir:             allocDir,
 		badParts:             make(map[string]struct{}),
 		deviceStatsCollector: deviceStatsCollector,
+		csiStatsCollector:    csiStatsCollector,
 	}
 	return collector
 }
@@ -159,6 +171,9 @@ func (h *HostStatsCollector) collectLocked() error {
 	deviceStats := h.collectDeviceGroupStats()
 	hs.DeviceStats = deviceStats
 
+	// Collect CSI volume stats
+	hs.CSIVolumeStats = h.collectCSIVolumeStats()
+
 	// Update the collected status object.
 	h.hostStats = hs
 
@@ -216,6 +231,14 @@ func (h *HostStatsCollector) collectDeviceGroupStats() []*DeviceGroupStats {
 	return h.deviceStatsCollector()
 }
 
+func (h *HostStatsCollector) collectCSIVolumeStats() []*CSIVolumeStats {
+	if h.csiStatsCollector == nil {
+		return []*CSIVolumeStats{}
+	}
+
+	return h.csiStatsCollector()
+}
+
 // Stats returns the host stats that has been collected
 func (h *HostStatsCollector) Stats() *HostStats {
 	h.hostStatsLock.RLock()
@@ -401,6 +401,50 @@ type ClientCSIControllerListSnapshotsResponse struct {
 	NextToken string
 }
 
+// ClientCSIControllerExpandVolumeRequest is the RPC made from the server to
+// a Nomad client to tell a CSI controller plugin on that client to perform
+// ControllerExpandVolume
+type ClientCSIControllerExpandVolumeRequest struct {
+	ExternalVolumeID string
+	Secrets          structs.CSISecrets
+	CapacityMin      int64
+	CapacityMax      int64
+
+	// VolumeCapability is optional and is only set if the volume is in use
+	VolumeCapability *structs.CSIVolumeCapability
+	MountOptions     *structs.CSIMountOptions
+
+	CSIControllerQuery
+}
+
+func (req *ClientCSIControllerExpandVolumeRequest) ToCSIRequest() (*csi.ControllerExpandVolumeRequest, error) {
+	creq := &csi.ControllerExpandVolumeRequest{
+		ExternalVolumeID: req.ExternalVolumeID,
+		Secrets:          req.Secrets,
+		CapacityRange: &csi.CapacityRange{
+			RequiredBytes: req.CapacityMin,
+			LimitBytes:    req.CapacityMax,
+		},
+	}
+
+	if req.VolumeCapability != nil {
+		ccap, err := csi.VolumeCapabilityFromStructs(
+			req.VolumeCapability.AttachmentMode,
+			req.VolumeCapability.AccessMode,
+			req.MountOptions)
+		if err != nil {
+			return nil, err
+		}
+		creq.VolumeCapability = ccap
+	}
+	return creq, nil
+}
+
+type ClientCSIControllerExpandVolumeResponse struct {
+	CapacityBytes         int64
+	NodeExpansionRequired bool
+}
+
 // ClientCSINodeDetachVolumeRequest is the RPC made from the server to
 // a Nomad client to tell a CSI node plugin on that client to perform
 // NodeUnpublish and NodeUnstage.
@@ -419,3 +463,26 @@ type ClientCSINodeDetachVolumeRequest struct {
 }
 
 type ClientCSINodeDetachVolumeResponse struct{}
+
+// ClientCSINodeExpandVolumeRequest is the RPC made from the server to a
+// Nomad client to tell a CSI node plugin on that client to perform
+// NodeExpandVolume for a volume that has been expanded by the controller.
+type ClientCSINodeExpandVolumeRequest struct {
+	PluginID    string // ID of the plugin that manages the volume (required)
+	VolumeID    string // ID of the volume to be expanded (required)
+	AllocID     string // ID of the allocation the volume is published for (required)
+	NodeID      string // ID of the Nomad client targeted
+	ExternalID  string // External ID of the volume to be expanded (required)
+	CapacityMin int64
+	CapacityMax int64
+
+	// These fields should match the original volume request so that
+	// we can find the mount points on the client
+	AttachmentMode structs.CSIVolumeAttachmentMode
+	AccessMode     structs.CSIVolumeAccessMode
+	ReadOnly       bool
+}
+
+type ClientCSINodeExpandVolumeResponse struct {
+	CapacityBytes int64
+}
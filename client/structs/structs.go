@@ -156,6 +156,33 @@ type FsLogsRequest struct {
 	structs.QueryOptions
 }
 
+// FsDownloadRequest is the initial request for downloading a tar archive of a
+// file or directory of an allocation. The archive is streamed back as the
+// payloads of StreamErrWrappers.
+type FsDownloadRequest struct {
+	// AllocID is the allocation to download from
+	AllocID string
+
+	// Path is the path to the file or directory to download
+	Path string
+
+	structs.QueryOptions
+}
+
+// FsUploadRequest is the initial request for uploading a tar archive into a
+// directory of an allocation. The archive follows as the payloads of
+// StreamErrWrappers, terminated by an empty payload. A single
+// StreamErrWrapper is sent back once the archive is extracted.
+type FsUploadRequest struct {
+	// AllocID is the allocation to upload to
+	AllocID string
+
+	// Path is the path to the directory to extract the archive into
+	Path string
+
+	structs.QueryOptions
+}
+
 // StreamErrWrapper is used to serialize output of a stream of a file or logs.
 type StreamErrWrapper struct {
 	// Error stores any error that may have occurred.
@@ -182,6 +209,20 @@ type AllocExecRequest struct {
 	structs.QueryOptions
 }
 
+// AllocPortForwardRequest is the initial request for forwarding a TCP
+// connection to a port of an allocation. It is followed by StreamErrWrapper
+// frames carrying the data sent to the port, with an empty payload signaling
+// that no more data will be sent.
+type AllocPortForwardRequest struct {
+	// AllocID is the allocation to forward the connection to
+	AllocID string
+
+	// Port is the label of the allocation port to forward the connection to
+	Port string
+
+	structs.QueryOptions
+}
+
 // AllocStatsRequest is used to request the resource usage of a given
 // allocation, potentially filtering by task
 type AllocStatsRequest struct {
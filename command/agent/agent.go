@@ -241,6 +241,16 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
+	for _, ac := range agentConfig.Server.AdmissionControllers {
+		if err := ac.Validate(); err != nil {
+			return nil, fmt.Errorf("Invalid Config, admission_controller %q: %v", ac.Name, err)
+		}
+		ac = ac.Copy()
+		if ac.Timeout == 0 {
+			ac.Timeout = config.DefaultAdmissionControllerTimeout
+		}
+		conf.AdmissionControllers = append(conf.AdmissionControllers, ac)
+	}
 	if agentConfig.Server.NonVotingServer {
 		conf.NonVoter = true
 	}
@@ -259,6 +269,15 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.EventBufferSize = int64(*agentConfig.Server.EventBufferSize)
 	}
+	if agentConfig.Server.EnableEventJournal != nil {
+		conf.EnableEventJournal = *agentConfig.Server.EnableEventJournal
+	}
+	if agentConfig.Server.EventJournalSize != nil {
+		if *agentConfig.Server.EventJournalSize <= 0 {
+			return nil, fmt.Errorf("Invalid Config, event_journal_size must be greater than zero")
+		}
+		conf.EventJournalSize = int64(*agentConfig.Server.EventJournalSize)
+	}
 	if agentConfig.Autopilot != nil {
 		if agentConfig.Autopilot.CleanupDeadServers != nil {
 			conf.AutopilotConfig.CleanupDeadServers = *agentConfig.Autopilot.CleanupDeadServers
@@ -456,6 +475,30 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftBoltNoFreelistSync = bolt.NoFreelistSync
 	}
 
+	// Set the rebalancer parameters
+	if rebalancer := agentConfig.Server.Rebalancer; rebalancer != nil {
+		conf.RebalancerConfig = conf.RebalancerConfig.Merge(rebalancer)
+		if err := conf.RebalancerConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("Invalid Config, rebalancer: %v", err)
+		}
+	}
+
+	// Set the fair share parameters
+	if fairShare := agentConfig.Server.FairShare; fairShare != nil {
+		conf.FairShareConfig = conf.FairShareConfig.Merge(fairShare)
+		if err := conf.FairShareConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("Invalid Config, fair_share: %v", err)
+		}
+	}
+
+	// Set the canary analysis parameters
+	if canaryAnalysis := agentConfig.Server.CanaryAnalysis; canaryAnalysis != nil {
+		conf.CanaryAnalysisConfig = conf.CanaryAnalysisConfig.Merge(canaryAnalysis)
+		if err := conf.CanaryAnalysisConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("Invalid Config, canary_analysis: %v", err)
+		}
+	}
+
 	return conf, nil
 }
 
@@ -591,6 +634,8 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.Client.MemoryMB != 0 {
 		conf.MemoryMB = agentConfig.Client.MemoryMB
 	}
+	conf.DiskIOPS = agentConfig.Client.DiskIOPS
+	conf.DiskMBps = agentConfig.Client.DiskMBps
 	if agentConfig.Client.MaxKillTimeout != "" {
 		dur, err := time.ParseDuration(agentConfig.Client.MaxKillTimeout)
 		if err != nil {
@@ -607,6 +652,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.Client.TemplateConfig != nil {
 		conf.TemplateConfig = agentConfig.Client.TemplateConfig.Copy()
 	}
+	conf.LogSinks = agentConfig.Client.LogSinks.Copy()
 
 	hvMap := make(map[string]*structs.ClientHostVolumeConfig, len(agentConfig.Client.HostVolumes))
 	for _, v := range agentConfig.Client.HostVolumes {
@@ -217,6 +217,8 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocStats(allocID, resp, req)
 	case "exec":
 		return s.allocExec(allocID, resp, req)
+	case "port-forward":
+		return s.allocPortForward(allocID, resp, req)
 	case "snapshot":
 		if s.agent.client == nil {
 			return nil, clientNotRunning
@@ -655,3 +657,200 @@ func forwardExecInput(encoder *codec.Encoder, ws *websocket.Conn, errCh chan<- H
 		}
 	}
 }
+
+// allocPortForwardEventType is the type of the audit event recorded when a
+// port forward session is requested
+const allocPortForwardEventType = "alloc-port-forward"
+
+// allocPortForwardEvent is the payload of the audit event recorded when a
+// port forward session is requested
+type allocPortForwardEvent struct {
+	AllocID    string
+	Namespace  string
+	Port       string
+	RemoteAddr string
+}
+
+func (s *HTTPServer) allocPortForward(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	args := cstructs.AllocPortForwardRequest{
+		AllocID: allocID,
+		Port:    req.URL.Query().Get("port"),
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	if args.Port == "" {
+		return nil, CodedError(400, "port label must be provided")
+	}
+
+	if err := s.auditPortForward(req, &args); err != nil {
+		return nil, CodedError(500, fmt.Sprintf("failed to record audit event: %v", err))
+	}
+
+	conn, err := s.wsUpgrader.Upgrade(resp, req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade connection: %v", err)
+	}
+
+	if err := readWsHandshake(conn.ReadJSON, req, &args.QueryOptions); err != nil {
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(toWsCode(400), err.Error()))
+		return nil, err
+	}
+
+	return s.portForwardStreamImpl(conn, &args)
+}
+
+// auditPortForward records the audit event of a port forward session. Errors
+// are only returned if the delivery of audit events is enforced.
+func (s *HTTPServer) auditPortForward(req *http.Request, args *cstructs.AllocPortForwardRequest) error {
+	auditor := s.agent.auditor
+	if auditor == nil || !auditor.Enabled() {
+		return nil
+	}
+
+	err := auditor.Event(req.Context(), allocPortForwardEventType, &allocPortForwardEvent{
+		AllocID:    args.AllocID,
+		Namespace:  args.RequestNamespace(),
+		Port:       args.Port,
+		RemoteAddr: req.RemoteAddr,
+	})
+	if err != nil {
+		if auditor.DeliveryEnforced() {
+			return err
+		}
+		s.logger.Error("failed to record audit event", "event", allocPortForwardEventType, "error", err)
+	}
+	return nil
+}
+
+func (s *HTTPServer) portForwardStreamImpl(ws *websocket.Conn, args *cstructs.AllocPortForwardRequest) (interface{}, error) {
+	allocID := args.AllocID
+	method := "Allocations.PortForward"
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the http response
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+
+		// don't close ws - wait to drain messages
+	}()
+
+	// Create a channel that decodes the results
+	errCh := make(chan HTTPCodedError, 2)
+
+	// stream response
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		go func() {
+			forwardPortForwardInput(encoder, ws, errCh)
+
+			// end the session once the websocket connection closes
+			cancel()
+		}()
+
+		for {
+			var res cstructs.StreamErrWrapper
+			err := decoder.Decode(&res)
+			if isClosedError(err) {
+				ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				errCh <- nil
+				return
+			}
+
+			if err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+			decoder.Reset(httpPipe)
+
+			if err := res.Error; err != nil {
+				code := 500
+				if err.Code != nil {
+					code = int(*err.Code)
+				}
+				errCh <- CodedError(code, err.Error())
+				return
+			}
+
+			if err := ws.WriteMessage(websocket.BinaryMessage, res.Payload); err != nil {
+				errCh <- CodedError(500, err.Error())
+				return
+			}
+		}
+	}()
+
+	// start streaming request to streaming RPC - returns when streaming completes or errors
+	handler(handlerPipe)
+	// stop streaming background goroutines for streaming - but not websocket activity
+	cancel()
+	// retrieve any error and/or wait until goroutine stop and close errCh connection before
+	// closing websocket connection
+	codedErr := <-errCh
+
+	// we won't return an error on ws close, but at least make it available in
+	// the logs so we can trace spurious disconnects
+	s.logger.Debug("alloc port forward channel closed with error", "error", codedErr)
+
+	if isClosedError(codedErr) {
+		codedErr = nil
+	} else if codedErr != nil {
+		ws.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(toWsCode(codedErr.Code()), codedErr.Error()))
+	}
+	ws.Close()
+
+	return nil, codedErr
+}
+
+// forwardPortForwardInput forwards the data received from the websocket
+// connection to the streaming RPC connection to client. An empty message
+// signals that no more data will be sent.
+func forwardPortForwardInput(encoder *codec.Encoder, ws *websocket.Conn, errCh chan<- HTTPCodedError) {
+	for {
+		_, data, err := ws.ReadMessage()
+		if err == io.EOF || websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			return
+		}
+
+		if err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		err = encoder.Encode(cstructs.StreamErrWrapper{Payload: data})
+		if err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+	}
+}
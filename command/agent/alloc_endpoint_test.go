@@ -684,6 +684,25 @@ func TestHTTP_AllocSnapshot(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocPortForward_MissingPort(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/client/allocation/123/port-forward", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		_, err = s.Server.ClientAllocRequest(respW, req)
+		require.Error(t, err)
+
+		codedErr, ok := err.(HTTPCodedError)
+		require.True(t, ok)
+		require.Equal(t, 400, codedErr.Code())
+		require.Contains(t, codedErr.Error(), "port label must be provided")
+	})
+}
+
 func TestHTTP_AllocSnapshot_WithMigrateToken(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -223,6 +223,11 @@ type ClientConfig struct {
 	// MemoryMB is used to override any detected or default total memory.
 	MemoryMB int `hcl:"memory_total_mb"`
 
+	// DiskIOPS and DiskMBps are the IO capacity of the disk backing the
+	// allocation directory, which can't be detected.
+	DiskIOPS int `hcl:"disk_total_iops"`
+	DiskMBps int `hcl:"disk_total_mbps"`
+
 	// ReservableCores is used to override detected reservable cpu cores.
 	ReserveableCores string `hcl:"reservable_cores"`
 
@@ -281,6 +286,9 @@ type ClientConfig struct {
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *client.ClientTemplateConfig `hcl:"template"`
 
+	// LogSinks restricts the destinations tasks may forward their logs to
+	LogSinks *client.LogSinkConfig `hcl:"log_sinks"`
+
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `hcl:"server_join"`
 
@@ -509,6 +517,16 @@ type ServerConfig struct {
 	// for the EventBufferSize is 1.
 	EventBufferSize *int `hcl:"event_buffer_size"`
 
+	// EnableEventJournal configures whether this server writes the events
+	// from its event stream to an on-disk journal, allowing subscribers to
+	// resume from indexes no longer held in memory, including across
+	// restarts. Requires EnableEventBroker.
+	EnableEventJournal *bool `hcl:"enable_event_journal"`
+
+	// EventJournalSize configures the number of raft indexes to retain events
+	// for in the event journal.
+	EventJournalSize *int `hcl:"event_journal_size"`
+
 	// LicensePath is the path to search for an enterprise license.
 	LicensePath string `hcl:"license_path"`
 
@@ -532,6 +550,22 @@ type ServerConfig struct {
 
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
+
+	// AdmissionControllers configures external admission controllers which
+	// can reject or patch jobs before they are registered.
+	AdmissionControllers []*config.AdmissionControllerConfig `hcl:"admission_controller"`
+
+	// Rebalancer configures the leader's rebalancer, which migrates
+	// allocations violating their spread or running on underutilized nodes.
+	Rebalancer *config.RebalancerConfig `hcl:"rebalancer"`
+
+	// FairShare configures weighted fair-share dequeuing of evaluations
+	// between namespaces or job meta queues.
+	FairShare *config.FairShareConfig `hcl:"fair_share"`
+
+	// CanaryAnalysis configures the sources of metrics that jobs may analyze
+	// their canaries with.
+	CanaryAnalysis *config.CanaryAnalysisConfig `hcl:"canary_analysis"`
 }
 
 // RaftBoltConfig is used in servers to configure parameters of the boltdb
@@ -976,11 +1010,13 @@ func DefaultConfig() *Config {
 			NomadServiceDiscovery:          helper.BoolToPtr(true),
 		},
 		Server: &ServerConfig{
-			Enabled:           false,
-			EnableEventBroker: helper.BoolToPtr(true),
-			EventBufferSize:   helper.IntToPtr(100),
-			RaftProtocol:      3,
-			StartJoin:         []string{},
+			Enabled:            false,
+			EnableEventBroker:  helper.BoolToPtr(true),
+			EventBufferSize:    helper.IntToPtr(100),
+			EnableEventJournal: helper.BoolToPtr(false),
+			EventJournalSize:   helper.IntToPtr(10000),
+			RaftProtocol:       3,
+			StartJoin:          []string{},
 			ServerJoin: &ServerJoin{
 				RetryJoin:        []string{},
 				RetryInterval:    30 * time.Second,
@@ -1582,6 +1618,14 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.EventBufferSize = b.EventBufferSize
 	}
 
+	if b.EnableEventJournal != nil {
+		result.EnableEventJournal = b.EnableEventJournal
+	}
+
+	if b.EventJournalSize != nil {
+		result.EventJournalSize = b.EventJournalSize
+	}
+
 	if b.DefaultSchedulerConfig != nil {
 		c := *b.DefaultSchedulerConfig
 		result.DefaultSchedulerConfig = &c
@@ -1610,6 +1654,35 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	if b.Rebalancer != nil {
+		if result.Rebalancer == nil {
+			result.Rebalancer = b.Rebalancer.Copy()
+		} else {
+			result.Rebalancer = result.Rebalancer.Merge(b.Rebalancer)
+		}
+	}
+
+	if b.FairShare != nil {
+		if result.FairShare == nil {
+			result.FairShare = b.FairShare.Copy()
+		} else {
+			result.FairShare = result.FairShare.Merge(b.FairShare)
+		}
+	}
+
+	if b.CanaryAnalysis != nil {
+		if result.CanaryAnalysis == nil {
+			result.CanaryAnalysis = b.CanaryAnalysis.Copy()
+		} else {
+			result.CanaryAnalysis = result.CanaryAnalysis.Merge(b.CanaryAnalysis)
+		}
+	}
+
+	// Add the admission controllers
+	for _, ac := range b.AdmissionControllers {
+		result.AdmissionControllers = append(result.AdmissionControllers, ac.Copy())
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -1654,6 +1727,12 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.MemoryMB != 0 {
 		result.MemoryMB = b.MemoryMB
 	}
+	if b.DiskIOPS != 0 {
+		result.DiskIOPS = b.DiskIOPS
+	}
+	if b.DiskMBps != 0 {
+		result.DiskMBps = b.DiskMBps
+	}
 	if b.MaxKillTimeout != "" {
 		result.MaxKillTimeout = b.MaxKillTimeout
 	}
@@ -1709,6 +1788,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.TemplateConfig = b.TemplateConfig
 	}
 
+	if b.LogSinks != nil {
+		result.LogSinks = b.LogSinks
+	}
+
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
 
@@ -129,6 +129,18 @@ func ParseConfigFile(path string) (*Config, error) {
 			fmt.Sprintf("audit.sink.%d", i), &sink.RotateDuration, &sink.RotateDurationHCL, nil})
 	}
 
+	// Add admission controllers for time.Duration parsing
+	for _, ac := range c.Server.AdmissionControllers {
+		tds = append(tds, durationConversionMap{
+			fmt.Sprintf("server.admission_controller.%s.timeout", ac.Name), &ac.Timeout, &ac.TimeoutHCL, nil})
+	}
+
+	// Add the rebalancer for time.Duration parsing
+	if r := c.Server.Rebalancer; r != nil {
+		tds = append(tds, durationConversionMap{
+			"server.rebalancer.interval", &r.Interval, &r.IntervalHCL, nil})
+	}
+
 	// convert strings to time.Durations
 	err = convertDurations(tds)
 	if err != nil {
@@ -226,6 +238,13 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Audit.ExtraKeysHCL, "sink")
 	}
 
+	// Remove AdmissionController extra keys
+	for _, ac := range c.Server.AdmissionControllers {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, ac.Name)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "admission_controller")
+		helper.RemoveEqualFold(&ac.ExtraKeysHCL, "headers")
+	}
+
 	for _, k := range []string{"enabled_schedulers", "start_join", "retry_join", "server_join"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "server")
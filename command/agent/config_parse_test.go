@@ -126,6 +126,8 @@ var basicConfig = &Config{
 		EncryptKey:                "abc",
 		EnableEventBroker:         helper.BoolToPtr(false),
 		EventBufferSize:           helper.IntToPtr(200),
+		EnableEventJournal:        helper.BoolToPtr(true),
+		EventJournalSize:          helper.IntToPtr(5000),
 		ServerJoin: &ServerJoin{
 			RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 			RetryInterval:    time.Duration(15) * time.Second,
@@ -869,3 +871,66 @@ func permutations(arr []string) [][]string {
 	helper(arr, len(arr))
 	return res
 }
+
+func TestConfig_ParseAdmissionControllers(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/admission_controller.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, []*config.AdmissionControllerConfig{
+		{
+			Name:       "registry",
+			Type:       config.AdmissionControllerTypeExec,
+			Command:    "/usr/local/bin/check-registry",
+			Args:       []string{"-allowed", "registry.example.com"},
+			Timeout:    2 * time.Second,
+			TimeoutHCL: "2s",
+		},
+		{
+			Name:     "policy",
+			Type:     config.AdmissionControllerTypeWebhook,
+			Address:  "https://policy.example.com/admit",
+			Headers:  map[string]string{"Authorization": "Bearer secret"},
+			FailOpen: true,
+		},
+	}, c.Server.AdmissionControllers)
+}
+
+func TestConfig_ParseRebalancer(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/rebalancer.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, &config.RebalancerConfig{
+		Interval:             10 * time.Minute,
+		IntervalHCL:          "10m",
+		MaxMigrations:        5,
+		UtilizationThreshold: helper.Float64ToPtr(0.3),
+	}, c.Server.Rebalancer)
+}
+
+func TestConfig_ParseFairShare(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/fair_share.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, &config.FairShareConfig{
+		Enabled:      helper.BoolToPtr(true),
+		QueueMetaKey: "queue",
+		Weights:      map[string]int{"default": 3, "batch": 1},
+	}, c.Server.FairShare)
+}
+
+func TestConfig_ParseCanaryAnalysis(t *testing.T) {
+	ci.Parallel(t)
+
+	c, err := ParseConfigFile("./testdata/canary_analysis.hcl")
+	require.NoError(t, err)
+
+	require.Equal(t, &config.CanaryAnalysisConfig{
+		PrometheusAddresses: []string{"http://prometheus.service.consul:9090"},
+	}, c.Server.CanaryAnalysis)
+}
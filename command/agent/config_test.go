@@ -148,6 +148,8 @@ func TestConfig_Merge(t *testing.T) {
 			UpgradeVersion:         "foo",
 			EnableEventBroker:      helper.BoolToPtr(false),
 			EventBufferSize:        helper.IntToPtr(0),
+			EnableEventJournal:     helper.BoolToPtr(false),
+			EventJournalSize:       helper.IntToPtr(0),
 		},
 		ACL: &ACLConfig{
 			Enabled:          true,
@@ -343,6 +345,8 @@ func TestConfig_Merge(t *testing.T) {
 			UpgradeVersion:         "bar",
 			EnableEventBroker:      helper.BoolToPtr(true),
 			EventBufferSize:        helper.IntToPtr(100),
+			EnableEventJournal:     helper.BoolToPtr(true),
+			EventJournalSize:       helper.IntToPtr(5000),
 		},
 		ACL: &ACLConfig{
 			Enabled:          true,
@@ -1393,6 +1397,19 @@ func TestEventBroker_Parse(t *testing.T) {
 		require.Equal(true, *result.EnableEventBroker)
 		require.Equal(20000, *result.EventBufferSize)
 	}
+
+	{
+		a := DefaultConfig().Server
+		b := &ServerConfig{
+			EnableEventJournal: helper.BoolToPtr(true),
+			EventJournalSize:   helper.IntToPtr(500),
+		}
+
+		result := a.Merge(b)
+		require.Equal(true, *result.EnableEventJournal)
+		require.Equal(500, *result.EventJournalSize)
+		require.Equal(true, *result.EnableEventBroker)
+	}
 }
 
 func TestConfig_LoadConsulTemplateConfig(t *testing.T) {
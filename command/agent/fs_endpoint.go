@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-msgpack/codec"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -38,6 +39,12 @@ func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (int
 		return s.wrapUntrustedContent(s.FileCatRequest)(resp, req)
 	case strings.HasPrefix(path, "stream/"):
 		return s.Stream(resp, req)
+	case strings.HasPrefix(path, "download/"):
+		// Downloads are *trusted* content because the endpoint
+		// explicitly sets the Content-Type to application/x-tar.
+		return s.Download(resp, req)
+	case strings.HasPrefix(path, "upload/"):
+		return s.Upload(resp, req)
 	case strings.HasPrefix(path, "logs/"):
 		// Logs are *trusted* content because the endpoint
 		// explicitly sets the Content-Type to text/plain or
@@ -254,6 +261,63 @@ func (s *HTTPServer) Stream(resp http.ResponseWriter, req *http.Request) (interf
 	return s.fsStreamImpl(resp, req, "FileSystem.Stream", fsReq, fsReq.AllocID)
 }
 
+// Download streams a tar archive of a file or directory. The parameters are:
+// * path: path to the file or directory to download.
+func (s *HTTPServer) Download(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	var allocID, path string
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/download/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+
+	if path = req.URL.Query().Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsDownloadRequest{
+		AllocID: allocID,
+		Path:    path,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	// Force the Content-Type to avoid Go's http.ResponseWriter from
+	// detecting an incorrect or unsafe one.
+	resp.Header().Set("Content-Type", "application/x-tar")
+
+	// Make the request
+	return s.fsStreamImpl(resp, req, "FileSystem.Download", fsReq, fsReq.AllocID)
+}
+
+// Upload extracts the tar archive of the request body into a directory. The
+// parameters are:
+// * path: path to the directory to extract the archive into.
+func (s *HTTPServer) Upload(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var allocID, path string
+
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/upload/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+
+	if path = req.URL.Query().Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsUploadRequest{
+		AllocID: allocID,
+		Path:    path,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	// Make the request
+	return s.fsUploadImpl(req, "FileSystem.Upload", fsReq, fsReq.AllocID)
+}
+
 // Logs streams the content of a log blocking on EOF. The parameters are:
 // * task: task name to stream logs for.
 // * type: stdout/stderr to stream.
@@ -429,3 +493,93 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 	}
 	return nil, codedErr
 }
+
+// fsUploadImpl is used to make a streaming filesystem call that serializes the
+// args and then sends the request body as the payloads of a stream of
+// StreamErrWrappers, terminated by an empty payload. A single
+// StreamErrWrapper result is expected once the upload completes.
+func (s *HTTPServer) fsUploadImpl(req *http.Request, method string, args interface{}, allocID string) (interface{}, error) {
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	// Create a pipe connecting the http request to the (possibly remote) handler
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	// Send the request and the body in the background
+	go func() {
+		if err := encoder.Encode(args); err != nil {
+			return
+		}
+
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := req.Body.Read(buf)
+			if n > 0 {
+				if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+					return
+				}
+			}
+
+			switch err {
+			case nil:
+			case io.EOF:
+				encoder.Encode(&cstructs.StreamErrWrapper{})
+				return
+			default:
+				encoder.Encode(&cstructs.StreamErrWrapper{
+					Error: cstructs.NewRpcError(err, helper.Int64ToPtr(400)),
+				})
+				return
+			}
+		}
+	}()
+
+	// Create a channel that decodes the result
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		var res cstructs.StreamErrWrapper
+		if err := decoder.Decode(&res); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		if err := res.Error; err != nil {
+			code := 500
+			if err.Code != nil {
+				code = int(*err.Code)
+			}
+
+			errCh <- CodedError(code, err.Error())
+			return
+		}
+		errCh <- nil
+	}()
+
+	handler(handlerPipe)
+	codedErr := <-errCh
+	return nil, codedErr
+}
@@ -69,6 +69,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/summary"):
 		jobName := strings.TrimSuffix(path, "/summary")
 		return s.jobSummaryRequest(resp, req, jobName)
+	case strings.HasSuffix(path, "/dependencies"):
+		jobName := strings.TrimSuffix(path, "/dependencies")
+		return s.jobDependenciesRequest(resp, req, jobName)
 	case strings.HasSuffix(path, "/dispatch"):
 		jobName := strings.TrimSuffix(path, "/dispatch")
 		return s.jobDispatchRequest(resp, req, jobName)
@@ -680,6 +683,26 @@ func (s *HTTPServer) jobSummaryRequest(resp http.ResponseWriter, req *http.Reque
 	return out.JobSummary, nil
 }
 
+func (s *HTTPServer) jobDependenciesRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.JobSpecificRequest{
+		JobID: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobDependenciesResponse
+	if err := s.agent.RPC("Job.Dependencies", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out.Dependencies, nil
+}
+
 func (s *HTTPServer) jobDispatchRequest(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
 	if req.Method != "PUT" && req.Method != "POST" {
 		return nil, CodedError(405, ErrInvalidMethod)
@@ -916,6 +939,20 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Affinities:     ApiAffinitiesToStructs(job.Affinities),
 	}
 
+	if job.Gang != nil {
+		j.Gang = *job.Gang
+	}
+
+	if len(job.DependsOn) > 0 {
+		j.DependsOn = make([]*structs.JobDependency, len(job.DependsOn))
+		for i, d := range job.DependsOn {
+			j.DependsOn[i] = &structs.JobDependency{
+				JobID:     d.JobID,
+				Condition: d.Condition,
+			}
+		}
+	}
+
 	// Update has been pushed into the task groups. stagger and max_parallel are
 	// preserved at the job level, but all other values are discarded. The job.Update
 	// api value is merged into TaskGroups already in api.Canonicalize
@@ -1004,6 +1041,10 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		Mode:     *taskGroup.RestartPolicy.Mode,
 	}
 
+	if taskGroup.Gang != nil {
+		tg.Gang = *taskGroup.Gang
+	}
+
 	if taskGroup.ShutdownDelay != nil {
 		tg.ShutdownDelay = taskGroup.ShutdownDelay
 	}
@@ -1102,6 +1143,33 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		if taskGroup.Update.AutoPromote != nil {
 			tg.Update.AutoPromote = *taskGroup.Update.AutoPromote
 		}
+
+		if analysis := taskGroup.Update.Analysis; analysis != nil {
+			tg.Update.Analysis = &structs.AnalysisStrategy{
+				Source:       *analysis.Source,
+				Address:      *analysis.Address,
+				Interval:     *analysis.Interval,
+				BakeTime:     *analysis.BakeTime,
+				FailureLimit: *analysis.FailureLimit,
+			}
+
+			for _, m := range analysis.Metrics {
+				tg.Update.Analysis.Metrics = append(tg.Update.Analysis.Metrics, &structs.AnalysisMetric{
+					Name:        m.Name,
+					Query:       m.Query,
+					Max:         m.Max,
+					MaxIncrease: m.MaxIncrease,
+				})
+			}
+		}
+
+		for _, s := range taskGroup.Update.Steps {
+			tg.Update.Steps = append(tg.Update.Steps, &structs.RolloutStep{
+				Percent: *s.Percent,
+				Pause:   *s.Pause,
+				Manual:  *s.Manual,
+			})
+		}
 	}
 
 	if len(taskGroup.Tasks) > 0 {
@@ -1177,10 +1245,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 
 	structsTask.Resources = ApiResourcesToStructs(apiTask.Resources)
 
-	structsTask.LogConfig = &structs.LogConfig{
-		MaxFiles:      *apiTask.LogConfig.MaxFiles,
-		MaxFileSizeMB: *apiTask.LogConfig.MaxFileSizeMB,
-	}
+	structsTask.LogConfig = apiLogConfigToStructs(apiTask.LogConfig)
 
 	if len(apiTask.Artifacts) > 0 {
 		structsTask.Artifacts = []*structs.TaskArtifact{}
@@ -1305,6 +1370,32 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 		}
 	}
 
+	if in.NUMA != nil {
+		out.NUMA = &structs.NUMA{
+			Affinity: in.NUMA.Affinity,
+		}
+	}
+
+	if in.DiskIO != nil {
+		out.DiskIO = &structs.DiskIO{}
+		if in.DiskIO.ReadIOPS != nil {
+			out.DiskIO.ReadIOPS = *in.DiskIO.ReadIOPS
+		}
+		if in.DiskIO.WriteIOPS != nil {
+			out.DiskIO.WriteIOPS = *in.DiskIO.WriteIOPS
+		}
+		if in.DiskIO.ReadMBps != nil {
+			out.DiskIO.ReadMBps = *in.DiskIO.ReadMBps
+		}
+		if in.DiskIO.WriteMBps != nil {
+			out.DiskIO.WriteMBps = *in.DiskIO.WriteMBps
+		}
+	}
+
+	if in.EgressMbits != nil {
+		out.EgressMbits = *in.EgressMbits
+	}
+
 	return out
 }
 
@@ -1704,10 +1795,21 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 	if in == nil {
 		return nil
 	}
-	return &structs.LogConfig{
+	out := &structs.LogConfig{
 		MaxFiles:      dereferenceInt(in.MaxFiles),
 		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
 	}
+	for _, sink := range in.Sinks {
+		out.Sinks = append(out.Sinks, &structs.LogSink{
+			Name:       sink.Name,
+			Type:       sink.Type,
+			Address:    sink.Address,
+			Facility:   sink.Facility,
+			Tag:        sink.Tag,
+			BufferSize: dereferenceInt(sink.BufferSize),
+		})
+	}
+	return out
 }
 
 func dereferenceInt(in *int) int {
@@ -1768,6 +1870,9 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	ret := &structs.Spread{}
 	ret.Attribute = a1.Attribute
 	ret.Weight = *a1.Weight
+	if a1.MaxSkew != nil {
+		ret.MaxSkew = *a1.MaxSkew
+	}
 	if a1.SpreadTarget != nil {
 		ret.SpreadTarget = make([]*structs.SpreadTarget, len(a1.SpreadTarget))
 		for i, st := range a1.SpreadTarget {
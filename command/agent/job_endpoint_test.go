@@ -3551,6 +3551,25 @@ func TestConversion_apiLogConfigToStructs(t *testing.T) {
 		MaxFiles:      helper.IntToPtr(2),
 		MaxFileSizeMB: helper.IntToPtr(8),
 	}))
+	require.Equal(t, &structs.LogConfig{
+		MaxFiles:      2,
+		MaxFileSizeMB: 8,
+		Sinks: []*structs.LogSink{{
+			Name:       "collector",
+			Type:       structs.LogSinkTypeTCP,
+			Address:    "127.0.0.1:5170",
+			BufferSize: 512,
+		}},
+	}, apiLogConfigToStructs(&api.LogConfig{
+		MaxFiles:      helper.IntToPtr(2),
+		MaxFileSizeMB: helper.IntToPtr(8),
+		Sinks: []*api.LogSink{{
+			Name:       "collector",
+			Type:       "tcp",
+			Address:    "127.0.0.1:5170",
+			BufferSize: helper.IntToPtr(512),
+		}},
+	}))
 }
 
 func TestConversion_apiResourcesToStructs(t *testing.T) {
@@ -267,6 +267,10 @@ func (s *HTTPServer) schedulerUpdateConfig(resp http.ResponseWriter, req *http.R
 			SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
 			BatchSchedulerEnabled:    conf.PreemptionConfig.BatchSchedulerEnabled,
 			ServiceSchedulerEnabled:  conf.PreemptionConfig.ServiceSchedulerEnabled},
+		RebalancerConfig: structs.RebalancerConfig{
+			Enabled: conf.RebalancerConfig.Enabled,
+			DryRun:  conf.RebalancerConfig.DryRun,
+		},
 	}
 
 	if err := args.Config.Validate(); err != nil {
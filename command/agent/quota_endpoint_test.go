@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_QuotaList(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		qs1 := mock.QuotaSpec()
+		qs2 := mock.QuotaSpec()
+		args := structs.QuotaSpecUpsertRequest{
+			Quotas:       []*structs.QuotaSpec{qs1, qs2},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(t, s.Agent.RPC("Quota.UpsertQuotaSpecs", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/quotas", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.QuotasRequest(respW, req)
+		require.NoError(t, err)
+
+		// Check for the index
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Len(t, obj.([]*structs.QuotaSpec), 2)
+
+		// List the usages
+		req, err = http.NewRequest("GET", "/v1/quota-usages", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.QuotaUsagesRequest(respW, req)
+		require.NoError(t, err)
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Len(t, obj.([]*structs.QuotaUsage), 2)
+	})
+}
+
+func TestHTTP_QuotaQuery(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		qs := mock.QuotaSpec()
+		args := structs.QuotaSpecUpsertRequest{
+			Quotas:       []*structs.QuotaSpec{qs},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(t, s.Agent.RPC("Quota.UpsertQuotaSpecs", &args, &resp))
+
+		// Query the quota specification
+		req, err := http.NewRequest("GET", "/v1/quota/"+qs.Name, nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal(t, qs.Name, obj.(*structs.QuotaSpec).Name)
+
+		// Query the quota usage
+		req, err = http.NewRequest("GET", "/v1/quota/usage/"+qs.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.Equal(t, qs.Name, obj.(*structs.QuotaUsage).Name)
+
+		// Query a missing quota specification
+		req, err = http.NewRequest("GET", "/v1/quota/missing", nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not found")
+	})
+}
+
+func TestHTTP_QuotaCreateDelete(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Create the quota specification
+		qs := mock.QuotaSpec()
+		buf := encodeReq(qs)
+		req, err := http.NewRequest("PUT", "/v1/quota", buf)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.QuotaCreateRequest(respW, req)
+		require.NoError(t, err)
+		require.Nil(t, obj)
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out, err := s.Agent.server.State().QuotaSpecByName(nil, qs.Name)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+
+		// Delete the quota specification
+		req, err = http.NewRequest("DELETE", "/v1/quota/"+qs.Name, nil)
+		require.NoError(t, err)
+		respW = httptest.NewRecorder()
+
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(t, err)
+		require.NotZero(t, respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out, err = s.Agent.server.State().QuotaSpecByName(nil, qs.Name)
+		require.NoError(t, err)
+		require.Nil(t, out)
+	})
+}
@@ -0,0 +1,20 @@
+server {
+  enabled = true
+
+  admission_controller "registry" {
+    type    = "exec"
+    command = "/usr/local/bin/check-registry"
+    args    = ["-allowed", "registry.example.com"]
+    timeout = "2s"
+  }
+
+  admission_controller "policy" {
+    type      = "webhook"
+    address   = "https://policy.example.com/admit"
+    fail_open = true
+
+    headers {
+      Authorization = "Bearer secret"
+    }
+  }
+}
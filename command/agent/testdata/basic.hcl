@@ -132,6 +132,8 @@ server {
   raft_multiplier               = 4
   enable_event_broker           = false
   event_buffer_size             = 200
+  enable_event_journal          = true
+  event_journal_size            = 5000
 
   server_join {
     retry_join     = ["1.1.1.1", "2.2.2.2"]
@@ -0,0 +1,7 @@
+server {
+  enabled = true
+
+  canary_analysis {
+    prometheus_addresses = ["http://prometheus.service.consul:9090"]
+  }
+}
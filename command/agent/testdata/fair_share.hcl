@@ -0,0 +1,13 @@
+server {
+  enabled = true
+
+  fair_share {
+    enabled        = true
+    queue_meta_key = "queue"
+
+    weights {
+      default = 3
+      batch   = 1
+    }
+  }
+}
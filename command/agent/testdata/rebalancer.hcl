@@ -0,0 +1,9 @@
+server {
+  enabled = true
+
+  rebalancer {
+    interval              = "10m"
+    max_migrations        = 5
+    utilization_threshold = 0.3
+  }
+}
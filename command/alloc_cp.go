@@ -0,0 +1,243 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper/tarfs"
+	"github.com/posener/complete"
+)
+
+type AllocCpCommand struct {
+	Meta
+}
+
+func (c *AllocCpCommand) Help() string {
+	helpText := `
+Usage: nomad alloc cp [options] <src> <dest>
+
+  Copies files or directories between an allocation and the local filesystem.
+  Either the source or the destination must be an allocation path, in the
+  form <allocation>:<path>. The path is relative to the root of the alloc dir.
+  Directories are copied recursively.
+
+  If the source is "-", a tar archive read from stdin is extracted into the
+  allocation directory given as destination. If the destination is "-", a tar
+  archive of the allocation source path is written to stdout.
+
+  When ACLs are enabled, copying files out of an allocation requires a token
+  with the 'read-fs', 'read-job', and 'list-jobs' capabilities for the
+  allocation's namespace. Copying files into an allocation additionally
+  requires the 'write-fs' capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocCpCommand) Synopsis() string {
+	return "Copy files into and out of an allocation directory"
+}
+
+func (c *AllocCpCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *AllocCpCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(complete.PredictFiles("*"), complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	}))
+}
+
+func (c *AllocCpCommand) Name() string { return "alloc cp" }
+
+func (c *AllocCpCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <src> <dest>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	src, dest := args[0], args[1]
+
+	srcAlloc, srcPath, srcRemote := parseAllocPath(src)
+	destAlloc, destPath, destRemote := parseAllocPath(dest)
+	if srcRemote == destRemote {
+		c.Ui.Error("Either the source or the destination must be an allocation path")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	if srcRemote {
+		alloc, code := c.lookupAlloc(client, srcAlloc)
+		if alloc == nil {
+			return code
+		}
+		return c.download(client, alloc, srcPath, dest)
+	}
+
+	alloc, code := c.lookupAlloc(client, destAlloc)
+	if alloc == nil {
+		return code
+	}
+	return c.upload(client, alloc, src, destPath)
+}
+
+// parseAllocPath splits an argument of the form <allocation>:<path> into the
+// allocation ID prefix and path. It returns false if the argument is a local
+// path, including Windows paths with a volume name.
+func parseAllocPath(arg string) (string, string, bool) {
+	if filepath.VolumeName(arg) != "" {
+		return "", "", false
+	}
+
+	i := strings.Index(arg, ":")
+	if i <= 0 || strings.ContainsAny(arg[:i], `/\`) {
+		return "", "", false
+	}
+
+	p := arg[i+1:]
+	if p == "" {
+		p = "/"
+	}
+	return arg[:i], p, true
+}
+
+// lookupAlloc returns the allocation matching the ID prefix. If the prefix
+// doesn't match a single allocation an error is output and the allocation is
+// nil, along with the exit code.
+func (c *AllocCpCommand) lookupAlloc(client *api.Client, allocID string) (*api.Allocation, int) {
+	if len(allocID) == 1 {
+		c.Ui.Error("Alloc ID must contain at least two characters.")
+		return nil, 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return nil, 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return nil, 1
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, false, shortId)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return nil, 1
+	}
+
+	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return nil, 1
+	}
+	return alloc, 0
+}
+
+// download copies the file or directory at the path of the allocation to the
+// local destination, or writes its archive to stdout if the destination is
+// "-".
+func (c *AllocCpCommand) download(client *api.Client, alloc *api.Allocation, src, dest string) int {
+	r, err := client.AllocFS().Download(alloc, src, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error downloading %s: %s", src, err))
+		return 1
+	}
+	defer r.Close()
+
+	if dest == "-" {
+		if _, err := io.Copy(os.Stdout, r); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error downloading %s: %s", src, err))
+			return 1
+		}
+		return 0
+	}
+
+	dest, err = filepath.Abs(dest)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error resolving %s: %s", dest, err))
+		return 1
+	}
+
+	// Copy into the destination if it's a directory, otherwise create or
+	// replace it
+	dir, name := dest, ""
+	if fi, err := os.Stat(dest); err != nil || !fi.IsDir() {
+		dir, name = filepath.Dir(dest), filepath.Base(dest)
+	}
+
+	if err := tarfs.Extract(r, dir, name, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error downloading %s: %s", src, err))
+		return 1
+	}
+	return 0
+}
+
+// upload copies the local file or directory to the path of the allocation, or
+// extracts the archive read from stdin into it if the source is "-".
+func (c *AllocCpCommand) upload(client *api.Client, alloc *api.Allocation, src, dest string) int {
+	if src == "-" {
+		if _, err := client.AllocFS().Upload(alloc, dest, os.Stdin, nil); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error uploading to %s: %s", dest, err))
+			return 1
+		}
+		return 0
+	}
+
+	if _, err := os.Stat(src); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading %s: %s", src, err))
+		return 1
+	}
+
+	// Copy into the destination if it's a directory, otherwise create or
+	// replace it
+	dir, name := dest, filepath.Base(src)
+	if file, _, err := client.AllocFS().Stat(alloc, dest, nil); err != nil || !file.IsDir {
+		dir, name = path.Dir(dest), path.Base(dest)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(tarfs.Archive(pw, src, name, nil))
+	}()
+	defer pr.Close()
+
+	if _, err := client.AllocFS().Upload(alloc, dir, pr, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error uploading to %s: %s", dest, err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocCpCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocCpCommand{}
+}
+
+func TestAllocCpCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &AllocCpCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails without an allocation path
+	code = cmd.Run([]string{"./src", "./dest"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "must be an allocation path")
+	ui.ErrorWriter.Reset()
+
+	// Fails when copying between allocations
+	code = cmd.Run([]string{"foo:src", "bar:dest"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "must be an allocation path")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	code = cmd.Run([]string{"-address=nope", "foobar:src", "dest"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error querying allocation")
+}
+
+func TestAllocCpCommand_parseAllocPath(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		arg    string
+		alloc  string
+		path   string
+		remote bool
+	}{
+		{arg: "eb17e557:redis/local", alloc: "eb17e557", path: "redis/local", remote: true},
+		{arg: "eb17e557:", alloc: "eb17e557", path: "/", remote: true},
+		{arg: "./local:file"},
+		{arg: ":file"},
+		{arg: "local/file"},
+		{arg: "-"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.arg, func(t *testing.T) {
+			alloc, path, remote := parseAllocPath(tc.arg)
+			require.Equal(t, tc.alloc, alloc)
+			require.Equal(t, tc.path, path)
+			require.Equal(t, tc.remote, remote)
+		})
+	}
+}
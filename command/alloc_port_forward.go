@@ -0,0 +1,244 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocPortForwardCommand struct {
+	Meta
+}
+
+func (c *AllocPortForwardCommand) Help() string {
+	helpText := `
+Usage: nomad alloc port-forward [options] <allocation> [<local>:]<port-label>
+
+  Forwards TCP connections made to a local port to a port of a running
+  allocation. The port is referenced by its label in the allocation's network
+  block. Connections are tunneled through the Nomad agents, so the port
+  doesn't need to be reachable from this machine, and allocations using bridge
+  or CNI networking are reached within their network namespace.
+
+  The local port may be prefixed by the address to listen on, which defaults to
+  127.0.0.1. If the local port is omitted or 0, a random port is used. The
+  command forwards connections until it's interrupted.
+
+  When ACLs are enabled, this command requires a token with the
+  'alloc-port-forward', 'read-job', and 'list-jobs' capabilities for the
+  allocation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Port Forward Specific Options:
+
+  -job
+    Use a random allocation from the specified job ID.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocPortForwardCommand) Synopsis() string {
+	return "Forward local connections to a port of an allocation"
+}
+
+func (c *AllocPortForwardCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job": complete.PredictAnything,
+		})
+}
+
+func (c *AllocPortForwardCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	})
+}
+
+func (c *AllocPortForwardCommand) Name() string { return "alloc port-forward" }
+
+func (c *AllocPortForwardCommand) Run(args []string) int {
+	var job bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&job, "job", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <allocation> [<local>:]<port-label>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if !job && len(args[0]) == 1 {
+		c.Ui.Error("Alloc ID must contain at least two characters")
+		return 1
+	}
+
+	local, label := parsePortMapping(args[1])
+	if label == "" {
+		c.Ui.Error("A port label is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	var allocStub *api.AllocationListStub
+	if job {
+		allocStub, err = getRandomJobAlloc(client, args[0])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
+			return 1
+		}
+	} else {
+		allocID := args[0]
+		allocs, _, err := client.Allocations().PrefixList(sanitizeUUIDPrefix(allocID))
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+			return 1
+		}
+
+		if len(allocs) == 0 {
+			c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+			return 1
+		}
+
+		if len(allocs) > 1 {
+			out := formatAllocListStubs(allocs, false, shortId)
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+			return 1
+		}
+
+		allocStub = allocs[0]
+	}
+
+	q := &api.QueryOptions{Namespace: allocStub.Namespace}
+	alloc, _, err := client.Allocations().Info(allocStub.ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	if err := validatePortExistsInAllocation(label, alloc); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	ln, err := net.Listen("tcp", local)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listening on %s: %s", local, err))
+		return 1
+	}
+	defer ln.Close()
+
+	c.Ui.Output(fmt.Sprintf("Forwarding from %s to port %q of allocation %s",
+		ln.Addr(), label, limit(alloc.ID, shortId)))
+
+	return c.forward(client, alloc, label, ln)
+}
+
+// forward accepts connections on the listener and forwards each of them to
+// the port of the allocation, until the command is interrupted.
+func (c *AllocPortForwardCommand) forward(client *api.Client, alloc *api.Allocation, label string, ln net.Listener) int {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+			cancelFn()
+			ln.Close()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0
+			}
+			c.Ui.Error(fmt.Sprintf("Error accepting connection: %s", err))
+			return 1
+		}
+
+		go func() {
+			defer conn.Close()
+			if err := client.Allocations().PortForward(ctx, alloc, label, conn, nil); err != nil && ctx.Err() == nil {
+				c.Ui.Error(fmt.Sprintf("Error forwarding connection from %s: %s", conn.RemoteAddr(), err))
+			}
+		}()
+	}
+}
+
+// parsePortMapping splits an argument of the form [<local>:]<port-label> into
+// the local address to listen on and the port label. The local address
+// defaults to a random port of 127.0.0.1.
+func parsePortMapping(arg string) (string, string) {
+	local, label := "0", arg
+	if i := strings.LastIndex(arg, ":"); i >= 0 {
+		local, label = arg[:i], arg[i+1:]
+	}
+
+	if local == "" {
+		local = "0"
+	}
+	if !strings.Contains(local, ":") {
+		local = net.JoinHostPort("127.0.0.1", local)
+	}
+	return local, label
+}
+
+// validatePortExistsInAllocation returns an error if the allocation doesn't
+// have a port with the given label.
+func validatePortExistsInAllocation(label string, alloc *api.Allocation) error {
+	var labels []string
+	if alloc.AllocatedResources != nil {
+		for _, port := range alloc.AllocatedResources.Shared.Ports {
+			if port.Label == label {
+				return nil
+			}
+			labels = append(labels, port.Label)
+		}
+	}
+
+	if len(labels) == 0 {
+		return fmt.Errorf("Allocation %s has no ports", limit(alloc.ID, shortId))
+	}
+
+	sort.Strings(labels)
+	return fmt.Errorf("Could not find port named: %s, found:\n%s", label, formatList(labels))
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocPortForwardCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocPortForwardCommand{}
+}
+
+func TestAllocPortForwardCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &AllocPortForwardCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails without a port label
+	code = cmd.Run([]string{"foobar", "8080:"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "A port label is required")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	code = cmd.Run([]string{"-address=nope", "foobar", "8080:http"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error querying allocation")
+}
+
+func TestAllocPortForwardCommand_parsePortMapping(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		arg   string
+		local string
+		label string
+	}{
+		{arg: "8080:http", local: "127.0.0.1:8080", label: "http"},
+		{arg: "http", local: "127.0.0.1:0", label: "http"},
+		{arg: ":http", local: "127.0.0.1:0", label: "http"},
+		{arg: "0.0.0.0:8080:http", local: "0.0.0.0:8080", label: "http"},
+		{arg: "[::1]:8080:http", local: "[::1]:8080", label: "http"},
+		{arg: "8080:", local: "127.0.0.1:8080", label: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.arg, func(t *testing.T) {
+			local, label := parsePortMapping(tc.arg)
+			require.Equal(t, tc.local, local)
+			require.Equal(t, tc.label, label)
+		})
+	}
+}
+
+func TestAllocPortForwardCommand_validatePortExistsInAllocation(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := &api.Allocation{
+		ID: "eb17e557-443e-4c51-c049-5bba7f9850cf",
+		AllocatedResources: &api.AllocatedResources{
+			Shared: api.AllocatedSharedResources{
+				Ports: []api.PortMapping{{Label: "http"}, {Label: "admin"}},
+			},
+		},
+	}
+	require.NoError(t, validatePortExistsInAllocation("http", alloc))
+
+	err := validatePortExistsInAllocation("db", alloc)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Could not find port named: db")
+
+	alloc.AllocatedResources = nil
+	require.EqualError(t, validatePortExistsInAllocation("http", alloc), "Allocation eb17e557 has no ports")
+}
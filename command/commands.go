@@ -147,11 +147,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc cp": func() (cli.Command, error) {
+			return &AllocCpCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc exec": func() (cli.Command, error) {
 			return &AllocExecCommand{
 				Meta: meta,
 			}, nil
 		},
+		"alloc port-forward": func() (cli.Command, error) {
+			return &AllocPortForwardCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc signal": func() (cli.Command, error) {
 			return &AllocSignalCommand{
 				Meta: meta,
@@ -480,11 +490,21 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"node maintenance": func() (cli.Command, error) {
+			return &NodeMaintenanceCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node-status": func() (cli.Command, error) {
 			return &NodeStatusCommand{
 				Meta: meta,
 			}, nil
 		},
+		"node reserve": func() (cli.Command, error) {
+			return &NodeReserveCommand{
+				Meta: meta,
+			}, nil
+		},
 		"node status": func() (cli.Command, error) {
 			return &NodeStatusCommand{
 				Meta: meta,
@@ -572,6 +592,17 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"operator scheduler": func() (cli.Command, error) {
+			return &OperatorSchedulerCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator scheduler simulate": func() (cli.Command, error) {
+			return &OperatorSchedulerSimulateCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"operator snapshot": func() (cli.Command, error) {
 			return &OperatorSnapshotCommand{
 				Meta: meta,
@@ -26,6 +26,9 @@ Usage: nomad deployment promote [options] <deployment id>
   the job can be failed forward by submitting a new version or failed backwards by
   reverting to an older version using the "nomad job revert" command.
 
+  Task groups with a progressive rollout are held once the allocations of each
+  rollout step are healthy. Manual steps are promoted using the -step flag.
+
   When ACLs are enabled, this command requires a token with the 'submit-job'
   and 'read-job' capabilities for the deployment's namespace.
 
@@ -39,6 +42,11 @@ Promote Options:
     Group may be specified many times and is used to promote that particular
     group. If no specific groups are specified, all groups are promoted.
 
+  -step
+    Promote the current rollout step of the task groups whose canaries are
+    already promoted, moving on to the next step of their rollout. The
+    canaries of the other task groups are promoted.
+
   -detach
     Return immediately instead of entering monitor mode. After deployment
     resume, the evaluation ID will be printed to the screen, which can be used
@@ -58,6 +66,7 @@ func (c *DeploymentPromoteCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
 			"-group":   complete.PredictAnything,
+			"-step":    complete.PredictNothing,
 			"-detach":  complete.PredictNothing,
 			"-verbose": complete.PredictNothing,
 		})
@@ -81,13 +90,14 @@ func (c *DeploymentPromoteCommand) AutocompleteArgs() complete.Predictor {
 func (c *DeploymentPromoteCommand) Name() string { return "deployment promote" }
 
 func (c *DeploymentPromoteCommand) Run(args []string) int {
-	var detach, verbose bool
+	var detach, verbose, step bool
 	var groups []string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&detach, "detach", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&step, "step", false, "")
 	flags.Var((*flaghelper.StringFlag)(&groups), "group", "")
 
 	if err := flags.Parse(args); err != nil {
@@ -129,7 +139,9 @@ func (c *DeploymentPromoteCommand) Run(args []string) int {
 	}
 
 	var u *api.DeploymentUpdateResponse
-	if len(groups) == 0 {
+	if step {
+		u, _, err = client.Deployments().PromoteStep(deploy.ID, groups, nil)
+	} else if len(groups) == 0 {
 		u, _, err = client.Deployments().PromoteAll(deploy.ID, nil)
 	} else {
 		u, _, err = client.Deployments().PromoteGroups(deploy.ID, groups, nil)
@@ -593,7 +593,7 @@ func formatMultiregionDeployment(regions map[string]*api.Deployment, uuidLength
 
 func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	// Detect if we need to add these columns
-	var canaries, autorevert, progressDeadline bool
+	var canaries, autorevert, progressDeadline, steps bool
 	tgNames := make([]string, 0, len(d.TaskGroups))
 	for name, state := range d.TaskGroups {
 		tgNames = append(tgNames, name)
@@ -606,6 +606,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if state.ProgressDeadline != 0 {
 			progressDeadline = true
 		}
+		if len(state.RolloutSteps) != 0 {
+			steps = true
+		}
 	}
 
 	// Sort the task group names to get a reliable ordering
@@ -623,6 +626,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	if canaries {
 		rowString += "Canaries|"
 	}
+	if steps {
+		rowString += "Step|"
+	}
 	rowString += "Placed|Healthy|Unhealthy"
 	if progressDeadline {
 		rowString += "|Progress Deadline"
@@ -648,6 +654,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if canaries {
 			row += fmt.Sprintf("%d|", state.DesiredCanaries)
 		}
+		if steps {
+			row += fmt.Sprintf("%s|", formatRolloutStep(state))
+		}
 		row += fmt.Sprintf("%d|%d|%d", state.PlacedAllocs, state.HealthyAllocs, state.UnhealthyAllocs)
 		if progressDeadline {
 			if state.RequireProgressBy.IsZero() {
@@ -663,6 +672,25 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	return formatList(rows)
 }
 
+// formatRolloutStep returns the current rollout step of the deployment state
+// along with the percentage of allocations it updates.
+func formatRolloutStep(state *api.DeploymentState) string {
+	total := len(state.RolloutSteps)
+	switch {
+	case total == 0:
+		return "N/A"
+	case state.CurrentStep >= total:
+		return "Complete"
+	}
+
+	step := state.RolloutSteps[state.CurrentStep]
+	out := fmt.Sprintf("%d/%d (%d%%)", state.CurrentStep+1, total, *step.Percent)
+	if *step.Manual {
+		out += " manual"
+	}
+	return out
+}
+
 func hasAutoRevert(d *api.Deployment) bool {
 	taskGroups := d.TaskGroups
 	for _, state := range taskGroups {
@@ -44,6 +44,10 @@ Eval List Options:
   -status
     Only show evaluations with this status.
 
+  -queues
+    Show the share and backlog of the fair-share queues evaluations are
+    dequeued from instead of the evaluations. Requires operator:read.
+
   -json
     Output the evaluation in its JSON format.
 
@@ -67,6 +71,7 @@ func (c *EvalListCommand) AutocompleteFlags() complete.Flags {
 			"-filter":     complete.PredictAnything,
 			"-job":        complete.PredictAnything,
 			"-status":     complete.PredictAnything,
+			"-queues":     complete.PredictNothing,
 			"-per-page":   complete.PredictAnything,
 			"-page-token": complete.PredictAnything,
 		})
@@ -90,7 +95,7 @@ func (c *EvalListCommand) AutocompleteArgs() complete.Predictor {
 func (c *EvalListCommand) Name() string { return "eval list" }
 
 func (c *EvalListCommand) Run(args []string) int {
-	var monitor, verbose, json bool
+	var monitor, verbose, json, queues bool
 	var perPage int
 	var tmpl, pageToken, filter, filterJobID, filterStatus string
 
@@ -105,6 +110,7 @@ func (c *EvalListCommand) Run(args []string) int {
 	flags.StringVar(&filter, "filter", "", "")
 	flags.StringVar(&filterJobID, "job", "", "")
 	flags.StringVar(&filterStatus, "status", "", "")
+	flags.BoolVar(&queues, "queues", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -124,6 +130,10 @@ func (c *EvalListCommand) Run(args []string) int {
 		return 1
 	}
 
+	if queues {
+		return c.listQueues(client, json, tmpl)
+	}
+
 	opts := &api.QueryOptions{
 		Filter:    filter,
 		PerPage:   int32(perPage),
@@ -192,6 +202,56 @@ Results have been paginated. To get the next page run:
 	return 0
 }
 
+// listQueues outputs the share and backlog of the fair-share queues.
+func (c *EvalListCommand) listQueues(client *api.Client, json bool, tmpl string) int {
+	resp, _, err := client.Evaluations().Queues(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation queues: %v", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, resp.Queues)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if !resp.Enabled {
+		c.Ui.Output("Fair-share queueing is not enabled")
+		return 0
+	}
+	if len(resp.Queues) == 0 {
+		c.Ui.Output("No queues found")
+		return 0
+	}
+
+	c.Ui.Output(formatEvalQueues(resp.Queues))
+	return 0
+}
+
+// formatEvalQueues formats the fair-share queues as a table.
+func formatEvalQueues(queues []*api.EvalQueueStats) string {
+	out := make([]string, len(queues)+1)
+	out[0] = "Queue|Weight|Share|Ready|Unacked|Blocked|Dequeued"
+	for i, q := range queues {
+		out[i+1] = fmt.Sprintf("%s|%d|%.1f%%|%d|%d|%d|%d",
+			q.Name,
+			q.Weight,
+			q.Share*100,
+			q.Ready,
+			q.Unacked,
+			q.Blocked,
+			q.Dequeued,
+		)
+	}
+	return formatList(out)
+}
+
 // argsWithoutPageToken strips out of the -page-token argument and
 // returns the joined string
 func argsWithoutPageToken(osArgs []string) string {
@@ -4,8 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEvalList_ArgsWithoutPageToken(t *testing.T) {
@@ -60,3 +62,17 @@ func TestEvalList_ArgsWithoutPageToken(t *testing.T) {
 	}
 
 }
+
+func TestEvalList_FormatQueues(t *testing.T) {
+	ci.Parallel(t)
+
+	out := formatEvalQueues([]*api.EvalQueueStats{
+		{Name: "batch", Weight: 1, Share: 0.25, Ready: 100, Unacked: 2, Blocked: 10, Dequeued: 40},
+		{Name: "default", Weight: 3, Share: 0.75, Ready: 1, Unacked: 1, Dequeued: 120},
+	})
+	lines := strings.Split(out, "\n")
+	require.Len(t, lines, 3)
+	require.Regexp(t, `^Queue\s+Weight\s+Share\s+Ready\s+Unacked\s+Blocked\s+Dequeued$`, lines[0])
+	require.Regexp(t, `^batch\s+1\s+25.0%\s+100\s+2\s+10\s+40$`, lines[1])
+	require.Regexp(t, `^default\s+3\s+75.0%\s+1\s+1\s+0\s+120$`, lines[2])
+}
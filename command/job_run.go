@@ -345,6 +345,13 @@ func (c *JobRunCommand) Run(args []string) int {
 
 	evalID := resp.EvalID
 
+	// Jobs waiting on their dependencies are evaluated once they are satisfied
+	if evalID == "" && len(job.DependsOn) > 0 {
+		c.Ui.Output("Job registration successful")
+		c.Ui.Output("Job is waiting on its dependencies")
+		return 0
+	}
+
 	// Check if we should enter monitor mode
 	if detach || periodic || paramjob || multiregion {
 		c.Ui.Output("Job registration successful")
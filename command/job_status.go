@@ -354,6 +354,13 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 		return err
 	}
 
+	// Output the dependencies
+	if len(job.DependsOn) > 0 {
+		if err := c.outputJobDependencies(client, job, q); err != nil {
+			return err
+		}
+	}
+
 	// Determine latest evaluation with failures whose follow up hasn't
 	// completed, this is done while formatting
 	var latestFailedPlacement *api.Evaluation
@@ -575,6 +582,24 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 	return nil
 }
 
+// outputJobDependencies displays the status of the dependencies of the job
+func (c *JobStatusCommand) outputJobDependencies(client *api.Client, job *api.Job, q *api.QueryOptions) error {
+	deps, _, err := client.Jobs().Dependencies(*job.ID, q)
+	if err != nil {
+		return fmt.Errorf("Error querying job dependencies: %s", err)
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Dependencies[reset]"))
+	out := make([]string, len(deps)+1)
+	out[0] = "Job ID|Condition|Status|Satisfied|Description"
+	for i, d := range deps {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%t|%s",
+			d.JobID, d.Condition, d.Status, d.Satisfied, d.Description)
+	}
+	c.Ui.Output(formatList(out))
+	return nil
+}
+
 // outputReschedulingEvals displays eval IDs and time for any
 // delayed evaluations by task group
 func (c *JobStatusCommand) outputReschedulingEvals(client *api.Client, job *api.Job, allocListStubs []*api.AllocationListStub, uuidLength int) error {
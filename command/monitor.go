@@ -354,6 +354,11 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 		out += fmt.Sprintf("%s* Quota limit hit %q\n", prefix, dim)
 	}
 
+	// Print gang info
+	for _, tg := range metrics.GangFailures {
+		out += fmt.Sprintf("%s* Gang placement blocked by task group %q\n", prefix, tg)
+	}
+
 	// Print scores
 	if scores {
 		if len(metrics.ScoreMetaData) > 0 {
@@ -0,0 +1,243 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/posener/complete"
+)
+
+type NodeMaintenanceCommand struct {
+	Meta
+}
+
+func (c *NodeMaintenanceCommand) Help() string {
+	helpText := `
+Usage: nomad node maintenance [options] <node>
+
+  Schedules a maintenance window on a node, or on all the nodes of a node
+  class. The scheduler avoids placing service jobs on nodes with a maintenance
+  window. The node is drained at the start of the window and marked as
+  eligible for scheduling at its end.
+
+  The -self flag is useful to schedule maintenance of the local node, and the
+  -class flag to schedule maintenance of all the nodes of a node class. The
+  window of a node class also applies to the nodes that join it before the
+  window ends.
+
+  If ACLs are enabled, this option requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Node Maintenance Options:
+
+  -start
+    The start of the maintenance window, in RFC 3339 format. Defaults to now.
+
+  -duration
+    The duration of the maintenance window, such as "2h". Required unless
+    -delete is set.
+
+  -deadline
+    The deadline of the drain started at the start of the window, after which
+    the remaining allocations are forced off the node. Defaults to 1h.
+
+  -ignore-system
+    Leave system jobs running during the drain.
+
+  -description
+    The reason for the maintenance.
+
+  -delete
+    Delete the maintenance window with the given ID.
+
+  -class
+    Schedule maintenance of the given node class, including the nodes that
+    join it later.
+
+  -self
+    Schedule maintenance of the local node.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMaintenanceCommand) Synopsis() string {
+	return "Schedule maintenance windows on nodes"
+}
+
+func (c *NodeMaintenanceCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-start":         complete.PredictAnything,
+			"-duration":      complete.PredictAnything,
+			"-deadline":      complete.PredictAnything,
+			"-ignore-system": complete.PredictNothing,
+			"-description":   complete.PredictAnything,
+			"-delete":        complete.PredictAnything,
+			"-class":         complete.PredictAnything,
+			"-self":          complete.PredictNothing,
+		})
+}
+
+func (c *NodeMaintenanceCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Nodes]
+	})
+}
+
+func (c *NodeMaintenanceCommand) Name() string { return "node maintenance" }
+
+func (c *NodeMaintenanceCommand) Run(args []string) int {
+	var start, description, deleteID, class string
+	var duration, deadline time.Duration
+	var ignoreSystem, self bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&start, "start", "", "")
+	flags.DurationVar(&duration, "duration", 0, "")
+	flags.DurationVar(&deadline, "deadline", 0, "")
+	flags.BoolVar(&ignoreSystem, "ignore-system", false, "")
+	flags.StringVar(&description, "description", "", "")
+	flags.StringVar(&deleteID, "delete", "", "")
+	flags.StringVar(&class, "class", "", "")
+	flags.BoolVar(&self, "self", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Build the window unless deleting one
+	var window *api.NodeMaintenanceWindow
+	if deleteID == "" {
+		if duration <= 0 {
+			c.Ui.Error("A positive -duration must be specified")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+
+		startTime := time.Now()
+		if start != "" {
+			var err error
+			if startTime, err = time.Parse(time.RFC3339, start); err != nil {
+				c.Ui.Error(fmt.Sprintf("Error parsing -start: %s", err))
+				return 1
+			}
+		}
+
+		window = &api.NodeMaintenanceWindow{
+			ID:               uuid.Generate(),
+			Start:            startTime,
+			End:              startTime.Add(duration),
+			Deadline:         deadline,
+			IgnoreSystemJobs: ignoreSystem,
+			Description:      description,
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	req, code := nodeMaintenanceTarget(&c.Meta, c, client, flags.Args(), self, class)
+	if req == nil {
+		return code
+	}
+	if window != nil {
+		req.Windows = []*api.NodeMaintenanceWindow{window}
+	} else {
+		req.DeleteWindows = []string{deleteID}
+	}
+
+	resp, err := client.Nodes().UpdateMaintenance(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating node maintenance: %s", err))
+		return 1
+	}
+
+	if window != nil {
+		c.Ui.Output(fmt.Sprintf("Maintenance window %q scheduled on %d node(s) from %s to %s",
+			window.ID, len(resp.NodeIDs), formatTime(window.Start), formatTime(window.End)))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Maintenance window %q deleted from %d node(s)", deleteID, len(resp.NodeIDs)))
+	}
+	return 0
+}
+
+// nodeMaintenanceTarget returns a maintenance request for the node given by
+// the arguments, the local node if self is set, or the nodes of the class. If
+// the target is invalid an error is output and the request is nil, along
+// with the exit code.
+func nodeMaintenanceTarget(m *Meta, cmd NamedCommand, client *api.Client, args []string, self bool, class string) (*api.NodeMaintenanceRequest, int) {
+	if class != "" {
+		if self || len(args) != 0 {
+			m.Ui.Error("A node can't be specified along with -class")
+			m.Ui.Error(commandErrorText(cmd))
+			return nil, 1
+		}
+		return &api.NodeMaintenanceRequest{NodeClass: class}, 0
+	}
+
+	// Check that we got a node ID
+	if l := len(args); self && l != 0 || !self && l != 1 {
+		m.Ui.Error("Node ID must be specified if -self or -class isn't being used")
+		m.Ui.Error(commandErrorText(cmd))
+		return nil, 1
+	}
+
+	// If -self flag is set then determine the current node.
+	var nodeID string
+	if !self {
+		nodeID = args[0]
+	} else {
+		var err error
+		if nodeID, err = getLocalNodeID(client); err != nil {
+			m.Ui.Error(err.Error())
+			return nil, 1
+		}
+	}
+
+	// Check if node exists
+	if len(nodeID) == 1 {
+		m.Ui.Error("Identifier must contain at least two characters.")
+		return nil, 1
+	}
+
+	nodeID = sanitizeUUIDPrefix(nodeID)
+	nodes, _, err := client.Nodes().PrefixList(nodeID)
+	if err != nil {
+		m.Ui.Error(fmt.Sprintf("Error updating node maintenance: %s", err))
+		return nil, 1
+	}
+	// Return error if no nodes are found
+	if len(nodes) == 0 {
+		m.Ui.Error(fmt.Sprintf("No node(s) with prefix or id %q found", nodeID))
+		return nil, 1
+	}
+	if len(nodes) > 1 {
+		m.Ui.Error(fmt.Sprintf("Prefix matched multiple nodes\n\n%s",
+			formatNodeStubList(nodes, true)))
+		return nil, 1
+	}
+
+	return &api.NodeMaintenanceRequest{NodeID: nodes[0].ID}, 0
+}
@@ -0,0 +1,184 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/posener/complete"
+)
+
+type NodeReserveCommand struct {
+	Meta
+}
+
+func (c *NodeReserveCommand) Help() string {
+	helpText := `
+Usage: nomad node reserve [options] <node>
+
+  Reserves capacity on a node, or on all the nodes of a node class, for an
+  upcoming job. The reserved capacity can only be used by allocations of the
+  job until the reservation expires.
+
+  The -self flag is useful to reserve capacity on the local node, and the
+  -class flag to reserve capacity on all the nodes of a node class. The
+  reservation of a node class also applies to the nodes that join it before
+  the reservation expires.
+
+  If ACLs are enabled, this option requires a token with the 'node:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Node Reserve Options:
+
+  -job
+    The ID of the job the capacity is reserved for, in the namespace given by
+    the -namespace flag. Required unless -delete is set.
+
+  -cpu
+    The CPU to reserve, in MHz.
+
+  -memory
+    The memory to reserve, in MB.
+
+  -expires
+    The duration after which the reservation is released. Defaults to 1h.
+
+  -description
+    The reason for the reservation.
+
+  -delete
+    Delete the capacity reservation with the given ID.
+
+  -class
+    Reserve capacity on the nodes of the given node class, including the
+    nodes that join it later.
+
+  -self
+    Reserve capacity on the local node.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeReserveCommand) Synopsis() string {
+	return "Reserve node capacity for an upcoming job"
+}
+
+func (c *NodeReserveCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job":         complete.PredictAnything,
+			"-cpu":         complete.PredictAnything,
+			"-memory":      complete.PredictAnything,
+			"-expires":     complete.PredictAnything,
+			"-description": complete.PredictAnything,
+			"-delete":      complete.PredictAnything,
+			"-class":       complete.PredictAnything,
+			"-self":        complete.PredictNothing,
+		})
+}
+
+func (c *NodeReserveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Nodes, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Nodes]
+	})
+}
+
+func (c *NodeReserveCommand) Name() string { return "node reserve" }
+
+func (c *NodeReserveCommand) Run(args []string) int {
+	var jobID, description, deleteID, class string
+	var cpu, memory int64
+	var expires time.Duration
+	var self bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&jobID, "job", "", "")
+	flags.Int64Var(&cpu, "cpu", 0, "")
+	flags.Int64Var(&memory, "memory", 0, "")
+	flags.DurationVar(&expires, "expires", time.Hour, "")
+	flags.StringVar(&description, "description", "", "")
+	flags.StringVar(&deleteID, "delete", "", "")
+	flags.StringVar(&class, "class", "", "")
+	flags.BoolVar(&self, "self", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Build the reservation unless deleting one
+	var reservation *api.NodeCapacityReservation
+	if deleteID == "" {
+		if jobID == "" {
+			c.Ui.Error("A job must be specified with -job")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		if cpu <= 0 && memory <= 0 {
+			c.Ui.Error("A positive -cpu or -memory must be specified")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		if expires <= 0 {
+			c.Ui.Error("A positive -expires must be specified")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+
+		reservation = &api.NodeCapacityReservation{
+			ID:          uuid.Generate(),
+			JobID:       jobID,
+			CPU:         cpu,
+			MemoryMB:    memory,
+			Expires:     time.Now().Add(expires),
+			Description: description,
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	req, code := nodeMaintenanceTarget(&c.Meta, c, client, flags.Args(), self, class)
+	if req == nil {
+		return code
+	}
+	if reservation != nil {
+		req.Reservations = []*api.NodeCapacityReservation{reservation}
+	} else {
+		req.DeleteReservations = []string{deleteID}
+	}
+
+	resp, err := client.Nodes().UpdateMaintenance(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error updating capacity reservations: %s", err))
+		return 1
+	}
+
+	if reservation != nil {
+		c.Ui.Output(fmt.Sprintf("Capacity reservation %q for job %q created on %d node(s) until %s",
+			reservation.ID, jobID, len(resp.NodeIDs), formatTime(reservation.Expires)))
+	} else {
+		c.Ui.Output(fmt.Sprintf("Capacity reservation %q deleted from %d node(s)", deleteID, len(resp.NodeIDs)))
+	}
+	return 0
+}
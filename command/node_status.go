@@ -538,6 +538,9 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 	// Emit node events
 	c.outputNodeStatusEvents(node)
 
+	// Emit maintenance windows and capacity reservations
+	c.outputNodeMaintenance(node)
+
 	// Get list of running allocations on the node
 	allocatedResources := getAllocatedResources(client, runningAllocs, node)
 	c.Ui.Output(c.Colorize().Color("\n[bold]Allocated Resources[reset]"))
@@ -657,6 +660,33 @@ func (c *NodeStatusCommand) outputNodeNetworkInfo(node *api.Node) {
 	}
 }
 
+func (c *NodeStatusCommand) outputNodeMaintenance(node *api.Node) {
+	if len(node.MaintenanceWindows) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Maintenance Windows[reset]"))
+		output := make([]string, 0, len(node.MaintenanceWindows)+1)
+		output = append(output, "ID|Start|End|Deadline|Status|Description")
+		for _, w := range node.MaintenanceWindows {
+			output = append(output, fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+				limit(w.ID, c.length), formatTime(w.Start), formatTime(w.End),
+				w.Deadline, w.Status, w.Description))
+		}
+		c.Ui.Output(formatList(output))
+	}
+
+	if len(node.CapacityReservations) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Capacity Reservations[reset]"))
+		output := make([]string, 0, len(node.CapacityReservations)+1)
+		output = append(output, "ID|Namespace|Job ID|CPU|Memory|Expires|Description")
+		for _, r := range node.CapacityReservations {
+			output = append(output, fmt.Sprintf("%s|%s|%s|%d MHz|%s|%s|%s",
+				limit(r.ID, c.length), r.Namespace, r.JobID, r.CPU,
+				humanize.IBytes(uint64(r.MemoryMB*bytesPerMegabyte)),
+				formatTime(r.Expires), r.Description))
+		}
+		c.Ui.Output(formatList(output))
+	}
+}
+
 func (c *NodeStatusCommand) outputNodeCSIVolumeInfo(client *api.Client, node *api.Node, runningAllocs []*api.Allocation) {
 
 	// Duplicate nodeCSIVolumeNames to sort by name but also index volume names to ids
@@ -0,0 +1,541 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/command/agent"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/posener/complete"
+)
+
+type OperatorSchedulerSimulateCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *OperatorSchedulerSimulateCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler simulate [options] <snapshot> <path>
+
+  Runs the job in the jobfile through the scheduler against the state in a
+  snapshot, without contacting a Nomad agent. The simulation displays the
+  allocations the scheduler would place, the allocations it would preempt and
+  the reasons any allocations couldn't be placed.
+
+  The snapshot may either be a snapshot file saved with "nomad operator
+  snapshot save", or the data directory of a Nomad server in which case the
+  state is obtained by replaying its raft logs. The Nomad server locks access
+  to the data directory, so it cannot be used while the server is running.
+
+  The scheduler configuration in the snapshot is used unless overridden by the
+  options below, so the placements for different scheduler algorithms and
+  preemption settings can be compared by running the simulation once per
+  configuration.
+
+  The simulation uses the same schedulers as the Nomad servers, but doesn't run
+  the job through the servers' admission controllers or enforce quotas or
+  Sentinel policies.
+
+  This is a low-level debugging tool and not subject to Nomad's usual backward
+  compatibility guarantees.
+
+Simulate Options:
+
+  -scheduler-algorithm=<binpack|spread>
+    Overrides the scheduler algorithm used to place allocations.
+
+  -preempt-service-scheduler=<true|false>
+    Overrides whether preemption is enabled for service jobs.
+
+  -preempt-batch-scheduler=<true|false>
+    Overrides whether preemption is enabled for batch jobs.
+
+  -preempt-system-scheduler=<true|false>
+    Overrides whether preemption is enabled for system jobs.
+
+  -preempt-sysbatch-scheduler=<true|false>
+    Overrides whether preemption is enabled for sysbatch jobs.
+
+  -memory-oversubscription=<true|false>
+    Overrides whether memory oversubscription is enabled.
+
+  -json
+    Parses the job file as JSON. If the outer object has a Job field, such as
+    from "nomad job inspect" or "nomad run -output", the value of the field is
+    used as the job.
+
+  -hcl1
+    Parses the job file as HCLv1.
+
+  -hcl2-strict
+    Whether an error should be produced from the HCL2 parser where a variable
+    has been supplied which is not defined within the root variables. Defaults
+    to true.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+
+  -verbose
+    Display full information, including the scores of the nodes placements
+    were made on.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerSimulateCommand) Synopsis() string {
+	return "Simulate scheduling a job against a snapshot"
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-scheduler-algorithm": complete.PredictSet(
+			string(structs.SchedulerAlgorithmBinpack),
+			string(structs.SchedulerAlgorithmSpread),
+		),
+		"-preempt-service-scheduler":  complete.PredictSet("true", "false"),
+		"-preempt-batch-scheduler":    complete.PredictSet("true", "false"),
+		"-preempt-system-scheduler":   complete.PredictSet("true", "false"),
+		"-preempt-sysbatch-scheduler": complete.PredictSet("true", "false"),
+		"-memory-oversubscription":    complete.PredictSet("true", "false"),
+		"-json":                       complete.PredictNothing,
+		"-hcl1":                       complete.PredictNothing,
+		"-hcl2-strict":                complete.PredictNothing,
+		"-var":                        complete.PredictAnything,
+		"-var-file":                   complete.PredictFiles("*.var"),
+		"-verbose":                    complete.PredictNothing,
+	}
+}
+
+func (c *OperatorSchedulerSimulateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSchedulerSimulateCommand) Name() string { return "operator scheduler simulate" }
+
+func (c *OperatorSchedulerSimulateCommand) Run(args []string) int {
+	var algorithm string
+	var verbose bool
+	var preemptService, preemptBatch, preemptSystem, preemptSysBatch flaghelper.BoolValue
+	var memoryOversubscription flaghelper.BoolValue
+
+	flags := c.Meta.FlagSet(c.Name(), 0)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&algorithm, "scheduler-algorithm", "", "")
+	flags.Var(&preemptService, "preempt-service-scheduler", "")
+	flags.Var(&preemptBatch, "preempt-batch-scheduler", "")
+	flags.Var(&preemptSystem, "preempt-system-scheduler", "")
+	flags.Var(&preemptSysBatch, "preempt-sysbatch-scheduler", "")
+	flags.Var(&memoryOversubscription, "memory-oversubscription", "")
+	flags.BoolVar(&c.JobGetter.JSON, "json", false, "")
+	flags.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flags.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flags.Var(&c.JobGetter.Vars, "var", "")
+	flags.Var(&c.JobGetter.VarFiles, "var-file", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <snapshot> <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if err := c.JobGetter.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+		return 1
+	}
+
+	// Get the job and validate it as the servers would
+	apiJob, err := c.JobGetter.Get(args[1])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+	job := agent.ApiJobToStructJob(apiJob)
+	job.Canonicalize()
+	if err := job.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Job validation errors:\n%s", err))
+		return 1
+	}
+
+	store, err := c.loadState(args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	// Overlay the options onto the scheduler configuration of the snapshot
+	_, schedConfig, err := store.SchedulerConfig()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading scheduler configuration: %s", err))
+		return 1
+	}
+	if schedConfig == nil {
+		schedConfig = defaultSimulateSchedulerConfig()
+	} else {
+		cfg := *schedConfig
+		schedConfig = &cfg
+	}
+	if algorithm != "" {
+		schedConfig.SchedulerAlgorithm = structs.SchedulerAlgorithm(algorithm)
+	}
+	preemptService.Merge(&schedConfig.PreemptionConfig.ServiceSchedulerEnabled)
+	preemptBatch.Merge(&schedConfig.PreemptionConfig.BatchSchedulerEnabled)
+	preemptSystem.Merge(&schedConfig.PreemptionConfig.SystemSchedulerEnabled)
+	preemptSysBatch.Merge(&schedConfig.PreemptionConfig.SysBatchSchedulerEnabled)
+	memoryOversubscription.Merge(&schedConfig.MemoryOversubscriptionEnabled)
+	schedConfig.Canonicalize()
+	if err := schedConfig.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid scheduler configuration: %s", err))
+		return 1
+	}
+
+	sim, err := simulateScheduler(hclog.NewNullLogger(), store, job, schedConfig)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error simulating scheduler: %s", err))
+		return 1
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+	c.Ui.Output(c.Colorize().Color(formatSchedulerSimulation(sim, job, length, verbose)))
+	return 0
+}
+
+// loadState restores the state from a snapshot file, or from the raft logs of
+// a Nomad server's data directory.
+func (c *OperatorSchedulerSimulateCommand) loadState(path string) (*state.StateStore, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening snapshot: %s", err)
+	}
+
+	if fi.IsDir() {
+		raftPath, err := raftutil.FindRaftDir(path)
+		if err != nil {
+			return nil, err
+		}
+
+		fsm, err := raftutil.NewFSM(raftPath)
+		if err != nil {
+			return nil, err
+		}
+		defer fsm.Close()
+
+		if _, _, err := fsm.ApplyAll(); err != nil {
+			return nil, err
+		}
+		return fsm.State(), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error opening snapshot file: %s", err)
+	}
+	defer f.Close()
+
+	store, _, err := raftutil.RestoreFromArchive(f)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read archive file: %s", err)
+	}
+	return store, nil
+}
+
+// defaultSimulateSchedulerConfig returns the scheduler configuration used when
+// the snapshot doesn't contain one, which matches the servers' default.
+func defaultSimulateSchedulerConfig() *structs.SchedulerConfiguration {
+	return &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled: true,
+		},
+	}
+}
+
+// schedulerSimulation is the outcome of running a job through the scheduler
+// against an offline copy of the state.
+type schedulerSimulation struct {
+	// SchedulerConfig is the scheduler configuration the job was scheduled
+	// with
+	SchedulerConfig *structs.SchedulerConfiguration
+
+	// Placements are the allocations placed or updated by the scheduler
+	Placements []*structs.Allocation
+
+	// Stops are the allocations of the job stopped by the scheduler
+	Stops []*structs.Allocation
+
+	// Preemptions are the allocations of other jobs preempted to make room
+	// for the placements
+	Preemptions []*structs.Allocation
+
+	// FailedTGAllocs are the metrics of the task groups that couldn't be
+	// fully placed
+	FailedTGAllocs map[string]*structs.AllocMetric
+
+	// nodes maps the IDs of the nodes in the plan to their names
+	nodes map[string]string
+}
+
+// simulateScheduler registers the job in the state and processes its
+// registration eval with the scheduler for the job's type, using an in-memory
+// planner that applies the plans to the state. The state is modified so it
+// must not be shared.
+func simulateScheduler(logger hclog.Logger, store *state.StateStore, job *structs.Job, schedConfig *structs.SchedulerConfiguration) (*schedulerSimulation, error) {
+	index, err := store.LatestIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest index: %v", err)
+	}
+
+	index++
+	if err := store.SchedulerSetConfig(index, schedConfig); err != nil {
+		return nil, fmt.Errorf("failed to set scheduler configuration: %v", err)
+	}
+
+	index++
+	if err := store.UpsertJob(structs.IgnoreUnknownTypeFlag, index, job); err != nil {
+		return nil, fmt.Errorf("failed to register job: %v", err)
+	}
+
+	now := time.Now().UnixNano()
+	eval := &structs.Evaluation{
+		ID:             uuid.Generate(),
+		Namespace:      job.Namespace,
+		Priority:       job.Priority,
+		Type:           job.Type,
+		TriggeredBy:    structs.EvalTriggerJobRegister,
+		JobID:          job.ID,
+		JobModifyIndex: index,
+		Status:         structs.EvalStatusPending,
+		AnnotatePlan:   true,
+		CreateTime:     now,
+		ModifyTime:     now,
+	}
+
+	index++
+	if err := store.UpsertEvals(structs.IgnoreUnknownTypeFlag, index, []*structs.Evaluation{eval}); err != nil {
+		return nil, fmt.Errorf("failed to create evaluation: %v", err)
+	}
+
+	snap, err := store.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot state: %v", err)
+	}
+
+	// Create an in-memory Planner that returns no errors and stores the
+	// submitted plans and updated evals.
+	planner := &scheduler.Harness{
+		State: store,
+	}
+
+	sched, err := scheduler.NewScheduler(eval.Type, logger, nil, snap, planner)
+	if err != nil {
+		return nil, err
+	}
+	if err := sched.Process(eval); err != nil {
+		return nil, err
+	}
+
+	sim := &schedulerSimulation{
+		SchedulerConfig: schedConfig,
+		nodes:           make(map[string]string),
+	}
+	for _, plan := range planner.Plans {
+		for nodeID, allocs := range plan.NodeAllocation {
+			sim.addNode(snap, nodeID)
+			sim.Placements = append(sim.Placements, allocs...)
+		}
+		for nodeID, allocs := range plan.NodeUpdate {
+			sim.addNode(snap, nodeID)
+			sim.Stops = append(sim.Stops, allocs...)
+		}
+		for nodeID, allocs := range plan.NodePreemptions {
+			sim.addNode(snap, nodeID)
+
+			// The plan only holds a stub of the preempted allocations
+			for _, stub := range allocs {
+				alloc, err := snap.AllocByID(nil, stub.ID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to lookup allocation %q: %v", stub.ID, err)
+				}
+				if alloc == nil {
+					alloc = stub
+					alloc.NodeID = nodeID
+				}
+				sim.Preemptions = append(sim.Preemptions, alloc)
+			}
+		}
+	}
+	if n := len(planner.Evals); n > 0 {
+		sim.FailedTGAllocs = planner.Evals[n-1].FailedTGAllocs
+	}
+
+	sortAllocs := func(allocs []*structs.Allocation) {
+		sort.Slice(allocs, func(i, j int) bool {
+			if allocs[i].Name != allocs[j].Name {
+				return allocs[i].Name < allocs[j].Name
+			}
+			return allocs[i].ID < allocs[j].ID
+		})
+	}
+	sortAllocs(sim.Placements)
+	sortAllocs(sim.Stops)
+	sortAllocs(sim.Preemptions)
+	return sim, nil
+}
+
+// addNode records the name of the node.
+func (s *schedulerSimulation) addNode(snap *state.StateSnapshot, nodeID string) {
+	if _, ok := s.nodes[nodeID]; ok {
+		return
+	}
+	if node, err := snap.NodeByID(nil, nodeID); err == nil && node != nil {
+		s.nodes[nodeID] = node.Name
+	} else {
+		s.nodes[nodeID] = ""
+	}
+}
+
+// formatSchedulerSimulation produces a string displaying the outcome of the
+// simulation.
+func formatSchedulerSimulation(sim *schedulerSimulation, job *structs.Job, length int, verbose bool) string {
+	cfg := sim.SchedulerConfig
+	out := "[bold]Scheduler Configuration[reset]\n"
+	out += formatKV([]string{
+		fmt.Sprintf("Scheduler Algorithm|%s", cfg.EffectiveSchedulerAlgorithm()),
+		fmt.Sprintf("Preemption Service Scheduler|%t", cfg.PreemptionConfig.ServiceSchedulerEnabled),
+		fmt.Sprintf("Preemption Batch Scheduler|%t", cfg.PreemptionConfig.BatchSchedulerEnabled),
+		fmt.Sprintf("Preemption System Scheduler|%t", cfg.PreemptionConfig.SystemSchedulerEnabled),
+		fmt.Sprintf("Preemption SysBatch Scheduler|%t", cfg.PreemptionConfig.SysBatchSchedulerEnabled),
+		fmt.Sprintf("Memory Oversubscription|%t", cfg.MemoryOversubscriptionEnabled),
+	})
+
+	out += "\n\n[bold]Placements[reset]\n"
+	out += sim.formatAllocs(sim.Placements, length)
+
+	if verbose {
+		for _, alloc := range sim.Placements {
+			if alloc.Metrics == nil || len(alloc.Metrics.ScoreMetaData) == 0 {
+				continue
+			}
+			out += fmt.Sprintf("\n\n[bold]Placement Metrics for %q[reset]\n", alloc.Name)
+			out += strings.TrimSuffix(formatAllocMetrics(apiAllocMetric(alloc.Metrics), true, "  "), "\n")
+		}
+	}
+
+	if len(sim.Stops) > 0 {
+		out += "\n\n[bold]Stops[reset]\n"
+		out += sim.formatAllocs(sim.Stops, length)
+	}
+
+	if len(sim.Preemptions) > 0 {
+		out += "\n\n[bold][yellow]Preemptions[reset]\n"
+		preemptions := make([]string, 0, len(sim.Preemptions)+1)
+		preemptions = append(preemptions, "Alloc ID|Namespace|Job ID|Task Group|Node ID|Node Name")
+		for _, alloc := range sim.Preemptions {
+			preemptions = append(preemptions, fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+				limit(alloc.ID, length),
+				alloc.Namespace,
+				alloc.JobID,
+				alloc.TaskGroup,
+				limit(alloc.NodeID, length),
+				sim.nodes[alloc.NodeID]))
+		}
+		out += formatList(preemptions)
+	}
+
+	if len(sim.FailedTGAllocs) == 0 {
+		out += "\n\n[bold][green]All tasks successfully allocated.[reset]"
+		return out
+	}
+
+	if job.Type == structs.JobTypeSystem || job.Type == structs.JobTypeSysBatch {
+		out += "\n\n[bold][yellow]Failed to place allocations on all nodes.[reset]\n"
+	} else {
+		out += "\n\n[bold][yellow]Failed to place all allocations.[reset]\n"
+	}
+	failed := make(map[string]*api.AllocationMetric, len(sim.FailedTGAllocs))
+	for tg, metrics := range sim.FailedTGAllocs {
+		failed[tg] = apiAllocMetric(metrics)
+	}
+	for _, tg := range sortedTaskGroupFromMetrics(failed) {
+		metrics := failed[tg]
+
+		noun := "allocation"
+		if metrics.CoalescedFailures > 0 {
+			noun += "s"
+		}
+		out += fmt.Sprintf("%s[yellow]Task Group %q (failed to place %d %s):\n[reset]", strings.Repeat(" ", 2), tg, metrics.CoalescedFailures+1, noun)
+		out += fmt.Sprintf("[yellow]%s[reset]\n\n", formatAllocMetrics(metrics, false, strings.Repeat(" ", 4)))
+	}
+	return strings.TrimSuffix(out, "\n\n")
+}
+
+// formatAllocs produces a list of the allocations and the nodes they are on.
+func (s *schedulerSimulation) formatAllocs(allocs []*structs.Allocation, length int) string {
+	if len(allocs) == 0 {
+		return "No allocations"
+	}
+
+	out := make([]string, 0, len(allocs)+1)
+	out = append(out, "Alloc ID|Name|Node ID|Node Name")
+	for _, alloc := range allocs {
+		out = append(out, fmt.Sprintf("%s|%s|%s|%s",
+			limit(alloc.ID, length),
+			alloc.Name,
+			limit(alloc.NodeID, length),
+			s.nodes[alloc.NodeID]))
+	}
+	return formatList(out)
+}
+
+// apiAllocMetric converts the scheduler's allocation metrics to their API
+// representation so they can be displayed like those returned by the agent.
+func apiAllocMetric(m *structs.AllocMetric) *api.AllocationMetric {
+	if m == nil {
+		return nil
+	}
+
+	out := &api.AllocationMetric{
+		NodesEvaluated:     m.NodesEvaluated,
+		NodesFiltered:      m.NodesFiltered,
+		NodesAvailable:     m.NodesAvailable,
+		ClassFiltered:      m.ClassFiltered,
+		ConstraintFiltered: m.ConstraintFiltered,
+		NodesExhausted:     m.NodesExhausted,
+		ClassExhausted:     m.ClassExhausted,
+		DimensionExhausted: m.DimensionExhausted,
+		QuotaExhausted:     m.QuotaExhausted,
+		AllocationTime:     m.AllocationTime,
+		CoalescedFailures:  m.CoalescedFailures,
+		GangFailures:       m.GangFailures,
+	}
+	for _, meta := range m.ScoreMetaData {
+		out.ScoreMetaData = append(out.ScoreMetaData, &api.NodeScoreMeta{
+			NodeID:    meta.NodeID,
+			Scores:    meta.Scores,
+			NormScore: meta.NormScore,
+		})
+	}
+	return out
+}
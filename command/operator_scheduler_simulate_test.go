@@ -0,0 +1,145 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSchedulerSimulateCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorSchedulerSimulateCommand{}
+}
+
+func TestOperatorSchedulerSimulateCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &OperatorSchedulerSimulateCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails when the job file doesn't exist
+	code = cmd.Run([]string{"/unicorns/leprechauns.snap", "/unicorns/leprechauns.nomad"})
+	require.Equal(t, 1, code)
+	require.Contains(t, ui.ErrorWriter.String(), "Error getting job struct")
+}
+
+// testSimulateState returns a state store with two nodes, the first of which
+// runs a low priority allocation using the given resources.
+func testSimulateState(t *testing.T, cpu, memoryMB int64) (*state.StateStore, []*structs.Node, *structs.Allocation) {
+	store := state.TestStateStore(t)
+
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	for i, node := range nodes {
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	low := mock.Job()
+	low.Priority = 20
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, low))
+
+	alloc := mock.Alloc()
+	alloc.Job = low
+	alloc.JobID = low.ID
+	alloc.NodeID = nodes[0].ID
+	alloc.AllocatedResources.Tasks["web"].Cpu.CpuShares = cpu
+	alloc.AllocatedResources.Tasks["web"].Memory.MemoryMB = memoryMB
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 201, []*structs.Allocation{alloc}))
+
+	return store, nodes, alloc
+}
+
+func testSimulateJob() *structs.Job {
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Networks = nil
+	job.TaskGroups[0].Tasks[0].Resources.Networks = nil
+	job.TaskGroups[0].Tasks[0].Services = nil
+	return job
+}
+
+func TestOperatorSchedulerSimulate_Algorithm(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		algorithm structs.SchedulerAlgorithm
+		node      int
+	}{
+		{structs.SchedulerAlgorithmBinpack, 0},
+		{structs.SchedulerAlgorithmSpread, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.algorithm), func(t *testing.T) {
+			store, nodes, _ := testSimulateState(t, 1000, 1024)
+
+			// Binpacking places the allocation on the node already in use
+			// while spreading places it on the idle node
+			cfg := defaultSimulateSchedulerConfig()
+			cfg.SchedulerAlgorithm = tc.algorithm
+			sim, err := simulateScheduler(hclog.NewNullLogger(), store, testSimulateJob(), cfg)
+			require.NoError(t, err)
+
+			require.Empty(t, sim.FailedTGAllocs)
+			require.Empty(t, sim.Preemptions)
+			require.Len(t, sim.Placements, 1)
+			require.Equal(t, nodes[tc.node].ID, sim.Placements[0].NodeID)
+			require.Equal(t, nodes[tc.node].Name, sim.nodes[nodes[tc.node].ID])
+		})
+	}
+}
+
+func TestOperatorSchedulerSimulate_Preemption(t *testing.T) {
+	ci.Parallel(t)
+
+	// Only the first node is eligible for the job
+	newJob := func(nodes []*structs.Node) *structs.Job {
+		job := testSimulateJob()
+		job.Constraints = append(job.Constraints, &structs.Constraint{
+			LTarget: "${node.unique.id}",
+			RTarget: nodes[0].ID,
+			Operand: "=",
+		})
+		return job
+	}
+
+	// Without preemption the job can't be placed
+	store, nodes, _ := testSimulateState(t, 3500, 7000)
+	sim, err := simulateScheduler(hclog.NewNullLogger(), store, newJob(nodes), defaultSimulateSchedulerConfig())
+	require.NoError(t, err)
+	require.Empty(t, sim.Placements)
+	require.Empty(t, sim.Preemptions)
+	require.Contains(t, sim.FailedTGAllocs, "web")
+	require.Equal(t, 1, sim.FailedTGAllocs["web"].NodesExhausted)
+
+	out := formatSchedulerSimulation(sim, newJob(nodes), shortId, false)
+	require.Contains(t, out, "Failed to place all allocations")
+	require.Contains(t, out, `Task Group "web" (failed to place 1 allocation)`)
+
+	// With preemption the low priority allocation is preempted
+	store, nodes, alloc := testSimulateState(t, 3500, 7000)
+	cfg := defaultSimulateSchedulerConfig()
+	cfg.PreemptionConfig.ServiceSchedulerEnabled = true
+	sim, err = simulateScheduler(hclog.NewNullLogger(), store, newJob(nodes), cfg)
+	require.NoError(t, err)
+	require.Empty(t, sim.FailedTGAllocs)
+	require.Len(t, sim.Placements, 1)
+	require.Equal(t, nodes[0].ID, sim.Placements[0].NodeID)
+	require.Len(t, sim.Preemptions, 1)
+	require.Equal(t, alloc.ID, sim.Preemptions[0].ID)
+
+	out = formatSchedulerSimulation(sim, newJob(nodes), shortId, false)
+	require.Contains(t, out, "Preemptions")
+	require.Contains(t, out, alloc.ID[:shortId])
+	require.Contains(t, out, "All tasks successfully allocated.")
+}
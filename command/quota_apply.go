@@ -265,6 +265,7 @@ func parseQuotaResource(result *api.Resources, list *ast.ObjectList) error {
 		"cpu",
 		"memory",
 		"memory_max",
+		"device",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -275,9 +276,55 @@ func parseQuotaResource(result *api.Resources, list *ast.ObjectList) error {
 		return err
 	}
 
+	// Manually parse
+	delete(m, "device")
+
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
 	}
 
+	// Parse device limits
+	if o := listVal.Filter("device"); len(o.Items) > 0 {
+		if err := parseQuotaDevices(&result.Devices, o); err != nil {
+			return multierror.Prefix(err, "device ->")
+		}
+	}
+
+	return nil
+}
+
+// parseQuotaDevices parses the device limits of the region_limit resources
+func parseQuotaDevices(result *[]*api.RequestedDevice, list *ast.ObjectList) error {
+	for idx, o := range list.Items {
+		if l := len(o.Keys); l == 0 {
+			return multierror.Prefix(fmt.Errorf("missing device name"), fmt.Sprintf("device[%d] ->", idx))
+		} else if l > 1 {
+			return multierror.Prefix(fmt.Errorf("only one name may be specified"), fmt.Sprintf("device[%d] ->", idx))
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		// Check for invalid keys
+		valid := []string{
+			"count",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("device %q ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		device := &api.RequestedDevice{
+			Name: name,
+		}
+		if err := mapstructure.WeakDecode(m, device); err != nil {
+			return err
+		}
+
+		*result = append(*result, device)
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestQuotaApplyCommand_Implements(t *testing.T) {
@@ -35,3 +36,43 @@ func TestQuotaApplyCommand_Fails(t *testing.T) {
 	}
 	ui.ErrorWriter.Reset()
 }
+
+func TestQuotaApplyCommand_ParseDevices(t *testing.T) {
+	ci.Parallel(t)
+
+	spec, err := parseQuotaSpec([]byte(`
+name = "gpu"
+
+limit {
+  region = "global"
+  region_limit {
+    cpu = 2500
+
+    device "nvidia/gpu" {
+      count = 2
+    }
+  }
+}
+`))
+	require.NoError(t, err)
+	require.Len(t, spec.Limits, 1)
+
+	limit := spec.Limits[0].RegionLimit
+	require.Equal(t, 2500, *limit.CPU)
+	require.Len(t, limit.Devices, 1)
+	require.Equal(t, "nvidia/gpu", limit.Devices[0].Name)
+	require.Equal(t, uint64(2), *limit.Devices[0].Count)
+
+	// Devices must be named
+	_, err = parseQuotaSpec([]byte(`
+limit {
+  region = "global"
+  region_limit {
+    device {
+      count = 2
+    }
+  }
+}
+`))
+	require.Error(t, err)
+}
@@ -158,7 +158,7 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 	sort.Sort(api.QuotaLimitSort(spec.Limits))
 
 	limits := make([]string, len(spec.Limits)+1)
-	limits[0] = "Region|CPU Usage|Memory Usage|Memory Max Usage|Network Usage"
+	limits[0] = "Region|CPU Usage|Memory Usage|Memory Max Usage|Device Usage"
 	i := 0
 	for _, specLimit := range spec.Limits {
 		i++
@@ -174,18 +174,13 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 			return used, ok
 		}
 
-		specBits := 0
-		if len(specLimit.RegionLimit.Networks) == 1 {
-			specBits = *specLimit.RegionLimit.Networks[0].MBits
-		}
-
 		used, ok := lookupUsage()
 		if !ok {
 			cpu := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.CPU))
 			memory := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.MemoryMB))
 			memoryMax := fmt.Sprintf("- / %s", formatQuotaLimitInt(specLimit.RegionLimit.MemoryMaxMB))
-			net := fmt.Sprintf("- / %s", formatQuotaLimitInt(&specBits))
-			limits[i] = fmt.Sprintf("%s|%s|%s|%s|%s", specLimit.Region, cpu, memory, memoryMax, net)
+			devices := formatQuotaDevices(specLimit.RegionLimit.Devices, nil)
+			limits[i] = fmt.Sprintf("%s|%s|%s|%s|%s", specLimit.Region, cpu, memory, memoryMax, devices)
 			continue
 		}
 
@@ -199,17 +194,43 @@ func formatQuotaLimits(spec *api.QuotaSpec, usages map[string]*api.QuotaUsage) s
 		cpu := fmt.Sprintf("%d / %s", orZero(used.RegionLimit.CPU), formatQuotaLimitInt(specLimit.RegionLimit.CPU))
 		memory := fmt.Sprintf("%d / %s", orZero(used.RegionLimit.MemoryMB), formatQuotaLimitInt(specLimit.RegionLimit.MemoryMB))
 		memoryMax := fmt.Sprintf("%d / %s", orZero(used.RegionLimit.MemoryMaxMB), formatQuotaLimitInt(specLimit.RegionLimit.MemoryMaxMB))
-
-		net := fmt.Sprintf("- / %s", formatQuotaLimitInt(&specBits))
-		if len(used.RegionLimit.Networks) == 1 {
-			net = fmt.Sprintf("%d / %s", *used.RegionLimit.Networks[0].MBits, formatQuotaLimitInt(&specBits))
-		}
-		limits[i] = fmt.Sprintf("%s|%s|%s|%s|%s", specLimit.Region, cpu, memory, memoryMax, net)
+		devices := formatQuotaDevices(specLimit.RegionLimit.Devices, used.RegionLimit.Devices)
+		limits[i] = fmt.Sprintf("%s|%s|%s|%s|%s", specLimit.Region, cpu, memory, memoryMax, devices)
 	}
 
 	return formatList(limits)
 }
 
+// formatQuotaDevices returns the usage of each device limit for output. A nil
+// usage is displayed as unknown.
+func formatQuotaDevices(limits, used []*api.RequestedDevice) string {
+	if len(limits) == 0 {
+		return "-"
+	}
+
+	out := make([]string, 0, len(limits))
+	for _, limit := range limits {
+		usage := "-"
+		if used != nil {
+			var count uint64
+			for _, u := range used {
+				if u.Name == limit.Name && u.Count != nil {
+					count = *u.Count
+				}
+			}
+			usage = strconv.FormatUint(count, 10)
+		}
+
+		var limitCount *int
+		if limit.Count != nil {
+			c := int(*limit.Count)
+			limitCount = &c
+		}
+		out = append(out, fmt.Sprintf("%s: %s / %s", limit.Name, usage, formatQuotaLimitInt(limitCount)))
+	}
+	return strings.Join(out, ", ")
+}
+
 // formatQuotaLimitInt takes a integer resource value and returns the
 // appropriate string for output.
 func formatQuotaLimitInt(value *int) string {
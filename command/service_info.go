@@ -220,6 +220,16 @@ func (s *ServiceInfoCommand) formatVerboseOutput(jobIDs []string, jobServices ma
 			}
 			s.Ui.Output(formatKV(out))
 			s.Ui.Output("")
+
+			if len(service.Checks) > 0 {
+				checks := []string{"Name|Type|Status"}
+				for _, check := range service.Checks {
+					checks = append(checks, fmt.Sprintf("%s|%s|%s", check.Name, check.Type, check.Status))
+				}
+				s.Ui.Output(s.Colorize().Color("[bold]Checks[reset]"))
+				s.Ui.Output(formatList(checks))
+				s.Ui.Output("")
+			}
 		}
 	}
 }
@@ -6,7 +6,9 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -240,6 +242,12 @@ func (c *VolumeStatusCommand) formatBasic(vol *api.CSIVolume) (string, error) {
 		full = append(full, topo)
 	}
 
+	if len(vol.Stats) > 0 {
+		statsBanner := c.Colorize().Color("\n[bold]Usage[reset]")
+		full = append(full, statsBanner)
+		full = append(full, c.formatStats(vol))
+	}
+
 	// Format the allocs
 	banner := c.Colorize().Color("\n[bold]Allocations[reset]")
 	allocs := formatAllocListStubs(vol.Allocations, c.verbose, c.length)
@@ -249,6 +257,46 @@ func (c *VolumeStatusCommand) formatBasic(vol *api.CSIVolume) (string, error) {
 	return strings.Join(full, "\n"), nil
 }
 
+func (c *VolumeStatusCommand) formatStats(vol *api.CSIVolume) string {
+	nodeIDs := make([]string, 0, len(vol.Stats))
+	for nodeID := range vol.Stats {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Strings(nodeIDs)
+
+	rows := []string{"Node ID|Used|Available|Total|Used %|Condition|Updated"}
+	for _, nodeID := range nodeIDs {
+		stats := vol.Stats[nodeID]
+		if stats == nil {
+			continue
+		}
+
+		var usedPercent float64
+		if stats.TotalBytes > 0 {
+			usedPercent = float64(stats.UsedBytes) / float64(stats.TotalBytes) * 100
+		}
+
+		condition := "healthy"
+		if stats.Abnormal {
+			condition = "abnormal"
+			if stats.Message != "" {
+				condition = fmt.Sprintf("abnormal: %s", stats.Message)
+			}
+		}
+
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%.2f%%|%s|%s",
+			limit(nodeID, c.length),
+			humanize.IBytes(uint64(stats.UsedBytes)),
+			humanize.IBytes(uint64(stats.AvailableBytes)),
+			humanize.IBytes(uint64(stats.TotalBytes)),
+			usedPercent,
+			condition,
+			prettyTimeDiff(stats.UpdateTime, time.Now()),
+		))
+	}
+	return formatList(rows)
+}
+
 func (c *VolumeStatusCommand) formatTopology(vol *api.CSIVolume) string {
 	rows := []string{"Topology|Segments"}
 	for i, t := range vol.Topologies {
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+	dockerclient "github.com/docker/docker/client"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// checkpointID is the name of the checkpoint created in the checkpoint
+// directory of a task. The directory only ever holds the one checkpoint.
+const checkpointID = "nomad"
+
+var _ drivers.CheckpointDriver = (*Driver)(nil)
+
+// CheckpointTask dumps the state of the task's container to dir, stopping it.
+// Checkpoints are created with CRIU by the docker daemon, which must have
+// experimental features enabled.
+func (d *Driver) CheckpointTask(taskID string, dir string) error {
+	h, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	client, err := d.checkpointClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to docker daemon: %v", err)
+	}
+	defer client.Close()
+
+	opts := types.CheckpointCreateOptions{
+		CheckpointID:  checkpointID,
+		CheckpointDir: dir,
+		Exit:          true,
+	}
+	if err := client.CheckpointCreate(d.ctx, h.containerID, opts); err != nil {
+		return fmt.Errorf("failed to checkpoint container %s: %v", h.containerID, err)
+	}
+
+	return nil
+}
+
+// RestoreTask starts the task's container from the checkpoint in dir created
+// by CheckpointTask
+func (d *Driver) RestoreTask(cfg *drivers.TaskConfig, dir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	return d.startTask(cfg, dir)
+}
+
+// restoreContainer starts the passed container from the checkpoint in dir
+func (d *Driver) restoreContainer(containerID, dir string) error {
+	client, err := d.checkpointClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to docker daemon: %v", err)
+	}
+	defer client.Close()
+
+	opts := types.ContainerStartOptions{
+		CheckpointID:  checkpointID,
+		CheckpointDir: dir,
+	}
+	return client.ContainerStart(d.ctx, containerID, opts)
+}
+
+// checkpointClient creates a client of the docker daemon's API for managing
+// checkpoints, which isn't exposed by the *docker.Client. It connects to the
+// daemon the same way as newDockerClient.
+func (d *Driver) checkpointClient() (*dockerclient.Client, error) {
+	opts := []dockerclient.Opt{dockerclient.WithAPIVersionNegotiation()}
+
+	if dockerEndpoint := d.config.Endpoint; dockerEndpoint != "" {
+		opts = append(opts, dockerclient.WithHost(dockerEndpoint))
+
+		cert := d.config.TLS.Cert
+		key := d.config.TLS.Key
+		ca := d.config.TLS.CA
+		if cert+key+ca != "" {
+			opts = append(opts, dockerclient.WithTLSClientConfig(ca, cert, key))
+		}
+	} else {
+		opts = append(opts, dockerclient.FromEnv)
+	}
+
+	return dockerclient.NewClientWithOpts(opts...)
+}
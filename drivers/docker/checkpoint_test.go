@@ -0,0 +1,80 @@
+//go:build linux
+
+package docker
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	tu "github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDockerDriver_CheckpointRestore(t *testing.T) {
+	ci.Parallel(t)
+
+	client := newTestDockerClient(t)
+	info, err := client.Info()
+	require.NoError(t, err)
+	if !info.ExperimentalBuild {
+		t.Skip("docker daemon does not have experimental features enabled")
+	}
+	if _, err := exec.LookPath("criu"); err != nil {
+		t.Skip("criu not found in PATH")
+	}
+
+	d := dockerDriverHarness(t, nil)
+	driver := d.Impl().(*Driver)
+	newTask := func() *drivers.TaskConfig {
+		taskCfg := newTaskConfig("", busyboxLongRunningCmd)
+		task := &drivers.TaskConfig{
+			ID:        uuid.Generate(),
+			Name:      "checkpoint",
+			AllocID:   uuid.Generate(),
+			Resources: basicResources,
+		}
+		require.NoError(t, task.EncodeConcreteDriverConfig(&taskCfg))
+
+		cleanup := d.MkAllocDir(task, true)
+		t.Cleanup(cleanup)
+		copyImage(t, task.TaskDir(), "busybox.tar")
+		return task
+	}
+
+	task := newTask()
+	_, _, err = d.StartTask(task)
+	require.NoError(t, err)
+	defer d.DestroyTask(task.ID, true)
+
+	waitCh, err := d.WaitTask(context.Background(), task.ID)
+	require.NoError(t, err)
+
+	// Checkpointing the task stops it
+	dir := filepath.Join(t.TempDir(), "checkpoint")
+	require.NoError(t, driver.CheckpointTask(task.ID, dir))
+	select {
+	case <-waitCh:
+	case <-time.After(time.Duration(tu.TestMultiplier()*5) * time.Second):
+		t.Fatalf("checkpointed task should have stopped")
+	}
+
+	// The checkpoint can be restored by another task
+	restored := newTask()
+	_, _, err = driver.RestoreTask(restored, dir)
+	require.NoError(t, err)
+	defer d.DestroyTask(restored.ID, true)
+
+	waitCh, err = d.WaitTask(context.Background(), restored.ID)
+	require.NoError(t, err)
+	select {
+	case <-waitCh:
+		t.Fatalf("restored task should be running")
+	case <-time.After(time.Duration(tu.TestMultiplier()*1) * time.Second):
+	}
+}
@@ -413,6 +413,7 @@ var (
 		},
 		MustInitiateNetwork: true,
 		MountConfigs:        drivers.MountConfigSupportAll,
+		Resize:              true,
 	}
 )
 
@@ -766,9 +767,12 @@ func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
 }
 
 // Capabilities is returned by the Capabilities RPC and indicates what optional
-// features this driver supports.
+// features this driver supports. Checkpointing is only supported once the
+// docker daemon was fingerprinted with experimental features enabled.
 func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
-	return driverCapabilities, nil
+	caps := *driverCapabilities
+	caps.Checkpoint = d.checkpointSupported()
+	return &caps, nil
 }
 
 var _ drivers.InternalCapabilitiesDriver = (*Driver)(nil)
@@ -123,6 +123,11 @@ type Driver struct {
 	detected     bool
 	detectedLock sync.RWMutex
 
+	// checkpoint indicates whether the docker daemon has the experimental
+	// features enabled, which checkpointing containers requires
+	checkpoint     bool
+	checkpointLock sync.RWMutex
+
 	danglingReconciler *containerReconciler
 	cpusetFixer        CpusetFixer
 }
@@ -244,6 +249,12 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 }
 
 func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	return d.startTask(cfg, "")
+}
+
+// startTask launches the task, or restores it from the checkpoint in
+// restoreDir if set
+func (d *Driver) startTask(cfg *drivers.TaskConfig, restoreDir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
 	if _, ok := d.tasks.Get(cfg.ID); ok {
 		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
 	}
@@ -306,8 +317,13 @@ CREATE:
 	// since we don't create containers which are already present on the host
 	// and are running
 	if !container.State.Running {
-		// Start the container
-		if err := d.startContainer(container); err != nil {
+		// Start the container, or restore it from the checkpoint
+		if restoreDir != "" {
+			err = d.restoreContainer(container.ID, restoreDir)
+		} else {
+			err = d.startContainer(container)
+		}
+		if err != nil {
 			d.logger.Error("failed to start container", "container_id", container.ID, "error", err)
 			client.RemoveContainer(docker.RemoveContainerOptions{
 				ID:    container.ID,
@@ -778,6 +794,26 @@ func memoryLimits(driverHardLimitMB int64, taskMemory drivers.MemoryResources) (
 	return hard * 1024 * 1024, softBytes
 }
 
+// setDiskIOLimits sets the disk IO limits of the task on the block device of
+// the disk holding the allocation directory, if it was fingerprinted.
+func setDiskIOLimits(hostConfig *docker.HostConfig, diskIO nstructs.AllocatedDiskIOResources) {
+	if diskIO.Device == "" {
+		return
+	}
+
+	path := "/dev/block/" + diskIO.Device
+	limit := func(rate int64) []docker.BlockLimit {
+		if rate <= 0 {
+			return nil
+		}
+		return []docker.BlockLimit{{Path: path, Rate: rate}}
+	}
+	hostConfig.BlkioDeviceReadIOps = limit(diskIO.ReadIOPS)
+	hostConfig.BlkioDeviceWriteIOps = limit(diskIO.WriteIOPS)
+	hostConfig.BlkioDeviceReadBps = limit(diskIO.ReadMBps * 1024 * 1024)
+	hostConfig.BlkioDeviceWriteBps = limit(diskIO.WriteMBps * 1024 * 1024)
+}
+
 func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *TaskConfig,
 	imageID string) (docker.CreateContainerOptions, error) {
 
@@ -896,6 +932,11 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 		hostConfig.CPUQuota = int64(task.Resources.LinuxResources.PercentTicks*float64(driverConfig.CPUCFSPeriod)) * int64(numCores)
 	}
 
+	// Limit disk IO to the task's reservation
+	if runtime.GOOS != "windows" {
+		setDiskIOLimits(hostConfig, task.Resources.NomadResources.DiskIO)
+	}
+
 	// Windows does not support MemorySwap/MemorySwappiness #2193
 	if runtime.GOOS == "windows" {
 		hostConfig.MemorySwap = 0
@@ -1538,6 +1579,57 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 	return h.Signal(context.Background(), sig)
 }
 
+var _ drivers.ResizeTaskDriver = (*Driver)(nil)
+
+// ResizeTask updates the memory and CPU limits of the task's container
+// without restarting it. The limits are computed the same way as when the
+// container is created.
+func (d *Driver) ResizeTask(taskID string, resources *drivers.Resources) error {
+	h, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if resources == nil || resources.NomadResources == nil || resources.LinuxResources == nil {
+		return fmt.Errorf("task resources are missing")
+	}
+
+	var driverConfig TaskConfig
+	if err := h.task.DecodeDriverConfig(&driverConfig); err != nil {
+		return fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	memory, memoryReservation := memoryLimits(driverConfig.MemoryHardLimit, resources.NomadResources.Memory)
+	opts := docker.UpdateContainerOptions{
+		Memory:            int(memory),
+		MemoryReservation: int(memoryReservation),
+		CPUShares:         int(resources.LinuxResources.CPUShares),
+	}
+
+	// Windows does not support MemorySwap #2193
+	if runtime.GOOS != "windows" {
+		opts.MemorySwap = int(memory)
+	}
+
+	if driverConfig.CPUHardLimit {
+		period := driverConfig.CPUCFSPeriod
+		if period == 0 {
+			period = resources.LinuxResources.CPUPeriod
+		}
+		opts.CPUPeriod = int(period)
+		opts.CPUQuota = int(int64(resources.LinuxResources.PercentTicks*float64(period)) * int64(runtime.NumCPU()))
+	}
+
+	if err := h.client.UpdateContainer(h.containerID, opts); err != nil {
+		return fmt.Errorf("failed to update container resources: %v", err)
+	}
+
+	h.logger.Debug("updated container resources", "memory", opts.Memory,
+		"memory_reservation", opts.MemoryReservation, "cpu_shares", opts.CPUShares,
+		"cpu_quota", opts.CPUQuota, "cpu_period", opts.CPUPeriod)
+	return nil
+}
+
 func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
 	h, ok := d.tasks.Get(taskID)
 	if !ok {
@@ -2844,6 +2844,25 @@ func TestDockerDriver_memoryLimits(t *testing.T) {
 	}
 }
 
+func TestDockerDriver_setDiskIOLimits(t *testing.T) {
+	ci.Parallel(t)
+
+	// Limits require the disk device
+	hostConfig := &docker.HostConfig{}
+	setDiskIOLimits(hostConfig, structs.AllocatedDiskIOResources{ReadIOPS: 100})
+	require.Nil(t, hostConfig.BlkioDeviceReadIOps)
+
+	setDiskIOLimits(hostConfig, structs.AllocatedDiskIOResources{
+		Device:    "8:0",
+		ReadIOPS:  100,
+		WriteMBps: 10,
+	})
+	require.Equal(t, []docker.BlockLimit{{Path: "/dev/block/8:0", Rate: 100}}, hostConfig.BlkioDeviceReadIOps)
+	require.Nil(t, hostConfig.BlkioDeviceWriteIOps)
+	require.Nil(t, hostConfig.BlkioDeviceReadBps)
+	require.Equal(t, []docker.BlockLimit{{Path: "/dev/block/8:0", Rate: 10 * 1024 * 1024}}, hostConfig.BlkioDeviceWriteBps)
+}
+
 func TestDockerDriver_parseSignal(t *testing.T) {
 	ci.Parallel(t)
 
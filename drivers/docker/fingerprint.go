@@ -37,6 +37,20 @@ func (d *Driver) setDetected(detected bool) {
 	d.detected = detected
 }
 
+func (d *Driver) checkpointSupported() bool {
+	d.checkpointLock.RLock()
+	defer d.checkpointLock.RUnlock()
+
+	return d.checkpoint
+}
+
+func (d *Driver) setCheckpointSupported(supported bool) {
+	d.checkpointLock.Lock()
+	defer d.checkpointLock.Unlock()
+
+	d.checkpoint = supported
+}
+
 // setFingerprintSuccess marks the driver as having fingerprinted successfully
 func (d *Driver) setFingerprintSuccess() {
 	d.fingerprintLock.Lock()
@@ -156,6 +170,9 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	if dockerInfo, err := client.Info(); err != nil {
 		d.logger.Warn("failed to get Docker system info", "error", err)
 	} else {
+		// Containers are checkpointed with the experimental checkpoint API
+		d.setCheckpointSupported(dockerInfo.ExperimentalBuild)
+
 		runtimeNames := make([]string, 0, len(dockerInfo.Runtimes))
 		for name := range dockerInfo.Runtimes {
 			if d.config.GPURuntimeName == name {
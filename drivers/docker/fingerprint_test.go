@@ -27,3 +27,30 @@ func TestDockerDriver_FingerprintHealth(t *testing.T) {
 	fp := d.buildFingerprint()
 	require.Equal(t, drivers.HealthStateHealthy, fp.Health)
 }
+
+// TestDockerDriver_FingerprintCheckpoint asserts that checkpointing is only
+// advertised if the docker daemon has experimental features enabled.
+func TestDockerDriver_FingerprintCheckpoint(t *testing.T) {
+	ci.Parallel(t)
+	testutil.DockerCompatible(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewDockerDriver(ctx, testlog.HCLogger(t)).(*Driver)
+
+	// Checkpointing isn't supported until the daemon is fingerprinted
+	caps, err := d.Capabilities()
+	require.NoError(t, err)
+	require.False(t, caps.Checkpoint)
+
+	fp := d.buildFingerprint()
+	require.Equal(t, drivers.HealthStateHealthy, fp.Health)
+
+	info, err := newTestDockerClient(t).Info()
+	require.NoError(t, err)
+
+	caps, err = d.Capabilities()
+	require.NoError(t, err)
+	require.Equal(t, info.ExperimentalBuild, caps.Checkpoint)
+}
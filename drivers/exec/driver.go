@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	osexec "os/exec"
 	"path/filepath"
 	"runtime"
 	"sync"
@@ -102,6 +103,7 @@ var (
 			drivers.NetIsolationModeGroup,
 		},
 		MountConfigs: drivers.MountConfigSupportAll,
+		Resize:       true,
 	}
 )
 
@@ -132,6 +134,12 @@ type Driver struct {
 	// whether it has been successful
 	fingerprintSuccess *bool
 	fingerprintLock    sync.Mutex
+
+	// checkpoint indicates whether tasks can be checkpointed with the CRIU
+	// binary at criuPath
+	checkpoint     bool
+	criuPath       string
+	checkpointLock sync.Mutex
 }
 
 // Config is the driver configuration set by the SetConfig RPC call
@@ -298,9 +306,15 @@ func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
 }
 
 // Capabilities is returned by the Capabilities RPC and indicates what
-// optional features this driver supports
+// optional features this driver supports. Checkpointing is only supported once
+// CRIU was fingerprinted.
 func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
-	return driverCapabilities, nil
+	d.checkpointLock.Lock()
+	defer d.checkpointLock.Unlock()
+
+	caps := *driverCapabilities
+	caps.Checkpoint = d.checkpoint
+	return &caps, nil
 }
 
 func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
@@ -365,11 +379,41 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 		return fp
 	}
 
+	d.fingerprintCheckpoint()
+
 	fp.Attributes["driver.exec"] = pstructs.NewBoolAttribute(true)
 	d.setFingerprintSuccess()
 	return fp
 }
 
+// fingerprintCheckpoint detects whether tasks can be checkpointed, which
+// requires criu to be in the PATH and its checks of the kernel to pass. The
+// checks are only run again if the path of criu changes.
+func (d *Driver) fingerprintCheckpoint() {
+	path, _ := osexec.LookPath("criu")
+
+	d.checkpointLock.Lock()
+	checked := path == d.criuPath
+	d.checkpointLock.Unlock()
+	if checked {
+		return
+	}
+
+	supported := false
+	if path != "" {
+		if out, err := osexec.Command(path, "check").CombinedOutput(); err != nil {
+			d.logger.Debug("criu check failed, tasks can't be checkpointed", "error", err, "output", string(out))
+		} else {
+			supported = true
+		}
+	}
+
+	d.checkpointLock.Lock()
+	d.checkpoint = supported
+	d.criuPath = path
+	d.checkpointLock.Unlock()
+}
+
 func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 	if handle == nil {
 		return fmt.Errorf("handle cannot be nil")
@@ -423,6 +467,20 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 }
 
 func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	return d.startTask(cfg, "")
+}
+
+var _ drivers.CheckpointDriver = (*Driver)(nil)
+
+// RestoreTask starts the task from the checkpoint in dir created by
+// CheckpointTask
+func (d *Driver) RestoreTask(cfg *drivers.TaskConfig, dir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	return d.startTask(cfg, dir)
+}
+
+// startTask launches the task, or restores it from the checkpoint in
+// restoreDir if set
+func (d *Driver) startTask(cfg *drivers.TaskConfig, restoreDir string) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
 	if _, ok := d.tasks.Get(cfg.ID); ok {
 		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
 	}
@@ -494,7 +552,12 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		Capabilities:     caps,
 	}
 
-	ps, err := exec.Launch(execCmd)
+	var ps *executor.ProcessState
+	if restoreDir != "" {
+		ps, err = exec.Restore(execCmd, restoreDir)
+	} else {
+		ps, err = exec.Launch(execCmd)
+	}
 	if err != nil {
 		pluginClient.Kill()
 		return nil, nil, fmt.Errorf("failed to launch command with executor: %v", err)
@@ -625,6 +688,40 @@ func (d *Driver) DestroyTask(taskID string, force bool) error {
 	return nil
 }
 
+// CheckpointTask dumps the state of the task's processes to dir, stopping
+// them. Checkpoints are created using CRIU, which must be installed.
+func (d *Driver) CheckpointTask(taskID string, dir string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if err := handle.exec.Checkpoint(dir); err != nil {
+		return fmt.Errorf("executor Checkpoint failed: %v", err)
+	}
+
+	return nil
+}
+
+var _ drivers.ResizeTaskDriver = (*Driver)(nil)
+
+// ResizeTask updates the resources enforced for the task's cgroup without restarting it.
+func (d *Driver) ResizeTask(taskID string, resources *drivers.Resources) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if err := handle.exec.UpdateResources(resources); err != nil {
+		return fmt.Errorf("executor UpdateResources failed: %v", err)
+	}
+
+	handle.stateLock.Lock()
+	handle.taskConfig.Resources = resources
+	handle.stateLock.Unlock()
+	return nil
+}
+
 func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
 	handle, ok := d.tasks.Get(taskID)
 	if !ok {
@@ -115,6 +115,47 @@ func TestExecDriver_Fingerprint(t *testing.T) {
 	}
 }
 
+func TestExecDriver_FingerprintCheckpoint(t *testing.T) {
+	// Not parallel, as the PATH is modified
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := NewExecDriver(ctx, testlog.HCLogger(t)).(*Driver)
+	checkpoint := func() bool {
+		caps, err := d.Capabilities()
+		require.NoError(err)
+		return caps.Checkpoint
+	}
+
+	// Create fake criu binaries whose checks fail and pass
+	criu := func(exitCode int) string {
+		dir := t.TempDir()
+		script := fmt.Sprintf("#!/bin/sh\nexit %d\n", exitCode)
+		require.NoError(os.WriteFile(filepath.Join(dir, "criu"), []byte(script), 0755))
+		return dir
+	}
+	failing, passing := criu(1), criu(0)
+
+	// Checkpointing isn't supported without criu
+	t.Setenv("PATH", t.TempDir())
+	d.fingerprintCheckpoint()
+	require.False(checkpoint())
+
+	// Nor if its checks fail
+	t.Setenv("PATH", failing)
+	d.fingerprintCheckpoint()
+	require.False(checkpoint())
+
+	t.Setenv("PATH", passing)
+	d.fingerprintCheckpoint()
+	require.True(checkpoint())
+
+	// The default capabilities are unchanged
+	require.False(driverCapabilities.Checkpoint)
+}
+
 func TestExecDriver_StartWait(t *testing.T) {
 	ci.Parallel(t)
 	ctestutils.ExecCompatible(t)
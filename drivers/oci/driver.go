@@ -0,0 +1,843 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/drivers/shared/capabilities"
+	"github.com/hashicorp/nomad/drivers/shared/eventer"
+	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/hashicorp/nomad/drivers/shared/hostnames"
+	"github.com/hashicorp/nomad/drivers/shared/resolvconf"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/drivers/utils"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "oci"
+
+	// fingerprintPeriod is the interval at which the driver will send fingerprint responses
+	fingerprintPeriod = 30 * time.Second
+
+	// taskHandleVersion is the version of task handle which this driver sets
+	// and understands how to decode driver state
+	taskHandleVersion = 1
+
+	// bundleDirName is the name of the directory within the task directory
+	// the task's OCI bundle is written to
+	bundleDirName = "oci"
+
+	// runtimeTimeout is the time allowed for runtime commands which manage
+	// containers, such as kill and delete
+	runtimeTimeout = 30 * time.Second
+)
+
+var (
+	// PluginID is the oci plugin metadata registered in the plugin
+	// catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeDriver,
+	}
+
+	// PluginConfig is the oci driver factory function registered in the
+	// plugin catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Config:  map[string]interface{}{},
+		Factory: func(ctx context.Context, l hclog.Logger) interface{} { return NewOCIDriver(ctx, l) },
+	}
+
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeDriver,
+		PluginApiVersions: []string{drivers.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"runtime": hclspec.NewDefault(
+			hclspec.NewAttr("runtime", "string", false),
+			hclspec.NewLiteral(`"runc"`),
+		),
+		"runtime_root": hclspec.NewAttr("runtime_root", "string", false),
+		"allow_caps": hclspec.NewDefault(
+			hclspec.NewAttr("allow_caps", "list(string)", false),
+			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
+		),
+	})
+
+	// taskConfigSpec is the hcl specification for the driver config section of
+	// a task within a job. It is returned in the TaskConfigSchema RPC
+	taskConfigSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"image":           hclspec.NewAttr("image", "string", true),
+		"image_ref":       hclspec.NewAttr("image_ref", "string", false),
+		"entrypoint":      hclspec.NewAttr("entrypoint", "list(string)", false),
+		"command":         hclspec.NewAttr("command", "string", false),
+		"args":            hclspec.NewAttr("args", "list(string)", false),
+		"work_dir":        hclspec.NewAttr("work_dir", "string", false),
+		"readonly_rootfs": hclspec.NewAttr("readonly_rootfs", "bool", false),
+		"cap_add":         hclspec.NewAttr("cap_add", "list(string)", false),
+		"cap_drop":        hclspec.NewAttr("cap_drop", "list(string)", false),
+		"extra_hosts":     hclspec.NewAttr("extra_hosts", "list(string)", false),
+	})
+
+	// driverCapabilities represents the RPC response for what features are
+	// implemented by the oci task driver
+	driverCapabilities = &drivers.Capabilities{
+		SendSignals: true,
+		Exec:        true,
+		FSIsolation: drivers.FSIsolationImage,
+		NetIsolationModes: []drivers.NetIsolationMode{
+			drivers.NetIsolationModeHost,
+			drivers.NetIsolationModeGroup,
+		},
+		MustInitiateNetwork: true,
+		MountConfigs:        drivers.MountConfigSupportAll,
+	}
+)
+
+// Driver runs tasks as containers using an OCI runtime such as runc or crun.
+// Images are read from OCI image layouts, typically fetched as artifacts, and
+// unpacked by the driver into a bundle the runtime runs the container from.
+type Driver struct {
+	// eventer is used to handle multiplexing of TaskEvents calls such that an
+	// event can be broadcast to all callers
+	eventer *eventer.Eventer
+
+	// config is the driver configuration set by the SetConfig RPC
+	config Config
+
+	// nomadConfig is the client config from nomad
+	nomadConfig *base.ClientDriverConfig
+
+	// tasks is the in memory datastore mapping taskIDs to driverHandles
+	tasks *taskStore
+
+	// ctx is the context for the driver. It is passed to other subsystems to
+	// coordinate shutdown
+	ctx context.Context
+
+	// logger will log to the Nomad agent
+	logger hclog.Logger
+
+	// A tri-state boolean to know if the fingerprinting has happened and
+	// whether it has been successful
+	fingerprintSuccess *bool
+	fingerprintLock    sync.Mutex
+}
+
+// Config is the driver configuration set by the SetConfig RPC call
+type Config struct {
+	// Runtime is the name or path of the OCI runtime binary used to run
+	// containers
+	Runtime string `codec:"runtime"`
+
+	// RuntimeRoot is the directory the runtime stores container state in. If
+	// unset the runtime's default is used.
+	RuntimeRoot string `codec:"runtime_root"`
+
+	// AllowCaps configures which Linux Capabilities are enabled for tasks
+	// running on this node.
+	AllowCaps []string `codec:"allow_caps"`
+}
+
+func (c *Config) validate() error {
+	if c.Runtime == "" {
+		return fmt.Errorf("runtime must be set")
+	}
+
+	badCaps := capabilities.Supported().Difference(capabilities.New(c.AllowCaps))
+	if !badCaps.Empty() {
+		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
+	}
+
+	return nil
+}
+
+// TaskConfig is the driver configuration of a task within a job
+type TaskConfig struct {
+	// Image is the path of the OCI image layout directory containing the
+	// task's image. Relative paths are relative to the task directory.
+	Image string `codec:"image"`
+
+	// ImageRef is the reference name of the image within the image layout.
+	// It may be omitted if the image layout contains a single image.
+	ImageRef string `codec:"image_ref"`
+
+	// Entrypoint overrides the image's entrypoint.
+	Entrypoint []string `codec:"entrypoint"`
+
+	// Command overrides the image's command.
+	Command string `codec:"command"`
+
+	// Args are passed along to Command, or the image's entrypoint if Command
+	// is not set.
+	Args []string `codec:"args"`
+
+	// WorkDir overrides the image's working directory.
+	WorkDir string `codec:"work_dir"`
+
+	// ReadonlyRootfs mounts the container's root filesystem read-only.
+	ReadonlyRootfs bool `codec:"readonly_rootfs"`
+
+	// CapAdd is a set of linux capabilities to enable.
+	CapAdd []string `codec:"cap_add"`
+
+	// CapDrop is a set of linux capabilities to disable.
+	CapDrop []string `codec:"cap_drop"`
+
+	// ExtraHosts are additional entries written to the container's
+	// /etc/hosts when using group network isolation.
+	ExtraHosts []string `codec:"extra_hosts"`
+}
+
+func (tc *TaskConfig) validate() error {
+	if tc.Image == "" {
+		return fmt.Errorf("image must be set")
+	}
+
+	supported := capabilities.Supported()
+	badAdds := supported.Difference(capabilities.New(tc.CapAdd))
+	if !badAdds.Empty() {
+		return fmt.Errorf("cap_add configured with capabilities not supported by system: %s", badAdds)
+	}
+	badDrops := supported.Difference(capabilities.New(tc.CapDrop))
+	if !badDrops.Empty() {
+		return fmt.Errorf("cap_drop configured with capabilities not supported by system: %s", badDrops)
+	}
+
+	return nil
+}
+
+// TaskState is the state which is encoded in the handle returned in
+// StartTask. This information is needed to rebuild the task state and handler
+// during recovery.
+type TaskState struct {
+	ReattachConfig *pstructs.ReattachConfig
+	TaskConfig     *drivers.TaskConfig
+	ContainerID    string
+	Pid            int
+	StartedAt      time.Time
+}
+
+// NewOCIDriver returns a new DrivePlugin implementation
+func NewOCIDriver(ctx context.Context, logger hclog.Logger) drivers.DriverPlugin {
+	logger = logger.Named(pluginName)
+	return &Driver{
+		eventer: eventer.NewEventer(ctx, logger),
+		tasks:   newTaskStore(),
+		ctx:     ctx,
+		logger:  logger,
+	}
+}
+
+// setFingerprintSuccess marks the driver as having fingerprinted successfully
+func (d *Driver) setFingerprintSuccess() {
+	d.fingerprintLock.Lock()
+	d.fingerprintSuccess = helper.BoolToPtr(true)
+	d.fingerprintLock.Unlock()
+}
+
+// setFingerprintFailure marks the driver as having failed fingerprinting
+func (d *Driver) setFingerprintFailure() {
+	d.fingerprintLock.Lock()
+	d.fingerprintSuccess = helper.BoolToPtr(false)
+	d.fingerprintLock.Unlock()
+}
+
+// fingerprintSuccessful returns true if the driver has
+// never fingerprinted or has successfully fingerprinted
+func (d *Driver) fingerprintSuccessful() bool {
+	d.fingerprintLock.Lock()
+	defer d.fingerprintLock.Unlock()
+	return d.fingerprintSuccess == nil || *d.fingerprintSuccess
+}
+
+// runtime returns the OCI runtime configured for the driver
+func (d *Driver) runtime() (*ociRuntime, error) {
+	path, err := exec.LookPath(d.config.Runtime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find OCI runtime %q: %v", d.config.Runtime, err)
+	}
+	return &ociRuntime{path: path, root: d.config.RuntimeRoot}, nil
+}
+
+func (d *Driver) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (d *Driver) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (d *Driver) SetConfig(cfg *base.Config) error {
+	// unpack, validate, and set agent plugin config
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+	d.config = config
+
+	if cfg != nil && cfg.AgentConfig != nil {
+		d.nomadConfig = cfg.AgentConfig.Driver
+	}
+	return nil
+}
+
+func (d *Driver) TaskConfigSchema() (*hclspec.Spec, error) {
+	return taskConfigSpec, nil
+}
+
+// Capabilities is returned by the Capabilities RPC and indicates what
+// optional features this driver supports
+func (d *Driver) Capabilities() (*drivers.Capabilities, error) {
+	return driverCapabilities, nil
+}
+
+func (d *Driver) Fingerprint(ctx context.Context) (<-chan *drivers.Fingerprint, error) {
+	ch := make(chan *drivers.Fingerprint)
+	go d.handleFingerprint(ctx, ch)
+	return ch, nil
+}
+
+func (d *Driver) handleFingerprint(ctx context.Context, ch chan<- *drivers.Fingerprint) {
+	defer close(ch)
+	ticker := time.NewTimer(0)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(fingerprintPeriod)
+			ch <- d.buildFingerprint()
+		}
+	}
+}
+
+func (d *Driver) buildFingerprint() *drivers.Fingerprint {
+	if runtime.GOOS != "linux" {
+		d.setFingerprintFailure()
+		return &drivers.Fingerprint{
+			Health:            drivers.HealthStateUndetected,
+			HealthDescription: "oci driver unsupported on client OS",
+		}
+	}
+
+	fp := &drivers.Fingerprint{
+		Attributes:        map[string]*pstructs.Attribute{},
+		Health:            drivers.HealthStateHealthy,
+		HealthDescription: drivers.DriverHealthy,
+	}
+
+	if !utils.IsUnixRoot() {
+		fp.Health = drivers.HealthStateUndetected
+		fp.HealthDescription = drivers.DriverRequiresRootMessage
+		d.setFingerprintFailure()
+		return fp
+	}
+
+	mount, err := cgutil.FindCgroupMountpointDir()
+	if err != nil {
+		fp.Health = drivers.HealthStateUnhealthy
+		fp.HealthDescription = drivers.NoCgroupMountMessage
+		if d.fingerprintSuccessful() {
+			d.logger.Warn(fp.HealthDescription, "error", err)
+		}
+		d.setFingerprintFailure()
+		return fp
+	}
+
+	if mount == "" {
+		fp.Health = drivers.HealthStateUnhealthy
+		fp.HealthDescription = drivers.CgroupMountEmpty
+		d.setFingerprintFailure()
+		return fp
+	}
+
+	rt, err := d.runtime()
+	if err != nil {
+		fp.Health = drivers.HealthStateUndetected
+		fp.HealthDescription = "OCI runtime not found"
+		if d.fingerprintSuccessful() {
+			d.logger.Debug(fp.HealthDescription, "error", err)
+		}
+		d.setFingerprintFailure()
+		return fp
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, runtimeTimeout)
+	defer cancel()
+	version, err := rt.Version(ctx)
+	if err != nil {
+		fp.Health = drivers.HealthStateUnhealthy
+		fp.HealthDescription = "failed to determine OCI runtime version"
+		if d.fingerprintSuccessful() {
+			d.logger.Warn(fp.HealthDescription, "runtime", rt.path, "error", err)
+		}
+		d.setFingerprintFailure()
+		return fp
+	}
+
+	fp.Attributes["driver.oci"] = pstructs.NewBoolAttribute(true)
+	fp.Attributes["driver.oci.runtime"] = pstructs.NewStringAttribute(filepath.Base(rt.path))
+	fp.Attributes["driver.oci.version"] = pstructs.NewStringAttribute(version)
+	d.setFingerprintSuccess()
+	return fp
+}
+
+func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
+	if handle == nil {
+		return fmt.Errorf("handle cannot be nil")
+	}
+
+	// If already attached to handle there's nothing to recover.
+	if _, ok := d.tasks.Get(handle.Config.ID); ok {
+		d.logger.Trace("nothing to recover; task already exists",
+			"task_id", handle.Config.ID,
+			"task_name", handle.Config.Name,
+		)
+		return nil
+	}
+
+	// Handle doesn't already exist, try to reattach
+	var taskState TaskState
+	if err := handle.GetDriverState(&taskState); err != nil {
+		d.logger.Error("failed to decode task state from handle", "error", err, "task_id", handle.Config.ID)
+		return fmt.Errorf("failed to decode task state from handle: %v", err)
+	}
+
+	// Create client for reattached executor
+	plugRC, err := pstructs.ReattachConfigToGoPlugin(taskState.ReattachConfig)
+	if err != nil {
+		d.logger.Error("failed to build ReattachConfig from task state", "error", err, "task_id", handle.Config.ID)
+		return fmt.Errorf("failed to build ReattachConfig from task state: %v", err)
+	}
+
+	exec, pluginClient, err := executor.ReattachToExecutor(plugRC,
+		d.logger.With("task_name", handle.Config.Name, "alloc_id", handle.Config.AllocID))
+	if err != nil {
+		d.logger.Error("failed to reattach to executor", "error", err, "task_id", handle.Config.ID)
+		return fmt.Errorf("failed to reattach to executor: %v", err)
+	}
+
+	h := &taskHandle{
+		exec:         exec,
+		pid:          taskState.Pid,
+		pluginClient: pluginClient,
+		containerID:  taskState.ContainerID,
+		doneCh:       make(chan struct{}),
+		taskConfig:   taskState.TaskConfig,
+		procState:    drivers.TaskStateRunning,
+		startedAt:    taskState.StartedAt,
+		exitResult:   &drivers.ExitResult{},
+		logger:       d.logger,
+	}
+
+	d.tasks.Set(taskState.TaskConfig.ID, h)
+
+	go h.run()
+	return nil
+}
+
+func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drivers.DriverNetwork, error) {
+	if _, ok := d.tasks.Get(cfg.ID); ok {
+		return nil, nil, fmt.Errorf("task with ID %q already started", cfg.ID)
+	}
+
+	var driverConfig TaskConfig
+	if err := cfg.DecodeDriverConfig(&driverConfig); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	if err := driverConfig.validate(); err != nil {
+		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
+	}
+
+	rt, err := d.runtime()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
+	handle := drivers.NewTaskHandle(taskHandleVersion)
+	handle.Config = cfg
+
+	// Unpack the image into the bundle's rootfs, replacing the rootfs left
+	// by any previous run of the task
+	bundle := bundleDir(cfg)
+	rootfs := filepath.Join(bundle, "rootfs")
+	if err := os.RemoveAll(rootfs); err != nil {
+		return nil, nil, fmt.Errorf("failed to remove previous rootfs: %v", err)
+	}
+
+	layout := driverConfig.Image
+	if !filepath.IsAbs(layout) {
+		layout = filepath.Join(cfg.TaskDir().Dir, layout)
+	}
+	image, err := unpackImage(layout, driverConfig.ImageRef, rootfs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unpack image: %v", err)
+	}
+
+	dnsMount, err := resolvconf.GenerateDNSMount(cfg.TaskDir().Dir, cfg.DNS)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build mount for resolv.conf: %v", err)
+	}
+	cfg.Mounts = append(cfg.Mounts, dnsMount)
+
+	etcHostsMount, err := hostnames.GenerateEtcHostsMount(
+		cfg.AllocDir, cfg.NetworkIsolation, driverConfig.ExtraHosts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build mount for /etc/hosts: %v", err)
+	}
+	if etcHostsMount != nil {
+		cfg.Mounts = append(cfg.Mounts, etcHostsMount)
+	}
+
+	caps, err := capabilities.Calculate(
+		capabilities.NomadDefaults(), d.config.AllowCaps, driverConfig.CapAdd, driverConfig.CapDrop,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	d.logger.Debug("task capabilities", "capabilities", caps)
+
+	builder := &specBuilder{
+		cfg:        cfg,
+		taskConfig: &driverConfig,
+		image:      image,
+		rootfs:     rootfs,
+		caps:       caps,
+	}
+	spec, err := builder.Build()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build container spec: %v", err)
+	}
+	if err := writeSpec(bundle, spec); err != nil {
+		return nil, nil, err
+	}
+
+	// Remove any container left behind by a previous run of the task, as
+	// container IDs must be unique
+	id := containerID(cfg.AllocID, cfg.Name)
+	ctx, cancel := context.WithTimeout(d.ctx, runtimeTimeout)
+	defer cancel()
+	if _, err := rt.State(ctx, id); err == nil {
+		if err := rt.Delete(ctx, id); err != nil {
+			return nil, nil, fmt.Errorf("failed to remove previous container: %v", err)
+		}
+	}
+
+	pluginLogFile := filepath.Join(cfg.TaskDir().Dir, "executor.out")
+	executorConfig := &executor.ExecutorConfig{
+		LogFile:     pluginLogFile,
+		LogLevel:    "debug",
+		FSIsolation: false,
+	}
+
+	exec, pluginClient, err := executor.CreateExecutor(
+		d.logger.With("task_name", handle.Config.Name, "alloc_id", handle.Config.AllocID),
+		d.nomadConfig, executorConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create executor: %v", err)
+	}
+
+	// The runtime runs the container in the foreground, so the executor
+	// supervises the runtime process which exits along with the container.
+	// Resource limits are applied to the container by the runtime.
+	args := rt.RunArgs(bundle, id)
+	execCmd := &executor.ExecCommand{
+		Cmd:        rt.path,
+		Args:       args,
+		TaskDir:    cfg.TaskDir().Dir,
+		StdoutPath: cfg.StdoutPath,
+		StderrPath: cfg.StderrPath,
+	}
+
+	ps, err := exec.Launch(execCmd)
+	if err != nil {
+		pluginClient.Kill()
+		return nil, nil, fmt.Errorf("failed to launch runtime with executor: %v", err)
+	}
+
+	h := &taskHandle{
+		exec:         exec,
+		pid:          ps.Pid,
+		pluginClient: pluginClient,
+		containerID:  id,
+		doneCh:       make(chan struct{}),
+		taskConfig:   cfg,
+		procState:    drivers.TaskStateRunning,
+		startedAt:    time.Now().Round(time.Millisecond),
+		logger:       d.logger,
+	}
+
+	driverState := TaskState{
+		ReattachConfig: pstructs.ReattachConfigFromGoPlugin(pluginClient.ReattachConfig()),
+		ContainerID:    id,
+		Pid:            ps.Pid,
+		TaskConfig:     cfg,
+		StartedAt:      h.startedAt,
+	}
+
+	if err := handle.SetDriverState(&driverState); err != nil {
+		d.logger.Error("failed to start task, error setting driver state", "error", err)
+		_ = rt.Delete(ctx, id)
+		_ = exec.Shutdown("", 0)
+		pluginClient.Kill()
+		return nil, nil, fmt.Errorf("failed to set driver state: %v", err)
+	}
+
+	d.tasks.Set(cfg.ID, h)
+	go h.run()
+	return handle, nil, nil
+}
+
+// writeSpec writes the runtime specification to the bundle's config.json
+func writeSpec(bundle string, spec interface{}) error {
+	raw, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode container spec: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(bundle, "config.json"), raw, 0600); err != nil {
+		return fmt.Errorf("failed to write container spec: %v", err)
+	}
+	return nil
+}
+
+func (d *Driver) WaitTask(ctx context.Context, taskID string) (<-chan *drivers.ExitResult, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	ch := make(chan *drivers.ExitResult)
+	go d.handleWait(ctx, handle, ch)
+
+	return ch, nil
+}
+
+func (d *Driver) handleWait(ctx context.Context, handle *taskHandle, ch chan *drivers.ExitResult) {
+	defer close(ch)
+
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case <-handle.doneCh:
+	}
+
+	result := handle.TaskStatus().ExitResult
+	select {
+	case <-ctx.Done():
+		return
+	case <-d.ctx.Done():
+		return
+	case ch <- result:
+	}
+}
+
+func (d *Driver) StopTask(taskID string, timeout time.Duration, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	rt, err := d.runtime()
+	if err != nil {
+		return err
+	}
+
+	if signal == "" {
+		signal = "SIGINT"
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, runtimeTimeout)
+	err = rt.Kill(ctx, handle.containerID, signal)
+	cancel()
+	if err != nil && handle.IsRunning() {
+		d.logger.Warn("failed to signal container", "signal", signal, "task_id", taskID, "error", err)
+	}
+
+	select {
+	case <-handle.doneCh:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	// the timeout may have outlived the context used to signal the task, so
+	// the kill gets a context of its own
+	killCtx, killCancel := context.WithTimeout(d.ctx, runtimeTimeout)
+	defer killCancel()
+
+	if err := rt.Kill(killCtx, handle.containerID, "SIGKILL"); err != nil && handle.IsRunning() {
+		return fmt.Errorf("failed to kill container: %v", err)
+	}
+	return nil
+}
+
+// resetCgroup will re-create the v2 cgroup for the task after the task has been
+// destroyed by the runtime. In the case of a task restart we call DestroyTask
+// which removes the cgroup - but we still need it!
+func (d *Driver) resetCgroup(handle *taskHandle) {
+	if cgutil.UseV2 {
+		if handle.taskConfig.Resources != nil &&
+			handle.taskConfig.Resources.LinuxResources != nil &&
+			handle.taskConfig.Resources.LinuxResources.CpusetCgroupPath != "" {
+			err := os.Mkdir(handle.taskConfig.Resources.LinuxResources.CpusetCgroupPath, 0755)
+			if err != nil {
+				d.logger.Trace("failed to reset cgroup", "path", handle.taskConfig.Resources.LinuxResources.CpusetCgroupPath)
+			}
+		}
+	}
+}
+
+func (d *Driver) DestroyTask(taskID string, force bool) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if handle.IsRunning() && !force {
+		return fmt.Errorf("cannot destroy running task")
+	}
+
+	// Forcibly deleting the container kills any processes still running
+	// within it, after which the runtime process exits
+	if rt, err := d.runtime(); err == nil {
+		ctx, cancel := context.WithTimeout(d.ctx, runtimeTimeout)
+		if err := rt.Delete(ctx, handle.containerID); err != nil {
+			handle.logger.Debug("failed to delete container", "container_id", handle.containerID, "error", err)
+		}
+		cancel()
+	}
+
+	if !handle.pluginClient.Exited() {
+		if err := handle.exec.Shutdown("", 0); err != nil {
+			handle.logger.Error("destroying executor failed", "err", err)
+		}
+
+		handle.pluginClient.Kill()
+	}
+
+	// workaround for the case where DestroyTask was issued on task restart
+	d.resetCgroup(handle)
+
+	d.tasks.Delete(taskID)
+	return nil
+}
+
+func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	return handle.TaskStatus(), nil
+}
+
+func (d *Driver) TaskStats(ctx context.Context, taskID string, interval time.Duration) (<-chan *drivers.TaskResourceUsage, error) {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	return handle.Stats(ctx, interval)
+}
+
+func (d *Driver) TaskEvents(ctx context.Context) (<-chan *drivers.TaskEvent, error) {
+	return d.eventer.TaskEvents(ctx)
+}
+
+func (d *Driver) SignalTask(taskID string, signal string) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	rt, err := d.runtime()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, runtimeTimeout)
+	defer cancel()
+	return rt.Kill(ctx, handle.containerID, signal)
+}
+
+func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
+	if len(cmd) == 0 {
+		return nil, fmt.Errorf("error cmd must have at least one value")
+	}
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return nil, drivers.ErrTaskNotFound
+	}
+
+	rt, err := d.runtime()
+	if err != nil {
+		return nil, err
+	}
+
+	out, exitCode, err := handle.exec.Exec(time.Now().Add(timeout), rt.path, rt.ExecArgs(handle.containerID, false, cmd))
+	if err != nil {
+		return nil, err
+	}
+
+	return &drivers.ExecTaskResult{
+		Stdout: out,
+		ExitResult: &drivers.ExitResult{
+			ExitCode: exitCode,
+		},
+	}, nil
+}
+
+var _ drivers.ExecTaskStreamingRawDriver = (*Driver)(nil)
+
+func (d *Driver) ExecTaskStreamingRaw(ctx context.Context,
+	taskID string,
+	command []string,
+	tty bool,
+	stream drivers.ExecTaskStream) error {
+
+	if len(command) == 0 {
+		return fmt.Errorf("error cmd must have at least one value")
+	}
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	rt, err := d.runtime()
+	if err != nil {
+		return err
+	}
+
+	execCmd := append([]string{rt.path}, rt.ExecArgs(handle.containerID, tty, command)...)
+	return handle.exec.ExecStreaming(ctx, execCmd, tty, stream)
+}
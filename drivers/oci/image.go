@@ -0,0 +1,353 @@
+package oci
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// whiteoutPrefix marks a file removed from the lower layers
+	whiteoutPrefix = ".wh."
+
+	// whiteoutOpaqueDir marks a directory whose contents in the lower layers
+	// are removed
+	whiteoutOpaqueDir = ".wh..wh..opq"
+
+	// refNameAnnotation is the annotation used to name manifests within an
+	// image layout's index
+	refNameAnnotation = "org.opencontainers.image.ref.name"
+
+	// legacyDockerLayerMediaType is the media type used by Docker for gzipped
+	// layers, which may be found in image layouts converted from Docker images
+	legacyDockerLayerMediaType = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+)
+
+// imageLayout is an OCI image layout directory, as described by the OCI image
+// specification, which the task's image is read from.
+type imageLayout struct {
+	dir string
+}
+
+// readBlob reads and decodes the JSON blob described by the descriptor,
+// verifying its digest
+func (l *imageLayout) readBlob(desc ocispec.Descriptor, v interface{}) error {
+	f, err := l.openBlob(desc)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	verifier := desc.Digest.Verifier()
+	if err := json.NewDecoder(io.TeeReader(f, verifier)).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode blob %s: %v", desc.Digest, err)
+	}
+
+	// Drain the remaining bytes so the whole blob is verified
+	if _, err := io.Copy(verifier, f); err != nil {
+		return fmt.Errorf("failed to read blob %s: %v", desc.Digest, err)
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("blob %s failed digest verification", desc.Digest)
+	}
+	return nil
+}
+
+// openBlob opens the blob described by the descriptor
+func (l *imageLayout) openBlob(desc ocispec.Descriptor) (*os.File, error) {
+	if err := desc.Digest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid digest %q: %v", desc.Digest, err)
+	}
+	return os.Open(filepath.Join(l.dir, "blobs", desc.Digest.Algorithm().String(), desc.Digest.Encoded()))
+}
+
+// manifest returns the manifest of the image with the given reference name.
+// If the reference is empty the image layout must contain a single image.
+func (l *imageLayout) manifest(ref string) (*ocispec.Manifest, error) {
+	var index ocispec.Index
+	raw, err := os.ReadFile(filepath.Join(l.dir, "index.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image index: %v", err)
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("failed to decode image index: %v", err)
+	}
+
+	var candidates []ocispec.Descriptor
+	for _, desc := range index.Manifests {
+		if ref == "" || desc.Annotations[refNameAnnotation] == ref {
+			candidates = append(candidates, desc)
+		}
+	}
+	switch {
+	case len(candidates) == 0 && ref != "":
+		return nil, fmt.Errorf("image %q not found in image layout", ref)
+	case len(candidates) == 0:
+		return nil, fmt.Errorf("image layout contains no images")
+	case len(candidates) > 1:
+		return nil, fmt.Errorf("image layout contains multiple images, image reference must be set")
+	}
+
+	desc := candidates[0]
+
+	// Multi-platform images reference a nested index of manifests
+	if desc.MediaType == ocispec.MediaTypeImageIndex {
+		var nested ocispec.Index
+		if err := l.readBlob(desc, &nested); err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, m := range nested.Manifests {
+			if m.Platform == nil || (m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH) {
+				desc, found = m, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("image has no manifest for platform %s/%s", runtime.GOOS, runtime.GOARCH)
+		}
+	}
+
+	var manifest ocispec.Manifest
+	if err := l.readBlob(desc, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// unpackImage unpacks the layers of the image with the given reference name
+// from the image layout into the rootfs directory, and returns the image's
+// configuration.
+func unpackImage(layoutDir, ref, rootfs string) (*ocispec.ImageConfig, error) {
+	layout := &imageLayout{dir: layoutDir}
+	manifest, err := layout.manifest(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var image ocispec.Image
+	if err := layout.readBlob(manifest.Config, &image); err != nil {
+		return nil, fmt.Errorf("failed to read image config: %v", err)
+	}
+
+	if err := os.MkdirAll(rootfs, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create rootfs: %v", err)
+	}
+
+	for _, desc := range manifest.Layers {
+		if err := layout.unpackLayer(desc, rootfs); err != nil {
+			return nil, fmt.Errorf("failed to unpack layer %s: %v", desc.Digest, err)
+		}
+	}
+
+	return &image.Config, nil
+}
+
+// unpackLayer applies the layer described by the descriptor to the rootfs
+func (l *imageLayout) unpackLayer(desc ocispec.Descriptor, rootfs string) error {
+	f, err := l.openBlob(desc)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	verifier := desc.Digest.Verifier()
+	tee := io.TeeReader(f, verifier)
+	r := tee
+
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageLayer, ocispec.MediaTypeImageLayerNonDistributable:
+	case ocispec.MediaTypeImageLayerGzip, ocispec.MediaTypeImageLayerNonDistributableGzip, legacyDockerLayerMediaType:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	default:
+		return fmt.Errorf("unsupported layer media type %q", desc.MediaType)
+	}
+
+	if err := applyLayer(tar.NewReader(r), rootfs); err != nil {
+		return err
+	}
+
+	// Drain any trailing bytes so the whole blob is verified
+	if _, err := io.Copy(io.Discard, tee); err != nil {
+		return err
+	}
+	if !verifier.Verified() {
+		return fmt.Errorf("layer failed digest verification")
+	}
+	return nil
+}
+
+// applyLayer extracts the layer's entries into the rootfs, applying any
+// whiteouts to the lower layers already extracted.
+func applyLayer(tr *tar.Reader, rootfs string) error {
+	// Track the paths extracted from this layer, as opaque whiteouts only
+	// remove entries from the lower layers
+	extracted := map[string]struct{}{}
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.Join("/", hdr.Name))
+		if name == "/" {
+			continue
+		}
+		dir, base := filepath.Split(name)
+
+		// Resolve the parent directory within the rootfs, so that symlinks
+		// can't be used to write outside of it
+		parent, err := securejoin.SecureJoin(rootfs, dir)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case base == whiteoutOpaqueDir:
+			if err := removeLowerEntries(parent, filepath.Clean(dir), extracted); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			if err := os.RemoveAll(filepath.Join(parent, strings.TrimPrefix(base, whiteoutPrefix))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return err
+		}
+		if err := extractEntry(tr, hdr, rootfs, filepath.Join(parent, base)); err != nil {
+			return fmt.Errorf("failed to extract %s: %v", name, err)
+		}
+		extracted[name] = struct{}{}
+	}
+}
+
+// removeLowerEntries removes the entries of the directory that were not
+// extracted from the current layer
+func removeLowerEntries(path, name string, extracted map[string]struct{}) error {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if _, ok := extracted[filepath.Join(name, entry.Name())]; ok {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractEntry creates the file described by the tar header at the path
+func extractEntry(tr *tar.Reader, hdr *tar.Header, rootfs, path string) error {
+	// Replace any existing entry from the lower layers, unless both are
+	// directories in which case the contents are merged
+	if fi, err := os.Lstat(path); err == nil {
+		if !(fi.IsDir() && hdr.Typeflag == tar.TypeDir) {
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	mode := os.FileMode(hdr.Mode).Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(path, mode); err != nil {
+			return err
+		}
+	case tar.TypeReg, tar.TypeRegA:
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := os.Symlink(hdr.Linkname, path); err != nil {
+			return err
+		}
+	case tar.TypeLink:
+		target, err := securejoin.SecureJoin(rootfs, hdr.Linkname)
+		if err != nil {
+			return err
+		}
+		if err := os.Link(target, path); err != nil {
+			return err
+		}
+	case tar.TypeChar, tar.TypeBlock, tar.TypeFifo:
+		if err := mknod(path, hdr); err != nil {
+			return err
+		}
+	default:
+		// Skip entries such as extended headers which don't create files
+		return nil
+	}
+
+	if err := os.Lchown(path, hdr.Uid, hdr.Gid); err != nil {
+		return err
+	}
+
+	// Symlinks don't have their own permissions or modification times
+	if hdr.Typeflag == tar.TypeSymlink {
+		return nil
+	}
+
+	// Apply the permissions explicitly, as they are masked by the umask and
+	// the setuid, setgid and sticky bits are cleared by chown
+	if err := os.Chmod(path, fileMode(hdr)); err != nil {
+		return err
+	}
+	atime := hdr.AccessTime
+	if atime.IsZero() {
+		atime = hdr.ModTime
+	}
+	return os.Chtimes(path, atime, hdr.ModTime)
+}
+
+// fileMode returns the permission and special mode bits of the tar entry
+func fileMode(hdr *tar.Header) os.FileMode {
+	mode := os.FileMode(hdr.Mode).Perm()
+	if hdr.Mode&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if hdr.Mode&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if hdr.Mode&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package oci
+
+import (
+	"archive/tar"
+	"fmt"
+)
+
+// mknod is unsupported as tasks only run on Linux
+func mknod(path string, hdr *tar.Header) error {
+	return fmt.Errorf("device files are not supported on this platform")
+}
@@ -0,0 +1,198 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	digest "github.com/opencontainers/go-digest"
+	specsgo "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// testLayerEntry is a file within a test image layer
+type testLayerEntry struct {
+	name     string
+	typeflag byte
+	body     string
+	linkname string
+}
+
+// testImageLayout writes an OCI image layout containing a single image built
+// from the layers, and returns its directory.
+func testImageLayout(t *testing.T, ref string, config ocispec.ImageConfig, layers ...[]testLayerEntry) string {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755))
+
+	writeBlob := func(mediaType string, raw []byte) ocispec.Descriptor {
+		d := digest.FromBytes(raw)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", d.Encoded()), raw, 0644))
+		return ocispec.Descriptor{MediaType: mediaType, Digest: d, Size: int64(len(raw))}
+	}
+	writeJSON := func(mediaType string, v interface{}) ocispec.Descriptor {
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		return writeBlob(mediaType, raw)
+	}
+
+	manifest := ocispec.Manifest{Versioned: specsgo.Versioned{SchemaVersion: 2}}
+	for _, entries := range layers {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for _, e := range entries {
+			hdr := &tar.Header{
+				Name:     e.name,
+				Typeflag: e.typeflag,
+				Linkname: e.linkname,
+				Mode:     0644,
+				Size:     int64(len(e.body)),
+				Uid:      os.Getuid(),
+				Gid:      os.Getgid(),
+			}
+			if e.typeflag == tar.TypeDir {
+				hdr.Mode = 0755
+			}
+			require.NoError(t, tw.WriteHeader(hdr))
+			_, err := tw.Write([]byte(e.body))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		manifest.Layers = append(manifest.Layers, writeBlob(ocispec.MediaTypeImageLayerGzip, buf.Bytes()))
+	}
+
+	manifest.Config = writeJSON(ocispec.MediaTypeImageConfig, ocispec.Image{
+		Architecture: "amd64",
+		OS:           "linux",
+		Config:       config,
+	})
+	desc := writeJSON(ocispec.MediaTypeImageManifest, manifest)
+	desc.Annotations = map[string]string{refNameAnnotation: ref}
+
+	index := ocispec.Index{
+		Versioned: specsgo.Versioned{SchemaVersion: 2},
+		Manifests: []ocispec.Descriptor{desc},
+	}
+	raw, err := json.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), raw, 0644))
+	return dir
+}
+
+func TestUnpackImage(t *testing.T) {
+	ci.Parallel(t)
+
+	layout := testImageLayout(t, "latest",
+		ocispec.ImageConfig{Cmd: []string{"/bin/app"}, Env: []string{"FOO=bar"}},
+		[]testLayerEntry{
+			{name: "bin/", typeflag: tar.TypeDir},
+			{name: "bin/app", typeflag: tar.TypeReg, body: "v1"},
+			{name: "etc/", typeflag: tar.TypeDir},
+			{name: "etc/removed", typeflag: tar.TypeReg, body: "gone"},
+			{name: "var/", typeflag: tar.TypeDir},
+			{name: "var/lib/", typeflag: tar.TypeDir},
+			{name: "var/lib/old", typeflag: tar.TypeReg, body: "old"},
+		},
+		[]testLayerEntry{
+			{name: "bin/app", typeflag: tar.TypeReg, body: "v2"},
+			{name: "bin/link", typeflag: tar.TypeSymlink, linkname: "app"},
+			{name: "etc/.wh.removed", typeflag: tar.TypeReg},
+			{name: "var/lib/new", typeflag: tar.TypeReg, body: "new"},
+			{name: "var/lib/.wh..wh..opq", typeflag: tar.TypeReg},
+		},
+	)
+
+	rootfs := filepath.Join(t.TempDir(), "rootfs")
+	config, err := unpackImage(layout, "latest", rootfs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/bin/app"}, config.Cmd)
+	require.Equal(t, []string{"FOO=bar"}, config.Env)
+
+	// Files from upper layers replace those from lower layers
+	raw, err := os.ReadFile(filepath.Join(rootfs, "bin", "app"))
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(raw))
+
+	target, err := os.Readlink(filepath.Join(rootfs, "bin", "link"))
+	require.NoError(t, err)
+	require.Equal(t, "app", target)
+
+	// Whiteouts remove files from lower layers
+	require.NoFileExists(t, filepath.Join(rootfs, "etc", "removed"))
+	require.NoFileExists(t, filepath.Join(rootfs, "etc", ".wh.removed"))
+
+	// Opaque whiteouts only remove the lower layers' entries
+	require.NoFileExists(t, filepath.Join(rootfs, "var", "lib", "old"))
+	require.FileExists(t, filepath.Join(rootfs, "var", "lib", "new"))
+}
+
+func TestUnpackImage_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	layout := testImageLayout(t, "latest", ocispec.ImageConfig{},
+		[]testLayerEntry{{name: "app", typeflag: tar.TypeReg, body: "app"}},
+	)
+
+	// Unknown image references
+	_, err := unpackImage(layout, "other", filepath.Join(t.TempDir(), "rootfs"))
+	require.EqualError(t, err, `image "other" not found in image layout`)
+
+	// The single image is used if no reference is given
+	_, err = unpackImage(layout, "", filepath.Join(t.TempDir(), "rootfs"))
+	require.NoError(t, err)
+
+	// Corrupted blobs fail verification
+	blobs, err := filepath.Glob(filepath.Join(layout, "blobs", "sha256", "*"))
+	require.NoError(t, err)
+	for _, blob := range blobs {
+		raw, err := os.ReadFile(blob)
+		require.NoError(t, err)
+
+		// Layers are the only gzipped blobs
+		if bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			require.NoError(t, tw.WriteHeader(&tar.Header{Name: "evil", Typeflag: tar.TypeReg, Mode: 0644}))
+			require.NoError(t, tw.Close())
+			require.NoError(t, gz.Close())
+			require.NoError(t, os.WriteFile(blob, buf.Bytes(), 0644))
+		}
+	}
+	_, err = unpackImage(layout, "", filepath.Join(t.TempDir(), "rootfs"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "digest verification")
+}
+
+func TestApplyLayer_PathTraversal(t *testing.T) {
+	ci.Parallel(t)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "escape", Typeflag: tar.TypeSymlink, Linkname: "../../../../", Uid: os.Getuid(), Gid: os.Getgid()},
+		{Name: "escape/file", Typeflag: tar.TypeReg, Mode: 0644, Uid: os.Getuid(), Gid: os.Getgid()},
+		{Name: "../outside", Typeflag: tar.TypeReg, Mode: 0644, Uid: os.Getuid(), Gid: os.Getgid()},
+	} {
+		require.NoError(t, tw.WriteHeader(hdr))
+	}
+	require.NoError(t, tw.Close())
+
+	dir := t.TempDir()
+	rootfs := filepath.Join(dir, "rootfs")
+	require.NoError(t, os.MkdirAll(rootfs, 0755))
+	require.NoError(t, applyLayer(tar.NewReader(&buf), rootfs))
+
+	// Entries are extracted within the rootfs even when their paths or
+	// parent symlinks point outside of it
+	require.NoFileExists(t, filepath.Join(dir, "outside"))
+	require.FileExists(t, filepath.Join(rootfs, "outside"))
+	require.FileExists(t, filepath.Join(rootfs, "file"))
+}
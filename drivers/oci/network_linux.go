@@ -0,0 +1,51 @@
+//go:build linux
+
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/hashicorp/nomad/client/lib/nsutil"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+var _ drivers.DriverNetworkManager = (*Driver)(nil)
+
+// CreateNetwork creates the network namespace shared by the allocation's
+// tasks. The namespace is owned by the driver so the hostname requested by
+// the job can be set within each container's UTS namespace.
+func (d *Driver) CreateNetwork(allocID string, req *drivers.NetworkCreateRequest) (*drivers.NetworkIsolationSpec, bool, error) {
+	netns, err := nsutil.NewNS(allocID)
+	if err != nil {
+		// when a client restarts, the namespace will already exist and
+		// there will be a namespace file in use by the task process
+		if e, ok := err.(*os.PathError); ok && e.Err == syscall.EPERM {
+			if _, err := os.Stat(filepath.Join(nsutil.NetNSRunDir, allocID)); err == nil {
+				return nil, false, nil
+			}
+		}
+		return nil, false, err
+	}
+
+	hostname := allocID[:8]
+	if req != nil && req.Hostname != "" {
+		hostname = req.Hostname
+	}
+
+	spec := &drivers.NetworkIsolationSpec{
+		Mode:   drivers.NetIsolationModeGroup,
+		Path:   netns.Path(),
+		Labels: make(map[string]string),
+		HostsConfig: &drivers.HostsConfig{
+			Hostname: hostname,
+		},
+	}
+	return spec, true, nil
+}
+
+// DestroyNetwork removes the allocation's network namespace
+func (d *Driver) DestroyNetwork(allocID string, spec *drivers.NetworkIsolationSpec) error {
+	return nsutil.UnmountNS(spec.Path)
+}
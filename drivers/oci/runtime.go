@@ -0,0 +1,131 @@
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var (
+	// runtimeVersionRe matches the version reported by OCI runtimes such as
+	// "runc version 1.1.0" or "crun version 1.4.5"
+	runtimeVersionRe = regexp.MustCompile(`version\s+v?(\S+)`)
+
+	// containerIDRe matches the characters allowed in a container ID by OCI
+	// runtimes
+	containerIDRe = regexp.MustCompile(`[^\w+.-]`)
+)
+
+// ociRuntime invokes an OCI runtime binary, such as runc or crun, which
+// implements the OCI runtime command line interface.
+type ociRuntime struct {
+	// path is the path to the runtime binary
+	path string
+
+	// root is the directory the runtime stores container state in. If empty
+	// the runtime's default is used.
+	root string
+}
+
+// containerState is the subset of the state reported by the runtime's state
+// command used by the driver
+type containerState struct {
+	ID     string `json:"id"`
+	Pid    int    `json:"pid"`
+	Status string `json:"status"`
+	Bundle string `json:"bundle"`
+}
+
+// containerID returns the ID of the container running a task. Container IDs
+// must be unique on the host, so are derived from the allocation ID and task
+// name.
+func containerID(allocID, taskName string) string {
+	return containerIDRe.ReplaceAllString(fmt.Sprintf("%s-%s", allocID, taskName), "_")
+}
+
+// args returns the command line for the runtime, prefixed with the global
+// flags
+func (r *ociRuntime) args(args ...string) []string {
+	var out []string
+	if r.root != "" {
+		out = append(out, "--root", r.root)
+	}
+	return append(out, args...)
+}
+
+// run runs the runtime with the arguments and returns its stdout
+func (r *ociRuntime) run(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.path, r.args(args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s %s failed: %v: %s", r.path, args[0], err, msg)
+		}
+		return nil, fmt.Errorf("%s %s failed: %v", r.path, args[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// Version returns the version of the runtime
+func (r *ociRuntime) Version(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, r.path, "--version").Output()
+	if err != nil {
+		return "", err
+	}
+
+	matches := runtimeVersionRe.FindStringSubmatch(string(out))
+	if len(matches) != 2 {
+		return "", fmt.Errorf("failed to parse runtime version from %q", strings.TrimSpace(string(out)))
+	}
+	return matches[1], nil
+}
+
+// RunArgs returns the arguments to run a container in the foreground. The
+// runtime forwards signals it receives to the container and exits with the
+// container's exit code.
+func (r *ociRuntime) RunArgs(bundle, id string) []string {
+	return r.args("run", "--bundle", bundle, id)
+}
+
+// ExecArgs returns the arguments to run a command within a container
+func (r *ociRuntime) ExecArgs(id string, tty bool, command []string) []string {
+	args := []string{"exec"}
+	if tty {
+		args = append(args, "--tty")
+	}
+	args = append(args, id)
+	return append(r.args(args...), command...)
+}
+
+// State returns the state of the container
+func (r *ociRuntime) State(ctx context.Context, id string) (*containerState, error) {
+	out, err := r.run(ctx, "state", id)
+	if err != nil {
+		return nil, err
+	}
+
+	var state containerState
+	if err := json.Unmarshal(out, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode container state: %v", err)
+	}
+	return &state, nil
+}
+
+// Kill sends the signal to the container's init process
+func (r *ociRuntime) Kill(ctx context.Context, id, signal string) error {
+	_, err := r.run(ctx, "kill", id, signal)
+	return err
+}
+
+// Delete forcibly deletes the container, killing any processes still running
+// within it
+func (r *ociRuntime) Delete(ctx context.Context, id string) error {
+	_, err := r.run(ctx, "delete", "--force", id)
+	return err
+}
@@ -0,0 +1,352 @@
+package oci
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/opencontainers/runc/libcontainer/user"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+const (
+	// defaultPath is the PATH set for tasks whose image doesn't set one
+	defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+)
+
+var (
+	// defaultMounts are the filesystems mounted into every container
+	defaultMounts = []specs.Mount{
+		{
+			Destination: "/proc",
+			Type:        "proc",
+			Source:      "proc",
+		},
+		{
+			Destination: "/dev",
+			Type:        "tmpfs",
+			Source:      "tmpfs",
+			Options:     []string{"nosuid", "strictatime", "mode=755", "size=65536k"},
+		},
+		{
+			Destination: "/dev/pts",
+			Type:        "devpts",
+			Source:      "devpts",
+			Options:     []string{"nosuid", "noexec", "newinstance", "ptmxmode=0666", "mode=0620", "gid=5"},
+		},
+		{
+			Destination: "/dev/shm",
+			Type:        "tmpfs",
+			Source:      "shm",
+			Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", "size=65536k"},
+		},
+		{
+			Destination: "/dev/mqueue",
+			Type:        "mqueue",
+			Source:      "mqueue",
+			Options:     []string{"nosuid", "noexec", "nodev"},
+		},
+		{
+			Destination: "/sys",
+			Type:        "sysfs",
+			Source:      "sysfs",
+			Options:     []string{"nosuid", "noexec", "nodev", "ro"},
+		},
+		{
+			Destination: "/sys/fs/cgroup",
+			Type:        "cgroup",
+			Source:      "cgroup",
+			Options:     []string{"nosuid", "noexec", "nodev", "relatime", "ro"},
+		},
+	}
+
+	// maskedPaths are the paths hidden from containers
+	maskedPaths = []string{
+		"/proc/acpi",
+		"/proc/asound",
+		"/proc/kcore",
+		"/proc/keys",
+		"/proc/latency_stats",
+		"/proc/timer_list",
+		"/proc/timer_stats",
+		"/proc/sched_debug",
+		"/proc/scsi",
+		"/sys/firmware",
+	}
+
+	// readonlyPaths are the paths mounted read-only within containers
+	readonlyPaths = []string{
+		"/proc/bus",
+		"/proc/fs",
+		"/proc/irq",
+		"/proc/sys",
+		"/proc/sysrq-trigger",
+	}
+)
+
+// specBuilder builds the OCI runtime specification of the container running
+// a task.
+type specBuilder struct {
+	cfg        *drivers.TaskConfig
+	taskConfig *TaskConfig
+	image      *ocispec.ImageConfig
+	rootfs     string
+	caps       []string
+}
+
+// Build returns the runtime specification
+func (b *specBuilder) Build() (*specs.Spec, error) {
+	args, err := b.processArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	execUser, err := b.user()
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &specs.Spec{
+		Version: specs.Version,
+		Root: &specs.Root{
+			Path:     b.rootfs,
+			Readonly: b.taskConfig.ReadonlyRootfs,
+		},
+		Process: &specs.Process{
+			User: specs.User{
+				UID: uint32(execUser.Uid),
+				GID: uint32(execUser.Gid),
+			},
+			Args:            args,
+			Env:             b.env(execUser.Home),
+			Cwd:             b.cwd(),
+			NoNewPrivileges: true,
+			Capabilities: &specs.LinuxCapabilities{
+				Bounding:  b.caps,
+				Effective: b.caps,
+				Permitted: b.caps,
+			},
+		},
+		Mounts: b.mounts(),
+		Linux: &specs.Linux{
+			Namespaces:    b.namespaces(),
+			Resources:     b.resources(),
+			CgroupsPath:   cgroupsPath(b.cfg),
+			MaskedPaths:   maskedPaths,
+			ReadonlyPaths: readonlyPaths,
+		},
+	}
+
+	for _, gid := range execUser.Sgids {
+		spec.Process.User.AdditionalGids = append(spec.Process.User.AdditionalGids, uint32(gid))
+	}
+
+	if iso := b.cfg.NetworkIsolation; iso != nil && iso.HostsConfig != nil {
+		spec.Hostname = iso.HostsConfig.Hostname
+	}
+
+	if err := configureDevices(spec, b.cfg.Devices); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// processArgs returns the arguments of the container's process. The
+// entrypoint and command of the image are overridden by the task's
+// configuration.
+func (b *specBuilder) processArgs() ([]string, error) {
+	entrypoint := b.image.Entrypoint
+	if len(b.taskConfig.Entrypoint) > 0 {
+		entrypoint = b.taskConfig.Entrypoint
+	}
+
+	cmd := b.image.Cmd
+	if b.taskConfig.Command != "" {
+		cmd = append([]string{b.taskConfig.Command}, b.taskConfig.Args...)
+	} else if len(b.taskConfig.Args) > 0 {
+		cmd = b.taskConfig.Args
+	}
+
+	args := append(append([]string{}, entrypoint...), cmd...)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("no command specified by the image or task configuration")
+	}
+	return args, nil
+}
+
+// user resolves the user the container's process runs as from the rootfs'
+// passwd and group files
+func (b *specBuilder) user() (*user.ExecUser, error) {
+	spec := b.cfg.User
+	if spec == "" {
+		spec = b.image.User
+	}
+
+	passwd, err := securejoin.SecureJoin(b.rootfs, "/etc/passwd")
+	if err != nil {
+		return nil, err
+	}
+	group, err := securejoin.SecureJoin(b.rootfs, "/etc/group")
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := &user.ExecUser{Uid: 0, Gid: 0, Home: "/"}
+	execUser, err := user.GetExecUserPath(spec, defaults, passwd, group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve user %q: %v", spec, err)
+	}
+	return execUser, nil
+}
+
+// env returns the environment of the container's process. The task's
+// environment takes precedence over the image's.
+func (b *specBuilder) env(home string) []string {
+	env := map[string]string{
+		"PATH": defaultPath,
+		"HOME": home,
+	}
+	for _, kv := range b.image.Env {
+		if parts := strings.SplitN(kv, "=", 2); len(parts) == 2 {
+			env[parts[0]] = parts[1]
+		}
+	}
+	for k, v := range b.cfg.Env {
+		env[k] = v
+	}
+
+	list := make([]string, 0, len(env))
+	for k, v := range env {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// cwd returns the working directory of the container's process
+func (b *specBuilder) cwd() string {
+	switch {
+	case b.taskConfig.WorkDir != "":
+		return b.taskConfig.WorkDir
+	case b.image.WorkingDir != "":
+		return b.image.WorkingDir
+	default:
+		return "/"
+	}
+}
+
+// mounts returns the filesystems mounted into the container, including the
+// task's directories and any volumes
+func (b *specBuilder) mounts() []specs.Mount {
+	mounts := append([]specs.Mount{}, defaultMounts...)
+
+	taskDir := b.cfg.TaskDir()
+	for _, m := range []struct{ source, dest string }{
+		{taskDir.SharedAllocDir, allocdir.SharedAllocContainerPath},
+		{taskDir.LocalDir, allocdir.TaskLocalContainerPath},
+		{taskDir.SecretsDir, allocdir.TaskSecretsContainerPath},
+	} {
+		mounts = append(mounts, specs.Mount{
+			Destination: m.dest,
+			Type:        "bind",
+			Source:      m.source,
+			Options:     []string{"rbind", "rw"},
+		})
+	}
+
+	for _, m := range b.cfg.Mounts {
+		opts := []string{"rbind"}
+		if m.Readonly {
+			opts = append(opts, "ro")
+		} else {
+			opts = append(opts, "rw")
+		}
+		switch m.PropagationMode {
+		case "host-to-task":
+			opts = append(opts, "rslave")
+		case "bidirectional":
+			opts = append(opts, "rshared")
+		default:
+			opts = append(opts, "rprivate")
+		}
+		mounts = append(mounts, specs.Mount{
+			Destination: m.TaskPath,
+			Type:        "bind",
+			Source:      m.HostPath,
+			Options:     opts,
+		})
+	}
+	return mounts
+}
+
+// namespaces returns the namespaces the container is isolated by. The
+// container joins the allocation's network namespace if one was created,
+// otherwise it shares the host's network.
+func (b *specBuilder) namespaces() []specs.LinuxNamespace {
+	namespaces := []specs.LinuxNamespace{
+		{Type: specs.PIDNamespace},
+		{Type: specs.IPCNamespace},
+		{Type: specs.UTSNamespace},
+		{Type: specs.MountNamespace},
+	}
+
+	if iso := b.cfg.NetworkIsolation; iso != nil && iso.Path != "" {
+		namespaces = append(namespaces, specs.LinuxNamespace{
+			Type: specs.NetworkNamespace,
+			Path: iso.Path,
+		})
+	}
+	return namespaces
+}
+
+// resources returns the cgroup limits of the container
+func (b *specBuilder) resources() *specs.LinuxResources {
+	resources := &specs.LinuxResources{
+		// Deny access to all devices other than those created by the runtime
+		// and those explicitly added to the container
+		Devices: []specs.LinuxDeviceCgroup{{Allow: false, Access: "rwm"}},
+	}
+
+	res := b.cfg.Resources
+	if res == nil || res.NomadResources == nil {
+		return resources
+	}
+
+	memHard, memSoft := res.NomadResources.Memory.MemoryMaxMB, res.NomadResources.Memory.MemoryMB
+	if memHard <= 0 {
+		memHard = res.NomadResources.Memory.MemoryMB
+		memSoft = 0
+	}
+	if memHard > 0 {
+		limit := memHard * 1024 * 1024
+		var swappiness uint64
+		resources.Memory = &specs.LinuxMemory{
+			Limit:      &limit,
+			Swappiness: &swappiness,
+		}
+		if memSoft > 0 {
+			reservation := memSoft * 1024 * 1024
+			resources.Memory.Reservation = &reservation
+		}
+	}
+
+	resources.CPU = &specs.LinuxCPU{}
+	if shares := uint64(res.NomadResources.Cpu.CpuShares); shares > 0 {
+		resources.CPU.Shares = &shares
+	}
+	if res.LinuxResources != nil {
+		resources.CPU.Cpus = res.LinuxResources.CpusetCpus
+	}
+	return resources
+}
+
+// bundleDir returns the directory of the task's OCI bundle
+func bundleDir(cfg *drivers.TaskConfig) string {
+	return filepath.Join(cfg.TaskDir().Dir, bundleDirName)
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package oci
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+func cgroupsPath(cfg *drivers.TaskConfig) string {
+	return ""
+}
+
+func configureDevices(spec *specs.Spec, devices []*drivers.DeviceConfig) error {
+	if len(devices) > 0 {
+		return fmt.Errorf("devices are only supported on linux")
+	}
+	return nil
+}
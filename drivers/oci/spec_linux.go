@@ -0,0 +1,82 @@
+//go:build linux
+
+package oci
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+// cgroupsPath returns the cgroup the container is placed in, relative to the
+// cgroup root. On cgroups v2 this is the cgroup created for the task by the
+// client's cpuset manager.
+func cgroupsPath(cfg *drivers.TaskConfig) string {
+	if cgutil.UseV2 {
+		if res := cfg.Resources; res != nil && res.LinuxResources != nil && res.LinuxResources.CpusetCgroupPath != "" {
+			parent, cgroup := cgutil.SplitPath(res.LinuxResources.CpusetCgroupPath)
+			return filepath.Join("/", parent, cgroup)
+		}
+		return filepath.Join("/", cgutil.DefaultCgroupParentV2, cgutil.CgroupScope(cfg.AllocID, cfg.Name))
+	}
+	return filepath.Join(cgutil.DefaultCgroupV1Parent, containerID(cfg.AllocID, cfg.Name))
+}
+
+// configureDevices adds the host devices to the container and allows access
+// to them in the device cgroup
+func configureDevices(spec *specs.Spec, devices []*drivers.DeviceConfig) error {
+	for _, d := range devices {
+		var st unix.Stat_t
+		if err := unix.Stat(d.HostPath, &st); err != nil {
+			return fmt.Errorf("failed to stat device %q: %v", d.HostPath, err)
+		}
+
+		var devType string
+		switch st.Mode & unix.S_IFMT {
+		case unix.S_IFCHR:
+			devType = "c"
+		case unix.S_IFBLK:
+			devType = "b"
+		default:
+			return fmt.Errorf("%q is not a device", d.HostPath)
+		}
+
+		perms := d.Permissions
+		if perms == "" {
+			perms = "rwm"
+		}
+
+		major, minor := int64(unix.Major(uint64(st.Rdev))), int64(unix.Minor(uint64(st.Rdev)))
+		mode := os.FileMode(st.Mode).Perm()
+		uid, gid := st.Uid, st.Gid
+
+		path := d.TaskPath
+		if path == "" {
+			path = d.HostPath
+		}
+
+		spec.Linux.Devices = append(spec.Linux.Devices, specs.LinuxDevice{
+			Path:     path,
+			Type:     devType,
+			Major:    major,
+			Minor:    minor,
+			FileMode: &mode,
+			UID:      &uid,
+			GID:      &gid,
+		})
+		spec.Linux.Resources.Devices = append(spec.Linux.Resources.Devices, specs.LinuxDeviceCgroup{
+			Allow:  true,
+			Type:   devType,
+			Major:  &major,
+			Minor:  &minor,
+			Access: strings.ToLower(perms),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package oci
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/require"
+)
+
+// testSpecBuilder returns a spec builder for a task whose rootfs contains a
+// passwd and group file
+func testSpecBuilder(t *testing.T) *specBuilder {
+	rootfs := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootfs, "etc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootfs, "etc", "passwd"),
+		[]byte("root:x:0:0:root:/root:/bin/sh\napp:x:1000:1000:app:/home/app:/bin/sh\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rootfs, "etc", "group"),
+		[]byte("root:x:0:\napp:x:1000:\nextra:x:2000:app\n"), 0644))
+
+	return &specBuilder{
+		cfg: &drivers.TaskConfig{
+			ID:       "task-id",
+			AllocID:  "6c8c2a64-1c2b-4e0a-b4e6-2b6b7f6ad7a7",
+			Name:     "web",
+			AllocDir: "/var/nomad/alloc/6c8c2a64-1c2b-4e0a-b4e6-2b6b7f6ad7a7",
+			Env:      map[string]string{"FOO": "task"},
+			Resources: &drivers.Resources{
+				NomadResources: &structs.AllocatedTaskResources{
+					Cpu:    structs.AllocatedCpuResources{CpuShares: 500},
+					Memory: structs.AllocatedMemoryResources{MemoryMB: 256},
+				},
+			},
+		},
+		taskConfig: &TaskConfig{Image: "local/image"},
+		image: &ocispec.ImageConfig{
+			Entrypoint: []string{"/bin/entrypoint"},
+			Cmd:        []string{"serve"},
+			Env:        []string{"FOO=image", "BAR=image"},
+			WorkingDir: "/srv",
+			User:       "app",
+		},
+		rootfs: rootfs,
+		caps:   []string{"CAP_CHOWN"},
+	}
+}
+
+func TestSpecBuilder_Build(t *testing.T) {
+	ci.Parallel(t)
+
+	b := testSpecBuilder(t)
+	spec, err := b.Build()
+	require.NoError(t, err)
+
+	require.Equal(t, b.rootfs, spec.Root.Path)
+	require.False(t, spec.Root.Readonly)
+
+	// The image's configuration is used by default
+	require.Equal(t, []string{"/bin/entrypoint", "serve"}, spec.Process.Args)
+	require.Equal(t, "/srv", spec.Process.Cwd)
+	require.Equal(t, uint32(1000), spec.Process.User.UID)
+	require.Equal(t, uint32(1000), spec.Process.User.GID)
+	require.Equal(t, []uint32{2000}, spec.Process.User.AdditionalGids)
+	require.Equal(t, []string{"CAP_CHOWN"}, spec.Process.Capabilities.Bounding)
+	require.True(t, spec.Process.NoNewPrivileges)
+
+	// The task's environment takes precedence over the image's
+	require.Contains(t, spec.Process.Env, "FOO=task")
+	require.Contains(t, spec.Process.Env, "BAR=image")
+	require.Contains(t, spec.Process.Env, "HOME=/home/app")
+	require.Contains(t, spec.Process.Env, "PATH="+defaultPath)
+
+	// The task directories are mounted into the container
+	taskDir := b.cfg.TaskDir()
+	mounts := map[string]string{}
+	for _, m := range spec.Mounts {
+		mounts[m.Destination] = m.Source
+	}
+	require.Equal(t, taskDir.SharedAllocDir, mounts["/alloc"])
+	require.Equal(t, taskDir.LocalDir, mounts["/local"])
+	require.Equal(t, taskDir.SecretsDir, mounts["/secrets"])
+
+	// The host's network is used without network isolation
+	for _, ns := range spec.Linux.Namespaces {
+		require.NotEqual(t, specs.NetworkNamespace, ns.Type)
+	}
+
+	// The memory limit defaults to the reserved memory
+	require.Equal(t, int64(256*1024*1024), *spec.Linux.Resources.Memory.Limit)
+	require.Nil(t, spec.Linux.Resources.Memory.Reservation)
+	require.Equal(t, uint64(500), *spec.Linux.Resources.CPU.Shares)
+}
+
+func TestSpecBuilder_Overrides(t *testing.T) {
+	ci.Parallel(t)
+
+	b := testSpecBuilder(t)
+	b.cfg.User = "root"
+	b.cfg.Resources.NomadResources.Memory.MemoryMaxMB = 512
+	b.cfg.NetworkIsolation = &drivers.NetworkIsolationSpec{
+		Mode:        drivers.NetIsolationModeGroup,
+		Path:        "/var/run/netns/6c8c2a64-1c2b-4e0a-b4e6-2b6b7f6ad7a7",
+		HostsConfig: &drivers.HostsConfig{Hostname: "web-host"},
+	}
+	b.cfg.Mounts = []*drivers.MountConfig{{
+		TaskPath: "/data",
+		HostPath: "/srv/data",
+		Readonly: true,
+	}}
+	b.taskConfig.Command = "/bin/worker"
+	b.taskConfig.Args = []string{"-v"}
+	b.taskConfig.WorkDir = "/tmp"
+	b.taskConfig.ReadonlyRootfs = true
+
+	spec, err := b.Build()
+	require.NoError(t, err)
+
+	require.True(t, spec.Root.Readonly)
+	require.Equal(t, []string{"/bin/entrypoint", "/bin/worker", "-v"}, spec.Process.Args)
+	require.Equal(t, "/tmp", spec.Process.Cwd)
+	require.Equal(t, uint32(0), spec.Process.User.UID)
+	require.Equal(t, "web-host", spec.Hostname)
+
+	require.Contains(t, spec.Linux.Namespaces, specs.LinuxNamespace{
+		Type: specs.NetworkNamespace,
+		Path: b.cfg.NetworkIsolation.Path,
+	})
+
+	require.Contains(t, spec.Mounts, specs.Mount{
+		Destination: "/data",
+		Type:        "bind",
+		Source:      "/srv/data",
+		Options:     []string{"rbind", "ro", "rprivate"},
+	})
+
+	// Oversubscribed memory is a soft limit
+	require.Equal(t, int64(512*1024*1024), *spec.Linux.Resources.Memory.Limit)
+	require.Equal(t, int64(256*1024*1024), *spec.Linux.Resources.Memory.Reservation)
+}
+
+func TestSpecBuilder_NoCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	b := testSpecBuilder(t)
+	b.image.Entrypoint = nil
+	b.image.Cmd = nil
+
+	_, err := b.Build()
+	require.EqualError(t, err, "no command specified by the image or task configuration")
+}
+
+func TestContainerID(t *testing.T) {
+	ci.Parallel(t)
+
+	require.Equal(t,
+		"6c8c2a64-1c2b-4e0a-b4e6-2b6b7f6ad7a7-web_server",
+		containerID("6c8c2a64-1c2b-4e0a-b4e6-2b6b7f6ad7a7", "web/server"))
+}
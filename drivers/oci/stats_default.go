@@ -0,0 +1,15 @@
+//go:build !linux
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+func (h *taskHandle) Stats(ctx context.Context, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
+	return nil, fmt.Errorf("stats are only supported on linux")
+}
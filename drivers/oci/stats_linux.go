@@ -0,0 +1,119 @@
+//go:build linux
+
+package oci
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/nomad/client/lib/cgutil"
+	"github.com/hashicorp/nomad/client/stats"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/drivers/shared/executor"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs2"
+	"github.com/opencontainers/runc/libcontainer/configs"
+)
+
+// statsSubsystems are the cgroup v1 subsystems stats are collected from
+var statsSubsystems = []string{"memory", "cpu", "cpuacct", "pids"}
+
+// cgroupManager returns a manager for the container's cgroup, used to read
+// its resource usage. The cgroup is created by the OCI runtime, so its
+// resource usage accounts for every process in the container rather than
+// just the runtime process launched by the executor.
+func cgroupManager(path string) (cgroups.Manager, error) {
+	if cgutil.UseV2 {
+		return fs2.NewManager(nil, filepath.Join(cgutil.CgroupRoot, path), false)
+	}
+
+	paths := make(map[string]string, len(statsSubsystems))
+	for _, subsystem := range statsSubsystems {
+		mount, err := cgroups.FindCgroupMountpoint("", subsystem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find %s cgroup mount: %v", subsystem, err)
+		}
+		paths[subsystem] = filepath.Join(mount, path)
+	}
+	return fs.NewManager(&configs.Cgroup{}, paths, false), nil
+}
+
+func (h *taskHandle) Stats(ctx context.Context, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
+	mgr, err := cgroupManager(cgroupsPath(h.taskConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *cstructs.TaskResourceUsage)
+	go h.handleStats(ctx, ch, mgr, interval)
+	return ch, nil
+}
+
+func (h *taskHandle) handleStats(ctx context.Context, ch chan<- *cstructs.TaskResourceUsage, mgr cgroups.Manager, interval time.Duration) {
+	defer close(ch)
+	timer := time.NewTimer(0)
+
+	measuredMemStats := executor.ExecutorCgroupV1MeasuredMemStats
+	if cgutil.UseV2 {
+		measuredMemStats = executor.ExecutorCgroupV2MeasuredMemStats
+	}
+
+	totalCpuStats := stats.NewCpuStats()
+	userCpuStats := stats.NewCpuStats()
+	systemCpuStats := stats.NewCpuStats()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			timer.Reset(interval)
+		}
+
+		cgStats, err := mgr.GetStats()
+		if err != nil {
+			h.logger.Warn("error collecting stats", "error", err)
+			return
+		}
+
+		ms := &cstructs.MemoryStats{
+			RSS:            cgStats.MemoryStats.Stats["rss"],
+			Cache:          cgStats.MemoryStats.Stats["cache"],
+			Swap:           cgStats.MemoryStats.SwapUsage.Usage,
+			MappedFile:     cgStats.MemoryStats.Stats["mapped_file"],
+			Usage:          cgStats.MemoryStats.Usage.Usage,
+			MaxUsage:       cgStats.MemoryStats.Usage.MaxUsage,
+			KernelUsage:    cgStats.MemoryStats.KernelUsage.Usage,
+			KernelMaxUsage: cgStats.MemoryStats.KernelUsage.MaxUsage,
+			Measured:       measuredMemStats,
+		}
+
+		totalPercent := totalCpuStats.Percent(float64(cgStats.CpuStats.CpuUsage.TotalUsage))
+		cs := &cstructs.CpuStats{
+			SystemMode:       systemCpuStats.Percent(float64(cgStats.CpuStats.CpuUsage.UsageInKernelmode)),
+			UserMode:         userCpuStats.Percent(float64(cgStats.CpuStats.CpuUsage.UsageInUsermode)),
+			Percent:          totalPercent,
+			ThrottledPeriods: cgStats.CpuStats.ThrottlingData.ThrottledPeriods,
+			ThrottledTime:    cgStats.CpuStats.ThrottlingData.ThrottledTime,
+			TotalTicks:       systemCpuStats.TicksConsumed(totalPercent),
+			Measured:         executor.ExecutorCgroupMeasuredCpuStats,
+		}
+
+		usage := &cstructs.TaskResourceUsage{
+			ResourceUsage: &cstructs.ResourceUsage{
+				MemoryStats: ms,
+				CpuStats:    cs,
+			},
+			Timestamp: time.Now().UTC().UnixNano(),
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- usage:
+		}
+	}
+}
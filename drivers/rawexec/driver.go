@@ -102,6 +102,7 @@ var (
 			drivers.NetIsolationModeGroup,
 		},
 		MountConfigs: drivers.MountConfigSupportNone,
+		Resize:       true,
 	}
 )
 
@@ -494,6 +495,26 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 	return handle.exec.Signal(sig)
 }
 
+var _ drivers.ResizeTaskDriver = (*Driver)(nil)
+
+// ResizeTask updates the resources of the task. Resources aren't enforced
+// for raw_exec tasks, so the task is never restarted to update them.
+func (d *Driver) ResizeTask(taskID string, resources *drivers.Resources) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if err := handle.exec.UpdateResources(resources); err != nil {
+		return fmt.Errorf("executor UpdateResources failed: %v", err)
+	}
+
+	handle.stateLock.Lock()
+	handle.taskConfig.Resources = resources
+	handle.stateLock.Unlock()
+	return nil
+}
+
 func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
 	if len(cmd) == 0 {
 		return nil, fmt.Errorf("error cmd must have at least one value")
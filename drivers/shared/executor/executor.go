@@ -88,14 +88,22 @@ type Executor interface {
 
 	ExecStreaming(ctx context.Context, cmd []string, tty bool,
 		stream drivers.ExecTaskStream) error
+
+	// Checkpoint dumps the state of the user process to the directory and
+	// stops it. Checkpointing is only supported by the isolating executor.
+	Checkpoint(dir string) error
+
+	// Restore restores the user process from the checkpoint in the
+	// directory, using the configuration the process was launched with.
+	Restore(launchCmd *ExecCommand, dir string) (*ProcessState, error)
 }
 
 // ExecCommand holds the user command, args, and other isolation related
 // settings.
 //
-// Important (!): when adding fields, make sure to update the RPC methods in
-// grpcExecutorClient.Launch and grpcExecutorServer.Launch. Number of hours
-// spent tracking this down: too many.
+// Important (!): when adding fields, make sure to update the RPC conversions in
+// launchRequestToProto and launchRequestFromProto. Number of hours spent
+// tracking this down: too many.
 type ExecCommand struct {
 	// Cmd is the command that the user wants to run.
 	Cmd string
@@ -449,6 +457,16 @@ func (e *UniversalExecutor) ExecStreaming(ctx context.Context, command []string,
 	return execHelper.run(ctx, tty, stream)
 }
 
+// Checkpoint is not supported by the universal executor
+func (e *UniversalExecutor) Checkpoint(dir string) error {
+	return fmt.Errorf("checkpointing tasks is not supported by the universal executor")
+}
+
+// Restore is not supported by the universal executor
+func (e *UniversalExecutor) Restore(command *ExecCommand, dir string) (*ProcessState, error) {
+	return nil, fmt.Errorf("restoring tasks is not supported by the universal executor")
+}
+
 // Wait waits until a process has exited and returns it's exitcode and errors
 func (e *UniversalExecutor) Wait(ctx context.Context) (*ProcessState, error) {
 	select {
@@ -459,6 +477,8 @@ func (e *UniversalExecutor) Wait(ctx context.Context) (*ProcessState, error) {
 	}
 }
 
+// UpdateResources is a no-op as the universal executor doesn't enforce the
+// memory and CPU resources of the task.
 func (e *UniversalExecutor) UpdateResources(resources *drivers.Resources) error {
 	return nil
 }
@@ -87,6 +87,80 @@ func NewExecutorWithIsolation(logger hclog.Logger) Executor {
 func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, error) {
 	l.logger.Trace("preparing to launch command", "command", command.Cmd, "args", strings.Join(command.Args, " "))
 
+	process, err := l.newContainer(command)
+	if err != nil {
+		return nil, err
+	}
+
+	l.logger.Debug("launching", "command", command.Cmd, "args", strings.Join(command.Args, " "))
+
+	// Starts the task
+	if err := l.container.Run(process); err != nil {
+		l.container.Destroy()
+		return nil, err
+	}
+
+	return l.started(process)
+}
+
+// Restore creates a new container in libcontainer and restores the process
+// checkpointed to dir into it using CRIU
+func (l *LibcontainerExecutor) Restore(command *ExecCommand, dir string) (*ProcessState, error) {
+	l.logger.Trace("preparing to restore command", "command", command.Cmd, "dir", dir)
+
+	process, err := l.newContainer(command)
+	if err != nil {
+		return nil, err
+	}
+
+	// The restored process is reparented to the executor as its subreaper,
+	// so that it can be waited on like a launched process
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+		l.container.Destroy()
+		return nil, fmt.Errorf("failed to set executor as subreaper: %v", err)
+	}
+
+	l.logger.Debug("restoring", "command", command.Cmd, "dir", dir)
+
+	if err := l.container.Restore(process, criuOpts(dir)); err != nil {
+		l.container.Destroy()
+		return nil, fmt.Errorf("failed to restore container(%s): %v", l.id, err)
+	}
+
+	return l.started(process)
+}
+
+// Checkpoint dumps the state of the container's processes to dir using CRIU.
+// The processes are stopped once the checkpoint is written.
+func (l *LibcontainerExecutor) Checkpoint(dir string) error {
+	if l.container == nil {
+		return fmt.Errorf("no container to checkpoint")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %v", err)
+	}
+
+	l.logger.Debug("checkpointing", "dir", dir)
+	if err := l.container.Checkpoint(criuOpts(dir)); err != nil {
+		return fmt.Errorf("failed to checkpoint container(%s): %v", l.id, err)
+	}
+	return nil
+}
+
+// criuOpts returns the options used to checkpoint and restore containers.
+// Established TCP connections aren't checkpointed, as they can't be restored
+// on another node; tasks with established connections fail to checkpoint.
+func criuOpts(dir string) *libcontainer.CriuOpts {
+	return &libcontainer.CriuOpts{
+		ImagesDirectory: dir,
+		FileLocks:       true,
+	}
+}
+
+// newContainer creates the libcontainer container the command runs in and
+// returns the process to run in it
+func (l *LibcontainerExecutor) newContainer(command *ExecCommand) (*libcontainer.Process, error) {
 	if command.Resources == nil {
 		command.Resources = &drivers.Resources{
 			NomadResources: &structs.AllocatedTaskResources{},
@@ -156,8 +230,6 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 		return nil, err
 	}
 
-	l.logger.Debug("launching", "command", command.Cmd, "args", strings.Join(command.Args, " "))
-
 	// the task process will be started by the container
 	process := &libcontainer.Process{
 		Args:   combined,
@@ -176,15 +248,14 @@ func (l *LibcontainerExecutor) Launch(command *ExecCommand) (*ProcessState, erro
 	l.userCpuStats = stats.NewCpuStats()
 	l.systemCpuStats = stats.NewCpuStats()
 
-	// Starts the task
-	if err := container.Run(process); err != nil {
-		container.Destroy()
-		return nil, err
-	}
+	return process, nil
+}
 
+// started begins supervising the process once it is running in the container
+func (l *LibcontainerExecutor) started(process *libcontainer.Process) (*ProcessState, error) {
 	pid, err := process.Pid()
 	if err != nil {
-		container.Destroy()
+		l.container.Destroy()
 		return nil, err
 	}
 
@@ -322,7 +393,20 @@ func (l *LibcontainerExecutor) Shutdown(signal string, grace time.Duration) erro
 
 // UpdateResources updates the resource isolation with new values to be enforced
 func (l *LibcontainerExecutor) UpdateResources(resources *drivers.Resources) error {
-	return nil
+	if l.container == nil {
+		return fmt.Errorf("container has not been started")
+	}
+
+	// Resources are only enforced if limited
+	if !l.command.ResourceLimits || resources == nil || resources.NomadResources == nil {
+		return nil
+	}
+
+	cfg := l.container.Config()
+	if err := setResourceLimits(cfg.Cgroups.Resources, resources.NomadResources); err != nil {
+		return err
+	}
+	return l.container.Set(cfg)
 }
 
 // Version returns the api version of the executor
@@ -683,8 +767,25 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 		return nil
 	}
 
+	if err := setResourceLimits(cfg.Cgroups.Resources, command.Resources.NomadResources); err != nil {
+		return err
+	}
+
+	if command.Resources.LinuxResources != nil && command.Resources.LinuxResources.CpusetCgroupPath != "" {
+		cfg.Hooks = lconfigs.Hooks{
+			lconfigs.CreateRuntime: lconfigs.HookList{
+				newSetCPUSetCgroupHook(command.Resources.LinuxResources.CpusetCgroupPath),
+			},
+		}
+	}
+
+	return nil
+}
+
+// setResourceLimits sets the memory and CPU limits of the cgroup resources
+// to the task resources.
+func setResourceLimits(cgroupRes *lconfigs.Resources, res *structs.AllocatedTaskResources) error {
 	// Total amount of memory allowed to consume
-	res := command.Resources.NomadResources
 	memHard, memSoft := res.Memory.MemoryMaxMB, res.Memory.MemoryMB
 	if memHard <= 0 {
 		memHard = res.Memory.MemoryMB
@@ -692,12 +793,12 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	}
 
 	if memHard > 0 {
-		cfg.Cgroups.Resources.Memory = memHard * 1024 * 1024
-		cfg.Cgroups.Resources.MemoryReservation = memSoft * 1024 * 1024
+		cgroupRes.Memory = memHard * 1024 * 1024
+		cgroupRes.MemoryReservation = memSoft * 1024 * 1024
 
 		// Disable swap to avoid issues on the machine
 		var memSwappiness uint64
-		cfg.Cgroups.Resources.MemorySwappiness = &memSwappiness
+		cgroupRes.MemorySwappiness = &memSwappiness
 	}
 
 	cpuShares := res.Cpu.CpuShares
@@ -706,17 +807,8 @@ func configureCgroups(cfg *lconfigs.Config, command *ExecCommand) error {
 	}
 
 	// Set the relative CPU shares for this cgroup, and convert for cgroupv2
-	cfg.Cgroups.Resources.CpuShares = uint64(cpuShares)
-	cfg.Cgroups.Resources.CpuWeight = cgroups.ConvertCPUSharesToCgroupV2Value(uint64(cpuShares))
-
-	if command.Resources.LinuxResources != nil && command.Resources.LinuxResources.CpusetCgroupPath != "" {
-		cfg.Hooks = lconfigs.Hooks{
-			lconfigs.CreateRuntime: lconfigs.HookList{
-				newSetCPUSetCgroupHook(command.Resources.LinuxResources.CpusetCgroupPath),
-			},
-		}
-	}
-
+	cgroupRes.CpuShares = uint64(cpuShares)
+	cgroupRes.CpuWeight = cgroups.ConvertCPUSharesToCgroupV2Value(uint64(cpuShares))
 	return nil
 }
 
@@ -32,7 +32,39 @@ type grpcExecutorClient struct {
 
 func (c *grpcExecutorClient) Launch(cmd *ExecCommand) (*ProcessState, error) {
 	ctx := context.Background()
-	req := &proto.LaunchRequest{
+	req := launchRequestToProto(cmd)
+	resp, err := c.client.Launch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := processStateFromProto(resp.Process)
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func (c *grpcExecutorClient) Restore(cmd *ExecCommand, dir string) (*ProcessState, error) {
+	ctx := context.Background()
+	req := &proto.RestoreRequest{
+		Launch: launchRequestToProto(cmd),
+		Dir:    dir,
+	}
+	resp, err := c.client.Restore(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ps, err := processStateFromProto(resp.Process)
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+func launchRequestToProto(cmd *ExecCommand) *proto.LaunchRequest {
+	return &proto.LaunchRequest{
 		Cmd:                cmd.Cmd,
 		Args:               cmd.Args,
 		Resources:          drivers.ResourcesToProto(cmd.Resources),
@@ -51,16 +83,6 @@ func (c *grpcExecutorClient) Launch(cmd *ExecCommand) (*ProcessState, error) {
 		DefaultIpcMode:     cmd.ModeIPC,
 		Capabilities:       cmd.Capabilities,
 	}
-	resp, err := c.client.Launch(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-
-	ps, err := processStateFromProto(resp.Process)
-	if err != nil {
-		return nil, err
-	}
-	return ps, nil
 }
 
 func (c *grpcExecutorClient) Wait(ctx context.Context) (*ProcessState, error) {
@@ -195,6 +217,15 @@ func (c *grpcExecutorClient) Exec(deadline time.Time, cmd string, args []string)
 	return resp.Output, int(resp.ExitCode), nil
 }
 
+func (c *grpcExecutorClient) Checkpoint(dir string) error {
+	ctx := context.Background()
+	if _, err := c.client.Checkpoint(ctx, &proto.CheckpointRequest{Dir: dir}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (c *grpcExecutorClient) ExecStreaming(ctx context.Context,
 	command []string,
 	tty bool,
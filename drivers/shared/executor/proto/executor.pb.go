@@ -786,6 +786,162 @@ func (m *ExecResponse) GetExitCode() int32 {
 	return 0
 }
 
+type CheckpointRequest struct {
+	Dir                  string   `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointRequest) Reset()         { *m = CheckpointRequest{} }
+func (m *CheckpointRequest) String() string { return proto.CompactTextString(m) }
+func (*CheckpointRequest) ProtoMessage()    {}
+func (*CheckpointRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{16}
+}
+
+func (m *CheckpointRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointRequest.Unmarshal(m, b)
+}
+func (m *CheckpointRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointRequest.Marshal(b, m, deterministic)
+}
+func (m *CheckpointRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointRequest.Merge(m, src)
+}
+func (m *CheckpointRequest) XXX_Size() int {
+	return xxx_messageInfo_CheckpointRequest.Size(m)
+}
+func (m *CheckpointRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointRequest proto.InternalMessageInfo
+
+func (m *CheckpointRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type CheckpointResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CheckpointResponse) Reset()         { *m = CheckpointResponse{} }
+func (m *CheckpointResponse) String() string { return proto.CompactTextString(m) }
+func (*CheckpointResponse) ProtoMessage()    {}
+func (*CheckpointResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{17}
+}
+
+func (m *CheckpointResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckpointResponse.Unmarshal(m, b)
+}
+func (m *CheckpointResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckpointResponse.Marshal(b, m, deterministic)
+}
+func (m *CheckpointResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckpointResponse.Merge(m, src)
+}
+func (m *CheckpointResponse) XXX_Size() int {
+	return xxx_messageInfo_CheckpointResponse.Size(m)
+}
+func (m *CheckpointResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckpointResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckpointResponse proto.InternalMessageInfo
+
+type RestoreRequest struct {
+	Launch               *LaunchRequest `protobuf:"bytes,1,opt,name=launch,proto3" json:"launch,omitempty"`
+	Dir                  string         `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *RestoreRequest) Reset()         { *m = RestoreRequest{} }
+func (m *RestoreRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreRequest) ProtoMessage()    {}
+func (*RestoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{18}
+}
+
+func (m *RestoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreRequest.Unmarshal(m, b)
+}
+func (m *RestoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreRequest.Merge(m, src)
+}
+func (m *RestoreRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreRequest.Size(m)
+}
+func (m *RestoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreRequest proto.InternalMessageInfo
+
+func (m *RestoreRequest) GetLaunch() *LaunchRequest {
+	if m != nil {
+		return m.Launch
+	}
+	return nil
+}
+
+func (m *RestoreRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+type RestoreResponse struct {
+	Process              *ProcessState `protobuf:"bytes,1,opt,name=process,proto3" json:"process,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *RestoreResponse) Reset()         { *m = RestoreResponse{} }
+func (m *RestoreResponse) String() string { return proto.CompactTextString(m) }
+func (*RestoreResponse) ProtoMessage()    {}
+func (*RestoreResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_66b85426380683f3, []int{19}
+}
+
+func (m *RestoreResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreResponse.Unmarshal(m, b)
+}
+func (m *RestoreResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreResponse.Marshal(b, m, deterministic)
+}
+func (m *RestoreResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreResponse.Merge(m, src)
+}
+func (m *RestoreResponse) XXX_Size() int {
+	return xxx_messageInfo_RestoreResponse.Size(m)
+}
+func (m *RestoreResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreResponse proto.InternalMessageInfo
+
+func (m *RestoreResponse) GetProcess() *ProcessState {
+	if m != nil {
+		return m.Process
+	}
+	return nil
+}
+
 type ProcessState struct {
 	Pid                  int32                `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
 	ExitCode             int32                `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
@@ -800,7 +956,7 @@ func (m *ProcessState) Reset()         { *m = ProcessState{} }
 func (m *ProcessState) String() string { return proto.CompactTextString(m) }
 func (*ProcessState) ProtoMessage()    {}
 func (*ProcessState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_66b85426380683f3, []int{16}
+	return fileDescriptor_66b85426380683f3, []int{20}
 }
 
 func (m *ProcessState) XXX_Unmarshal(b []byte) error {
@@ -866,6 +1022,10 @@ func init() {
 	proto.RegisterType((*SignalResponse)(nil), "hashicorp.nomad.plugins.executor.proto.SignalResponse")
 	proto.RegisterType((*ExecRequest)(nil), "hashicorp.nomad.plugins.executor.proto.ExecRequest")
 	proto.RegisterType((*ExecResponse)(nil), "hashicorp.nomad.plugins.executor.proto.ExecResponse")
+	proto.RegisterType((*CheckpointRequest)(nil), "hashicorp.nomad.plugins.executor.proto.CheckpointRequest")
+	proto.RegisterType((*CheckpointResponse)(nil), "hashicorp.nomad.plugins.executor.proto.CheckpointResponse")
+	proto.RegisterType((*RestoreRequest)(nil), "hashicorp.nomad.plugins.executor.proto.RestoreRequest")
+	proto.RegisterType((*RestoreResponse)(nil), "hashicorp.nomad.plugins.executor.proto.RestoreResponse")
 	proto.RegisterType((*ProcessState)(nil), "hashicorp.nomad.plugins.executor.proto.ProcessState")
 }
 
@@ -874,74 +1034,79 @@ func init() {
 }
 
 var fileDescriptor_66b85426380683f3 = []byte{
-	// 1058 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xee, 0xc5, 0x89, 0x5f, 0xc6, 0x76, 0xe2, 0x2e, 0xa8, 0x5c, 0x8d, 0x50, 0xcd, 0x21, 0x51,
-	0x0b, 0xca, 0x25, 0x4a, 0xdf, 0x90, 0x90, 0x28, 0x22, 0x2d, 0xa8, 0x52, 0x1a, 0x45, 0x97, 0x42,
-	0x25, 0x3e, 0x70, 0x6c, 0xee, 0xb6, 0xf6, 0x2a, 0xf6, 0xed, 0xb2, 0xbb, 0xe7, 0x04, 0x09, 0x89,
-	0x4f, 0xfc, 0x03, 0x90, 0xf8, 0x31, 0xfc, 0x38, 0xb4, 0x6f, 0x17, 0x3b, 0x2d, 0xd5, 0xb9, 0x88,
-	0x4f, 0xbe, 0x1d, 0x3f, 0xcf, 0xcc, 0xec, 0xce, 0xcc, 0x33, 0x70, 0x27, 0x17, 0x74, 0x41, 0x84,
-	0xdc, 0x95, 0x53, 0x2c, 0x48, 0xbe, 0x4b, 0x2e, 0x48, 0x56, 0x2a, 0x26, 0x76, 0xb9, 0x60, 0x8a,
-	0x55, 0xc7, 0xd8, 0x1c, 0xd1, 0xc7, 0x53, 0x2c, 0xa7, 0x34, 0x63, 0x82, 0xc7, 0x05, 0x9b, 0xe3,
-	0x3c, 0xe6, 0xb3, 0x72, 0x42, 0x0b, 0x19, 0xaf, 0xe2, 0x86, 0xb7, 0x26, 0x8c, 0x4d, 0x66, 0xc4,
-	0x3a, 0x39, 0x2d, 0x5f, 0xee, 0x2a, 0x3a, 0x27, 0x52, 0xe1, 0x39, 0x77, 0x80, 0xc8, 0x11, 0x77,
-	0x7d, 0x78, 0x1b, 0xce, 0x9e, 0x2c, 0x26, 0xfa, 0xbb, 0x09, 0xfd, 0x43, 0x5c, 0x16, 0xd9, 0x34,
-	0x21, 0x3f, 0x97, 0x44, 0x2a, 0x34, 0x80, 0x46, 0x36, 0xcf, 0xc3, 0x60, 0x14, 0x8c, 0x3b, 0x89,
-	0xfe, 0x44, 0x08, 0x36, 0xb1, 0x98, 0xc8, 0x70, 0x63, 0xd4, 0x18, 0x77, 0x12, 0xf3, 0x8d, 0x8e,
-	0xa0, 0x23, 0x88, 0x64, 0xa5, 0xc8, 0x88, 0x0c, 0x1b, 0xa3, 0x60, 0xdc, 0xdd, 0xdf, 0x8b, 0xff,
-	0x2d, 0x71, 0x17, 0xdf, 0x86, 0x8c, 0x13, 0xcf, 0x4b, 0x2e, 0x5d, 0xa0, 0x5b, 0xd0, 0x95, 0x2a,
-	0x67, 0xa5, 0x4a, 0x39, 0x56, 0xd3, 0x70, 0xd3, 0x44, 0x07, 0x6b, 0x3a, 0xc6, 0x6a, 0xea, 0x00,
-	0x44, 0x08, 0x0b, 0xd8, 0xaa, 0x00, 0x44, 0x08, 0x03, 0x18, 0x40, 0x83, 0x14, 0x8b, 0xb0, 0x69,
-	0x92, 0xd4, 0x9f, 0x3a, 0xef, 0x52, 0x12, 0x11, 0xb6, 0x0c, 0xd6, 0x7c, 0xa3, 0x9b, 0xd0, 0x56,
-	0x58, 0x9e, 0xa5, 0x39, 0x15, 0x61, 0xdb, 0xd8, 0x5b, 0xfa, 0xfc, 0x98, 0x0a, 0x74, 0x1b, 0x76,
-	0x7c, 0x3e, 0xe9, 0x8c, 0xce, 0xa9, 0x92, 0x61, 0x67, 0x14, 0x8c, 0xdb, 0xc9, 0xb6, 0x37, 0x1f,
-	0x1a, 0x2b, 0xda, 0x83, 0x77, 0x4f, 0xb1, 0xa4, 0x59, 0xca, 0x05, 0xcb, 0x88, 0x94, 0x69, 0x36,
-	0x11, 0xac, 0xe4, 0x21, 0x18, 0x34, 0x32, 0xff, 0x1d, 0xdb, 0xbf, 0x0e, 0xcc, 0x3f, 0xe8, 0x31,
-	0x34, 0xe7, 0xac, 0x2c, 0x94, 0x0c, 0xbb, 0xa3, 0xc6, 0xb8, 0xbb, 0x7f, 0xa7, 0xe6, 0x53, 0x3d,
-	0xd3, 0xa4, 0xc4, 0x71, 0xd1, 0xb7, 0xd0, 0xca, 0xc9, 0x82, 0xea, 0x17, 0xef, 0x19, 0x37, 0x9f,
-	0xd5, 0x74, 0xf3, 0xd8, 0xb0, 0x12, 0xcf, 0x46, 0x53, 0xb8, 0x5e, 0x10, 0x75, 0xce, 0xc4, 0x59,
-	0x4a, 0x25, 0x9b, 0x61, 0x45, 0x59, 0x11, 0xf6, 0x4d, 0x11, 0xbf, 0xa8, 0xe9, 0xf2, 0xc8, 0xf2,
-	0x9f, 0x7a, 0xfa, 0x09, 0x27, 0x59, 0x32, 0x28, 0xae, 0x58, 0x51, 0x04, 0xfd, 0x82, 0xa5, 0x9c,
-	0x2e, 0x98, 0x4a, 0x05, 0x63, 0x2a, 0xdc, 0x36, 0x6f, 0xd4, 0x2d, 0xd8, 0xb1, 0xb6, 0x25, 0x8c,
-	0x29, 0x34, 0x86, 0x41, 0x4e, 0x5e, 0xe2, 0x72, 0xa6, 0x52, 0x4e, 0xf3, 0x74, 0xce, 0x72, 0x12,
-	0xee, 0x98, 0xd2, 0x6c, 0x3b, 0xfb, 0x31, 0xcd, 0x9f, 0xb1, 0x9c, 0x2c, 0x23, 0x29, 0xcf, 0x2c,
-	0x72, 0xb0, 0x82, 0x7c, 0xca, 0x33, 0x83, 0xfc, 0x08, 0xfa, 0x19, 0x2f, 0x25, 0x51, 0xbe, 0x36,
-	0xd7, 0x0d, 0xac, 0x67, 0x8d, 0xae, 0x2a, 0x1f, 0x00, 0xe0, 0xd9, 0x8c, 0x9d, 0xa7, 0x19, 0xe6,
-	0x32, 0x44, 0xa6, 0x71, 0x3a, 0xc6, 0x72, 0x80, 0xb9, 0x44, 0x11, 0xf4, 0x32, 0xcc, 0xf1, 0x29,
-	0x9d, 0x51, 0x45, 0x89, 0x0c, 0xdf, 0x31, 0x80, 0x15, 0x5b, 0xf4, 0x13, 0x6c, 0xfb, 0xe9, 0x91,
-	0x9c, 0x15, 0x92, 0xa0, 0x23, 0x68, 0xb9, 0xb6, 0x30, 0x23, 0xd4, 0xdd, 0xbf, 0x17, 0xd7, 0x9b,
-	0xe7, 0xd8, 0xb5, 0xcc, 0x89, 0xc2, 0x8a, 0x24, 0xde, 0x49, 0xd4, 0x87, 0xee, 0x0b, 0x4c, 0x95,
-	0x9b, 0xce, 0xe8, 0x47, 0xe8, 0xd9, 0xe3, 0xff, 0x14, 0xee, 0x10, 0x76, 0x4e, 0xa6, 0xa5, 0xca,
-	0xd9, 0x79, 0xe1, 0x05, 0xe1, 0x06, 0x34, 0x25, 0x9d, 0x14, 0x78, 0xe6, 0x34, 0xc1, 0x9d, 0xd0,
-	0x87, 0xd0, 0x9b, 0x08, 0x9c, 0x91, 0x94, 0x13, 0x41, 0x59, 0x1e, 0x6e, 0x8c, 0x82, 0x71, 0x23,
-	0xe9, 0x1a, 0xdb, 0xb1, 0x31, 0x45, 0x08, 0x06, 0x97, 0xde, 0x6c, 0xc6, 0xd1, 0x14, 0x6e, 0x7c,
-	0xc7, 0x73, 0x1d, 0xb4, 0xd2, 0x01, 0x17, 0x68, 0x45, 0x53, 0x82, 0xff, 0xac, 0x29, 0xd1, 0x4d,
-	0x78, 0xef, 0x95, 0x48, 0x2e, 0x89, 0x01, 0x6c, 0x7f, 0x4f, 0x84, 0xa4, 0xcc, 0xdf, 0x32, 0xfa,
-	0x14, 0x76, 0x2a, 0x8b, 0x7b, 0xdb, 0x10, 0x5a, 0x0b, 0x6b, 0x72, 0x37, 0xf7, 0xc7, 0xe8, 0x13,
-	0xe8, 0xe9, 0x77, 0xab, 0x32, 0x1f, 0x42, 0x9b, 0x16, 0x8a, 0x88, 0x85, 0x7b, 0xa4, 0x46, 0x52,
-	0x9d, 0xa3, 0x17, 0xd0, 0x77, 0x58, 0xe7, 0xf6, 0x1b, 0xd8, 0x92, 0xda, 0xb0, 0xe6, 0x15, 0x9f,
-	0x63, 0x79, 0x66, 0x1d, 0x59, 0x7a, 0x74, 0x1b, 0xfa, 0x27, 0xa6, 0x12, 0xaf, 0x2f, 0xd4, 0x96,
-	0x2f, 0x94, 0xbe, 0xac, 0x07, 0xba, 0xeb, 0x9f, 0x41, 0xf7, 0xc9, 0x05, 0xc9, 0x3c, 0xf1, 0x01,
-	0xb4, 0x73, 0x82, 0xf3, 0x19, 0x2d, 0x88, 0x4b, 0x6a, 0x18, 0xdb, 0xe5, 0x12, 0xfb, 0xe5, 0x12,
-	0x3f, 0xf7, 0xcb, 0x25, 0xa9, 0xb0, 0x7e, 0x55, 0x6c, 0xbc, 0xba, 0x2a, 0x1a, 0x97, 0xab, 0x22,
-	0x3a, 0x80, 0x9e, 0x0d, 0xe6, 0xee, 0x7f, 0x03, 0x9a, 0xac, 0x54, 0xbc, 0x54, 0x26, 0x56, 0x2f,
-	0x71, 0x27, 0xf4, 0x3e, 0x74, 0xc8, 0x05, 0x55, 0x69, 0xa6, 0xc7, 0x7a, 0xc3, 0xdc, 0xa0, 0xad,
-	0x0d, 0x07, 0x2c, 0x27, 0xd1, 0xef, 0x01, 0xf4, 0x96, 0x3b, 0x56, 0xc7, 0xe6, 0x34, 0x77, 0x37,
-	0xd5, 0x9f, 0x6f, 0xe4, 0x2f, 0xbd, 0x4d, 0x63, 0xf9, 0x6d, 0x50, 0x0c, 0x9b, 0x7a, 0x6d, 0x9a,
-	0x85, 0xf3, 0xe6, 0x6b, 0x1b, 0xdc, 0xfe, 0x9f, 0x1d, 0x68, 0x3f, 0x71, 0x83, 0x84, 0x7e, 0x81,
-	0xa6, 0x9d, 0x7e, 0x74, 0xbf, 0xee, 0xd4, 0xad, 0xec, 0xda, 0xe1, 0x83, 0x75, 0x69, 0xae, 0x7e,
-	0xd7, 0x90, 0x84, 0x4d, 0xad, 0x03, 0xe8, 0x6e, 0x5d, 0x0f, 0x4b, 0x22, 0x32, 0xbc, 0xb7, 0x1e,
-	0xa9, 0x0a, 0xfa, 0x1b, 0xb4, 0xfd, 0x38, 0xa3, 0x87, 0x75, 0x7d, 0x5c, 0x91, 0x93, 0xe1, 0xe7,
-	0xeb, 0x13, 0xab, 0x04, 0xfe, 0x08, 0x60, 0xe7, 0xca, 0x48, 0xa3, 0x2f, 0xeb, 0xfa, 0x7b, 0xbd,
-	0xea, 0x0c, 0x1f, 0xbd, 0x35, 0xbf, 0x4a, 0xeb, 0x57, 0x68, 0x39, 0xed, 0x40, 0xb5, 0x2b, 0xba,
-	0x2a, 0x3f, 0xc3, 0x87, 0x6b, 0xf3, 0xaa, 0xe8, 0x17, 0xb0, 0x65, 0x74, 0x01, 0xd5, 0x2e, 0xeb,
-	0xb2, 0x76, 0x0d, 0xef, 0xaf, 0xc9, 0xf2, 0x71, 0xf7, 0x02, 0xdd, 0xff, 0x56, 0x58, 0xea, 0xf7,
-	0xff, 0x8a, 0x62, 0xd5, 0xef, 0xff, 0x2b, 0xfa, 0x65, 0xfa, 0x5f, 0x8f, 0x61, 0xfd, 0xfe, 0x5f,
-	0xd2, 0xbb, 0xfa, 0xfd, 0xbf, 0xac, 0x5b, 0xd1, 0x35, 0xf4, 0x57, 0x00, 0x7d, 0x6d, 0x3a, 0x51,
-	0x82, 0xe0, 0x39, 0x2d, 0x26, 0xe8, 0x51, 0x4d, 0xf1, 0xd6, 0x2c, 0x2b, 0xe0, 0x8e, 0xe9, 0x53,
-	0xf9, 0xea, 0xed, 0x1d, 0xf8, 0xb4, 0xc6, 0xc1, 0x5e, 0xf0, 0x75, 0xeb, 0x87, 0x2d, 0xab, 0x59,
-	0x4d, 0xf3, 0x73, 0xf7, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x2e, 0x63, 0xc4, 0xd3, 0x74, 0x0c,
-	0x00, 0x00,
+	// 1148 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x6d, 0x6f, 0xdc, 0x44,
+	0x10, 0xc6, 0xb9, 0xe4, 0x5e, 0xe6, 0x5e, 0xb3, 0x54, 0xc1, 0x35, 0x42, 0x3d, 0x8c, 0xa0, 0x27,
+	0x28, 0x4e, 0x94, 0xa6, 0x09, 0x2f, 0x12, 0x45, 0x24, 0x05, 0x55, 0x4a, 0xa2, 0xc8, 0x29, 0x54,
+	0xe2, 0x03, 0x66, 0x63, 0x6f, 0xef, 0x56, 0xb9, 0xf3, 0xba, 0xbb, 0xeb, 0x4b, 0x90, 0x90, 0xf8,
+	0xd4, 0x7f, 0xc0, 0x07, 0x24, 0xfe, 0x0a, 0x3f, 0x0e, 0x79, 0xbd, 0xeb, 0xf8, 0x92, 0x02, 0xbe,
+	0xa0, 0x7e, 0xba, 0xdd, 0xf1, 0xf3, 0xcc, 0xcc, 0xee, 0xcc, 0x3e, 0x73, 0xf0, 0x20, 0xe2, 0x74,
+	0x4e, 0xb8, 0xd8, 0x14, 0x13, 0xcc, 0x49, 0xb4, 0x49, 0x2e, 0x49, 0x98, 0x4a, 0xc6, 0x37, 0x13,
+	0xce, 0x24, 0x2b, 0xb6, 0x9e, 0xda, 0xa2, 0x8f, 0x26, 0x58, 0x4c, 0x68, 0xc8, 0x78, 0xe2, 0xc5,
+	0x6c, 0x86, 0x23, 0x2f, 0x99, 0xa6, 0x63, 0x1a, 0x0b, 0x6f, 0x11, 0xe7, 0xdc, 0x1b, 0x33, 0x36,
+	0x9e, 0x92, 0xdc, 0xc9, 0x59, 0xfa, 0x62, 0x53, 0xd2, 0x19, 0x11, 0x12, 0xcf, 0x12, 0x0d, 0x70,
+	0x35, 0x71, 0xd3, 0x84, 0xcf, 0xc3, 0xe5, 0xbb, 0x1c, 0xe3, 0xfe, 0x55, 0x87, 0xee, 0x21, 0x4e,
+	0xe3, 0x70, 0xe2, 0x93, 0x97, 0x29, 0x11, 0x12, 0x0d, 0xa0, 0x16, 0xce, 0x22, 0xdb, 0x1a, 0x5a,
+	0xa3, 0x96, 0x9f, 0x2d, 0x11, 0x82, 0x55, 0xcc, 0xc7, 0xc2, 0x5e, 0x19, 0xd6, 0x46, 0x2d, 0x5f,
+	0xad, 0xd1, 0x31, 0xb4, 0x38, 0x11, 0x2c, 0xe5, 0x21, 0x11, 0x76, 0x6d, 0x68, 0x8d, 0xda, 0xdb,
+	0x5b, 0xde, 0x3f, 0x25, 0xae, 0xe3, 0xe7, 0x21, 0x3d, 0xdf, 0xf0, 0xfc, 0x2b, 0x17, 0xe8, 0x1e,
+	0xb4, 0x85, 0x8c, 0x58, 0x2a, 0x83, 0x04, 0xcb, 0x89, 0xbd, 0xaa, 0xa2, 0x43, 0x6e, 0x3a, 0xc1,
+	0x72, 0xa2, 0x01, 0x84, 0xf3, 0x1c, 0xb0, 0x56, 0x00, 0x08, 0xe7, 0x0a, 0x30, 0x80, 0x1a, 0x89,
+	0xe7, 0x76, 0x5d, 0x25, 0x99, 0x2d, 0xb3, 0xbc, 0x53, 0x41, 0xb8, 0xdd, 0x50, 0x58, 0xb5, 0x46,
+	0x77, 0xa1, 0x29, 0xb1, 0x38, 0x0f, 0x22, 0xca, 0xed, 0xa6, 0xb2, 0x37, 0xb2, 0xfd, 0x01, 0xe5,
+	0xe8, 0x3e, 0xf4, 0x4d, 0x3e, 0xc1, 0x94, 0xce, 0xa8, 0x14, 0x76, 0x6b, 0x68, 0x8d, 0x9a, 0x7e,
+	0xcf, 0x98, 0x0f, 0x95, 0x15, 0x6d, 0xc1, 0x9d, 0x33, 0x2c, 0x68, 0x18, 0x24, 0x9c, 0x85, 0x44,
+	0x88, 0x20, 0x1c, 0x73, 0x96, 0x26, 0x36, 0x28, 0x34, 0x52, 0xdf, 0x4e, 0xf2, 0x4f, 0xfb, 0xea,
+	0x0b, 0x3a, 0x80, 0xfa, 0x8c, 0xa5, 0xb1, 0x14, 0x76, 0x7b, 0x58, 0x1b, 0xb5, 0xb7, 0x1f, 0x54,
+	0xbc, 0xaa, 0xa3, 0x8c, 0xe4, 0x6b, 0x2e, 0xfa, 0x0e, 0x1a, 0x11, 0x99, 0xd3, 0xec, 0xc6, 0x3b,
+	0xca, 0xcd, 0xa7, 0x15, 0xdd, 0x1c, 0x28, 0x96, 0x6f, 0xd8, 0x68, 0x02, 0xeb, 0x31, 0x91, 0x17,
+	0x8c, 0x9f, 0x07, 0x54, 0xb0, 0x29, 0x96, 0x94, 0xc5, 0x76, 0x57, 0x15, 0xf1, 0xcb, 0x8a, 0x2e,
+	0x8f, 0x73, 0xfe, 0x53, 0x43, 0x3f, 0x4d, 0x48, 0xe8, 0x0f, 0xe2, 0x6b, 0x56, 0xe4, 0x42, 0x37,
+	0x66, 0x41, 0x42, 0xe7, 0x4c, 0x06, 0x9c, 0x31, 0x69, 0xf7, 0xd4, 0x1d, 0xb5, 0x63, 0x76, 0x92,
+	0xd9, 0x7c, 0xc6, 0x24, 0x1a, 0xc1, 0x20, 0x22, 0x2f, 0x70, 0x3a, 0x95, 0x41, 0x42, 0xa3, 0x60,
+	0xc6, 0x22, 0x62, 0xf7, 0x55, 0x69, 0x7a, 0xda, 0x7e, 0x42, 0xa3, 0x23, 0x16, 0x91, 0x32, 0x92,
+	0x26, 0x61, 0x8e, 0x1c, 0x2c, 0x20, 0x9f, 0x26, 0xa1, 0x42, 0x7e, 0x00, 0xdd, 0x30, 0x49, 0x05,
+	0x91, 0xa6, 0x36, 0xeb, 0x0a, 0xd6, 0xc9, 0x8d, 0xba, 0x2a, 0xef, 0x01, 0xe0, 0xe9, 0x94, 0x5d,
+	0x04, 0x21, 0x4e, 0x84, 0x8d, 0x54, 0xe3, 0xb4, 0x94, 0x65, 0x1f, 0x27, 0x02, 0xb9, 0xd0, 0x09,
+	0x71, 0x82, 0xcf, 0xe8, 0x94, 0x4a, 0x4a, 0x84, 0xfd, 0xb6, 0x02, 0x2c, 0xd8, 0xdc, 0x9f, 0xa1,
+	0x67, 0x5e, 0x8f, 0x48, 0x58, 0x2c, 0x08, 0x3a, 0x86, 0x86, 0x6e, 0x0b, 0xf5, 0x84, 0xda, 0xdb,
+	0x3b, 0x5e, 0xb5, 0xf7, 0xec, 0xe9, 0x96, 0x39, 0x95, 0x58, 0x12, 0xdf, 0x38, 0x71, 0xbb, 0xd0,
+	0x7e, 0x8e, 0xa9, 0xd4, 0xaf, 0xd3, 0xfd, 0x09, 0x3a, 0xf9, 0xf6, 0x0d, 0x85, 0x3b, 0x84, 0xfe,
+	0xe9, 0x24, 0x95, 0x11, 0xbb, 0x88, 0x8d, 0x20, 0x6c, 0x40, 0x5d, 0xd0, 0x71, 0x8c, 0xa7, 0x5a,
+	0x13, 0xf4, 0x0e, 0xbd, 0x0f, 0x9d, 0x31, 0xc7, 0x21, 0x09, 0x12, 0xc2, 0x29, 0x8b, 0xec, 0x95,
+	0xa1, 0x35, 0xaa, 0xf9, 0x6d, 0x65, 0x3b, 0x51, 0x26, 0x17, 0xc1, 0xe0, 0xca, 0x5b, 0x9e, 0xb1,
+	0x3b, 0x81, 0x8d, 0xef, 0x93, 0x28, 0x0b, 0x5a, 0xe8, 0x80, 0x0e, 0xb4, 0xa0, 0x29, 0xd6, 0xff,
+	0xd6, 0x14, 0xf7, 0x2e, 0xbc, 0x73, 0x23, 0x92, 0x4e, 0x62, 0x00, 0xbd, 0x1f, 0x08, 0x17, 0x94,
+	0x99, 0x53, 0xba, 0x9f, 0x40, 0xbf, 0xb0, 0xe8, 0xbb, 0xb5, 0xa1, 0x31, 0xcf, 0x4d, 0xfa, 0xe4,
+	0x66, 0xeb, 0x7e, 0x0c, 0x9d, 0xec, 0xde, 0x8a, 0xcc, 0x1d, 0x68, 0xd2, 0x58, 0x12, 0x3e, 0xd7,
+	0x97, 0x54, 0xf3, 0x8b, 0xbd, 0xfb, 0x1c, 0xba, 0x1a, 0xab, 0xdd, 0x7e, 0x0b, 0x6b, 0x22, 0x33,
+	0x2c, 0x79, 0xc4, 0x67, 0x58, 0x9c, 0xe7, 0x8e, 0x72, 0xba, 0x7b, 0x1f, 0xba, 0xa7, 0xaa, 0x12,
+	0xaf, 0x2f, 0xd4, 0x9a, 0x29, 0x54, 0x76, 0x58, 0x03, 0xd4, 0xc7, 0x3f, 0x87, 0xf6, 0x93, 0x4b,
+	0x12, 0x1a, 0xe2, 0x2e, 0x34, 0x23, 0x82, 0xa3, 0x29, 0x8d, 0x89, 0x4e, 0xca, 0xf1, 0xf2, 0xe1,
+	0xe2, 0x99, 0xe1, 0xe2, 0x3d, 0x33, 0xc3, 0xc5, 0x2f, 0xb0, 0x66, 0x54, 0xac, 0xdc, 0x1c, 0x15,
+	0xb5, 0xab, 0x51, 0xe1, 0xee, 0x43, 0x27, 0x0f, 0xa6, 0xcf, 0xbf, 0x01, 0x75, 0x96, 0xca, 0x24,
+	0x95, 0x2a, 0x56, 0xc7, 0xd7, 0x3b, 0xf4, 0x2e, 0xb4, 0xc8, 0x25, 0x95, 0x41, 0x98, 0x3d, 0xeb,
+	0x15, 0x75, 0x82, 0x66, 0x66, 0xd8, 0x67, 0x11, 0x71, 0x3f, 0x84, 0xf5, 0xfd, 0x09, 0x09, 0xcf,
+	0x13, 0x46, 0x63, 0x59, 0x1a, 0x55, 0x99, 0x8e, 0xeb, 0x51, 0x15, 0x51, 0xee, 0xde, 0x01, 0x54,
+	0x86, 0xe9, 0xe3, 0xbe, 0x84, 0x9e, 0x4f, 0x84, 0x64, 0x9c, 0x18, 0xe6, 0x11, 0xd4, 0xa7, 0xea,
+	0xdd, 0xea, 0xf3, 0x3e, 0xaa, 0xfa, 0x6a, 0x16, 0x66, 0xa5, 0xaf, 0x9d, 0x98, 0x44, 0x56, 0xae,
+	0x12, 0xc1, 0xd0, 0x2f, 0x42, 0xbe, 0xa1, 0xa7, 0xfa, 0xca, 0x82, 0x4e, 0xf9, 0x4b, 0x96, 0x45,
+	0x42, 0x23, 0x5d, 0xfc, 0x6c, 0xf9, 0xaf, 0x57, 0x5a, 0x6a, 0x97, 0x5a, 0xb9, 0x5d, 0x90, 0x07,
+	0xab, 0xd9, 0x3f, 0x09, 0x7b, 0xf5, 0x3f, 0x3b, 0x41, 0xe1, 0xb6, 0xff, 0x6c, 0x43, 0xf3, 0x89,
+	0x4e, 0x18, 0xfd, 0x02, 0xf5, 0xfc, 0x8a, 0xd0, 0xed, 0xae, 0xd4, 0xd9, 0x5d, 0x96, 0xa6, 0x6b,
+	0xfc, 0x16, 0x12, 0xb0, 0x9a, 0x49, 0x23, 0x7a, 0x58, 0xd5, 0x43, 0x49, 0x57, 0x9d, 0x9d, 0xe5,
+	0x48, 0x45, 0xd0, 0xdf, 0xa0, 0x69, 0x14, 0x0e, 0xed, 0x55, 0xf5, 0x71, 0x4d, 0x61, 0x9d, 0xcf,
+	0x96, 0x27, 0x16, 0x09, 0xfc, 0x6e, 0x41, 0xff, 0x9a, 0xca, 0xa1, 0xaf, 0xaa, 0xfa, 0x7b, 0xbd,
+	0x10, 0x3b, 0x8f, 0x6f, 0xcd, 0x2f, 0xd2, 0xfa, 0x15, 0x1a, 0x5a, 0x4e, 0x51, 0xe5, 0x8a, 0x2e,
+	0x2a, 0xb2, 0xb3, 0xb7, 0x34, 0xaf, 0x88, 0x7e, 0x09, 0x6b, 0x4a, 0x2a, 0x51, 0xe5, 0xb2, 0x96,
+	0xe5, 0xdc, 0x79, 0xb4, 0x24, 0xcb, 0xc4, 0xdd, 0xb2, 0xb2, 0xfe, 0xcf, 0xb5, 0xb6, 0x7a, 0xff,
+	0x2f, 0x88, 0xb8, 0xb3, 0xbb, 0x2c, 0xad, 0xdc, 0xff, 0xd9, 0x33, 0xac, 0xde, 0xff, 0xa5, 0x11,
+	0xe0, 0xec, 0x2c, 0x47, 0x2a, 0x82, 0xbe, 0xb2, 0x00, 0xae, 0x14, 0x17, 0x7d, 0x5e, 0xd5, 0xcd,
+	0x0d, 0x31, 0x77, 0xbe, 0xb8, 0x0d, 0xb5, 0xdc, 0x6f, 0x5a, 0x6f, 0xab, 0xf7, 0xdb, 0xe2, 0x4c,
+	0x70, 0xf6, 0x96, 0xe6, 0x15, 0xd1, 0xff, 0xb0, 0xa0, 0x9b, 0x5d, 0xcc, 0xa9, 0xe4, 0x04, 0xcf,
+	0x68, 0x3c, 0x46, 0x8f, 0x2b, 0x4e, 0xf5, 0x8c, 0x95, 0x4f, 0x76, 0xcd, 0x34, 0xd9, 0x7c, 0x7d,
+	0x7b, 0x07, 0x26, 0xad, 0x91, 0xb5, 0x65, 0x7d, 0xd3, 0xf8, 0x71, 0x2d, 0x57, 0xee, 0xba, 0xfa,
+	0x79, 0xf8, 0xf7, 0x00, 0x80, 0x57, 0x28, 0x4a, 0x8d, 0x0e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -964,6 +1129,8 @@ type ExecutorClient interface {
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (Executor_StatsClient, error)
 	Signal(ctx context.Context, in *SignalRequest, opts ...grpc.CallOption) (*SignalResponse, error)
 	Exec(ctx context.Context, in *ExecRequest, opts ...grpc.CallOption) (*ExecResponse, error)
+	Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error)
+	Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error)
 	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	ExecStreaming(ctx context.Context, opts ...grpc.CallOption) (Executor_ExecStreamingClient, error)
 }
@@ -1071,6 +1238,24 @@ func (c *executorClient) Exec(ctx context.Context, in *ExecRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *executorClient) Checkpoint(ctx context.Context, in *CheckpointRequest, opts ...grpc.CallOption) (*CheckpointResponse, error) {
+	out := new(CheckpointResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.executor.proto.Executor/Checkpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *executorClient) Restore(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreResponse, error) {
+	out := new(RestoreResponse)
+	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.executor.proto.Executor/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *executorClient) ExecStreaming(ctx context.Context, opts ...grpc.CallOption) (Executor_ExecStreamingClient, error) {
 	stream, err := c.cc.NewStream(ctx, &_Executor_serviceDesc.Streams[1], "/hashicorp.nomad.plugins.executor.proto.Executor/ExecStreaming", opts...)
 	if err != nil {
@@ -1112,6 +1297,8 @@ type ExecutorServer interface {
 	Stats(*StatsRequest, Executor_StatsServer) error
 	Signal(context.Context, *SignalRequest) (*SignalResponse, error)
 	Exec(context.Context, *ExecRequest) (*ExecResponse, error)
+	Checkpoint(context.Context, *CheckpointRequest) (*CheckpointResponse, error)
+	Restore(context.Context, *RestoreRequest) (*RestoreResponse, error)
 	// buf:lint:ignore RPC_REQUEST_RESPONSE_UNIQUE
 	ExecStreaming(Executor_ExecStreamingServer) error
 }
@@ -1144,6 +1331,12 @@ func (*UnimplementedExecutorServer) Signal(ctx context.Context, req *SignalReque
 func (*UnimplementedExecutorServer) Exec(ctx context.Context, req *ExecRequest) (*ExecResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Exec not implemented")
 }
+func (*UnimplementedExecutorServer) Checkpoint(ctx context.Context, req *CheckpointRequest) (*CheckpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkpoint not implemented")
+}
+func (*UnimplementedExecutorServer) Restore(ctx context.Context, req *RestoreRequest) (*RestoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
 func (*UnimplementedExecutorServer) ExecStreaming(srv Executor_ExecStreamingServer) error {
 	return status.Errorf(codes.Unimplemented, "method ExecStreaming not implemented")
 }
@@ -1299,6 +1492,42 @@ func _Executor_Exec_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Executor_Checkpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Checkpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.executor.proto.Executor/Checkpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Checkpoint(ctx, req.(*CheckpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Executor_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutorServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hashicorp.nomad.plugins.executor.proto.Executor/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutorServer).Restore(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Executor_ExecStreaming_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(ExecutorServer).ExecStreaming(&executorExecStreamingServer{stream})
 }
@@ -1357,6 +1586,14 @@ var _Executor_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Exec",
 			Handler:    _Executor_Exec_Handler,
 		},
+		{
+			MethodName: "Checkpoint",
+			Handler:    _Executor_Checkpoint_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Executor_Restore_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
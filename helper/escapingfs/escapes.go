@@ -49,15 +49,20 @@ func pathEscapesBaseViaSymlink(base, full string) (bool, error) {
 		return false, err
 	}
 
-	rel, err := filepath.Rel(resolveSym, base)
+	// Resolve the base as well, so it compares with the resolved path.
+	resolveBase, err := filepath.EvalSymlinks(base)
+	if err != nil {
+		return false, err
+	}
+
+	rel, err := filepath.Rel(resolveBase, resolveSym)
 	if err != nil {
 		return true, nil
 	}
 
-	// note: this is not the same as !filesystem.IsAbs; we are asking if the relative
-	// path is descendent of the base path, indicating it does not escape.
-	isRelative := strings.HasPrefix(rel, "..") || rel == "."
-	escapes := !isRelative
+	// note: the path is a descendent of the base path unless the relative path
+	// leads out of it, in which case it escapes.
+	escapes := rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
 	return escapes, nil
 }
 
@@ -62,6 +62,22 @@ func Test_pathEscapesBaseViaSymlink(t *testing.T) {
 		require.True(t, escape)
 	})
 
+	t.Run("symlink-escape-sibling", func(t *testing.T) {
+		dir := t.TempDir()
+
+		// link from dir/link
+		link := filepath.Join(dir, "link")
+
+		// link to a directory next to dir
+		target := t.TempDir()
+		err := os.Symlink(target, link)
+		require.NoError(t, err)
+
+		escape, err := pathEscapesBaseViaSymlink(dir, link)
+		require.NoError(t, err)
+		require.True(t, escape)
+	})
+
 	t.Run("symlink-noescape", func(t *testing.T) {
 		dir := t.TempDir()
 
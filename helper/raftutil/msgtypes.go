@@ -54,6 +54,10 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         "ServiceRegistrationUpsertRequestType",
 	structs.ServiceRegistrationDeleteByIDRequestType:     "ServiceRegistrationDeleteByIDRequestType",
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: "ServiceRegistrationDeleteByNodeIDRequestType",
+	structs.CSIVolumeUpdateStatsRequestType:              "CSIVolumeUpdateStatsRequestType",
+	structs.NodeMaintenanceRequestType:                   "NodeMaintenanceRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
+	structs.QuotaSpecUpsertRequestType:                   "QuotaSpecUpsertRequestType",
+	structs.QuotaSpecDeleteRequestType:                   "QuotaSpecDeleteRequestType",
 }
@@ -0,0 +1,191 @@
+// Package tarfs archives directory trees as tar streams and extracts them,
+// guarding against archives writing outside of their destination.
+package tarfs
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper/escapingfs"
+)
+
+// Archive writes a tar archive of the file or directory at src to w. The
+// entries are named relative to the parent of src, with src itself renamed to
+// name. Paths for which skip returns true are left out of the archive, along
+// with their contents. Devices, sockets and named pipes are always left out.
+func Archive(w io.Writer, src, name string, skip func(path string) bool) error {
+	tw := tar.NewWriter(w)
+
+	walkFn := func(p string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if skip != nil && skip(p) {
+			if fileInfo.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		switch mode := fileInfo.Mode(); {
+		case mode.IsRegular(), mode.IsDir():
+		case mode&os.ModeSymlink != 0:
+			if link, err = os.Readlink(p); err != nil {
+				return fmt.Errorf("error reading symlink: %v", err)
+			}
+		default:
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return fmt.Errorf("error creating file header: %v", err)
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if fileInfo.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	}
+
+	if err := filepath.Walk(src, walkFn); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// Extract extracts the tar archive read from r into the existing directory
+// dst, which must be an absolute path. If name isn't empty, the top level entry
+// of the archive is renamed to it.
+//
+// Entries escaping dst, either through relative paths or symlinks, are
+// rejected, as are entries for which deny returns true, and links whose target
+// deny returns true for. Where supported, the
+// paths of the entries are resolved without following symlinks, so that dst
+// can't be escaped by swapping its directories for symlinks while it's being
+// extracted to.
+func Extract(r io.Reader, dst, name string, deny func(path string) bool) error {
+	if fi, err := os.Stat(dst); err != nil {
+		return err
+	} else if !fi.IsDir() {
+		return fmt.Errorf("%s is not a directory", dst)
+	}
+
+	root, err := openRoot(dst)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Global headers only hold metadata
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		rel := entryPath(hdr.Name, name)
+		if rel == "." {
+			continue
+		}
+
+		target := filepath.Join(dst, rel)
+		if deny != nil && deny(target) {
+			return fmt.Errorf("writing to %s prohibited", rel)
+		}
+		if err := checkEntry(hdr, rel); err != nil {
+			return fmt.Errorf("failed to extract %s: %v", rel, err)
+		}
+		if link := linkTarget(hdr, rel, name); link != "" && deny != nil && deny(filepath.Join(dst, link)) {
+			return fmt.Errorf("linking %s to %s prohibited", rel, link)
+		}
+		if err := root.extractEntry(tr, hdr, rel, name); err != nil {
+			return fmt.Errorf("failed to extract %s: %v", rel, err)
+		}
+	}
+}
+
+// entryPath returns the path of the tar entry relative to the destination,
+// with its top level directory renamed to name if it isn't empty.
+func entryPath(entry, name string) string {
+	// Cleaning the entry as an absolute path removes any leading ".."
+	rel := strings.TrimPrefix(path.Clean("/"+entry), "/")
+	if rel == "" {
+		return "."
+	}
+	if name != "" {
+		parts := strings.SplitN(rel, "/", 2)
+		parts[0] = name
+		rel = path.Join(parts...)
+	}
+	return filepath.FromSlash(rel)
+}
+
+// linkTarget returns the path relative to the destination that the tar entry
+// links to, or an empty string if it isn't a link. Like entry names, hard link
+// targets are relative to the root of the archive, while symlink targets are
+// relative to the directory of the symlink.
+func linkTarget(hdr *tar.Header, rel, name string) string {
+	switch hdr.Typeflag {
+	case tar.TypeLink:
+		return entryPath(hdr.Linkname, name)
+	case tar.TypeSymlink:
+		return filepath.Join(filepath.Dir(rel), hdr.Linkname)
+	}
+	return ""
+}
+
+// checkEntry returns an error if the type of the tar entry isn't supported, or
+// if it's a symlink whose target escapes the destination.
+func checkEntry(hdr *tar.Header, rel string) error {
+	switch hdr.Typeflag {
+	case tar.TypeDir, tar.TypeReg, tar.TypeRegA, tar.TypeLink:
+	case tar.TypeSymlink:
+		if filepath.IsAbs(hdr.Linkname) {
+			return fmt.Errorf("absolute symlink target %s", hdr.Linkname)
+		}
+		if escapes, err := escapingfs.PathEscapesAllocViaRelative(filepath.Dir(rel), hdr.Linkname); err != nil {
+			return err
+		} else if escapes {
+			return fmt.Errorf("symlink target %s escapes the destination", hdr.Linkname)
+		}
+	default:
+		return fmt.Errorf("unsupported entry type %q", hdr.Typeflag)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+
+package tarfs
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/helper/escapingfs"
+)
+
+// extractRoot is the destination of an extraction. Entries are created by
+// path, after checking that their parent directories don't escape the
+// destination.
+type extractRoot struct {
+	dst string
+}
+
+func openRoot(dst string) (*extractRoot, error) {
+	return &extractRoot{dst: dst}, nil
+}
+
+func (r *extractRoot) Close() error {
+	return nil
+}
+
+// checkParents returns an error if any of the parent directories of the path
+// relative to the destination escapes it, including through symlinks.
+func (r *extractRoot) checkParents(rel string) error {
+	for p := filepath.Dir(rel); p != "."; p = filepath.Dir(p) {
+		if escapes, err := escapingfs.PathEscapesAllocDir(r.dst, "", p); err != nil {
+			return fmt.Errorf("failed to check if path escapes the destination: %v", err)
+		} else if escapes {
+			return fmt.Errorf("path %s escapes the destination", rel)
+		}
+	}
+	return nil
+}
+
+// extractEntry creates the file described by the tar header at the path
+// relative to the destination.
+func (r *extractRoot) extractEntry(tr *tar.Reader, hdr *tar.Header, rel, name string) error {
+	if err := r.checkParents(rel); err != nil {
+		return err
+	}
+
+	target := filepath.Join(r.dst, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// Replace existing files rather than writing through them, as they may be
+	// symlinks pointing outside of the destination. Directories are merged.
+	if fi, err := os.Lstat(target); err == nil {
+		switch {
+		case fi.IsDir() && hdr.Typeflag == tar.TypeDir:
+		case fi.IsDir():
+			return fmt.Errorf("cannot overwrite directory with non-directory")
+		default:
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+		}
+	}
+
+	mode := os.FileMode(hdr.Mode).Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(target, mode); err != nil {
+			return err
+		}
+		return os.Chmod(target, mode)
+	case tar.TypeReg, tar.TypeRegA:
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Chtimes(target, hdr.ModTime, hdr.ModTime)
+	case tar.TypeSymlink:
+		return os.Symlink(hdr.Linkname, target)
+	case tar.TypeLink:
+		// Hard links are relative to the root of the archive
+		linkname := linkTarget(hdr, rel, name)
+		if escapes, err := escapingfs.PathEscapesAllocDir(r.dst, "", linkname); err != nil {
+			return err
+		} else if escapes {
+			return fmt.Errorf("link target %s escapes the destination", hdr.Linkname)
+		}
+		return os.Link(filepath.Join(r.dst, linkname), target)
+	}
+	return nil
+}
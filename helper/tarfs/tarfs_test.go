@@ -0,0 +1,250 @@
+package tarfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_Extract(t *testing.T) {
+	ci.Parallel(t)
+
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "conf", "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "conf", "a.txt"), []byte("a"), 0640))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "conf", "sub", "b.txt"), []byte("b"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "conf", "skipped"), []byte("c"), 0600))
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(src, "conf", "link")))
+
+	var buf bytes.Buffer
+	skip := func(path string) bool { return filepath.Base(path) == "skipped" }
+	require.NoError(t, Archive(&buf, filepath.Join(src, "conf"), "conf", skip))
+
+	// Extracting renames the top level directory
+	dst := t.TempDir()
+	require.NoError(t, Extract(&buf, dst, "renamed", nil))
+
+	data, err := os.ReadFile(filepath.Join(dst, "renamed", "sub", "b.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "b", string(data))
+
+	fi, err := os.Stat(filepath.Join(dst, "renamed", "a.txt"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dst, "renamed", "link"))
+	require.NoError(t, err)
+	require.Equal(t, "a.txt", link)
+
+	require.NoFileExists(t, filepath.Join(dst, "renamed", "skipped"))
+}
+
+func TestExtract_Escapes(t *testing.T) {
+	ci.Parallel(t)
+
+	archive := func(hdrs ...*tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range hdrs {
+			hdr.Mode = 0644
+			require.NoError(t, tw.WriteHeader(hdr))
+		}
+		require.NoError(t, tw.Close())
+		return &buf
+	}
+
+	outside := t.TempDir()
+
+	cases := []struct {
+		name string
+		hdrs []*tar.Header
+		err  string
+	}{
+		{
+			name: "relative path",
+			hdrs: []*tar.Header{{Name: "../../escaped", Typeflag: tar.TypeReg}},
+		},
+		{
+			name: "absolute symlink",
+			hdrs: []*tar.Header{{Name: "link", Linkname: outside, Typeflag: tar.TypeSymlink}},
+			err:  "absolute symlink target",
+		},
+		{
+			name: "relative symlink",
+			hdrs: []*tar.Header{{Name: "dir/link", Linkname: "../../", Typeflag: tar.TypeSymlink}},
+			err:  "escapes the destination",
+		},
+		{
+			name: "hard link",
+			hdrs: []*tar.Header{{Name: "link", Linkname: "../../etc/passwd", Typeflag: tar.TypeLink}},
+			err:  "no such file",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := t.TempDir()
+			err := Extract(archive(tc.hdrs...), dst, "", nil)
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.FileExists(t, filepath.Join(dst, "escaped"))
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	// Existing symlinks pointing outside of the destination aren't followed
+	dst := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(dst, "link")))
+	err := Extract(archive(&tar.Header{Name: "link/file", Typeflag: tar.TypeReg}), dst, "", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "escapes the destination")
+	require.NoFileExists(t, filepath.Join(outside, "file"))
+
+	// Denied paths are rejected
+	deny := func(path string) bool { return strings.HasSuffix(path, "secret") }
+	err = Extract(archive(&tar.Header{Name: "secret", Typeflag: tar.TypeReg}), dst, "", deny)
+	require.EqualError(t, err, "writing to secret prohibited")
+}
+
+// swapReader calls swap once reading from r goes past offset.
+type swapReader struct {
+	r      io.Reader
+	offset int
+	swap   func()
+	read   int
+}
+
+func (s *swapReader) Read(p []byte) (int, error) {
+	if s.swap != nil && s.read >= s.offset {
+		s.swap()
+		s.swap = nil
+	}
+	n, err := s.r.Read(p)
+	s.read += n
+	return n, err
+}
+
+func TestExtract_SymlinkSwap(t *testing.T) {
+	ci.Parallel(t)
+
+	archive := func(names ...string) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, name := range names {
+			hdr := &tar.Header{Name: name, Mode: 0755, Typeflag: tar.TypeDir}
+			if !strings.HasSuffix(name, "/") {
+				hdr.Mode, hdr.Typeflag, hdr.Size = 0644, tar.TypeReg, 1
+			}
+			require.NoError(t, tw.WriteHeader(hdr))
+			if hdr.Size != 0 {
+				_, err := tw.Write([]byte("x"))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+		return &buf
+	}
+
+	outside := t.TempDir()
+	dst := t.TempDir()
+	dir := filepath.Join(dst, "dir")
+	moved := filepath.Join(dst, "moved")
+
+	// The directory is swapped for a symlink once it's been extracted, while
+	// the header of the file is read
+	r := &swapReader{
+		r:      archive("dir/", "dir/file"),
+		offset: 512,
+		swap: func() {
+			require.NoError(t, os.Rename(dir, moved))
+			require.NoError(t, os.Symlink(outside, dir))
+		},
+	}
+	err := Extract(r, dst, "", nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "escapes the destination")
+	require.Nil(t, r.swap)
+	require.NoFileExists(t, filepath.Join(outside, "file"))
+
+	// Swapping the directory concurrently never writes outside of the
+	// destination, although the extraction may fail
+	require.NoError(t, os.Remove(dir))
+	require.NoError(t, os.Rename(moved, dir))
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		for {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			os.Rename(dir, moved)
+			os.Symlink(outside, dir)
+			time.Sleep(time.Millisecond)
+			os.Remove(dir)
+			os.Rename(moved, dir)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	names := []string{"dir/"}
+	for i := 0; i < 100; i++ {
+		names = append(names, fmt.Sprintf("dir/file%d", i))
+	}
+	for i := 0; i < 50; i++ {
+		Extract(archive(names...), dst, "", nil)
+	}
+	close(stopCh)
+	<-doneCh
+
+	entries, err := os.ReadDir(outside)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestExtract_DenyLinkTargets(t *testing.T) {
+	ci.Parallel(t)
+
+	archive := func(hdr *tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		hdr.Mode = 0644
+		require.NoError(t, tw.WriteHeader(hdr))
+		require.NoError(t, tw.Close())
+		return &buf
+	}
+
+	dst := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dst, "task", "secret"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "task", "secret", "token"), []byte("s"), 0600))
+	deny := func(path string) bool {
+		return strings.HasPrefix(path, filepath.Join(dst, "task", "secret"))
+	}
+
+	// Hard links to denied paths are rejected
+	err := Extract(archive(&tar.Header{Name: "x", Linkname: "task/secret/token", Typeflag: tar.TypeLink}), dst, "", deny)
+	require.EqualError(t, err, "linking x to task/secret/token prohibited")
+	require.NoFileExists(t, filepath.Join(dst, "x"))
+
+	// As are symlinks, whose targets are relative to their directory
+	err = Extract(archive(&tar.Header{Name: "task/x", Linkname: "secret", Typeflag: tar.TypeSymlink}), dst, "", deny)
+	require.EqualError(t, err, "linking task/x to task/secret prohibited")
+	require.NoFileExists(t, filepath.Join(dst, "task", "x"))
+
+	// Links to other paths are extracted
+	require.NoError(t, Extract(archive(&tar.Header{Name: "x", Linkname: "task", Typeflag: tar.TypeSymlink}), dst, "", deny))
+}
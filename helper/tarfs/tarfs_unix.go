@@ -0,0 +1,157 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package tarfs
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// extractRoot is the destination of an extraction. Entries are created
+// relative to a file descriptor of the destination, and the directories of
+// their paths are opened one at a time without following symlinks. Swapping a
+// directory for a symlink during the extraction therefore can't redirect
+// writes outside of the destination.
+type extractRoot struct {
+	fd int
+}
+
+func openRoot(dst string) (*extractRoot, error) {
+	fd, err := unix.Open(dst, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: dst, Err: err}
+	}
+	return &extractRoot{fd: fd}, nil
+}
+
+func (r *extractRoot) Close() error {
+	return unix.Close(r.fd)
+}
+
+// openDir returns a file descriptor of the directory at the path relative to
+// the destination, which must be closed by the caller. Missing directories
+// are created if create is set.
+func (r *extractRoot) openDir(rel string, create bool) (int, error) {
+	fd, err := unix.Dup(r.fd)
+	if err != nil {
+		return -1, err
+	}
+	if rel == "." {
+		return fd, nil
+	}
+
+	for _, elem := range strings.Split(rel, string(filepath.Separator)) {
+		next, err := openDirNoFollow(fd, elem)
+		if err == unix.ENOENT && create {
+			if err = unix.Mkdirat(fd, elem, 0755); err == nil || err == unix.EEXIST {
+				next, err = openDirNoFollow(fd, elem)
+			}
+		}
+		if err != nil {
+			if isSymlink(fd, elem) {
+				err = fmt.Errorf("path %s escapes the destination", rel)
+			} else {
+				err = &os.PathError{Op: "open", Path: rel, Err: err}
+			}
+			unix.Close(fd)
+			return -1, err
+		}
+
+		unix.Close(fd)
+		fd = next
+	}
+	return fd, nil
+}
+
+// openDirNoFollow opens the directory name in the directory dirfd, failing if
+// it's a symlink.
+func openDirNoFollow(dirfd int, name string) (int, error) {
+	return unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+}
+
+// isSymlink returns whether name in the directory dirfd is a symlink.
+func isSymlink(dirfd int, name string) bool {
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return false
+	}
+	return st.Mode&unix.S_IFMT == unix.S_IFLNK
+}
+
+// extractEntry creates the file described by the tar header at the path
+// relative to the destination.
+func (r *extractRoot) extractEntry(tr *tar.Reader, hdr *tar.Header, rel, name string) error {
+	dirfd, err := r.openDir(filepath.Dir(rel), true)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(dirfd)
+	base := filepath.Base(rel)
+
+	// Replace existing files rather than writing through them, as they may be
+	// symlinks pointing outside of the destination. Directories are merged.
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, base, &st, unix.AT_SYMLINK_NOFOLLOW); err == nil {
+		isDir := st.Mode&unix.S_IFMT == unix.S_IFDIR
+		switch {
+		case isDir && hdr.Typeflag == tar.TypeDir:
+		case isDir:
+			return fmt.Errorf("cannot overwrite directory with non-directory")
+		default:
+			if err := unix.Unlinkat(dirfd, base, 0); err != nil {
+				return err
+			}
+		}
+	}
+
+	mode := uint32(os.FileMode(hdr.Mode).Perm())
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := unix.Mkdirat(dirfd, base, mode); err != nil && err != unix.EEXIST {
+			return err
+		}
+		fd, err := openDirNoFollow(dirfd, base)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(fd)
+		return unix.Fchmod(fd, mode)
+	case tar.TypeReg, tar.TypeRegA:
+		// The file is created exclusively so that a symlink created in its
+		// place since it was removed isn't written through
+		fd, err := unix.Openat(dirfd, base, unix.O_CREAT|unix.O_EXCL|unix.O_WRONLY|unix.O_NOFOLLOW|unix.O_CLOEXEC, mode)
+		if err != nil {
+			return err
+		}
+		f := os.NewFile(uintptr(fd), rel)
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close()
+			return err
+		}
+		mtime := unix.NsecToTimeval(hdr.ModTime.UnixNano())
+		if err := unix.Futimes(fd, []unix.Timeval{mtime, mtime}); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case tar.TypeSymlink:
+		return unix.Symlinkat(hdr.Linkname, dirfd, base)
+	case tar.TypeLink:
+		// Hard links are relative to the root of the archive, and their
+		// targets are resolved without following symlinks too
+		linkname := linkTarget(hdr, rel, name)
+		linkfd, err := r.openDir(filepath.Dir(linkname), false)
+		if err != nil {
+			return err
+		}
+		defer unix.Close(linkfd)
+		return unix.Linkat(linkfd, filepath.Base(linkname), dirfd, base, 0)
+	}
+	return nil
+}
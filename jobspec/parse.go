@@ -304,6 +304,7 @@ func parseSpread(result *[]*api.Spread, list *ast.ObjectList) error {
 		valid := []string{
 			"attribute",
 			"weight",
+			"max_skew",
 			"target",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
@@ -431,10 +432,14 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"auto_revert",
 		"auto_promote",
 		"canary",
+		"analysis",
+		"step",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return err
 	}
+	delete(m, "analysis")
+	delete(m, "step")
 
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
@@ -444,7 +449,156 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 	if err != nil {
 		return err
 	}
-	return dec.Decode(m)
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the analysis
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		if o := ot.List.Filter("analysis"); len(o.Items) > 0 {
+			if *result == nil {
+				*result = &api.UpdateStrategy{}
+			}
+			if err := parseAnalysis(&(*result).Analysis, o); err != nil {
+				return multierror.Prefix(err, "analysis ->")
+			}
+		}
+
+		// Parse the rollout steps
+		if o := ot.List.Filter("step"); len(o.Items) > 0 {
+			if *result == nil {
+				*result = &api.UpdateStrategy{}
+			}
+			if err := parseRolloutSteps(&(*result).Steps, o); err != nil {
+				return multierror.Prefix(err, "step ->")
+			}
+		}
+	}
+	return nil
+}
+
+func parseRolloutSteps(result *[]*api.RolloutStep, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"percent",
+			"pause",
+			"manual",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var step api.RolloutStep
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &step,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		*result = append(*result, &step)
+	}
+	return nil
+}
+
+func parseAnalysis(result **api.AnalysisStrategy, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'analysis' block allowed")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	// We need this later
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("analysis should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"source",
+		"address",
+		"interval",
+		"bake_time",
+		"failure_limit",
+		"metric",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+	delete(m, "metric")
+
+	var analysis api.AnalysisStrategy
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &analysis,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse the metrics
+	seen := make(map[string]struct{})
+	for _, item := range listVal.Filter("metric").Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("missing metric name")
+		}
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("metric '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"query",
+			"max",
+			"max_increase",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("metric '%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		metric := api.AnalysisMetric{Name: n}
+		if err := mapstructure.WeakDecode(m, &metric); err != nil {
+			return err
+		}
+		analysis.Metrics = append(analysis.Metrics, &metric)
+	}
+
+	*result = &analysis
+	return nil
 }
 
 func parseMigrate(result **api.MigrateStrategy, list *ast.ObjectList) error {
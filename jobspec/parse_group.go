@@ -41,6 +41,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"count",
 			"constraint",
 			"consul",
+			"gang",
 			"affinity",
 			"restart",
 			"meta",
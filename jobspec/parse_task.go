@@ -248,6 +248,7 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		valid := []string{
 			"max_files",
 			"max_file_size",
+			"sink",
 		}
 		if err := checkHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
@@ -256,12 +257,21 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 			return nil, err
 		}
+		delete(m, "sink")
 
 		var log api.LogConfig
 		if err := mapstructure.WeakDecode(m, &log); err != nil {
 			return nil, err
 		}
 
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			if so := ot.List.Filter("sink"); len(so.Items) > 0 {
+				if err := parseLogSinks(&log.Sinks, so); err != nil {
+					return nil, multierror.Prefix(err, "logs ->")
+				}
+			}
+		}
+
 		t.LogConfig = &log
 	}
 
@@ -472,6 +482,51 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	seen := make(map[string]bool)
+	for _, item := range list.Items {
+		if l := len(item.Keys); l == 0 {
+			return fmt.Errorf("sink missing name")
+		} else if l > 1 {
+			return fmt.Errorf("sink should only have one name")
+		}
+		n := item.Keys[0].Token.Value().(string)
+		errPrefix := fmt.Sprintf("sink[%v] ->", n)
+
+		// Make sure we haven't already found this
+		if seen[n] {
+			return multierror.Prefix(fmt.Errorf("sink cannot be defined more than once"), errPrefix)
+		}
+		seen[n] = true
+
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"address",
+			"facility",
+			"tag",
+			"buffer_size",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, errPrefix)
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		sink := &api.LogSink{Name: n}
+		if err := mapstructure.WeakDecode(m, sink); err != nil {
+			return multierror.Prefix(err, errPrefix)
+		}
+
+		*result = append(*result, sink)
+	}
+
+	return nil
+}
+
 func parseTaskScalingPolicies(result *[]*api.ScalingPolicy, list *ast.ObjectList) error {
 	if len(list.Items) == 0 {
 		return nil
@@ -552,6 +607,9 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"network",
 		"device",
 		"cores",
+		"numa",
+		"disk_io",
+		"egress_mbits",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -563,6 +621,8 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 	}
 	delete(m, "network")
 	delete(m, "device")
+	delete(m, "numa")
+	delete(m, "disk_io")
 
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
@@ -641,6 +701,54 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse the NUMA affinity
+	if o := listVal.Filter("numa"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'numa' block allowed per resources")
+		}
+		if err := checkHCLKeys(o.Items[0].Val, []string{"affinity"}); err != nil {
+			return multierror.Prefix(err, "resources, numa ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Items[0].Val); err != nil {
+			return err
+		}
+
+		var numa api.NUMA
+		if err := mapstructure.WeakDecode(m, &numa); err != nil {
+			return err
+		}
+		result.NUMA = &numa
+	}
+
+	// Parse the disk IO
+	if o := listVal.Filter("disk_io"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return fmt.Errorf("only one 'disk_io' block allowed per resources")
+		}
+		valid := []string{
+			"read_iops",
+			"write_iops",
+			"read_mbps",
+			"write_mbps",
+		}
+		if err := checkHCLKeys(o.Items[0].Val, valid); err != nil {
+			return multierror.Prefix(err, "resources, disk_io ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Items[0].Val); err != nil {
+			return err
+		}
+
+		var diskIO api.DiskIO
+		if err := mapstructure.WeakDecode(m, &diskIO); err != nil {
+			return err
+		}
+		result.DiskIO = &diskIO
+	}
+
 	return nil
 }
 
@@ -332,6 +332,21 @@ func TestParse(t *testing.T) {
 								LogConfig: &api.LogConfig{
 									MaxFiles:      intToPtr(14),
 									MaxFileSizeMB: intToPtr(101),
+									Sinks: []*api.LogSink{
+										{
+											Name:     "syslog",
+											Type:     "syslog",
+											Address:  "udp://127.0.0.1:514",
+											Facility: "local0",
+											Tag:      "binstore",
+										},
+										{
+											Name:       "collector",
+											Type:       "tcp",
+											Address:    "127.0.0.1:5170",
+											BufferSize: intToPtr(2048),
+										},
+									},
 								},
 								Artifacts: []*api.TaskArtifact{
 									{
@@ -1767,6 +1782,225 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"resources-io.hcl",
+			&api.Job{
+				ID:   stringToPtr("io-test"),
+				Name: stringToPtr("io-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Resources: &api.Resources{
+									CPU:         intToPtr(500),
+									MemoryMB:    intToPtr(128),
+									EgressMbits: intToPtr(100),
+									DiskIO: &api.DiskIO{
+										ReadIOPS:  intToPtr(1000),
+										WriteIOPS: intToPtr(500),
+										ReadMBps:  intToPtr(200),
+										WriteMBps: intToPtr(100),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"spread-max-skew.hcl",
+			&api.Job{
+				ID:   stringToPtr("spread-test"),
+				Name: stringToPtr("spread-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  stringToPtr("group"),
+						Count: intToPtr(6),
+						Spreads: []*api.Spread{
+							{
+								Attribute: "${meta.zone}",
+								MaxSkew:   intToPtr(1),
+							},
+							{
+								Attribute: "${meta.rack}",
+								Weight:    int8ToPtr(25),
+								MaxSkew:   intToPtr(2),
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"job-dependencies.hcl",
+			&api.Job{
+				ID:   stringToPtr("report"),
+				Name: stringToPtr("report"),
+				Type: stringToPtr("batch"),
+				DependsOn: []*api.JobDependency{
+					{
+						JobID: "extract",
+					},
+					{
+						JobID:     "cleanup",
+						Condition: "complete",
+					},
+				},
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"update-analysis.hcl",
+			&api.Job{
+				ID:   stringToPtr("web"),
+				Name: stringToPtr("web"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Update: &api.UpdateStrategy{
+							Canary:      intToPtr(1),
+							AutoPromote: boolToPtr(true),
+							Analysis: &api.AnalysisStrategy{
+								Source:       stringToPtr("prometheus"),
+								Address:      stringToPtr("http://prometheus.service.consul:9090"),
+								Interval:     timeToPtr(time.Minute),
+								BakeTime:     timeToPtr(10 * time.Minute),
+								FailureLimit: intToPtr(2),
+								Metrics: []*api.AnalysisMetric{
+									{
+										Name:        "error_rate",
+										Query:       `sum(rate(http_errors_total{alloc_id=~"{{ .AllocIDs }}"}[1m]))`,
+										Max:         float64ToPtr(0.05),
+										MaxIncrease: float64ToPtr(0.1),
+									},
+									{
+										Name:  "latency",
+										Query: `histogram_quantile(0.99, sum(rate(http_request_seconds_bucket{alloc_id=~"{{ .AllocIDs }}"}[1m])) by (le))`,
+										Max:   float64ToPtr(1),
+									},
+								},
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"update-steps.hcl",
+			&api.Job{
+				ID:   stringToPtr("web"),
+				Name: stringToPtr("web"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  stringToPtr("group"),
+						Count: intToPtr(8),
+						Update: &api.UpdateStrategy{
+							Canary:      intToPtr(1),
+							AutoPromote: boolToPtr(true),
+							Steps: []*api.RolloutStep{
+								{
+									Percent: intToPtr(25),
+									Pause:   timeToPtr(10 * time.Minute),
+								},
+								{
+									Percent: intToPtr(50),
+									Manual:  boolToPtr(true),
+								},
+								{
+									Percent: intToPtr(100),
+								},
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"resources-numa.hcl",
+			&api.Job{
+				ID:   stringToPtr("numa-test"),
+				Name: stringToPtr("numa-test"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Tasks: []*api.Task{
+							{
+								Name:   "task",
+								Driver: "docker",
+								Resources: &api.Resources{
+									Cores:    intToPtr(4),
+									MemoryMB: intToPtr(128),
+									NUMA: &api.NUMA{
+										Affinity: "require",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"gang.hcl",
+			&api.Job{
+				ID:   stringToPtr("training"),
+				Name: stringToPtr("training"),
+				Type: stringToPtr("batch"),
+				Gang: boolToPtr(true),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name:  stringToPtr("workers"),
+						Count: intToPtr(4),
+						Gang:  boolToPtr(true),
+						Tasks: []*api.Task{
+							{
+								Name:   "worker",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-provider.hcl",
 			&api.Job{
@@ -193,6 +193,19 @@ job "binstore-storagelocker" {
       logs {
         max_files     = 14
         max_file_size = 101
+
+        sink "syslog" {
+          type     = "syslog"
+          address  = "udp://127.0.0.1:514"
+          facility = "local0"
+          tag      = "binstore"
+        }
+
+        sink "collector" {
+          type        = "tcp"
+          address     = "127.0.0.1:5170"
+          buffer_size = 2048
+        }
       }
 
       env {
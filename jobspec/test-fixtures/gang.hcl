@@ -0,0 +1,13 @@
+job "training" {
+  type = "batch"
+  gang = true
+
+  group "workers" {
+    count = 4
+    gang  = true
+
+    task "worker" {
+      driver = "docker"
+    }
+  }
+}
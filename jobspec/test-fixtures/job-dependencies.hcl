@@ -0,0 +1,18 @@
+job "report" {
+  type = "batch"
+
+  depends_on {
+    job = "extract"
+  }
+
+  depends_on {
+    job       = "cleanup"
+    condition = "complete"
+  }
+
+  group "group" {
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
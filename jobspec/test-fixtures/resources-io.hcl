@@ -0,0 +1,20 @@
+job "io-test" {
+  group "group" {
+    task "task" {
+      driver = "docker"
+
+      resources {
+        cpu          = 500
+        memory       = 128
+        egress_mbits = 100
+
+        disk_io {
+          read_iops  = 1000
+          write_iops = 500
+          read_mbps  = 200
+          write_mbps = 100
+        }
+      }
+    }
+  }
+}
@@ -0,0 +1,20 @@
+job "spread-test" {
+  group "group" {
+    count = 6
+
+    spread {
+      attribute = "${meta.zone}"
+      max_skew  = 1
+    }
+
+    spread {
+      attribute = "${meta.rack}"
+      weight    = 25
+      max_skew  = 2
+    }
+
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
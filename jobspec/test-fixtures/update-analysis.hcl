@@ -0,0 +1,31 @@
+job "web" {
+  group "group" {
+    update {
+      canary       = 1
+      auto_promote = true
+
+      analysis {
+        source        = "prometheus"
+        address       = "http://prometheus.service.consul:9090"
+        interval      = "1m"
+        bake_time     = "10m"
+        failure_limit = 2
+
+        metric "error_rate" {
+          query        = "sum(rate(http_errors_total{alloc_id=~\"{{ .AllocIDs }}\"}[1m]))"
+          max          = 0.05
+          max_increase = 0.1
+        }
+
+        metric "latency" {
+          query = "histogram_quantile(0.99, sum(rate(http_request_seconds_bucket{alloc_id=~\"{{ .AllocIDs }}\"}[1m])) by (le))"
+          max   = 1
+        }
+      }
+    }
+
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
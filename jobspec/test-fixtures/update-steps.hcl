@@ -0,0 +1,28 @@
+job "web" {
+  group "group" {
+    count = 8
+
+    update {
+      canary       = 1
+      auto_promote = true
+
+      step {
+        percent = 25
+        pause   = "10m"
+      }
+
+      step {
+        percent = 50
+        manual  = true
+      }
+
+      step {
+        percent = 100
+      }
+    }
+
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
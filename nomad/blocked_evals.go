@@ -576,7 +576,9 @@ func (b *BlockedEvals) unblock(computedClass, quota string, index uint64) {
 			b.stats.Unblock(eval)
 		}
 
-		// Enqueue all the unblocked evals into the broker.
+		// Enqueue all the unblocked evals into the broker. If fair share is
+		// enabled, the broker dequeues them by the share of their queue
+		// rather than in the order capacity became available.
 		b.evalBroker.EnqueueAll(unblocked)
 	}
 }
@@ -686,6 +688,22 @@ func (b *BlockedEvals) Flush() {
 	b.system = newSystemEvals()
 }
 
+// Evals returns the evaluations blocked on capacity, excluding system
+// evaluations.
+func (b *BlockedEvals) Evals() []*structs.Evaluation {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	evals := make([]*structs.Evaluation, 0, len(b.captured)+len(b.escaped))
+	for _, wrapped := range b.captured {
+		evals = append(evals, wrapped.eval)
+	}
+	for _, wrapped := range b.escaped {
+		evals = append(evals, wrapped.eval)
+	}
+	return evals
+}
+
 // Stats is used to query the state of the blocked eval tracker.
 func (b *BlockedEvals) Stats() *BlockedStats {
 	// Allocate a new stats struct
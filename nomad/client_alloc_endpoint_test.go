@@ -1077,6 +1077,87 @@ func TestClientAllocations_Restart_ACL(t *testing.T) {
 
 // TestAlloc_ExecStreaming asserts that exec task requests are forwarded
 // to appropriate server or remote regions
+func TestClientAllocations_PortForward_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Executing commands doesn't allow forwarding ports
+	policyBad := mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocExec})
+	tokenBad := mock.CreatePolicyAndToken(t, s.State(), 1005, "invalid", policyBad)
+
+	policyGood := mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocPortForward})
+	tokenGood := mock.CreatePolicyAndToken(t, s.State(), 1009, "valid", policyGood)
+
+	// Upsert the allocation
+	state := s.State()
+	alloc := mock.Alloc()
+	require.NoError(t, state.UpsertJob(nstructs.MsgTypeTestSetup, 1010, alloc.Job))
+	require.NoError(t, state.UpsertAllocs(nstructs.MsgTypeTestSetup, 1011, []*nstructs.Allocation{alloc}))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenBad.SecretID,
+			ExpectedError: nstructs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenGood.SecretID,
+			ExpectedError: nstructs.ErrUnknownNodePrefix,
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: nstructs.ErrUnknownNodePrefix,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.AllocPortForwardRequest{
+				AllocID: alloc.ID,
+				Port:    "http",
+				QueryOptions: nstructs.QueryOptions{
+					Namespace: nstructs.DefaultNamespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := s.StreamingRpcHandler("Allocations.PortForward")
+			require.NoError(t, err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			// Start the handler
+			go handler(p2)
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+			require.NoError(t, encoder.Encode(req))
+
+			// The request is rejected before connecting to the port
+			var msg cstructs.StreamErrWrapper
+			decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+			require.NoError(t, decoder.Decode(&msg))
+			require.NotNil(t, msg.Error)
+			require.Contains(t, msg.Error.Error(), c.ExpectedError)
+		})
+	}
+}
+
 func TestAlloc_ExecStreaming(t *testing.T) {
 	ci.Parallel(t)
 
@@ -40,6 +40,10 @@ type MockClientCSI struct {
 	NextListExternalSnapshotsError    error
 	NextListExternalSnapshotsResponse *cstructs.ClientCSIControllerListSnapshotsResponse
 	NextNodeDetachError               error
+	NextExpandError                   error
+	NextExpandResponse                *cstructs.ClientCSIControllerExpandVolumeResponse
+	NextNodeExpandError               error
+	NextNodeExpandResponse            *cstructs.ClientCSINodeExpandVolumeResponse
 }
 
 func newMockClientCSI() *MockClientCSI {
@@ -49,6 +53,8 @@ func newMockClientCSI() *MockClientCSI {
 		NextListExternalResponse:          &cstructs.ClientCSIControllerListVolumesResponse{},
 		NextCreateSnapshotResponse:        &cstructs.ClientCSIControllerCreateSnapshotResponse{},
 		NextListExternalSnapshotsResponse: &cstructs.ClientCSIControllerListSnapshotsResponse{},
+		NextExpandResponse:                &cstructs.ClientCSIControllerExpandVolumeResponse{},
+		NextNodeExpandResponse:            &cstructs.ClientCSINodeExpandVolumeResponse{},
 	}
 }
 
@@ -93,10 +99,20 @@ func (c *MockClientCSI) ControllerListSnapshots(req *cstructs.ClientCSIControlle
 	return c.NextListExternalSnapshotsError
 }
 
+func (c *MockClientCSI) ControllerExpandVolume(req *cstructs.ClientCSIControllerExpandVolumeRequest, resp *cstructs.ClientCSIControllerExpandVolumeResponse) error {
+	*resp = *c.NextExpandResponse
+	return c.NextExpandError
+}
+
 func (c *MockClientCSI) NodeDetachVolume(req *cstructs.ClientCSINodeDetachVolumeRequest, resp *cstructs.ClientCSINodeDetachVolumeResponse) error {
 	return c.NextNodeDetachError
 }
 
+func (c *MockClientCSI) NodeExpandVolume(req *cstructs.ClientCSINodeExpandVolumeRequest, resp *cstructs.ClientCSINodeExpandVolumeResponse) error {
+	*resp = *c.NextNodeExpandResponse
+	return c.NextNodeExpandError
+}
+
 func TestClientCSIController_AttachVolume_Local(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -68,6 +68,15 @@ type Config struct {
 	// EventBufferSize is the amount of events to hold in memory.
 	EventBufferSize int64
 
+	// EnableEventJournal is used to enable or disable writing published
+	// events to an on-disk journal. It has no effect in dev mode, or if
+	// EnableEventBroker is false.
+	EnableEventJournal bool
+
+	// EventJournalSize is the number of raft indexes to retain events for
+	// in the event journal.
+	EventJournalSize int64
+
 	// LogOutput is the location to write logs to. If this is not set,
 	// logs will go to stderr.
 	LogOutput io.Writer
@@ -284,6 +293,22 @@ type Config struct {
 	// SentinelConfig is this Agent's Sentinel configuration
 	SentinelConfig *config.SentinelConfig
 
+	// AdmissionControllers are the external admission controllers jobs are
+	// sent to before being registered or planned.
+	AdmissionControllers []*config.AdmissionControllerConfig
+
+	// RebalancerConfig configures the rebalancer core job, which migrates
+	// allocations violating their spread or running on underutilized nodes.
+	RebalancerConfig *config.RebalancerConfig
+
+	// FairShareConfig configures weighted fair-share dequeuing of
+	// evaluations between namespaces or job meta queues.
+	FairShareConfig *config.FairShareConfig
+
+	// CanaryAnalysisConfig configures the sources of metrics that jobs may
+	// analyze their canaries with.
+	CanaryAnalysisConfig *config.CanaryAnalysisConfig
+
 	// StatsCollectionInterval is the interval at which the Nomad server
 	// publishes metrics which are periodic in nature like updating gauges
 	StatsCollectionInterval time.Duration
@@ -402,9 +427,13 @@ func DefaultConfig() *Config {
 		TLSConfig:                        &config.TLSConfig{},
 		ReplicationBackoff:               30 * time.Second,
 		SentinelGCInterval:               30 * time.Second,
+		RebalancerConfig:                 config.DefaultRebalancerConfig(),
+		FairShareConfig:                  config.DefaultFairShareConfig(),
+		CanaryAnalysisConfig:             config.DefaultCanaryAnalysisConfig(),
 		LicenseConfig:                    &LicenseConfig{},
 		EnableEventBroker:                true,
 		EventBufferSize:                  100,
+		EventJournalSize:                 10000,
 		AutopilotConfig: &structs.AutopilotConfig{
 			CleanupDeadServers:      true,
 			LastContactThreshold:    200 * time.Millisecond,
@@ -3,12 +3,16 @@ package nomad
 import (
 	"fmt"
 	"math"
+	"strconv"
 	"strings"
 	"time"
 
+	metrics "github.com/armon/go-metrics"
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
@@ -60,6 +64,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.expiredOneTimeTokenGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
+	case structs.CoreJobRebalance:
+		return c.rebalance(eval)
 	default:
 		return fmt.Errorf("core scheduler cannot handle job '%s'", eval.JobID)
 	}
@@ -858,3 +864,109 @@ func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
 	}
 	return c.srv.RPC("ACL.ExpireOneTimeTokens", req, &structs.GenericResponse{})
 }
+
+// rebalance is used to migrate allocations that violate their spread or run
+// on underutilized nodes. In dry run mode, the migrations are only reported.
+func (c *CoreScheduler) rebalance(eval *structs.Evaluation) error {
+	defer metrics.MeasureSince([]string{"nomad", "rebalancer", "run"}, time.Now())
+
+	_, schedConfig, err := c.snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if schedConfig == nil || !schedConfig.RebalancerConfig.Enabled {
+		return nil
+	}
+	dryRun := schedConfig.RebalancerConfig.DryRun
+
+	conf := c.srv.config.RebalancerConfig
+
+	opts := scheduler.RebalanceOptions{MaxMigrations: conf.MaxMigrations}
+	if conf.UtilizationThreshold != nil {
+		opts.UtilizationThreshold = *conf.UtilizationThreshold
+	}
+	migrations, err := scheduler.NewRebalancer(c.logger, c.snap, opts).Compute()
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	transitions := make(map[string]*structs.DesiredTransition, len(migrations))
+	events := make(map[string][]*structs.NodeEvent)
+	var evals []*structs.Evaluation
+	jobs := make(map[structs.NamespacedID]struct{})
+	for _, m := range migrations {
+		alloc := m.Alloc
+		c.logger.Info("rebalancing allocation", "alloc_id", alloc.ID, "job_id", alloc.JobID,
+			"namespace", alloc.Namespace, "node_id", alloc.NodeID, "reason", m.Reason, "dry_run", dryRun)
+		metrics.IncrCounterWithLabels([]string{"nomad", "rebalancer", "migrations"}, 1, []metrics.Label{
+			{Name: "reason", Value: m.Reason},
+			{Name: "dry_run", Value: strconv.FormatBool(dryRun)},
+		})
+
+		msg := "Allocation migrated by rebalancer"
+		if dryRun {
+			msg = "Allocation would be migrated by rebalancer"
+		}
+		event := structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemRebalancer).
+			SetMessage(msg).
+			SetTimestamp(now).
+			AddDetail("alloc_id", alloc.ID).
+			AddDetail("job", alloc.JobID).
+			AddDetail("namespace", alloc.Namespace).
+			AddDetail("task_group", alloc.TaskGroup).
+			AddDetail("reason", m.Reason).
+			AddDetail("description", m.Description).
+			AddDetail("dry_run", strconv.FormatBool(dryRun))
+		events[alloc.NodeID] = append(events[alloc.NodeID], event)
+
+		transitions[alloc.ID] = &structs.DesiredTransition{Migrate: helper.BoolToPtr(true)}
+		job := structs.NamespacedID{Namespace: alloc.Namespace, ID: alloc.JobID}
+		if _, ok := jobs[job]; ok {
+			continue
+		}
+		jobs[job] = struct{}{}
+		evals = append(evals, &structs.Evaluation{
+			ID:          uuid.Generate(),
+			Namespace:   alloc.Namespace,
+			Priority:    alloc.Job.Priority,
+			Type:        alloc.Job.Type,
+			TriggeredBy: structs.EvalTriggerRebalance,
+			JobID:       alloc.JobID,
+			Status:      structs.EvalStatusPending,
+			CreateTime:  now.UnixNano(),
+			ModifyTime:  now.UnixNano(),
+		})
+	}
+
+	if !dryRun {
+		req := &structs.AllocUpdateDesiredTransitionRequest{
+			Allocs: transitions,
+			Evals:  evals,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.Region(),
+				AuthToken: eval.LeaderACL,
+			},
+		}
+		if err := c.srv.RPC("Alloc.UpdateDesiredTransition", req, &structs.GenericResponse{}); err != nil {
+			return fmt.Errorf("failed to migrate allocations: %v", err)
+		}
+	}
+
+	// Record the decisions on the nodes the allocations are migrated off of
+	eventsReq := &structs.EmitNodeEventsRequest{
+		NodeEvents: events,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	if err := c.srv.RPC("Node.EmitEvents", eventsReq, &structs.EmitNodeEventsResponse{}); err != nil {
+		c.logger.Error("failed to emit rebalancer node events", "error", err)
+	}
+	return nil
+}
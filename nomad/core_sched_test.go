@@ -8,6 +8,7 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
@@ -2510,3 +2511,78 @@ func TestCoreScheduler_FailLoop(t *testing.T) {
 			out.TriggeredBy)
 	}
 }
+
+func TestCoreScheduler_Rebalance(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, dryRun := range []bool{true, false} {
+		t.Run(fmt.Sprintf("dry_run=%v", dryRun), func(t *testing.T) {
+			s1, cleanupS1 := TestServer(t, func(c *Config) {
+				c.NumSchedulers = 0 // Prevent automatic dequeue
+			})
+			defer cleanupS1()
+			testutil.WaitForLeader(t, s1.RPC)
+
+			store := s1.fsm.State()
+			schedConfig := &structs.SchedulerConfiguration{
+				RebalancerConfig: structs.RebalancerConfig{Enabled: true, DryRun: dryRun},
+			}
+			require.NoError(t, store.SchedulerSetConfig(999, schedConfig))
+
+			// Run all allocations of a job spread across racks on the
+			// first rack
+			nodes := []*structs.Node{mock.Node(), mock.Node()}
+			nodes[0].Meta["rack"] = "a"
+			nodes[1].Meta["rack"] = "b"
+			for i, node := range nodes {
+				require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+			}
+
+			job := mock.Job()
+			job.Spreads = []*structs.Spread{{Attribute: "${meta.rack}", Weight: 100}}
+			job.TaskGroups[0].Count = 2
+			require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1002, job))
+
+			var allocs []*structs.Allocation
+			for i := 0; i < 2; i++ {
+				alloc := mock.Alloc()
+				alloc.Job = job
+				alloc.JobID = job.ID
+				alloc.NodeID = nodes[0].ID
+				alloc.ClientStatus = structs.AllocClientStatusRunning
+				alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+				allocs = append(allocs, alloc)
+			}
+			require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1003, allocs))
+
+			snap, err := store.Snapshot()
+			require.NoError(t, err)
+			core := NewCoreScheduler(s1, snap)
+			eval := s1.coreJobEval(structs.CoreJobRebalance, 2000)
+			require.NoError(t, core.Process(eval))
+
+			// The decision is recorded as a node event
+			node, err := store.NodeByID(nil, nodes[0].ID)
+			require.NoError(t, err)
+			event := node.Events[len(node.Events)-1]
+			require.Equal(t, structs.NodeEventSubsystemRebalancer, event.Subsystem)
+			require.Equal(t, fmt.Sprint(dryRun), event.Details["dry_run"])
+
+			// Only one allocation is migrated, and only outside of dry run
+			migrating := 0
+			for _, alloc := range allocs {
+				out, err := store.AllocByID(nil, alloc.ID)
+				require.NoError(t, err)
+				if out.DesiredTransition.ShouldMigrate() {
+					require.Equal(t, out.ID, event.Details["alloc_id"])
+					migrating++
+				}
+			}
+			if dryRun {
+				require.Zero(t, migrating)
+			} else {
+				require.Equal(t, 1, migrating)
+			}
+		})
+	}
+}
@@ -353,7 +353,10 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 		// without having to manually remove the fields unused by
 		// register (and similar use cases with API consumers such as
 		// Terraform).
+		var needsExpansion bool
+		current := existingVol
 		if existingVol != nil {
+			needsExpansion = existingVol.NeedsExpansion(vol)
 			existingVol = existingVol.Copy()
 			err = existingVol.Merge(vol)
 			if err != nil {
@@ -376,6 +379,20 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 		if err := v.controllerValidateVolume(args, vol, plugin); err != nil {
 			return err
 		}
+
+		// The state store rejects updates to volumes in use that aren't
+		// limited to their capacity, so we check before expanding the
+		// volume in the storage provider
+		if current != nil {
+			if err := current.ValidateUpdate(vol); err != nil {
+				return err
+			}
+		}
+		if needsExpansion {
+			if err := v.expandVolume(vol, plugin); err != nil {
+				return err
+			}
+		}
 	}
 
 	resp, index, err := v.srv.raftApply(structs.CSIVolumeRegisterRequestType, args)
@@ -493,6 +510,50 @@ func (v *CSIVolume) Claim(args *structs.CSIVolumeClaimRequest, reply *structs.CS
 	return nil
 }
 
+// UpdateStats records the usage and condition of volumes mounted on a
+// client, as reported by the node plugins. This endpoint is only callable by
+// the clients.
+func (v *CSIVolume) UpdateStats(args *structs.CSIVolumeUpdateStatsRequest, reply *structs.CSIVolumeUpdateStatsResponse) error {
+	if done, err := v.srv.forward("CSIVolume.UpdateStats", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "update_stats"}, time.Now())
+
+	// Perform a node lookup using the secret ID to confirm the caller is a
+	// known node, and that it is only reporting stats for itself.
+	node, err := v.srv.fsm.State().NodeBySecretID(nil, args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return structs.ErrTokenNotFound
+	}
+	if args.NodeID != node.ID {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Stats) == 0 {
+		return fmt.Errorf("must update at least one volume")
+	}
+	for _, stats := range args.Stats {
+		if stats.VolumeID == "" {
+			return fmt.Errorf("missing volume ID")
+		}
+	}
+
+	resp, index, err := v.srv.raftApply(structs.CSIVolumeUpdateStatsRequestType, args)
+	if err != nil {
+		v.logger.Error("csi raft apply failed", "error", err, "method", "update_stats")
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	reply.Index = index
+	return nil
+}
+
 func csiVolumeMountOptions(c *structs.CSIMountOptions) *cstructs.CSIVolumeMountOptions {
 	if c == nil {
 		return nil
@@ -914,9 +975,19 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 	type validated struct {
 		vol    *structs.CSIVolume
 		plugin *structs.CSIPlugin
+
+		// exists is true if the volume has already been created, in which
+		// case we update it and expand it if needed
+		exists         bool
+		needsExpansion bool
 	}
 	validatedVols := []validated{}
 
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
 	// This is the only namespace we ACL checked, force all the volumes to use it.
 	// We also validate that the plugin exists for each plugin, and validate the
 	// capabilities when the plugin has a controller.
@@ -927,6 +998,29 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 		if err = vol.Validate(); err != nil {
 			return err
 		}
+
+		// if the volume already exists, we merge the update onto it in
+		// the same way as Register, instead of creating a new volume
+		current, err := snap.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+		if err != nil {
+			return err
+		}
+		var needsExpansion bool
+		existing := current
+		if current != nil {
+			needsExpansion = current.NeedsExpansion(vol)
+			current = current.Copy()
+
+			// the volume context was set by the plugin when the volume
+			// was created and can't be set by the user in Create
+			volContext := current.Context
+			if err := current.Merge(vol); err != nil {
+				return err
+			}
+			current.Context = volContext
+			*vol = *current
+		}
+
 		plugin, err := v.pluginValidateVolume(regArgs, vol)
 		if err != nil {
 			return err
@@ -938,7 +1032,16 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 			return fmt.Errorf("plugin does not support creating volumes")
 		}
 
-		validatedVols = append(validatedVols, validated{vol, plugin})
+		// Reject updates the state store would reject before expanding
+		// the volume in the storage provider
+		if existing != nil {
+			if err := existing.ValidateUpdate(vol); err != nil {
+				return err
+			}
+		}
+
+		validatedVols = append(validatedVols,
+			validated{vol, plugin, current != nil, needsExpansion})
 	}
 
 	// Attempt to create all the validated volumes and write only successfully
@@ -955,7 +1058,13 @@ func (v *CSIVolume) Create(args *structs.CSIVolumeCreateRequest, reply *structs.
 	var mErr multierror.Error
 
 	for _, valid := range validatedVols {
-		err = v.createVolume(valid.vol, valid.plugin)
+		if valid.exists {
+			if valid.needsExpansion {
+				err = v.expandVolume(valid.vol, valid.plugin)
+			}
+		} else {
+			err = v.createVolume(valid.vol, valid.plugin)
+		}
 		if err != nil {
 			multierror.Append(&mErr, err)
 		} else {
@@ -1012,6 +1121,98 @@ func (v *CSIVolume) createVolume(vol *structs.CSIVolume, plugin *structs.CSIPlug
 	return nil
 }
 
+// expandVolume expands the volume in the external storage provider to the
+// volume's requested capacity, and then expands the volume on each node
+// where it is claimed if the plugin requires it. The new capacity is set on
+// the volume, but the caller is responsible for writing it to raft.
+func (v *CSIVolume) expandVolume(vol *structs.CSIVolume, plugin *structs.CSIPlugin) error {
+	if !plugin.ControllerRequired ||
+		!plugin.HasControllerCapability(structs.CSIControllerSupportsExpand) {
+		// Some plugins can only expand a volume on the node, in which case
+		// we skip the controller
+		if !plugin.HasNodeCapability(structs.CSINodeSupportsExpand) {
+			return fmt.Errorf("plugin does not support expanding volumes")
+		}
+		return v.nodeExpandVolume(vol, plugin)
+	}
+
+	method := "ClientCSI.ControllerExpandVolume"
+	cReq := &cstructs.ClientCSIControllerExpandVolumeRequest{
+		ExternalVolumeID: vol.ExternalID,
+		Secrets:          vol.Secrets,
+		CapacityMin:      vol.RequestedCapacityMin,
+		CapacityMax:      vol.RequestedCapacityMax,
+	}
+	if vol.InUse() {
+		cReq.VolumeCapability = &structs.CSIVolumeCapability{
+			AttachmentMode: vol.AttachmentMode,
+			AccessMode:     vol.AccessMode,
+		}
+		cReq.MountOptions = vol.MountOptions
+	}
+	cReq.PluginID = plugin.ID
+	cResp := &cstructs.ClientCSIControllerExpandVolumeResponse{}
+	err := v.srv.RPC(method, cReq, cResp)
+	if err != nil {
+		return err
+	}
+
+	if cResp.CapacityBytes > vol.Capacity {
+		vol.Capacity = cResp.CapacityBytes
+	}
+	if !cResp.NodeExpansionRequired {
+		return nil
+	}
+	return v.nodeExpandVolume(vol, plugin)
+}
+
+// nodeExpandVolume expands the volume on each node where it has been
+// published for an allocation. Volumes that are not in use will be expanded
+// by the plugin when they are next staged.
+func (v *CSIVolume) nodeExpandVolume(vol *structs.CSIVolume, plugin *structs.CSIPlugin) error {
+	if !plugin.HasNodeCapability(structs.CSINodeSupportsExpand) {
+		return nil
+	}
+
+	var mErr multierror.Error
+	expand := func(claim *structs.CSIVolumeClaim) {
+		if claim == nil || claim.State != structs.CSIVolumeClaimStateTaken {
+			return
+		}
+		req := &cstructs.ClientCSINodeExpandVolumeRequest{
+			PluginID:       plugin.ID,
+			VolumeID:       vol.ID,
+			ExternalID:     vol.RemoteID(),
+			AllocID:        claim.AllocationID,
+			NodeID:         claim.NodeID,
+			CapacityMin:    vol.RequestedCapacityMin,
+			CapacityMax:    vol.RequestedCapacityMax,
+			AttachmentMode: claim.AttachmentMode,
+			AccessMode:     claim.AccessMode,
+			ReadOnly:       claim.Mode == structs.CSIVolumeClaimRead,
+		}
+		resp := &cstructs.ClientCSINodeExpandVolumeResponse{}
+		err := v.srv.RPC("ClientCSI.NodeExpandVolume", req, resp)
+		if err != nil {
+			multierror.Append(&mErr, fmt.Errorf(
+				"could not expand volume on node %q: %w", claim.NodeID, err))
+			return
+		}
+		if resp.CapacityBytes > vol.Capacity {
+			vol.Capacity = resp.CapacityBytes
+		}
+	}
+
+	for _, claim := range vol.ReadClaims {
+		expand(claim)
+	}
+	for _, claim := range vol.WriteClaims {
+		expand(claim)
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 func (v *CSIVolume) Delete(args *structs.CSIVolumeDeleteRequest, reply *structs.CSIVolumeDeleteResponse) error {
 	if done, err := v.srv.forward("CSIVolume.Delete", args, args, reply); done {
 		return err
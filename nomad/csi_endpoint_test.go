@@ -47,7 +47,7 @@ func TestCSIVolumeEndpoint_Get(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err := state.UpsertCSIVolume(999, vols)
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, 999, vols)
 	require.NoError(t, err)
 
 	// Create the register request
@@ -97,7 +97,7 @@ func TestCSIVolumeEndpoint_Get_ACL(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err := state.UpsertCSIVolume(999, vols)
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, 999, vols)
 	require.NoError(t, err)
 
 	// Create the register request
@@ -132,7 +132,7 @@ func TestCSIVolumeEndpoint_Register(t *testing.T) {
 
 	// Create the register request
 	ns := mock.Namespace()
-	store.UpsertNamespaces(900, []*structs.Namespace{ns})
+	store.UpsertNamespaces(structs.MsgTypeTestSetup, 900, []*structs.Namespace{ns})
 
 	// Create the node and plugin
 	node := mock.Node()
@@ -291,7 +291,7 @@ func TestCSIVolumeEndpoint_Claim(t *testing.T) {
 		}},
 	}}
 	index++
-	err = state.UpsertCSIVolume(index, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols)
 	require.NoError(t, err)
 
 	// Verify that the volume exists, and is healthy
@@ -430,7 +430,7 @@ func TestCSIVolumeEndpoint_ClaimWithController(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err = state.UpsertCSIVolume(1003, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1003, vols)
 	require.NoError(t, err)
 
 	alloc := mock.BatchAlloc()
@@ -463,6 +463,109 @@ func TestCSIVolumeEndpoint_ClaimWithController(t *testing.T) {
 	require.EqualError(t, err, "controller publish: controller attach volume: No path to node")
 }
 
+func TestCSIVolumeEndpoint_UpdateStats(t *testing.T) {
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	index := uint64(1000)
+	state := srv.fsm.State()
+	codec := rpcClient(t, srv)
+	ns := structs.DefaultNamespace
+
+	node := mock.Node()
+	node.CSINodePlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID: "minnie",
+			Healthy:  true,
+			NodeInfo: &structs.CSINodeInfo{},
+		},
+	}
+	index++
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, index, node))
+
+	alloc := mock.BatchAlloc()
+	alloc.NodeID = node.ID
+	index++
+	require.NoError(t, state.UpsertJobSummary(index, mock.JobSummary(alloc.JobID)))
+	index++
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+
+	volID := uuid.Generate()
+	vols := []*structs.CSIVolume{{
+		ID:        volID,
+		Namespace: ns,
+		PluginID:  "minnie",
+		RequestedCapabilities: []*structs.CSIVolumeCapability{{
+			AccessMode:     structs.CSIVolumeAccessModeMultiNodeSingleWriter,
+			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+		}},
+	}}
+	index++
+	require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols))
+
+	index++
+	require.NoError(t, state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, volID, &structs.CSIVolumeClaim{
+		AllocationID: alloc.ID,
+		NodeID:       node.ID,
+		Mode:         structs.CSIVolumeClaimWrite,
+		State:        structs.CSIVolumeClaimStateTaken,
+	}))
+
+	req := &structs.CSIVolumeUpdateStatsRequest{
+		NodeID: node.ID,
+		Stats: []*structs.CSIVolumeStats{{
+			VolumeID:   volID,
+			Namespace:  ns,
+			PluginID:   "minnie",
+			TotalBytes: 4096,
+			UsedBytes:  4000,
+			Abnormal:   true,
+			Message:    "filesystem is read-only",
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: uuid.Generate(),
+		},
+	}
+	resp := &structs.CSIVolumeUpdateStatsResponse{}
+
+	// only known nodes can report stats
+	err := msgpackrpc.CallWithCodec(codec, "CSIVolume.UpdateStats", req, resp)
+	require.EqualError(t, err, structs.ErrTokenNotFound.Error())
+
+	// and only for themselves
+	req.AuthToken = node.SecretID
+	req.NodeID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.UpdateStats", req, resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.NodeID = node.ID
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.UpdateStats", req, resp)
+	require.NoError(t, err)
+	require.NotZero(t, resp.Index)
+
+	getReq := &structs.CSIVolumeGetRequest{
+		ID: volID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: ns,
+		},
+	}
+	getResp := &structs.CSIVolumeGetResponse{}
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.Get", getReq, getResp)
+	require.NoError(t, err)
+	require.Len(t, getResp.Volume.Stats, 1)
+	stats := getResp.Volume.Stats[node.ID]
+	require.NotNil(t, stats)
+	require.Equal(t, int64(4000), stats.UsedBytes)
+	require.True(t, stats.Abnormal)
+	require.Equal(t, "filesystem is read-only", stats.Message)
+}
+
 func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 	ci.Parallel(t)
 	srv, shutdown := TestServer(t, func(c *Config) { c.NumSchedulers = 0 })
@@ -540,7 +643,7 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 			}
 
 			index++
-			err = state.UpsertCSIVolume(index, []*structs.CSIVolume{vol})
+			err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol})
 			require.NoError(t, err)
 
 			// setup: create an alloc that will claim our volume
@@ -561,7 +664,7 @@ func TestCSIVolumeEndpoint_Unpublish(t *testing.T) {
 
 			index++
 			claim.State = structs.CSIVolumeClaimStateTaken
-			err = state.CSIVolumeClaim(index, ns, volID, claim)
+			err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, volID, claim)
 			require.NoError(t, err)
 
 			// test: unpublish and check the results
@@ -647,7 +750,7 @@ func TestCSIVolumeEndpoint_List(t *testing.T) {
 			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
 		}},
 	}}
-	err = state.UpsertCSIVolume(1002, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1002, vols)
 	require.NoError(t, err)
 
 	// Query everything in the namespace
@@ -692,7 +795,7 @@ func TestCSIVolumeEndpoint_ListAllNamespaces(t *testing.T) {
 	ns0 := structs.DefaultNamespace
 	ns1 := "namespace-1"
 	ns2 := "namespace-2"
-	err := state.UpsertNamespaces(1000, []*structs.Namespace{{Name: ns1}, {Name: ns2}})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{{Name: ns1}, {Name: ns2}})
 	require.NoError(t, err)
 
 	// Create volumes in multiple namespaces.
@@ -726,7 +829,7 @@ func TestCSIVolumeEndpoint_ListAllNamespaces(t *testing.T) {
 		}},
 	},
 	}
-	err = state.UpsertCSIVolume(1001, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1001, vols)
 	require.NoError(t, err)
 
 	// Lookup volumes in all namespaces
@@ -791,7 +894,7 @@ func TestCSIVolumeEndpoint_List_PaginationFiltering(t *testing.T) {
 	plugin := mock.CSIPlugin()
 
 	// Create namespaces.
-	err := state.UpsertNamespaces(999, []*structs.Namespace{{Name: nonDefaultNS}})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 999, []*structs.Namespace{{Name: nonDefaultNS}})
 	require.NoError(t, err)
 
 	for i, m := range mocks {
@@ -805,7 +908,7 @@ func TestCSIVolumeEndpoint_List_PaginationFiltering(t *testing.T) {
 			volume.Namespace = m.namespace
 		}
 		index := 1000 + uint64(i)
-		require.NoError(t, state.UpsertCSIVolume(index, []*structs.CSIVolume{volume}))
+		require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{volume}))
 	}
 
 	cases := []struct {
@@ -1098,6 +1201,163 @@ func TestCSIVolumeEndpoint_Create(t *testing.T) {
 	require.Equal(t, map[string]string{"rack": "R1"}, vol.Topologies[0].Segments)
 }
 
+func TestCSIVolumeEndpoint_Register_Expand(t *testing.T) {
+	ci.Parallel(t)
+	var err error
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+
+	testutil.WaitForLeader(t, srv.RPC)
+
+	fake := newMockClientCSI()
+	fake.NextValidateError = nil
+	fake.NextExpandResponse = &cstructs.ClientCSIControllerExpandVolumeResponse{
+		CapacityBytes: 2048,
+	}
+
+	client, cleanup := client.TestClientWithRPCs(t,
+		func(c *cconfig.Config) {
+			c.Servers = []string{srv.config.RPCAddr.String()}
+		},
+		map[string]interface{}{"CSI": fake},
+	)
+	defer cleanup()
+
+	node := client.Node()
+	node.Attributes["nomad.version"] = "0.11.0" // client RPCs not supported on early versions
+
+	req0 := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp0 structs.NodeUpdateResponse
+	err = client.RPC("Node.Register", req0, &resp0)
+	require.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := srv.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a client")
+	})
+
+	ns := structs.DefaultNamespace
+	state := srv.fsm.State()
+	codec := rpcClient(t, srv)
+	index := uint64(1000)
+
+	node.CSIControllerPlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID: "minnie",
+			Healthy:  true,
+			ControllerInfo: &structs.CSIControllerInfo{
+				SupportsAttachDetach: true,
+				SupportsExpand:       true,
+			},
+			RequiresControllerPlugin: true,
+		},
+	}
+	node.CSINodePlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID: "minnie",
+			Healthy:  true,
+			NodeInfo: &structs.CSINodeInfo{},
+		},
+	}
+	index++
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, index, node))
+
+	volID := uuid.Generate()
+	caps := []*structs.CSIVolumeCapability{
+		{
+			AccessMode:     structs.CSIVolumeAccessModeMultiNodeReader,
+			AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+		},
+	}
+	vol := &structs.CSIVolume{
+		ID:                    volID,
+		Name:                  "vol",
+		Namespace:             ns,
+		PluginID:              "minnie",
+		ExternalID:            "vol-12345",
+		Capacity:              1024,
+		RequestedCapacityMin:  1024,
+		RequestedCapabilities: caps,
+	}
+	index++
+	require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol}))
+
+	register := func(min, max int64) error {
+		req := &structs.CSIVolumeRegisterRequest{
+			Volumes: []*structs.CSIVolume{{
+				ID:                    volID,
+				Name:                  "vol",
+				PluginID:              "minnie",
+				RequestedCapacityMin:  min,
+				RequestedCapacityMax:  max,
+				RequestedCapabilities: caps,
+			}},
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: ns,
+			},
+		}
+		resp := &structs.CSIVolumeRegisterResponse{}
+		return msgpackrpc.CallWithCodec(codec, "CSIVolume.Register", req, resp)
+	}
+
+	// shrinking the volume is not supported
+	err = register(0, 512)
+	require.ErrorContains(t, err, "volume requested capacity update was not compatible with existing capacity")
+
+	// a larger minimum capacity expands the volume via the controller
+	err = register(2000, 0)
+	require.NoError(t, err)
+
+	got, err := state.CSIVolumeByID(nil, ns, volID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2048), got.Capacity)
+	require.Equal(t, int64(2000), got.RequestedCapacityMin)
+
+	// errors from the controller are returned and the capacity unchanged
+	fake.NextExpandError = fmt.Errorf("out of space")
+	err = register(4000, 0)
+	require.ErrorContains(t, err, "out of space")
+
+	got, err = state.CSIVolumeByID(nil, ns, volID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2048), got.Capacity)
+
+	// updates of volumes in use that the state store would reject are
+	// rejected before the volume is expanded
+	inUse := got.Copy()
+	inUse.AccessMode = structs.CSIVolumeAccessModeMultiNodeReader
+	inUse.AttachmentMode = structs.CSIVolumeAttachmentModeFilesystem
+	inUse.ReadAllocs = map[string]*structs.Allocation{uuid.Generate(): nil}
+	index++
+	require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{inUse}))
+
+	fake.NextExpandError = fmt.Errorf("should not see this")
+	req := &structs.CSIVolumeRegisterRequest{
+		Volumes: []*structs.CSIVolume{{
+			ID:                    volID,
+			Name:                  "vol",
+			PluginID:              "minnie",
+			RequestedCapacityMin:  4000,
+			RequestedCapabilities: caps,
+			MountOptions:          &structs.CSIMountOptions{FSType: "ext4"},
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: ns,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "CSIVolume.Register", req, &structs.CSIVolumeRegisterResponse{})
+	require.EqualError(t, err, "volume cannot be updated while in use")
+}
+
 func TestCSIVolumeEndpoint_Delete(t *testing.T) {
 	ci.Parallel(t)
 	var err error
@@ -1171,7 +1431,7 @@ func TestCSIVolumeEndpoint_Delete(t *testing.T) {
 		Secrets:   structs.CSISecrets{"mysecret": "secretvalue"},
 	}}
 	index++
-	err = state.UpsertCSIVolume(index, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols)
 	require.NoError(t, err)
 
 	// Delete volumes
@@ -1395,7 +1655,7 @@ func TestCSIVolumeEndpoint_CreateSnapshot(t *testing.T) {
 		ExternalID:     "vol-12345",
 	}}
 	index++
-	require.NoError(t, state.UpsertCSIVolume(index, vols))
+	require.NoError(t, state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, vols))
 
 	// Create the snapshot request
 	req1 := &structs.CSISnapshotCreateRequest{
@@ -1871,7 +2131,7 @@ func TestCSI_RPCVolumeAndPluginLookup(t *testing.T) {
 			ControllerRequired: false,
 		},
 	}
-	err = state.UpsertCSIVolume(1002, vols)
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, 1002, vols)
 	require.NoError(t, err)
 
 	// has controller
@@ -1,6 +1,9 @@
 package nomad
 
 import (
+	"context"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -54,3 +57,39 @@ func (d *deploymentWatcherRaftShim) UpdateAllocDesiredTransition(req *structs.Al
 	fsmErrIntf, index, raftErr := d.apply(structs.AllocUpdateDesiredTransitionRequestType, req)
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
 }
+
+// deploymentWatcherStatsShim is the shim that provides the resource usage of
+// allocations to the deployment watcher, to analyze canaries.
+type deploymentWatcherStatsShim struct {
+	s *Server
+}
+
+func (d *deploymentWatcherStatsShim) AllocStats(ctx context.Context, allocID string) (*cstructs.AllocResourceUsage, error) {
+	args := &cstructs.AllocStatsRequest{
+		AllocID: allocID,
+		QueryOptions: structs.QueryOptions{
+			Region:    d.s.config.Region,
+			AuthToken: d.s.getLeaderAcl(),
+		},
+	}
+
+	// The RPC can't be canceled, so stop waiting for it once the context is
+	// done. The buffered channel lets the RPC complete in the background.
+	type statsResult struct {
+		stats *cstructs.AllocResourceUsage
+		err   error
+	}
+	resultCh := make(chan statsResult, 1)
+	go func() {
+		var reply cstructs.AllocStatsResponse
+		err := d.s.staticEndpoints.ClientAllocations.Stats(args, &reply)
+		resultCh <- statsResult{stats: reply.Stats, err: err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.stats, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
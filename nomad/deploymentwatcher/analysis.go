@@ -0,0 +1,479 @@
+package deploymentwatcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// analysisQueryTimeout is the timeout of the queries of the metrics of
+	// the allocations analyzed.
+	analysisQueryTimeout = 10 * time.Second
+)
+
+// AllocStatsRPC is used to read the resource usage of allocations when
+// analyzing canaries with the Nomad metrics source.
+type AllocStatsRPC interface {
+	// AllocStats returns the resource usage of the allocation, giving up
+	// once the context is done
+	AllocStats(ctx context.Context, allocID string) (*cstructs.AllocResourceUsage, error)
+}
+
+// canaryAnalysis is the state of the analysis of the canaries of a task
+// group. It is only accessed by the watch loop.
+type canaryAnalysis struct {
+	// lastCheck is the time the metrics were last analyzed
+	lastCheck time.Time
+
+	// failures is the number of failed analyses
+	failures int
+
+	// passed is set once the canaries passed the analysis for the bake time
+	passed bool
+}
+
+// analysisResult is used to return the desired actions given an analysis of
+// the canaries of the deployment.
+type analysisResult struct {
+	failDeployment bool
+	rollback       bool
+	desc           string
+
+	// passed is set if the canaries of a task group passed their analysis
+	passed bool
+
+	// next is the time of the next analysis, or zero if none is needed
+	next time.Time
+}
+
+// analysisQueryData is the data given to the templates of the Prometheus
+// queries of analysis metrics.
+type analysisQueryData struct {
+	// AllocIDs is a regular expression matching the analyzed allocations
+	AllocIDs string
+
+	Namespace string
+	Job       string
+	Group     string
+}
+
+// hasAnalysis returns whether a task group of the deployment analyzes its
+// canaries.
+func (w *deploymentWatcher) hasAnalysis() bool {
+	for _, tg := range w.j.TaskGroups {
+		if tg.Update != nil && tg.Update.Analysis != nil && tg.Update.Canary > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// analysisPassed returns whether the canaries of the task group passed their
+// analysis, if the task group has one.
+func (w *deploymentWatcher) analysisPassed(group string) bool {
+	tg := w.j.LookupTaskGroup(group)
+	if tg == nil || tg.Update == nil || tg.Update.Analysis == nil {
+		return true
+	}
+	analysis, ok := w.analyses[group]
+	return ok && analysis.passed
+}
+
+// analysisCheck is an analysis of the canaries of a task group that is due.
+type analysisCheck struct {
+	group        string
+	strategy     *structs.AnalysisStrategy
+	autoRevert   bool
+	healthySince time.Time
+	now          time.Time
+
+	// canaries and stable are the IDs of the canaries and of the stable
+	// allocations of the task group
+	canaries []string
+	stable   []string
+
+	// reason is why the canaries failed the analysis, and err is set if the
+	// analysis couldn't be completed
+	reason string
+	err    error
+}
+
+// analyzeCanaries analyzes the metrics of the canaries of the task groups
+// with an analysis strategy that are due to be analyzed at the given time.
+// The watch loop runs the analyses without blocking, see dueAnalyses.
+func (w *deploymentWatcher) analyzeCanaries(allocs []*structs.AllocListStub, now time.Time) analysisResult {
+	checks, next := w.dueAnalyses(allocs, now)
+	w.runAnalyses(checks)
+	res := w.applyAnalyses(checks)
+	res.next = next
+	return res
+}
+
+// dueAnalyses returns the analyses of the task groups that are due at the
+// given time, along with the time of the next analysis or zero if none is
+// needed. The analyses must then be run by runAnalyses, and their outcome
+// applied by applyAnalyses.
+func (w *deploymentWatcher) dueAnalyses(allocs []*structs.AllocListStub, now time.Time) ([]*analysisCheck, time.Time) {
+	var checks []*analysisCheck
+	var nextCheck time.Time
+	d := w.getDeployment()
+
+	for name, dstate := range d.TaskGroups {
+		tg := w.j.LookupTaskGroup(name)
+		if tg == nil || tg.Update == nil || tg.Update.Analysis == nil {
+			continue
+		}
+		if dstate.DesiredCanaries == 0 || dstate.Promoted {
+			continue
+		}
+
+		strategy := tg.Update.Analysis
+		analysis, ok := w.analyses[name]
+		if !ok {
+			analysis = &canaryAnalysis{}
+			w.analyses[name] = analysis
+		}
+		if analysis.passed {
+			continue
+		}
+
+		// Schedule the next analysis of the group
+		next := now.Add(strategy.Interval)
+		if due := analysis.lastCheck.Add(strategy.Interval); now.Before(due) {
+			next = due
+		}
+		if nextCheck.IsZero() || next.Before(nextCheck) {
+			nextCheck = next
+		}
+
+		// Only analyze running deployments whose canaries are all healthy
+		if d.Status != structs.DeploymentStatusRunning || now.Before(analysis.lastCheck.Add(strategy.Interval)) {
+			continue
+		}
+		canaries, healthySince := healthyCanaries(dstate, allocs)
+		if canaries == nil {
+			continue
+		}
+		analysis.lastCheck = now
+
+		stable, err := w.stableAllocs(name)
+		if err != nil {
+			w.logger.Error("failed to lookup stable allocations", "task_group", name, "error", err)
+			continue
+		}
+
+		checks = append(checks, &analysisCheck{
+			group:        name,
+			strategy:     strategy,
+			autoRevert:   dstate.AutoRevert,
+			healthySince: healthySince,
+			now:          now,
+			canaries:     canaries,
+			stable:       stable,
+		})
+	}
+
+	return checks, nextCheck
+}
+
+// runAnalyses queries the metrics of the analyses. It doesn't access the
+// state of the watch loop, so it may run concurrently with it.
+func (w *deploymentWatcher) runAnalyses(checks []*analysisCheck) {
+	for _, c := range checks {
+		c.reason, c.err = w.analyze(c.strategy, c.group, c.canaries, c.stable)
+	}
+}
+
+// applyAnalyses updates the state of the analyses of the task groups with
+// the outcome of the analyses run, and returns the desired actions.
+func (w *deploymentWatcher) applyAnalyses(checks []*analysisCheck) analysisResult {
+	var res analysisResult
+	for _, c := range checks {
+		analysis, ok := w.analyses[c.group]
+		if !ok {
+			continue
+		}
+
+		// Analyses that couldn't be completed don't count towards the bake
+		// time or the failure limit
+		if c.err != nil {
+			w.logger.Warn("failed to analyze canaries", "task_group", c.group, "error", c.err)
+			continue
+		}
+
+		if c.reason != "" {
+			analysis.failures++
+			w.logger.Info("canary analysis failed", "task_group", c.group, "reason", c.reason,
+				"failures", analysis.failures, "failure_limit", c.strategy.FailureLimit)
+			if analysis.failures > c.strategy.FailureLimit {
+				res.failDeployment = true
+				res.rollback = c.autoRevert
+				res.desc = fmt.Sprintf("%s of task group %q: %s",
+					structs.DeploymentStatusDescriptionFailedAnalysis, c.group, c.reason)
+				return res
+			}
+			continue
+		}
+
+		if !c.now.Before(c.healthySince.Add(c.strategy.BakeTime)) {
+			w.logger.Info("canary analysis passed", "task_group", c.group)
+			analysis.passed = true
+			res.passed = true
+		}
+	}
+
+	return res
+}
+
+// healthyCanaries returns the IDs of the canaries of the deployment state if
+// they're all placed and healthy, along with the time the last of them became
+// healthy.
+func healthyCanaries(dstate *structs.DeploymentState, allocs []*structs.AllocListStub) ([]string, time.Time) {
+	if len(dstate.PlacedCanaries) < dstate.DesiredCanaries {
+		return nil, time.Time{}
+	}
+
+	byID := make(map[string]*structs.AllocListStub, len(allocs))
+	for _, alloc := range allocs {
+		byID[alloc.ID] = alloc
+	}
+
+	var healthySince time.Time
+	for _, id := range dstate.PlacedCanaries {
+		alloc, ok := byID[id]
+		if !ok || !alloc.DeploymentStatus.IsHealthy() {
+			return nil, time.Time{}
+		}
+		if alloc.DeploymentStatus.Timestamp.After(healthySince) {
+			healthySince = alloc.DeploymentStatus.Timestamp
+		}
+	}
+	return dstate.PlacedCanaries, healthySince
+}
+
+// stableAllocs returns the IDs of the running allocations of the task group
+// that aren't part of the deployment.
+func (w *deploymentWatcher) stableAllocs(group string) ([]string, error) {
+	allocs, err := w.state.AllocsByJob(nil, w.j.Namespace, w.j.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, alloc := range allocs {
+		if alloc.TaskGroup != group || alloc.DeploymentID == w.deploymentID {
+			continue
+		}
+		if alloc.TerminalStatus() || alloc.ClientStatus != structs.AllocClientStatusRunning {
+			continue
+		}
+		ids = append(ids, alloc.ID)
+	}
+	return ids, nil
+}
+
+// analyze compares the metrics of the canaries to their thresholds and to the
+// metrics of the stable allocations. It returns the reason the canaries
+// failed the analysis, or an empty string if they passed.
+func (w *deploymentWatcher) analyze(strategy *structs.AnalysisStrategy, group string, canaries, stable []string) (string, error) {
+	for _, m := range strategy.Metrics {
+		value, err := w.queryMetric(strategy, m, group, canaries)
+		if err != nil {
+			return "", fmt.Errorf("failed to query metric %q of canaries: %v", m.Name, err)
+		}
+
+		if m.Max != nil && value > *m.Max {
+			return fmt.Sprintf("metric %q is %v, above the maximum of %v", m.Name, value, *m.Max), nil
+		}
+
+		// Canaries can only be compared to stable allocations if there are any
+		if m.MaxIncrease == nil || len(stable) == 0 {
+			continue
+		}
+
+		base, err := w.queryMetric(strategy, m, group, stable)
+		if err != nil {
+			return "", fmt.Errorf("failed to query metric %q of stable allocations: %v", m.Name, err)
+		}
+		if limit := base * (1 + *m.MaxIncrease); value > limit {
+			return fmt.Sprintf("metric %q is %v, more than %v%% above %v for stable allocations",
+				m.Name, value, *m.MaxIncrease*100, base), nil
+		}
+	}
+	return "", nil
+}
+
+// queryMetric returns the value of the metric for the given allocations.
+func (w *deploymentWatcher) queryMetric(strategy *structs.AnalysisStrategy, m *structs.AnalysisMetric, group string, allocIDs []string) (float64, error) {
+	switch strategy.Source {
+	case structs.AnalysisSourcePrometheus:
+		// Jobs may only query the addresses allowed by the operator, as
+		// queries are made from the servers
+		if !w.analysisConfig.AllowsPrometheusAddress(strategy.Address) {
+			return 0, fmt.Errorf("prometheus address %q is not allowed by the server's canary_analysis configuration", strategy.Address)
+		}
+
+		tmpl, err := m.QueryTemplate()
+		if err != nil {
+			return 0, err
+		}
+
+		quoted := make([]string, len(allocIDs))
+		for i, id := range allocIDs {
+			quoted[i] = regexp.QuoteMeta(id)
+		}
+
+		var query strings.Builder
+		data := analysisQueryData{
+			AllocIDs:  strings.Join(quoted, "|"),
+			Namespace: w.j.Namespace,
+			Job:       w.j.ID,
+			Group:     group,
+		}
+		if err := tmpl.Execute(&query, data); err != nil {
+			return 0, err
+		}
+
+		ctx, cancel := context.WithTimeout(w.ctx, analysisQueryTimeout)
+		defer cancel()
+		return queryPrometheus(ctx, strategy.Address, query.String())
+
+	case structs.AnalysisSourceNomad:
+		return w.queryAllocStats(m.Query, allocIDs)
+
+	default:
+		return 0, fmt.Errorf("unknown analysis source %q", strategy.Source)
+	}
+}
+
+// queryAllocStats returns the average of the resource usage metric of the
+// allocations.
+func (w *deploymentWatcher) queryAllocStats(metric string, allocIDs []string) (float64, error) {
+	if w.allocStats == nil {
+		return 0, fmt.Errorf("allocation stats unavailable")
+	}
+	if len(allocIDs) == 0 {
+		return 0, fmt.Errorf("no allocations to query")
+	}
+
+	var total float64
+	for _, id := range allocIDs {
+		ctx, cancel := context.WithTimeout(w.ctx, analysisQueryTimeout)
+		stats, err := w.allocStats.AllocStats(ctx, id)
+		cancel()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read stats of allocation %q: %v", id, err)
+		}
+		if stats == nil || stats.ResourceUsage == nil ||
+			stats.ResourceUsage.CpuStats == nil || stats.ResourceUsage.MemoryStats == nil {
+			return 0, fmt.Errorf("no stats for allocation %q", id)
+		}
+
+		usage := stats.ResourceUsage
+		switch metric {
+		case structs.AnalysisMetricCPUPercent:
+			total += usage.CpuStats.Percent
+		case structs.AnalysisMetricCPUThrottledTime:
+			total += float64(usage.CpuStats.ThrottledTime)
+		case structs.AnalysisMetricMemoryRSS:
+			total += float64(usage.MemoryStats.RSS)
+		case structs.AnalysisMetricMemoryUsage:
+			total += float64(usage.MemoryStats.Usage)
+		default:
+			return 0, fmt.Errorf("unknown metric %q", metric)
+		}
+	}
+	return total / float64(len(allocIDs)), nil
+}
+
+// prometheusResponse is the response of the query endpoint of the Prometheus
+// HTTP API.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// prometheusClient is the HTTP client of the Prometheus queries. It doesn't
+// follow redirects, so that queries can't reach addresses other than the
+// allowed ones.
+var prometheusClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// queryPrometheus returns the value of the instant query against the
+// Prometheus compatible HTTP API at the address. The query must return a
+// scalar or a vector with a single sample.
+func queryPrometheus(ctx context.Context, address, query string) (float64, error) {
+	u := strings.TrimSuffix(address, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := prometheusClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var out prometheusResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		return 0, fmt.Errorf("failed to decode response with status %d: %v", resp.StatusCode, err)
+	}
+	if out.Status != "success" {
+		return 0, fmt.Errorf("query failed: %s", out.Error)
+	}
+
+	// Samples are a timestamp and the value as a string
+	var sample []interface{}
+	switch out.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(out.Data.Result, &sample); err != nil {
+			return 0, err
+		}
+	case "vector":
+		var vector []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(out.Data.Result, &vector); err != nil {
+			return 0, err
+		}
+		if len(vector) != 1 {
+			return 0, fmt.Errorf("query returned %d samples, expected one", len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", out.Data.ResultType)
+	}
+
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("invalid sample %v", sample)
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("invalid sample value %v", sample[1])
+	}
+	return strconv.ParseFloat(value, 64)
+}
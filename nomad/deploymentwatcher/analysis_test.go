@@ -0,0 +1,394 @@
+package deploymentwatcher
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	mocker "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testAnalysisWatcher returns a deployment watcher of a job whose web task
+// group analyzes its canary, the canary and a stable allocation. The watch
+// loop of the deployment watcher isn't started.
+func testAnalysisWatcher(t *testing.T, analysis *structs.AnalysisStrategy, healthySince time.Time) (*deploymentWatcher, *mockBackend, []*structs.AllocListStub, *structs.Allocation) {
+	m := newMockBackend(t)
+
+	upd := structs.DefaultUpdateStrategy.Copy()
+	upd.Canary = 1
+	upd.AutoPromote = true
+	upd.AutoRevert = true
+	upd.Analysis = analysis
+	j := mock.Job()
+	j.TaskGroups[0].Update = upd
+	require.NoError(t, j.TaskGroups[0].Update.Validate())
+	require.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), j))
+	j, err := m.state.JobByID(nil, j.Namespace, j.ID)
+	require.NoError(t, err)
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].DesiredCanaries = 1
+	d.TaskGroups["web"].AutoRevert = true
+
+	stable := mock.Alloc()
+	stable.Job = j
+	stable.JobID = j.ID
+	stable.ClientStatus = structs.AllocClientStatusRunning
+
+	canary := mock.Alloc()
+	canary.Job = j
+	canary.JobID = j.ID
+	canary.DeploymentID = d.ID
+	canary.ClientStatus = structs.AllocClientStatusRunning
+	canary.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy:   helper.BoolToPtr(true),
+		Timestamp: healthySince,
+		Canary:    true,
+	}
+	d.TaskGroups["web"].PlacedCanaries = []string{canary.ID}
+
+	require.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+	require.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{stable, canary}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	w := &deploymentWatcher{
+		deploymentID: d.ID,
+		d:            d,
+		j:            j,
+		state:        m.state,
+		allocStats:   m,
+		analysisConfig: &config.CanaryAnalysisConfig{
+			PrometheusAddresses: []string{analysis.Address},
+		},
+		analyses: make(map[string]*canaryAnalysis),
+		logger:   testlog.HCLogger(t),
+		ctx:      ctx,
+	}
+	return w, m, []*structs.AllocListStub{canary.Stub(nil)}, stable
+}
+
+func TestDeploymentWatcher_AnalyzeCanaries_Prometheus(t *testing.T) {
+	ci.Parallel(t)
+
+	var canaryValue float64
+	var canaryID string
+	addr := testMetricsServer(t, func(query string) float64 {
+		if strings.Contains(query, canaryID) {
+			return canaryValue
+		}
+		return 0.04
+	})
+
+	analysis := &structs.AnalysisStrategy{
+		Source:   structs.AnalysisSourcePrometheus,
+		Address:  addr,
+		Interval: time.Minute,
+		BakeTime: 10 * time.Minute,
+		Metrics: []*structs.AnalysisMetric{
+			{
+				Name:        "error_rate",
+				Query:       `sum(rate(errors{alloc_id=~"{{ .AllocIDs }}",job="{{ .Job }}"}[1m]))`,
+				Max:         helper.Float64ToPtr(0.1),
+				MaxIncrease: helper.Float64ToPtr(0.5),
+			},
+		},
+	}
+
+	now := time.Now()
+	w, _, allocs, _ := testAnalysisWatcher(t, analysis, now.Add(-5*time.Minute))
+	canaryID = allocs[0].ID
+
+	// Canaries within the thresholds don't pass before the bake time
+	canaryValue = 0.05
+	res := w.analyzeCanaries(allocs, now)
+	require.False(t, res.failDeployment)
+	require.False(t, res.passed)
+	require.Equal(t, now.Add(time.Minute), res.next)
+	require.False(t, w.analysisPassed("web"))
+
+	// Analyses aren't repeated before the interval
+	canaryValue = 1
+	res = w.analyzeCanaries(allocs, now.Add(30*time.Second))
+	require.False(t, res.failDeployment)
+	require.Equal(t, now.Add(time.Minute), res.next)
+
+	// Canaries regressing compared to stable allocations fail the deployment
+	canaryValue = 0.07
+	res = w.analyzeCanaries(allocs, now.Add(time.Minute))
+	require.True(t, res.failDeployment)
+	require.True(t, res.rollback)
+	require.Contains(t, res.desc, structs.DeploymentStatusDescriptionFailedAnalysis)
+	require.Contains(t, res.desc, `metric "error_rate" is 0.07, more than 50% above 0.04`)
+
+	// Canaries passing the analysis for the bake time pass
+	w, _, allocs, _ = testAnalysisWatcher(t, analysis, now.Add(-10*time.Minute))
+	canaryID = allocs[0].ID
+	canaryValue = 0.05
+	res = w.analyzeCanaries(allocs, now)
+	require.False(t, res.failDeployment)
+	require.True(t, res.passed)
+	require.True(t, w.analysisPassed("web"))
+
+	// Canaries that passed aren't analyzed anymore
+	res = w.analyzeCanaries(allocs, now.Add(time.Minute))
+	require.False(t, res.passed)
+	require.True(t, res.next.IsZero())
+}
+
+func TestDeploymentWatcher_AnalyzeCanaries_FailureLimit(t *testing.T) {
+	ci.Parallel(t)
+
+	addr := testMetricsServer(t, func(string) float64 { return 0.5 })
+	analysis := &structs.AnalysisStrategy{
+		Source:       structs.AnalysisSourcePrometheus,
+		Address:      addr,
+		Interval:     time.Minute,
+		FailureLimit: 1,
+		Metrics: []*structs.AnalysisMetric{
+			{
+				Name:  "error_rate",
+				Query: `sum(rate(errors{alloc_id=~"{{ .AllocIDs }}"}[1m]))`,
+				Max:   helper.Float64ToPtr(0.1),
+			},
+		},
+	}
+
+	now := time.Now()
+	w, _, allocs, _ := testAnalysisWatcher(t, analysis, now)
+
+	res := w.analyzeCanaries(allocs, now)
+	require.False(t, res.failDeployment)
+	require.False(t, res.passed)
+
+	res = w.analyzeCanaries(allocs, now.Add(time.Minute))
+	require.True(t, res.failDeployment)
+	require.Contains(t, res.desc, `metric "error_rate" is 0.5, above the maximum of 0.1`)
+}
+
+func TestDeploymentWatcher_AnalyzeCanaries_Unhealthy(t *testing.T) {
+	ci.Parallel(t)
+
+	analysis := &structs.AnalysisStrategy{
+		Source:   structs.AnalysisSourcePrometheus,
+		Address:  "http://127.0.0.1:0",
+		Interval: time.Minute,
+		Metrics: []*structs.AnalysisMetric{
+			{Name: "error_rate", Query: "errors", Max: helper.Float64ToPtr(0)},
+		},
+	}
+
+	now := time.Now()
+	w, _, allocs, _ := testAnalysisWatcher(t, analysis, now)
+
+	// Canaries aren't analyzed until they're healthy
+	allocs[0].DeploymentStatus.Healthy = nil
+	res := w.analyzeCanaries(allocs, now)
+	require.False(t, res.failDeployment)
+	require.False(t, res.passed)
+	require.Equal(t, now.Add(time.Minute), res.next)
+	require.True(t, w.analyses["web"].lastCheck.IsZero())
+
+	// Nor are they if the metrics can't be queried
+	allocs[0].DeploymentStatus.Healthy = helper.BoolToPtr(true)
+	res = w.analyzeCanaries(allocs, now)
+	require.False(t, res.failDeployment)
+	require.False(t, res.passed)
+	require.Zero(t, w.analyses["web"].failures)
+}
+
+func TestDeploymentWatcher_AnalyzeCanaries_PrometheusNotAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	queried := false
+	addr := testMetricsServer(t, func(string) float64 {
+		queried = true
+		return 1
+	})
+
+	analysis := &structs.AnalysisStrategy{
+		Source:   structs.AnalysisSourcePrometheus,
+		Address:  addr,
+		Interval: time.Minute,
+		Metrics: []*structs.AnalysisMetric{
+			{Name: "error_rate", Query: "errors", Max: helper.Float64ToPtr(0)},
+		},
+	}
+
+	now := time.Now()
+	w, _, allocs, _ := testAnalysisWatcher(t, analysis, now)
+	w.analysisConfig = &config.CanaryAnalysisConfig{
+		PrometheusAddresses: []string{"http://prometheus:9090"},
+	}
+
+	// Addresses that aren't allowed aren't queried, and the analysis doesn't
+	// count as failed
+	res := w.analyzeCanaries(allocs, now)
+	require.False(t, res.failDeployment)
+	require.False(t, res.passed)
+	require.False(t, queried)
+	require.Zero(t, w.analyses["web"].failures)
+}
+
+func TestDeploymentWatcher_Watch_AnalysisNotBlocking(t *testing.T) {
+	ci.Parallel(t)
+
+	// The metrics server doesn't respond until the end of the test
+	queried := make(chan struct{}, 1)
+	release := make(chan struct{})
+	addr := testMetricsServer(t, func(string) float64 {
+		select {
+		case queried <- struct{}{}:
+		default:
+		}
+		<-release
+		return 0
+	})
+	t.Cleanup(func() { close(release) })
+
+	w, m := testDeploymentWatcher(t, 1000.0, 1*time.Millisecond)
+	w.analysisConfig = &config.CanaryAnalysisConfig{
+		PrometheusAddresses: []string{addr},
+	}
+
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.Canary = 1
+	j.TaskGroups[0].Update.ProgressDeadline = 0
+	j.TaskGroups[0].Update.Analysis = &structs.AnalysisStrategy{
+		Source:   structs.AnalysisSourcePrometheus,
+		Address:  addr,
+		Interval: 10 * time.Millisecond,
+		BakeTime: time.Hour,
+		Metrics: []*structs.AnalysisMetric{
+			{Name: "error_rate", Query: "errors", Max: helper.Float64ToPtr(1)},
+		},
+	}
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].ProgressDeadline = 0
+	d.TaskGroups["web"].DesiredCanaries = 1
+
+	canary := mock.Alloc()
+	canary.Job = j
+	canary.JobID = j.ID
+	canary.DeploymentID = d.ID
+	canary.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy:   helper.BoolToPtr(true),
+		Timestamp: time.Now(),
+		Canary:    true,
+	}
+	d.TaskGroups["web"].PlacedCanaries = []string{canary.ID}
+
+	require.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), j))
+	require.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+	require.NoError(t, m.state.UpsertAllocs(structs.MsgTypeTestSetup, m.nextIndex(), []*structs.Allocation{canary}))
+
+	m.On("UpdateAllocDesiredTransition", mocker.Anything).Return(nil)
+	matcher := matchDeploymentStatusUpdateRequest(&matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusFailed,
+		StatusDescription: structs.DeploymentStatusDescriptionFailedAllocations,
+		Eval:              true,
+	})
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	select {
+	case <-queried:
+	case <-time.After(5 * time.Second):
+		t.Fatal("canaries weren't analyzed")
+	}
+
+	// The watch loop handles the unhealthy canary while its analysis is
+	// still running
+	req := &structs.ApplyDeploymentAllocHealthRequest{
+		DeploymentAllocHealthRequest: structs.DeploymentAllocHealthRequest{
+			DeploymentID:           d.ID,
+			UnhealthyAllocationIDs: []string{canary.ID},
+		},
+	}
+	require.NoError(t, m.state.UpdateDeploymentAllocHealth(structs.MsgTypeTestSetup, m.nextIndex(), req))
+
+	testutil.WaitForResultUntil(2*time.Second, func() (bool, error) {
+		d, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		return d.Status == structs.DeploymentStatusFailed, fmt.Errorf("bad status %q", d.Status)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestDeploymentWatcher_AnalyzeCanaries_Nomad(t *testing.T) {
+	ci.Parallel(t)
+
+	analysis := &structs.AnalysisStrategy{
+		Source:   structs.AnalysisSourceNomad,
+		Interval: time.Minute,
+		Metrics: []*structs.AnalysisMetric{
+			{
+				Name:        "memory",
+				Query:       structs.AnalysisMetricMemoryRSS,
+				MaxIncrease: helper.Float64ToPtr(0.2),
+			},
+		},
+	}
+
+	usage := func(rss uint64) *cstructs.AllocResourceUsage {
+		return &cstructs.AllocResourceUsage{
+			ResourceUsage: &cstructs.ResourceUsage{
+				MemoryStats: &cstructs.MemoryStats{RSS: rss},
+				CpuStats:    &cstructs.CpuStats{},
+			},
+		}
+	}
+
+	now := time.Now()
+	w, m, allocs, stable := testAnalysisWatcher(t, analysis, now)
+	m.On("AllocStats", allocs[0].ID).Return(usage(200), nil)
+	m.On("AllocStats", stable.ID).Return(usage(100), nil)
+
+	res := w.analyzeCanaries(allocs, now)
+	require.True(t, res.failDeployment)
+	require.Contains(t, res.desc, `metric "memory" is 200, more than 20% above 100`)
+}
+
+func TestQueryPrometheus(t *testing.T) {
+	ci.Parallel(t)
+
+	addr := testMetricsServer(t, func(query string) float64 {
+		if query != "up" {
+			return 0
+		}
+		return 1.5
+	})
+
+	value, err := queryPrometheus(context.Background(), addr, "up")
+	require.NoError(t, err)
+	require.Equal(t, 1.5, value)
+
+	_, err = queryPrometheus(context.Background(), addr+"/missing", "up")
+	require.Error(t, err)
+
+	// Redirects aren't followed
+	redirect := httptest.NewServer(http.RedirectHandler(addr+"/api/v1/query", http.StatusFound))
+	t.Cleanup(redirect.Close)
+	_, err = queryPrometheus(context.Background(), redirect.URL, "up")
+	require.Error(t, err)
+}
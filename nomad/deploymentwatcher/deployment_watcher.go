@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"golang.org/x/time/rate"
 )
 
@@ -70,6 +71,14 @@ type deploymentWatcher struct {
 	// in enterprise edition
 	JobRPC
 
+	// allocStats is used to read the resource usage of allocations when
+	// analyzing canaries
+	allocStats AllocStatsRPC
+
+	// analysisConfig configures the sources of metrics that canaries may be
+	// analyzed with
+	analysisConfig *config.CanaryAnalysisConfig
+
 	// state is the state that is watched for state changes.
 	state *state.StateStore
 
@@ -98,6 +107,14 @@ type deploymentWatcher struct {
 	// by holding the lock or using the setter and getter methods.
 	latestEval uint64
 
+	// analyses is the state of the analysis of the canaries of each task
+	// group. It is only accessed by the watch loop.
+	analyses map[string]*canaryAnalysis
+
+	// rolloutPauses is the pause of the current rollout step of each task
+	// group. It is only accessed by the watch loop.
+	rolloutPauses map[string]*rolloutPause
+
 	logger log.Logger
 	ctx    context.Context
 	exitFn context.CancelFunc
@@ -108,7 +125,8 @@ type deploymentWatcher struct {
 // deployments and trigger the scheduler as needed.
 func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 	logger log.Logger, state *state.StateStore, d *structs.Deployment,
-	j *structs.Job, triggers deploymentTriggers,
+	j *structs.Job, triggers deploymentTriggers, allocStats AllocStatsRPC,
+	analysisConfig *config.CanaryAnalysisConfig,
 	deploymentRPC DeploymentRPC, jobRPC JobRPC) *deploymentWatcher {
 
 	ctx, exitFn := context.WithCancel(parent)
@@ -122,6 +140,10 @@ func newDeploymentWatcher(parent context.Context, queryLimiter *rate.Limiter,
 		deploymentTriggers: triggers,
 		DeploymentRPC:      deploymentRPC,
 		JobRPC:             jobRPC,
+		allocStats:         allocStats,
+		analysisConfig:     analysisConfig,
+		analyses:           make(map[string]*canaryAnalysis),
+		rolloutPauses:      make(map[string]*rolloutPause),
 		logger:             logger.With("deployment_id", d.ID, "job", j.NamespacedID()),
 		ctx:                ctx,
 		exitFn:             exitFn,
@@ -285,7 +307,7 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 
 	// AutoPromote iff every task group with canaries is marked auto_promote and is healthy. The whole
 	// job version has been incremented, so we promote together. See also AutoRevert
-	for _, dstate := range d.TaskGroups {
+	for name, dstate := range d.TaskGroups {
 
 		// skip auto promote canary validation if the task group has no canaries
 		// to prevent auto promote hanging on mixed canary/non-canary taskgroup deploys
@@ -297,6 +319,11 @@ func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStu
 			return nil
 		}
 
+		// Canaries being analyzed are only promoted once they passed
+		if !w.analysisPassed(name) {
+			return nil
+		}
+
 		// Find the health status of each canary
 		for _, c := range dstate.PlacedCanaries {
 			for _, a := range allocs {
@@ -426,7 +453,33 @@ func (w *deploymentWatcher) watch() {
 	allocsCh := w.getAllocsCh(allocIndex)
 	var updates *allocUpdates
 
+	// Analyze the canaries as soon as the deployment starts if the job has a
+	// canary analysis. The analyses run outside of the watch loop, which
+	// receives them on analysisDoneCh once they're complete, and the timer is
+	// then reset to the next analysis. Only one analysis runs at a time.
+	var analysisCh <-chan time.Time
+	var analysisTimer *time.Timer
+	var analysisNext time.Time
+	analysisDoneCh := make(chan []*analysisCheck, 1)
+	if w.hasAnalysis() {
+		analysisTimer = time.NewTimer(0)
+		defer analysisTimer.Stop()
+		analysisCh = analysisTimer.C
+	}
+
+	// Promote the rollout steps that are due as soon as the deployment starts
+	// if the job has a progressive rollout. The timer is then reset to the
+	// end of the next pause.
+	var stepCh <-chan time.Time
+	var stepTimer *time.Timer
+	if w.hasRolloutSteps() {
+		stepTimer = time.NewTimer(0)
+		defer stepTimer.Stop()
+		stepCh = stepTimer.C
+	}
+
 	rollback, deadlineHit := false, false
+	analysisDesc := ""
 
 FAIL:
 	for {
@@ -495,6 +548,55 @@ FAIL:
 				break FAIL
 			}
 
+		case <-analysisCh:
+			var allocs []*structs.AllocListStub
+			if updates != nil {
+				allocs = updates.allocs
+			}
+
+			checks, next := w.dueAnalyses(allocs, time.Now())
+			analysisNext = next
+			if len(checks) == 0 {
+				if !next.IsZero() {
+					analysisTimer.Reset(time.Until(next))
+				}
+				continue
+			}
+
+			go func() {
+				w.runAnalyses(checks)
+				analysisDoneCh <- checks
+			}()
+
+		case checks := <-analysisDoneCh:
+			// The deployment has failed its canary analysis, so break out of
+			// the watch loop and handle the failure
+			res := w.applyAnalyses(checks)
+			if res.failDeployment {
+				rollback = res.rollback
+				analysisDesc = res.desc
+				err := w.nextRegion(structs.DeploymentStatusFailed)
+				if err != nil {
+					w.logger.Error("multiregion deployment error", "error", err)
+				}
+				break FAIL
+			}
+
+			// If permitted, automatically promote the canaries that passed
+			// their analysis
+			if res.passed && updates != nil {
+				if err := w.autoPromoteDeployment(updates.allocs); err != nil {
+					w.logger.Error("failed to auto promote deployment", "error", err)
+				}
+			}
+
+			if !analysisNext.IsZero() {
+				analysisTimer.Reset(time.Until(analysisNext))
+			}
+
+		case <-stepCh:
+			w.promoteRolloutSteps(stepTimer)
+
 		case updates = <-allocsCh:
 			if err := updates.err; err != nil {
 				if err == context.Canceled || w.ctx.Err() == context.Canceled {
@@ -535,6 +637,11 @@ FAIL:
 				w.logger.Error("failed to auto promote deployment", "error", err)
 			}
 
+			// Move on to the next rollout steps that are complete
+			if stepTimer != nil {
+				w.promoteRolloutSteps(stepTimer)
+			}
+
 			// Create an eval to push the deployment along
 			if res.createEval || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
@@ -550,6 +657,9 @@ FAIL:
 	if deadlineHit {
 		desc = structs.DeploymentStatusDescriptionProgressDeadline
 	}
+	if analysisDesc != "" {
+		desc = analysisDesc
+	}
 
 	// Rollback to the old job if necessary
 	var j *structs.Job
@@ -675,6 +785,9 @@ func (w *deploymentWatcher) shouldFail() (fail, rollback bool, err error) {
 			if dstate.HealthyAllocs >= dstate.DesiredCanaries {
 				continue
 			}
+		} else if dstate.StepComplete() {
+			// Rollouts waiting on a rollout step to be promoted don't fail
+			continue
 		} else if dstate.HealthyAllocs >= dstate.DesiredTotal {
 			continue
 		}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 const (
@@ -72,6 +73,14 @@ type Watcher struct {
 	// deployments watcher
 	raft DeploymentRaftEndpoints
 
+	// allocStats is used to read the resource usage of allocations when
+	// analyzing canaries
+	allocStats AllocStatsRPC
+
+	// analysisConfig configures the sources of metrics that canaries may be
+	// analyzed with
+	analysisConfig *config.CanaryAnalysisConfig
+
 	// state is the state that is watched for state changes.
 	state *state.StateStore
 
@@ -99,6 +108,8 @@ type Watcher struct {
 // deployments and trigger the scheduler as needed.
 func NewDeploymentsWatcher(logger log.Logger,
 	raft DeploymentRaftEndpoints,
+	allocStats AllocStatsRPC,
+	analysisConfig *config.CanaryAnalysisConfig,
 	deploymentRPC DeploymentRPC, jobRPC JobRPC,
 	stateQueriesPerSecond float64,
 	updateBatchDuration time.Duration,
@@ -106,6 +117,8 @@ func NewDeploymentsWatcher(logger log.Logger,
 
 	return &Watcher{
 		raft:                raft,
+		allocStats:          allocStats,
+		analysisConfig:      analysisConfig,
 		deploymentRPC:       deploymentRPC,
 		jobRPC:              jobRPC,
 		queryLimiter:        rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
@@ -269,7 +282,7 @@ func (w *Watcher) addLocked(d *structs.Deployment) (*deploymentWatcher, error) {
 	}
 
 	watcher := newDeploymentWatcher(w.ctx, w.queryLimiter, w.logger, w.state, d, job,
-		w, w.deploymentRPC, w.jobRPC)
+		w, w.allocStats, w.analysisConfig, w.deploymentRPC, w.jobRPC)
 	w.watchers[d.ID] = watcher
 	return watcher, nil
 }
@@ -0,0 +1,110 @@
+package deploymentwatcher
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// rolloutPause is the pause of the current rollout step of a task group. It
+// is only accessed by the watch loop.
+type rolloutPause struct {
+	// step is the index of the paused rollout step
+	step int
+
+	// until is the time the pause ends
+	until time.Time
+}
+
+// hasRolloutSteps returns whether a task group of the deployment has a
+// progressive rollout.
+func (w *deploymentWatcher) hasRolloutSteps() bool {
+	for _, tg := range w.j.TaskGroups {
+		if tg.Update != nil && len(tg.Update.Steps) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// promoteRolloutSteps promotes the rollout steps that are due and resets the
+// timer to the end of the next pause.
+func (w *deploymentWatcher) promoteRolloutSteps(timer *time.Timer) {
+	next, err := w.advanceRolloutSteps(time.Now())
+	if err != nil {
+		w.logger.Error("failed to promote rollout step", "error", err)
+	}
+
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	if !next.IsZero() {
+		timer.Reset(time.Until(next))
+	}
+}
+
+// advanceRolloutSteps promotes the rollout step of the task groups whose
+// current step is complete and isn't manual, once the pause of the step has
+// elapsed at the given time. It returns the end of the next pause, or zero if
+// there is none.
+func (w *deploymentWatcher) advanceRolloutSteps(now time.Time) (time.Time, error) {
+	// Health updates are applied to the deployment along with the allocations,
+	// so read the latest deployment from the state
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return time.Time{}, err
+	}
+	d, err := snap.DeploymentByID(nil, w.deploymentID)
+	if err != nil || d == nil || d.Status != structs.DeploymentStatusRunning {
+		return time.Time{}, err
+	}
+
+	var next time.Time
+	var groups []string
+	for name, dstate := range d.TaskGroups {
+		step := dstate.ActiveStep()
+		if step == nil || step.Manual || !dstate.StepComplete() {
+			continue
+		}
+
+		// A last step updating every allocation completes the deployment
+		if dstate.CurrentStep == len(dstate.RolloutSteps)-1 && step.Percent == 100 {
+			continue
+		}
+
+		// The pause starts once the step is complete
+		pause, ok := w.rolloutPauses[name]
+		if !ok || pause.step != dstate.CurrentStep {
+			pause = &rolloutPause{step: dstate.CurrentStep, until: now.Add(step.Pause)}
+			w.rolloutPauses[name] = pause
+		}
+		if now.Before(pause.until) {
+			if next.IsZero() || pause.until.Before(next) {
+				next = pause.until
+			}
+			continue
+		}
+
+		groups = append(groups, name)
+	}
+
+	if len(groups) == 0 {
+		return next, nil
+	}
+
+	sort.Strings(groups)
+	w.logger.Debug("promoting rollout step", "task_groups", groups)
+	_, err = w.upsertDeploymentPromotion(&structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			Groups:       groups,
+			Step:         true,
+		},
+		Eval: w.getEval(),
+	})
+	return next, err
+}
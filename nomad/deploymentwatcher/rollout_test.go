@@ -0,0 +1,105 @@
+package deploymentwatcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	mocker "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentWatcher_AdvanceRolloutSteps(t *testing.T) {
+	ci.Parallel(t)
+	watcher, m := defaultTestDeploymentWatcher(t)
+
+	upd := structs.DefaultUpdateStrategy.Copy()
+	upd.Steps = []*structs.RolloutStep{
+		{Percent: 25, Pause: time.Minute},
+		{Percent: 50, Manual: true},
+		{Percent: 100},
+	}
+	j := mock.Job()
+	j.TaskGroups[0].Update = upd
+	require.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), j))
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups["web"].DesiredTotal = 8
+	d.TaskGroups["web"].HealthyAllocs = 1
+	d.TaskGroups["web"].RolloutSteps = upd.Steps
+	require.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	w := &deploymentWatcher{
+		deploymentID:       d.ID,
+		d:                  d,
+		j:                  j,
+		state:              m.state,
+		deploymentTriggers: watcher,
+		rolloutPauses:      make(map[string]*rolloutPause),
+		logger:             testlog.HCLogger(t),
+		ctx:                ctx,
+	}
+
+	setHealthy := func(healthy int) {
+		t.Helper()
+		out, err := m.state.DeploymentByID(nil, d.ID)
+		require.NoError(t, err)
+		out = out.Copy()
+		out.TaskGroups["web"].HealthyAllocs = healthy
+		require.NoError(t, m.state.UpsertDeployment(m.nextIndex(), out))
+	}
+	currentStep := func() int {
+		t.Helper()
+		out, err := m.state.DeploymentByID(nil, d.ID)
+		require.NoError(t, err)
+		return out.TaskGroups["web"].CurrentStep
+	}
+
+	// Steps aren't promoted before their allocations are healthy
+	now := time.Now()
+	next, err := w.advanceRolloutSteps(now)
+	require.NoError(t, err)
+	require.True(t, next.IsZero())
+
+	// Complete steps are paused
+	setHealthy(2)
+	next, err = w.advanceRolloutSteps(now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), next)
+
+	next, err = w.advanceRolloutSteps(now.Add(30 * time.Second))
+	require.NoError(t, err)
+	require.Equal(t, now.Add(time.Minute), next)
+	require.Zero(t, currentStep())
+
+	// Steps are promoted once the pause ends
+	m.On("UpdateDeploymentPromotion", mocker.MatchedBy(matchDeploymentPromoteRequest(&matchDeploymentPromoteRequestConfig{
+		Promotion: &structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			Groups:       []string{"web"},
+			Step:         true,
+		},
+		Eval: true,
+	}))).Return(nil).Once()
+
+	next, err = w.advanceRolloutSteps(now.Add(time.Minute))
+	require.NoError(t, err)
+	require.True(t, next.IsZero())
+	require.Equal(t, 1, currentStep())
+
+	// Manual steps aren't promoted
+	setHealthy(4)
+	next, err = w.advanceRolloutSteps(now.Add(2 * time.Minute))
+	require.NoError(t, err)
+	require.True(t, next.IsZero())
+	require.Equal(t, 1, currentStep())
+
+	m.AssertExpectations(t)
+}
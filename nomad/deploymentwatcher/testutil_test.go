@@ -1,11 +1,17 @@
 package deploymentwatcher
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
 
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	mocker "github.com/stretchr/testify/mock"
@@ -267,3 +273,39 @@ func matchDeploymentAllocHealthRequest(c *matchDeploymentAllocHealthRequestConfi
 		return true
 	}
 }
+
+func (m *mockBackend) AllocStats(_ context.Context, allocID string) (*cstructs.AllocResourceUsage, error) {
+	args := m.Called(allocID)
+	stats, _ := args.Get(0).(*cstructs.AllocResourceUsage)
+	return stats, args.Error(1)
+}
+
+// testMetricsServer starts a stand-in for a Prometheus compatible HTTP API
+// whose queries return the value given by the function, and returns its
+// address.
+func testMetricsServer(t *testing.T, value func(query string) float64) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+
+		v := value(r.URL.Query().Get("query"))
+		resp := map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"resultType": "vector",
+				"result": []interface{}{
+					map[string]interface{}{
+						"metric": map[string]string{},
+						"value":  []interface{}{1435781451.781, strconv.FormatFloat(v, 'f', -1, 64)},
+					},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
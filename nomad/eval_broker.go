@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/lib/delayheap"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
 const (
@@ -62,7 +63,8 @@ type EvalBroker struct {
 	// blocked tracks the blocked evaluations by JobID in a priority queue
 	blocked map[structs.NamespacedID]PendingEvaluations
 
-	// ready tracks the ready jobs by scheduler in a priority queue
+	// ready tracks the ready jobs by scheduler in a priority queue. Jobs
+	// subject to fair share are held by fairShare instead.
 	ready map[string]PendingEvaluations
 
 	// unack is a map of evalID to an un-acknowledged evaluation
@@ -101,6 +103,10 @@ type EvalBroker struct {
 	// compounding after the first Nack.
 	subsequentNackDelay time.Duration
 
+	// fairShare orders the ready evaluations of each scheduler by the
+	// weighted fair share of their queue. It is nil if disabled.
+	fairShare *fairShare
+
 	l sync.RWMutex
 }
 
@@ -178,6 +184,32 @@ func (b *EvalBroker) SetEnabled(enabled bool) {
 	}
 }
 
+// SetFairShare enables dequeuing evaluations by the weighted fair share of
+// their queue, as returned by queueFn. It must be called before the broker is
+// enabled.
+func (b *EvalBroker) SetFairShare(conf *config.FairShareConfig, queueFn func(*structs.Evaluation) string) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if conf.IsEnabled() {
+		b.fairShare = newFairShare(conf, queueFn)
+	} else {
+		b.fairShare = nil
+	}
+}
+
+// QueueStats returns the share and backlog of the fair-share queues, and
+// whether fair share is enabled.
+func (b *EvalBroker) QueueStats() ([]*structs.EvalQueueStats, bool) {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	if b.fairShare == nil {
+		return nil, false
+	}
+	return b.fairShare.stats(), true
+}
+
 // Enqueue is used to enqueue a new evaluation
 func (b *EvalBroker) Enqueue(eval *structs.Evaluation) {
 	b.l.Lock()
@@ -307,8 +339,12 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, queue string) {
 		}
 	}
 
-	// Push onto the heap
-	heap.Push(&pending, eval)
+	// Push onto the heap, or hold for fair share if enabled
+	if b.fairShare != nil && fairShareApplies(eval, queue) {
+		b.fairShare.enqueue(eval, queue)
+	} else {
+		heap.Push(&pending, eval)
+	}
 	b.ready[queue] = pending
 
 	// Update the stats
@@ -390,6 +426,9 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 
 		// Peek at the next item
 		ready := pending.Peek()
+		if ready == nil && b.fairShare != nil {
+			ready = b.fairShare.peek(sched)
+		}
 		if ready == nil {
 			continue
 		}
@@ -428,11 +467,17 @@ func (b *EvalBroker) scanForSchedulers(schedulers []string) (*structs.Evaluation
 // dequeueForSched is used to dequeue the next work item for a given scheduler.
 // This assumes locks are held and that this scheduler has work
 func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string, error) {
-	// Get the pending queue
-	pending := b.ready[sched]
-	raw := heap.Pop(&pending)
-	b.ready[sched] = pending
-	eval := raw.(*structs.Evaluation)
+	// Dequeue by fair share if enabled, falling back to the pending queue
+	// for evaluations not subject to it
+	var eval *structs.Evaluation
+	if b.fairShare != nil {
+		eval = b.fairShare.dequeue(sched)
+	}
+	if eval == nil {
+		pending := b.ready[sched]
+		eval = heap.Pop(&pending).(*structs.Evaluation)
+		b.ready[sched] = pending
+	}
 
 	// Generate a UUID for the token
 	token := uuid.Generate()
@@ -569,6 +614,9 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	// Cleanup
 	delete(b.unack, evalID)
 	delete(b.evals, evalID)
+	if b.fairShare != nil {
+		b.fairShare.remove(evalID)
+	}
 
 	namespacedID := structs.NamespacedID{
 		ID:        jobID,
@@ -620,6 +668,9 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 
 	// Cleanup
 	delete(b.unack, evalID)
+	if b.fairShare != nil {
+		b.fairShare.remove(evalID)
+	}
 
 	// Update the stats
 	b.stats.TotalUnacked -= 1
@@ -734,6 +785,9 @@ func (b *EvalBroker) flush() {
 	b.unack = make(map[string]*unackEval)
 	b.timeWait = make(map[string]*time.Timer)
 	b.delayHeap = delayheap.NewDelayHeap()
+	if b.fairShare != nil {
+		b.fairShare.flush()
+	}
 }
 
 // evalWrapper satisfies the HeapNode interface
@@ -0,0 +1,267 @@
+package nomad
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// fairShare orders the ready evaluations of the eval broker by weighted fair
+// share between queues, so that a single queue submitting many evaluations
+// can't starve the others. It implements start-time fair queueing: each queue
+// has a virtual time which advances by the inverse of its weight whenever one
+// of its evaluations is dequeued, and the next evaluation is dequeued from the
+// queue with the lowest virtual time. Within a queue evaluations are dequeued
+// by priority and then FIFO.
+//
+// The ready evaluations of each scheduler are held in a heap of queues by
+// virtual time, each holding a heap of its evaluations, so dequeuing is
+// logarithmic in the number of queues and evaluations.
+//
+// fairShare is not safe for concurrent use and is protected by the lock of
+// the eval broker.
+type fairShare struct {
+	config *config.FairShareConfig
+
+	// queueFn returns the queue of an evaluation
+	queueFn func(*structs.Evaluation) string
+
+	// queues tracks the queues by name
+	queues map[string]*fairShareQueue
+
+	// evals is the queue of the ready and unacknowledged evaluations by ID
+	evals map[string]*fairShareQueue
+
+	// ready holds the ready evaluations by scheduler
+	ready map[string]*fairShareReady
+
+	// vtime is the virtual time of the last dequeued evaluation. Queues
+	// gaining a backlog start at this time so they can't claim the share
+	// they didn't use while idle.
+	vtime float64
+}
+
+// fairShareQueue tracks the backlog and virtual time of a queue.
+type fairShareQueue struct {
+	name     string
+	weight   int
+	ready    int
+	unacked  int
+	dequeued uint64
+	vtime    float64
+}
+
+// fairShareReady holds the ready evaluations of a scheduler.
+type fairShareReady struct {
+	// heap orders the queues with ready evaluations by virtual time
+	heap fairShareHeap
+
+	// queues tracks the entries of the heap by queue name
+	queues map[string]*fairShareEntry
+}
+
+// fairShareEntry holds the ready evaluations of a queue for a scheduler.
+type fairShareEntry struct {
+	queue   *fairShareQueue
+	pending PendingEvaluations
+	index   int
+}
+
+func newFairShare(conf *config.FairShareConfig, queueFn func(*structs.Evaluation) string) *fairShare {
+	return &fairShare{
+		config:  conf,
+		queueFn: queueFn,
+		queues:  make(map[string]*fairShareQueue),
+		evals:   make(map[string]*fairShareQueue),
+		ready:   make(map[string]*fairShareReady),
+	}
+}
+
+// fairShareApplies returns whether the evaluation is dequeued by fair share.
+// Core evaluations and those that reached the delivery limit are dequeued by
+// the leader and not subject to fair share.
+func fairShareApplies(eval *structs.Evaluation, sched string) bool {
+	return sched != failedQueue && eval.Type != structs.JobTypeCore
+}
+
+// enqueue holds the ready evaluation for the scheduler.
+func (f *fairShare) enqueue(eval *structs.Evaluation, sched string) {
+	name := f.queueFn(eval)
+	q, ok := f.queues[name]
+	if !ok {
+		q = &fairShareQueue{name: name, weight: f.config.Weight(name)}
+		f.queues[name] = q
+	}
+
+	// Queues gaining a backlog start at the current virtual time
+	if q.ready == 0 && q.vtime < f.vtime {
+		q.vtime = f.vtime
+	}
+	q.ready++
+	f.evals[eval.ID] = q
+
+	r, ok := f.ready[sched]
+	if !ok {
+		r = &fairShareReady{queues: make(map[string]*fairShareEntry)}
+		f.ready[sched] = r
+	}
+	e, ok := r.queues[name]
+	if !ok {
+		e = &fairShareEntry{queue: q}
+		r.queues[name] = e
+		heap.Push(&e.pending, eval)
+		heap.Push(&r.heap, e)
+		return
+	}
+
+	// A new evaluation may become the head of the queue
+	heap.Push(&e.pending, eval)
+	heap.Fix(&r.heap, e.index)
+}
+
+// peek returns the next ready evaluation of the scheduler, or nil if it has
+// none.
+func (f *fairShare) peek(sched string) *structs.Evaluation {
+	r, ok := f.ready[sched]
+	if !ok || len(r.heap) == 0 {
+		return nil
+	}
+	return r.heap[0].pending[0]
+}
+
+// dequeue removes the next ready evaluation of the scheduler, marks it as
+// unacknowledged and advances the virtual time of its queue. It returns nil
+// if the scheduler has no ready evaluations.
+func (f *fairShare) dequeue(sched string) *structs.Evaluation {
+	r, ok := f.ready[sched]
+	if !ok || len(r.heap) == 0 {
+		return nil
+	}
+
+	e := r.heap[0]
+	eval := heap.Pop(&e.pending).(*structs.Evaluation)
+	if len(e.pending) == 0 {
+		heap.Pop(&r.heap)
+		delete(r.queues, e.queue.name)
+	}
+
+	q := e.queue
+	f.vtime = q.vtime
+	q.vtime += 1 / float64(q.weight)
+	q.ready--
+	q.unacked++
+	q.dequeued++
+
+	// The queue may have ready evaluations for every scheduler, whose order
+	// changes with its virtual time
+	for _, r := range f.ready {
+		if e, ok := r.queues[q.name]; ok {
+			heap.Fix(&r.heap, e.index)
+		}
+	}
+	return eval
+}
+
+// remove stops tracking the unacknowledged evaluation.
+func (f *fairShare) remove(evalID string) {
+	q, ok := f.evals[evalID]
+	if !ok {
+		return
+	}
+
+	q.unacked--
+	delete(f.evals, evalID)
+}
+
+// flush clears the state of the queues.
+func (f *fairShare) flush() {
+	f.queues = make(map[string]*fairShareQueue)
+	f.evals = make(map[string]*fairShareQueue)
+	f.ready = make(map[string]*fairShareReady)
+	f.vtime = 0
+}
+
+// stats returns the stats of the queues sorted by name. The share of a
+// queue is its fraction of the weights of the queues with a backlog.
+func (f *fairShare) stats() []*structs.EvalQueueStats {
+	total := 0
+	for _, q := range f.queues {
+		if q.ready+q.unacked > 0 {
+			total += q.weight
+		}
+	}
+
+	stats := make([]*structs.EvalQueueStats, 0, len(f.queues))
+	for _, q := range f.queues {
+		s := &structs.EvalQueueStats{
+			Name:     q.name,
+			Weight:   q.weight,
+			Ready:    q.ready,
+			Unacked:  q.unacked,
+			Dequeued: q.dequeued,
+		}
+		if q.ready+q.unacked > 0 {
+			s.Share = float64(q.weight) / float64(total)
+		}
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// fairShareHeap is a heap of queues ordered by virtual time, and then by
+// the order of their next evaluation.
+type fairShareHeap []*fairShareEntry
+
+func (h fairShareHeap) Len() int {
+	return len(h)
+}
+
+func (h fairShareHeap) Less(i, j int) bool {
+	if vi, vj := h[i].queue.vtime, h[j].queue.vtime; vi != vj {
+		return vi < vj
+	}
+	heads := PendingEvaluations{h[i].pending[0], h[j].pending[0]}
+	return heads.Less(0, 1)
+}
+
+func (h fairShareHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *fairShareHeap) Push(x interface{}) {
+	e := x.(*fairShareEntry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *fairShareHeap) Pop() interface{} {
+	n := len(*h)
+	e := (*h)[n-1]
+	(*h)[n-1] = nil
+	*h = (*h)[:n-1]
+	return e
+}
+
+// evalQueue returns the fair-share queue of the evaluation, which is the
+// value of its job's queue meta key or its namespace.
+func (s *Server) evalQueue(eval *structs.Evaluation) string {
+	key := s.config.FairShareConfig.QueueMetaKey
+	if key == "" {
+		return eval.Namespace
+	}
+
+	job, err := s.fsm.State().JobByID(nil, eval.Namespace, eval.JobID)
+	if err != nil || job == nil {
+		return eval.Namespace
+	}
+	if queue := job.Meta[key]; queue != "" {
+		return queue
+	}
+	return eval.Namespace
+}
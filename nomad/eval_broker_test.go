@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -1506,3 +1508,93 @@ func TestEvalBroker_NamespacedJobs(t *testing.T) {
 	require.Equal(1, len(b.blocked))
 
 }
+
+func TestEvalBroker_FairShare(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetFairShare(&config.FairShareConfig{
+		Enabled: helper.BoolToPtr(true),
+		Weights: map[string]int{"b": 2},
+	}, func(eval *structs.Evaluation) string { return eval.Namespace })
+	b.SetEnabled(true)
+
+	// Enqueue many evals for the first queue before the second
+	for i := 0; i < 30; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "a"
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+	}
+	for i := 0; i < 6; i++ {
+		eval := mock.Eval()
+		eval.Namespace = "b"
+		eval.CreateIndex = uint64(100 + i)
+		b.Enqueue(eval)
+	}
+
+	// The second queue gets twice the share of the first
+	var order []string
+	for i := 0; i < 9; i++ {
+		out, token, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		require.NoError(t, b.Ack(out.ID, token))
+		order = append(order, out.Namespace)
+	}
+	require.Equal(t, []string{"a", "b", "b", "a", "b", "b", "a", "b", "b"}, order)
+
+	// Only the first queue has a backlog left
+	queues, enabled := b.QueueStats()
+	require.True(t, enabled)
+	require.Equal(t, []*structs.EvalQueueStats{
+		{Name: "a", Weight: 1, Share: 1, Ready: 27, Dequeued: 3},
+		{Name: "b", Weight: 2, Share: 0, Dequeued: 6},
+	}, queues)
+
+	// Evals of a queue are dequeued in order
+	out, _, err := b.Dequeue(defaultSched, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), out.CreateIndex)
+}
+
+func TestEvalBroker_FairShare_Schedulers(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetFairShare(&config.FairShareConfig{
+		Enabled: helper.BoolToPtr(true),
+	}, func(eval *structs.Evaluation) string { return eval.Namespace })
+	b.SetEnabled(true)
+
+	// The share of a queue is accounted across schedulers
+	for i, ns := range []string{"a", "a", "b"} {
+		eval := mock.Eval()
+		eval.Namespace = ns
+		eval.CreateIndex = uint64(i)
+		b.Enqueue(eval)
+	}
+	batch := mock.Eval()
+	batch.Namespace = "a"
+	batch.Type = structs.JobTypeBatch
+	b.Enqueue(batch)
+
+	out, _, err := b.Dequeue([]string{structs.JobTypeBatch}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, batch.ID, out.ID)
+
+	var order []string
+	for i := 0; i < 3; i++ {
+		out, _, err := b.Dequeue(defaultSched, time.Second)
+		require.NoError(t, err)
+		require.NotNil(t, out)
+		order = append(order, out.Namespace)
+	}
+	require.Equal(t, []string{"b", "a", "a"}, order)
+
+	// Core evaluations aren't subject to fair share
+	core := mock.Eval()
+	core.Type = structs.JobTypeCore
+	b.Enqueue(core)
+	out, _, err = b.Dequeue([]string{structs.JobTypeCore}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, core.ID, out.ID)
+}
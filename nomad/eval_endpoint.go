@@ -3,6 +3,7 @@ package nomad
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -500,6 +501,60 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 	return e.srv.blockingRPC(&opts)
 }
 
+// Queues is used to return the share and backlog of the fair-share queues of
+// the eval broker.
+func (e *Eval) Queues(args *structs.GenericRequest,
+	reply *structs.EvalQueuesResponse) error {
+
+	// The eval broker is only enabled on the leader
+	args.AllowStale = false
+	if done, err := e.srv.forward("Eval.Queues", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "queues"}, time.Now())
+
+	// This action requires operator read access as queues span namespaces.
+	rule, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	queues, enabled := e.srv.evalBroker.QueueStats()
+	reply.Enabled = enabled
+	if enabled {
+		// Count the evaluations blocked on capacity by queue
+		byName := make(map[string]*structs.EvalQueueStats, len(queues))
+		for _, q := range queues {
+			byName[q.Name] = q
+		}
+		for _, eval := range e.srv.blockedEvals.Evals() {
+			name := e.srv.evalQueue(eval)
+			q, ok := byName[name]
+			if !ok {
+				q = &structs.EvalQueueStats{
+					Name:   name,
+					Weight: e.srv.config.FairShareConfig.Weight(name),
+				}
+				byName[name] = q
+				queues = append(queues, q)
+			}
+			q.Blocked++
+		}
+		sort.Slice(queues, func(i, j int) bool { return queues[i].Name < queues[j].Name })
+	}
+	reply.Queues = queues
+
+	index, err := e.srv.fsm.State().Index("evals")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	e.srv.setQueryMeta(&reply.QueryMeta)
+	return nil
+}
+
 // Allocations is used to list the allocations for an evaluation
 func (e *Eval) Allocations(args *structs.EvalSpecificRequest,
 	reply *structs.EvalAllocationsResponse) error {
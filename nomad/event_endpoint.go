@@ -1,7 +1,9 @@
 package nomad
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"time"
@@ -66,6 +68,11 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 		subscription, subErr = publisher.Subscribe(subReq)
 	}
 	if subErr != nil {
+		var resubErr *stream.ResubscribeError
+		if errors.As(subErr, &resubErr) {
+			handleResubscribeError(resubErr, encoder)
+			return
+		}
 		handleJsonResultError(subErr, helper.Int64ToPtr(500), encoder)
 		return
 	}
@@ -141,6 +148,25 @@ OUTER:
 	}
 
 	if streamErr != nil {
+		var resubErr *stream.ResubscribeError
+		if errors.As(streamErr, &resubErr) {
+			// Flush the events sent before the error, so the subscriber
+			// only misses the events which are no longer available.
+		DRAIN:
+			for {
+				select {
+				case eventJSON := <-jsonStream.OutCh():
+					if err := encoder.Encode(&structs.EventStreamWrapper{Event: eventJSON}); err != nil {
+						return
+					}
+					encoder.Reset(conn)
+				default:
+					break DRAIN
+				}
+			}
+			handleResubscribeError(resubErr, encoder)
+			return
+		}
 		handleJsonResultError(streamErr, helper.Int64ToPtr(500), encoder)
 		return
 	}
@@ -185,3 +211,24 @@ func handleJsonResultError(err error, code *int64, encoder *codec.Encoder) {
 		Error: structs.NewRpcError(err, code),
 	})
 }
+
+// handleResubscribeError sends the entry telling the subscriber that the
+// events it requested are no longer available and it must resubscribe. It is
+// sent as an event stream entry rather than an RPC error, so that it reaches
+// the subscriber after any events already sent.
+func handleResubscribeError(err *stream.ResubscribeError, encoder *codec.Encoder) {
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf, structs.JsonHandleWithExtensions)
+	if encErr := enc.Encode(&structs.EventStreamResubscribe{
+		Resubscribe:    true,
+		Error:          err.Error(),
+		TruncatedIndex: err.TruncatedIndex,
+	}); encErr != nil {
+		handleJsonResultError(encErr, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	encoder.Encode(&structs.EventStreamWrapper{
+		Event: &structs.EventJson{Data: buf.Bytes()},
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -207,6 +208,75 @@ OUTER:
 	}
 }
 
+// TestEventStream_Resubscribe asserts a resubscribe entry is sent when the
+// requested index is no longer available
+func TestEventStream_Resubscribe(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.EnableEventBroker = true
+		c.EventBufferSize = 1
+	})
+	defer cleanupS1()
+
+	testutil.WaitForLeader(t, s1.RPC)
+
+	publisher, err := s1.State().EventBroker()
+	require.NoError(t, err)
+
+	// publish events until the requested index is dropped from the buffer
+	node := mock.Node()
+	for i := uint64(1); i <= 3; i++ {
+		publisher.Publish(&structs.Events{Index: i, Events: []structs.Event{{Topic: "test", Payload: node}}})
+	}
+	testutil.WaitForResult(func() (bool, error) {
+		sub, err := publisher.Subscribe(&stream.SubscribeRequest{
+			Topics: map[structs.Topic][]string{"*": {"*"}},
+			Index:  1,
+		})
+		if err == nil {
+			sub.Unsubscribe()
+			return false, fmt.Errorf("expected index to be dropped")
+		}
+		return errors.Is(err, stream.ErrResubscribeRequired), err
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	req := structs.EventStreamRequest{
+		Topics: map[structs.Topic][]string{"*": {"*"}},
+		Index:  1,
+		QueryOptions: structs.QueryOptions{
+			Region: s1.Region(),
+		},
+	}
+
+	handler, err := s1.StreamingRpcHandler("Event.Stream")
+	require.Nil(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	// the stream must send the resubscribe entry instead of an error
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	var msg structs.EventStreamWrapper
+	require.NoError(t, decoder.Decode(&msg))
+	require.Nil(t, msg.Error)
+	require.NotNil(t, msg.Event)
+
+	var resub structs.EventStreamResubscribe
+	require.NoError(t, json.Unmarshal(msg.Event.Data, &resub))
+	require.True(t, resub.Resubscribe)
+	require.NotZero(t, resub.TruncatedIndex)
+	require.Contains(t, resub.Error, "client should resubscribe")
+}
+
 // TestEventStream_RegionForward tests event streaming from one server
 // to another in a different region
 func TestEventStream_RegionForward(t *testing.T) {
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
@@ -54,8 +55,11 @@ const (
 	ScalingEventsSnapshot                SnapshotType = 19
 	EventSinkSnapshot                    SnapshotType = 20
 	ServiceRegistrationSnapshot          SnapshotType = 21
+	NodeClassMaintenanceSnapshot         SnapshotType = 22
 	// Namespace appliers were moved from enterprise and therefore start at 64
-	NamespaceSnapshot SnapshotType = 64
+	NamespaceSnapshot  SnapshotType = 64
+	QuotaSpecSnapshot  SnapshotType = 65
+	QuotaUsageSnapshot SnapshotType = 66
 )
 
 // LogApplier is the definition of a function that can apply a Raft log
@@ -136,6 +140,11 @@ type FSMConfig struct {
 
 	// EventBufferSize is the amount of messages to hold in memory
 	EventBufferSize int64
+
+	// EventJournal is the optional on-disk journal the state store's event
+	// publisher writes events to. It is shared by every state store the FSM
+	// creates and is owned by the server.
+	EventJournal *stream.EventJournal
 }
 
 // NewFSM is used to construct a new FSM with a blank state.
@@ -146,6 +155,7 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		Region:          config.Region,
 		EnablePublisher: config.EnableEventBroker,
 		EventBufferSize: config.EventBufferSize,
+		EventJournal:    config.EventJournal,
 	}
 	state, err := state.NewStateStore(sconfig)
 	if err != nil {
@@ -279,21 +289,29 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	case structs.ServiceIdentityAccessorDeregisterRequestType:
 		return n.applyDeregisterSIAccessor(buf[1:], log.Index)
 	case structs.CSIVolumeRegisterRequestType:
-		return n.applyCSIVolumeRegister(buf[1:], log.Index)
+		return n.applyCSIVolumeRegister(msgType, buf[1:], log.Index)
 	case structs.CSIVolumeDeregisterRequestType:
-		return n.applyCSIVolumeDeregister(buf[1:], log.Index)
+		return n.applyCSIVolumeDeregister(msgType, buf[1:], log.Index)
 	case structs.CSIVolumeClaimRequestType:
-		return n.applyCSIVolumeClaim(buf[1:], log.Index)
+		return n.applyCSIVolumeClaim(msgType, buf[1:], log.Index)
 	case structs.ScalingEventRegisterRequestType:
 		return n.applyUpsertScalingEvent(buf[1:], log.Index)
 	case structs.CSIVolumeClaimBatchRequestType:
-		return n.applyCSIVolumeBatchClaim(buf[1:], log.Index)
+		return n.applyCSIVolumeBatchClaim(msgType, buf[1:], log.Index)
+	case structs.CSIVolumeUpdateStatsRequestType:
+		return n.applyCSIVolumeUpdateStats(msgType, buf[1:], log.Index)
+	case structs.NodeMaintenanceRequestType:
+		return n.applyNodeMaintenance(msgType, buf[1:], log.Index)
 	case structs.CSIPluginDeleteRequestType:
-		return n.applyCSIPluginDelete(buf[1:], log.Index)
+		return n.applyCSIPluginDelete(msgType, buf[1:], log.Index)
 	case structs.NamespaceUpsertRequestType:
-		return n.applyNamespaceUpsert(buf[1:], log.Index)
+		return n.applyNamespaceUpsert(msgType, buf[1:], log.Index)
 	case structs.NamespaceDeleteRequestType:
-		return n.applyNamespaceDelete(buf[1:], log.Index)
+		return n.applyNamespaceDelete(msgType, buf[1:], log.Index)
+	case structs.QuotaSpecUpsertRequestType:
+		return n.applyQuotaSpecUpsert(msgType, buf[1:], log.Index)
+	case structs.QuotaSpecDeleteRequestType:
+		return n.applyQuotaSpecDelete(msgType, buf[1:], log.Index)
 	// COMPAT(1.0): These messages were added and removed during the 1.0-beta
 	// series and should not be immediately reused for other purposes
 	case structs.EventSinkUpsertRequestType,
@@ -502,6 +520,36 @@ func (n *nomadFSM) applyNodeEligibilityUpdate(msgType structs.MessageType, buf [
 	return nil
 }
 
+func (n *nomadFSM) applyNodeMaintenance(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "node_maintenance"}, time.Now())
+	var req structs.NodeMaintenanceRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateNodeMaintenance(msgType, index, &req); err != nil {
+		n.logger.Error("UpdateNodeMaintenance failed", "error", err)
+		return err
+	}
+
+	// Unblock evals for the nodes whose reserved capacity was released
+	if len(req.DeleteReservations) > 0 {
+		for _, nodeID := range req.NodeIDs {
+			node, err := n.state.NodeByID(nil, nodeID)
+			if err != nil {
+				n.logger.Error("UpdateNodeMaintenance failed to lookup node", "node_id", nodeID, "error", err)
+				return err
+			}
+			if node != nil && node.Ready() {
+				n.blockedEvals.Unblock(node.ComputedClass, index)
+				n.blockedEvals.UnblockNode(nodeID, index)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -1259,14 +1307,14 @@ func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interfac
 	return n.state.SchedulerSetConfig(index, &req.Config)
 }
 
-func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeRegister(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeRegisterRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_register"}, time.Now())
 
-	if err := n.state.UpsertCSIVolume(index, req.Volumes); err != nil {
+	if err := n.state.UpsertCSIVolume(msgType, index, req.Volumes); err != nil {
 		n.logger.Error("CSIVolumeRegister failed", "error", err)
 		return err
 	}
@@ -1274,14 +1322,14 @@ func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{}
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeDeregister(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeDeregisterRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_deregister"}, time.Now())
 
-	if err := n.state.CSIVolumeDeregister(index, req.RequestNamespace(), req.VolumeIDs, req.Force); err != nil {
+	if err := n.state.CSIVolumeDeregister(msgType, index, req.RequestNamespace(), req.VolumeIDs, req.Force); err != nil {
 		n.logger.Error("CSIVolumeDeregister failed", "error", err)
 		return err
 	}
@@ -1289,7 +1337,7 @@ func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeBatchClaim(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var batch *structs.CSIVolumeClaimBatchRequest
 	if err := structs.Decode(buf, &batch); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
@@ -1297,7 +1345,7 @@ func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_batch_claim"}, time.Now())
 
 	for _, req := range batch.Claims {
-		err := n.state.CSIVolumeClaim(index, req.RequestNamespace(),
+		err := n.state.CSIVolumeClaim(msgType, index, req.RequestNamespace(),
 			req.VolumeID, req.ToClaim())
 		if err != nil {
 			n.logger.Error("CSIVolumeClaim for batch failed", "error", err)
@@ -1307,28 +1355,42 @@ func (n *nomadFSM) applyCSIVolumeBatchClaim(buf []byte, index uint64) interface{
 	return nil
 }
 
-func (n *nomadFSM) applyCSIVolumeClaim(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeClaim(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeClaimRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_claim"}, time.Now())
 
-	if err := n.state.CSIVolumeClaim(index, req.RequestNamespace(), req.VolumeID, req.ToClaim()); err != nil {
+	if err := n.state.CSIVolumeClaim(msgType, index, req.RequestNamespace(), req.VolumeID, req.ToClaim()); err != nil {
 		n.logger.Error("CSIVolumeClaim failed", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyCSIVolumeUpdateStats(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	var req structs.CSIVolumeUpdateStatsRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_update_stats"}, time.Now())
+
+	if err := n.state.UpdateCSIVolumeStats(msgType, index, req.NodeID, req.Stats); err != nil {
+		n.logger.Error("UpdateCSIVolumeStats failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyCSIPluginDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	var req structs.CSIPluginDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_plugin_delete"}, time.Now())
 
-	if err := n.state.DeleteCSIPlugin(index, req.ID); err != nil {
+	if err := n.state.DeleteCSIPlugin(msgType, index, req.ID); err != nil {
 		// "plugin in use" is an error for the state store but not for typical
 		// callers, so reduce log noise by not logging that case here
 		if err.Error() != "plugin in use" {
@@ -1340,7 +1402,7 @@ func (n *nomadFSM) applyCSIPluginDelete(buf []byte, index uint64) interface{} {
 }
 
 // applyNamespaceUpsert is used to upsert a set of namespaces
-func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyNamespaceUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_upsert"}, time.Now())
 	var req structs.NamespaceUpsertRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -1362,7 +1424,7 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 		}
 	}
 
-	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
+	if err := n.state.UpsertNamespaces(msgType, index, req.Namespaces); err != nil {
 		n.logger.Error("UpsertNamespaces failed", "error", err)
 		return err
 	}
@@ -1376,20 +1438,58 @@ func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
 }
 
 // applyNamespaceDelete is used to delete a set of namespaces
-func (n *nomadFSM) applyNamespaceDelete(buf []byte, index uint64) interface{} {
+func (n *nomadFSM) applyNamespaceDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_delete"}, time.Now())
 	var req structs.NamespaceDeleteRequest
 	if err := structs.Decode(buf, &req); err != nil {
 		panic(fmt.Errorf("failed to decode request: %v", err))
 	}
 
-	if err := n.state.DeleteNamespaces(index, req.Namespaces); err != nil {
+	if err := n.state.DeleteNamespaces(msgType, index, req.Namespaces); err != nil {
 		n.logger.Error("DeleteNamespaces failed", "error", err)
 	}
 
 	return nil
 }
 
+// applyQuotaSpecUpsert is used to upsert a set of quota specifications
+func (n *nomadFSM) applyQuotaSpecUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_quota_spec_upsert"}, time.Now())
+	var req structs.QuotaSpecUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertQuotaSpecs(msgType, index, req.Quotas); err != nil {
+		n.logger.Error("UpsertQuotaSpecs failed", "error", err)
+		return err
+	}
+
+	// The limits may have been raised, so unblock any evaluations which
+	// were blocked on the quotas
+	for _, quota := range req.Quotas {
+		n.blockedEvals.UnblockQuota(quota.Name, index)
+	}
+
+	return nil
+}
+
+// applyQuotaSpecDelete is used to delete a set of quota specifications
+func (n *nomadFSM) applyQuotaSpecDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_quota_spec_delete"}, time.Now())
+	var req structs.QuotaSpecDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteQuotaSpecs(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteQuotaSpecs failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 	// Create a new snapshot
 	snap, err := n.state.Snapshot()
@@ -1413,6 +1513,7 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 		Region:          n.config.Region,
 		EnablePublisher: n.config.EnableEventBroker,
 		EventBufferSize: n.config.EventBufferSize,
+		EventJournal:    n.config.EventJournal,
 	}
 	newState, err := state.NewStateStore(config)
 	if err != nil {
@@ -1666,6 +1767,24 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case QuotaSpecSnapshot:
+			spec := new(structs.QuotaSpec)
+			if err := dec.Decode(spec); err != nil {
+				return err
+			}
+			if err := restore.QuotaSpecRestore(spec); err != nil {
+				return err
+			}
+
+		case QuotaUsageSnapshot:
+			usage := new(structs.QuotaUsage)
+			if err := dec.Decode(usage); err != nil {
+				return err
+			}
+			if err := restore.QuotaUsageRestore(usage); err != nil {
+				return err
+			}
+
 		// COMPAT(1.0): Allow 1.0-beta clusterers to gracefully handle
 		case EventSinkSnapshot:
 			return nil
@@ -1685,6 +1804,15 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case NodeClassMaintenanceSnapshot:
+			maintenance := new(structs.NodeClassMaintenance)
+			if err := dec.Decode(maintenance); err != nil {
+				return err
+			}
+			if err := restore.NodeClassMaintenanceRestore(maintenance); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -1721,6 +1849,18 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 	// blocking queries won't see any changes and need to be woken up.
 	stateOld.Abandon()
 
+	// Events are not published for the changes held by the snapshot, so
+	// ensure subscribers resuming from the journal don't silently miss them.
+	if n.config.EventJournal != nil {
+		latestIndex, err := newState.LatestIndex()
+		if err != nil {
+			return err
+		}
+		if err := n.config.EventJournal.RestoredFromSnapshot(latestIndex); err != nil {
+			n.logger.Error("failed to update event journal after snapshot restore", "error", err)
+		}
+	}
+
 	return nil
 }
 
@@ -2036,6 +2176,14 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistQuotaSpecs(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistQuotaUsages(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -2052,6 +2200,10 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistNodeClassMaintenances(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	return nil
 }
 
@@ -2429,6 +2581,52 @@ func (s *nomadSnapshot) persistNamespaces(sink raft.SnapshotSink, encoder *codec
 	return nil
 }
 
+// persistQuotaSpecs persists all the quota specifications.
+func (s *nomadSnapshot) persistQuotaSpecs(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	specs, err := s.snap.QuotaSpecs(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := specs.Next()
+		if raw == nil {
+			break
+		}
+		spec := raw.(*structs.QuotaSpec)
+
+		sink.Write([]byte{byte(QuotaSpecSnapshot)})
+		if err := encoder.Encode(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persistQuotaUsages persists all the quota usages.
+func (s *nomadSnapshot) persistQuotaUsages(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	usages, err := s.snap.QuotaUsages(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := usages.Next()
+		if raw == nil {
+			break
+		}
+		usage := raw.(*structs.QuotaUsage)
+
+		sink.Write([]byte{byte(QuotaUsageSnapshot)})
+		if err := encoder.Encode(usage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistSchedulerConfig(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get scheduler config
@@ -2610,6 +2808,30 @@ func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
 	}
 }
 
+// persistNodeClassMaintenances persists the maintenance of all the node
+// classes.
+func (s *nomadSnapshot) persistNodeClassMaintenances(sink raft.SnapshotSink, encoder *codec.Encoder) error {
+	ws := memdb.NewWatchSet()
+	maintenances, err := s.snap.NodeClassMaintenances(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := maintenances.Next()
+		if raw == nil {
+			break
+		}
+		maintenance := raw.(*structs.NodeClassMaintenance)
+
+		sink.Write([]byte{byte(NodeClassMaintenanceSnapshot)})
+		if err := encoder.Encode(maintenance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
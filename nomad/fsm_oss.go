@@ -3,8 +3,23 @@
 
 package nomad
 
-// allocQuota returns the quota object associated with the allocation. In
-// anything but Premium this will always be empty
-func (n *nomadFSM) allocQuota(_ string) (string, error) {
-	return "", nil
+// allocQuota returns the name of the quota referenced by the namespace of the
+// allocation, if any.
+func (n *nomadFSM) allocQuota(allocID string) (string, error) {
+	alloc, err := n.state.AllocByID(nil, allocID)
+	if err != nil {
+		return "", err
+	}
+	if alloc == nil {
+		return "", nil
+	}
+
+	ns, err := n.state.NamespaceByName(nil, alloc.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if ns == nil {
+		return "", nil
+	}
+	return ns.Quota, nil
 }
@@ -3244,7 +3244,7 @@ func TestFSM_DeleteNamespaces(t *testing.T) {
 
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	assert.Nil(fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	assert.Nil(fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	req := structs.NamespaceDeleteRequest{
 		Namespaces: []string{ns1.Name, ns2.Name},
@@ -3271,7 +3271,7 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	state := fsm.State()
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
-	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+	state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2})
 
 	// Verify the contents
 	fsm2 := testSnapshotRestore(t, fsm)
@@ -3287,6 +3287,90 @@ func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_NodeClassMaintenance(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	now := time.Now().UTC()
+	req := &structs.NodeMaintenanceRequest{
+		NodeClass: "maintained",
+		Windows: []*structs.NodeMaintenanceWindow{{
+			ID:     uuid.Generate(),
+			Start:  now.Add(time.Hour),
+			End:    now.Add(2 * time.Hour),
+			Status: structs.NodeMaintenanceWindowPending,
+		}},
+	}
+	require.NoError(t, state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1000, req))
+	maintenance, err := state.NodeClassMaintenanceByClass(nil, req.NodeClass)
+	require.NoError(t, err)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().NodeClassMaintenanceByClass(nil, req.NodeClass)
+	require.NoError(t, err)
+	require.Equal(t, maintenance, out)
+}
+
+func TestFSM_UpsertQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	fsm := testFSM(t)
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	req := structs.QuotaSpecUpsertRequest{
+		Quotas: []*structs.QuotaSpec{qs1, qs2},
+	}
+	buf, err := structs.Encode(structs.QuotaSpecUpsertRequestType, req)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	out, err := fsm.State().QuotaSpecByName(nil, qs1.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	usage, err := fsm.State().QuotaUsageByName(nil, qs2.Name)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+
+	// Verify we can delete them
+	delReq := structs.QuotaSpecDeleteRequest{
+		Names: []string{qs1.Name, qs2.Name},
+	}
+	buf, err = structs.Encode(structs.QuotaSpecDeleteRequestType, delReq)
+	require.NoError(t, err)
+	require.Nil(t, fsm.Apply(makeLog(buf)))
+
+	out, err = fsm.State().QuotaSpecByName(nil, qs1.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestFSM_SnapshotRestore_QuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs1, qs2}))
+	usage1, err := state.QuotaUsageByName(nil, qs1.Name)
+	require.NoError(t, err)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.QuotaSpecByName(nil, qs1.Name)
+	out2, _ := state2.QuotaSpecByName(nil, qs2.Name)
+	require.Equal(t, qs1, out1)
+	require.Equal(t, qs2, out2)
+
+	outUsage1, _ := state2.QuotaUsageByName(nil, qs1.Name)
+	require.Equal(t, usage1, outUsage1)
+}
+
 func TestFSM_UpsertServiceRegistrations(t *testing.T) {
 	ci.Parallel(t)
 	fsm := testFSM(t)
@@ -0,0 +1,150 @@
+package nomad
+
+import (
+	"context"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+const (
+	// jobDependenciesRateLimit is the minimum time between checks of the
+	// dependencies of the registered jobs.
+	jobDependenciesRateLimit = time.Second
+)
+
+// watchJobDependencies creates an evaluation for each job whose dependencies
+// became satisfied. Jobs registered while their dependencies are not
+// satisfied don't get an evaluation, and evaluations of them are completed
+// by the scheduler without placing allocations.
+func (s *Server) watchJobDependencies(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var minIndex uint64 = 1
+	for {
+		raw, index, err := s.State().BlockingQuery(readyDependentJobs, minIndex, ctx)
+		if err == context.Canceled {
+			return
+		} else if err != nil {
+			s.logger.Error("failed to get jobs with satisfied dependencies", "error", err)
+		} else if err := s.createJobDependencyEvals(raw.([]*structs.Job)); err != nil {
+			s.logger.Error("failed to create evals for jobs with satisfied dependencies", "error", err)
+		} else {
+			minIndex = index + 1
+		}
+
+		select {
+		case <-time.After(jobDependenciesRateLimit):
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// readyDependentJobs returns the jobs whose dependencies are satisfied but
+// that haven't been evaluated since.
+func readyDependentJobs(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	iter, err := store.Jobs(ws)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []*structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if len(job.DependsOn) == 0 || job.Stopped() {
+			continue
+		}
+
+		ready, err := jobDependenciesReady(store, job)
+		if err != nil {
+			return nil, 0, err
+		}
+		if ready {
+			jobs = append(jobs, job)
+		}
+	}
+
+	index, err := store.Index("jobs")
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, index, nil
+}
+
+// jobDependenciesReady returns whether the current version of the job has
+// satisfied dependencies but no evaluation that may place it.
+func jobDependenciesReady(store *state.StateStore, job *structs.Job) (bool, error) {
+	pending, err := scheduler.JobDependenciesPending(store, job)
+	if err != nil || pending {
+		return false, err
+	}
+
+	// Jobs with allocations of their current version aren't pending either
+	allocs, err := store.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return false, err
+	}
+	for _, alloc := range allocs {
+		if alloc.Job != nil && alloc.Job.JobModifyIndex == job.JobModifyIndex {
+			return false, nil
+		}
+	}
+
+	evals, err := store.EvalsByJob(nil, job.Namespace, job.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, eval := range evals {
+		if eval.JobModifyIndex != job.JobModifyIndex {
+			continue
+		}
+		if eval.Status == structs.EvalStatusComplete && eval.StatusDescription == scheduler.JobDependenciesPendingDesc {
+			continue
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// createJobDependencyEvals creates an evaluation for each of the jobs.
+func (s *Server) createJobDependencyEvals(jobs []*structs.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	now := time.Now().UnixNano()
+	evals := make([]*structs.Evaluation, len(jobs))
+	for i, job := range jobs {
+		evals[i] = &structs.Evaluation{
+			ID:             uuid.Generate(),
+			Namespace:      job.Namespace,
+			Priority:       job.Priority,
+			Type:           job.Type,
+			TriggeredBy:    structs.EvalTriggerJobDependency,
+			JobID:          job.ID,
+			JobModifyIndex: job.JobModifyIndex,
+			Status:         structs.EvalStatusPending,
+			CreateTime:     now,
+			ModifyTime:     now,
+		}
+	}
+
+	req := structs.EvalUpdateRequest{
+		Evals: evals,
+	}
+	_, _, err := s.raftApply(structs.EvalUpdateRequestType, &req)
+	return err
+}
@@ -50,22 +50,33 @@ type Job struct {
 	srv    *Server
 	logger hclog.Logger
 
-	// builtin admission controllers
+	// builtin and external admission controllers
 	mutators   []jobMutator
 	validators []jobValidator
 }
 
 // NewJobEndpoints creates a new job endpoint with builtin admission controllers
+// and any external admission controllers configured on the server
 func NewJobEndpoints(s *Server) *Job {
+	logger := s.logger.Named("job")
+
+	// External admission controllers run after the job is canonicalized, so
+	// they view a job with its defaults set, and before the remaining
+	// builtin mutators so their patches are processed like any other job
+	mutators := []jobMutator{jobCanonicalizer{}}
+	for _, ac := range s.config.AdmissionControllers {
+		mutators = append(mutators, newJobAdmissionControllerHook(ac, logger))
+	}
+	mutators = append(mutators,
+		jobConnectHook{},
+		jobExposeCheckHook{},
+		jobImpliedConstraints{},
+	)
+
 	return &Job{
-		srv:    s,
-		logger: s.logger.Named("job"),
-		mutators: []jobMutator{
-			jobCanonicalizer{},
-			jobConnectHook{},
-			jobExposeCheckHook{},
-			jobImpliedConstraints{},
-		},
+		srv:      s,
+		logger:   logger,
+		mutators: mutators,
 		validators: []jobValidator{
 			jobConnectHook{},
 			jobExposeCheckHook{},
@@ -73,6 +84,7 @@ func NewJobEndpoints(s *Server) *Job {
 			jobNamespaceConstraintCheckHook{srv: s},
 			jobValidate{},
 			&memoryOversubscriptionValidate{srv: s},
+			&canaryAnalysisValidate{srv: s},
 		},
 	}
 }
@@ -167,6 +179,12 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			}
 		}
 
+		if ok, err := allowJobAffinities(aclObj, j.srv.State(), args.Job); err != nil {
+			return err
+		} else if !ok {
+			return structs.ErrPermissionDenied
+		}
+
 		// Check if override is set and we do not have permissions
 		if args.PolicyOverride {
 			if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySentinelOverride) {
@@ -213,6 +231,11 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Ensure the job doesn't depend on itself through other jobs
+	if err := validateJobDependencies(snap, args.Job); err != nil {
+		return err
+	}
+
 	// Ensure that all scaling policies have an appropriate ID
 	if err := propagateScalingPolicyIDs(existingJob, args.Job); err != nil {
 		return err
@@ -319,8 +342,20 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	// Set the submit time
 	args.Job.SubmitTime = now
 
-	// If the job is periodic or parameterized, we don't create an eval.
-	if !(args.Job.IsPeriodic() || args.Job.IsParameterized()) {
+	// Jobs waiting on their dependencies are evaluated once they are
+	// satisfied.
+	dependenciesPending := false
+	if len(args.Job.DependsOn) > 0 {
+		satisfied, err := scheduler.JobDependenciesSatisfied(snap, args.Job)
+		if err != nil {
+			return err
+		}
+		dependenciesPending = !satisfied
+	}
+
+	// If the job is periodic or parameterized, or waits on its dependencies,
+	// we don't create an eval.
+	if !(args.Job.IsPeriodic() || args.Job.IsParameterized() || dependenciesPending) {
 
 		// Initially set the eval priority to that of the job priority. If the
 		// user supplied an eval priority override, we subsequently use this.
@@ -511,6 +546,54 @@ func (j *Job) Summary(args *structs.JobSummaryRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// Dependencies is used to get the status of the dependencies of a job
+func (j *Job) Dependencies(args *structs.JobSpecificRequest,
+	reply *structs.JobDependenciesResponse) error {
+
+	if done, err := j.srv.forward("Job.Dependencies", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "dependencies"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			job, err := state.JobByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			if job == nil {
+				return structs.NewErrUnknownJob(args.JobID)
+			}
+
+			reply.Dependencies, err = scheduler.JobDependenciesStatus(ws, state, job)
+			if err != nil {
+				return err
+			}
+
+			// Use the last index that affected the jobs table
+			index, err := state.Index("jobs")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // Validate validates a job
 func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValidateResponse) error {
 	defer metrics.MeasureSince([]string{"nomad", "job", "validate"}, time.Now())
@@ -1273,6 +1356,37 @@ func allowedNSes(aclObj *acl.ACL, state *state.StateStore, allow func(ns string)
 	return r, nil
 }
 
+// allowJobAffinities checks that the token can read the jobs of the other
+// namespaces selected by the job's job affinities and anti-affinities, as the
+// job's placements reveal where those jobs' allocations run. Selecting jobs
+// in all namespaces requires reading jobs in every namespace, including ones
+// created later.
+func allowJobAffinities(aclObj *acl.ACL, state *state.StateStore, job *structs.Job) (bool, error) {
+	if aclObj == nil || aclObj.IsManagement() {
+		return true, nil
+	}
+
+	for _, ns := range job.JobAffinityNamespaces() {
+		if !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+			return false, nil
+		}
+		if ns != structs.AllNamespacesSentinel {
+			continue
+		}
+
+		nses, err := state.NamespaceNames()
+		if err != nil {
+			return false, err
+		}
+		for _, ns := range nses {
+			if !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
 // registrationsAreAllowed checks that the scheduler is not in
 // RejectJobRegistration mode for load-shedding.
 func registrationsAreAllowed(aclObj *acl.ACL, state *state.StateStore) (bool, error) {
@@ -1623,6 +1737,11 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
 			return structs.ErrPermissionDenied
 		}
+		if ok, err := allowJobAffinities(aclObj, j.srv.State(), args.Job); err != nil {
+			return err
+		} else if !ok {
+			return structs.ErrPermissionDenied
+		}
 		// Check if override is set and we do not have permissions
 		if args.PolicyOverride {
 			if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySentinelOverride) {
@@ -1801,6 +1920,38 @@ func validateJobUpdate(old, new *structs.Job) error {
 	return nil
 }
 
+// validateJobDependencies ensures the dependencies of a job don't form a
+// cycle with those of the registered jobs.
+func validateJobDependencies(snap *state.StateSnapshot, job *structs.Job) error {
+	visited := make(map[string]struct{})
+	var visit func(deps []*structs.JobDependency, path []string) error
+	visit = func(deps []*structs.JobDependency, path []string) error {
+		for _, d := range deps {
+			next := append(path[:len(path):len(path)], d.JobID)
+			if d.JobID == job.ID {
+				return fmt.Errorf("job dependencies form a cycle: %s", strings.Join(next, " -> "))
+			}
+			if _, ok := visited[d.JobID]; ok {
+				continue
+			}
+			visited[d.JobID] = struct{}{}
+
+			upstream, err := snap.JobByID(nil, job.Namespace, d.JobID)
+			if err != nil {
+				return err
+			}
+			if upstream == nil {
+				continue
+			}
+			if err := visit(upstream.DependsOn, next); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(job.DependsOn, []string{job.ID})
+}
+
 // Dispatch a parameterized job.
 func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
 	if done, err := j.srv.forward("Job.Dispatch", args, args, reply); done {
@@ -0,0 +1,186 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// admissionControllerMaxResponseSize is the maximum size of the response
+	// read from an admission controller.
+	admissionControllerMaxResponseSize = 16 * 1024 * 1024
+)
+
+// admissionControllerRequest is the payload sent to external admission
+// controllers.
+type admissionControllerRequest struct {
+	Job *structs.Job
+}
+
+// admissionControllerResponse is the payload returned by external admission
+// controllers. If Allowed is false the job is rejected with the Reason. If
+// Job is set it replaces the submitted job.
+type admissionControllerResponse struct {
+	Allowed  bool
+	Reason   string
+	Warnings []string
+	Job      *structs.Job
+}
+
+// jobAdmissionControllerHook implements a job Mutating admission controller
+// which sends the job to an operator configured webhook or executable. The
+// admission controller may reject the job or return a patched job.
+type jobAdmissionControllerHook struct {
+	config *config.AdmissionControllerConfig
+	client *http.Client
+	logger hclog.Logger
+}
+
+func newJobAdmissionControllerHook(cfg *config.AdmissionControllerConfig, logger hclog.Logger) *jobAdmissionControllerHook {
+	cfg = cfg.Copy()
+	if cfg.Timeout == 0 {
+		cfg.Timeout = config.DefaultAdmissionControllerTimeout
+	}
+	return &jobAdmissionControllerHook{
+		config: cfg,
+		client: cleanhttp.DefaultPooledClient(),
+		logger: logger.With("admission_controller", cfg.Name),
+	}
+}
+
+func (h *jobAdmissionControllerHook) Name() string {
+	return "admission controller " + h.config.Name
+}
+
+func (h *jobAdmissionControllerHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	resp, err := h.admit(job)
+	if err != nil {
+		if !h.config.FailOpen {
+			return nil, nil, err
+		}
+		h.logger.Warn("admission controller failed, admitting job", "job_id", job.ID, "error", err)
+		return job, []error{fmt.Errorf("%s failed, job admitted without review: %v", h.Name(), err)}, nil
+	}
+
+	if !resp.Allowed {
+		reason := resp.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return nil, nil, fmt.Errorf("job rejected: %s", reason)
+	}
+
+	var warnings []error
+	for _, w := range resp.Warnings {
+		warnings = append(warnings, fmt.Errorf("%s: %s", h.Name(), w))
+	}
+
+	if resp.Job == nil {
+		return job, warnings, nil
+	}
+
+	// Patched jobs may include new fields, so ensure the defaults are set
+	// before the remaining admission controllers run
+	resp.Job.Canonicalize()
+	return resp.Job, warnings, nil
+}
+
+// admit sends the job to the admission controller and returns its decoded
+// response. An error is returned if the admission controller can't be run or
+// returns an invalid response.
+func (h *jobAdmissionControllerHook) admit(job *structs.Job) (*admissionControllerResponse, error) {
+	payload, err := json.Marshal(&admissionControllerRequest{Job: job})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
+	defer cancel()
+
+	var out []byte
+	switch h.config.Type {
+	case config.AdmissionControllerTypeWebhook:
+		out, err = h.webhook(ctx, payload)
+	case config.AdmissionControllerTypeExec:
+		out, err = h.exec(ctx, payload)
+	default:
+		err = fmt.Errorf("unknown admission controller type %q", h.config.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resp admissionControllerResponse
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	// The admission controller may patch the job but not change its identity
+	if patched := resp.Job; patched != nil {
+		if patched.ID != job.ID || patched.Namespace != job.Namespace {
+			return nil, fmt.Errorf("patched job must have the same ID and namespace as the submitted job")
+		}
+	}
+
+	return &resp, nil
+}
+
+// webhook POSTs the payload to the admission controller's address and returns
+// the response body.
+func (h *jobAdmissionControllerHook) webhook(ctx context.Context, payload []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.config.Address, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, admissionControllerMaxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// exec runs the admission controller's command with the payload on stdin and
+// returns its stdout.
+func (h *jobAdmissionControllerHook) exec(ctx context.Context, payload []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.config.Command, h.config.Args...)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("command timed out after %s", h.config.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("command failed: %v: %s", err, msg)
+		}
+		return nil, fmt.Errorf("command failed: %v", err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,206 @@
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdmissionWebhook returns a webhook server which responds to each
+// admission request using the handler.
+func testAdmissionWebhook(t *testing.T, fn func(*structs.Job) *admissionControllerResponse) *httptest.Server {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req admissionControllerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(fn(req.Job)))
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestJobAdmissionControllerHook_Webhook(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := testAdmissionWebhook(t, func(job *structs.Job) *admissionControllerResponse {
+		if job.Meta["team"] == "" {
+			return &admissionControllerResponse{Reason: "jobs must set the team meta"}
+		}
+
+		job.Meta["patched"] = "true"
+		return &admissionControllerResponse{
+			Allowed:  true,
+			Warnings: []string{"image is not pinned"},
+			Job:      job,
+		}
+	})
+
+	hook := newJobAdmissionControllerHook(&config.AdmissionControllerConfig{
+		Name:    "policy",
+		Type:    config.AdmissionControllerTypeWebhook,
+		Address: ts.URL,
+	}, testlog.HCLogger(t))
+
+	// The job is rejected with the reason
+	job := mock.Job()
+	_, _, err := hook.Mutate(job)
+	require.EqualError(t, err, "job rejected: jobs must set the team meta")
+
+	// The job is patched and the warnings are returned
+	job.Meta["team"] = "web"
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(t, err)
+	require.Equal(t, "true", out.Meta["patched"])
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0].Error(), "image is not pinned")
+}
+
+func TestJobAdmissionControllerHook_InvalidPatch(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := testAdmissionWebhook(t, func(job *structs.Job) *admissionControllerResponse {
+		job.ID = "other"
+		return &admissionControllerResponse{Allowed: true, Job: job}
+	})
+
+	hook := newJobAdmissionControllerHook(&config.AdmissionControllerConfig{
+		Name:    "policy",
+		Type:    config.AdmissionControllerTypeWebhook,
+		Address: ts.URL,
+	}, testlog.HCLogger(t))
+
+	_, _, err := hook.Mutate(mock.Job())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "same ID and namespace")
+}
+
+func TestJobAdmissionControllerHook_FailOpen(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	cfg := &config.AdmissionControllerConfig{
+		Name:    "policy",
+		Type:    config.AdmissionControllerTypeWebhook,
+		Address: ts.URL,
+	}
+
+	// Failures reject the job by default
+	job := mock.Job()
+	_, _, err := newJobAdmissionControllerHook(cfg, testlog.HCLogger(t)).Mutate(job)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected response code 500")
+
+	// Failures admit the job with a warning when failing open
+	cfg.FailOpen = true
+	out, warnings, err := newJobAdmissionControllerHook(cfg, testlog.HCLogger(t)).Mutate(job)
+	require.NoError(t, err)
+	require.Equal(t, job, out)
+	require.Len(t, warnings, 1)
+}
+
+func TestJobAdmissionControllerHook_Exec(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("test requires a posix shell")
+	}
+
+	// The script rejects every job
+	script := filepath.Join(t.TempDir(), "admit.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+cat > /dev/null
+echo '{"Allowed": false, "Reason": "registry not allowed"}'
+`), 0755))
+
+	hook := newJobAdmissionControllerHook(&config.AdmissionControllerConfig{
+		Name:    "registry",
+		Type:    config.AdmissionControllerTypeExec,
+		Command: script,
+	}, testlog.HCLogger(t))
+
+	_, _, err := hook.Mutate(mock.Job())
+	require.EqualError(t, err, "job rejected: registry not allowed")
+
+	// Commands exceeding the timeout fail
+	hook = newJobAdmissionControllerHook(&config.AdmissionControllerConfig{
+		Name:    "slow",
+		Type:    config.AdmissionControllerTypeExec,
+		Command: "/bin/sh",
+		Args:    []string{"-c", "sleep 5"},
+		Timeout: 100 * time.Millisecond,
+	}, testlog.HCLogger(t))
+
+	_, _, err = hook.Mutate(mock.Job())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "timed out")
+}
+
+func TestJobEndpoint_Register_AdmissionController(t *testing.T) {
+	ci.Parallel(t)
+
+	ts := testAdmissionWebhook(t, func(job *structs.Job) *admissionControllerResponse {
+		return &admissionControllerResponse{Reason: "registrations are frozen"}
+	})
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionControllers = []*config.AdmissionControllerConfig{{
+			Name:    "freeze",
+			Type:    config.AdmissionControllerTypeWebhook,
+			Address: ts.URL,
+		}}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// The rejection reason is returned for registrations and plans
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "registrations are frozen")
+
+	planReq := &structs.JobPlanRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var planResp structs.JobPlanResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "registrations are frozen")
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
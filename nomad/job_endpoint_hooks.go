@@ -302,3 +302,32 @@ func (v *memoryOversubscriptionValidate) Validate(job *structs.Job) (warnings []
 
 	return warnings, err
 }
+
+// canaryAnalysisValidate rejects jobs analyzing their canaries with metrics
+// queried from Prometheus addresses the servers aren't configured to allow.
+type canaryAnalysisValidate struct {
+	srv *Server
+}
+
+func (*canaryAnalysisValidate) Name() string {
+	return "canary_analysis"
+}
+
+func (v *canaryAnalysisValidate) Validate(job *structs.Job) (warnings []error, err error) {
+	validationErrors := new(multierror.Error)
+	for _, tg := range job.TaskGroups {
+		if tg.Update == nil || tg.Update.Analysis == nil {
+			continue
+		}
+		analysis := tg.Update.Analysis
+		if analysis.Source != structs.AnalysisSourcePrometheus {
+			continue
+		}
+		if !v.srv.config.CanaryAnalysisConfig.AllowsPrometheusAddress(analysis.Address) {
+			multierror.Append(validationErrors, fmt.Errorf(
+				"Task group %q analysis address %q is not one of the prometheus_addresses of the server's canary_analysis configuration",
+				tg.Name, analysis.Address))
+		}
+	}
+	return nil, validationErrors.ErrorOrNil()
+}
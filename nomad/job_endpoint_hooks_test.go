@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
 )
 
@@ -717,3 +719,36 @@ func Test_jobImpliedConstraints_Mutate(t *testing.T) {
 		})
 	}
 }
+
+func Test_canaryAnalysisValidate_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	v := &canaryAnalysisValidate{srv: &Server{config: &Config{
+		CanaryAnalysisConfig: &config.CanaryAnalysisConfig{
+			PrometheusAddresses: []string{"http://prometheus:9090"},
+		},
+	}}}
+
+	job := mock.Job()
+	job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	job.TaskGroups[0].Update.Canary = 1
+	job.TaskGroups[0].Update.Analysis = &structs.AnalysisStrategy{
+		Source:  structs.AnalysisSourcePrometheus,
+		Address: "http://prometheus:9090/",
+	}
+
+	// Allowed addresses are accepted
+	_, err := v.Validate(job)
+	require.NoError(t, err)
+
+	// Other addresses are rejected
+	job.TaskGroups[0].Update.Analysis.Address = "http://169.254.169.254"
+	_, err = v.Validate(job)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "169.254.169.254")
+
+	// The Nomad source doesn't query an address
+	job.TaskGroups[0].Update.Analysis.Source = structs.AnalysisSourceNomad
+	_, err = v.Validate(job)
+	require.NoError(t, err)
+}
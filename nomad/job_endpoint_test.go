@@ -804,6 +804,19 @@ func TestJobEndpoint_Register_ACL(t *testing.T) {
 	pluginPolicy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityCSIRegisterPlugin})
 	pluginToken := mock.CreatePolicyAndToken(t, s1.State(), 1005, "test-csi-register-plugin", submitJobPolicy+pluginPolicy)
 
+	newJobAffinityJob := func(selector string) *structs.Job {
+		j := mock.Job()
+		j.TaskGroups[0].Constraints = append(j.TaskGroups[0].Constraints, &structs.Constraint{
+			LTarget: "${node.unique.id}",
+			RTarget: selector,
+			Operand: structs.ConstraintJobAntiAffinity,
+		})
+		return j
+	}
+
+	readAllJobsPolicy := mock.NamespacePolicy("*", "", []string{acl.NamespaceCapabilityReadJob})
+	readAllJobsToken := mock.CreatePolicyAndToken(t, s1.State(), 1006, "test-read-all-jobs", submitJobPolicy+readAllJobsPolicy)
+
 	cases := []struct {
 		Name        string
 		Job         *structs.Job
@@ -858,6 +871,30 @@ func TestJobEndpoint_Register_ACL(t *testing.T) {
 			Token:       pluginToken.SecretID,
 			ErrExpected: false,
 		},
+		{
+			Name:        "with a token that can submit a job, job affinity in own namespace accepted",
+			Job:         newJobAffinityJob("job=cache"),
+			Token:       submitJobToken.SecretID,
+			ErrExpected: false,
+		},
+		{
+			Name:        "with a token that can submit a job, job affinity in other namespace rejected",
+			Job:         newJobAffinityJob("job=cache,namespace=other"),
+			Token:       submitJobToken.SecretID,
+			ErrExpected: true,
+		},
+		{
+			Name:        "with a token that can submit a job, job affinity in all namespaces rejected",
+			Job:         newJobAffinityJob("job=cache,namespace=*"),
+			Token:       submitJobToken.SecretID,
+			ErrExpected: true,
+		},
+		{
+			Name:        "with a token that can read all jobs, job affinity in all namespaces accepted",
+			Job:         newJobAffinityJob("job=cache,namespace=*"),
+			Token:       readAllJobsToken.SecretID,
+			ErrExpected: false,
+		},
 	}
 
 	for _, tt := range cases {
@@ -1196,6 +1233,65 @@ func TestJobEndpoint_Register_ParameterizedJob(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_Dependencies(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(job *structs.Job) (*structs.JobRegisterResponse, error) {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+		return &resp, err
+	}
+
+	upstream := mock.BatchJob()
+	_, err := register(upstream)
+	require.NoError(err)
+
+	// A job whose dependencies aren't satisfied doesn't get an eval
+	job := mock.BatchJob()
+	job.DependsOn = []*structs.JobDependency{{JobID: upstream.ID}}
+	resp, err := register(job)
+	require.NoError(err)
+	require.NotZero(resp.JobModifyIndex)
+	require.Empty(resp.EvalID)
+
+	// Dependencies can't form a cycle
+	upstream = upstream.Copy()
+	upstream.DependsOn = []*structs.JobDependency{{JobID: job.ID}}
+	_, err = register(upstream)
+	require.Error(err)
+	require.Contains(err.Error(), "job dependencies form a cycle")
+
+	// The status of the dependencies is available
+	get := &structs.JobSpecificRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var deps structs.JobDependenciesResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Dependencies", get, &deps))
+	require.Len(deps.Dependencies, 1)
+	require.Equal(upstream.ID, deps.Dependencies[0].JobID)
+	require.Equal(structs.JobDependencyConditionSuccess, deps.Dependencies[0].Condition)
+	require.False(deps.Dependencies[0].Satisfied)
+}
+
 func TestJobEndpoint_Register_Dispatched(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -2215,7 +2311,7 @@ func TestJobEndpoint_Register_ACL_Namespace(t *testing.T) {
 	// Upsert namespace
 	ns := mock.Namespace()
 	ns.Name = "test"
-	err = s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns})
+	err = s1.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns})
 	assert.Nil(err)
 
 	// Create the register request
@@ -5175,7 +5271,7 @@ func TestJobEndpoint_ListJobs_PaginationFiltering(t *testing.T) {
 	}
 
 	state := s1.fsm.State()
-	require.NoError(t, state.UpsertNamespaces(999, []*structs.Namespace{{Name: "non-default"}, {Name: "other"}}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 999, []*structs.Namespace{{Name: "non-default"}, {Name: "other"}}))
 
 	for i, m := range mocks {
 		if m.name == "" {
@@ -359,6 +359,12 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job status metrics
 	go s.publishJobStatusMetrics(stopCh)
 
+	// Evaluate jobs once their dependencies are satisfied
+	go s.watchJobDependencies(stopCh)
+
+	// Apply the maintenance windows and capacity reservations of nodes
+	go s.watchNodeMaintenance(stopCh)
+
 	// Setup the heartbeat timers. This is done both when starting up or when
 	// a leader fail over happens. Since the timers are maintained by the leader
 	// node, effectively this means all the timers are renewed at the time of failover.
@@ -379,6 +385,7 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 		go s.replicateACLPolicies(stopCh)
 		go s.replicateACLTokens(stopCh)
 		go s.replicateNamespaces(stopCh)
+		go s.replicateQuotaSpecs(stopCh)
 	}
 
 	// Setup any enterprise systems required.
@@ -493,6 +500,100 @@ ERR_WAIT:
 	}
 }
 
+// replicateQuotaSpecs is used to replicate quota specifications from the
+// authoritative region to this region.
+func (s *Server) replicateQuotaSpecs(stopCh chan struct{}) {
+	req := structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Debug("starting quota specification replication from authoritative region", "region", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+		}
+
+		// Rate limit how often we attempt replication
+		limiter.Wait(context.Background())
+
+		// Fetch the list of quota specifications
+		var resp structs.QuotaSpecListResponse
+		req.AuthToken = s.ReplicationToken()
+		err := s.forwardRegion(s.config.AuthoritativeRegion, "Quota.ListQuotaSpecs", &req, &resp)
+		if err != nil {
+			s.logger.Error("failed to fetch quota specifications from authoritative region", "error", err)
+			goto ERR_WAIT
+		}
+
+		// Perform a two-way diff
+		delete, update := diffQuotaSpecs(s.State(), req.MinQueryIndex, resp.Quotas)
+
+		// Delete quota specifications that should not exist
+		if len(delete) > 0 {
+			args := &structs.QuotaSpecDeleteRequest{
+				Names: delete,
+			}
+			_, _, err := s.raftApply(structs.QuotaSpecDeleteRequestType, args)
+			if err != nil {
+				s.logger.Error("failed to delete quota specifications", "error", err)
+				goto ERR_WAIT
+			}
+		}
+
+		// Fetch any outdated quota specifications
+		var fetched []*structs.QuotaSpec
+		if len(update) > 0 {
+			req := structs.QuotaSpecSetRequest{
+				Names: update,
+				QueryOptions: structs.QueryOptions{
+					Region:        s.config.AuthoritativeRegion,
+					AuthToken:     s.ReplicationToken(),
+					AllowStale:    true,
+					MinQueryIndex: resp.Index - 1,
+				},
+			}
+			var reply structs.QuotaSpecSetResponse
+			if err := s.forwardRegion(s.config.AuthoritativeRegion, "Quota.GetQuotaSpecs", &req, &reply); err != nil {
+				s.logger.Error("failed to fetch quota specifications from authoritative region", "error", err)
+				goto ERR_WAIT
+			}
+			for _, spec := range reply.Quotas {
+				fetched = append(fetched, spec)
+			}
+		}
+
+		// Update local quota specifications
+		if len(fetched) > 0 {
+			args := &structs.QuotaSpecUpsertRequest{
+				Quotas: fetched,
+			}
+			_, _, err := s.raftApply(structs.QuotaSpecUpsertRequestType, args)
+			if err != nil {
+				s.logger.Error("failed to update quota specifications", "error", err)
+				goto ERR_WAIT
+			}
+		}
+
+		// Update the minimum query index, blocks until there is a change.
+		req.MinQueryIndex = resp.Index
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
 func (s *Server) handlePausableWorkers(isLeader bool) {
 	for _, w := range s.pausableWorkers() {
 		if isLeader {
@@ -549,6 +650,52 @@ func diffNamespaces(state *state.StateStore, minIndex uint64, remoteList []*stru
 	return
 }
 
+// diffQuotaSpecs is used to perform a two-way diff between the local quota
+// specifications and the remote quota specifications to determine which need
+// to be deleted or updated.
+func diffQuotaSpecs(state *state.StateStore, minIndex uint64, remoteList []*structs.QuotaSpec) (delete []string, update []string) {
+	// Construct a set of the local and remote quota specifications
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local quota specifications
+	iter, err := state.QuotaSpecs(nil)
+	if err != nil {
+		panic("failed to iterate local quota specifications")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		spec := raw.(*structs.QuotaSpec)
+		local[spec.Name] = spec.Hash
+	}
+
+	// Iterate over the remote quota specifications
+	for _, rspec := range remoteList {
+		remote[rspec.Name] = struct{}{}
+
+		// Check if the quota specification is missing locally
+		if localHash, ok := local[rspec.Name]; !ok {
+			update = append(update, rspec.Name)
+
+			// Check if the quota specification is newer remotely and there is
+			// a hash mis-match.
+		} else if rspec.ModifyIndex > minIndex && !bytes.Equal(localHash, rspec.Hash) {
+			update = append(update, rspec.Name)
+		}
+	}
+
+	// Check if quota specifications should be deleted
+	for lspec := range local {
+		if _, ok := remote[lspec]; !ok {
+			delete = append(delete, lspec)
+		}
+	}
+	return
+}
+
 // restoreEvals is used to restore pending evaluations into the eval broker and
 // blocked evaluations into the blocked eval tracker. The broker and blocked
 // eval tracker is maintained only by the leader, so it must be restored anytime
@@ -762,6 +909,9 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	oneTimeTokenGC := time.NewTicker(s.config.OneTimeTokenGCInterval)
 	defer oneTimeTokenGC.Stop()
 
+	rebalance := time.NewTicker(s.config.RebalancerConfig.Interval)
+	defer rebalance.Stop()
+
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
 	getLatest := func() (uint64, bool) {
@@ -809,6 +959,21 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobOneTimeTokenGC, index))
 			}
+		case <-rebalance.C:
+			// The rebalancer is enabled in the scheduler configuration, which
+			// may change at any time
+			_, schedConfig, err := s.fsm.State().SchedulerConfig()
+			if err != nil {
+				s.logger.Error("failed to get scheduler config", "error", err)
+				continue
+			}
+			if schedConfig == nil || !schedConfig.RebalancerConfig.Enabled {
+				continue
+			}
+
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobRebalance, index))
+			}
 		case <-stopCh:
 			return
 		}
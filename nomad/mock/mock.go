@@ -2266,6 +2266,27 @@ func Namespace() *structs.Namespace {
 	return ns
 }
 
+func QuotaSpec() *structs.QuotaSpec {
+	qs := &structs.QuotaSpec{
+		Name:        fmt.Sprintf("quota-%s", uuid.Generate()),
+		Description: "test quota",
+		Limits: []*structs.QuotaLimit{
+			{
+				Region: "global",
+				RegionLimit: &structs.Resources{
+					CPU:      2000,
+					MemoryMB: 2000,
+				},
+			},
+		},
+	}
+	for _, limit := range qs.Limits {
+		limit.SetHash()
+	}
+	qs.SetHash()
+	return qs
+}
+
 // ServiceRegistrations generates an array containing two unique service
 // registrations.
 func ServiceRegistrations() []*structs.ServiceRegistration {
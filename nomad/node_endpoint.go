@@ -37,6 +37,11 @@ const (
 	NodeDrainEventDrainDisabled = "Node drain disabled"
 	NodeDrainEventDrainUpdated  = "Node drain strategy updated"
 
+	// NodeMaintenanceEvents are the various maintenance messages
+	NodeMaintenanceEventUpdated       = "Node maintenance updated"
+	NodeMaintenanceEventWindowStarted = "Node maintenance window started"
+	NodeMaintenanceEventWindowEnded   = "Node maintenance window ended"
+
 	// NodeEligibilityEventEligible is used when the nodes eligiblity is marked
 	// eligible
 	NodeEligibilityEventEligible = "Node marked as eligible for scheduling"
@@ -768,6 +773,115 @@ func (n *Node) UpdateEligibility(args *structs.NodeUpdateEligibilityRequest,
 	return nil
 }
 
+// UpdateMaintenance is used to update the maintenance windows and capacity
+// reservations of a node or of all the nodes of a node class
+func (n *Node) UpdateMaintenance(args *structs.NodeMaintenanceRequest,
+	reply *structs.NodeMaintenanceResponse) error {
+	if done, err := n.srv.forward("Node.UpdateMaintenance", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_maintenance"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Verify the arguments
+	if (args.NodeID == "") == (args.NodeClass == "") {
+		return fmt.Errorf("exactly one of node ID or node class must be set for updating maintenance")
+	}
+	if args.NodeEvent != nil {
+		return fmt.Errorf("node event must not be set")
+	}
+
+	var mErr multierror.Error
+	for i, w := range args.Windows {
+		if w.ID == "" {
+			w.ID = uuid.Generate()
+		}
+		if w.Deadline == 0 {
+			w.Deadline = structs.DefaultNodeMaintenanceDeadline
+		}
+		w.Status = structs.NodeMaintenanceWindowPending
+		w.DrainStarted = false
+		w.MarkEligible = false
+		if err := w.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("maintenance window %d: %v", i+1, err))
+		}
+	}
+	for i, r := range args.Reservations {
+		if r.ID == "" {
+			r.ID = uuid.Generate()
+		}
+		if r.Namespace == "" {
+			r.Namespace = args.RequestNamespace()
+		}
+		if err := r.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("capacity reservation %d: %v", i+1, err))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	// Resolve the nodes to update
+	snap, err := n.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	if args.NodeID != "" {
+		node, err := snap.NodeByID(nil, args.NodeID)
+		if err != nil {
+			return err
+		}
+		if node == nil {
+			return fmt.Errorf("node not found")
+		}
+		args.NodeIDs = []string{node.ID}
+	} else {
+		// The update is also stored for the class, so that nodes joining it
+		// later get it too. The class may have no nodes yet.
+		iter, err := snap.Nodes(nil)
+		if err != nil {
+			return err
+		}
+		args.NodeIDs = nil
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if node := raw.(*structs.Node); node.NodeClass == args.NodeClass {
+				args.NodeIDs = append(args.NodeIDs, node.ID)
+			}
+		}
+	}
+
+	// Update the timestamp of when the node status was updated
+	args.UpdatedAt = time.Now().Unix()
+	args.NodeEvent = structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemMaintenance).
+		SetMessage(NodeMaintenanceEventUpdated)
+
+	// Commit this update via Raft
+	outErr, index, err := n.srv.raftApply(structs.NodeMaintenanceRequestType, args)
+	if err != nil {
+		n.logger.Error("maintenance update failed", "error", err)
+		return err
+	}
+	if outErr != nil {
+		if err, ok := outErr.(error); ok && err != nil {
+			n.logger.Error("maintenance update failed", "error", err)
+			return err
+		}
+	}
+
+	// Set the reply index
+	reply.NodeIDs = args.NodeIDs
+	reply.NodeModifyIndex = index
+	reply.Index = index
+	return nil
+}
+
 // Evaluate is used to force a re-evaluation of the node
 func (n *Node) Evaluate(args *structs.NodeEvaluateRequest, reply *structs.NodeUpdateResponse) error {
 	if done, err := n.srv.forward("Node.Evaluate", args, args, reply); done {
@@ -1422,6 +1422,96 @@ func TestClientEndpoint_Drain_Down(t *testing.T) {
 	})
 }
 
+func TestClientEndpoint_UpdateMaintenance(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register two nodes of the same class
+	node1, node2 := mock.Node(), mock.Node()
+	for _, node := range []*structs.Node{node1, node2} {
+		node.NodeClass = "maintained"
+		require.NoError(s1.State().UpsertNode(structs.MsgTypeTestSetup, 10, node))
+	}
+
+	// Schedule a window on the node class
+	now := time.Now()
+	req := &structs.NodeMaintenanceRequest{
+		NodeClass: "maintained",
+		Windows: []*structs.NodeMaintenanceWindow{
+			{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.NodeMaintenanceResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateMaintenance", req, &resp))
+	require.NotZero(resp.Index)
+	require.ElementsMatch([]string{node1.ID, node2.ID}, resp.NodeIDs)
+
+	state := s1.fsm.State()
+	for _, nodeID := range resp.NodeIDs {
+		out, err := state.NodeByID(nil, nodeID)
+		require.NoError(err)
+		require.Len(out.MaintenanceWindows, 1)
+		require.NotEmpty(out.MaintenanceWindows[0].ID)
+		require.Equal(structs.NodeMaintenanceWindowPending, out.MaintenanceWindows[0].Status)
+		require.Equal(structs.DefaultNodeMaintenanceDeadline, out.MaintenanceWindows[0].Deadline)
+		require.Equal(NodeMaintenanceEventUpdated, out.Events[len(out.Events)-1].Message)
+	}
+
+	// Reserve capacity on a single node
+	req = &structs.NodeMaintenanceRequest{
+		NodeID: node1.ID,
+		Reservations: []*structs.NodeCapacityReservation{
+			{JobID: "example", MemoryMB: 512, Expires: now.Add(time.Hour)},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp2 structs.NodeMaintenanceResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateMaintenance", req, &resp2))
+	require.Equal([]string{node1.ID}, resp2.NodeIDs)
+
+	out, err := state.NodeByID(nil, node1.ID)
+	require.NoError(err)
+	require.Len(out.MaintenanceWindows, 1)
+	require.Len(out.CapacityReservations, 1)
+	require.Equal(structs.DefaultNamespace, out.CapacityReservations[0].Namespace)
+
+	// Invalid requests are rejected
+	req.Reservations[0].MemoryMB = 0
+	err = msgpackrpc.CallWithCodec(codec, "Node.UpdateMaintenance", req, &resp2)
+	require.Error(err)
+	require.Contains(err.Error(), "must reserve cpu or memory")
+
+	// Nodes joining the class later get its window
+	node3 := mock.Node()
+	node3.NodeClass = "maintained"
+	require.NoError(s1.State().UpsertNode(structs.MsgTypeTestSetup, resp2.Index+1, node3))
+	out, err = state.NodeByID(nil, node3.ID)
+	require.NoError(err)
+	require.Len(out.MaintenanceWindows, 1)
+	require.Nil(out.CapacityReservations)
+
+	// Classes without nodes are updated for the nodes that join them
+	req = &structs.NodeMaintenanceRequest{
+		NodeClass: "empty",
+		Windows: []*structs.NodeMaintenanceWindow{
+			{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+		},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp3 structs.NodeMaintenanceResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.UpdateMaintenance", req, &resp3))
+	require.Empty(resp3.NodeIDs)
+	maintenance, err := state.NodeClassMaintenanceByClass(nil, "empty")
+	require.NoError(err)
+	require.Len(maintenance.MaintenanceWindows, 1)
+}
+
 func TestClientEndpoint_UpdateEligibility(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -1934,7 +2024,7 @@ func TestClientEndpoint_GetAllocs_ACL_Namespaces(t *testing.T) {
 	allocAltNS.NodeID = node.ID
 	allocOtherNS.NodeID = node.ID
 	state := s1.fsm.State()
-	assert.Nil(state.UpsertNamespaces(1, []*structs.Namespace{ns1, ns2}), "UpsertNamespaces")
+	assert.Nil(state.UpsertNamespaces(structs.MsgTypeTestSetup, 1, []*structs.Namespace{ns1, ns2}), "UpsertNamespaces")
 	assert.Nil(state.UpsertNode(structs.MsgTypeTestSetup, 2, node), "UpsertNode")
 	assert.Nil(state.UpsertJobSummary(3, mock.JobSummary(allocDefaultNS.JobID)), "UpsertJobSummary")
 	assert.Nil(state.UpsertJobSummary(4, mock.JobSummary(allocAltNS.JobID)), "UpsertJobSummary")
@@ -2767,7 +2857,7 @@ func TestClientEndpoint_CreateNodeEvals_MultipleNSes(t *testing.T) {
 
 	idx := uint64(3)
 	ns1 := mock.Namespace()
-	err := state.UpsertNamespaces(idx, []*structs.Namespace{ns1})
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, idx, []*structs.Namespace{ns1})
 	require.NoError(t, err)
 	idx++
 
@@ -0,0 +1,162 @@
+package nomad
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// nodeMaintenanceInterval is the interval at which the maintenance
+	// windows and capacity reservations of the nodes are checked.
+	nodeMaintenanceInterval = 10 * time.Second
+)
+
+// watchNodeMaintenance drains nodes at the start of their maintenance
+// windows, marks the nodes it drained eligible at the end and releases
+// expired capacity reservations.
+func (s *Server) watchNodeMaintenance(stopCh chan struct{}) {
+	ticker := time.NewTicker(nodeMaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			if err := s.updateNodeMaintenance(time.Now()); err != nil {
+				s.logger.Error("failed to update node maintenance", "error", err)
+			}
+		}
+	}
+}
+
+// updateNodeMaintenance applies the transitions of the maintenance windows
+// and capacity reservations of the nodes due at the given time, and removes
+// those that ended from the node classes.
+func (s *Server) updateNodeMaintenance(now time.Time) error {
+	iter, err := s.fsm.State().Nodes(nil)
+	if err != nil {
+		return err
+	}
+
+	drains := &structs.BatchNodeUpdateDrainRequest{
+		Updates:      make(map[string]*structs.DrainUpdate),
+		NodeEvents:   make(map[string]*structs.NodeEvent),
+		WriteRequest: structs.WriteRequest{Region: s.config.Region},
+		UpdatedAt:    now.Unix(),
+	}
+	var updates []*structs.NodeMaintenanceRequest
+	var eligible []string
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		update := &structs.NodeMaintenanceRequest{
+			NodeIDs:      []string{node.ID},
+			WriteRequest: structs.WriteRequest{Region: s.config.Region},
+			UpdatedAt:    now.Unix(),
+		}
+
+		for _, w := range node.MaintenanceWindows {
+			switch {
+			case w.Ended(now):
+				// Windows that ended before they could start are dropped
+				update.DeleteWindows = append(update.DeleteWindows, w.ID)
+				if w.Status != structs.NodeMaintenanceWindowActive || !w.DrainStarted {
+					continue
+				}
+
+				// Cancel any remaining drain started by the window and
+				// restore the eligibility the node had
+				drains.Updates[node.ID] = &structs.DrainUpdate{MarkEligible: w.MarkEligible}
+				drains.NodeEvents[node.ID] = structs.NewNodeEvent().
+					SetSubsystem(structs.NodeEventSubsystemMaintenance).
+					SetMessage(NodeMaintenanceEventWindowEnded).
+					AddDetail("window_id", w.ID)
+				if w.MarkEligible {
+					eligible = append(eligible, node.ID)
+				}
+
+			case w.Started(now) && w.Status == structs.NodeMaintenanceWindowPending:
+				started := w.Copy()
+				started.Status = structs.NodeMaintenanceWindowActive
+
+				// Don't override a drain that is already in progress
+				if node.DrainStrategy == nil {
+					started.DrainStarted = true
+					started.MarkEligible = node.SchedulingEligibility == structs.NodeSchedulingEligible
+					drains.Updates[node.ID] = &structs.DrainUpdate{DrainStrategy: w.DrainStrategy(now)}
+					drains.NodeEvents[node.ID] = structs.NewNodeEvent().
+						SetSubsystem(structs.NodeEventSubsystemMaintenance).
+						SetMessage(NodeMaintenanceEventWindowStarted).
+						AddDetail("window_id", w.ID)
+				}
+				update.Windows = append(update.Windows, started)
+			}
+		}
+
+		for _, r := range node.CapacityReservations {
+			if r.Expired(now) {
+				update.DeleteReservations = append(update.DeleteReservations, r.ID)
+			}
+		}
+
+		if len(update.Windows)+len(update.DeleteWindows)+len(update.DeleteReservations) > 0 {
+			updates = append(updates, update)
+		}
+	}
+
+	// The nodes of a class are updated above, so only the class itself is
+	// updated here
+	classIter, err := s.fsm.State().NodeClassMaintenances(nil)
+	if err != nil {
+		return err
+	}
+	for raw := classIter.Next(); raw != nil; raw = classIter.Next() {
+		maintenance := raw.(*structs.NodeClassMaintenance)
+		update := &structs.NodeMaintenanceRequest{
+			NodeClass:    maintenance.NodeClass,
+			WriteRequest: structs.WriteRequest{Region: s.config.Region},
+			UpdatedAt:    now.Unix(),
+		}
+		for _, w := range maintenance.MaintenanceWindows {
+			if w.Ended(now) {
+				update.DeleteWindows = append(update.DeleteWindows, w.ID)
+			}
+		}
+		for _, r := range maintenance.CapacityReservations {
+			if r.Expired(now) {
+				update.DeleteReservations = append(update.DeleteReservations, r.ID)
+			}
+		}
+		if len(update.DeleteWindows)+len(update.DeleteReservations) > 0 {
+			updates = append(updates, update)
+		}
+	}
+
+	// Apply the drains before marking the windows as active, so that a
+	// failed drain is retried
+	shim := drainerShim{s: s}
+	if len(drains.Updates) > 0 {
+		resp, index, err := s.raftApply(structs.BatchNodeUpdateDrainRequestType, drains)
+		if _, err := shim.convertApplyErrors(resp, index, err); err != nil {
+			return err
+		}
+
+		// Nodes marked as eligible may need to run system jobs
+		for _, nodeID := range eligible {
+			if _, _, err := s.staticEndpoints.Node.createNodeEvals(nodeID, index); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, update := range updates {
+		resp, index, err := s.raftApply(structs.NodeMaintenanceRequestType, update)
+		if _, err := shim.convertApplyErrors(resp, index, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
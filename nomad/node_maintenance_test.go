@@ -0,0 +1,153 @@
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_UpdateNodeMaintenance(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	now := time.Now()
+	window := &structs.NodeMaintenanceWindow{
+		ID:       "window",
+		Start:    now.Add(time.Minute),
+		End:      now.Add(time.Hour),
+		Deadline: 10 * time.Minute,
+		Status:   structs.NodeMaintenanceWindowPending,
+	}
+	reservation := &structs.NodeCapacityReservation{
+		ID:        "reservation",
+		Namespace: structs.DefaultNamespace,
+		JobID:     "example",
+		CPU:       500,
+		Expires:   now.Add(time.Minute),
+	}
+
+	node := mock.Node()
+	node.NodeClass = "maintained"
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 10, node))
+	require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 11, &structs.NodeMaintenanceRequest{
+		NodeClass:    node.NodeClass,
+		NodeIDs:      []string{node.ID},
+		Windows:      []*structs.NodeMaintenanceWindow{window},
+		Reservations: []*structs.NodeCapacityReservation{reservation},
+	}))
+
+	// Nothing is due yet
+	require.NoError(s1.updateNodeMaintenance(now))
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Nil(out.DrainStrategy)
+	require.Len(out.CapacityReservations, 1)
+
+	// The start of the window drains the node and the reservation expires
+	require.NoError(s1.updateNodeMaintenance(now.Add(time.Minute)))
+	out, err = state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.NotNil(out.DrainStrategy)
+	require.Equal(10*time.Minute, out.DrainStrategy.Deadline)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.Len(out.MaintenanceWindows, 1)
+	require.Equal(structs.NodeMaintenanceWindowActive, out.MaintenanceWindows[0].Status)
+	require.Nil(out.CapacityReservations)
+
+	// The node class keeps the window for nodes joining it
+	maintenance, err := state.NodeClassMaintenanceByClass(nil, node.NodeClass)
+	require.NoError(err)
+	require.Len(maintenance.MaintenanceWindows, 1)
+	require.Nil(maintenance.CapacityReservations)
+
+	// The end of the window cancels the drain and restores the eligibility
+	require.NoError(s1.updateNodeMaintenance(now.Add(time.Hour)))
+	out, err = state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Nil(out.DrainStrategy)
+	require.Equal(structs.NodeSchedulingEligible, out.SchedulingEligibility)
+	require.Nil(out.MaintenanceWindows)
+	require.Equal(NodeMaintenanceEventWindowEnded, out.Events[len(out.Events)-1].Message)
+
+	maintenance, err = state.NodeClassMaintenanceByClass(nil, node.NodeClass)
+	require.NoError(err)
+	require.Nil(maintenance)
+}
+
+func TestServer_UpdateNodeMaintenance_Eligibility(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent the drained allocation from stopping
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	now := time.Now()
+	newWindow := func() *structs.NodeMaintenanceWindow {
+		return &structs.NodeMaintenanceWindow{
+			ID:       "window",
+			Start:    now.Add(time.Minute),
+			End:      now.Add(time.Hour),
+			Deadline: 10 * time.Minute,
+			Status:   structs.NodeMaintenanceWindowPending,
+		}
+	}
+
+	// An ineligible node and a node already draining, with an allocation so
+	// its drain doesn't complete
+	ineligible := mock.Node()
+	ineligible.SchedulingEligibility = structs.NodeSchedulingIneligible
+	draining := mock.DrainNode()
+	alloc := mock.Alloc()
+	alloc.NodeID = draining.ID
+	require.NoError(state.UpsertJob(structs.MsgTypeTestSetup, 10, alloc.Job))
+	require.NoError(state.UpsertAllocs(structs.MsgTypeTestSetup, 11, []*structs.Allocation{alloc}))
+	for i, node := range []*structs.Node{ineligible, draining} {
+		require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, uint64(20+i), node))
+		require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, uint64(30+i), &structs.NodeMaintenanceRequest{
+			NodeIDs: []string{node.ID},
+			Windows: []*structs.NodeMaintenanceWindow{newWindow()},
+		}))
+	}
+
+	// The window only drains the node that isn't draining yet
+	require.NoError(s1.updateNodeMaintenance(now.Add(time.Minute)))
+	out, err := state.NodeByID(nil, ineligible.ID)
+	require.NoError(err)
+	require.NotNil(out.DrainStrategy)
+	require.True(out.MaintenanceWindows[0].DrainStarted)
+	require.False(out.MaintenanceWindows[0].MarkEligible)
+
+	out, err = state.NodeByID(nil, draining.ID)
+	require.NoError(err)
+	require.Equal(draining.DrainStrategy.Deadline, out.DrainStrategy.Deadline)
+	require.False(out.MaintenanceWindows[0].DrainStarted)
+
+	// The end of the window cancels its drain but doesn't mark either node
+	// eligible
+	require.NoError(s1.updateNodeMaintenance(now.Add(time.Hour)))
+	out, err = state.NodeByID(nil, ineligible.ID)
+	require.NoError(err)
+	require.Nil(out.DrainStrategy)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.Nil(out.MaintenanceWindows)
+
+	out, err = state.NodeByID(nil, draining.ID)
+	require.NoError(err)
+	require.NotNil(out.DrainStrategy)
+	require.Equal(structs.NodeSchedulingIneligible, out.SchedulingEligibility)
+	require.Nil(out.MaintenanceWindows)
+}
@@ -9,7 +9,7 @@ import (
 )
 
 // refreshIndex returns the index the scheduler should refresh to as the maximum
-// of both the allocation and node tables.
+// of the allocation, node and quota tables.
 func refreshIndex(snap *state.StateSnapshot) (uint64, error) {
 	allocIndex, err := snap.Index("allocs")
 	if err != nil {
@@ -19,10 +19,65 @@ func refreshIndex(snap *state.StateSnapshot) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
-	return maxUint64(nodeIndex, allocIndex), nil
+	specIndex, err := snap.Index(state.TableQuotaSpecs)
+	if err != nil {
+		return 0, err
+	}
+	usageIndex, err := snap.Index(state.TableQuotaUsages)
+	if err != nil {
+		return 0, err
+	}
+	return maxUint64(nodeIndex, allocIndex, specIndex, usageIndex), nil
 }
 
-// evaluatePlanQuota returns whether the plan would be over quota
-func evaluatePlanQuota(_ *state.StateSnapshot, _ *structs.Plan) (bool, error) {
-	return false, nil
+// evaluatePlanQuota returns whether the plan would be over quota. A plan is
+// only rejected if it increases the usage of a quota in a dimension which
+// would then be over its limit, so plans which reduce usage are still applied
+// when a quota limit is lowered below the current usage.
+func evaluatePlanQuota(snap *state.StateSnapshot, plan *structs.Plan) (bool, error) {
+	if plan.Job == nil {
+		return false, nil
+	}
+
+	ns, err := snap.NamespaceByName(nil, plan.Job.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if ns == nil || ns.Quota == "" {
+		return false, nil
+	}
+
+	spec, err := snap.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		return false, err
+	}
+	if spec == nil {
+		return false, nil
+	}
+	limit := spec.RegionLimit(snap.Config().Region)
+	if limit == nil {
+		return false, nil
+	}
+
+	usage, err := snap.QuotaUsageByName(nil, ns.Quota)
+	if err != nil {
+		return false, err
+	}
+	if usage == nil {
+		return false, nil
+	}
+	base, ok := usage.Used[limit.UsageKey()]
+	if !ok {
+		return false, nil
+	}
+
+	proposed := base.Copy()
+	existingFn := func(allocID string) (*structs.Allocation, error) {
+		return snap.AllocByID(nil, allocID)
+	}
+	if err := proposed.AddPlan(plan, existingFn); err != nil {
+		return false, err
+	}
+
+	return limit.Exceeds(base, proposed), nil
 }
@@ -434,6 +434,65 @@ func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Quota(t *testing.T) {
+	ci.Parallel(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	// Create a quota which only allows a single mock allocation
+	qs := mock.QuotaSpec()
+	qs.Limits[0].RegionLimit.CPU = 500
+	qs.Limits[0].SetHash()
+	qs.SetHash()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1001, []*structs.QuotaSpec{qs}))
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1002, []*structs.Namespace{ns}))
+
+	existing := mock.Alloc()
+	existing.NodeID = node.ID
+	existing.Namespace = ns.Name
+	existing.Job.Namespace = ns.Name
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1003, existing.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1004, []*structs.Allocation{existing}))
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	// Placing another allocation exceeds the quota, so the plan is rejected
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Namespace = ns.Name
+	alloc.Job = existing.Job
+	alloc.JobID = existing.JobID
+	plan := &structs.Plan{
+		Job: existing.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	snap, _ := state.Snapshot()
+	result, err := evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Empty(t, result.NodeAllocation)
+	require.EqualValues(t, 1004, result.RefreshIndex)
+
+	// Replacing the existing allocation stays within the quota
+	stop := existing.Copy()
+	stop.DesiredStatus = structs.AllocDesiredStatusStop
+	plan.NodeUpdate = map[string][]*structs.Allocation{
+		node.ID: {stop},
+	}
+
+	result, err = evaluatePlan(pool, snap, plan, testlog.HCLogger(t))
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, plan.NodeAllocation, result.NodeAllocation)
+}
+
 func TestPlanApply_EvalPlan_Preemption(t *testing.T) {
 	ci.Parallel(t)
 	state := testStateStore(t)
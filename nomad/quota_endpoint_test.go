@@ -0,0 +1,229 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaEndpoint_UpsertQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas:       []*structs.QuotaSpec{qs1, qs2},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	// Check we created the quota specifications and their usages
+	out, err := s1.fsm.State().QuotaSpecByName(nil, qs1.Name)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.NotEmpty(t, out.Hash)
+	usage, err := s1.fsm.State().QuotaUsageByName(nil, qs2.Name)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+
+	// Invalid quota specifications are rejected
+	invalid := mock.QuotaSpec()
+	invalid.Name = "*"
+	req.Quotas = []*structs.QuotaSpec{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid name")
+}
+
+func TestQuotaEndpoint_UpsertQuotaSpecs_ACL(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "quota-read", mock.QuotaPolicy(acl.PolicyRead))
+	writeToken := mock.CreatePolicyAndToken(t, state, 1003, "quota-write", mock.QuotaPolicy(acl.PolicyWrite))
+
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas:       []*structs.QuotaSpec{mock.QuotaSpec()},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+
+	// Try without a token and with a read token
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = readToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with a write token and the root token
+	req.AuthToken = writeToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+
+	req.Quotas = []*structs.QuotaSpec{mock.QuotaSpec()}
+	req.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+}
+
+func TestQuotaEndpoint_DeleteQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	state := s1.fsm.State()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs1, qs2}))
+
+	// A quota specification referenced by a namespace can't be deleted
+	ns := mock.Namespace()
+	ns.Quota = qs1.Name
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns}))
+
+	req := &structs.QuotaSpecDeleteRequest{
+		Names:        []string{qs1.Name},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", req, &resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is referenced by namespace")
+
+	req.Names = []string{qs2.Name}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", req, &resp))
+	require.NotZero(t, resp.Index)
+
+	out, err := state.QuotaSpecByName(nil, qs2.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+}
+
+func TestQuotaEndpoint_GetQuotaSpec(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	qs := mock.QuotaSpec()
+	require.NoError(t, s1.fsm.State().UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs}))
+
+	// Lookup the quota specification
+	get := &structs.QuotaSpecSpecificRequest{
+		Name:         qs.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleQuotaSpecResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", get, &resp))
+	require.EqualValues(t, 1000, resp.Index)
+	require.Equal(t, qs, resp.Quota)
+
+	// Lookup the quota usage
+	var usageResp structs.SingleQuotaUsageResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaUsage", get, &usageResp))
+	require.EqualValues(t, 1000, usageResp.Index)
+	require.NotNil(t, usageResp.Usage)
+	require.Contains(t, usageResp.Usage.Used, qs.Limits[0].UsageKey())
+
+	// Lookup non-existing quota specification
+	get.Name = uuid.Generate()
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", get, &resp))
+	require.EqualValues(t, 1000, resp.Index)
+	require.Nil(t, resp.Quota)
+}
+
+func TestQuotaEndpoint_ListQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	qs1.Name = "aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"
+	qs2.Name = "aaaabbbb-3350-4b4b-d185-0e1992ed43e9"
+	require.NoError(t, s1.fsm.State().UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs1, qs2}))
+
+	// Lookup the quota specifications
+	get := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.QuotaSpecListResponse
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &resp))
+	require.EqualValues(t, 1000, resp.Index)
+	require.Len(t, resp.Quotas, 2)
+
+	// Lookup the quota specifications by prefix
+	get.Prefix = "aaaab"
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &resp))
+	require.Len(t, resp.Quotas, 1)
+	require.Equal(t, qs2.Name, resp.Quotas[0].Name)
+
+	// Lookup the quota usages by prefix
+	var usageResp structs.QuotaUsageListResponse
+	usageReq := &structs.QuotaUsageListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Prefix: "aaaab"},
+	}
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaUsages", usageReq, &usageResp))
+	require.EqualValues(t, 1000, usageResp.Index)
+	require.Len(t, usageResp.Usages, 1)
+	require.Equal(t, qs2.Name, usageResp.Usages[0].Name)
+}
+
+func TestQuotaEndpoint_ListQuotaSpecs_ACL(t *testing.T) {
+	ci.Parallel(t)
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	qs := mock.QuotaSpec()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs}))
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "quota-read", mock.QuotaPolicy(acl.PolicyRead))
+	denyToken := mock.CreatePolicyAndToken(t, state, 1003, "quota-deny", mock.QuotaPolicy(acl.PolicyDeny))
+
+	get := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Try without a token and with a deny token
+	var resp structs.QuotaSpecListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = denyToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &resp)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with a read token and the root token
+	get.AuthToken = readToken.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &resp))
+	require.Len(t, resp.Quotas, 1)
+
+	get.AuthToken = root.SecretID
+	require.NoError(t, msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", get, &resp))
+	require.Len(t, resp.Quotas, 1)
+}
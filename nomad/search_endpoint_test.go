@@ -729,7 +729,7 @@ func TestSearch_PrefixSearch_CSIVolume(t *testing.T) {
 	testutil.WaitForLeader(t, s.RPC)
 
 	id := uuid.Generate()
-	err := s.fsm.State().UpsertCSIVolume(1000, []*structs.CSIVolume{{
+	err := s.fsm.State().UpsertCSIVolume(structs.MsgTypeTestSetup, 1000, []*structs.CSIVolume{{
 		ID:        id,
 		Namespace: structs.DefaultNamespace,
 		PluginID:  "glade",
@@ -766,7 +766,7 @@ func TestSearch_PrefixSearch_Namespace(t *testing.T) {
 	testutil.WaitForLeader(t, s.RPC)
 
 	ns := mock.Namespace()
-	require.NoError(t, s.fsm.State().UpsertNamespaces(2000, []*structs.Namespace{ns}))
+	require.NoError(t, s.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 2000, []*structs.Namespace{ns}))
 
 	prefix := ns.Name[:len(ns.Name)-2]
 
@@ -800,7 +800,7 @@ func TestSearch_PrefixSearch_Namespace_ACL(t *testing.T) {
 	fsmState := s.fsm.State()
 
 	ns := mock.Namespace()
-	require.NoError(t, fsmState.UpsertNamespaces(500, []*structs.Namespace{ns}))
+	require.NoError(t, fsmState.UpsertNamespaces(structs.MsgTypeTestSetup, 500, []*structs.Namespace{ns}))
 
 	job1 := mock.Job()
 	require.NoError(t, fsmState.UpsertJob(structs.MsgTypeTestSetup, 502, job1))
@@ -1349,7 +1349,7 @@ func TestSearch_FuzzySearch_CSIVolume(t *testing.T) {
 	testutil.WaitForLeader(t, s.RPC)
 
 	id := uuid.Generate()
-	err := s.fsm.State().UpsertCSIVolume(1000, []*structs.CSIVolume{{
+	err := s.fsm.State().UpsertCSIVolume(structs.MsgTypeTestSetup, 1000, []*structs.CSIVolume{{
 		ID:        id,
 		Namespace: structs.DefaultNamespace,
 		PluginID:  "glade",
@@ -1384,7 +1384,7 @@ func TestSearch_FuzzySearch_Namespace(t *testing.T) {
 	testutil.WaitForLeader(t, s.RPC)
 
 	ns := mock.Namespace()
-	require.NoError(t, s.fsm.State().UpsertNamespaces(2000, []*structs.Namespace{ns}))
+	require.NoError(t, s.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 2000, []*structs.Namespace{ns}))
 
 	req := &structs.FuzzySearchRequest{
 		Text:    "am", // mock is team-<uuid>
@@ -1415,7 +1415,7 @@ func TestSearch_FuzzySearch_Namespace_caseInsensitive(t *testing.T) {
 
 	ns := mock.Namespace()
 	ns.Name = "TheFooNamespace"
-	require.NoError(t, s.fsm.State().UpsertNamespaces(2000, []*structs.Namespace{ns}))
+	require.NoError(t, s.fsm.State().UpsertNamespaces(structs.MsgTypeTestSetup, 2000, []*structs.Namespace{ns}))
 
 	req := &structs.FuzzySearchRequest{
 		Text:    "foon",
@@ -1485,7 +1485,7 @@ func TestSearch_FuzzySearch_Namespace_ACL(t *testing.T) {
 
 	ns := mock.Namespace()
 	ns.Name = "team-job-app"
-	require.NoError(t, fsmState.UpsertNamespaces(500, []*structs.Namespace{ns}))
+	require.NoError(t, fsmState.UpsertNamespaces(structs.MsgTypeTestSetup, 500, []*structs.Namespace{ns}))
 
 	job1 := mock.Job()
 	require.NoError(t, fsmState.UpsertJob(structs.MsgTypeTestSetup, 502, job1))
@@ -1597,7 +1597,7 @@ func TestSearch_FuzzySearch_MultiNamespace_ACL(t *testing.T) {
 	testutil.WaitForLeader(t, s.RPC)
 	fsmState := s.fsm.State()
 
-	require.NoError(t, fsmState.UpsertNamespaces(500, []*structs.Namespace{{
+	require.NoError(t, fsmState.UpsertNamespaces(structs.MsgTypeTestSetup, 500, []*structs.Namespace{{
 		Name:        "teamA",
 		Description: "first namespace",
 		CreateIndex: 100,
@@ -1939,7 +1939,7 @@ func TestSearch_FuzzySearch_Job(t *testing.T) {
 
 	ns := mock.Namespace()
 	ns.Name = job.Namespace
-	require.NoError(t, fsmState.UpsertNamespaces(2000, []*structs.Namespace{ns}))
+	require.NoError(t, fsmState.UpsertNamespaces(structs.MsgTypeTestSetup, 2000, []*structs.Namespace{ns}))
 	registerJob(s, t, job)
 	require.NoError(t, fsmState.UpsertNode(structs.MsgTypeTestSetup, 1003, mock.Node()))
 
@@ -34,6 +34,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/nomad/volumewatcher"
@@ -62,6 +63,7 @@ const (
 	peersPollJitterFactor = 2
 
 	raftState         = "raft/"
+	eventJournalPath  = "event_journal.db"
 	serfSnapshot      = "serf/snapshot"
 	snapshotsRetained = 2
 
@@ -110,6 +112,10 @@ type Server struct {
 	raftInmem     *raft.InmemStore
 	raftTransport *raft.NetworkTransport
 
+	// eventJournal is the optional on-disk journal of the events published
+	// by the state store. It outlives the state stores created by the FSM.
+	eventJournal *stream.EventJournal
+
 	// reassertLeaderCh is used to signal that the leader loop must
 	// re-establish leadership.
 	//
@@ -279,6 +285,7 @@ type endpoints struct {
 	Enterprise          *EnterpriseEndpoints
 	Event               *Event
 	Namespace           *Namespace
+	Quota               *Quota
 	ServiceRegistration *ServiceRegistration
 
 	// Client endpoints
@@ -347,6 +354,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigEntr
 	s.shutdownCtx, s.shutdownCancel = context.WithCancel(context.Background())
 	s.shutdownCh = s.shutdownCtx.Done()
 
+	// Dequeue evaluations by fair share between queues if enabled
+	evalBroker.SetFairShare(config.FairShareConfig, s.evalQueue)
+
 	// Create the RPC handler
 	s.rpcHandler = newRpcHandler(s)
 
@@ -659,6 +669,13 @@ func (s *Server) Shutdown() error {
 		s.fsm.Close()
 	}
 
+	// Close the event journal once the FSM can no longer publish events
+	if s.eventJournal != nil {
+		if err := s.eventJournal.Close(); err != nil {
+			s.logger.Warn("failed to close event journal", "error", err)
+		}
+	}
+
 	// Stop Vault token renewal and revocations
 	if s.vault != nil {
 		s.vault.Stop()
@@ -1028,6 +1045,8 @@ func (s *Server) setupDeploymentWatcher() error {
 	s.deploymentWatcher = deploymentwatcher.NewDeploymentsWatcher(
 		s.logger,
 		raftShim,
+		&deploymentWatcherStatsShim{s: s},
+		s.config.CanaryAnalysisConfig,
 		s.staticEndpoints.Deployment,
 		s.staticEndpoints.Job,
 		s.config.DeploymentQueryRateLimit,
@@ -1159,6 +1178,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.System = &System{srv: s, logger: s.logger.Named("system")}
 		s.staticEndpoints.Search = &Search{srv: s, logger: s.logger.Named("search")}
 		s.staticEndpoints.Namespace = &Namespace{srv: s}
+		s.staticEndpoints.Quota = &Quota{srv: s}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// These endpoints are dynamic because they need access to the
@@ -1206,6 +1226,7 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.FileSystem)
 	server.Register(s.staticEndpoints.Agent)
 	server.Register(s.staticEndpoints.Namespace)
+	server.Register(s.staticEndpoints.Quota)
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	alloc := &Alloc{srv: s, ctx: ctx, logger: s.logger.Named("alloc")}
@@ -1246,6 +1267,24 @@ func (s *Server) setupRaft() error {
 		EnableEventBroker: s.config.EnableEventBroker,
 		EventBufferSize:   s.config.EventBufferSize,
 	}
+
+	// The event journal is opened before the FSM, as the events published
+	// when replaying the raft log on startup must be written to it.
+	if s.config.EnableEventBroker && s.config.EnableEventJournal {
+		if s.config.DevMode {
+			s.logger.Warn("event journal is not supported in dev mode")
+		} else {
+			journal, err := stream.NewEventJournal(
+				filepath.Join(s.config.DataDir, eventJournalPath),
+				s.config.EventJournalSize, s.logger)
+			if err != nil {
+				return err
+			}
+			s.eventJournal = journal
+			fsmConfig.EventJournal = journal
+		}
+	}
+
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
 	if err != nil {
@@ -712,7 +712,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Create a policy and grab the token which has the read-job
 				// capability on the platform namespace.
@@ -765,7 +765,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Create a policy and grab the token which has the read policy
 				// on the platform namespace.
@@ -818,7 +818,7 @@ func TestServiceRegistration_List(t *testing.T) {
 					ModifyIndex: 5,
 				}
 				ns.SetHash()
-				require.NoError(t, s.State().UpsertNamespaces(5, []*structs.Namespace{ns}))
+				require.NoError(t, s.State().UpsertNamespaces(structs.MsgTypeTestSetup, 5, []*structs.Namespace{ns}))
 
 				// Generate a node.
 				node := mock.Node()
@@ -20,6 +20,7 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.NodeUpdateEligibilityRequestType:             structs.TypeNodeDrain,
 	structs.NodeUpdateDrainRequestType:                   structs.TypeNodeDrain,
 	structs.BatchNodeUpdateDrainRequestType:              structs.TypeNodeDrain,
+	structs.NodeMaintenanceRequestType:                   structs.TypeNodeMaintenance,
 	structs.DeploymentStatusUpdateRequestType:            structs.TypeDeploymentUpdate,
 	structs.DeploymentPromoteRequestType:                 structs.TypeDeploymentPromotion,
 	structs.DeploymentAllocHealthRequestType:             structs.TypeDeploymentAllocHealth,
@@ -31,6 +32,14 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
 	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
+	structs.CSIVolumeRegisterRequestType:                 structs.TypeCSIVolumeRegistered,
+	structs.CSIVolumeDeregisterRequestType:               structs.TypeCSIVolumeDeregistered,
+	structs.CSIVolumeClaimRequestType:                    structs.TypeCSIVolumeClaim,
+	structs.CSIVolumeClaimBatchRequestType:               structs.TypeCSIVolumeClaim,
+	structs.CSIVolumeUpdateStatsRequestType:              structs.TypeCSIVolumeStatsUpdated,
+	structs.CSIPluginDeleteRequestType:                   structs.TypeCSIPluginDeleted,
+	structs.NamespaceUpsertRequestType:                   structs.TypeNamespaceUpserted,
+	structs.NamespaceDeleteRequestType:                   structs.TypeNamespaceDeleted,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -108,6 +117,57 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					Service: before,
 				},
 			}, true
+		case "csi_volumes":
+			before, ok := change.Before.(*structs.CSIVolume)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:      structs.TopicCSIVolume,
+				Key:        before.ID,
+				FilterKeys: []string{before.PluginID},
+				Namespace:  before.Namespace,
+				Payload:    structs.NewCSIVolumeEvent(before),
+			}, true
+		case "csi_plugins":
+			before, ok := change.Before.(*structs.CSIPlugin)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic: structs.TopicCSIPlugin,
+				Key:   before.ID,
+				Payload: &structs.CSIPluginEvent{
+					Plugin: before,
+				},
+			}, true
+		case TableNamespaces:
+			before, ok := change.Before.(*structs.Namespace)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicNamespace,
+				Key:       before.Name,
+				Namespace: before.Name,
+				Payload: &structs.NamespaceEvent{
+					Namespace: before,
+				},
+			}, true
+		case "scaling_policy":
+			before, ok := change.Before.(*structs.ScalingPolicy)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:      structs.TopicScalingPolicy,
+				Key:        before.ID,
+				FilterKeys: []string{before.Target[structs.ScalingTargetJob]},
+				Namespace:  before.Target[structs.ScalingTargetNamespace],
+				Payload: &structs.ScalingPolicyEvent{
+					ScalingPolicy: before,
+				},
+			}, true
 		}
 		return structs.Event{}, false
 	}
@@ -235,6 +295,57 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 				Service: after,
 			},
 		}, true
+	case "csi_volumes":
+		after, ok := change.After.(*structs.CSIVolume)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:      structs.TopicCSIVolume,
+			Key:        after.ID,
+			FilterKeys: []string{after.PluginID},
+			Namespace:  after.Namespace,
+			Payload:    structs.NewCSIVolumeEvent(after),
+		}, true
+	case "csi_plugins":
+		after, ok := change.After.(*structs.CSIPlugin)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic: structs.TopicCSIPlugin,
+			Key:   after.ID,
+			Payload: &structs.CSIPluginEvent{
+				Plugin: after,
+			},
+		}, true
+	case TableNamespaces:
+		after, ok := change.After.(*structs.Namespace)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:     structs.TopicNamespace,
+			Key:       after.Name,
+			Namespace: after.Name,
+			Payload: &structs.NamespaceEvent{
+				Namespace: after,
+			},
+		}, true
+	case "scaling_policy":
+		after, ok := change.After.(*structs.ScalingPolicy)
+		if !ok {
+			return structs.Event{}, false
+		}
+		return structs.Event{
+			Topic:      structs.TopicScalingPolicy,
+			Key:        after.ID,
+			FilterKeys: []string{after.Target[structs.ScalingTargetJob]},
+			Namespace:  after.Target[structs.ScalingTargetNamespace],
+			Payload: &structs.ScalingPolicyEvent{
+				ScalingPolicy: after,
+			},
+		}, true
 	}
 
 	return structs.Event{}, false
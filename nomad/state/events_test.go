@@ -1002,6 +1002,187 @@ func Test_eventsFromChanges_ServiceRegistration(t *testing.T) {
 	require.Equal(t, service, eventPayload.Service)
 }
 
+func TestEventFromChange_CSIVolumeSecrets(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	alloc := mock.Alloc()
+	vol := mock.CSIVolume(mock.CSIPlugin())
+	vol.Secrets = structs.CSISecrets{"password": "hunter2"}
+	vol.ReadAllocs[alloc.ID] = alloc
+	vol.ReadClaims[alloc.ID] = &structs.CSIVolumeClaim{
+		AllocationID: alloc.ID,
+		NodeID:       alloc.NodeID,
+		Mode:         structs.CSIVolumeClaimRead,
+		State:        structs.CSIVolumeClaimStateTaken,
+	}
+
+	// Create
+	changes := Changes{
+		Index:   100,
+		MsgType: structs.CSIVolumeRegisterRequestType,
+		Changes: memdb.Changes{
+			{
+				Table:  "csi_volumes",
+				Before: nil,
+				After:  vol,
+			},
+		},
+	}
+
+	out := eventsFromChanges(s.db.ReadTxn(), changes)
+	require.Len(t, out.Events, 1)
+	require.Equal(t, structs.TopicCSIVolume, out.Events[0].Topic)
+	require.Equal(t, structs.TypeCSIVolumeRegistered, out.Events[0].Type)
+	require.Equal(t, vol.ID, out.Events[0].Key)
+	require.Equal(t, vol.Namespace, out.Events[0].Namespace)
+	require.Equal(t, []string{vol.PluginID}, out.Events[0].FilterKeys)
+
+	// Ensure original value not altered
+	require.Equal(t, "hunter2", vol.Secrets["password"])
+	require.NotNil(t, vol.ReadAllocs[alloc.ID])
+
+	volEvent, ok := out.Events[0].Payload.(*structs.CSIVolumeEvent)
+	require.True(t, ok)
+	require.Empty(t, volEvent.Volume.Secrets)
+	require.Contains(t, volEvent.Volume.ReadAllocs, alloc.ID)
+	require.Nil(t, volEvent.Volume.ReadAllocs[alloc.ID])
+	require.Contains(t, volEvent.Volume.ReadClaims, alloc.ID)
+
+	// Delete
+	changes = Changes{
+		Index:   101,
+		MsgType: structs.CSIVolumeDeregisterRequestType,
+		Changes: memdb.Changes{
+			{
+				Table:  "csi_volumes",
+				Before: vol,
+				After:  nil,
+			},
+		},
+	}
+
+	out2 := eventsFromChanges(s.db.ReadTxn(), changes)
+	require.Len(t, out2.Events, 1)
+	require.Equal(t, structs.TypeCSIVolumeDeregistered, out2.Events[0].Type)
+
+	volEvent2, ok := out2.Events[0].Payload.(*structs.CSIVolumeEvent)
+	require.True(t, ok)
+	require.Empty(t, volEvent2.Volume.Secrets)
+}
+
+func TestEventFromChange_CSIPlugin(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	plug := mock.CSIPlugin()
+
+	changes := Changes{
+		Index:   100,
+		MsgType: structs.CSIPluginDeleteRequestType,
+		Changes: memdb.Changes{
+			{
+				Table:  "csi_plugins",
+				Before: plug,
+				After:  nil,
+			},
+		},
+	}
+
+	out := eventsFromChanges(s.db.ReadTxn(), changes)
+	require.Len(t, out.Events, 1)
+	require.Equal(t, structs.TopicCSIPlugin, out.Events[0].Topic)
+	require.Equal(t, structs.TypeCSIPluginDeleted, out.Events[0].Type)
+	require.Equal(t, plug.ID, out.Events[0].Key)
+	require.Empty(t, out.Events[0].Namespace)
+
+	plugEvent, ok := out.Events[0].Payload.(*structs.CSIPluginEvent)
+	require.True(t, ok)
+	require.Equal(t, plug, plugEvent.Plugin)
+}
+
+func TestEventFromChange_Namespace(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	ns := mock.Namespace()
+
+	changes := Changes{
+		Index:   100,
+		MsgType: structs.NamespaceUpsertRequestType,
+		Changes: memdb.Changes{
+			{
+				Table:  TableNamespaces,
+				Before: nil,
+				After:  ns,
+			},
+		},
+	}
+
+	out := eventsFromChanges(s.db.ReadTxn(), changes)
+	require.Len(t, out.Events, 1)
+	require.Equal(t, structs.TopicNamespace, out.Events[0].Topic)
+	require.Equal(t, structs.TypeNamespaceUpserted, out.Events[0].Type)
+	require.Equal(t, ns.Name, out.Events[0].Key)
+	require.Equal(t, ns.Name, out.Events[0].Namespace)
+
+	nsEvent, ok := out.Events[0].Payload.(*structs.NamespaceEvent)
+	require.True(t, ok)
+	require.Equal(t, ns.Name, nsEvent.Namespace.Name)
+	require.Equal(t, ns.Description, nsEvent.Namespace.Description)
+
+	changes = Changes{
+		Index:   101,
+		MsgType: structs.NamespaceDeleteRequestType,
+		Changes: memdb.Changes{
+			{
+				Table:  TableNamespaces,
+				Before: ns,
+				After:  nil,
+			},
+		},
+	}
+
+	out = eventsFromChanges(s.db.ReadTxn(), changes)
+	require.Len(t, out.Events, 1)
+	require.Equal(t, structs.TopicNamespace, out.Events[0].Topic)
+	require.Equal(t, structs.TypeNamespaceDeleted, out.Events[0].Type)
+	require.Equal(t, ns.Name, out.Events[0].Key)
+	require.Equal(t, ns.Name, out.Events[0].Namespace)
+}
+
+func TestEventsFromChanges_ScalingPolicy(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	job, policy := mock.JobWithScalingPolicy()
+	require.NoError(t, s.UpsertJob(structs.JobRegisterRequestType, 100, job))
+
+	events := WaitForEvents(t, s, 100, 2, 1*time.Second)
+
+	var policyEvents []structs.Event
+	for _, e := range events {
+		if e.Topic == structs.TopicScalingPolicy {
+			policyEvents = append(policyEvents, e)
+		}
+	}
+	require.Len(t, policyEvents, 1)
+
+	got := policyEvents[0]
+	require.Equal(t, structs.TypeJobRegistered, got.Type)
+	require.Equal(t, policy.ID, got.Key)
+	require.Equal(t, job.Namespace, got.Namespace)
+	require.Contains(t, got.FilterKeys, job.ID)
+
+	policyEvent := got.Payload.(*structs.ScalingPolicyEvent)
+	require.Equal(t, policy.ID, policyEvent.ScalingPolicy.ID)
+	require.Equal(t, job.TaskGroups[0].Name, policyEvent.ScalingPolicy.Target[structs.ScalingTargetGroup])
+}
+
 func requireNodeRegistrationEventEqual(t *testing.T, want, got structs.Event) {
 	t.Helper()
 
@@ -14,6 +14,9 @@ const (
 
 	TableNamespaces           = "namespaces"
 	TableServiceRegistrations = "service_registrations"
+	TableQuotaSpecs           = "quota_specs"
+	TableQuotaUsages          = "quota_usages"
+	TableNodeClassMaintenance = "node_class_maintenance"
 )
 
 const (
@@ -70,6 +73,9 @@ func init() {
 		scalingEventTableSchema,
 		namespaceTableSchema,
 		serviceRegistrationsTableSchema,
+		quotaSpecTableSchema,
+		quotaUsageTableSchema,
+		nodeClassMaintenanceTableSchema,
 	}...)
 }
 
@@ -1202,3 +1208,58 @@ func serviceRegistrationsTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// quotaSpecTableSchema returns the MemDB schema for the quota specification
+// table.
+func quotaSpecTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableQuotaSpecs,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// quotaUsageTableSchema returns the MemDB schema for the quota usage table.
+// There is a single usage for each quota specification, tracking the usage of
+// the specification within the local region.
+func quotaUsageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableQuotaUsages,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// nodeClassMaintenanceTableSchema returns the MemDB schema for the table of
+// the maintenance windows and capacity reservations declared for node classes.
+func nodeClassMaintenanceTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableNodeClassMaintenance,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodeClass",
+				},
+			},
+		},
+	}
+}
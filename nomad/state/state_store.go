@@ -71,6 +71,10 @@ type StateStoreConfig struct {
 
 	// EventBufferSize configures the amount of events to hold in memory
 	EventBufferSize int64
+
+	// EventJournal is the optional on-disk journal the event publisher
+	// writes events to.
+	EventJournal *stream.EventJournal
 }
 
 // The StateStore is responsible for maintaining all the Nomad
@@ -127,6 +131,7 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		broker, err := stream.NewEventBroker(ctx, &streamACLDelegate{s}, stream.EventBrokerCfg{
 			EventBufferSize: config.EventBufferSize,
 			Logger:          config.Logger,
+			Journal:         config.EventJournal,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("creating state store event broker %w", err)
@@ -172,7 +177,7 @@ func (s *StateStore) namespaceInit() error {
 		Description: structs.DefaultNamespaceDescription,
 	}
 
-	if err := s.UpsertNamespaces(1, []*structs.Namespace{defaultNs}); err != nil {
+	if err := s.UpsertNamespaces(structs.IgnoreUnknownTypeFlag, 1, []*structs.Namespace{defaultNs}); err != nil {
 		return fmt.Errorf("inserting default namespace failed: %v", err)
 	}
 
@@ -896,6 +901,16 @@ func upsertNodeTxn(txn *txn, index uint64, node *structs.Node) error {
 		node.SchedulingEligibility = exist.SchedulingEligibility // Retain the eligibility
 		node.DrainStrategy = exist.DrainStrategy                 // Retain the drain strategy
 		node.LastDrain = exist.LastDrain                         // Retain the drain metadata
+
+		// Retain the maintenance windows and capacity reservations, and
+		// update those of the node class if it changed
+		node.MaintenanceWindows = exist.MaintenanceWindows
+		node.CapacityReservations = exist.CapacityReservations
+		if exist.NodeClass != node.NodeClass {
+			if err := joinNodeClassMaintenanceTxn(txn, node, exist.NodeClass); err != nil {
+				return err
+			}
+		}
 	} else {
 		// Because this is the first time the node is being registered, we should
 		// also create a node registration event
@@ -905,6 +920,12 @@ func upsertNodeTxn(txn *txn, index uint64, node *structs.Node) error {
 		node.Events = []*structs.NodeEvent{nodeEvent}
 		node.CreateIndex = index
 		node.ModifyIndex = index
+
+		// Add the maintenance windows and capacity reservations of the
+		// node class
+		if err := joinNodeClassMaintenanceTxn(txn, node, ""); err != nil {
+			return err
+		}
 	}
 
 	// Insert the node
@@ -1179,6 +1200,230 @@ func (s *StateStore) UpdateNodeEligibility(msgType structs.MessageType, index ui
 	return txn.Commit()
 }
 
+// UpdateNodeMaintenance is used to update the maintenance windows and
+// capacity reservations of nodes
+func (s *StateStore) UpdateNodeMaintenance(msgType structs.MessageType, index uint64, req *structs.NodeMaintenanceRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, nodeID := range req.NodeIDs {
+		// Lookup the node
+		existing, err := txn.First("nodes", "id", nodeID)
+		if err != nil {
+			return fmt.Errorf("node lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("node not found: %s", nodeID)
+		}
+
+		// Copy the existing node
+		copyNode := existing.(*structs.Node).Copy()
+		copyNode.StatusUpdatedAt = req.UpdatedAt
+
+		// Add the event if given
+		if req.NodeEvent != nil {
+			appendNodeEvents(index, copyNode, []*structs.NodeEvent{req.NodeEvent.Copy()})
+		}
+
+		copyNode.MaintenanceWindows, copyNode.CapacityReservations = mergeNodeMaintenance(
+			copyNode.MaintenanceWindows, copyNode.CapacityReservations, req)
+		copyNode.ModifyIndex = index
+
+		// Insert the node
+		if err := txn.Insert("nodes", copyNode); err != nil {
+			return fmt.Errorf("node update failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"nodes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	// Store the update of a node class for the nodes that join it later
+	if req.NodeClass != "" {
+		if err := updateNodeClassMaintenanceTxn(txn, index, req); err != nil {
+			return err
+		}
+	}
+
+	return txn.Commit()
+}
+
+// updateNodeClassMaintenanceTxn applies a maintenance update to the
+// maintenance stored for its node class, deleting it once it's empty.
+func updateNodeClassMaintenanceTxn(txn *txn, index uint64, req *structs.NodeMaintenanceRequest) error {
+	existing, err := txn.First(TableNodeClassMaintenance, indexID, req.NodeClass)
+	if err != nil {
+		return fmt.Errorf("node class maintenance lookup failed: %v", err)
+	}
+
+	maintenance := &structs.NodeClassMaintenance{
+		NodeClass:   req.NodeClass,
+		CreateIndex: index,
+	}
+	if existing != nil {
+		maintenance = existing.(*structs.NodeClassMaintenance).Copy()
+	}
+	maintenance.ModifyIndex = index
+	maintenance.MaintenanceWindows, maintenance.CapacityReservations = mergeNodeMaintenance(
+		maintenance.MaintenanceWindows, maintenance.CapacityReservations, req)
+
+	if len(maintenance.MaintenanceWindows)+len(maintenance.CapacityReservations) == 0 {
+		if existing != nil {
+			if err := txn.Delete(TableNodeClassMaintenance, existing); err != nil {
+				return fmt.Errorf("node class maintenance delete failed: %v", err)
+			}
+		}
+	} else if err := txn.Insert(TableNodeClassMaintenance, maintenance); err != nil {
+		return fmt.Errorf("node class maintenance update failed: %v", err)
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableNodeClassMaintenance, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// joinNodeClassMaintenanceTxn adds the maintenance windows and capacity
+// reservations of the class of a node that joins it. Those of the class the
+// node left are removed, unless the window already started on the node.
+func joinNodeClassMaintenanceTxn(txn *txn, node *structs.Node, prevClass string) error {
+	req := &structs.NodeMaintenanceRequest{}
+
+	if prevClass != "" {
+		existing, err := txn.First(TableNodeClassMaintenance, indexID, prevClass)
+		if err != nil {
+			return fmt.Errorf("node class maintenance lookup failed: %v", err)
+		}
+		if existing != nil {
+			prev := existing.(*structs.NodeClassMaintenance)
+			for _, w := range prev.MaintenanceWindows {
+				for _, nw := range node.MaintenanceWindows {
+					if nw.ID == w.ID && nw.Status == structs.NodeMaintenanceWindowPending {
+						req.DeleteWindows = append(req.DeleteWindows, w.ID)
+					}
+				}
+			}
+			for _, r := range prev.CapacityReservations {
+				req.DeleteReservations = append(req.DeleteReservations, r.ID)
+			}
+		}
+	}
+
+	if node.NodeClass != "" {
+		existing, err := txn.First(TableNodeClassMaintenance, indexID, node.NodeClass)
+		if err != nil {
+			return fmt.Errorf("node class maintenance lookup failed: %v", err)
+		}
+		if existing != nil {
+			maintenance := existing.(*structs.NodeClassMaintenance)
+			for _, w := range maintenance.MaintenanceWindows {
+				// Don't restart a window the node already has
+				if !hasNodeMaintenanceWindow(node, w.ID) {
+					req.Windows = append(req.Windows, w)
+				}
+			}
+			req.Reservations = maintenance.CapacityReservations
+		}
+	}
+
+	node.MaintenanceWindows, node.CapacityReservations = mergeNodeMaintenance(
+		node.MaintenanceWindows, node.CapacityReservations, req)
+	return nil
+}
+
+// hasNodeMaintenanceWindow returns whether the node has the maintenance
+// window with the given ID.
+func hasNodeMaintenanceWindow(node *structs.Node, id string) bool {
+	for _, w := range node.MaintenanceWindows {
+		if w.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeNodeMaintenance returns the maintenance windows and capacity
+// reservations with those of the request replaced or added, and the deleted
+// ones removed.
+func mergeNodeMaintenance(windows []*structs.NodeMaintenanceWindow, reservations []*structs.NodeCapacityReservation,
+	req *structs.NodeMaintenanceRequest) ([]*structs.NodeMaintenanceWindow, []*structs.NodeCapacityReservation) {
+
+	deleteWindows := make(map[string]struct{}, len(req.DeleteWindows)+len(req.Windows))
+	for _, id := range req.DeleteWindows {
+		deleteWindows[id] = struct{}{}
+	}
+	for _, w := range req.Windows {
+		deleteWindows[w.ID] = struct{}{}
+	}
+	deleteReservations := make(map[string]struct{}, len(req.DeleteReservations)+len(req.Reservations))
+	for _, id := range req.DeleteReservations {
+		deleteReservations[id] = struct{}{}
+	}
+	for _, r := range req.Reservations {
+		deleteReservations[r.ID] = struct{}{}
+	}
+
+	newWindows := make([]*structs.NodeMaintenanceWindow, 0, len(windows)+len(req.Windows))
+	for _, w := range windows {
+		if _, ok := deleteWindows[w.ID]; !ok {
+			newWindows = append(newWindows, w)
+		}
+	}
+	for _, w := range req.Windows {
+		newWindows = append(newWindows, w.Copy())
+	}
+	sort.Slice(newWindows, func(i, j int) bool { return newWindows[i].Start.Before(newWindows[j].Start) })
+	if len(newWindows) == 0 {
+		newWindows = nil
+	}
+
+	newReservations := make([]*structs.NodeCapacityReservation, 0, len(reservations)+len(req.Reservations))
+	for _, r := range reservations {
+		if _, ok := deleteReservations[r.ID]; !ok {
+			newReservations = append(newReservations, r)
+		}
+	}
+	for _, r := range req.Reservations {
+		newReservations = append(newReservations, r.Copy())
+	}
+	if len(newReservations) == 0 {
+		newReservations = nil
+	}
+
+	return newWindows, newReservations
+}
+
+// NodeClassMaintenanceByClass is used to lookup the maintenance declared for
+// a node class
+func (s *StateStore) NodeClassMaintenanceByClass(ws memdb.WatchSet, class string) (*structs.NodeClassMaintenance, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableNodeClassMaintenance, indexID, class)
+	if err != nil {
+		return nil, fmt.Errorf("node class maintenance lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.NodeClassMaintenance), nil
+	}
+	return nil, nil
+}
+
+// NodeClassMaintenances returns an iterator over the maintenance declared for
+// all the node classes
+func (s *StateStore) NodeClassMaintenances(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableNodeClassMaintenance, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("node class maintenance lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // UpsertNodeEvents adds the node events to the nodes, rotating events as
 // necessary.
 func (s *StateStore) UpsertNodeEvents(msgType structs.MessageType, index uint64, nodeEvents map[string][]*structs.NodeEvent) error {
@@ -2194,8 +2439,8 @@ func (s *StateStore) JobSummaryByPrefix(ws memdb.WatchSet, namespace, id string)
 }
 
 // UpsertCSIVolume inserts a volume in the state store.
-func (s *StateStore) UpsertCSIVolume(index uint64, volumes []*structs.CSIVolume) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertCSIVolume(msgType structs.MessageType, index uint64, volumes []*structs.CSIVolume) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, v := range volumes {
@@ -2214,16 +2459,28 @@ func (s *StateStore) UpsertCSIVolume(index uint64, volumes []*structs.CSIVolume)
 			// prevent accidentally overwriting important properties, or
 			// overwriting a volume in use
 			old := obj.(*structs.CSIVolume)
-			if old.ExternalID != v.ExternalID ||
-				old.PluginID != v.PluginID ||
-				old.Provider != v.Provider {
-				return fmt.Errorf("volume identity cannot be updated: %s", v.ID)
+			if err := old.ValidateUpdate(v); err != nil {
+				return err
 			}
 			s.CSIVolumeDenormalize(nil, old.Copy())
 			if old.InUse() {
-				return fmt.Errorf("volume cannot be updated while in use")
+				// Volumes in use can be expanded, but the claims may
+				// have changed since the update was submitted, so we
+				// always keep the current claims
+				current := old.Copy()
+				v.AccessMode = current.AccessMode
+				v.AttachmentMode = current.AttachmentMode
+				v.ReadAllocs = current.ReadAllocs
+				v.WriteAllocs = current.WriteAllocs
+				v.ReadClaims = current.ReadClaims
+				v.WriteClaims = current.WriteClaims
+				v.PastClaims = current.PastClaims
 			}
 
+			// Stats are only ever reported by clients, so they are
+			// never part of the update
+			v.Stats = old.Copy().Stats
+
 			v.CreateIndex = old.CreateIndex
 			v.ModifyIndex = index
 		} else {
@@ -2419,8 +2676,8 @@ func (s *StateStore) CSIVolumesByNamespace(ws memdb.WatchSet, namespace, prefix
 }
 
 // CSIVolumeClaim updates the volume's claim count and allocation list
-func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *structs.CSIVolumeClaim) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) CSIVolumeClaim(msgType structs.MessageType, index uint64, namespace, id string, claim *structs.CSIVolumeClaim) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	row, err := txn.First("csi_volumes", "id", namespace, id)
@@ -2494,9 +2751,61 @@ func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *s
 	return txn.Commit()
 }
 
+// UpdateCSIVolumeStats records the volume stats reported by a node.
+// Stats for volumes which no longer exist, or which are no longer claimed by
+// an allocation on the node, are ignored.
+func (s *StateStore) UpdateCSIVolumeStats(msgType structs.MessageType, index uint64, nodeID string, stats []*structs.CSIVolumeStats) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, stat := range stats {
+		row, err := txn.First("csi_volumes", "id", stat.Namespace, stat.VolumeID)
+		if err != nil {
+			return fmt.Errorf("volume lookup failed: %s: %v", stat.VolumeID, err)
+		}
+		if row == nil {
+			continue
+		}
+
+		orig, ok := row.(*structs.CSIVolume)
+		if !ok {
+			return fmt.Errorf("volume row conversion error")
+		}
+		if !orig.HasNodeClaims(nodeID) {
+			continue
+		}
+
+		volume := orig.Copy()
+		stat = stat.Copy()
+		stat.NodeID = nodeID
+		volume.UpdateStats(stat)
+		volume.ModifyIndex = index
+
+		// Allocations are copy on write, so we want to keep the
+		// Allocation ID but we need to clear the pointer so that we
+		// don't store it when we write the volume to the state store.
+		for allocID := range volume.ReadAllocs {
+			volume.ReadAllocs[allocID] = nil
+		}
+		for allocID := range volume.WriteAllocs {
+			volume.WriteAllocs[allocID] = nil
+		}
+
+		if err = txn.Insert("csi_volumes", volume); err != nil {
+			return fmt.Errorf("volume update failed: %s: %v", stat.VolumeID, err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"csi_volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
 // CSIVolumeDeregister removes the volume from the server
-func (s *StateStore) CSIVolumeDeregister(index uint64, namespace string, ids []string, force bool) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) CSIVolumeDeregister(msgType structs.MessageType, index uint64, namespace string, ids []string, force bool) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, id := range ids {
@@ -2826,8 +3135,8 @@ func (s *StateStore) UpsertCSIPlugin(index uint64, plug *structs.CSIPlugin) erro
 }
 
 // DeleteCSIPlugin deletes the plugin if it's not in use.
-func (s *StateStore) DeleteCSIPlugin(index uint64, id string) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) DeleteCSIPlugin(msgType structs.MessageType, index uint64, id string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	plug, err := s.CSIPluginByIDTxn(txn, nil, id)
@@ -3444,6 +3753,10 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 		return fmt.Errorf("error updating job summary: %v", err)
 	}
 
+	if err := s.updateQuotaWithAlloc(index, copyAlloc, exist, txn); err != nil {
+		return fmt.Errorf("error updating quota usage: %v", err)
+	}
+
 	if err := s.updateEntWithAlloc(index, copyAlloc, exist, txn); err != nil {
 		return err
 	}
@@ -3554,6 +3867,10 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return fmt.Errorf("error updating job summary: %v", err)
 		}
 
+		if err := s.updateQuotaWithAlloc(index, alloc, exist, txn); err != nil {
+			return fmt.Errorf("error updating quota usage: %v", err)
+		}
+
 		if err := s.updateEntWithAlloc(index, alloc, exist, txn); err != nil {
 			return err
 		}
@@ -4340,9 +4657,47 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 		groupIndex[g] = struct{}{}
 	}
 
+	// stepIndex is the set of groups whose current rollout step is promoted
+	// rather than their canaries
+	stepIndex := make(map[string]struct{})
+	if req.Step {
+		var stepErr multierror.Error
+		for tg, dstate := range deployment.TaskGroups {
+			if _, ok := groupIndex[tg]; !req.All && !ok {
+				continue
+			}
+			if dstate.DesiredCanaries > 0 && !dstate.Promoted {
+				continue
+			}
+
+			target, ok := dstate.StepTarget()
+			if !ok {
+				// Groups without a rollout step are only an error when
+				// explicitly promoted
+				if !req.All {
+					multierror.Append(&stepErr, fmt.Errorf("Task group %q has no rollout step to promote", tg))
+				}
+				continue
+			}
+			if dstate.HealthyAllocs < target {
+				multierror.Append(&stepErr, fmt.Errorf("Task group %q has %d/%d healthy allocations for rollout step %d",
+					tg, dstate.HealthyAllocs, target, dstate.CurrentStep+1))
+				continue
+			}
+			stepIndex[tg] = struct{}{}
+		}
+
+		if err := stepErr.ErrorOrNil(); err != nil {
+			return err
+		}
+	}
+
 	// canaryIndex is the set of placed canaries in the deployment
 	canaryIndex := make(map[string]struct{}, len(deployment.TaskGroups))
-	for _, dstate := range deployment.TaskGroups {
+	for tg, dstate := range deployment.TaskGroups {
+		if _, ok := stepIndex[tg]; ok {
+			continue
+		}
 		for _, c := range dstate.PlacedCanaries {
 			canaryIndex[c] = struct{}{}
 		}
@@ -4388,6 +4743,9 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 		if _, ok := groupIndex[tg]; !req.All && !ok {
 			continue
 		}
+		if _, ok := stepIndex[tg]; ok {
+			continue
+		}
 
 		need := dstate.DesiredCanaries
 		if need == 0 {
@@ -4412,11 +4770,22 @@ func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, inde
 			continue
 		}
 
+		// Rollouts with steps only move on to their next step if their
+		// canaries are already promoted
+		_, promoteStep := stepIndex[tg]
+		if req.Step && !promoteStep && (status.DesiredCanaries == 0 || status.Promoted) {
+			continue
+		}
+
 		// reset the progress deadline
 		if status.ProgressDeadline > 0 && !status.RequireProgressBy.IsZero() {
 			status.RequireProgressBy = time.Now().Add(status.ProgressDeadline)
 		}
-		status.Promoted = true
+		if promoteStep {
+			status.CurrentStep++
+		} else {
+			status.Promoted = true
+		}
 	}
 
 	// If the deployment no longer needs promotion, update its status
@@ -6163,8 +6532,8 @@ func (s *StateStore) NamespaceNames() ([]string, error) {
 }
 
 // UpsertNamespaces is used to register or update a set of namespaces.
-func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) UpsertNamespaces(msgType structs.MessageType, index uint64, namespaces []*structs.Namespace) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, ns := range namespaces {
@@ -6230,8 +6599,8 @@ func (s *StateStore) upsertNamespaceImpl(index uint64, txn *txn, namespace *stru
 }
 
 // DeleteNamespaces is used to remove a set of namespaces
-func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
-	txn := s.db.WriteTxn(index)
+func (s *StateStore) DeleteNamespaces(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
 	defer txn.Abort()
 
 	for _, name := range names {
@@ -6272,6 +6641,11 @@ func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
 		if err := txn.Delete(TableNamespaces, existing); err != nil {
 			return fmt.Errorf("namespace deletion failed: %v", err)
 		}
+
+		// Reconcile the quota the namespace referenced
+		if err := s.quotaReconcile(index, txn, "", ns.Quota); err != nil {
+			return err
+		}
 	}
 
 	if err := txn.Insert("index", &IndexEntry{TableNamespaces, index}); err != nil {
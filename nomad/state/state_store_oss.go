@@ -4,15 +4,47 @@
 package state
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
-// quotaSpecExists on returns whether the quota exists
+// quotaSpecExists returns whether the quota exists
 func (s *StateStore) quotaSpecExists(txn *txn, name string) (bool, error) {
-	return false, nil
+	existing, err := txn.First(TableQuotaSpecs, indexID, name)
+	if err != nil {
+		return false, fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	return existing != nil, nil
 }
 
+// quotaReconcile recomputes the usage of the quotas when a namespace changes
+// the quota it references.
 func (s *StateStore) quotaReconcile(index uint64, txn *txn, newQuota, oldQuota string) error {
+	if newQuota == oldQuota {
+		return nil
+	}
+
+	for _, name := range []string{newQuota, oldQuota} {
+		if name == "" {
+			continue
+		}
+
+		raw, err := txn.First(TableQuotaSpecs, indexID, name)
+		if err != nil {
+			return fmt.Errorf("quota specification lookup failed: %v", err)
+		}
+		if raw == nil {
+			continue
+		}
+		if err := s.computeQuotaUsage(index, txn, raw.(*structs.QuotaSpec)); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaUsages, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,304 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertQuotaSpecs is used to upsert a set of quota specifications. The usage
+// of each specification within the local region is recomputed, as its limits
+// may have changed.
+func (s *StateStore) UpsertQuotaSpecs(msgType structs.MessageType, index uint64, specs []*structs.QuotaSpec) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, spec := range specs {
+		if err := s.upsertQuotaSpecImpl(index, txn, spec); err != nil {
+			return err
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaSpecs, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaUsages, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// upsertQuotaSpecImpl is used to upsert a single quota specification and
+// recompute its usage.
+func (s *StateStore) upsertQuotaSpecImpl(index uint64, txn *txn, spec *structs.QuotaSpec) error {
+	// Ensure the spec hash is non-nil. This should be done outside the state
+	// store for performance reasons, but we check here for defense in depth.
+	if len(spec.Hash) == 0 {
+		spec.SetHash()
+	}
+
+	existing, err := txn.First(TableQuotaSpecs, indexID, spec.Name)
+	if err != nil {
+		return fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+
+	if existing != nil {
+		exist := existing.(*structs.QuotaSpec)
+		spec.CreateIndex = exist.CreateIndex
+		spec.ModifyIndex = index
+	} else {
+		spec.CreateIndex = index
+		spec.ModifyIndex = index
+	}
+
+	if err := txn.Insert(TableQuotaSpecs, spec); err != nil {
+		return fmt.Errorf("quota specification insert failed: %v", err)
+	}
+
+	return s.computeQuotaUsage(index, txn, spec)
+}
+
+// DeleteQuotaSpecs is used to remove a set of quota specifications and their
+// usages. A quota specification can not be deleted whilst it is referenced by
+// a namespace.
+func (s *StateStore) DeleteQuotaSpecs(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableQuotaSpecs, indexID, name)
+		if err != nil {
+			return fmt.Errorf("quota specification lookup failed: %v", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("quota specification %q not found", name)
+		}
+
+		// Ensure the quota is not in use
+		iter, err := txn.Get(TableNamespaces, "quota", name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+		if raw := iter.Next(); raw != nil {
+			return fmt.Errorf("quota specification %q is referenced by namespace %q",
+				name, raw.(*structs.Namespace).Name)
+		}
+
+		if err := txn.Delete(TableQuotaSpecs, existing); err != nil {
+			return fmt.Errorf("quota specification deletion failed: %v", err)
+		}
+		if _, err := txn.DeleteAll(TableQuotaUsages, indexID, name); err != nil {
+			return fmt.Errorf("quota usage deletion failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaSpecs, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaUsages, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// QuotaSpecByName is used to lookup a quota specification by name
+func (s *StateStore) QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableQuotaSpecs, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.QuotaSpec), nil
+	}
+	return nil, nil
+}
+
+// QuotaSpecs returns an iterator over all the quota specifications
+func (s *StateStore) QuotaSpecs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableQuotaSpecs, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("quota specifications lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// QuotaSpecsByNamePrefix is used to lookup quota specifications by prefix
+func (s *StateStore) QuotaSpecsByNamePrefix(ws memdb.WatchSet, namePrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableQuotaSpecs, indexID+"_prefix", namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("quota specifications lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// QuotaUsageByName is used to lookup the usage of a quota specification by
+// name
+func (s *StateStore) QuotaUsageByName(ws memdb.WatchSet, name string) (*structs.QuotaUsage, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableQuotaUsages, indexID, name)
+	if err != nil {
+		return nil, fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.QuotaUsage), nil
+	}
+	return nil, nil
+}
+
+// QuotaUsages returns an iterator over all the quota usages
+func (s *StateStore) QuotaUsages(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableQuotaUsages, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("quota usages lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// QuotaUsagesByNamePrefix is used to lookup quota usages by prefix
+func (s *StateStore) QuotaUsagesByNamePrefix(ws memdb.WatchSet, namePrefix string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableQuotaUsages, indexID+"_prefix", namePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("quota usages lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// computeQuotaUsage computes the usage of a quota specification from the
+// non-terminal allocations in every namespace referencing it, and inserts it.
+// It is the responsibility of the caller to update the index table.
+func (s *StateStore) computeQuotaUsage(index uint64, txn *txn, spec *structs.QuotaSpec) error {
+	usage := structs.NewQuotaUsage(spec, s.config.Region)
+
+	existing, err := txn.First(TableQuotaUsages, indexID, spec.Name)
+	if err != nil {
+		return fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	if existing != nil {
+		usage.CreateIndex = existing.(*structs.QuotaUsage).CreateIndex
+	} else {
+		usage.CreateIndex = index
+	}
+	usage.ModifyIndex = index
+
+	// Only the limit of the local region is tracked
+	if limit := spec.RegionLimit(s.config.Region); limit != nil {
+		used := usage.Used[limit.UsageKey()]
+
+		namespaces, err := txn.Get(TableNamespaces, "quota", spec.Name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+		for raw := namespaces.Next(); raw != nil; raw = namespaces.Next() {
+			ns := raw.(*structs.Namespace)
+			allocs, err := s.allocsByNamespaceImpl(nil, txn, ns.Name)
+			if err != nil {
+				return fmt.Errorf("allocation lookup failed: %v", err)
+			}
+			for rawAlloc := allocs.Next(); rawAlloc != nil; rawAlloc = allocs.Next() {
+				alloc := rawAlloc.(*structs.Allocation)
+				if !alloc.TerminalStatus() {
+					used.AddAllocation(alloc)
+				}
+			}
+		}
+	}
+
+	if err := txn.Insert(TableQuotaUsages, usage); err != nil {
+		return fmt.Errorf("quota usage insert failed: %v", err)
+	}
+	return nil
+}
+
+// updateQuotaWithAlloc is used to update the usage of the quota referenced by
+// the namespace of an allocation when the allocation is added, modified or
+// deleted.
+func (s *StateStore) updateQuotaWithAlloc(index uint64, new, existing *structs.Allocation, txn *txn) error {
+	// Namespaces are immutable for an allocation, so a single lookup suffices
+	alloc := new
+	if alloc == nil {
+		alloc = existing
+	}
+	if alloc == nil {
+		return nil
+	}
+
+	// Nothing to do if the allocation didn't use, and doesn't use, resources
+	existingUsing := existing != nil && !existing.TerminalStatus()
+	newUsing := new != nil && !new.TerminalStatus()
+	if !existingUsing && !newUsing {
+		return nil
+	}
+
+	rawNs, err := txn.First(TableNamespaces, indexID, alloc.Namespace)
+	if err != nil {
+		return fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	if rawNs == nil || rawNs.(*structs.Namespace).Quota == "" {
+		return nil
+	}
+	quota := rawNs.(*structs.Namespace).Quota
+
+	rawSpec, err := txn.First(TableQuotaSpecs, indexID, quota)
+	if err != nil {
+		return fmt.Errorf("quota specification lookup failed: %v", err)
+	}
+	if rawSpec == nil {
+		return nil
+	}
+	limit := rawSpec.(*structs.QuotaSpec).RegionLimit(s.config.Region)
+	if limit == nil {
+		return nil
+	}
+
+	rawUsage, err := txn.First(TableQuotaUsages, indexID, quota)
+	if err != nil {
+		return fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	if rawUsage == nil {
+		return fmt.Errorf("quota usage %q not found", quota)
+	}
+
+	usage := rawUsage.(*structs.QuotaUsage).Copy()
+	used, ok := usage.Used[limit.UsageKey()]
+	if !ok {
+		used = limit.NewUsage()
+		usage.Used[limit.UsageKey()] = used
+	}
+	if existingUsing {
+		used.SubtractAllocation(existing)
+	}
+	if newUsing {
+		used.AddAllocation(new)
+	}
+	usage.ModifyIndex = index
+
+	if err := txn.Insert(TableQuotaUsages, usage); err != nil {
+		return fmt.Errorf("quota usage insert failed: %v", err)
+	}
+	if err := txn.Insert(tableIndex, &IndexEntry{TableQuotaUsages, index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.QuotaSpecByName(ws, qs1.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs1, qs2}))
+	require.True(t, watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.QuotaSpecByName(ws, qs1.Name)
+	require.NoError(t, err)
+	require.Equal(t, qs1, out)
+
+	// An empty usage is created for each quota specification
+	usage, err := state.QuotaUsageByName(ws, qs2.Name)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+	require.Contains(t, usage.Used, qs2.Limits[0].UsageKey())
+	require.Zero(t, usage.Used[qs2.Limits[0].UsageKey()].RegionLimit.CPU)
+
+	index, err := state.Index(TableQuotaSpecs)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+	index, err = state.Index(TableQuotaUsages)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+	require.False(t, watchFired(ws))
+
+	iter, err := state.QuotaSpecsByNamePrefix(nil, "quota-")
+	require.NoError(t, err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(t, 2, count)
+}
+
+func TestStateStore_DeleteQuotaSpecs(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs1, qs2}))
+
+	// A quota specification referenced by a namespace can't be deleted
+	ns := mock.Namespace()
+	ns.Quota = qs1.Name
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns}))
+	err := state.DeleteQuotaSpecs(structs.MsgTypeTestSetup, 1002, []string{qs1.Name})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is referenced by namespace")
+
+	// Create a watchset so we can test that delete fires the watch
+	ws := memdb.NewWatchSet()
+	_, err = state.QuotaSpecByName(ws, qs2.Name)
+	require.NoError(t, err)
+
+	require.NoError(t, state.DeleteQuotaSpecs(structs.MsgTypeTestSetup, 1003, []string{qs2.Name}))
+	require.True(t, watchFired(ws))
+
+	out, err := state.QuotaSpecByName(nil, qs2.Name)
+	require.NoError(t, err)
+	require.Nil(t, out)
+	usage, err := state.QuotaUsageByName(nil, qs2.Name)
+	require.NoError(t, err)
+	require.Nil(t, usage)
+
+	index, err := state.Index(TableQuotaSpecs)
+	require.NoError(t, err)
+	require.EqualValues(t, 1003, index)
+}
+
+func TestStateStore_QuotaUsage_Allocs(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	qs := mock.QuotaSpec()
+	key := qs.Limits[0].UsageKey()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1000, []*structs.QuotaSpec{qs}))
+
+	ns := mock.Namespace()
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns}))
+
+	alloc1 := mock.Alloc()
+	alloc1.Namespace = ns.Name
+	alloc1.Job.Namespace = ns.Name
+	alloc2 := mock.Alloc()
+	alloc2.Namespace = ns.Name
+	alloc2.Job.Namespace = ns.Name
+	alloc2.JobID = alloc1.JobID
+	alloc2.Job = alloc1.Job
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1002, alloc1.Job))
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc1, alloc2}))
+
+	// The namespace doesn't reference the quota yet
+	usage, err := state.QuotaUsageByName(nil, qs.Name)
+	require.NoError(t, err)
+	require.Zero(t, usage.Used[key].RegionLimit.CPU)
+
+	// Referencing the quota accounts for the existing allocations
+	ns = ns.Copy()
+	ns.Quota = qs.Name
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1004, []*structs.Namespace{ns}))
+
+	usage, err = state.QuotaUsageByName(nil, qs.Name)
+	require.NoError(t, err)
+	require.Equal(t, 1000, usage.Used[key].RegionLimit.CPU)
+	require.Equal(t, 512, usage.Used[key].RegionLimit.MemoryMB)
+	require.EqualValues(t, 1004, usage.ModifyIndex)
+
+	// Stopping an allocation releases its usage
+	stopped := alloc2.Copy()
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, state.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1005, []*structs.Allocation{stopped}))
+
+	ws := memdb.NewWatchSet()
+	usage, err = state.QuotaUsageByName(ws, qs.Name)
+	require.NoError(t, err)
+	require.Equal(t, 500, usage.Used[key].RegionLimit.CPU)
+	require.Equal(t, 256, usage.Used[key].RegionLimit.MemoryMB)
+	require.EqualValues(t, 1005, usage.ModifyIndex)
+
+	// Placing a new allocation adds its usage
+	alloc3 := mock.Alloc()
+	alloc3.Namespace = ns.Name
+	alloc3.Job = alloc1.Job
+	alloc3.JobID = alloc1.JobID
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1006, []*structs.Allocation{alloc3}))
+	require.True(t, watchFired(ws))
+
+	usage, err = state.QuotaUsageByName(nil, qs.Name)
+	require.NoError(t, err)
+	require.Equal(t, 1000, usage.Used[key].RegionLimit.CPU)
+
+	// Changing the limits recomputes the usage
+	qs = qs.Copy()
+	qs.Limits[0].RegionLimit.CPU = 4000
+	qs.Limits[0].SetHash()
+	qs.SetHash()
+	require.NoError(t, state.UpsertQuotaSpecs(structs.MsgTypeTestSetup, 1007, []*structs.QuotaSpec{qs}))
+
+	usage, err = state.QuotaUsageByName(nil, qs.Name)
+	require.NoError(t, err)
+	require.Len(t, usage.Used, 1)
+	require.Equal(t, 1000, usage.Used[qs.Limits[0].UsageKey()].RegionLimit.CPU)
+
+	// Removing the quota from the namespace releases the usage
+	ns = ns.Copy()
+	ns.Quota = ""
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1008, []*structs.Namespace{ns}))
+
+	usage, err = state.QuotaUsageByName(nil, qs.Name)
+	require.NoError(t, err)
+	require.Zero(t, usage.Used[qs.Limits[0].UsageKey()].RegionLimit.CPU)
+}
@@ -189,6 +189,31 @@ func (r *StateRestore) NamespaceRestore(ns *structs.Namespace) error {
 	return nil
 }
 
+// QuotaSpecRestore is used to restore a quota specification
+func (r *StateRestore) QuotaSpecRestore(spec *structs.QuotaSpec) error {
+	if err := r.txn.Insert(TableQuotaSpecs, spec); err != nil {
+		return fmt.Errorf("quota specification insert failed: %v", err)
+	}
+	return nil
+}
+
+// QuotaUsageRestore is used to restore a quota usage
+func (r *StateRestore) QuotaUsageRestore(usage *structs.QuotaUsage) error {
+	if err := r.txn.Insert(TableQuotaUsages, usage); err != nil {
+		return fmt.Errorf("quota usage insert failed: %v", err)
+	}
+	return nil
+}
+
+// NodeClassMaintenanceRestore is used to restore the maintenance of a node
+// class
+func (r *StateRestore) NodeClassMaintenanceRestore(maintenance *structs.NodeClassMaintenance) error {
+	if err := r.txn.Insert(TableNodeClassMaintenance, maintenance); err != nil {
+		return fmt.Errorf("node class maintenance insert failed: %v", err)
+	}
+	return nil
+}
+
 // ServiceRegistrationRestore is used to restore a single service registration
 // into the service_registrations table.
 func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegistration) error {
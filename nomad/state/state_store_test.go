@@ -705,7 +705,7 @@ func TestStateStore_Deployments_Namespace(t *testing.T) {
 	deploy3.Namespace = ns2.Name
 	deploy4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -864,7 +864,7 @@ func TestStateStore_DeploymentsByIDPrefix_Namespaces(t *testing.T) {
 	deploy1.Namespace = ns1.Name
 	deploy2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertDeployment(1000, deploy1))
 	require.NoError(t, state.UpsertDeployment(1001, deploy2))
 
@@ -912,7 +912,7 @@ func TestStateStore_UpsertNamespaces(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns1.Name)
 	require.NoError(t, err)
 
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 	require.True(t, watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -937,14 +937,14 @@ func TestStateStore_DeleteNamespaces(t *testing.T) {
 	ns1 := mock.Namespace()
 	ns2 := mock.Namespace()
 
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns1, ns2}))
 
 	// Create a watchset so we can test that delete fires the watch
 	ws := memdb.NewWatchSet()
 	_, err := state.NamespaceByName(ws, ns1.Name)
 	require.NoError(t, err)
 
-	require.NoError(t, state.DeleteNamespaces(1001, []string{ns1.Name, ns2.Name}))
+	require.NoError(t, state.DeleteNamespaces(structs.MsgTypeTestSetup, 1001, []string{ns1.Name, ns2.Name}))
 	require.True(t, watchFired(ws))
 
 	ws = memdb.NewWatchSet()
@@ -969,9 +969,9 @@ func TestStateStore_DeleteNamespaces_Default(t *testing.T) {
 
 	ns := mock.Namespace()
 	ns.Name = structs.DefaultNamespace
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
-	err := state.DeleteNamespaces(1002, []string{ns.Name})
+	err := state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "can not be deleted")
 }
@@ -982,7 +982,7 @@ func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
 	state := testStateStore(t)
 
 	ns := mock.Namespace()
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	job := mock.Job()
 	job.Namespace = ns.Name
@@ -993,7 +993,7 @@ func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
 	_, err := state.NamespaceByName(ws, ns.Name)
 	require.NoError(t, err)
 
-	err = state.DeleteNamespaces(1002, []string{ns.Name})
+	err = state.DeleteNamespaces(structs.MsgTypeTestSetup, 1002, []string{ns.Name})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "one non-terminal")
 	require.False(t, watchFired(ws))
@@ -1020,7 +1020,7 @@ func TestStateStore_Namespaces(t *testing.T) {
 		namespaces = append(namespaces, ns)
 	}
 
-	require.NoError(t, state.UpsertNamespaces(1000, namespaces))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, namespaces))
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
@@ -1059,7 +1059,7 @@ func TestStateStore_NamespaceNames(t *testing.T) {
 		expectedNames = append(expectedNames, ns.Name)
 	}
 
-	err := state.UpsertNamespaces(1000, namespaces)
+	err := state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, namespaces)
 	require.NoError(t, err)
 
 	found, err := state.NamespaceNames()
@@ -1078,7 +1078,7 @@ func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 	ns := mock.Namespace()
 
 	ns.Name = "foobar"
-	require.NoError(t, state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 1000, []*structs.Namespace{ns}))
 
 	// Create a watchset so we can test that getters don't cause it to fire
 	ws := memdb.NewWatchSet()
@@ -1110,7 +1110,7 @@ func TestStateStore_NamespaceByNamePrefix(t *testing.T) {
 
 	ns = mock.Namespace()
 	ns.Name = "foozip"
-	err = state.UpsertNamespaces(1001, []*structs.Namespace{ns})
+	err = state.UpsertNamespaces(structs.MsgTypeTestSetup, 1001, []*structs.Namespace{ns})
 	require.NoError(t, err)
 	require.True(t, watchFired(ws))
 
@@ -1591,6 +1591,149 @@ func TestStateStore_UpdateNodeEligibility(t *testing.T) {
 	require.Contains(err.Error(), "while it is draining")
 }
 
+func TestStateStore_UpdateNodeMaintenance(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := testStateStore(t)
+	node := mock.Node()
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	now := time.Now()
+	later := &structs.NodeMaintenanceWindow{ID: "later", Start: now.Add(2 * time.Hour), End: now.Add(3 * time.Hour)}
+	sooner := &structs.NodeMaintenanceWindow{ID: "sooner", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)}
+	reservation := &structs.NodeCapacityReservation{ID: "r1", Namespace: "default", JobID: "example", CPU: 500, Expires: now.Add(time.Hour)}
+	event := structs.NewNodeEvent().SetSubsystem(structs.NodeEventSubsystemMaintenance).SetMessage("updated")
+
+	req := &structs.NodeMaintenanceRequest{
+		NodeIDs:      []string{node.ID},
+		Windows:      []*structs.NodeMaintenanceWindow{later, sooner},
+		Reservations: []*structs.NodeCapacityReservation{reservation},
+		NodeEvent:    event,
+		UpdatedAt:    7,
+	}
+	require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1001, req))
+
+	out, err := state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal([]*structs.NodeMaintenanceWindow{sooner, later}, out.MaintenanceWindows)
+	require.Equal([]*structs.NodeCapacityReservation{reservation}, out.CapacityReservations)
+	require.Len(out.Events, 2)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	index, err := state.Index("nodes")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+
+	// Re-registering the node retains its maintenance
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1002, node.Copy()))
+	out, err = state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Len(out.MaintenanceWindows, 2)
+	require.Len(out.CapacityReservations, 1)
+
+	// Update a window and delete the reservation
+	started := sooner.Copy()
+	started.Status = structs.NodeMaintenanceWindowActive
+	req = &structs.NodeMaintenanceRequest{
+		NodeIDs:            []string{node.ID},
+		Windows:            []*structs.NodeMaintenanceWindow{started},
+		DeleteWindows:      []string{later.ID},
+		DeleteReservations: []string{reservation.ID},
+	}
+	require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1003, req))
+
+	out, err = state.NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.Equal([]*structs.NodeMaintenanceWindow{started}, out.MaintenanceWindows)
+	require.Nil(out.CapacityReservations)
+
+	// Unknown nodes are an error
+	req.NodeIDs = []string{uuid.Generate()}
+	require.Error(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1004, req))
+}
+
+func TestStateStore_NodeClassMaintenance(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := testStateStore(t)
+	now := time.Now()
+	window := &structs.NodeMaintenanceWindow{ID: "w1", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Status: structs.NodeMaintenanceWindowPending}
+	reservation := &structs.NodeCapacityReservation{ID: "r1", Namespace: "default", JobID: "example", CPU: 500, Expires: now.Add(time.Hour)}
+
+	// Updating a class without nodes stores the update for the class
+	req := &structs.NodeMaintenanceRequest{
+		NodeClass:    "maintained",
+		Windows:      []*structs.NodeMaintenanceWindow{window},
+		Reservations: []*structs.NodeCapacityReservation{reservation},
+	}
+	require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1000, req))
+
+	maintenance, err := state.NodeClassMaintenanceByClass(nil, "maintained")
+	require.NoError(err)
+	require.Equal([]*structs.NodeMaintenanceWindow{window}, maintenance.MaintenanceWindows)
+	require.Equal([]*structs.NodeCapacityReservation{reservation}, maintenance.CapacityReservations)
+	index, err := state.Index(TableNodeClassMaintenance)
+	require.NoError(err)
+	require.EqualValues(1000, index)
+
+	// Nodes joining the class get its maintenance
+	node1, node2 := mock.Node(), mock.Node()
+	node1.NodeClass, node2.NodeClass = "maintained", "maintained"
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1001, node1))
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1002, node2))
+	for _, node := range []*structs.Node{node1, node2} {
+		out, err := state.NodeByID(nil, node.ID)
+		require.NoError(err)
+		require.Equal([]*structs.NodeMaintenanceWindow{window}, out.MaintenanceWindows)
+		require.Equal([]*structs.NodeCapacityReservation{reservation}, out.CapacityReservations)
+	}
+
+	// Start the window on the first node
+	started := window.Copy()
+	started.Status = structs.NodeMaintenanceWindowActive
+	require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1003, &structs.NodeMaintenanceRequest{
+		NodeIDs: []string{node1.ID},
+		Windows: []*structs.NodeMaintenanceWindow{started},
+	}))
+
+	// Nodes leaving the class lose its pending windows and its reservations
+	for i, node := range []*structs.Node{node1, node2} {
+		node = node.Copy()
+		node.NodeClass = "other"
+		require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, uint64(1004+i), node))
+	}
+	out, err := state.NodeByID(nil, node1.ID)
+	require.NoError(err)
+	require.Equal([]*structs.NodeMaintenanceWindow{started}, out.MaintenanceWindows)
+	require.Nil(out.CapacityReservations)
+	out, err = state.NodeByID(nil, node2.ID)
+	require.NoError(err)
+	require.Nil(out.MaintenanceWindows)
+	require.Nil(out.CapacityReservations)
+
+	// Rejoining the class doesn't restart a window that already started
+	node := out.Copy()
+	node.ID = node1.ID
+	node.NodeClass = "maintained"
+	require.NoError(state.UpsertNode(structs.MsgTypeTestSetup, 1006, node))
+	out, err = state.NodeByID(nil, node1.ID)
+	require.NoError(err)
+	require.Equal([]*structs.NodeMaintenanceWindow{started}, out.MaintenanceWindows)
+	require.Equal([]*structs.NodeCapacityReservation{reservation}, out.CapacityReservations)
+
+	// Deleting everything from the class deletes its maintenance
+	require.NoError(state.UpdateNodeMaintenance(structs.MsgTypeTestSetup, 1007, &structs.NodeMaintenanceRequest{
+		NodeClass:          "maintained",
+		DeleteWindows:      []string{window.ID},
+		DeleteReservations: []string{reservation.ID},
+	}))
+	maintenance, err = state.NodeClassMaintenanceByClass(nil, "maintained")
+	require.NoError(err)
+	require.Nil(maintenance)
+}
+
 func TestStateStore_Nodes(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2568,7 +2711,7 @@ func TestStateStore_JobsByIDPrefix_Namespaces(t *testing.T) {
 	job1.Namespace = ns1.Name
 	job2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, job1))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1001, job2))
 
@@ -2651,7 +2794,7 @@ func TestStateStore_JobsByNamespace(t *testing.T) {
 	job3.Namespace = ns2.Name
 	job4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -3240,18 +3383,18 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	}}
 
 	index++
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v0, v1})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0, v1})
 	require.NoError(t, err)
 
 	// volume registration is idempotent, unless identies are changed
 	index++
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v0, v1})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0, v1})
 	require.NoError(t, err)
 
 	index++
 	v2 := v0.Copy()
 	v2.PluginID = "new-id"
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v2})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v2})
 	require.Error(t, err, fmt.Sprintf("volume exists: %s", v0.ID))
 
 	ws := memdb.NewWatchSet()
@@ -3308,10 +3451,10 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	}
 
 	index++
-	err = state.CSIVolumeClaim(index, ns, vol0, claim0)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim0)
 	require.NoError(t, err)
 	index++
-	err = state.CSIVolumeClaim(index, ns, vol0, claim1)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim1)
 	require.NoError(t, err)
 
 	ws = memdb.NewWatchSet()
@@ -3321,7 +3464,7 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	require.False(t, vs[0].HasFreeWriteClaims())
 
 	claim0.Mode = u
-	err = state.CSIVolumeClaim(2, ns, vol0, claim0)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, 2, ns, vol0, claim0)
 	require.NoError(t, err)
 	ws = memdb.NewWatchSet()
 	iter, err = state.CSIVolumesByPluginID(ws, ns, "", "minnie")
@@ -3329,38 +3472,46 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	vs = slurp(iter)
 	require.True(t, vs[0].ReadSchedulable())
 
-	// registration is an error when the volume is in use
+	// registration is an error when the volume is in use, unless only
+	// the capacity is updated
 	index++
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{v0})
+	v0Expanded := v0.Copy()
+	v0Expanded.RequestedCapacityMin = 10 * 1024 * 1024
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0Expanded})
+	require.NoError(t, err, "volume expanded while in use")
+	index++
+	v0Changed := v0.Copy()
+	v0Changed.Name = "bar"
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{v0Changed})
 	require.Error(t, err, "volume re-registered while in use")
 	// as is deregistration
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{vol0}, false)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{vol0}, false)
 	require.Error(t, err, "volume deregistered while in use")
 
 	// even if forced, because we have a non-terminal claim
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{vol0}, true)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{vol0}, true)
 	require.Error(t, err, "volume force deregistered while in use")
 
 	// we use the ID, not a prefix
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{"fo"}, true)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{"fo"}, true)
 	require.Error(t, err, "volume deregistered by prefix")
 
 	// release claims to unblock deregister
 	index++
 	claim0.State = structs.CSIVolumeClaimStateReadyToFree
-	err = state.CSIVolumeClaim(index, ns, vol0, claim0)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim0)
 	require.NoError(t, err)
 	index++
 	claim1.Mode = u
 	claim1.State = structs.CSIVolumeClaimStateReadyToFree
-	err = state.CSIVolumeClaim(index, ns, vol0, claim1)
+	err = state.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol0, claim1)
 	require.NoError(t, err)
 
 	index++
-	err = state.CSIVolumeDeregister(index, ns, []string{vol0}, false)
+	err = state.CSIVolumeDeregister(structs.MsgTypeTestSetup, index, ns, []string{vol0}, false)
 	require.NoError(t, err)
 
 	// List, now omitting the deregistered volume
@@ -3377,6 +3528,101 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	require.Equal(t, 1, len(vs))
 }
 
+func TestStateStore_UpdateCSIVolumeStats(t *testing.T) {
+	ci.Parallel(t)
+
+	store := testStateStore(t)
+	index := uint64(1000)
+	ns := structs.DefaultNamespace
+
+	node := mock.Node()
+	node.CSINodePlugins = map[string]*structs.CSIInfo{
+		"minnie": {
+			PluginID: "minnie",
+			Healthy:  true,
+			NodeInfo: &structs.CSINodeInfo{ID: node.ID},
+		},
+	}
+	index++
+	require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, index, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	index++
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index, []*structs.Allocation{alloc}))
+
+	vol := structs.NewCSIVolume("foo", index)
+	vol.ID = uuid.Generate()
+	vol.Namespace = ns
+	vol.PluginID = "minnie"
+	vol.AccessMode = structs.CSIVolumeAccessModeMultiNodeMultiWriter
+	vol.AttachmentMode = structs.CSIVolumeAttachmentModeFilesystem
+	vol.RequestedCapabilities = []*structs.CSIVolumeCapability{{
+		AccessMode:     structs.CSIVolumeAccessModeMultiNodeMultiWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+	}}
+	index++
+	require.NoError(t, store.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol}))
+
+	stats := []*structs.CSIVolumeStats{{
+		VolumeID:   vol.ID,
+		Namespace:  ns,
+		PluginID:   "minnie",
+		TotalBytes: 1024,
+		UsedBytes:  1000,
+		Abnormal:   true,
+		Message:    "nearly full",
+	}, {
+		VolumeID:  "does-not-exist",
+		Namespace: ns,
+	}}
+
+	// stats are ignored while the volume isn't claimed on the node
+	index++
+	require.NoError(t, store.UpdateCSIVolumeStats(structs.MsgTypeTestSetup, index, node.ID, stats))
+	got, err := store.CSIVolumeByID(nil, ns, vol.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.Stats)
+
+	claim := &structs.CSIVolumeClaim{
+		AllocationID: alloc.ID,
+		NodeID:       node.ID,
+		Mode:         structs.CSIVolumeClaimWrite,
+		State:        structs.CSIVolumeClaimStateTaken,
+	}
+	index++
+	require.NoError(t, store.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol.ID, claim))
+
+	index++
+	require.NoError(t, store.UpdateCSIVolumeStats(structs.MsgTypeTestSetup, index, node.ID, stats))
+	got, err = store.CSIVolumeByID(nil, ns, vol.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Stats, 1)
+	require.Equal(t, node.ID, got.Stats[node.ID].NodeID)
+	require.Equal(t, int64(1000), got.Stats[node.ID].UsedBytes)
+	require.True(t, got.Abnormal())
+	require.Equal(t, index, got.ModifyIndex)
+
+	// stats are preserved when the volume is updated
+	index++
+	update := got.Copy()
+	update.Stats = nil
+	update.RequestedCapacityMin = 2048
+	require.NoError(t, store.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{update}))
+	got, err = store.CSIVolumeByID(nil, ns, vol.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Stats, 1)
+
+	// stats are removed when the last claim on the node is released
+	claim.State = structs.CSIVolumeClaimStateReadyToFree
+	index++
+	require.NoError(t, store.CSIVolumeClaim(structs.MsgTypeTestSetup, index, ns, vol.ID, claim))
+	got, err = store.CSIVolumeByID(nil, ns, vol.ID)
+	require.NoError(t, err)
+	require.Empty(t, got.Stats)
+	require.False(t, got.Abnormal())
+}
+
 func TestStateStore_CSIPlugin_Lifecycle(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3671,7 +3917,7 @@ func TestStateStore_CSIPlugin_Lifecycle(t *testing.T) {
 			Namespace: structs.DefaultNamespace,
 			PluginID:  plugID,
 		}
-		err = store.UpsertCSIVolume(nextIndex(store), []*structs.CSIVolume{vol})
+		err = store.UpsertCSIVolume(structs.MsgTypeTestSetup, nextIndex(store), []*structs.CSIVolume{vol})
 		require.NoError(t, err)
 
 		err = store.DeleteJob(nextIndex(store), structs.DefaultNamespace, controllerJobID)
@@ -3957,7 +4203,7 @@ func TestStateStore_UpsertEvals_Namespace(t *testing.T) {
 	eval3.Namespace = ns2.Name
 	eval4.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 
 	// Create watchsets so we can test that update fires the watch
 	watches := []memdb.WatchSet{memdb.NewWatchSet(), memdb.NewWatchSet()}
@@ -4564,7 +4810,7 @@ func TestStateStore_EvalsByIDPrefix_Namespaces(t *testing.T) {
 	eval1.Namespace = ns1.Name
 	eval2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval1, eval2}))
 
 	gatherEvals := func(iter memdb.ResultIterator) []*structs.Evaluation {
@@ -5249,7 +5495,7 @@ func TestStateStore_UpsertAlloc_AllocsByNamespace(t *testing.T) {
 	alloc4.Namespace = ns2.Name
 	alloc4.Job.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, alloc1.Job))
 	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, alloc3.Job))
 
@@ -6333,7 +6579,7 @@ func TestStateStore_AllocsByIDPrefix_Namespaces(t *testing.T) {
 	alloc1.Namespace = ns1.Name
 	alloc2.Namespace = ns2.Name
 
-	require.NoError(t, state.UpsertNamespaces(998, []*structs.Namespace{ns1, ns2}))
+	require.NoError(t, state.UpsertNamespaces(structs.MsgTypeTestSetup, 998, []*structs.Namespace{ns1, ns2}))
 	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc1, alloc2}))
 
 	gatherAllocs := func(iter memdb.ResultIterator) []*structs.Allocation {
@@ -7533,6 +7779,91 @@ func TestStateStore_UpsertDeploymentPromotion_Subset(t *testing.T) {
 	require.True(aout3.DeploymentStatus.Canary)
 }
 
+// Test promoting the rollout steps of a deployment
+func TestStateStore_UpsertDeploymentPromotion_Step(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	state := testStateStore(t)
+
+	// Create a job with two task groups
+	j := mock.Job()
+	tg1 := j.TaskGroups[0]
+	tg2 := tg1.Copy()
+	tg2.Name = "foo"
+	j.TaskGroups = append(j.TaskGroups, tg2)
+	require.Nil(state.UpsertJob(structs.MsgTypeTestSetup, 1, j))
+
+	// Create a deployment whose web group completed its first rollout step
+	// and whose foo group has canaries to promote
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.TaskGroups = map[string]*structs.DeploymentState{
+		"web": {
+			DesiredTotal:    8,
+			DesiredCanaries: 1,
+			Promoted:        true,
+			HealthyAllocs:   2,
+			RolloutSteps: []*structs.RolloutStep{
+				{Percent: 25},
+				{Percent: 50, Manual: true},
+				{Percent: 100},
+			},
+		},
+		"foo": {
+			DesiredTotal:    4,
+			DesiredCanaries: 1,
+		},
+	}
+	require.Nil(state.UpsertDeployment(2, d))
+
+	c := mock.Alloc()
+	c.JobID = j.ID
+	c.DeploymentID = d.ID
+	c.TaskGroup = tg2.Name
+	d.TaskGroups[tg2.Name].PlacedCanaries = []string{c.ID}
+	c.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy: helper.BoolToPtr(true),
+		Canary:  true,
+	}
+	require.Nil(state.UpsertAllocs(structs.MsgTypeTestSetup, 3, []*structs.Allocation{c}))
+
+	// Promote the rollout step of web and the canaries of foo
+	req := &structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+			Step:         true,
+		},
+		Eval: mock.Eval(),
+	}
+	require.Nil(state.UpdateDeploymentPromotion(structs.MsgTypeTestSetup, 4, req))
+
+	ws := memdb.NewWatchSet()
+	dout, err := state.DeploymentByID(ws, d.ID)
+	require.Nil(err)
+	require.Equal(1, dout.TaskGroups["web"].CurrentStep)
+	require.True(dout.TaskGroups["foo"].Promoted)
+	require.Zero(dout.TaskGroups["foo"].CurrentStep)
+
+	aout, err := state.AllocByID(ws, c.ID)
+	require.Nil(err)
+	require.False(aout.DeploymentStatus.Canary)
+
+	// The next step of web isn't complete
+	req.Eval = mock.Eval()
+	err = state.UpdateDeploymentPromotion(structs.MsgTypeTestSetup, 5, req)
+	require.Error(err)
+	require.Contains(err.Error(), `Task group "web" has 2/4 healthy allocations for rollout step 2`)
+
+	// The foo group has no rollout step
+	req.All = false
+	req.Groups = []string{"foo"}
+	err = state.UpdateDeploymentPromotion(structs.MsgTypeTestSetup, 5, req)
+	require.Error(err)
+	require.Contains(err.Error(), `Task group "foo" has no rollout step to promote`)
+}
+
 // Test that allocation health can't be set against a nonexistent deployment
 func TestStateStore_UpsertDeploymentAllocHealth_Nonexistent(t *testing.T) {
 	ci.Parallel(t)
@@ -25,6 +25,11 @@ const (
 type EventBrokerCfg struct {
 	EventBufferSize int64
 	Logger          hclog.Logger
+
+	// Journal is an optional on-disk journal that published events are
+	// written to, allowing subscribers to resume from indexes no longer
+	// held by the event buffer. The journal is not closed by the broker.
+	Journal *EventJournal
 }
 
 type EventBroker struct {
@@ -35,6 +40,14 @@ type EventBroker struct {
 	// eventBuf stores a configurable amount of events in memory
 	eventBuf *eventBuffer
 
+	// journal stores events on disk, and may be nil
+	journal *EventJournal
+
+	// appendLock serializes appending events to the journal and event
+	// buffer with subscriptions switching from replaying the journal to
+	// reading the buffer, so that no events are missed in between.
+	appendLock sync.Mutex
+
 	// publishCh is used to send messages from an active txn to a goroutine which
 	// publishes events, so that publishing can happen asynchronously from
 	// the Commit call in the FSM hot path.
@@ -71,6 +84,7 @@ func NewEventBroker(ctx context.Context, aclDelegate ACLDelegate, cfg EventBroke
 	e := &EventBroker{
 		logger:      cfg.Logger.Named("event_broker"),
 		eventBuf:    buffer,
+		journal:     cfg.Journal,
 		publishCh:   make(chan *structs.Events, 64),
 		aclCh:       make(chan *structs.Event, 10),
 		aclDelegate: aclDelegate,
@@ -133,12 +147,23 @@ func (e *EventBroker) SubscribeWithACLCheck(req *SubscribeRequest) (*Subscriptio
 // set and the index is no longer in the buffer or not yet in the buffer an error
 // will be returned.
 //
+// If the broker has an event journal, a subscription requesting an index is
+// first sent the events held by the journal, and then continues with the
+// buffer.
+//
+// A ResubscribeError is returned if events following the requested index
+// have been dropped from the buffer, or truncated from the journal.
+//
 // When a caller is finished with the subscription it must call Subscription.Unsubscribe
 // to free ACL tracking resources.
 func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.journal != nil && req.Index != 0 {
+		return e.subscribeFromJournal(req)
+	}
+
 	var head *bufferItem
 	var offset int
 	if req.Index != 0 {
@@ -148,6 +173,8 @@ func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
 	}
 	if offset > 0 && req.StartExactlyAtIndex {
 		return nil, fmt.Errorf("requested index not in buffer")
+	} else if offset > 0 && req.Index < head.Events.Index && req.Index <= e.eventBuf.TruncatedIndex() {
+		return nil, &ResubscribeError{Index: req.Index, TruncatedIndex: e.eventBuf.TruncatedIndex()}
 	} else if offset > 0 {
 		metrics.SetGauge([]string{"nomad", "event_broker", "subscription", "request_offset"}, float32(offset))
 		e.logger.Debug("requested index no longer in buffer", "requsted", int(req.Index), "closest", int(head.Events.Index))
@@ -164,6 +191,40 @@ func (e *EventBroker) Subscribe(req *SubscribeRequest) (*Subscription, error) {
 	return sub, nil
 }
 
+// subscribeFromJournal returns a subscription which replays the events held
+// by the journal from the requested index before reading from the buffer.
+func (e *EventBroker) subscribeFromJournal(req *SubscribeRequest) (*Subscription, error) {
+	if truncated := e.journal.TruncatedIndex(); req.Index <= truncated {
+		return nil, &ResubscribeError{Index: req.Index, TruncatedIndex: truncated}
+	}
+
+	replay := &journalReplay{
+		journal: e.journal,
+		index:   req.Index,
+		join:    e.joinBuffer,
+	}
+	sub := newSubscription(req, nil, e.subscriptions.unsubscribeFn(req))
+	sub.replay = replay
+
+	e.subscriptions.add(req, sub)
+	return sub, nil
+}
+
+// joinBuffer returns the tail of the buffer, for a subscription which has
+// replayed the journal up to but excluding the given index to continue
+// from. If events at or after the index have since been written to the
+// journal, false is returned and the subscription must continue replaying
+// the journal.
+func (e *EventBroker) joinBuffer(index uint64) (*bufferItem, bool) {
+	e.appendLock.Lock()
+	defer e.appendLock.Unlock()
+
+	if e.journal.LastIndex() >= index {
+		return nil, false
+	}
+	return e.eventBuf.Tail(), true
+}
+
 // CloseAll closes all subscriptions
 func (e *EventBroker) CloseAll() {
 	e.subscriptions.closeAll()
@@ -176,11 +237,40 @@ func (e *EventBroker) handleUpdates(ctx context.Context) {
 			e.subscriptions.closeAll()
 			return
 		case update := <-e.publishCh:
-			e.eventBuf.Append(update)
+			// Take the updates published in the meantime as well, so they
+			// are written to the journal in a single transaction
+			batch := []*structs.Events{update}
+		DRAIN:
+			for len(batch) < cap(e.publishCh) {
+				select {
+				case update := <-e.publishCh:
+					batch = append(batch, update)
+				default:
+					break DRAIN
+				}
+			}
+			e.appendEvents(batch)
 		}
 	}
 }
 
+// appendEvents writes the batch of events to the journal, if there is one,
+// and then appends them to the buffer.
+func (e *EventBroker) appendEvents(batch []*structs.Events) {
+	e.appendLock.Lock()
+	defer e.appendLock.Unlock()
+
+	if e.journal != nil {
+		if err := e.journal.Append(batch...); err != nil {
+			e.logger.Error("failed to write events to journal",
+				"first_index", batch[0].Index, "last_index", batch[len(batch)-1].Index, "error", err)
+		}
+	}
+	for _, events := range batch {
+		e.eventBuf.Append(events)
+	}
+}
+
 func (e *EventBroker) handleACLUpdates(ctx context.Context) {
 	for {
 		select {
@@ -308,6 +398,25 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 			if ok := aclObj.AllowNodeRead(); !ok {
 				return false
 			}
+		case structs.TopicCSIVolume:
+			if ok := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityCSIReadVolume); !ok {
+				return false
+			}
+		case structs.TopicCSIPlugin:
+			if ok := aclObj.AllowPluginRead(); !ok {
+				return false
+			}
+		case structs.TopicNamespace:
+			if ok := aclObj.AllowNamespace(subReq.Namespace); !ok {
+				return false
+			}
+		case structs.TopicScalingPolicy:
+			hasReadScalingPolicy := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadScalingPolicy)
+			hasListAndReadJobs := aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityListJobs) &&
+				aclObj.AllowNsOp(subReq.Namespace, acl.NamespaceCapabilityReadJob)
+			if !(hasReadScalingPolicy || hasListAndReadJobs) {
+				return false
+			}
 		default:
 			if ok := aclObj.IsManagement(); !ok {
 				return false
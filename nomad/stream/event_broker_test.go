@@ -2,6 +2,8 @@ package stream
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -281,6 +283,86 @@ func TestEventBroker_handleACLUpdates_policyupdated(t *testing.T) {
 				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
 			},
 		},
+		{
+			desc:              "subscribed to csi volumes and removed access",
+			policyBeforeRules: mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityCSIReadVolume}),
+			policyAfterRules:  mock.NamespacePolicy(structs.DefaultNamespace, "", []string{}),
+			shouldUnsubscribe: true,
+			event: structs.Event{
+				Topic: structs.TopicCSIVolume,
+				Type:  structs.TypeCSIVolumeRegistered,
+				Payload: structs.CSIVolumeEvent{
+					Volume: &structs.CSIVolume{
+						ID: "some-id",
+					},
+				},
+			},
+			policyEvent: structs.Event{
+				Topic:   structs.TopicACLToken,
+				Type:    structs.TypeACLTokenUpserted,
+				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
+			},
+		},
+		{
+			desc:              "subscribed to csi plugins and removed access",
+			policyBeforeRules: mock.PluginPolicy(acl.PolicyRead),
+			policyAfterRules:  mock.PluginPolicy(acl.PolicyDeny),
+			shouldUnsubscribe: true,
+			event: structs.Event{
+				Topic: structs.TopicCSIPlugin,
+				Type:  structs.TypeNodeRegistration,
+				Payload: structs.CSIPluginEvent{
+					Plugin: &structs.CSIPlugin{
+						ID: "some-id",
+					},
+				},
+			},
+			policyEvent: structs.Event{
+				Topic:   structs.TopicACLToken,
+				Type:    structs.TypeACLTokenUpserted,
+				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
+			},
+		},
+		{
+			desc:              "subscribed to namespaces and removed access",
+			policyBeforeRules: mock.NamespacePolicy(structs.DefaultNamespace, acl.PolicyRead, nil),
+			policyAfterRules:  mock.NamespacePolicy(structs.DefaultNamespace, acl.PolicyDeny, nil),
+			shouldUnsubscribe: true,
+			event: structs.Event{
+				Topic: structs.TopicNamespace,
+				Type:  structs.TypeNamespaceUpserted,
+				Payload: structs.NamespaceEvent{
+					Namespace: &structs.Namespace{
+						Name: structs.DefaultNamespace,
+					},
+				},
+			},
+			policyEvent: structs.Event{
+				Topic:   structs.TopicACLToken,
+				Type:    structs.TypeACLTokenUpserted,
+				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
+			},
+		},
+		{
+			desc:              "subscribed to scaling policies and removed access",
+			policyBeforeRules: mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadScalingPolicy}),
+			policyAfterRules:  mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}),
+			shouldUnsubscribe: true,
+			event: structs.Event{
+				Topic: structs.TopicScalingPolicy,
+				Type:  structs.TypeJobRegistered,
+				Payload: structs.ScalingPolicyEvent{
+					ScalingPolicy: &structs.ScalingPolicy{
+						ID: "some-id",
+					},
+				},
+			},
+			policyEvent: structs.Event{
+				Topic:   structs.TopicACLToken,
+				Type:    structs.TypeACLTokenUpserted,
+				Payload: structs.NewACLTokenEvent(&structs.ACLToken{SecretID: secretID}),
+			},
+		},
 		{
 			desc:              "subscribed to evals in all namespaces and removed access",
 			policyBeforeRules: mock.NamespacePolicy("*", "", []string{acl.NamespaceCapabilityReadJob}),
@@ -578,6 +660,95 @@ func TestEventBroker_handleACLUpdates_policyupdated(t *testing.T) {
 	}
 }
 
+func TestEventBroker_Subscribe_ResubscribeRequired(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	publisher, err := NewEventBroker(ctx, nil, EventBrokerCfg{EventBufferSize: 2})
+	require.NoError(t, err)
+
+	for i := uint64(1); i <= 5; i++ {
+		publisher.Publish(&structs.Events{Index: i, Events: []structs.Event{{Topic: "Test", Index: i}}})
+	}
+	require.Eventually(t, func() bool {
+		return publisher.eventBuf.Tail().Events.Index == 5
+	}, time.Second, 10*time.Millisecond)
+
+	// The events following the index have been dropped from the buffer
+	_, err = publisher.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{"*": {"*"}},
+		Index:  1,
+	})
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrResubscribeRequired))
+
+	// Subscribing from the current state is unaffected
+	sub, err := publisher.Subscribe(&SubscribeRequest{
+		Topics: map[structs.Topic][]string{"*": {"*"}},
+	})
+	require.NoError(t, err)
+	sub.Unsubscribe()
+}
+
+func TestEventBroker_SubscribeFromJournal(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	journal := testJournal(t, filepath.Join(t.TempDir(), "journal.db"), 4)
+	publisher, err := NewEventBroker(ctx, nil, EventBrokerCfg{
+		EventBufferSize: 2,
+		Journal:         journal,
+	})
+	require.NoError(t, err)
+
+	publish := func(index uint64) {
+		publisher.Publish(&structs.Events{Index: index, Events: []structs.Event{{
+			Topic: "Test",
+			Key:   "sub-key",
+			Index: index,
+		}}})
+	}
+	for i := uint64(1); i <= 5; i++ {
+		publish(i)
+	}
+	require.Eventually(t, func() bool {
+		return journal.LastIndex() == 5
+	}, time.Second, 10*time.Millisecond)
+
+	req := &SubscribeRequest{
+		Topics: map[structs.Topic][]string{"Test": {"sub-key"}},
+		Index:  2,
+	}
+	sub, err := publisher.Subscribe(req)
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	eventCh := consumeSubscription(ctx, sub)
+
+	// The events no longer in the buffer are replayed from the journal,
+	// followed by the events published after subscribing without any
+	// duplicates.
+	publish(6)
+	for i := uint64(2); i <= 6; i++ {
+		result := nextResult(t, eventCh)
+		require.NoError(t, result.Err)
+		require.Len(t, result.Events, 1)
+		require.Equal(t, i, result.Events[0].Index)
+	}
+	assertNoResult(t, eventCh)
+
+	// Events truncated from the journal require resubscribing
+	req.Index = 1
+	_, err = publisher.Subscribe(req)
+	require.Error(t, err)
+	var resubErr *ResubscribeError
+	require.True(t, errors.As(err, &resubErr))
+	require.Equal(t, uint64(2), resubErr.TruncatedIndex)
+}
+
 func consumeSubscription(ctx context.Context, sub *Subscription) <-chan subNextResult {
 	eventCh := make(chan subNextResult, 1)
 	go func() {
@@ -49,6 +49,10 @@ type eventBuffer struct {
 	tail atomic.Value
 
 	maxSize int64
+
+	// truncatedIndex is the index of the most recent events dropped from
+	// the buffer. It must be accessed atomically.
+	truncatedIndex uint64
 }
 
 // newEventBuffer creates an eventBuffer ready for use.
@@ -115,6 +119,9 @@ func (b *eventBuffer) advanceHead() {
 
 	// notify readers that old is being dropped
 	close(old.link.droppedCh)
+	if old.Events != nil && old.Events.Index > atomic.LoadUint64(&b.truncatedIndex) {
+		atomic.StoreUint64(&b.truncatedIndex, old.Events.Index)
+	}
 
 	// store the next value to head
 	b.head.Store(next)
@@ -178,6 +185,12 @@ func (b *eventBuffer) StartAtClosest(index uint64) (*bufferItem, int) {
 	}
 }
 
+// TruncatedIndex returns the index of the most recent events dropped from the
+// buffer, or zero if no events have been dropped.
+func (b *eventBuffer) TruncatedIndex() uint64 {
+	return atomic.LoadUint64(&b.truncatedIndex)
+}
+
 // Len returns the current length of the buffer
 func (b *eventBuffer) Len() int {
 	return int(atomic.LoadInt64(b.size))
@@ -0,0 +1,383 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-msgpack/codec"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// journalReadLimit is the maximum number of raft indexes read from the
+	// journal at a time when replaying events to a subscriber.
+	journalReadLimit = 64
+)
+
+var (
+	// journalEventsBucket holds the journaled events keyed by raft index.
+	journalEventsBucket = []byte("events")
+
+	// journalMetaBucket holds metadata about the journal.
+	journalMetaBucket = []byte("meta")
+
+	// journalTruncatedIndexKey is the key within the meta bucket holding the
+	// highest raft index for which events may have been removed from, or
+	// were never written to, the journal.
+	journalTruncatedIndexKey = []byte("truncated_index")
+)
+
+// EventJournal is an on-disk log of the events published by an EventBroker.
+// It allows subscribers to resume from an index which is no longer held by
+// the in-memory event buffer, including after a server restart or a leader
+// election. The journal retains the events for a fixed number of raft
+// indexes; the oldest are truncated as new events are appended.
+//
+// A single journal is shared by every EventBroker a server creates, so it is
+// owned by the server and must be closed by it.
+type EventJournal struct {
+	db     *bbolt.DB
+	logger hclog.Logger
+
+	// maxSize is the number of raft indexes to retain events for.
+	maxSize int64
+
+	// l protects the fields below
+	l sync.Mutex
+
+	// size is the number of raft indexes currently held by the journal.
+	size int64
+
+	// lastIndex is the highest raft index held by the journal.
+	lastIndex uint64
+
+	// truncatedIndex is the highest raft index for which events may be
+	// missing from the journal. Subscribers requesting an index at or below
+	// it must resubscribe.
+	truncatedIndex uint64
+}
+
+// journalEntry is the set of events published for a single raft index, as
+// stored by the journal.
+type journalEntry struct {
+	Index  uint64
+	Events []journalEvent
+}
+
+// journalEvent is an event as stored by the journal. The payload is stored
+// JSON encoded, as events read from the journal are only ever sent to event
+// stream subscribers.
+type journalEvent struct {
+	Topic      structs.Topic
+	Type       string
+	Key        string
+	Namespace  string
+	FilterKeys []string
+	Payload    []byte
+}
+
+// journalPayload is a JSON encoded event payload read from the journal. It
+// is written to event streams verbatim.
+type journalPayload struct {
+	data []byte
+}
+
+func (p *journalPayload) MarshalJSON() ([]byte, error) {
+	return p.data, nil
+}
+
+func (p *journalPayload) UnmarshalJSON(data []byte) error {
+	p.data = append(p.data[:0], data...)
+	return nil
+}
+
+// NewEventJournal opens, or creates, the event journal at the given path.
+// Events are retained for up to maxSize raft indexes.
+func NewEventJournal(path string, maxSize int64, logger hclog.Logger) (*EventJournal, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("event journal size must be greater than zero")
+	}
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create event journal directory: %v", err)
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event journal: %v", err)
+	}
+
+	j := &EventJournal{
+		db:      db,
+		logger:  logger.Named("event_journal"),
+		maxSize: maxSize,
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		events, err := tx.CreateBucketIfNotExists(journalEventsBucket)
+		if err != nil {
+			return err
+		}
+		meta, err := tx.CreateBucketIfNotExists(journalMetaBucket)
+		if err != nil {
+			return err
+		}
+
+		if raw := meta.Get(journalTruncatedIndexKey); raw != nil {
+			j.truncatedIndex = decodeJournalIndex(raw)
+		}
+		if last, _ := events.Cursor().Last(); last != nil {
+			j.lastIndex = decodeJournalIndex(last)
+		}
+		j.size = int64(events.Stats().KeyN)
+
+		// The retention may have been lowered since the journal was last
+		// opened.
+		return j.truncateTxn(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize event journal: %v", err)
+	}
+
+	return j, nil
+}
+
+// Close closes the journal. Events can no longer be appended or read once
+// it has been closed.
+func (j *EventJournal) Close() error {
+	return j.db.Close()
+}
+
+// LastIndex returns the highest raft index held by the journal.
+func (j *EventJournal) LastIndex() uint64 {
+	j.l.Lock()
+	defer j.l.Unlock()
+	return j.lastIndex
+}
+
+// TruncatedIndex returns the highest raft index for which events may be
+// missing from the journal.
+func (j *EventJournal) TruncatedIndex() uint64 {
+	j.l.Lock()
+	defer j.l.Unlock()
+	return j.truncatedIndex
+}
+
+// Append writes the events for one or more raft indexes to the journal in a
+// single transaction, and truncates the oldest events if the journal exceeds
+// its maximum size. If the events for an index can't be written, the index is
+// marked as truncated so subscribers resuming from before it are told to
+// resubscribe instead of silently missing the events.
+func (j *EventJournal) Append(batch ...*structs.Events) error {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	var mErr multierror.Error
+	type encodedEntry struct {
+		index uint64
+		data  []byte
+	}
+	entries := make([]encodedEntry, 0, len(batch))
+	for _, events := range batch {
+		data, err := encodeJournalEntry(events)
+		if err != nil {
+			j.markTruncated(events.Index)
+			multierror.Append(&mErr, fmt.Errorf("failed to encode events at index %d: %v", events.Index, err))
+			continue
+		}
+		entries = append(entries, encodedEntry{index: events.Index, data: data})
+	}
+	if len(entries) == 0 {
+		return mErr.ErrorOrNil()
+	}
+
+	size, truncated := j.size, j.truncatedIndex
+	err := j.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(journalEventsBucket)
+		for _, entry := range entries {
+			key := encodeJournalIndex(entry.index)
+
+			// Events may be published more than once for the same index
+			// when the raft log is replayed on startup.
+			if b.Get(key) == nil {
+				j.size++
+			}
+			if err := b.Put(key, entry.data); err != nil {
+				return err
+			}
+		}
+		return j.truncateTxn(tx)
+	})
+	lastIndex := entries[len(entries)-1].index
+	if err != nil {
+		j.size, j.truncatedIndex = size, truncated
+		j.markTruncated(lastIndex)
+		multierror.Append(&mErr, fmt.Errorf("failed to write events to journal: %v", err))
+		return mErr.ErrorOrNil()
+	}
+
+	if lastIndex > j.lastIndex {
+		j.lastIndex = lastIndex
+	}
+	return mErr.ErrorOrNil()
+}
+
+// encodeJournalEntry encodes the events for a single raft index as stored by
+// the journal.
+func encodeJournalEntry(events *structs.Events) ([]byte, error) {
+	entry := journalEntry{
+		Index:  events.Index,
+		Events: make([]journalEvent, 0, len(events.Events)),
+	}
+	for _, e := range events.Events {
+		var payload bytes.Buffer
+		if err := codec.NewEncoder(&payload, structs.JsonHandleWithExtensions).Encode(e.Payload); err != nil {
+			return nil, fmt.Errorf("failed to encode event payload: %v", err)
+		}
+		entry.Events = append(entry.Events, journalEvent{
+			Topic:      e.Topic,
+			Type:       e.Type,
+			Key:        e.Key,
+			Namespace:  e.Namespace,
+			FilterKeys: e.FilterKeys,
+			Payload:    payload.Bytes(),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, structs.MsgpackHandle).Encode(&entry); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// truncateTxn removes the oldest events from the journal until it is within
+// its maximum size, recording the highest index removed. It must be called
+// with the lock held.
+func (j *EventJournal) truncateTxn(tx *bbolt.Tx) error {
+	if j.size <= j.maxSize {
+		return nil
+	}
+
+	b := tx.Bucket(journalEventsBucket)
+	c := b.Cursor()
+	var truncated uint64
+	// Deleting the current key moves the cursor, so seek to the first key
+	// again rather than advancing it.
+	for k, _ := c.First(); k != nil && j.size > j.maxSize; k, _ = c.First() {
+		truncated = decodeJournalIndex(k)
+		if err := c.Delete(); err != nil {
+			return err
+		}
+		j.size--
+	}
+
+	if truncated <= j.truncatedIndex {
+		return nil
+	}
+	j.truncatedIndex = truncated
+	return tx.Bucket(journalMetaBucket).Put(journalTruncatedIndexKey, encodeJournalIndex(truncated))
+}
+
+// markTruncated records in memory that the events for the given index are
+// missing from the journal. It must be called with the lock held.
+func (j *EventJournal) markTruncated(index uint64) {
+	if index > j.truncatedIndex {
+		j.truncatedIndex = index
+	}
+}
+
+// RestoredFromSnapshot must be called when the state store is restored from a
+// snapshot of the given index. Events are not published for the changes held
+// by a snapshot, so if the journal doesn't already hold events up to the
+// snapshot index, those events are marked as truncated.
+func (j *EventJournal) RestoredFromSnapshot(index uint64) error {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	if index <= j.lastIndex || index <= j.truncatedIndex {
+		return nil
+	}
+
+	err := j.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(journalMetaBucket).Put(journalTruncatedIndexKey, encodeJournalIndex(index))
+	})
+
+	// Mark the index as truncated regardless of the write succeeding, so
+	// that this server never silently skips the events.
+	j.truncatedIndex = index
+	return err
+}
+
+// Read returns the events held by the journal starting at the given index,
+// for at most limit raft indexes. A ResubscribeError is returned if events at
+// or following the index may have been truncated.
+func (j *EventJournal) Read(index uint64, limit int) ([]*structs.Events, error) {
+	if truncated := j.TruncatedIndex(); index <= truncated {
+		return nil, &ResubscribeError{Index: index, TruncatedIndex: truncated}
+	}
+
+	var out []*structs.Events
+	err := j.db.View(func(tx *bbolt.Tx) error {
+		// Truncation may have occurred since the check above; the meta
+		// bucket is consistent with the events bucket within the
+		// transaction.
+		if raw := tx.Bucket(journalMetaBucket).Get(journalTruncatedIndexKey); raw != nil {
+			if truncated := decodeJournalIndex(raw); index <= truncated {
+				return &ResubscribeError{Index: index, TruncatedIndex: truncated}
+			}
+		}
+
+		c := tx.Bucket(journalEventsBucket).Cursor()
+		for k, v := c.Seek(encodeJournalIndex(index)); k != nil && len(out) < limit; k, v = c.Next() {
+			var entry journalEntry
+			if err := codec.NewDecoderBytes(v, structs.MsgpackHandle).Decode(&entry); err != nil {
+				return fmt.Errorf("failed to decode journaled events at index %d: %v", decodeJournalIndex(k), err)
+			}
+
+			events := &structs.Events{
+				Index:  entry.Index,
+				Events: make([]structs.Event, 0, len(entry.Events)),
+			}
+			for _, e := range entry.Events {
+				events.Events = append(events.Events, structs.Event{
+					Topic:      e.Topic,
+					Type:       e.Type,
+					Key:        e.Key,
+					Namespace:  e.Namespace,
+					FilterKeys: e.FilterKeys,
+					Index:      entry.Index,
+					Payload:    &journalPayload{data: e.Payload},
+				})
+			}
+			out = append(out, events)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func encodeJournalIndex(index uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, index)
+	return buf
+}
+
+func decodeJournalIndex(buf []byte) uint64 {
+	return binary.BigEndian.Uint64(buf)
+}
@@ -0,0 +1,180 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testJournal(t *testing.T, path string, size int64) *EventJournal {
+	t.Helper()
+	journal, err := NewEventJournal(path, size, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { journal.Close() })
+	return journal
+}
+
+func testJournalEvents(index uint64) *structs.Events {
+	return &structs.Events{
+		Index: index,
+		Events: []structs.Event{{
+			Topic:      "Test",
+			Type:       "Updated",
+			Key:        fmt.Sprintf("key-%d", index),
+			Namespace:  "default",
+			FilterKeys: []string{"filter"},
+			Index:      index,
+			Payload:    map[string]interface{}{"Index": index},
+		}},
+	}
+}
+
+func TestEventJournal_AppendRead(t *testing.T) {
+	ci.Parallel(t)
+
+	journal := testJournal(t, filepath.Join(t.TempDir(), "journal.db"), 10)
+
+	for i := uint64(1); i <= 5; i++ {
+		require.NoError(t, journal.Append(testJournalEvents(i)))
+	}
+	require.Equal(t, uint64(5), journal.LastIndex())
+	require.Zero(t, journal.TruncatedIndex())
+
+	// Read from an index, limited in the number of indexes returned
+	out, err := journal.Read(2, 2)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, uint64(2), out[0].Index)
+	require.Equal(t, uint64(3), out[1].Index)
+
+	event := out[0].Events[0]
+	require.Equal(t, structs.Topic("Test"), event.Topic)
+	require.Equal(t, "Updated", event.Type)
+	require.Equal(t, "key-2", event.Key)
+	require.Equal(t, "default", event.Namespace)
+	require.Equal(t, []string{"filter"}, event.FilterKeys)
+	require.Equal(t, uint64(2), event.Index)
+
+	// The payload is written to event streams as it was published
+	payload, err := json.Marshal(event.Payload)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"Index":2}`, string(payload))
+
+	// Reading past the last index returns nothing
+	out, err = journal.Read(6, 10)
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestEventJournal_Truncate(t *testing.T) {
+	ci.Parallel(t)
+
+	journal := testJournal(t, filepath.Join(t.TempDir(), "journal.db"), 3)
+
+	for i := uint64(1); i <= 5; i++ {
+		require.NoError(t, journal.Append(testJournalEvents(i)))
+	}
+	require.Equal(t, uint64(2), journal.TruncatedIndex())
+
+	// Reading a truncated index requires resubscribing
+	_, err := journal.Read(2, 10)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, ErrResubscribeRequired))
+	var resubErr *ResubscribeError
+	require.True(t, errors.As(err, &resubErr))
+	require.Equal(t, uint64(2), resubErr.Index)
+	require.Equal(t, uint64(2), resubErr.TruncatedIndex)
+
+	out, err := journal.Read(3, 10)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+	require.Equal(t, uint64(3), out[0].Index)
+
+	// Appending an existing index doesn't truncate
+	require.NoError(t, journal.Append(testJournalEvents(5)))
+	require.Equal(t, uint64(2), journal.TruncatedIndex())
+}
+
+func TestEventJournal_AppendBatch(t *testing.T) {
+	ci.Parallel(t)
+
+	journal := testJournal(t, filepath.Join(t.TempDir(), "journal.db"), 3)
+
+	// A batch is written at once and truncated as a whole
+	batch := []*structs.Events{}
+	for i := uint64(1); i <= 5; i++ {
+		batch = append(batch, testJournalEvents(i))
+	}
+	require.NoError(t, journal.Append(batch...))
+	require.Equal(t, uint64(5), journal.LastIndex())
+	require.Equal(t, uint64(2), journal.TruncatedIndex())
+
+	out, err := journal.Read(3, 10)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+	require.Equal(t, uint64(3), out[0].Index)
+	require.Equal(t, uint64(5), out[2].Index)
+}
+
+func TestEventJournal_Reopen(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "journal.db")
+
+	journal, err := NewEventJournal(path, 5, nil)
+	require.NoError(t, err)
+	for i := uint64(1); i <= 6; i++ {
+		require.NoError(t, journal.Append(testJournalEvents(i)))
+	}
+	require.NoError(t, journal.Close())
+
+	// The events and truncation are retained across restarts
+	journal = testJournal(t, path, 5)
+	require.Equal(t, uint64(6), journal.LastIndex())
+	require.Equal(t, uint64(1), journal.TruncatedIndex())
+
+	out, err := journal.Read(2, 10)
+	require.NoError(t, err)
+	require.Len(t, out, 5)
+	require.NoError(t, journal.Close())
+
+	// Lowering the retention truncates the journal when opened
+	journal = testJournal(t, path, 2)
+	require.Equal(t, uint64(4), journal.TruncatedIndex())
+
+	out, err = journal.Read(5, 10)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+}
+
+func TestEventJournal_RestoredFromSnapshot(t *testing.T) {
+	ci.Parallel(t)
+
+	journal := testJournal(t, filepath.Join(t.TempDir(), "journal.db"), 10)
+
+	for i := uint64(1); i <= 3; i++ {
+		require.NoError(t, journal.Append(testJournalEvents(i)))
+	}
+
+	// A snapshot the journal already holds the events for is a no-op
+	require.NoError(t, journal.RestoredFromSnapshot(3))
+	require.Zero(t, journal.TruncatedIndex())
+
+	// A snapshot beyond the journal means the events in between are missing
+	require.NoError(t, journal.RestoredFromSnapshot(10))
+	require.Equal(t, uint64(10), journal.TruncatedIndex())
+
+	_, err := journal.Read(2, 10)
+	require.True(t, errors.Is(err, ErrResubscribeRequired))
+
+	require.NoError(t, journal.Append(testJournalEvents(11)))
+	out, err := journal.Read(11, 10)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+}
@@ -3,6 +3,7 @@ package stream
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync/atomic"
 
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -24,6 +25,32 @@ const (
 var ErrSubscriptionClosed = errors.New("subscription closed by server, client should resubscribe")
 var ErrACLInvalid = errors.New("Provided ACL token is invalid for requested topics")
 
+// ErrResubscribeRequired is a error signalling that events following the
+// requested index are no longer available. The client should resynchronise
+// its view of the cluster state, then re-Subscribe. It is matched by any
+// ResubscribeError using errors.Is.
+var ErrResubscribeRequired = errors.New("requested index is no longer available, client should resubscribe")
+
+// ResubscribeError is returned when a subscription can't be given every
+// event following the requested index, because the events have been dropped
+// from the event buffer or truncated from the event journal.
+type ResubscribeError struct {
+	// Index is the index the subscriber requested, or had reached.
+	Index uint64
+
+	// TruncatedIndex is the highest index for which events may be missing.
+	TruncatedIndex uint64
+}
+
+func (e *ResubscribeError) Error() string {
+	return fmt.Sprintf("events from index %d are no longer available, events up to index %d have been truncated: client should resubscribe",
+		e.Index, e.TruncatedIndex)
+}
+
+func (e *ResubscribeError) Is(target error) bool {
+	return target == ErrResubscribeRequired
+}
+
 type Subscription struct {
 	// state must be accessed atomically 0 means open, 1 means closed with reload
 	state uint32
@@ -34,6 +61,15 @@ type Subscription struct {
 	// is mutated by calls to Next.
 	currentItem *bufferItem
 
+	// replay is set when the subscription is replaying events from the event
+	// journal. currentItem is nil until the replay has caught up with the
+	// buffer.
+	replay *journalReplay
+
+	// minIndex is the lowest index of events to send from the buffer. Events
+	// at lower indexes have already been replayed from the event journal.
+	minIndex uint64
+
 	// forceClosed is closed when forceClose is called. It is used by
 	// EventBroker to cancel Next().
 	forceClosed chan struct{}
@@ -68,11 +104,76 @@ func newSubscription(req *SubscribeRequest, item *bufferItem, unsub func()) *Sub
 	}
 }
 
+// journalReplay tracks the progress of a subscription replaying events from
+// the event journal.
+type journalReplay struct {
+	journal *EventJournal
+
+	// index is the next index to read from the journal.
+	index uint64
+
+	// pending holds events read from the journal which have not yet been
+	// sent to the subscriber.
+	pending []*structs.Events
+
+	// join is called by the subscription once it has replayed the journal
+	// to switch to reading from the buffer. It returns false if events have
+	// been written to the journal since it was last read.
+	join func(index uint64) (*bufferItem, bool)
+}
+
+// nextFromJournal returns the next set of matching events from the journal.
+// Once the journal has been replayed, the subscription switches to reading
+// the buffer and an empty set of events is returned.
+func (s *Subscription) nextFromJournal() (structs.Events, error) {
+	for s.replay != nil {
+		if atomic.LoadUint32(&s.state) == subscriptionStateClosed {
+			return structs.Events{}, ErrSubscriptionClosed
+		}
+
+		if len(s.replay.pending) == 0 {
+			page, err := s.replay.journal.Read(s.replay.index, journalReadLimit)
+			if err != nil {
+				return structs.Events{}, err
+			}
+
+			if len(page) == 0 {
+				if item, ok := s.replay.join(s.replay.index); ok {
+					s.currentItem = item
+					s.minIndex = s.replay.index
+					s.replay = nil
+				}
+				continue
+			}
+			s.replay.pending = page
+		}
+
+		next := s.replay.pending[0]
+		s.replay.pending = s.replay.pending[1:]
+		s.replay.index = next.Index + 1
+
+		events := filter(s.req, next.Events)
+		if len(events) == 0 {
+			continue
+		}
+		return structs.Events{Index: next.Index, Events: events}, nil
+	}
+
+	return structs.Events{}, nil
+}
+
 func (s *Subscription) Next(ctx context.Context) (structs.Events, error) {
 	if atomic.LoadUint32(&s.state) == subscriptionStateClosed {
 		return structs.Events{}, ErrSubscriptionClosed
 	}
 
+	if s.replay != nil {
+		events, err := s.nextFromJournal()
+		if err != nil || len(events.Events) > 0 {
+			return events, err
+		}
+	}
+
 	for {
 		next, err := s.currentItem.Next(ctx, s.forceClosed)
 		switch {
@@ -83,6 +184,10 @@ func (s *Subscription) Next(ctx context.Context) (structs.Events, error) {
 		}
 		s.currentItem = next
 
+		if next.Events.Index < s.minIndex {
+			continue
+		}
+
 		events := filter(s.req, next.Events.Events)
 		if len(events) == 0 {
 			continue
@@ -96,6 +201,13 @@ func (s *Subscription) NextNoBlock() ([]structs.Event, error) {
 		return nil, ErrSubscriptionClosed
 	}
 
+	if s.replay != nil {
+		events, err := s.nextFromJournal()
+		if err != nil || len(events.Events) > 0 {
+			return events.Events, err
+		}
+	}
+
 	for {
 		next := s.currentItem.NextNoBlock()
 		if next == nil {
@@ -103,6 +215,10 @@ func (s *Subscription) NextNoBlock() ([]structs.Event, error) {
 		}
 		s.currentItem = next
 
+		if next.Events.Index < s.minIndex {
+			continue
+		}
+
 		events := filter(s.req, next.Events.Events)
 		if len(events) == 0 {
 			continue
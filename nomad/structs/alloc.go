@@ -34,25 +34,34 @@ type AllocServiceRegistrationsResponse struct {
 func (a *Allocation) ServiceProviderNamespace() string {
 	tg := a.Job.LookupTaskGroup(a.TaskGroup)
 
-	if len(tg.Services) > 0 {
-		switch tg.Services[0].Provider {
-		case ServiceProviderNomad:
-			return a.Job.Namespace
-		default:
-			return tg.Consul.GetNamespace()
-		}
+	switch a.ServiceProvider() {
+	case ServiceProviderNomad:
+		return a.Job.Namespace
+	default:
+		return tg.Consul.GetNamespace()
 	}
+}
 
-	if len(tg.Tasks) > 0 {
-		if len(tg.Tasks[0].Services) > 0 {
-			switch tg.Tasks[0].Services[0].Provider {
-			case ServiceProviderNomad:
-				return a.Job.Namespace
-			default:
-				return tg.Consul.GetNamespace()
-			}
-		}
+// ServiceProvider returns the provider used to register the allocations
+// services. In the event no services are found, the Consul provider is
+// returned which allows hooks to work as they did before native service
+// discovery was introduced.
+//
+// It currently assumes that all services within an allocation use the same
+// provider.
+func (a *Allocation) ServiceProvider() string {
+	tg := a.Job.LookupTaskGroup(a.TaskGroup)
+
+	var services []*Service
+
+	if len(tg.Services) > 0 {
+		services = tg.Services
+	} else if len(tg.Tasks) > 0 {
+		services = tg.Tasks[0].Services
 	}
 
-	return tg.Consul.GetNamespace()
+	if len(services) > 0 && services[0].Provider == ServiceProviderNomad {
+		return ServiceProviderNomad
+	}
+	return ServiceProviderConsul
 }
@@ -112,3 +112,61 @@ func Test_Allocation_ServiceProviderNamespace(t *testing.T) {
 		})
 	}
 }
+
+func Test_Allocation_ServiceProvider(t *testing.T) {
+	testCases := []struct {
+		inputAllocation *Allocation
+		expectedOutput  string
+		name            string
+	}{
+		{
+			inputAllocation: &Allocation{
+				Job: &Job{
+					TaskGroups: []*TaskGroup{{Name: "test-group"}},
+				},
+				TaskGroup: "test-group",
+			},
+			expectedOutput: ServiceProviderConsul,
+			name:           "no services",
+		},
+		{
+			inputAllocation: &Allocation{
+				Job: &Job{
+					TaskGroups: []*TaskGroup{
+						{
+							Name:     "test-group",
+							Services: []*Service{{Provider: ServiceProviderNomad}},
+						},
+					},
+				},
+				TaskGroup: "test-group",
+			},
+			expectedOutput: ServiceProviderNomad,
+			name:           "nomad task group service",
+		},
+		{
+			inputAllocation: &Allocation{
+				Job: &Job{
+					TaskGroups: []*TaskGroup{
+						{
+							Name: "test-group",
+							Tasks: []*Task{
+								{Services: []*Service{{Provider: ServiceProviderNomad}}},
+							},
+						},
+					},
+				},
+				TaskGroup: "test-group",
+			},
+			expectedOutput: ServiceProviderNomad,
+			name:           "nomad task service",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualOutput := tc.inputAllocation.ServiceProvider()
+			require.Equal(t, tc.expectedOutput, actualOutput)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// AdmissionControllerTypeWebhook is an admission controller which POSTs
+	// the job to an HTTP endpoint.
+	AdmissionControllerTypeWebhook = "webhook"
+
+	// AdmissionControllerTypeExec is an admission controller which runs a
+	// local executable with the job written to its stdin.
+	AdmissionControllerTypeExec = "exec"
+
+	// DefaultAdmissionControllerTimeout is the default time allowed for an
+	// admission controller to respond.
+	DefaultAdmissionControllerTimeout = 5 * time.Second
+)
+
+// AdmissionControllerConfig configures an external admission controller which
+// can reject or patch jobs before they are registered.
+type AdmissionControllerConfig struct {
+	// Name is a unique name given to the admission controller
+	Name string `hcl:",key"`
+
+	// Type is the admission controller type. (webhook, exec)
+	Type string `hcl:"type"`
+
+	// Address is the URL the job is sent to for webhook admission
+	// controllers.
+	Address string `hcl:"address"`
+
+	// Headers are additional HTTP headers sent to webhook admission
+	// controllers, such as authorization headers.
+	Headers map[string]string `hcl:"headers" json:"-"`
+
+	// Command and Args are the executable and its arguments run for exec
+	// admission controllers.
+	Command string   `hcl:"command"`
+	Args    []string `hcl:"args"`
+
+	// Timeout is the time allowed for the admission controller to respond.
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// FailOpen allows jobs to be admitted with a warning if the admission
+	// controller can't be reached or returns an invalid response. By
+	// default such jobs are rejected.
+	FailOpen bool `hcl:"fail_open"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// Validate returns an error if the admission controller is misconfigured.
+func (a *AdmissionControllerConfig) Validate() error {
+	var mErr multierror.Error
+	if a.Name == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing name"))
+	}
+
+	switch a.Type {
+	case AdmissionControllerTypeWebhook:
+		if a.Address == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("webhook admission controller requires an address"))
+		}
+	case AdmissionControllerTypeExec:
+		if a.Command == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("exec admission controller requires a command"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("unknown admission controller type %q", a.Type))
+	}
+
+	if a.Timeout < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("timeout must be non-negative"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a new copy of an AdmissionControllerConfig
+func (a *AdmissionControllerConfig) Copy() *AdmissionControllerConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(AdmissionControllerConfig)
+	*nc = *a
+	nc.Headers = helper.CopyMapStringString(a.Headers)
+	nc.Args = helper.CopySliceString(a.Args)
+	return nc
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// CanaryAnalysisConfig configures the sources of metrics that jobs may
+// analyze their canaries with.
+type CanaryAnalysisConfig struct {
+	// PrometheusAddresses are the addresses of the Prometheus compatible
+	// HTTP APIs that jobs may query. Jobs can't use the Prometheus source if
+	// it's empty.
+	PrometheusAddresses []string `hcl:"prometheus_addresses"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// DefaultCanaryAnalysisConfig returns the canonical defaults for the Nomad
+// `canary_analysis` configuration.
+func DefaultCanaryAnalysisConfig() *CanaryAnalysisConfig {
+	return &CanaryAnalysisConfig{}
+}
+
+// AllowsPrometheusAddress returns whether jobs may query the Prometheus
+// compatible HTTP API at the address.
+func (c *CanaryAnalysisConfig) AllowsPrometheusAddress(address string) bool {
+	if c == nil {
+		return false
+	}
+	address = strings.TrimSuffix(address, "/")
+	for _, allowed := range c.PrometheusAddresses {
+		if strings.TrimSuffix(allowed, "/") == address {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error if the canary analysis configuration is invalid.
+func (c *CanaryAnalysisConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	for _, address := range c.PrometheusAddresses {
+		u, err := url.Parse(address)
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid prometheus address %q: %v", address, err))
+			continue
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("prometheus address %q must be an http or https URL", address))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (c *CanaryAnalysisConfig) Merge(b *CanaryAnalysisConfig) *CanaryAnalysisConfig {
+	result := c.Copy()
+
+	if len(b.PrometheusAddresses) != 0 {
+		result.PrometheusAddresses = append([]string(nil), b.PrometheusAddresses...)
+	}
+
+	return result
+}
+
+// Copy returns a copy of this CanaryAnalysis config.
+func (c *CanaryAnalysisConfig) Copy() *CanaryAnalysisConfig {
+	if c == nil {
+		return nil
+	}
+
+	nc := new(CanaryAnalysisConfig)
+	*nc = *c
+
+	if c.PrometheusAddresses != nil {
+		nc.PrometheusAddresses = append([]string(nil), c.PrometheusAddresses...)
+	}
+
+	return nc
+}
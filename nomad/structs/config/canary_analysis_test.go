@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryAnalysisConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	c1 := &CanaryAnalysisConfig{
+		PrometheusAddresses: []string{"http://prometheus-1:9090"},
+	}
+	c2 := &CanaryAnalysisConfig{
+		PrometheusAddresses: []string{"http://prometheus-2:9090/"},
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(t, []string{"http://prometheus-2:9090/"}, result.PrometheusAddresses)
+	require.True(t, result.AllowsPrometheusAddress("http://prometheus-2:9090"))
+	require.False(t, result.AllowsPrometheusAddress("http://prometheus-1:9090"))
+
+	// The original is unchanged
+	require.True(t, c1.AllowsPrometheusAddress("http://prometheus-1:9090/"))
+	require.False(t, c1.AllowsPrometheusAddress("http://169.254.169.254"))
+
+	// Addresses must be http URLs
+	require.NoError(t, result.Validate())
+	result.PrometheusAddresses = append(result.PrometheusAddresses, "file:///etc/passwd")
+	require.Error(t, result.Validate())
+}
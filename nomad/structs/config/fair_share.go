@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// FairShareConfig configures weighted fair-share dequeuing of evaluations
+// between queues. An evaluation's queue is the value of its job's meta key
+// QueueMetaKey, or its namespace if the key isn't set.
+type FairShareConfig struct {
+	// Enabled controls whether evaluations are dequeued by fair share.
+	Enabled *bool `hcl:"enabled"`
+
+	// QueueMetaKey is the job meta key whose value is used as the queue of
+	// the job's evaluations. If empty, evaluations are queued by namespace.
+	QueueMetaKey string `hcl:"queue_meta_key"`
+
+	// Weights are the relative weights of the queues. Queues without a
+	// weight have a weight of 1.
+	Weights map[string]int `hcl:"weights"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// DefaultFairShareConfig returns the canonical defaults for the Nomad
+// `fair_share` configuration.
+func DefaultFairShareConfig() *FairShareConfig {
+	return &FairShareConfig{
+		Enabled: helper.BoolToPtr(false),
+	}
+}
+
+// IsEnabled returns whether fair-share dequeuing is enabled.
+func (f *FairShareConfig) IsEnabled() bool {
+	return f != nil && f.Enabled != nil && *f.Enabled
+}
+
+// Weight returns the weight of the queue.
+func (f *FairShareConfig) Weight(queue string) int {
+	if w, ok := f.Weights[queue]; ok {
+		return w
+	}
+	return 1
+}
+
+// Validate returns an error if the fair share configuration is invalid.
+func (f *FairShareConfig) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	for queue, weight := range f.Weights {
+		if weight <= 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("weight of queue %q must be positive", queue))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (f *FairShareConfig) Merge(b *FairShareConfig) *FairShareConfig {
+	result := f.Copy()
+
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+	if b.QueueMetaKey != "" {
+		result.QueueMetaKey = b.QueueMetaKey
+	}
+	if len(b.Weights) != 0 {
+		if result.Weights == nil {
+			result.Weights = make(map[string]int, len(b.Weights))
+		}
+		for queue, weight := range b.Weights {
+			result.Weights[queue] = weight
+		}
+	}
+
+	return result
+}
+
+// Copy returns a copy of this FairShare config.
+func (f *FairShareConfig) Copy() *FairShareConfig {
+	if f == nil {
+		return nil
+	}
+
+	nc := new(FairShareConfig)
+	*nc = *f
+
+	if f.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*f.Enabled)
+	}
+	if f.Weights != nil {
+		nc.Weights = make(map[string]int, len(f.Weights))
+		for queue, weight := range f.Weights {
+			nc.Weights[queue] = weight
+		}
+	}
+
+	return nc
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFairShareConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	c1 := &FairShareConfig{
+		Enabled: helper.BoolToPtr(false),
+		Weights: map[string]int{"default": 2, "batch": 1},
+	}
+	c2 := &FairShareConfig{
+		Enabled:      helper.BoolToPtr(true),
+		QueueMetaKey: "queue",
+		Weights:      map[string]int{"batch": 4},
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(t, &FairShareConfig{
+		Enabled:      helper.BoolToPtr(true),
+		QueueMetaKey: "queue",
+		Weights:      map[string]int{"default": 2, "batch": 4},
+	}, result)
+	require.Equal(t, 4, result.Weight("batch"))
+	require.Equal(t, 1, result.Weight("other"))
+
+	// The original is unchanged
+	require.Equal(t, 1, c1.Weight("batch"))
+	require.False(t, c1.IsEnabled())
+
+	// Weights must be positive
+	require.NoError(t, result.Validate())
+	result.Weights["other"] = 0
+	require.Error(t, result.Validate())
+}
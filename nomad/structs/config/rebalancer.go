@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// RebalancerConfig configures the leader's rebalancer, which periodically
+// migrates allocations violating their spread or running on underutilized
+// nodes.
+//
+// Whether the rebalancer is run, and whether it only reports the migrations it
+// would make, are part of the replicated scheduler configuration so that all
+// servers agree on them.
+type RebalancerConfig struct {
+	// Interval is how often the rebalancer is run.
+	Interval    time.Duration `hcl:"-"`
+	IntervalHCL string        `hcl:"interval" json:"-"`
+
+	// MaxMigrations is the maximum number of allocations migrated per run.
+	MaxMigrations int `hcl:"max_migrations"`
+
+	// UtilizationThreshold is the fraction of a node's CPU and memory below
+	// which the node is considered underutilized, and its allocations are
+	// migrated so it can be emptied. Zero disables consolidating
+	// underutilized nodes.
+	UtilizationThreshold *float64 `hcl:"utilization_threshold"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// DefaultRebalancerConfig returns the canonical defaults for the Nomad
+// `rebalancer` configuration.
+func DefaultRebalancerConfig() *RebalancerConfig {
+	return &RebalancerConfig{
+		Interval:             5 * time.Minute,
+		MaxMigrations:        10,
+		UtilizationThreshold: helper.Float64ToPtr(0.2),
+	}
+}
+
+// Validate returns an error if the rebalancer is misconfigured.
+func (r *RebalancerConfig) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if r.Interval <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("interval must be positive"))
+	}
+	if r.MaxMigrations <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max_migrations must be positive"))
+	}
+	if t := r.UtilizationThreshold; t != nil && (*t < 0 || *t >= 1) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("utilization_threshold must be at least 0 and less than 1"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+func (r *RebalancerConfig) Merge(b *RebalancerConfig) *RebalancerConfig {
+	result := r.Copy()
+
+	if b.Interval != 0 {
+		result.Interval = b.Interval
+	}
+	if b.IntervalHCL != "" {
+		result.IntervalHCL = b.IntervalHCL
+	}
+	if b.MaxMigrations != 0 {
+		result.MaxMigrations = b.MaxMigrations
+	}
+	if b.UtilizationThreshold != nil {
+		result.UtilizationThreshold = helper.Float64ToPtr(*b.UtilizationThreshold)
+	}
+
+	return result
+}
+
+// Copy returns a copy of this Rebalancer config.
+func (r *RebalancerConfig) Copy() *RebalancerConfig {
+	if r == nil {
+		return nil
+	}
+
+	nc := new(RebalancerConfig)
+	*nc = *r
+
+	if r.UtilizationThreshold != nil {
+		nc.UtilizationThreshold = helper.Float64ToPtr(*r.UtilizationThreshold)
+	}
+
+	return nc
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRebalancerConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	c1 := DefaultRebalancerConfig()
+	c2 := &RebalancerConfig{
+		Interval:      time.Minute,
+		IntervalHCL:   "1m",
+		MaxMigrations: 20,
+	}
+
+	result := c1.Merge(c2)
+	require.Equal(t, &RebalancerConfig{
+		Interval:             time.Minute,
+		IntervalHCL:          "1m",
+		MaxMigrations:        20,
+		UtilizationThreshold: helper.Float64ToPtr(0.2),
+	}, result)
+
+	// The original is unchanged
+	require.Equal(t, 5*time.Minute, c1.Interval)
+}
+
+func TestRebalancerConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	require.NoError(t, DefaultRebalancerConfig().Validate())
+
+	c := DefaultRebalancerConfig()
+	c.Interval = 0
+	c.MaxMigrations = -1
+	c.UtilizationThreshold = helper.Float64ToPtr(1)
+	err := c.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "interval must be positive")
+	require.Contains(t, err.Error(), "max_migrations must be positive")
+	require.Contains(t, err.Error(), "utilization_threshold")
+}
@@ -256,6 +256,47 @@ const (
 	CSIVolumeClaimStateUnpublishing
 )
 
+// CSIVolumeStats is the usage and condition of a CSI volume as reported by
+// the NodeGetVolumeStats RPC of the node plugin where it is mounted. Usage
+// values are zero if they were not reported by the plugin.
+type CSIVolumeStats struct {
+	VolumeID  string
+	Namespace string
+	PluginID  string
+	NodeID    string
+
+	AvailableBytes  int64
+	TotalBytes      int64
+	UsedBytes       int64
+	AvailableInodes int64
+	TotalInodes     int64
+	UsedInodes      int64
+
+	// Abnormal and Message are the volume condition, which is only
+	// reported by plugins with the VOLUME_CONDITION node capability
+	Abnormal bool
+	Message  string
+
+	UpdateTime time.Time
+}
+
+func (s *CSIVolumeStats) Copy() *CSIVolumeStats {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	return &out
+}
+
+// UsedPercent returns the percentage of the volume's capacity in use, or
+// zero if the plugin did not report the volume's capacity
+func (s *CSIVolumeStats) UsedPercent() float64 {
+	if s == nil || s.TotalBytes <= 0 {
+		return 0
+	}
+	return float64(s.UsedBytes) / float64(s.TotalBytes) * 100
+}
+
 // CSIVolume is the full representation of a CSI Volume
 type CSIVolume struct {
 	// ID is a namespace unique URL safe identifier for the volume
@@ -301,6 +342,10 @@ type CSIVolume struct {
 	WriteClaims map[string]*CSIVolumeClaim `json:"-"` // AllocID -> claim
 	PastClaims  map[string]*CSIVolumeClaim `json:"-"` // AllocID -> claim
 
+	// Stats are the usage and condition of the volume, as most recently
+	// reported by the node plugin on each node where the volume is mounted
+	Stats map[string]*CSIVolumeStats // NodeID -> stats
+
 	// Schedulable is true if all the denormalized plugin health fields are true, and the
 	// volume has not been marked for garbage collection
 	Schedulable         bool
@@ -552,9 +597,49 @@ func (v *CSIVolume) Copy() *CSIVolume {
 		out.PastClaims[k] = &claim
 	}
 
+	if v.Stats != nil {
+		out.Stats = make(map[string]*CSIVolumeStats, len(v.Stats))
+		for k, v := range v.Stats {
+			out.Stats[k] = v.Copy()
+		}
+	}
+
 	return out
 }
 
+// Abnormal returns true if the node plugin on any node where the volume is
+// mounted has reported the volume condition as abnormal
+func (v *CSIVolume) Abnormal() bool {
+	for _, stats := range v.Stats {
+		if stats.Abnormal {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateStats records the stats reported by a node plugin for the volume
+func (v *CSIVolume) UpdateStats(stats *CSIVolumeStats) {
+	if v.Stats == nil {
+		v.Stats = map[string]*CSIVolumeStats{}
+	}
+	v.Stats[stats.NodeID] = stats
+}
+
+// HasNodeClaims returns true if any allocation on the node has a claim on
+// the volume
+func (v *CSIVolume) HasNodeClaims(nodeID string) bool {
+	for _, claims := range []map[string]*CSIVolumeClaim{
+		v.ReadClaims, v.WriteClaims, v.PastClaims} {
+		for _, claim := range claims {
+			if claim.NodeID == nodeID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Claim updates the allocations and changes the volume state
 func (v *CSIVolume) Claim(claim *CSIVolumeClaim, alloc *Allocation) error {
 	// COMPAT: volumes registered prior to 1.1.0 will be missing caps for the
@@ -665,12 +750,25 @@ func (v *CSIVolume) setModesFromClaim(claim *CSIVolumeClaim) {
 // already stopped using the volume
 func (v *CSIVolume) claimRelease(claim *CSIVolumeClaim) error {
 	if claim.State == CSIVolumeClaimStateReadyToFree {
+		nodeID := claim.NodeID
+		if nodeID == "" {
+			if past, ok := v.PastClaims[claim.AllocationID]; ok {
+				nodeID = past.NodeID
+			}
+		}
+
 		delete(v.ReadAllocs, claim.AllocationID)
 		delete(v.WriteAllocs, claim.AllocationID)
 		delete(v.ReadClaims, claim.AllocationID)
 		delete(v.WriteClaims, claim.AllocationID)
 		delete(v.PastClaims, claim.AllocationID)
 
+		// the volume is no longer mounted on the node, so its stats
+		// from that node are stale
+		if nodeID != "" && !v.HasNodeClaims(nodeID) {
+			delete(v.Stats, nodeID)
+		}
+
 		// remove AccessMode/AttachmentMode if this is the last claim
 		if len(v.ReadClaims) == 0 && len(v.WriteClaims) == 0 && len(v.PastClaims) == 0 {
 			v.AccessMode = CSIVolumeAccessModeUnknown
@@ -783,18 +881,16 @@ func (v *CSIVolume) Merge(other *CSIVolume) error {
 			"volume snapshot ID cannot be updated"))
 	}
 
-	// must be compatible with capacity range
-	// TODO: when ExpandVolume is implemented we'll need to update
-	// this logic https://github.com/hashicorp/nomad/issues/10324
-	if v.Capacity != 0 {
-		if other.RequestedCapacityMax < v.Capacity ||
-			other.RequestedCapacityMin > v.Capacity {
-			errs = multierror.Append(errs, errors.New(
-				"volume requested capacity update was not compatible with existing capacity"))
-		} else {
-			v.RequestedCapacityMin = other.RequestedCapacityMin
-			v.RequestedCapacityMax = other.RequestedCapacityMax
-		}
+	// must be compatible with capacity range. Volumes can be expanded
+	// but not shrunk, so a requested minimum above the existing capacity
+	// is accepted and the caller is responsible for expanding the volume
+	if v.Capacity != 0 && other.RequestedCapacityMax != 0 &&
+		other.RequestedCapacityMax < v.Capacity {
+		errs = multierror.Append(errs, errors.New(
+			"volume requested capacity update was not compatible with existing capacity"))
+	} else {
+		v.RequestedCapacityMin = other.RequestedCapacityMin
+		v.RequestedCapacityMax = other.RequestedCapacityMax
 	}
 
 	// must be compatible with volume_capabilities
@@ -847,6 +943,81 @@ func (v *CSIVolume) Merge(other *CSIVolume) error {
 	return errs.ErrorOrNil()
 }
 
+// NeedsExpansion returns true if the requested minimum capacity of the
+// updated volume exceeds both the capacity and the requested minimum
+// capacity of this volume, in which case the volume must be expanded by
+// the plugin before the update is written.
+func (v *CSIVolume) NeedsExpansion(other *CSIVolume) bool {
+	return other.RequestedCapacityMin > v.Capacity &&
+		other.RequestedCapacityMin > v.RequestedCapacityMin
+}
+
+// ValidateUpdate returns an error if the other volume can't replace this
+// volume, either because it changes the identity of the volume, or because
+// this volume is in use and the update isn't limited to its capacity.
+func (v *CSIVolume) ValidateUpdate(other *CSIVolume) error {
+	if v.ExternalID != other.ExternalID ||
+		v.PluginID != other.PluginID ||
+		v.Provider != other.Provider {
+		return fmt.Errorf("volume identity cannot be updated: %s", other.ID)
+	}
+	if v.InUse() && !v.IsCapacityUpdate(other) {
+		return fmt.Errorf("volume cannot be updated while in use")
+	}
+	return nil
+}
+
+// IsCapacityUpdate returns true if the only user-settable fields that
+// differ between this volume and the other are its capacity and secrets.
+// These can be updated while the volume is in use, because the plugin
+// expands the volume without interrupting the allocations using it.
+func (v *CSIVolume) IsCapacityUpdate(other *CSIVolume) bool {
+	if v.Name != other.Name ||
+		v.ExternalID != other.ExternalID ||
+		v.PluginID != other.PluginID ||
+		v.CloneID != other.CloneID ||
+		v.SnapshotID != other.SnapshotID {
+		return false
+	}
+	if !optionalMountOptions(v.MountOptions).Equal(optionalMountOptions(other.MountOptions)) ||
+		!v.RequestedTopologies.Equal(other.RequestedTopologies) {
+		return false
+	}
+	if !compareOptionalMaps(v.Parameters, other.Parameters) ||
+		!compareOptionalMaps(v.Context, other.Context) {
+		return false
+	}
+	if len(v.RequestedCapabilities) != len(other.RequestedCapabilities) {
+		return false
+	}
+	for i, cap := range v.RequestedCapabilities {
+		otherCap := other.RequestedCapabilities[i]
+		if cap.AccessMode != otherCap.AccessMode ||
+			cap.AttachmentMode != otherCap.AttachmentMode {
+			return false
+		}
+	}
+	return true
+}
+
+// optionalMountOptions returns empty mount options in place of nil, because
+// volume copies always allocate their mount options.
+func optionalMountOptions(o *CSIMountOptions) *CSIMountOptions {
+	if o == nil {
+		return &CSIMountOptions{}
+	}
+	return o
+}
+
+// compareOptionalMaps compares two maps, treating nil and empty maps as
+// equal because volume copies always allocate their maps.
+func compareOptionalMaps(a, b map[string]string) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return helper.CompareMapStringString(a, b)
+}
+
 // Request and response wrappers
 type CSIVolumeRegisterRequest struct {
 	Volumes []*CSIVolume
@@ -950,6 +1121,19 @@ type CSIVolumeClaimResponse struct {
 	QueryMeta
 }
 
+// CSIVolumeUpdateStatsRequest is used by clients to report the stats of the
+// volumes mounted on the node. Clients only report stats that have changed
+// since they were last reported.
+type CSIVolumeUpdateStatsRequest struct {
+	NodeID string
+	Stats  []*CSIVolumeStats
+	WriteRequest
+}
+
+type CSIVolumeUpdateStatsResponse struct {
+	WriteMeta
+}
+
 type CSIVolumeListRequest struct {
 	PluginID string
 	NodeID   string
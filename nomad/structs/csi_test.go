@@ -527,6 +527,10 @@ func TestVolume_Copy(t *testing.T) {
 		WriteClaims: map[string]*CSIVolumeClaim{a3.ID: c3},
 		PastClaims:  map[string]*CSIVolumeClaim{},
 
+		Stats: map[string]*CSIVolumeStats{
+			a1.NodeID: {VolumeID: "vol1", NodeID: a1.NodeID, UsedBytes: 1024},
+		},
+
 		Schedulable:         true,
 		PluginID:            "moosefs",
 		Provider:            "n/a",
@@ -548,6 +552,7 @@ func TestVolume_Copy(t *testing.T) {
 	v1.ReadAllocs[a2.ID] = a2
 	v1.WriteAllocs[a3.ID].ClientStatus = AllocClientStatusComplete
 	v1.MountOptions.FSType = "zfs"
+	v1.Stats[a1.NodeID].Abnormal = true
 
 	if v2.ReadClaims[a1.ID].State == CSIVolumeClaimStateReadyToFree {
 		t.Fatalf("Volume.Copy() failed; changes to original ReadClaims seen in copy")
@@ -561,6 +566,9 @@ func TestVolume_Copy(t *testing.T) {
 	if v2.MountOptions.FSType == "zfs" {
 		t.Fatalf("Volume.Copy() failed; changes to original MountOptions seen in copy")
 	}
+	if v2.Stats[a1.NodeID].Abnormal {
+		t.Fatalf("Volume.Copy() failed; changes to original Stats seen in copy")
+	}
 
 }
 
@@ -595,11 +603,22 @@ func TestCSIVolume_Merge(t *testing.T) {
 			name: "invalid capacity update",
 			v:    &CSIVolume{Capacity: 100},
 			update: &CSIVolume{
-				RequestedCapacityMax: 300, RequestedCapacityMin: 200},
+				RequestedCapacityMax: 50, RequestedCapacityMin: 20},
 			expected: "volume requested capacity update was not compatible with existing capacity",
 			expectFn: func(t *testing.T, v *CSIVolume) {
-				require.NotEqual(t, 300, v.RequestedCapacityMax)
-				require.NotEqual(t, 200, v.RequestedCapacityMin)
+				require.NotEqual(t, int64(50), v.RequestedCapacityMax)
+				require.NotEqual(t, int64(20), v.RequestedCapacityMin)
+			},
+		},
+		{
+			name: "valid capacity expansion",
+			v:    &CSIVolume{Capacity: 100},
+			update: &CSIVolume{
+				RequestedCapacityMax: 300, RequestedCapacityMin: 200},
+			expectFn: func(t *testing.T, v *CSIVolume) {
+				require.Equal(t, int64(300), v.RequestedCapacityMax)
+				require.Equal(t, int64(200), v.RequestedCapacityMin)
+				require.Equal(t, int64(100), v.Capacity)
 			},
 		},
 		{
@@ -738,12 +757,12 @@ func TestCSIVolume_Merge(t *testing.T) {
 		tc = tc
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.v.Merge(tc.update)
+			if tc.expectFn != nil {
+				tc.expectFn(t, tc.v)
+			}
 			if tc.expected == "" {
 				require.NoError(t, err)
 			} else {
-				if tc.expectFn != nil {
-					tc.expectFn(t, tc.v)
-				}
 				require.Error(t, err, tc.expected)
 				require.Contains(t, err.Error(), tc.expected)
 			}
@@ -751,6 +770,133 @@ func TestCSIVolume_Merge(t *testing.T) {
 	}
 }
 
+func TestCSIVolume_NeedsExpansion(t *testing.T) {
+	ci.Parallel(t)
+
+	v := &CSIVolume{Capacity: 100, RequestedCapacityMin: 50}
+	require.False(t, v.NeedsExpansion(&CSIVolume{RequestedCapacityMin: 100}))
+	require.False(t, v.NeedsExpansion(&CSIVolume{RequestedCapacityMin: 50}))
+	require.True(t, v.NeedsExpansion(&CSIVolume{RequestedCapacityMin: 200}))
+
+	// registered volumes may not have a known capacity
+	v = &CSIVolume{RequestedCapacityMin: 50}
+	require.False(t, v.NeedsExpansion(&CSIVolume{RequestedCapacityMin: 50}))
+	require.True(t, v.NeedsExpansion(&CSIVolume{RequestedCapacityMin: 60}))
+}
+
+func TestCSIVolume_ValidateUpdate(t *testing.T) {
+	ci.Parallel(t)
+
+	v := NewCSIVolume("vol", 0)
+	v.ExternalID = "ext"
+	v.PluginID = "plugin"
+	v.Provider = "provider"
+
+	other := v.Copy()
+	other.MountOptions = &CSIMountOptions{FSType: "ext4"}
+	require.NoError(t, v.ValidateUpdate(other))
+
+	other.ExternalID = "changed"
+	require.EqualError(t, v.ValidateUpdate(other), "volume identity cannot be updated: vol")
+
+	// Only the capacity of volumes in use can be updated
+	v.ReadAllocs = map[string]*Allocation{"alloc": nil}
+	other = v.Copy()
+	other.MountOptions = &CSIMountOptions{FSType: "ext4"}
+	require.EqualError(t, v.ValidateUpdate(other), "volume cannot be updated while in use")
+
+	other = v.Copy()
+	other.RequestedCapacityMin = 1024
+	require.NoError(t, v.ValidateUpdate(other))
+}
+
+func TestCSIVolume_IsCapacityUpdate(t *testing.T) {
+	ci.Parallel(t)
+
+	v := NewCSIVolume("vol", 0)
+	v.PluginID = "plugin"
+	v.Capacity = 100
+	v.RequestedCapabilities = []*CSIVolumeCapability{{
+		AccessMode:     CSIVolumeAccessModeSingleNodeWriter,
+		AttachmentMode: CSIVolumeAttachmentModeFilesystem,
+	}}
+
+	other := v.Copy()
+	other.Secrets = nil
+	other.RequestedCapacityMin = 200
+	other.Capacity = 200
+	require.True(t, v.IsCapacityUpdate(other))
+
+	other.MountOptions = nil
+	require.True(t, v.IsCapacityUpdate(other))
+
+	other.MountOptions = &CSIMountOptions{FSType: "xfs"}
+	require.False(t, v.IsCapacityUpdate(other))
+
+	other = v.Copy()
+	other.RequestedCapabilities = []*CSIVolumeCapability{{
+		AccessMode:     CSIVolumeAccessModeMultiNodeReader,
+		AttachmentMode: CSIVolumeAttachmentModeFilesystem,
+	}}
+	require.False(t, v.IsCapacityUpdate(other))
+
+	other = v.Copy()
+	other.Parameters = map[string]string{"foo": "bar"}
+	require.False(t, v.IsCapacityUpdate(other))
+}
+
+func TestCSIVolume_Stats(t *testing.T) {
+	ci.Parallel(t)
+
+	vol := NewCSIVolume("vol0", 0)
+	vol.Schedulable = true
+	vol.RequestedCapabilities = []*CSIVolumeCapability{{
+		AccessMode:     CSIVolumeAccessModeMultiNodeMultiWriter,
+		AttachmentMode: CSIVolumeAttachmentModeFilesystem,
+	}}
+
+	alloc1 := &Allocation{ID: "a1", Namespace: "n", JobID: "j", NodeID: "node1"}
+	alloc2 := &Allocation{ID: "a2", Namespace: "n", JobID: "j", NodeID: "node1"}
+	alloc3 := &Allocation{ID: "a3", Namespace: "n", JobID: "j", NodeID: "node2"}
+	for _, alloc := range []*Allocation{alloc1, alloc2, alloc3} {
+		require.NoError(t, vol.Claim(&CSIVolumeClaim{
+			AllocationID:   alloc.ID,
+			NodeID:         alloc.NodeID,
+			Mode:           CSIVolumeClaimWrite,
+			AccessMode:     CSIVolumeAccessModeMultiNodeMultiWriter,
+			AttachmentMode: CSIVolumeAttachmentModeFilesystem,
+			State:          CSIVolumeClaimStateTaken,
+		}, alloc))
+	}
+
+	vol.UpdateStats(&CSIVolumeStats{NodeID: "node1", TotalBytes: 200, UsedBytes: 50})
+	vol.UpdateStats(&CSIVolumeStats{NodeID: "node2", Abnormal: true})
+	require.Len(t, vol.Stats, 2)
+	require.True(t, vol.Abnormal())
+	require.Equal(t, float64(25), vol.Stats["node1"].UsedPercent())
+	require.Zero(t, vol.Stats["node2"].UsedPercent())
+
+	release := func(alloc *Allocation) {
+		require.NoError(t, vol.Claim(&CSIVolumeClaim{
+			AllocationID: alloc.ID,
+			NodeID:       alloc.NodeID,
+			Mode:         CSIVolumeClaimWrite,
+			State:        CSIVolumeClaimStateReadyToFree,
+		}, nil))
+	}
+
+	// stats are kept while any allocation on the node has a claim
+	release(alloc1)
+	require.Len(t, vol.Stats, 2)
+
+	release(alloc3)
+	require.Len(t, vol.Stats, 1)
+	require.False(t, vol.Abnormal())
+
+	release(alloc2)
+	require.Empty(t, vol.Stats)
+}
+
 func TestCSIPluginJobs(t *testing.T) {
 	ci.Parallel(t)
 
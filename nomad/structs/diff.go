@@ -123,6 +123,17 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Dependencies diff
+	dependsOnDiff := primitiveObjectSetDiff(
+		interfaceSlice(j.DependsOn),
+		interfaceSlice(other.DependsOn),
+		nil,
+		"DependsOn",
+		contextual)
+	if dependsOnDiff != nil {
+		diff.Objects = append(diff.Objects, dependsOnDiff...)
+	}
+
 	// Task groups diff
 	tgs, err := taskGroupDiffs(j.TaskGroups, other.TaskGroups, contextual)
 	if err != nil {
@@ -329,7 +340,36 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 
 	// Update diff
 	// COMPAT: Remove "Stagger" in 0.7.0.
-	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
+	uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual)
+	var oldAnalysis, newAnalysis *AnalysisStrategy
+	var oldSteps, newSteps []*RolloutStep
+	if tg.Update != nil {
+		oldAnalysis = tg.Update.Analysis
+		oldSteps = tg.Update.Steps
+	}
+	if other.Update != nil {
+		newAnalysis = other.Update.Analysis
+		newSteps = other.Update.Steps
+	}
+	if aDiff := analysisDiff(oldAnalysis, newAnalysis, contextual); aDiff != nil {
+		if uDiff == nil {
+			uDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		uDiff.Objects = append(uDiff.Objects, aDiff)
+	}
+	stepsDiff := primitiveObjectSetDiff(
+		interfaceSlice(oldSteps),
+		interfaceSlice(newSteps),
+		nil,
+		"Step",
+		contextual)
+	if stepsDiff != nil {
+		if uDiff == nil {
+			uDiff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+		}
+		uDiff.Objects = append(uDiff.Objects, stepsDiff...)
+	}
+	if uDiff != nil {
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
@@ -505,7 +545,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1435,7 +1475,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1443,6 +1483,35 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// logConfigDiff returns the diff of two LogConfig objects, including their
+// sinks. If contextual diff is enabled, all fields will be returned, even if
+// no diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	var oldSinks, newSinks []interface{}
+	if old != nil {
+		for _, sink := range old.Sinks {
+			oldSinks = append(oldSinks, sink)
+		}
+	}
+	if new != nil {
+		for _, sink := range new.Sinks {
+			newSinks = append(newSinks, sink)
+		}
+	}
+	sinkDiffs := primitiveObjectSetDiff(oldSinks, newSinks, nil, "Sink", contextual)
+
+	diff := primitiveObjectDiff(old, new, nil, "LogConfig", contextual)
+	if diff == nil {
+		if len(sinkDiffs) == 0 {
+			return nil
+		}
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+		diff.Fields = fieldDiffs(flatmap.Flatten(old, nil, true), flatmap.Flatten(new, nil, true), contextual)
+	}
+	diff.Objects = sinkDiffs
+	return diff
+}
+
 // consulProxyDiff returns the diff of two ConsulProxy objects.
 // If contextual diff is enabled, all fields will be returned, even if no diff occurred.
 func consulProxyDiff(old, new *ConsulProxy, contextual bool) *ObjectDiff {
@@ -1853,6 +1922,84 @@ func multiregionDiff(old, new *Multiregion, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// analysisDiff returns the diff of two canary analysis strategies. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func analysisDiff(old, new *AnalysisStrategy, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Analysis"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &AnalysisStrategy{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &AnalysisStrategy{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Metrics diff
+	oldMetrics := make(map[string]*AnalysisMetric, len(old.Metrics))
+	newMetrics := make(map[string]*AnalysisMetric, len(new.Metrics))
+	for _, m := range old.Metrics {
+		oldMetrics[m.Name] = m
+	}
+	for _, m := range new.Metrics {
+		newMetrics[m.Name] = m
+	}
+	for name, oldMetric := range oldMetrics {
+		if mDiff := analysisMetricDiff(oldMetric, newMetrics[name], contextual); mDiff != nil {
+			diff.Objects = append(diff.Objects, mDiff)
+		}
+	}
+	for name, newMetric := range newMetrics {
+		if _, ok := oldMetrics[name]; !ok {
+			if mDiff := analysisMetricDiff(nil, newMetric, contextual); mDiff != nil {
+				diff.Objects = append(diff.Objects, mDiff)
+			}
+		}
+	}
+
+	sort.Sort(FieldDiffs(diff.Fields))
+	sort.Sort(ObjectDiffs(diff.Objects))
+	return diff
+}
+
+// analysisMetricDiff returns the diff of two canary analysis metrics. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func analysisMetricDiff(old, new *AnalysisMetric, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Metric"}
+	var oldFlat, newFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		diff.Type = DiffTypeAdded
+		newFlat = flatmap.Flatten(new, nil, false)
+	} else if new == nil {
+		diff.Type = DiffTypeDeleted
+		oldFlat = flatmap.Flatten(old, nil, false)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldFlat = flatmap.Flatten(old, nil, false)
+		newFlat = flatmap.Flatten(new, nil, false)
+	}
+
+	diff.Fields = fieldDiffs(oldFlat, newFlat, contextual)
+	return diff
+}
+
 func multiregionRegionDiff(r, other *MultiregionRegion, contextual bool) *ObjectDiff {
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Region"}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
@@ -2044,6 +2191,16 @@ func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, nDiffs...)
 	}
 
+	// NUMA diff
+	if nDiff := primitiveObjectDiff(r.NUMA, other.NUMA, nil, "NUMA", contextual); nDiff != nil {
+		diff.Objects = append(diff.Objects, nDiff)
+	}
+
+	// Disk IO diff
+	if dDiff := primitiveObjectDiff(r.DiskIO, other.DiskIO, nil, "DiskIO", contextual); dDiff != nil {
+		diff.Objects = append(diff.Objects, dDiff)
+	}
+
 	return diff
 }
 
@@ -16,21 +16,26 @@ type EventStreamWrapper struct {
 type Topic string
 
 const (
-	TopicDeployment Topic = "Deployment"
-	TopicEvaluation Topic = "Evaluation"
-	TopicAllocation Topic = "Allocation"
-	TopicJob        Topic = "Job"
-	TopicNode       Topic = "Node"
-	TopicACLPolicy  Topic = "ACLPolicy"
-	TopicACLToken   Topic = "ACLToken"
-	TopicService    Topic = "Service"
-	TopicAll        Topic = "*"
+	TopicDeployment    Topic = "Deployment"
+	TopicEvaluation    Topic = "Evaluation"
+	TopicAllocation    Topic = "Allocation"
+	TopicJob           Topic = "Job"
+	TopicNode          Topic = "Node"
+	TopicACLPolicy     Topic = "ACLPolicy"
+	TopicACLToken      Topic = "ACLToken"
+	TopicService       Topic = "Service"
+	TopicCSIVolume     Topic = "CSIVolume"
+	TopicCSIPlugin     Topic = "CSIPlugin"
+	TopicNamespace     Topic = "Namespace"
+	TopicScalingPolicy Topic = "ScalingPolicy"
+	TopicAll           Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
 	TypeNodeDeregistration            = "NodeDeregistration"
 	TypeNodeEligibilityUpdate         = "NodeEligibility"
 	TypeNodeDrain                     = "NodeDrain"
 	TypeNodeEvent                     = "NodeStreamEvent"
+	TypeNodeMaintenance               = "NodeMaintenance"
 	TypeDeploymentUpdate              = "DeploymentStatusUpdate"
 	TypeDeploymentPromotion           = "DeploymentPromotion"
 	TypeDeploymentAllocHealth         = "DeploymentAllocHealth"
@@ -48,6 +53,13 @@ const (
 	TypeACLPolicyUpserted             = "ACLPolicyUpserted"
 	TypeServiceRegistration           = "ServiceRegistration"
 	TypeServiceDeregistration         = "ServiceDeregistration"
+	TypeCSIVolumeRegistered           = "CSIVolumeRegistered"
+	TypeCSIVolumeDeregistered         = "CSIVolumeDeregistered"
+	TypeCSIVolumeClaim                = "CSIVolumeClaim"
+	TypeCSIVolumeStatsUpdated         = "CSIVolumeStatsUpdated"
+	TypeCSIPluginDeleted              = "CSIPluginDeleted"
+	TypeNamespaceUpserted             = "NamespaceUpserted"
+	TypeNamespaceDeleted              = "NamespaceDeleted"
 )
 
 // Event represents a change in Nomads state.
@@ -95,6 +107,24 @@ func (j *EventJson) Copy() *EventJson {
 	return n
 }
 
+// EventStreamResubscribe is sent as the final entry of an event stream when
+// the events following the requested index are no longer available, because
+// they have been truncated from the server's event buffer or journal. The
+// subscriber must resubscribe, reconciling any state it derives from events
+// instead of assuming it has observed every event.
+type EventStreamResubscribe struct {
+	// Resubscribe is always true, and identifies the entry.
+	Resubscribe bool
+
+	// Error describes why the stream was ended.
+	Error string
+
+	// TruncatedIndex is the highest index for which events may be missing.
+	// Subscribing from an index above it will succeed, provided it has not
+	// been truncated in the meantime.
+	TruncatedIndex uint64
+}
+
 // JobEvent holds a newly updated Job.
 type JobEvent struct {
 	Job *Job
@@ -132,6 +162,45 @@ type ServiceRegistrationStreamEvent struct {
 	Service *ServiceRegistration
 }
 
+// CSIVolumeEvent holds a newly updated or deleted CSI volume. The volume's
+// secrets and the allocations embedded in its claims have been removed.
+type CSIVolumeEvent struct {
+	Volume *CSIVolume
+}
+
+// NewCSIVolumeEvent takes a volume and creates a new CSIVolumeEvent. It
+// creates a copy of the passed in volume with its secrets emptied out, and its
+// claimed allocations replaced with nil to keep the size of the event down.
+// The claims themselves are retained.
+func NewCSIVolumeEvent(vol *CSIVolume) *CSIVolumeEvent {
+	c := vol.Copy()
+	c.Secrets = CSISecrets{}
+
+	for id := range c.ReadAllocs {
+		c.ReadAllocs[id] = nil
+	}
+	for id := range c.WriteAllocs {
+		c.WriteAllocs[id] = nil
+	}
+
+	return &CSIVolumeEvent{Volume: c}
+}
+
+// CSIPluginEvent holds a newly updated or deleted CSI plugin.
+type CSIPluginEvent struct {
+	Plugin *CSIPlugin
+}
+
+// NamespaceEvent holds a newly updated or deleted namespace.
+type NamespaceEvent struct {
+	Namespace *Namespace
+}
+
+// ScalingPolicyEvent holds a newly updated or deleted scaling policy.
+type ScalingPolicyEvent struct {
+	ScalingPolicy *ScalingPolicy
+}
+
 // NewACLTokenEvent takes a token and creates a new ACLTokenEvent.  It creates
 // a copy of the passed in ACLToken and empties out the copied tokens SecretID
 func NewACLTokenEvent(token *ACLToken) *ACLTokenEvent {
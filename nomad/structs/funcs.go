@@ -202,6 +202,12 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex, checkDevi
 		return false, dimension, used, nil
 	}
 
+	// Check that the disk IO and egress bandwidth of the node aren't
+	// oversubscribed
+	if fit, dimension := ioFit(node, used); !fit {
+		return false, dimension, used, nil
+	}
+
 	// Create the network index if missing
 	if netIdx == nil {
 		netIdx = NewNetworkIndex()
@@ -232,6 +238,29 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex, checkDevi
 	return true, "", used, nil
 }
 
+// ioFit returns whether the disk IO and egress bandwidth used by allocations
+// fit the capacity of the node. Nodes that don't report a capacity only fit
+// allocations that don't use the resource.
+func ioFit(node *Node, used *ComparableResources) (bool, string) {
+	var disk NodeDiskResources
+	var bandwidth NodeBandwidthResources
+	if node.NodeResources != nil {
+		disk = node.NodeResources.Disk
+		bandwidth = node.NodeResources.Bandwidth
+	}
+
+	if used.Flattened.DiskIO.IOPS() > disk.IOPS {
+		return false, "disk iops"
+	}
+	if used.Flattened.DiskIO.MBps() > disk.MBps {
+		return false, "disk throughput"
+	}
+	if used.Flattened.Bandwidth.EgressMbits > bandwidth.EgressMbits {
+		return false, "egress bandwidth"
+	}
+	return true, ""
+}
+
 func computeFreePercentage(node *Node, util *ComparableResources) (freePctCpu, freePctRam float64) {
 	// COMPAT(0.11): Remove in 0.11
 	reserved := node.ComparableReservedResources()
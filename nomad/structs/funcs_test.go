@@ -626,6 +626,88 @@ func TestAllocsFit_MemoryOversubscription(t *testing.T) {
 	require.EqualValues(t, 12000, used.Flattened.Memory.MemoryMaxMB)
 }
 
+func TestAllocsFit_DiskIOAndBandwidth(t *testing.T) {
+	ci.Parallel(t)
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			Cpu: NodeCpuResources{
+				CpuShares: 2000,
+			},
+			Memory: NodeMemoryResources{
+				MemoryMB: 2048,
+			},
+			Disk: NodeDiskResources{
+				IOPS: 1000,
+				MBps: 200,
+			},
+			Bandwidth: NodeBandwidthResources{
+				EgressMbits: 1000,
+			},
+		},
+	}
+
+	alloc := func(iops, mbps, egress int64) *Allocation {
+		return &Allocation{
+			AllocatedResources: &AllocatedResources{
+				Tasks: map[string]*AllocatedTaskResources{
+					"web": {
+						Cpu: AllocatedCpuResources{
+							CpuShares: 100,
+						},
+						Memory: AllocatedMemoryResources{
+							MemoryMB: 100,
+						},
+						DiskIO: AllocatedDiskIOResources{
+							ReadIOPS:  iops / 2,
+							WriteIOPS: iops / 2,
+							ReadMBps:  mbps / 2,
+							WriteMBps: mbps / 2,
+						},
+						Bandwidth: AllocatedBandwidthResources{
+							EgressMbits: egress,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Should fit two allocations
+	fit, _, used, err := AllocsFit(n, []*Allocation{alloc(500, 100, 500), alloc(500, 100, 500)}, nil, false)
+	require.NoError(t, err)
+	require.True(t, fit)
+	require.EqualValues(t, 1000, used.Flattened.DiskIO.IOPS())
+	require.EqualValues(t, 200, used.Flattened.DiskIO.MBps())
+	require.EqualValues(t, 1000, used.Flattened.Bandwidth.EgressMbits)
+
+	cases := []struct {
+		alloc     *Allocation
+		dimension string
+	}{
+		{alloc(1200, 0, 0), "disk iops"},
+		{alloc(0, 400, 0), "disk throughput"},
+		{alloc(0, 0, 2000), "egress bandwidth"},
+	}
+	for _, tc := range cases {
+		fit, dim, _, err := AllocsFit(n, []*Allocation{tc.alloc}, nil, false)
+		require.NoError(t, err)
+		require.False(t, fit)
+		require.Equal(t, tc.dimension, dim)
+	}
+
+	// Nodes without capacity only fit allocations not using the resources
+	n.NodeResources.Disk = NodeDiskResources{}
+	fit, dim, _, err := AllocsFit(n, []*Allocation{alloc(100, 0, 0)}, nil, false)
+	require.NoError(t, err)
+	require.False(t, fit)
+	require.Equal(t, "disk iops", dim)
+
+	fit, _, _, err = AllocsFit(n, []*Allocation{alloc(0, 0, 100)}, nil, false)
+	require.NoError(t, err)
+	require.True(t, fit)
+}
+
 // COMPAT(0.11): Remove in 0.11
 func TestScoreFitBinPack_Old(t *testing.T) {
 	ci.Parallel(t)
@@ -156,6 +156,10 @@ type SchedulerConfiguration struct {
 	// management ACL token
 	RejectJobRegistration bool `hcl:"reject_job_registration"`
 
+	// RebalancerConfig specifies whether the rebalancer migrates allocations
+	// violating their spread or running on underutilized nodes.
+	RebalancerConfig RebalancerConfig `hcl:"rebalancer_config"`
+
 	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -222,6 +226,17 @@ type PreemptionConfig struct {
 	ServiceSchedulerEnabled bool `hcl:"service_scheduler_enabled"`
 }
 
+// RebalancerConfig specifies whether the rebalancer is run. Its interval and
+// limits are part of each server's configuration.
+type RebalancerConfig struct {
+	// Enabled specifies if the rebalancer is run
+	Enabled bool `hcl:"enabled"`
+
+	// DryRun specifies if the rebalancer only reports the migrations it would
+	// make, without migrating any allocations
+	DryRun bool `hcl:"dry_run"`
+}
+
 // SchedulerSetConfigRequest is used by the Operator endpoint to update the
 // current Scheduler configuration of the cluster.
 type SchedulerSetConfigRequest struct {
@@ -0,0 +1,616 @@
+package structs
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// maxQuotaDescriptionLength limits a quota specification description
+	// length
+	maxQuotaDescriptionLength = 256
+)
+
+// QuotaSpec specifies the allowed resource usage across regions. Namespaces
+// reference a quota specification, and the allocations in every namespace
+// referencing it account against its limits.
+type QuotaSpec struct {
+	// Name is the name for the quota object
+	Name string
+
+	// Description is an optional description for the quota object
+	Description string
+
+	// Limits is the set of quota limits encapsulated by this quota object.
+	// Each limit applies quota in a particular region.
+	Limits []*QuotaLimit
+
+	// Hash is the hash of the object and is used to make replication
+	// efficient.
+	Hash []byte
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate returns an error if the quota specification is invalid.
+func (q *QuotaSpec) Validate() error {
+	var mErr multierror.Error
+
+	if !validNamespaceName.MatchString(q.Name) {
+		err := fmt.Errorf("invalid name %q. Must match regex %s", q.Name, validNamespaceName)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(q.Description) > maxQuotaDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxQuotaDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	regions := make(map[string]struct{}, len(q.Limits))
+	for i, limit := range q.Limits {
+		if limit == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("limit %d is nil", i+1))
+			continue
+		}
+		if _, ok := regions[limit.Region]; ok {
+			err := fmt.Errorf("limit %d: region %q has multiple limits", i+1, limit.Region)
+			mErr.Errors = append(mErr.Errors, err)
+		}
+		regions[limit.Region] = struct{}{}
+
+		if err := limit.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("limit %d:", i+1)))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the quota specification and
+// its limits.
+func (q *QuotaSpec) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	_, _ = hash.Write([]byte(q.Name))
+	_, _ = hash.Write([]byte(q.Description))
+	for _, limit := range q.Limits {
+		_, _ = hash.Write(limit.SetHash())
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	q.Hash = hashVal
+	return hashVal
+}
+
+// RegionLimit returns the limit of the quota specification for the given
+// region, or nil if the quota does not limit the region.
+func (q *QuotaSpec) RegionLimit(region string) *QuotaLimit {
+	for _, limit := range q.Limits {
+		if limit.Region == region {
+			return limit
+		}
+	}
+	return nil
+}
+
+func (q *QuotaSpec) Copy() *QuotaSpec {
+	if q == nil {
+		return nil
+	}
+
+	nq := new(QuotaSpec)
+	*nq = *q
+	nq.Hash = copyBytes(q.Hash)
+	if q.Limits != nil {
+		nq.Limits = make([]*QuotaLimit, len(q.Limits))
+		for i, limit := range q.Limits {
+			nq.Limits[i] = limit.Copy()
+		}
+	}
+	return nq
+}
+
+// QuotaLimit describes the resource limit in a particular region.
+type QuotaLimit struct {
+	// Region is the region in which this limit has affect
+	Region string
+
+	// RegionLimit is the quota limit that applies to any allocation within a
+	// referencing namespace in the region. A value of zero is treated as
+	// unlimited and a negative value is treated as fully disallowed. Only
+	// the CPU, MemoryMB, MemoryMaxMB and Devices fields are supported; device
+	// limits are expressed as a device name and count.
+	RegionLimit *Resources
+
+	// Hash is the hash of the object and is used to make replication
+	// efficient, and to key the usage of the limit.
+	Hash []byte
+}
+
+// Validate returns an error if the quota limit is invalid.
+func (q *QuotaLimit) Validate() error {
+	var mErr multierror.Error
+
+	if q.Region == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("must specify a region"))
+	}
+
+	if q.RegionLimit == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("must specify a region limit"))
+		return mErr.ErrorOrNil()
+	}
+
+	r := q.RegionLimit
+	if r.Cores != 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("cores can not be limited, limit cpu instead"))
+	}
+	if r.DiskMB != 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("disk can not be limited"))
+	}
+	if len(r.Networks) != 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("networks can not be limited"))
+	}
+
+	devices := make(map[string]struct{}, len(r.Devices))
+	for _, d := range r.Devices {
+		if d == nil {
+			continue
+		}
+		if d.Name == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("device limit must specify a name"))
+			continue
+		}
+		if _, ok := devices[d.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("device %q has multiple limits", d.Name))
+		}
+		devices[d.Name] = struct{}{}
+		if len(d.Constraints) != 0 || len(d.Affinities) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("device %q limit can not have constraints or affinities", d.Name))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the quota limit.
+func (q *QuotaLimit) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	_, _ = hash.Write([]byte(q.Region))
+	if r := q.RegionLimit; r != nil {
+		_, _ = hash.Write([]byte(strconv.Itoa(r.CPU)))
+		_, _ = hash.Write([]byte(strconv.Itoa(r.MemoryMB)))
+		_, _ = hash.Write([]byte(strconv.Itoa(r.MemoryMaxMB)))
+		for _, d := range r.Devices {
+			_, _ = hash.Write([]byte(d.Name))
+			_, _ = hash.Write([]byte(strconv.FormatUint(d.Count, 10)))
+		}
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	q.Hash = hashVal
+	return hashVal
+}
+
+// UsageKey returns the key of the limit within the Used map of a quota
+// usage.
+func (q *QuotaLimit) UsageKey() string {
+	return base64.StdEncoding.EncodeToString(q.Hash)
+}
+
+func (q *QuotaLimit) Copy() *QuotaLimit {
+	if q == nil {
+		return nil
+	}
+
+	nq := new(QuotaLimit)
+	*nq = *q
+	nq.RegionLimit = q.RegionLimit.Copy()
+	nq.Hash = copyBytes(q.Hash)
+	return nq
+}
+
+// NewUsage returns a quota limit used to track the usage against this limit.
+// All its resources are zero, with an entry for each limited device.
+func (q *QuotaLimit) NewUsage() *QuotaLimit {
+	used := &QuotaLimit{
+		Region:      q.Region,
+		RegionLimit: &Resources{},
+		Hash:        copyBytes(q.Hash),
+	}
+	if q.RegionLimit != nil {
+		for _, d := range q.RegionLimit.Devices {
+			used.RegionLimit.Devices = append(used.RegionLimit.Devices, &RequestedDevice{Name: d.Name})
+		}
+	}
+	return used
+}
+
+// AddAllocation adds the resources of the allocation to the quota limit,
+// which is tracking usage.
+func (q *QuotaLimit) AddAllocation(alloc *Allocation) {
+	q.addAllocation(alloc, 1)
+}
+
+// SubtractAllocation subtracts the resources of the allocation from the quota
+// limit, which is tracking usage.
+func (q *QuotaLimit) SubtractAllocation(alloc *Allocation) {
+	q.addAllocation(alloc, -1)
+}
+
+func (q *QuotaLimit) addAllocation(alloc *Allocation, sign int) {
+	if alloc == nil {
+		return
+	}
+
+	r := q.RegionLimit
+	if alloc.AllocatedResources == nil {
+		// COMPAT: allocations created before 0.9 only have Resources
+		if alloc.Resources != nil {
+			r.CPU += sign * alloc.Resources.CPU
+			r.MemoryMB += sign * alloc.Resources.MemoryMB
+			r.MemoryMaxMB += sign * quotaMemoryMax(alloc.Resources.MemoryMB, alloc.Resources.MemoryMaxMB)
+		}
+		return
+	}
+
+	flattened := alloc.AllocatedResources.Comparable().Flattened
+	r.CPU += sign * int(flattened.Cpu.CpuShares)
+	r.MemoryMB += sign * int(flattened.Memory.MemoryMB)
+	r.MemoryMaxMB += sign * int(flattened.Memory.MemoryMaxMB)
+
+	for _, used := range r.Devices {
+		limitID := used.ID()
+		for _, task := range alloc.AllocatedResources.Tasks {
+			for _, device := range task.Devices {
+				if device.ID().Matches(limitID) {
+					used.Count = addQuotaDevices(used.Count, uint64(len(device.DeviceIDs)), sign)
+				}
+			}
+		}
+	}
+}
+
+// AddTaskGroup adds the resources requested by a single allocation of the
+// task group to the quota limit, which is tracking usage. A device request
+// is only accounted for against the device limits it is guaranteed to match,
+// as the device which will be allocated is not yet known.
+func (q *QuotaLimit) AddTaskGroup(tg *TaskGroup) {
+	resources := &AllocatedResources{
+		Tasks:          make(map[string]*AllocatedTaskResources, len(tg.Tasks)),
+		TaskLifecycles: make(map[string]*TaskLifecycleConfig, len(tg.Tasks)),
+	}
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		resources.Tasks[task.Name] = &AllocatedTaskResources{
+			Cpu: AllocatedCpuResources{
+				CpuShares: int64(task.Resources.CPU),
+			},
+			Memory: AllocatedMemoryResources{
+				MemoryMB:    int64(task.Resources.MemoryMB),
+				MemoryMaxMB: int64(task.Resources.MemoryMaxMB),
+			},
+		}
+		resources.TaskLifecycles[task.Name] = task.Lifecycle
+	}
+
+	flattened := resources.Comparable().Flattened
+	r := q.RegionLimit
+	r.CPU += int(flattened.Cpu.CpuShares)
+	r.MemoryMB += int(flattened.Memory.MemoryMB)
+	r.MemoryMaxMB += int(flattened.Memory.MemoryMaxMB)
+
+	for _, used := range r.Devices {
+		limitID := used.ID()
+		for _, task := range tg.Tasks {
+			if task.Resources == nil {
+				continue
+			}
+			for _, req := range task.Resources.Devices {
+				if req.ID().Matches(limitID) {
+					used.Count += req.Count
+				}
+			}
+		}
+	}
+}
+
+// AddPlan applies the change in usage caused by the plan to the quota limit,
+// which is tracking usage. The existing allocation with a given ID is looked
+// up using the passed function, so that stopped, preempted and updated
+// allocations are accounted for correctly. Only allocations in the namespace
+// of the planned job are accounted for.
+func (q *QuotaLimit) AddPlan(plan *Plan, existingFn func(allocID string) (*Allocation, error)) error {
+	if plan == nil || plan.Job == nil {
+		return nil
+	}
+
+	// removed tracks the existing allocations already subtracted, as an
+	// allocation may be both stopped and updated by a plan
+	removed := make(map[string]struct{})
+	remove := func(allocID string) error {
+		if _, ok := removed[allocID]; ok {
+			return nil
+		}
+		removed[allocID] = struct{}{}
+
+		existing, err := existingFn(allocID)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.Namespace == plan.Job.Namespace && !existing.TerminalStatus() {
+			q.SubtractAllocation(existing)
+		}
+		return nil
+	}
+
+	for _, allocs := range plan.NodeUpdate {
+		for _, alloc := range allocs {
+			if err := remove(alloc.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, allocs := range plan.NodePreemptions {
+		for _, alloc := range allocs {
+			if err := remove(alloc.ID); err != nil {
+				return err
+			}
+		}
+	}
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			if err := remove(alloc.ID); err != nil {
+				return err
+			}
+			if !alloc.TerminalStatus() {
+				q.AddAllocation(alloc)
+			}
+		}
+	}
+	return nil
+}
+
+// Superset returns whether the quota limit is a superset of the passed usage,
+// and if not the dimensions which are exhausted.
+func (q *QuotaLimit) Superset(used *QuotaLimit) (bool, []string) {
+	var exhausted []string
+	check := func(dimension string, limit, used int64) {
+		if quotaLimitExceeded(limit, used) {
+			exhausted = append(exhausted, fmt.Sprintf("%s exhausted (%d needed > %d limit)",
+				dimension, used, quotaLimitValue(limit)))
+		}
+	}
+
+	l, u := q.RegionLimit, used.RegionLimit
+	check("cpu", int64(l.CPU), int64(u.CPU))
+	check("memory", int64(l.MemoryMB), int64(u.MemoryMB))
+	check("memory_max", int64(l.MemoryMaxMB), int64(u.MemoryMaxMB))
+
+	for _, limit := range l.Devices {
+		var count uint64
+		for _, d := range u.Devices {
+			if d.Name == limit.Name {
+				count = d.Count
+			}
+		}
+		check(fmt.Sprintf("devices: %s", limit.Name), int64(limit.Count), int64(count))
+	}
+
+	sort.Strings(exhausted)
+	return len(exhausted) == 0, exhausted
+}
+
+// Exceeds returns whether the usage exceeds the quota limit in any dimension
+// in which it is greater than the base usage. It is used to determine if a
+// change in usage makes a quota limit which may already be exceeded worse.
+func (q *QuotaLimit) Exceeds(base, used *QuotaLimit) bool {
+	exceeds := func(limit, base, used int64) bool {
+		return used > base && quotaLimitExceeded(limit, used)
+	}
+
+	l, b, u := q.RegionLimit, base.RegionLimit, used.RegionLimit
+	if exceeds(int64(l.CPU), int64(b.CPU), int64(u.CPU)) ||
+		exceeds(int64(l.MemoryMB), int64(b.MemoryMB), int64(u.MemoryMB)) ||
+		exceeds(int64(l.MemoryMaxMB), int64(b.MemoryMaxMB), int64(u.MemoryMaxMB)) {
+		return true
+	}
+
+	deviceCount := func(r *Resources, name string) int64 {
+		for _, d := range r.Devices {
+			if d.Name == name {
+				return int64(d.Count)
+			}
+		}
+		return 0
+	}
+	for _, limit := range l.Devices {
+		if exceeds(int64(limit.Count), deviceCount(b, limit.Name), deviceCount(u, limit.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// quotaLimitExceeded returns whether the usage exceeds the limit value, where
+// a value of zero is unlimited and a negative value disallows any usage.
+func quotaLimitExceeded(limit, used int64) bool {
+	switch {
+	case limit == 0:
+		return false
+	case limit < 0:
+		return used > 0
+	default:
+		return used > limit
+	}
+}
+
+// quotaLimitValue returns the effective value of the limit for display.
+func quotaLimitValue(limit int64) int64 {
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// quotaMemoryMax returns the maximum memory usage of a workload, which is its
+// memory if it has no separate maximum.
+func quotaMemoryMax(memory, memoryMax int) int {
+	if memoryMax > memory {
+		return memoryMax
+	}
+	return memory
+}
+
+// addQuotaDevices adds or subtracts a number of devices from a device count
+// without underflowing.
+func addQuotaDevices(count, delta uint64, sign int) uint64 {
+	if sign > 0 {
+		return count + delta
+	}
+	if delta > count {
+		return 0
+	}
+	return count - delta
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	nb := make([]byte, len(b))
+	copy(nb, b)
+	return nb
+}
+
+// QuotaUsage is the resource usage of a quota specification within the
+// local region.
+type QuotaUsage struct {
+	// Name is the name of the quota specification
+	Name string
+
+	// Used is the usage of each limit of the quota specification which
+	// applies to the local region, keyed by the UsageKey of the limit.
+	Used map[string]*QuotaLimit
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NewQuotaUsage returns an empty usage of the quota specification in the
+// given region.
+func NewQuotaUsage(spec *QuotaSpec, region string) *QuotaUsage {
+	usage := &QuotaUsage{
+		Name: spec.Name,
+		Used: make(map[string]*QuotaLimit, 1),
+	}
+	if limit := spec.RegionLimit(region); limit != nil {
+		usage.Used[limit.UsageKey()] = limit.NewUsage()
+	}
+	return usage
+}
+
+func (q *QuotaUsage) Copy() *QuotaUsage {
+	if q == nil {
+		return nil
+	}
+
+	nq := new(QuotaUsage)
+	*nq = *q
+	if q.Used != nil {
+		nq.Used = make(map[string]*QuotaLimit, len(q.Used))
+		for k, v := range q.Used {
+			nq.Used[k] = v.Copy()
+		}
+	}
+	return nq
+}
+
+// QuotaSpecUpsertRequest is used to upsert a set of quota specifications
+type QuotaSpecUpsertRequest struct {
+	Quotas []*QuotaSpec
+	WriteRequest
+}
+
+// QuotaSpecDeleteRequest is used to delete a set of quota specifications
+type QuotaSpecDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// QuotaSpecListRequest is used to request a list of quota specifications
+type QuotaSpecListRequest struct {
+	QueryOptions
+}
+
+// QuotaSpecListResponse is used for a list request
+type QuotaSpecListResponse struct {
+	Quotas []*QuotaSpec
+	QueryMeta
+}
+
+// QuotaSpecSpecificRequest is used to query a specific quota specification
+// or its usage
+type QuotaSpecSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleQuotaSpecResponse is used to return a single quota specification
+type SingleQuotaSpecResponse struct {
+	Quota *QuotaSpec
+	QueryMeta
+}
+
+// QuotaSpecSetRequest is used to query a set of quota specifications
+type QuotaSpecSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// QuotaSpecSetResponse is used to return a set of quota specifications
+type QuotaSpecSetResponse struct {
+	Quotas map[string]*QuotaSpec
+	QueryMeta
+}
+
+// QuotaUsageListRequest is used to request a list of quota usages
+type QuotaUsageListRequest struct {
+	QueryOptions
+}
+
+// QuotaUsageListResponse is used for a list request
+type QuotaUsageListResponse struct {
+	Usages []*QuotaUsage
+	QueryMeta
+}
+
+// SingleQuotaUsageResponse is used to return a single quota usage
+type SingleQuotaUsageResponse struct {
+	Usage *QuotaUsage
+	QueryMeta
+}
@@ -0,0 +1,208 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func testQuotaSpec() *QuotaSpec {
+	return &QuotaSpec{
+		Name: "test",
+		Limits: []*QuotaLimit{
+			{
+				Region: "global",
+				RegionLimit: &Resources{
+					CPU:      1000,
+					MemoryMB: 1000,
+					Devices: []*RequestedDevice{
+						{Name: "nvidia/gpu", Count: 2},
+					},
+				},
+			},
+		},
+	}
+}
+
+func testQuotaAlloc(cpu, memory int64, gpus int) *Allocation {
+	alloc := &Allocation{
+		ID:            "alloc",
+		Namespace:     DefaultNamespace,
+		DesiredStatus: AllocDesiredStatusRun,
+		ClientStatus:  AllocClientStatusRunning,
+		AllocatedResources: &AllocatedResources{
+			Tasks: map[string]*AllocatedTaskResources{
+				"web": {
+					Cpu:    AllocatedCpuResources{CpuShares: cpu},
+					Memory: AllocatedMemoryResources{MemoryMB: memory},
+				},
+			},
+		},
+	}
+	if gpus > 0 {
+		device := &AllocatedDeviceResource{Vendor: "nvidia", Type: "gpu", Name: "1080ti"}
+		for i := 0; i < gpus; i++ {
+			device.DeviceIDs = append(device.DeviceIDs, string(rune('a'+i)))
+		}
+		alloc.AllocatedResources.Tasks["web"].Devices = []*AllocatedDeviceResource{device}
+	}
+	return alloc
+}
+
+func TestQuotaSpec_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := testQuotaSpec()
+	require.NoError(t, spec.Validate())
+
+	spec.Name = "*"
+	spec.Limits = append(spec.Limits, &QuotaLimit{Region: "global"})
+	spec.Limits[0].RegionLimit.DiskMB = 100
+	err := spec.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid name")
+	require.Contains(t, err.Error(), "multiple limits")
+	require.Contains(t, err.Error(), "must specify a region limit")
+	require.Contains(t, err.Error(), "disk can not be limited")
+}
+
+func TestQuotaSpec_SetHash(t *testing.T) {
+	ci.Parallel(t)
+
+	spec := testQuotaSpec()
+	out1 := spec.SetHash()
+	require.NotEmpty(t, out1)
+	require.Equal(t, out1, spec.Hash)
+
+	spec.Limits[0].RegionLimit.CPU = 2000
+	out2 := spec.SetHash()
+	require.NotEqual(t, out1, out2)
+}
+
+func TestQuotaLimit_Usage(t *testing.T) {
+	ci.Parallel(t)
+
+	limit := testQuotaSpec().Limits[0]
+	limit.SetHash()
+
+	used := limit.NewUsage()
+	used.AddAllocation(testQuotaAlloc(500, 256, 1))
+	used.AddAllocation(testQuotaAlloc(500, 256, 1))
+	require.Equal(t, 1000, used.RegionLimit.CPU)
+	require.Equal(t, 512, used.RegionLimit.MemoryMB)
+	require.Equal(t, 512, used.RegionLimit.MemoryMaxMB)
+	require.Equal(t, uint64(2), used.RegionLimit.Devices[0].Count)
+
+	ok, dims := limit.Superset(used)
+	require.True(t, ok)
+	require.Empty(t, dims)
+
+	// Exceed the cpu and device limits
+	used.AddAllocation(testQuotaAlloc(100, 0, 1))
+	ok, dims = limit.Superset(used)
+	require.False(t, ok)
+	require.Equal(t, []string{
+		"cpu exhausted (1100 needed > 1000 limit)",
+		"devices: nvidia/gpu exhausted (3 needed > 2 limit)",
+	}, dims)
+
+	used.SubtractAllocation(testQuotaAlloc(100, 0, 1))
+	ok, _ = limit.Superset(used)
+	require.True(t, ok)
+
+	// A negative limit disallows any usage
+	limit.RegionLimit.MemoryMB = -1
+	ok, dims = limit.Superset(used)
+	require.False(t, ok)
+	require.Equal(t, []string{"memory exhausted (512 needed > 0 limit)"}, dims)
+}
+
+func TestQuotaLimit_AddPlan(t *testing.T) {
+	ci.Parallel(t)
+
+	limit := testQuotaSpec().Limits[0]
+	limit.SetHash()
+
+	stopped := testQuotaAlloc(500, 256, 0)
+	stopped.ID = "stopped"
+	updated := testQuotaAlloc(200, 256, 0)
+	updated.ID = "updated"
+	existing := map[string]*Allocation{
+		stopped.ID: stopped,
+		updated.ID: updated,
+	}
+	existingFn := func(id string) (*Allocation, error) {
+		return existing[id], nil
+	}
+
+	base := limit.NewUsage()
+	base.AddAllocation(stopped)
+	base.AddAllocation(updated)
+
+	placed := testQuotaAlloc(100, 128, 0)
+	placed.ID = "placed"
+	inplace := testQuotaAlloc(400, 256, 0)
+	inplace.ID = updated.ID
+	plan := &Plan{
+		Job: &Job{Namespace: DefaultNamespace},
+		NodeUpdate: map[string][]*Allocation{
+			"node": {{ID: stopped.ID}},
+		},
+		NodeAllocation: map[string][]*Allocation{
+			"node": {placed, inplace},
+		},
+	}
+
+	proposed := base.Copy()
+	require.NoError(t, proposed.AddPlan(plan, existingFn))
+	require.Equal(t, 500, proposed.RegionLimit.CPU)
+	require.Equal(t, 384, proposed.RegionLimit.MemoryMB)
+
+	// The plan reduces usage, so doesn't exceed the limit even if the limit
+	// is lowered below the usage
+	limit.RegionLimit.CPU = 100
+	require.False(t, limit.Exceeds(base, proposed))
+
+	// Increasing usage of an exhausted dimension exceeds the limit
+	plan.NodeUpdate = nil
+	proposed = base.Copy()
+	require.NoError(t, proposed.AddPlan(plan, existingFn))
+	require.True(t, limit.Exceeds(base, proposed))
+}
+
+func TestQuotaLimit_AddTaskGroup(t *testing.T) {
+	ci.Parallel(t)
+
+	limit := testQuotaSpec().Limits[0]
+	used := limit.NewUsage()
+
+	tg := &TaskGroup{
+		Tasks: []*Task{
+			{
+				Name: "web",
+				Resources: &Resources{
+					CPU:         500,
+					MemoryMB:    256,
+					MemoryMaxMB: 512,
+					Devices:     []*RequestedDevice{{Name: "nvidia/gpu/1080ti", Count: 1}},
+				},
+			},
+			{
+				Name: "init",
+				Resources: &Resources{
+					CPU:      100,
+					MemoryMB: 128,
+				},
+				Lifecycle: &TaskLifecycleConfig{Hook: TaskLifecycleHookPrestart},
+			},
+		},
+	}
+	used.AddTaskGroup(tg)
+
+	// The ephemeral prestart task doesn't run alongside the main task
+	require.Equal(t, 500, used.RegionLimit.CPU)
+	require.Equal(t, 256, used.RegionLimit.MemoryMB)
+	require.Equal(t, 512, used.RegionLimit.MemoryMaxMB)
+	require.Equal(t, uint64(1), used.RegionLimit.Devices[0].Count)
+}
@@ -85,6 +85,11 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Checks contains the current status of the health checks defined within
+	// the service block. The checks are executed by the client running the
+	// allocation, which updates the registration whenever a status changes.
+	Checks []*ServiceRegistrationCheck
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -100,6 +105,13 @@ func (s *ServiceRegistration) Copy() *ServiceRegistration {
 	*ns = *s
 	ns.Tags = helper.CopySliceString(ns.Tags)
 
+	if s.Checks != nil {
+		ns.Checks = make([]*ServiceRegistrationCheck, len(s.Checks))
+		for i, c := range s.Checks {
+			ns.Checks[i] = c.Copy()
+		}
+	}
+
 	return ns
 }
 
@@ -139,6 +151,26 @@ func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
 	if !helper.CompareSliceSetString(s.Tags, o.Tags) {
 		return false
 	}
+	if len(s.Checks) != len(o.Checks) {
+		return false
+	}
+	for i := range s.Checks {
+		if !s.Checks[i].Equals(o.Checks[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ChecksPassing returns whether all the health checks of the service
+// registration are passing. A registration without checks is always
+// considered passing.
+func (s *ServiceRegistration) ChecksPassing() bool {
+	for _, c := range s.Checks {
+		if c.Status != ServiceCheckStatusPassing {
+			return false
+		}
+	}
 	return true
 }
 
@@ -153,6 +185,54 @@ func (s *ServiceRegistration) Validate() error {
 	return nil
 }
 
+// ServiceRegistrationCheck is the status of an individual health check which
+// is part of a service registration using the Nomad provider.
+type ServiceRegistrationCheck struct {
+
+	// ID is the unique identifier for this check. It follows the Consul check
+	// ID format, so script checks can be updated in the same manner
+	// regardless of provider.
+	ID string
+
+	// Name and Type are copied from the ServiceCheck which defined the check.
+	Name string
+	Type string
+
+	// Status is the current status of the check and is one of the
+	// ServiceCheckStatus constants.
+	Status string
+
+	// Output is the output of the latest check execution, such as the HTTP
+	// response or script stdout.
+	Output string
+}
+
+const (
+	ServiceCheckStatusPassing  = "passing"
+	ServiceCheckStatusWarning  = "warning"
+	ServiceCheckStatusCritical = "critical"
+)
+
+// Copy creates a copy of the service registration check. It handles nil
+// objects.
+func (c *ServiceRegistrationCheck) Copy() *ServiceRegistrationCheck {
+	if c == nil {
+		return nil
+	}
+	nc := new(ServiceRegistrationCheck)
+	*nc = *c
+	return nc
+}
+
+// Equals performs an equality check on the two service registration checks.
+// It handles nil objects.
+func (c *ServiceRegistrationCheck) Equals(o *ServiceRegistrationCheck) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	return *c == *o
+}
+
 // GetID is a helper for getting the ID when the object may be nil and is
 // required for pagination.
 func (s *ServiceRegistration) GetID() string {
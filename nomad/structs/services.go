@@ -655,11 +655,34 @@ func (s *Service) validateConsulService(mErr *multierror.Error) {
 // nomad provider.
 func (s *Service) validateNomadService(mErr *multierror.Error) {
 
-	// Service blocks for the Nomad provider do not support checks. We perform
-	// a nil check, as an empty check list is nilled within the service
-	// canonicalize function.
-	if s.Checks != nil {
-		mErr.Errors = append(mErr.Errors, errors.New("Service with provider nomad cannot include Check blocks"))
+	// Checks for the Nomad provider are executed by the client, which supports
+	// a subset of the check types available when using Consul.
+	for _, c := range s.Checks {
+		switch c.Type {
+		case ServiceCheckHTTP, ServiceCheckTCP, ServiceCheckScript:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: type %q is not supported by provider nomad", c.Name, c.Type))
+			continue
+		}
+
+		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: check requires a port but neither check nor service %+q have a port", c.Name, s.Name))
+			continue
+		}
+
+		if c.Expose {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: expose is not supported by provider nomad", c.Name))
+			continue
+		}
+
+		if c.CheckRestart != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: check_restart is not supported by provider nomad", c.Name))
+			continue
+		}
+
+		if err := c.validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: %v", c.Name, err))
+		}
 	}
 
 	// Services using the Nomad provider do not support Consul connect.
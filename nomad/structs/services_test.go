@@ -1541,12 +1541,13 @@ func TestService_Validate(t *testing.T) {
 				Checks: []*ServiceCheck{
 					{
 						Name: "servicecheck",
+						Type: "grpc",
 					},
 				},
 			},
 			expErr:    true,
-			expErrStr: "Service with provider nomad cannot include Check blocks",
-			name:      "provider nomad with checks",
+			expErrStr: `Check servicecheck invalid: type "grpc" is not supported by provider nomad`,
+			name:      "provider nomad with grpc check",
 		},
 		{
 			input: &Service{
@@ -1691,12 +1692,56 @@ func TestService_validateNomadService(t *testing.T) {
 				Namespace: "default",
 				Provider:  "nomad",
 				Checks: []*ServiceCheck{
-					{Name: "some-check"},
+					{
+						Name:     "some-check",
+						Type:     "http",
+						Path:     "/health",
+						Interval: 10 * time.Second,
+						Timeout:  2 * time.Second,
+					},
+					{
+						Name:     "some-script",
+						Type:     "script",
+						Command:  "/bin/true",
+						Interval: 10 * time.Second,
+						Timeout:  2 * time.Second,
+					},
 				},
 			},
 			inputErr:             &multierror.Error{},
-			expectedOutputErrors: []error{errors.New("Service with provider nomad cannot include Check blocks")},
-			name:                 "invalid service due to checks",
+			expectedOutputErrors: []error{},
+			name:                 "valid service with checks",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				PortLabel: "http",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{
+					{Name: "some-check", Type: "grpc"},
+				},
+			},
+			inputErr: &multierror.Error{},
+			expectedOutputErrors: []error{
+				errors.New(`Check some-check invalid: type "grpc" is not supported by provider nomad`),
+			},
+			name: "invalid service due to grpc check",
+		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{
+					{Name: "some-check", Type: "tcp"},
+				},
+			},
+			inputErr: &multierror.Error{},
+			expectedOutputErrors: []error{
+				errors.New(`Check some-check invalid: check requires a port but neither check nor service "webapp" have a port`),
+			},
+			name: "invalid service due to check without port",
 		},
 		{
 			inputService: &Service{
@@ -1727,7 +1772,7 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New("Service with provider nomad cannot include Check blocks"),
+				errors.New(`Check some-check invalid: type "" is not supported by provider nomad`),
 				errors.New("Service with provider nomad cannot include Connect blocks"),
 			},
 			name: "invalid service due to checks and connect",
@@ -16,12 +16,15 @@ import (
 	"hash/crc32"
 	"math"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/hashicorp/nomad/helper/escapingfs"
@@ -43,6 +46,7 @@ import (
 	psstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"github.com/miekg/dns"
 	"github.com/mitchellh/copystructure"
+	"github.com/ryanuber/go-glob"
 )
 
 var (
@@ -108,10 +112,16 @@ const (
 	ServiceRegistrationUpsertRequestType         MessageType = 47
 	ServiceRegistrationDeleteByIDRequestType     MessageType = 48
 	ServiceRegistrationDeleteByNodeIDRequestType MessageType = 49
+	CSIVolumeUpdateStatsRequestType              MessageType = 50
+	NodeMaintenanceRequestType                   MessageType = 51
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	// Quota types were moved from enterprise and follow the namespace types
+	QuotaSpecUpsertRequestType MessageType = 66
+	QuotaSpecDeleteRequestType MessageType = 67
 )
 
 const (
@@ -519,6 +529,38 @@ type NodeUpdateDrainRequest struct {
 	WriteRequest
 }
 
+// NodeMaintenanceRequest is used to update the maintenance windows and
+// capacity reservations of a node or of a node class.
+type NodeMaintenanceRequest struct {
+	// NodeID or NodeClass select the nodes to update. Updates of a node class
+	// are also stored for the class, and apply to the nodes that join it
+	// later.
+	NodeID    string
+	NodeClass string
+
+	// NodeIDs are the nodes selected by NodeID or NodeClass, resolved by the
+	// server before the update is applied
+	NodeIDs []string
+
+	// Windows and Reservations are added to the nodes, replacing those with
+	// the same ID
+	Windows      []*NodeMaintenanceWindow
+	Reservations []*NodeCapacityReservation
+
+	// DeleteWindows and DeleteReservations are the IDs of the windows and
+	// reservations to remove from the nodes
+	DeleteWindows      []string
+	DeleteReservations []string
+
+	// NodeEvent is the event added to the nodes
+	NodeEvent *NodeEvent
+
+	// UpdatedAt represents server time of receiving request
+	UpdatedAt int64
+
+	WriteRequest
+}
+
 // BatchNodeUpdateDrainRequest is used for updating the drain strategy for a
 // batch of nodes
 type BatchNodeUpdateDrainRequest struct {
@@ -1159,6 +1201,10 @@ type DeploymentPromoteRequest struct {
 	// Groups is used to set the promotion status per task group
 	Groups []string
 
+	// Step is used to promote the canaries of the task groups if they are
+	// not promoted yet, or otherwise their current rollout step
+	Step bool
+
 	WriteRequest
 }
 
@@ -1350,6 +1396,14 @@ type NodeEligibilityUpdateResponse struct {
 	WriteMeta
 }
 
+// NodeMaintenanceResponse is used to respond to a maintenance update
+type NodeMaintenanceResponse struct {
+	// NodeIDs are the nodes that were updated
+	NodeIDs         []string
+	NodeModifyIndex uint64
+	WriteMeta
+}
+
 // NodeAllocsResponse is used to return allocs for a single node
 type NodeAllocsResponse struct {
 	Allocs []*Allocation
@@ -1557,6 +1611,41 @@ type EvalListResponse struct {
 	QueryMeta
 }
 
+// EvalQueuesResponse is used to return the fair-share queues of the
+// evaluation broker
+type EvalQueuesResponse struct {
+	// Enabled is whether evaluations are dequeued by fair share
+	Enabled bool
+	Queues  []*EvalQueueStats
+	QueryMeta
+}
+
+// EvalQueueStats is the share and backlog of a fair-share queue
+type EvalQueueStats struct {
+	// Name is the namespace or job meta value of the queue
+	Name string
+
+	// Weight is the configured weight of the queue
+	Weight int
+
+	// Share is the fraction of evaluations dequeued from this queue while
+	// the queues with a backlog remain backlogged
+	Share float64
+
+	// Ready is the number of evaluations waiting to be dequeued
+	Ready int
+
+	// Unacked is the number of evaluations being processed by schedulers
+	Unacked int
+
+	// Blocked is the number of evaluations blocked on capacity
+	Blocked int
+
+	// Dequeued is the number of evaluations dequeued from this queue since
+	// the server became leader
+	Dequeued uint64
+}
+
 // EvalAllocationsResponse is used to return the allocations for an evaluation
 type EvalAllocationsResponse struct {
 	Allocations []*AllocListStub
@@ -1628,11 +1717,14 @@ type EmitNodeEventsResponse struct {
 }
 
 const (
-	NodeEventSubsystemDrain     = "Drain"
-	NodeEventSubsystemDriver    = "Driver"
-	NodeEventSubsystemHeartbeat = "Heartbeat"
-	NodeEventSubsystemCluster   = "Cluster"
-	NodeEventSubsystemStorage   = "Storage"
+	NodeEventSubsystemDrain       = "Drain"
+	NodeEventSubsystemDriver      = "Driver"
+	NodeEventSubsystemHeartbeat   = "Heartbeat"
+	NodeEventSubsystemCluster     = "Cluster"
+	NodeEventSubsystemStorage     = "Storage"
+	NodeEventSubsystemRebalancer  = "Rebalancer"
+	NodeEventSubsystemMaintenance = "Maintenance"
+	NodeEventSubsystemPortForward = "PortForward"
 )
 
 // NodeEvent is a single unit representing a node’s state change
@@ -1847,6 +1939,181 @@ func (m *DrainMetadata) Copy() *DrainMetadata {
 	return c
 }
 
+const (
+	// NodeMaintenanceWindowPending is the status of a maintenance window
+	// that hasn't started
+	NodeMaintenanceWindowPending = "pending"
+
+	// NodeMaintenanceWindowActive is the status of a maintenance window
+	// whose drain was started
+	NodeMaintenanceWindowActive = "active"
+
+	// DefaultNodeMaintenanceDeadline is the default deadline of the drain
+	// started by a maintenance window
+	DefaultNodeMaintenanceDeadline = time.Hour
+)
+
+// NodeMaintenanceWindow is a scheduled maintenance of a node. The scheduler
+// avoids placing service jobs on nodes with a maintenance window, the node is
+// drained at the start of the window and marked as eligible at its end.
+type NodeMaintenanceWindow struct {
+	// ID uniquely identifies the window. Windows declared for a node class
+	// share their ID on all of its nodes.
+	ID string
+
+	// Start and End bound the window
+	Start time.Time
+	End   time.Time
+
+	// Deadline is the deadline of the drain started at the start of the
+	// window, after which its allocations are forced off the node
+	Deadline time.Duration
+
+	// IgnoreSystemJobs leaves system jobs running during the drain
+	IgnoreSystemJobs bool
+
+	// Description is the operator provided reason for the maintenance
+	Description string
+
+	// Status is pending until the window starts and active afterwards
+	Status string
+
+	// DrainStarted is whether the window started the drain of the node,
+	// which is cancelled when the window ends
+	DrainStarted bool
+
+	// MarkEligible is whether the node was eligible when the window started
+	// its drain, in which case it's marked eligible when the window ends
+	MarkEligible bool
+}
+
+func (w *NodeMaintenanceWindow) Copy() *NodeMaintenanceWindow {
+	if w == nil {
+		return nil
+	}
+	nw := new(NodeMaintenanceWindow)
+	*nw = *w
+	return nw
+}
+
+func (w *NodeMaintenanceWindow) Validate() error {
+	var mErr multierror.Error
+	if w.Start.IsZero() {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing maintenance window start"))
+	}
+	if !w.End.After(w.Start) {
+		mErr.Errors = append(mErr.Errors, errors.New("Maintenance window must end after its start"))
+	}
+	if w.Deadline < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Maintenance window deadline must not be negative"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Started returns whether the window started at the given time.
+func (w *NodeMaintenanceWindow) Started(now time.Time) bool {
+	return !now.Before(w.Start)
+}
+
+// Ended returns whether the window ended at the given time.
+func (w *NodeMaintenanceWindow) Ended(now time.Time) bool {
+	return !now.Before(w.End)
+}
+
+// DrainStrategy returns the strategy of the drain started by the window.
+func (w *NodeMaintenanceWindow) DrainStrategy(now time.Time) *DrainStrategy {
+	return &DrainStrategy{
+		DrainSpec: DrainSpec{
+			Deadline:         w.Deadline,
+			IgnoreSystemJobs: w.IgnoreSystemJobs,
+		},
+		ForceDeadline: now.Add(w.Deadline),
+		StartedAt:     now,
+	}
+}
+
+// NodeCapacityReservation holds resources of a node for an upcoming job
+// until it expires. The resources are unavailable to other jobs, less those
+// used by the allocations of the job on the node.
+type NodeCapacityReservation struct {
+	// ID uniquely identifies the reservation. Reservations declared for a
+	// node class share their ID on all of its nodes.
+	ID string
+
+	// Namespace and JobID identify the job the resources are held for
+	Namespace string
+	JobID     string
+
+	// CPU and MemoryMB are the reserved resources
+	CPU      int64
+	MemoryMB int64
+
+	// Expires is the time after which the resources are released
+	Expires time.Time
+
+	// Description is the operator provided reason for the reservation
+	Description string
+}
+
+func (r *NodeCapacityReservation) Copy() *NodeCapacityReservation {
+	if r == nil {
+		return nil
+	}
+	nr := new(NodeCapacityReservation)
+	*nr = *r
+	return nr
+}
+
+func (r *NodeCapacityReservation) Validate() error {
+	var mErr multierror.Error
+	if r.JobID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing capacity reservation job"))
+	}
+	if r.CPU < 0 || r.MemoryMB < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Capacity reservation resources must not be negative"))
+	} else if r.CPU == 0 && r.MemoryMB == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Capacity reservation must reserve cpu or memory"))
+	}
+	if r.Expires.IsZero() {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing capacity reservation expiry"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Expired returns whether the reservation expired at the given time.
+func (r *NodeCapacityReservation) Expired(now time.Time) bool {
+	return !now.Before(r.Expires)
+}
+
+// NodeClassMaintenance is the maintenance windows and capacity reservations
+// declared for a node class. They are added to the nodes of the class when
+// declared, and to the nodes that join the class until they end.
+type NodeClassMaintenance struct {
+	// NodeClass is the class of the nodes the maintenance applies to
+	NodeClass string
+
+	// MaintenanceWindows are the pending maintenance windows of the class
+	MaintenanceWindows []*NodeMaintenanceWindow
+
+	// CapacityReservations are the capacity reservations of the class
+	CapacityReservations []*NodeCapacityReservation
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (m *NodeClassMaintenance) Copy() *NodeClassMaintenance {
+	if m == nil {
+		return nil
+	}
+	nm := new(NodeClassMaintenance)
+	*nm = *m
+	nm.MaintenanceWindows = copyNodeMaintenanceWindows(m.MaintenanceWindows)
+	nm.CapacityReservations = copyNodeCapacityReservations(m.CapacityReservations)
+	return nm
+}
+
 // Node is a representation of a schedulable client node
 type Node struct {
 	// ID is a unique identifier for the node. It can be constructed
@@ -1957,6 +2224,12 @@ type Node struct {
 	// LastDrain contains metadata about the most recent drain operation
 	LastDrain *DrainMetadata
 
+	// MaintenanceWindows are the scheduled maintenance windows of the node
+	MaintenanceWindows []*NodeMaintenanceWindow
+
+	// CapacityReservations hold resources of the node for upcoming jobs
+	CapacityReservations []*NodeCapacityReservation
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -2049,9 +2322,39 @@ func (n *Node) Copy() *Node {
 	nn.HostVolumes = copyNodeHostVolumes(n.HostVolumes)
 	nn.HostNetworks = copyNodeHostNetworks(n.HostNetworks)
 	nn.LastDrain = nn.LastDrain.Copy()
+	nn.MaintenanceWindows = copyNodeMaintenanceWindows(n.MaintenanceWindows)
+	nn.CapacityReservations = copyNodeCapacityReservations(n.CapacityReservations)
 	return nn
 }
 
+// copyNodeMaintenanceWindows is a helper to copy a list of maintenance
+// windows
+func copyNodeMaintenanceWindows(windows []*NodeMaintenanceWindow) []*NodeMaintenanceWindow {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	c := make([]*NodeMaintenanceWindow, len(windows))
+	for i, w := range windows {
+		c[i] = w.Copy()
+	}
+	return c
+}
+
+// copyNodeCapacityReservations is a helper to copy a list of capacity
+// reservations
+func copyNodeCapacityReservations(reservations []*NodeCapacityReservation) []*NodeCapacityReservation {
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	c := make([]*NodeCapacityReservation, len(reservations))
+	for i, r := range reservations {
+		c[i] = r.Copy()
+	}
+	return c
+}
+
 // copyNodeEvents is a helper to copy a list of NodeEvent's
 func copyNodeEvents(events []*NodeEvent) []*NodeEvent {
 	l := len(events)
@@ -2276,6 +2579,9 @@ type Resources struct {
 	IOPS        int // COMPAT(0.10): Only being used to issue warnings
 	Networks    Networks
 	Devices     ResourceDevices
+	NUMA        *NUMA
+	DiskIO      *DiskIO
+	EgressMbits int
 }
 
 const (
@@ -2338,6 +2644,25 @@ func (r *Resources) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("MemoryMaxMB value (%d) should be larger than MemoryMB value (%d)", r.MemoryMaxMB, r.MemoryMB))
 	}
 
+	if r.DiskIO != nil {
+		if err := r.DiskIO.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
+	if r.EgressMbits < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("EgressMbits value (%d) must not be negative", r.EgressMbits))
+	}
+
+	if r.NUMA != nil {
+		if r.Cores == 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("Task can only ask for 'numa' affinity along with 'cores' resource."))
+		}
+		if err := r.NUMA.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -2365,6 +2690,15 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Devices) != 0 {
 		r.Devices = other.Devices
 	}
+	if other.NUMA != nil {
+		r.NUMA = other.NUMA
+	}
+	if other.DiskIO != nil {
+		r.DiskIO = other.DiskIO
+	}
+	if other.EgressMbits != 0 {
+		r.EgressMbits = other.EgressMbits
+	}
 }
 
 // Equals Resources.
@@ -2384,7 +2718,10 @@ func (r *Resources) Equals(o *Resources) bool {
 		r.DiskMB == o.DiskMB &&
 		r.IOPS == o.IOPS &&
 		r.Networks.Equals(&o.Networks) &&
-		r.Devices.Equals(&o.Devices)
+		r.Devices.Equals(&o.Devices) &&
+		r.NUMA.Equals(o.NUMA) &&
+		r.DiskIO.Equals(o.DiskIO) &&
+		r.EgressMbits == o.EgressMbits
 }
 
 // ResourceDevices are part of Resources.
@@ -2434,6 +2771,10 @@ func (r *Resources) Canonicalize() {
 	for _, n := range r.Networks {
 		n.Canonicalize()
 	}
+
+	if r.NUMA != nil {
+		r.NUMA.Canonicalize()
+	}
 }
 
 // MeetsMinResources returns an error if the resources specified are less than
@@ -2472,6 +2813,9 @@ func (r *Resources) Copy() *Resources {
 		}
 	}
 
+	newR.NUMA = r.NUMA.Copy()
+	newR.DiskIO = r.DiskIO.Copy()
+
 	return newR
 }
 
@@ -2516,6 +2860,98 @@ func (r *Resources) GoString() string {
 	return fmt.Sprintf("*%#v", *r)
 }
 
+const (
+	// NUMAAffinityNone places the reserved cores of a task without regard for
+	// the NUMA topology of the node.
+	NUMAAffinityNone = "none"
+
+	// NUMAAffinityPrefer places the reserved cores of a task on a single NUMA
+	// node when possible, but spreads them across NUMA nodes otherwise.
+	NUMAAffinityPrefer = "prefer"
+
+	// NUMAAffinityRequire places the reserved cores of a task on a single NUMA
+	// node and fails placement on nodes where that isn't possible.
+	NUMAAffinityRequire = "require"
+)
+
+// NUMA configures how the reserved cores of a task are placed with regard to
+// the NUMA topology of the node.
+type NUMA struct {
+	// Affinity is one of "none", "prefer" or "require".
+	Affinity string
+}
+
+func (n *NUMA) Copy() *NUMA {
+	if n == nil {
+		return nil
+	}
+	nn := *n
+	return &nn
+}
+
+func (n *NUMA) Equals(o *NUMA) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.Affinity == o.Affinity
+}
+
+func (n *NUMA) Canonicalize() {
+	if n.Affinity == "" {
+		n.Affinity = NUMAAffinityPrefer
+	}
+}
+
+func (n *NUMA) Validate() error {
+	switch n.Affinity {
+	case NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire:
+		return nil
+	default:
+		return fmt.Errorf("Unknown NUMA affinity %q; must be one of %q, %q or %q",
+			n.Affinity, NUMAAffinityNone, NUMAAffinityPrefer, NUMAAffinityRequire)
+	}
+}
+
+// DiskIO is the disk IO a task requires of the disk backing the allocation
+// directory of the node.
+type DiskIO struct {
+	ReadIOPS  int
+	WriteIOPS int
+	ReadMBps  int
+	WriteMBps int
+}
+
+func (d *DiskIO) Copy() *DiskIO {
+	if d == nil {
+		return nil
+	}
+	nd := *d
+	return &nd
+}
+
+func (d *DiskIO) Equals(o *DiskIO) bool {
+	if d == nil || o == nil {
+		return d == o
+	}
+	return *d == *o
+}
+
+func (d *DiskIO) Validate() error {
+	if d.ReadIOPS < 0 || d.WriteIOPS < 0 || d.ReadMBps < 0 || d.WriteMBps < 0 {
+		return errors.New("Disk IO values must not be negative")
+	}
+	return nil
+}
+
+// NUMAAffinity returns the NUMA affinity of the reserved cores, which defaults
+// to preferring a single NUMA node.
+func (r *Resources) NUMAAffinity() string {
+	if r.NUMA == nil || r.NUMA.Affinity == "" {
+		return NUMAAffinityPrefer
+	}
+	return r.NUMA.Affinity
+}
+
 // NodeNetworkResource is used to describe a fingerprinted network of a node
 type NodeNetworkResource struct {
 	Mode string // host for physical networks, cni/<name> for cni networks
@@ -2870,7 +3306,7 @@ func (r *RequestedDevice) Validate() error {
 	for idx, constr := range r.Constraints {
 		// Ensure that the constraint doesn't use an operand we do not allow
 		switch constr.Operand {
-		case ConstraintDistinctHosts, ConstraintDistinctProperty:
+		case ConstraintDistinctHosts, ConstraintDistinctProperty, ConstraintJobAffinity, ConstraintJobAntiAffinity:
 			outer := fmt.Errorf("Constraint %d validation failed: using unsupported operand %q", idx+1, constr.Operand)
 			_ = multierror.Append(&mErr, outer)
 		default:
@@ -2881,7 +3317,10 @@ func (r *RequestedDevice) Validate() error {
 		}
 	}
 	for idx, affinity := range r.Affinities {
-		if err := affinity.Validate(); err != nil {
+		if affinity.Operand == ConstraintJobAffinity {
+			outer := fmt.Errorf("Affinity %d validation failed: using unsupported operand %q", idx+1, affinity.Operand)
+			_ = multierror.Append(&mErr, outer)
+		} else if err := affinity.Validate(); err != nil {
 			outer := fmt.Errorf("Affinity %d validation failed: %s", idx+1, err)
 			_ = multierror.Append(&mErr, outer)
 		}
@@ -2898,6 +3337,7 @@ type NodeResources struct {
 	Networks     Networks
 	NodeNetworks []*NodeNetworkResource
 	Devices      []*NodeDeviceResource
+	Bandwidth    NodeBandwidthResources
 
 	MinDynamicPort int
 	MaxDynamicPort int
@@ -2965,6 +3405,7 @@ func (n *NodeResources) Merge(o *NodeResources) {
 	n.Cpu.Merge(&o.Cpu)
 	n.Memory.Merge(&o.Memory)
 	n.Disk.Merge(&o.Disk)
+	n.Bandwidth.Merge(&o.Bandwidth)
 
 	if len(o.Networks) != 0 {
 		n.Networks = append(n.Networks, o.Networks...)
@@ -3012,6 +3453,9 @@ func (n *NodeResources) Equals(o *NodeResources) bool {
 	if !n.Disk.Equals(&o.Disk) {
 		return false
 	}
+	if n.Bandwidth != o.Bandwidth {
+		return false
+	}
 	if !n.Networks.Equals(&o.Networks) {
 		return false
 	}
@@ -3102,6 +3546,11 @@ type NodeCpuResources struct {
 	// This value is currently only reported on Linux platforms which support cgroups and is
 	// discovered by inspecting the cpuset of the agent's cgroup.
 	ReservableCpuCores []uint16
+
+	// NumaNodes is the NUMA topology of the cpus. This value is currently only
+	// reported on Linux platforms and is empty if the topology couldn't be
+	// discovered.
+	NumaNodes []*NodeNumaNode
 }
 
 func (n NodeCpuResources) Copy() NodeCpuResources {
@@ -3110,6 +3559,12 @@ func (n NodeCpuResources) Copy() NodeCpuResources {
 		newN.ReservableCpuCores = make([]uint16, len(n.ReservableCpuCores))
 		copy(newN.ReservableCpuCores, n.ReservableCpuCores)
 	}
+	if n.NumaNodes != nil {
+		newN.NumaNodes = make([]*NodeNumaNode, len(n.NumaNodes))
+		for i, numa := range n.NumaNodes {
+			newN.NumaNodes[i] = numa.Copy()
+		}
+	}
 
 	return newN
 }
@@ -3130,6 +3585,10 @@ func (n *NodeCpuResources) Merge(o *NodeCpuResources) {
 	if len(o.ReservableCpuCores) != 0 {
 		n.ReservableCpuCores = o.ReservableCpuCores
 	}
+
+	if len(o.NumaNodes) != 0 {
+		n.NumaNodes = o.NumaNodes
+	}
 }
 
 func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
@@ -3157,6 +3616,15 @@ func (n *NodeCpuResources) Equals(o *NodeCpuResources) bool {
 			return false
 		}
 	}
+
+	if len(n.NumaNodes) != len(o.NumaNodes) {
+		return false
+	}
+	for i := range n.NumaNodes {
+		if !n.NumaNodes[i].Equals(o.NumaNodes[i]) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -3164,6 +3632,49 @@ func (n *NodeCpuResources) SharesPerCore() int64 {
 	return n.CpuShares / int64(n.TotalCpuCores)
 }
 
+// NodeNumaNode captures a NUMA node of the node's CPU topology.
+type NodeNumaNode struct {
+	// ID is the ID of the NUMA node, which is also the ID of its memory node.
+	ID uint16
+
+	// Cores is the set of cpus local to the NUMA node.
+	Cores []uint16
+
+	// Siblings groups the cpus of the NUMA node by physical core. Each group
+	// is the set of hyperthreads sharing a physical core.
+	Siblings [][]uint16
+
+	// MemoryMB is the memory local to the NUMA node.
+	MemoryMB int64
+}
+
+func (n *NodeNumaNode) Copy() *NodeNumaNode {
+	if n == nil {
+		return nil
+	}
+
+	newN := new(NodeNumaNode)
+	*newN = *n
+	newN.Cores = helper.CopySliceUint16(n.Cores)
+	if n.Siblings != nil {
+		newN.Siblings = make([][]uint16, len(n.Siblings))
+		for i, siblings := range n.Siblings {
+			newN.Siblings[i] = helper.CopySliceUint16(siblings)
+		}
+	}
+	return newN
+}
+
+func (n *NodeNumaNode) Equals(o *NodeNumaNode) bool {
+	if n == nil || o == nil {
+		return n == o
+	}
+	return n.ID == o.ID &&
+		n.MemoryMB == o.MemoryMB &&
+		reflect.DeepEqual(n.Cores, o.Cores) &&
+		reflect.DeepEqual(n.Siblings, o.Siblings)
+}
+
 // NodeMemoryResources captures the memory resources of the node
 type NodeMemoryResources struct {
 	// MemoryMB is the total available memory on the node
@@ -3200,6 +3711,15 @@ func (n *NodeMemoryResources) Equals(o *NodeMemoryResources) bool {
 type NodeDiskResources struct {
 	// DiskMB is the total available disk space on the node
 	DiskMB int64
+
+	// IOPS and MBps are the IO capacity of the disk backing the allocation
+	// directory, which is zero if not configured.
+	IOPS int64
+	MBps int64
+
+	// Device is the "major:minor" number of the disk backing the allocation
+	// directory, used to limit the disk IO of tasks.
+	Device string
 }
 
 func (n *NodeDiskResources) Merge(o *NodeDiskResources) {
@@ -3209,6 +3729,15 @@ func (n *NodeDiskResources) Merge(o *NodeDiskResources) {
 	if o.DiskMB != 0 {
 		n.DiskMB = o.DiskMB
 	}
+	if o.IOPS != 0 {
+		n.IOPS = o.IOPS
+	}
+	if o.MBps != 0 {
+		n.MBps = o.MBps
+	}
+	if o.Device != "" {
+		n.Device = o.Device
+	}
 }
 
 func (n *NodeDiskResources) Equals(o *NodeDiskResources) bool {
@@ -3224,9 +3753,29 @@ func (n *NodeDiskResources) Equals(o *NodeDiskResources) bool {
 		return false
 	}
 
+	if n.IOPS != o.IOPS || n.MBps != o.MBps || n.Device != o.Device {
+		return false
+	}
+
 	return true
 }
 
+// NodeBandwidthResources captures the network bandwidth of the node.
+type NodeBandwidthResources struct {
+	// EgressMbits is the egress bandwidth of the node, which is the link speed
+	// of its network interface.
+	EgressMbits int64
+}
+
+func (n *NodeBandwidthResources) Merge(o *NodeBandwidthResources) {
+	if o == nil {
+		return
+	}
+	if o.EgressMbits != 0 {
+		n.EgressMbits = o.EgressMbits
+	}
+}
+
 // DeviceIdTuple is the tuple that identifies a device
 type DeviceIdTuple struct {
 	Vendor string
@@ -3637,10 +4186,12 @@ func (a *AllocatedResources) Canonicalize() {
 
 // AllocatedTaskResources are the set of resources allocated to a task.
 type AllocatedTaskResources struct {
-	Cpu      AllocatedCpuResources
-	Memory   AllocatedMemoryResources
-	Networks Networks
-	Devices  []*AllocatedDeviceResource
+	Cpu       AllocatedCpuResources
+	Memory    AllocatedMemoryResources
+	Networks  Networks
+	Devices   []*AllocatedDeviceResource
+	DiskIO    AllocatedDiskIOResources
+	Bandwidth AllocatedBandwidthResources
 }
 
 func (a *AllocatedTaskResources) Copy() *AllocatedTaskResources {
@@ -3677,6 +4228,8 @@ func (a *AllocatedTaskResources) Add(delta *AllocatedTaskResources) {
 
 	a.Cpu.Add(&delta.Cpu)
 	a.Memory.Add(&delta.Memory)
+	a.DiskIO.Add(&delta.DiskIO)
+	a.Bandwidth.Add(&delta.Bandwidth)
 
 	for _, n := range delta.Networks {
 		// Find the matching interface by IP or CIDR
@@ -3706,6 +4259,8 @@ func (a *AllocatedTaskResources) Max(other *AllocatedTaskResources) {
 
 	a.Cpu.Max(&other.Cpu)
 	a.Memory.Max(&other.Memory)
+	a.DiskIO.Max(&other.DiskIO)
+	a.Bandwidth.Max(&other.Bandwidth)
 
 	for _, n := range other.Networks {
 		// Find the matching interface by IP or CIDR
@@ -3741,14 +4296,16 @@ func (a *AllocatedTaskResources) Comparable() *ComparableResources {
 				MemoryMB:    a.Memory.MemoryMB,
 				MemoryMaxMB: a.Memory.MemoryMaxMB,
 			},
+			DiskIO:    a.DiskIO,
+			Bandwidth: a.Bandwidth,
 		},
 	}
 	ret.Flattened.Networks = append(ret.Flattened.Networks, a.Networks...)
 	return ret
 }
 
-// Subtract only subtracts CPU and Memory resources. Network utilization
-// is managed separately in NetworkIndex
+// Subtract only subtracts CPU, Memory, disk IO and bandwidth resources.
+// Network utilization is managed separately in NetworkIndex
 func (a *AllocatedTaskResources) Subtract(delta *AllocatedTaskResources) {
 	if delta == nil {
 		return
@@ -3756,6 +4313,8 @@ func (a *AllocatedTaskResources) Subtract(delta *AllocatedTaskResources) {
 
 	a.Cpu.Subtract(&delta.Cpu)
 	a.Memory.Subtract(&delta.Memory)
+	a.DiskIO.Subtract(&delta.DiskIO)
+	a.Bandwidth.Subtract(&delta.Bandwidth)
 }
 
 // AllocatedSharedResources are the set of resources allocated to a task group.
@@ -3902,6 +4461,110 @@ func (a *AllocatedMemoryResources) Max(other *AllocatedMemoryResources) {
 	}
 }
 
+// AllocatedDiskIOResources captures the allocated disk IO resources.
+type AllocatedDiskIOResources struct {
+	// Device is the "major:minor" number of the disk the IO is allocated on.
+	Device string
+
+	ReadIOPS  int64
+	WriteIOPS int64
+	ReadMBps  int64
+	WriteMBps int64
+}
+
+// IOPS returns the total allocated IOPS.
+func (a *AllocatedDiskIOResources) IOPS() int64 {
+	return a.ReadIOPS + a.WriteIOPS
+}
+
+// MBps returns the total allocated throughput.
+func (a *AllocatedDiskIOResources) MBps() int64 {
+	return a.ReadMBps + a.WriteMBps
+}
+
+// IsZero returns whether no disk IO is allocated.
+func (a *AllocatedDiskIOResources) IsZero() bool {
+	return a.IOPS() == 0 && a.MBps() == 0
+}
+
+func (a *AllocatedDiskIOResources) Add(delta *AllocatedDiskIOResources) {
+	if delta == nil {
+		return
+	}
+
+	if a.Device == "" {
+		a.Device = delta.Device
+	}
+	a.ReadIOPS += delta.ReadIOPS
+	a.WriteIOPS += delta.WriteIOPS
+	a.ReadMBps += delta.ReadMBps
+	a.WriteMBps += delta.WriteMBps
+}
+
+func (a *AllocatedDiskIOResources) Subtract(delta *AllocatedDiskIOResources) {
+	if delta == nil {
+		return
+	}
+
+	a.ReadIOPS -= delta.ReadIOPS
+	a.WriteIOPS -= delta.WriteIOPS
+	a.ReadMBps -= delta.ReadMBps
+	a.WriteMBps -= delta.WriteMBps
+}
+
+func (a *AllocatedDiskIOResources) Max(other *AllocatedDiskIOResources) {
+	if other == nil {
+		return
+	}
+
+	if a.Device == "" {
+		a.Device = other.Device
+	}
+	if other.ReadIOPS > a.ReadIOPS {
+		a.ReadIOPS = other.ReadIOPS
+	}
+	if other.WriteIOPS > a.WriteIOPS {
+		a.WriteIOPS = other.WriteIOPS
+	}
+	if other.ReadMBps > a.ReadMBps {
+		a.ReadMBps = other.ReadMBps
+	}
+	if other.WriteMBps > a.WriteMBps {
+		a.WriteMBps = other.WriteMBps
+	}
+}
+
+// AllocatedBandwidthResources captures the allocated network bandwidth.
+type AllocatedBandwidthResources struct {
+	EgressMbits int64
+}
+
+func (a *AllocatedBandwidthResources) Add(delta *AllocatedBandwidthResources) {
+	if delta == nil {
+		return
+	}
+
+	a.EgressMbits += delta.EgressMbits
+}
+
+func (a *AllocatedBandwidthResources) Subtract(delta *AllocatedBandwidthResources) {
+	if delta == nil {
+		return
+	}
+
+	a.EgressMbits -= delta.EgressMbits
+}
+
+func (a *AllocatedBandwidthResources) Max(other *AllocatedBandwidthResources) {
+	if other == nil {
+		return
+	}
+
+	if other.EgressMbits > a.EgressMbits {
+		a.EgressMbits = other.EgressMbits
+	}
+}
+
 type AllocatedDevices []*AllocatedDeviceResource
 
 // Index finds the matching index using the passed device. If not found, -1 is
@@ -4110,6 +4773,14 @@ type Job struct {
 	// can slow down larger jobs if resources are not available.
 	AllAtOnce bool
 
+	// Gang is used to only place the job's allocations if all of the
+	// allocations of all of its task groups can be placed.
+	Gang bool
+
+	// DependsOn are the jobs that must complete before the job's allocations
+	// are placed.
+	DependsOn []*JobDependency
+
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
@@ -4274,6 +4945,10 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+
+	for _, d := range j.DependsOn {
+		d.Canonicalize()
+	}
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -4289,6 +4964,14 @@ func (j *Job) Copy() *Job {
 	nj.Affinities = CopySliceAffinities(nj.Affinities)
 	nj.Multiregion = nj.Multiregion.Copy()
 
+	if j.DependsOn != nil {
+		deps := make([]*JobDependency, len(j.DependsOn))
+		for i, d := range j.DependsOn {
+			deps[i] = d.Copy()
+		}
+		nj.DependsOn = deps
+	}
+
 	if j.TaskGroups != nil {
 		tgs := make([]*TaskGroup, len(nj.TaskGroups))
 		for i, tg := range nj.TaskGroups {
@@ -4366,6 +5049,30 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Gang && j.Type != JobTypeBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Gang scheduling is only supported for batch jobs"))
+	}
+
+	if len(j.DependsOn) > 0 {
+		if j.Type != JobTypeBatch && j.Type != JobTypeService {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Job dependencies are only supported for %q and %q jobs", JobTypeBatch, JobTypeService))
+		}
+		if j.IsPeriodic() || j.IsParameterized() {
+			mErr.Errors = append(mErr.Errors, errors.New("Periodic and parameterized jobs may not have dependencies"))
+		}
+		seen := make(map[string]struct{}, len(j.DependsOn))
+		for idx, d := range j.DependsOn {
+			if err := d.Validate(); err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d validation failed: %s", idx+1, err))
+			} else if d.JobID == j.ID {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d references the job itself", idx+1))
+			} else if _, ok := seen[d.JobID]; ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Dependency %d redefines job %q", idx+1, d.JobID))
+			}
+			seen[d.JobID] = struct{}{}
+		}
+	}
+
 	if j.Type == JobTypeSystem {
 		if j.Spreads != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a spread stanza"))
@@ -4887,6 +5594,15 @@ type UpdateStrategy struct {
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
+
+	// Analysis configures the analysis of the metrics of the canaries before
+	// they can be promoted.
+	Analysis *AnalysisStrategy
+
+	// Steps are the ordered steps of a progressive rollout. Once the canaries
+	// are promoted, each step only updates a percentage of the allocations
+	// before pausing.
+	Steps []*RolloutStep
 }
 
 func (u *UpdateStrategy) Copy() *UpdateStrategy {
@@ -4896,6 +5612,13 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 
 	copy := new(UpdateStrategy)
 	*copy = *u
+	copy.Analysis = u.Analysis.Copy()
+	if u.Steps != nil {
+		copy.Steps = make([]*RolloutStep, len(u.Steps))
+		for i, s := range u.Steps {
+			copy.Steps[i] = s.Copy()
+		}
+	}
 	return copy
 }
 
@@ -4938,22 +5661,261 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Stagger <= 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Stagger must be greater than zero: %v", u.Stagger))
 	}
+	if u.Analysis != nil {
+		if u.Canary == 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Analysis requires a Canary count greater than zero"))
+		}
+		if err := u.Analysis.Validate(); err != nil {
+			_ = multierror.Append(&mErr, err)
+		}
+	}
+	for i, s := range u.Steps {
+		if err := s.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Rollout step %d validation failed: %v", i+1, err))
+		}
+		if i > 0 && s.Percent <= u.Steps[i-1].Percent {
+			_ = multierror.Append(&mErr, fmt.Errorf("Rollout step %d percent must be greater than the previous step: %d <= %d", i+1, s.Percent, u.Steps[i-1].Percent))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+func (u *UpdateStrategy) IsEmpty() bool {
+	if u == nil {
+		return true
+	}
+
+	return u.MaxParallel == 0
+}
+
+// Rolling returns if a rolling strategy should be used.
+// TODO(alexdadgar): Remove once no longer used by the scheduler.
+func (u *UpdateStrategy) Rolling() bool {
+	return u.Stagger > 0 && u.MaxParallel > 0
+}
+
+const (
+	// AnalysisSourcePrometheus queries the metrics of the allocations from a
+	// Prometheus compatible HTTP API.
+	AnalysisSourcePrometheus = "prometheus"
+
+	// AnalysisSourceNomad uses the resource usage of the allocations reported
+	// by the Nomad clients.
+	AnalysisSourceNomad = "nomad"
+)
+
+const (
+	// AnalysisMetricCPUPercent, AnalysisMetricCPUThrottledTime,
+	// AnalysisMetricMemoryRSS and AnalysisMetricMemoryUsage are the metrics of
+	// the Nomad analysis source. Their value is averaged across allocations.
+	AnalysisMetricCPUPercent       = "cpu_percent"
+	AnalysisMetricCPUThrottledTime = "cpu_throttled_time"
+	AnalysisMetricMemoryRSS        = "memory_rss"
+	AnalysisMetricMemoryUsage      = "memory_usage"
+)
+
+// AnalysisStrategy configures the analysis of the canaries of a deployment.
+// While the canaries bake, the metrics of the canaries are periodically
+// queried and compared to their thresholds and to the metrics of the stable
+// allocations. The deployment fails if the canaries regress, and canaries can
+// only be auto-promoted once they passed the analysis for the whole bake
+// time.
+type AnalysisStrategy struct {
+	// Source is the source of the metrics.
+	Source string
+
+	// Address is the address of the Prometheus compatible HTTP API.
+	Address string
+
+	// Interval is the interval at which the metrics are queried.
+	Interval time.Duration
+
+	// BakeTime is how long the metrics of the canaries must pass the analysis,
+	// starting when all the canaries are healthy.
+	BakeTime time.Duration
+
+	// FailureLimit is the number of failed analyses tolerated before the
+	// deployment is failed.
+	FailureLimit int
+
+	// Metrics are the metrics to analyze.
+	Metrics []*AnalysisMetric
+}
+
+func (a *AnalysisStrategy) Copy() *AnalysisStrategy {
+	if a == nil {
+		return nil
+	}
+
+	na := new(AnalysisStrategy)
+	*na = *a
+	if a.Metrics != nil {
+		na.Metrics = make([]*AnalysisMetric, len(a.Metrics))
+		for i, m := range a.Metrics {
+			na.Metrics[i] = m.Copy()
+		}
+	}
+	return na
+}
+
+func (a *AnalysisStrategy) Validate() error {
+	var mErr multierror.Error
+	switch a.Source {
+	case AnalysisSourcePrometheus:
+		if a.Address == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Analysis address must be set for source %q", a.Source))
+		}
+	case AnalysisSourceNomad:
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid analysis source given: %q", a.Source))
+	}
+	if a.Interval <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Analysis interval must be greater than zero: %v", a.Interval))
+	}
+	if a.BakeTime < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Analysis bake time must be zero or greater: %v", a.BakeTime))
+	}
+	if a.FailureLimit < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Analysis failure limit can not be less than zero: %d < 0", a.FailureLimit))
+	}
+	if len(a.Metrics) == 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Analysis must have at least one metric"))
+	}
+
+	names := make(map[string]struct{}, len(a.Metrics))
+	for _, m := range a.Metrics {
+		if _, ok := names[m.Name]; ok {
+			_ = multierror.Append(&mErr, fmt.Errorf("Analysis metric %q defined more than once", m.Name))
+		}
+		names[m.Name] = struct{}{}
+
+		if err := m.Validate(a.Source); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Analysis metric %q validation failed: %v", m.Name, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// AnalysisMetric is a metric of an AnalysisStrategy.
+type AnalysisMetric struct {
+	// Name is the name of the metric.
+	Name string
+
+	// Query is the query of the metric. For the Prometheus source, it is a
+	// template of the query given the AllocIDs regular expression matching
+	// the analyzed allocations, and the Namespace, Job and Group of the
+	// deployment. For the Nomad source, it is the name of the metric.
+	Query string
+
+	// Max is the maximum value of the metric for the canaries.
+	Max *float64
+
+	// MaxIncrease is the maximum increase of the value of the metric for the
+	// canaries relative to the value for the stable allocations, such as 0.1
+	// for 10%.
+	MaxIncrease *float64
+}
+
+func (m *AnalysisMetric) Copy() *AnalysisMetric {
+	if m == nil {
+		return nil
+	}
+
+	nm := new(AnalysisMetric)
+	*nm = *m
+	if m.Max != nil {
+		nm.Max = helper.Float64ToPtr(*m.Max)
+	}
+	if m.MaxIncrease != nil {
+		nm.MaxIncrease = helper.Float64ToPtr(*m.MaxIncrease)
+	}
+	return nm
+}
+
+func (m *AnalysisMetric) Validate(source string) error {
+	var mErr multierror.Error
+	if m.Name == "" {
+		_ = multierror.Append(&mErr, fmt.Errorf("Missing name"))
+	}
+
+	switch source {
+	case AnalysisSourcePrometheus:
+		if _, err := m.QueryTemplate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Invalid query: %v", err))
+		}
+	case AnalysisSourceNomad:
+		switch m.Query {
+		case AnalysisMetricCPUPercent, AnalysisMetricCPUThrottledTime, AnalysisMetricMemoryRSS, AnalysisMetricMemoryUsage:
+		default:
+			_ = multierror.Append(&mErr, fmt.Errorf("Invalid query given: %q", m.Query))
+		}
+	}
+
+	if m.Max == nil && m.MaxIncrease == nil {
+		_ = multierror.Append(&mErr, fmt.Errorf("Either max or max increase must be set"))
+	}
+	if m.MaxIncrease != nil && *m.MaxIncrease < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max increase must be zero or greater: %v", *m.MaxIncrease))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// QueryTemplate parses the query of the metric as a template.
+func (m *AnalysisMetric) QueryTemplate() (*template.Template, error) {
+	if m.Query == "" {
+		return nil, fmt.Errorf("missing query")
+	}
+	return template.New(m.Name).Option("missingkey=error").Parse(m.Query)
+}
 
-	return mErr.ErrorOrNil()
+// RolloutStep is a step of a progressive rollout. A step updates the
+// allocations of the task group until the percentage of the step is reached
+// and the updated allocations are healthy. The rollout then moves on to the
+// next step after the pause, or once the step is manually promoted.
+type RolloutStep struct {
+	// Percent is the percentage of the allocations of the task group that
+	// are updated by the end of the step.
+	Percent int
+
+	// Pause is how long the rollout is held once the allocations of the step
+	// are healthy.
+	Pause time.Duration
+
+	// Manual marks that the step must be manually promoted for the rollout
+	// to continue.
+	Manual bool
 }
 
-func (u *UpdateStrategy) IsEmpty() bool {
-	if u == nil {
-		return true
+func (s *RolloutStep) Copy() *RolloutStep {
+	if s == nil {
+		return nil
 	}
+	copy := new(RolloutStep)
+	*copy = *s
+	return copy
+}
 
-	return u.MaxParallel == 0
+func (s *RolloutStep) Validate() error {
+	var mErr multierror.Error
+	if s.Percent <= 0 || s.Percent > 100 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Percent must be between 1 and 100: %d", s.Percent))
+	}
+	if s.Pause < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Pause must be zero or greater: %v", s.Pause))
+	}
+	if s.Manual && s.Pause != 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Pause can't be set on a manual step"))
+	}
+	return mErr.ErrorOrNil()
 }
 
-// Rolling returns if a rolling strategy should be used.
-// TODO(alexdadgar): Remove once no longer used by the scheduler.
-func (u *UpdateStrategy) Rolling() bool {
-	return u.Stagger > 0 && u.MaxParallel > 0
+// Target returns the number of allocations updated by the end of the step
+// out of the given total.
+func (s *RolloutStep) Target(total int) int {
+	return (total*s.Percent + 99) / 100
 }
 
 type Multiregion struct {
@@ -6130,6 +7092,10 @@ type TaskGroup struct {
 	// be scheduled.
 	Count int
 
+	// Gang is used to only place the task group's allocations if all of them
+	// can be placed.
+	Gang bool
+
 	// Update is used to control the update strategy for this task group
 	Update *UpdateStrategy
 
@@ -6323,6 +7289,10 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, errors.New("max_client_disconnect cannot be negative"))
 	}
 
+	if tg.Gang && j.Type != JobTypeBatch {
+		mErr.Errors = append(mErr.Errors, errors.New("Gang scheduling is only supported for batch jobs"))
+	}
+
 	for idx, constr := range tg.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -6854,6 +7824,10 @@ const (
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
+
+	// Sinks are destinations the task's logs are forwarded to in addition to
+	// the rotated log files.
+	Sinks []*LogSink
 }
 
 func (l *LogConfig) Equals(o *LogConfig) bool {
@@ -6869,6 +7843,15 @@ func (l *LogConfig) Equals(o *LogConfig) bool {
 		return false
 	}
 
+	if len(l.Sinks) != len(o.Sinks) {
+		return false
+	}
+	for i, sink := range l.Sinks {
+		if !sink.Equals(o.Sinks[i]) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -6876,10 +7859,17 @@ func (l *LogConfig) Copy() *LogConfig {
 	if l == nil {
 		return nil
 	}
-	return &LogConfig{
+	nl := &LogConfig{
 		MaxFiles:      l.MaxFiles,
 		MaxFileSizeMB: l.MaxFileSizeMB,
 	}
+	if l.Sinks != nil {
+		nl.Sinks = make([]*LogSink, len(l.Sinks))
+		for i, sink := range l.Sinks {
+			nl.Sinks[i] = sink.Copy()
+		}
+	}
+	return nl
 }
 
 // DefaultLogConfig returns the default LogConfig values.
@@ -6900,9 +7890,204 @@ func (l *LogConfig) Validate() error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+
+	names := make(map[string]struct{}, len(l.Sinks))
+	for idx, sink := range l.Sinks {
+		if err := sink.Validate(); err != nil {
+			outer := fmt.Errorf("Log sink %d validation failed: %v", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+		if _, ok := names[sink.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Log sink %q defined more than once", sink.Name))
+		}
+		names[sink.Name] = struct{}{}
+	}
+	return mErr.ErrorOrNil()
+}
+
+const (
+	// LogSinkTypeSyslog forwards log lines to a syslog server as RFC 5424
+	// messages.
+	LogSinkTypeSyslog = "syslog"
+
+	// LogSinkTypeTCP forwards log lines as JSON lines over TCP.
+	LogSinkTypeTCP = "tcp"
+
+	// LogSinkTypeUDP forwards log lines as JSON lines over UDP, one record
+	// per datagram.
+	LogSinkTypeUDP = "udp"
+
+	// LogSinkTypeUnix forwards log lines as JSON lines to a Unix socket.
+	LogSinkTypeUnix = "unix"
+)
+
+const (
+	// NodeAttrLogSinksPrefix prefixes the node attributes fingerprinting
+	// the log_sinks configuration of clients. The attribute of each sink
+	// type tasks may use on the client is set to true.
+	NodeAttrLogSinksPrefix = "nomad.log_sinks."
+
+	// NodeAttrLogSinksAllowedAddresses and NodeAttrLogSinksAllowedSockets
+	// are the comma separated addresses and socket paths allowed by the
+	// log_sinks configuration of clients.
+	NodeAttrLogSinksAllowedAddresses = NodeAttrLogSinksPrefix + "allowed_addresses"
+	NodeAttrLogSinksAllowedSockets   = NodeAttrLogSinksPrefix + "allowed_sockets"
+)
+
+var (
+	// validLogSinkSyslogFacilities are the syslog facilities a log sink may
+	// use.
+	validLogSinkSyslogFacilities = []string{
+		"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+		"uucp", "cron", "authpriv", "ftp", "local0", "local1", "local2",
+		"local3", "local4", "local5", "local6", "local7",
+	}
+)
+
+// LogSink is a destination logmon forwards a task's stdout and stderr to.
+type LogSink struct {
+	// Name uniquely identifies the sink within the task.
+	Name string
+
+	// Type is the kind of sink, one of syslog, tcp, udp or unix.
+	Type string
+
+	// Address is where log lines are sent. For tcp and udp sinks it is a
+	// host:port and for unix sinks the path of the socket. Syslog sinks
+	// take a URL such as udp://host:514, or send to the local syslog daemon
+	// if it is empty.
+	Address string
+
+	// Facility is the syslog facility of messages sent to syslog sinks.
+	Facility string
+
+	// Tag is the syslog APP-NAME of messages sent to syslog sinks.
+	Tag string
+
+	// BufferSize is the number of log lines buffered while the sink is
+	// unavailable. Log lines are dropped once the buffer is full.
+	BufferSize int
+}
+
+// DiffID fulfills the DiffableWithID interface.
+func (s *LogSink) DiffID() string {
+	return s.Name
+}
+
+func (s *LogSink) Equals(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return *s == *o
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	ns := new(LogSink)
+	*ns = *s
+	return ns
+}
+
+// Validate returns an error if the log sink is invalid.
+func (s *LogSink) Validate() error {
+	var mErr multierror.Error
+	if s.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing sink name"))
+	}
+	if s.BufferSize < 1 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum buffer size is 1; got %d", s.BufferSize))
+	}
+
+	switch s.Type {
+	case LogSinkTypeSyslog:
+		if s.Facility != "" && !helper.SliceStringContains(validLogSinkSyslogFacilities, s.Facility) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog facility %q", s.Facility))
+		}
+		if s.Address != "" {
+			u, err := url.Parse(s.Address)
+			if err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid syslog address %q: %v", s.Address, err))
+			} else if !helper.SliceStringContains([]string{"tcp", "udp", "unix", "unixgram"}, u.Scheme) {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("syslog address %q must use the tcp, udp, unix or unixgram scheme", s.Address))
+			}
+		}
+	case LogSinkTypeTCP, LogSinkTypeUDP:
+		if _, _, err := net.SplitHostPort(s.Address); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid %s address %q: %v", s.Type, s.Address, err))
+		}
+	case LogSinkTypeUnix:
+		if s.Address == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("Missing unix socket path"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid sink type %q", s.Type))
+	}
+
+	if s.Type != LogSinkTypeSyslog && (s.Facility != "" || s.Tag != "") {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("facility and tag are only valid for syslog sinks"))
+	}
 	return mErr.ErrorOrNil()
 }
 
+// Destination returns the network and address the sink connects to. Both are
+// empty for syslog sinks connecting to the local syslog daemon.
+func (s *LogSink) Destination() (string, string, error) {
+	switch s.Type {
+	case LogSinkTypeTCP, LogSinkTypeUDP, LogSinkTypeUnix:
+		return s.Type, s.Address, nil
+	case LogSinkTypeSyslog:
+		if s.Address == "" {
+			return "", "", nil
+		}
+		u, err := url.Parse(s.Address)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid syslog address %q: %v", s.Address, err)
+		}
+		switch u.Scheme {
+		case "tcp", "udp":
+			return u.Scheme, u.Host, nil
+		case "unix", "unixgram":
+			return u.Scheme, u.Path, nil
+		default:
+			return "", "", fmt.Errorf("unsupported syslog address scheme %q", u.Scheme)
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported log sink type %q", s.Type)
+	}
+}
+
+// CheckAllowed returns an error if a client doesn't allow tasks to forward
+// their logs to the sink's destination. The addresses of tcp and udp
+// destinations must match one of allowedAddresses, and the paths of unix
+// sockets one of allowedSockets. Both may contain glob patterns. Syslog sinks
+// without an address connect to the local syslog daemon and are always
+// allowed.
+func (s *LogSink) CheckAllowed(allowedAddresses, allowedSockets []string) error {
+	network, address, err := s.Destination()
+	if err != nil {
+		return err
+	}
+
+	allowed := allowedAddresses
+	switch network {
+	case "":
+		return nil
+	case "unix", "unixgram":
+		// Match the cleaned path so a path can't escape an allowed directory
+		address = filepath.Clean(address)
+		allowed = allowedSockets
+	}
+
+	for _, pattern := range allowed {
+		if glob.Glob(pattern, address) {
+			return nil
+		}
+	}
+	return fmt.Errorf("log sink %q: destination %s %q is not allowed by the client", s.Name, network, address)
+}
+
 // Task is a single process typically that is executed as part of a task group.
 type Task struct {
 	// Name of the task
@@ -7148,7 +8333,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgServices
 		}
 
 		switch constr.Operand {
-		case ConstraintDistinctHosts, ConstraintDistinctProperty:
+		case ConstraintDistinctHosts, ConstraintDistinctProperty, ConstraintJobAffinity, ConstraintJobAntiAffinity:
 			outer := fmt.Errorf("Constraint %d has disallowed Operand at task level: %s", idx+1, constr.Operand)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
@@ -8015,6 +9200,18 @@ const (
 
 	// TaskClientReconnected indicates that the client running the task disconnected.
 	TaskClientReconnected = "Reconnected"
+
+	// TaskCheckpointed indicates that the task was checkpointed instead of
+	// being killed so that it can be restored by its replacement allocation.
+	TaskCheckpointed = "Checkpointed"
+
+	// TaskCheckpointRestored indicates that the task was restored from the
+	// checkpoint of the allocation it replaces.
+	TaskCheckpointRestored = "Checkpoint Restored"
+
+	// TaskResized indicates that the resources of the running task were
+	// updated in-place without restarting it.
+	TaskResized = "Resized"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -8228,6 +9425,12 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		desc = "Main tasks in the group died"
 	case TaskClientReconnected:
 		desc = "Client reconnected"
+	case TaskCheckpointed:
+		desc = "Task checkpointed for migration"
+	case TaskCheckpointRestored:
+		desc = "Task restored from checkpoint"
+	case TaskResized:
+		desc = "Task resources updated"
 	default:
 		desc = e.Message
 	}
@@ -8578,6 +9781,8 @@ const (
 	ConstraintSetContainsAny    = "set_contains_any"
 	ConstraintAttributeIsSet    = "is_set"
 	ConstraintAttributeIsNotSet = "is_not_set"
+	ConstraintJobAffinity       = "job_affinity"
+	ConstraintJobAntiAffinity   = "job_anti_affinity"
 )
 
 // A Constraint is used to restrict placement options.
@@ -8659,6 +9864,12 @@ func (c *Constraint) Validate() error {
 		if c.RTarget != "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q does not support an RTarget", c.Operand))
 		}
+	case ConstraintJobAffinity, ConstraintJobAntiAffinity:
+		// The LTarget is validated along with the job selector
+		requireLtarget = false
+		if err := validateJobAffinityTargets(c.LTarget, c.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
 	case "=", "==", "is", "!=", "not", "<", "<=", ">", ">=":
 		if c.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q requires an RTarget", c.Operand))
@@ -8700,6 +9911,144 @@ SETEQUALS:
 	return true
 }
 
+// JobSelector selects the jobs whose allocations are targeted by job affinity
+// and anti-affinity constraints. It is parsed from the RTarget of the
+// constraint or affinity.
+type JobSelector struct {
+	// Namespace is the namespace of the selected jobs. If empty, the
+	// namespace of the job being scheduled is used, and the wildcard
+	// namespace selects jobs in all namespaces.
+	Namespace string
+
+	// JobID selects the job with the given ID.
+	JobID string
+
+	// Meta selects jobs whose meta contains all of the given key/value pairs.
+	Meta map[string]string
+}
+
+// ParseJobSelector parses a job selector from a comma separated list of
+// key=value terms, all of which must match. The supported keys are "job",
+// "namespace", and "meta.<key>", and at least one of "job" or a meta key must
+// be given. For example: "job=redis,namespace=cache".
+func ParseJobSelector(s string) (*JobSelector, error) {
+	sel := &JobSelector{}
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		parts := strings.SplitN(term, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid job selector term %q: must be of the form key=value", term)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if value == "" {
+			return nil, fmt.Errorf("invalid job selector term %q: missing value", term)
+		}
+
+		switch {
+		case key == "job":
+			sel.JobID = value
+		case key == "namespace":
+			sel.Namespace = value
+		case strings.HasPrefix(key, "meta.") && len(key) > len("meta."):
+			if sel.Meta == nil {
+				sel.Meta = make(map[string]string)
+			}
+			sel.Meta[strings.TrimPrefix(key, "meta.")] = value
+		default:
+			return nil, fmt.Errorf("invalid job selector key %q", key)
+		}
+	}
+
+	if sel.JobID == "" && len(sel.Meta) == 0 {
+		return nil, errors.New("job selector must select a job or job meta")
+	}
+	return sel, nil
+}
+
+// Matches returns whether the job is selected.
+func (s *JobSelector) Matches(job *Job) bool {
+	if job == nil {
+		return false
+	}
+	if s.Namespace != AllNamespacesSentinel && s.Namespace != job.Namespace {
+		return false
+	}
+	if s.JobID != "" && s.JobID != job.ID {
+		return false
+	}
+	for k, v := range s.Meta {
+		if jv, ok := job.Meta[k]; !ok || jv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// JobAffinityNamespaces returns the namespaces other than the job's own whose
+// jobs are selected by the job's job affinity and anti-affinity constraints
+// and affinities. The wildcard namespace is returned if any of them selects
+// jobs in all namespaces.
+func (j *Job) JobAffinityNamespaces() []string {
+	seen := make(map[string]struct{})
+	var namespaces []string
+	add := func(rTarget string) {
+		sel, err := ParseJobSelector(rTarget)
+		if err != nil || sel.Namespace == "" || sel.Namespace == j.Namespace {
+			return
+		}
+		if _, ok := seen[sel.Namespace]; !ok {
+			seen[sel.Namespace] = struct{}{}
+			namespaces = append(namespaces, sel.Namespace)
+		}
+	}
+	addConstraints := func(constraints []*Constraint) {
+		for _, c := range constraints {
+			if c.Operand == ConstraintJobAffinity || c.Operand == ConstraintJobAntiAffinity {
+				add(c.RTarget)
+			}
+		}
+	}
+	addAffinities := func(affinities []*Affinity) {
+		for _, a := range affinities {
+			if a.Operand == ConstraintJobAffinity {
+				add(a.RTarget)
+			}
+		}
+	}
+
+	addConstraints(j.Constraints)
+	addAffinities(j.Affinities)
+	for _, tg := range j.TaskGroups {
+		addConstraints(tg.Constraints)
+		addAffinities(tg.Affinities)
+		for _, task := range tg.Tasks {
+			addConstraints(task.Constraints)
+			addAffinities(task.Affinities)
+		}
+	}
+	return namespaces
+}
+
+// validateJobAffinityTargets validates the targets of a job affinity or
+// anti-affinity. The LTarget is the node attribute defining the topology the
+// selected jobs' allocations are matched on, and the RTarget the job selector.
+func validateJobAffinityTargets(lTarget, rTarget string) error {
+	var mErr multierror.Error
+	if lTarget == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Job affinity requires an LTarget node attribute such as ${node.unique.id}"))
+	} else if !(strings.HasPrefix(lTarget, "${") && strings.HasSuffix(lTarget, "}")) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Job affinity LTarget %q must be a node attribute such as ${node.unique.id}", lTarget))
+	}
+	if _, err := ParseJobSelector(rTarget); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Job selector is invalid: %v", err))
+	}
+	return mErr.ErrorOrNil()
+}
+
 // Affinity is used to score placement options based on a weight
 type Affinity struct {
 	LTarget string // Left-hand target
@@ -8761,6 +10110,10 @@ func (a *Affinity) Validate() error {
 		if _, err := semver.NewConstraint(a.RTarget); err != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Semver affinity is invalid: %v", err))
 		}
+	case ConstraintJobAffinity:
+		if err := validateJobAffinityTargets(a.LTarget, a.RTarget); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
 	case "=", "==", "is", "!=", "not", "<", "<=", ">", ">=":
 		if a.RTarget == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Operator %q requires an RTarget", a.Operand))
@@ -8786,6 +10139,79 @@ func (a *Affinity) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+const (
+	// JobDependencyConditionSuccess is satisfied when the upstream job is
+	// dead and all of its allocations completed successfully.
+	JobDependencyConditionSuccess = "success"
+
+	// JobDependencyConditionComplete is satisfied when the upstream job is
+	// dead, whether its allocations succeeded or not.
+	JobDependencyConditionComplete = "complete"
+)
+
+// JobDependency is a dependency of a job on the completion of another job in
+// the same namespace. When the upstream job is periodic or parameterized, the
+// dependency is on the completion of all of its children.
+type JobDependency struct {
+	// JobID is the ID of the upstream job
+	JobID string
+
+	// Condition is the completion criteria of the upstream job
+	Condition string
+}
+
+func (d *JobDependency) Copy() *JobDependency {
+	if d == nil {
+		return nil
+	}
+	nd := new(JobDependency)
+	*nd = *d
+	return nd
+}
+
+func (d *JobDependency) Canonicalize() {
+	if d.Condition == "" {
+		d.Condition = JobDependencyConditionSuccess
+	}
+}
+
+func (d *JobDependency) Validate() error {
+	var mErr multierror.Error
+	if d.JobID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("Missing dependency job"))
+	}
+	switch d.Condition {
+	case JobDependencyConditionSuccess, JobDependencyConditionComplete:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid dependency condition %q", d.Condition))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// JobDependencyStatus is the status of a dependency of a job.
+type JobDependencyStatus struct {
+	// JobID and Condition are those of the dependency
+	JobID     string
+	Condition string
+
+	// Status is the status of the upstream job, or "missing" if it doesn't
+	// exist
+	Status string
+
+	// Satisfied is whether the condition of the dependency is met
+	Satisfied bool
+
+	// Description explains why the dependency isn't satisfied
+	Description string
+}
+
+// JobDependenciesResponse is used to return the status of the dependencies of
+// a job.
+type JobDependenciesResponse struct {
+	Dependencies []*JobDependencyStatus
+	QueryMeta
+}
+
 // Spread is used to specify desired distribution of allocations according to weight
 type Spread struct {
 	// Attribute is the node attribute used as the spread criteria
@@ -8795,6 +10221,11 @@ type Spread struct {
 	// spread and affinities
 	Weight int8
 
+	// MaxSkew makes the spread a hard constraint when set: allocations are not
+	// placed where the count of a value of the attribute would exceed the
+	// lowest count among the values of feasible nodes by more than MaxSkew.
+	MaxSkew int
+
 	// SpreadTarget is used to describe desired percentages for each attribute value
 	SpreadTarget []*SpreadTarget
 
@@ -8843,6 +10274,9 @@ func (s *Spread) String() string {
 		return s.str
 	}
 	s.str = fmt.Sprintf("%s %s %v", s.Attribute, s.SpreadTarget, s.Weight)
+	if s.MaxSkew > 0 {
+		s.str += fmt.Sprintf(" max_skew = %d", s.MaxSkew)
+	}
 	return s.str
 }
 
@@ -8854,6 +10288,12 @@ func (s *Spread) Validate() error {
 	if s.Weight <= 0 || s.Weight > 100 {
 		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza must have a positive weight from 0 to 100"))
 	}
+	if s.MaxSkew < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread max_skew must not be negative"))
+	}
+	if s.MaxSkew > 0 && len(s.SpreadTarget) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Spread stanza with max_skew must not have targets"))
+	}
 	seen := make(map[string]struct{})
 	sumPercent := uint32(0)
 
@@ -9061,6 +10501,7 @@ const (
 	DeploymentStatusDescriptionNewerJob              = "Cancelled due to newer version of job"
 	DeploymentStatusDescriptionFailedAllocations     = "Failed due to unhealthy allocations"
 	DeploymentStatusDescriptionProgressDeadline      = "Failed due to progress deadline"
+	DeploymentStatusDescriptionFailedAnalysis        = "Failed due to canary analysis"
 	DeploymentStatusDescriptionFailedByUser          = "Deployment marked as failed"
 
 	// used only in multiregion deployments
@@ -9293,6 +10734,14 @@ type DeploymentState struct {
 
 	// UnhealthyAllocs are allocations that have been marked as unhealthy.
 	UnhealthyAllocs int
+
+	// RolloutSteps are the steps of the rollout copied from TaskGroup
+	// UpdateStrategy in scheduler.reconcile
+	RolloutSteps []*RolloutStep
+
+	// CurrentStep is the index of the rollout step in progress. It is equal
+	// to the number of rollout steps once the rollout is past all its steps.
+	CurrentStep int
 }
 
 func (d *DeploymentState) GoString() string {
@@ -9305,6 +10754,9 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
 	base += fmt.Sprintf("\n\tAutoPromote: %v", d.AutoPromote)
+	if len(d.RolloutSteps) != 0 {
+		base += fmt.Sprintf("\n\tRollout Step: %d/%d", d.CurrentStep, len(d.RolloutSteps))
+	}
 	return base
 }
 
@@ -9312,9 +10764,46 @@ func (d *DeploymentState) Copy() *DeploymentState {
 	c := &DeploymentState{}
 	*c = *d
 	c.PlacedCanaries = helper.CopySliceString(d.PlacedCanaries)
+	if d.RolloutSteps != nil {
+		c.RolloutSteps = make([]*RolloutStep, len(d.RolloutSteps))
+		for i, s := range d.RolloutSteps {
+			c.RolloutSteps[i] = s.Copy()
+		}
+	}
 	return c
 }
 
+// ActiveStep returns the rollout step in progress, or nil if the rollout has
+// no steps or is past all of them.
+func (d *DeploymentState) ActiveStep() *RolloutStep {
+	if d == nil || d.CurrentStep >= len(d.RolloutSteps) {
+		return nil
+	}
+	return d.RolloutSteps[d.CurrentStep]
+}
+
+// StepTarget returns the number of allocations that are part of the
+// deployment by the end of the rollout step in progress, and whether there is
+// a rollout step in progress.
+func (d *DeploymentState) StepTarget() (int, bool) {
+	step := d.ActiveStep()
+	if step == nil {
+		return 0, false
+	}
+	return step.Target(d.DesiredTotal), true
+}
+
+// StepComplete returns whether the allocations of the rollout step in
+// progress are all healthy. Canaries must be promoted before the first step
+// is complete.
+func (d *DeploymentState) StepComplete() bool {
+	target, ok := d.StepTarget()
+	if !ok || (d.DesiredCanaries > 0 && !d.Promoted) {
+		return false
+	}
+	return d.HealthyAllocs >= target
+}
+
 // DeploymentStatusUpdate is used to update the status of a given deployment
 type DeploymentStatusUpdate struct {
 	// DeploymentID is the ID of the deployment to update
@@ -10401,6 +11890,11 @@ type AllocMetric struct {
 	// This is to prevent creating many failed allocations for a
 	// single task group.
 	CoalescedFailures int
+
+	// GangFailures is the list of task groups which failed to be placed,
+	// preventing the placement of this task group's allocations as part of
+	// the same gang.
+	GangFailures []string
 }
 
 func (a *AllocMetric) Copy() *AllocMetric {
@@ -10415,6 +11909,7 @@ func (a *AllocMetric) Copy() *AllocMetric {
 	na.ClassExhausted = helper.CopyMapStringInt(na.ClassExhausted)
 	na.DimensionExhausted = helper.CopyMapStringInt(na.DimensionExhausted)
 	na.QuotaExhausted = helper.CopySliceString(na.QuotaExhausted)
+	na.GangFailures = helper.CopySliceString(na.GangFailures)
 	na.Scores = helper.CopyMapStringFloat64(na.Scores)
 	na.ScoreMetaData = CopySliceNodeScoreMeta(na.ScoreMetaData)
 	return na
@@ -10691,6 +12186,8 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerRebalance            = "rebalance"
+	EvalTriggerJobDependency        = "job-dependency"
 )
 
 const (
@@ -10732,6 +12229,11 @@ const (
 
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
+
+	// CoreJobRebalance is used to migrate allocations violating their spread
+	// or running on underutilized nodes. It is only run if the rebalancer is
+	// enabled.
+	CoreJobRebalance = "rebalance"
 )
 
 // Evaluation is used anytime we need to apply business logic as a result
@@ -11330,6 +12832,55 @@ func (p *Plan) PopUpdate(alloc *Allocation) {
 	}
 }
 
+// RemoveUpdate removes an allocation appended to the plan's updates, such as
+// with AppendStoppedAlloc. Unlike PopUpdate, the update doesn't have to be the
+// last one appended for the allocation's node.
+func (p *Plan) RemoveUpdate(alloc *Allocation) {
+	node := alloc.NodeID
+	if existing := RemoveAllocs(p.NodeUpdate[node], []*Allocation{alloc}); len(existing) > 0 {
+		p.NodeUpdate[node] = existing
+	} else {
+		delete(p.NodeUpdate, node)
+	}
+}
+
+// RemoveAlloc removes an allocation appended to the plan with AppendAlloc,
+// along with the preemptions of other allocations made to place it.
+func (p *Plan) RemoveAlloc(alloc *Allocation) {
+	node := alloc.NodeID
+	if existing := RemoveAllocs(p.NodeAllocation[node], []*Allocation{alloc}); len(existing) > 0 {
+		p.NodeAllocation[node] = existing
+	} else {
+		delete(p.NodeAllocation, node)
+	}
+
+	if len(alloc.PreemptedAllocations) == 0 {
+		return
+	}
+
+	preempted := make([]*Allocation, 0, len(alloc.PreemptedAllocations))
+	preemptedIDs := make(map[string]struct{}, len(alloc.PreemptedAllocations))
+	for _, id := range alloc.PreemptedAllocations {
+		preempted = append(preempted, &Allocation{ID: id})
+		preemptedIDs[id] = struct{}{}
+	}
+	if existing := RemoveAllocs(p.NodePreemptions[node], preempted); len(existing) > 0 {
+		p.NodePreemptions[node] = existing
+	} else {
+		delete(p.NodePreemptions, node)
+	}
+
+	if p.Annotations != nil {
+		var stubs []*AllocListStub
+		for _, stub := range p.Annotations.PreemptedAllocs {
+			if _, ok := preemptedIDs[stub.ID]; !ok {
+				stubs = append(stubs, stub)
+			}
+		}
+		p.Annotations.PreemptedAllocs = stubs
+	}
+}
+
 // AppendAlloc appends the alloc to the plan allocations.
 // Uses the passed job if explicitly passed, otherwise
 // it is assumed the alloc will use the plan Job version.
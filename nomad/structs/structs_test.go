@@ -100,6 +100,67 @@ func TestJob_Validate(t *testing.T) {
 	require.Error(t, err, "datacenter must be non-empty string")
 }
 
+func TestJob_Validate_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	j := testJob()
+	j.Gang = true
+	j.TaskGroups[0].Gang = true
+	err := j.Validate()
+	requireErrors(t, err,
+		"Gang scheduling is only supported for batch jobs",
+		"Task group web validation failed",
+	)
+
+	// Batch jobs and their task groups may be gang scheduled
+	j.Type = JobTypeBatch
+	err = j.Validate()
+	if err != nil {
+		require.NotContains(t, err.Error(), "Gang scheduling")
+	}
+}
+
+func TestJob_Validate_DependsOn(t *testing.T) {
+	ci.Parallel(t)
+
+	j := testJob()
+	j.DependsOn = []*JobDependency{
+		{JobID: j.ID, Condition: JobDependencyConditionSuccess},
+		{JobID: "upstream", Condition: JobDependencyConditionComplete},
+		{JobID: "upstream", Condition: JobDependencyConditionSuccess},
+		{Condition: "failed"},
+	}
+	err := j.Validate()
+	requireErrors(t, err,
+		"Dependency 1 references the job itself",
+		`Dependency 3 redefines job "upstream"`,
+		"Dependency 4 validation failed",
+		"Missing dependency job",
+		`Invalid dependency condition "failed"`,
+	)
+
+	// Periodic jobs may not have dependencies
+	j = testJob()
+	j.Type = JobTypeBatch
+	j.DependsOn = []*JobDependency{{JobID: "upstream", Condition: JobDependencyConditionSuccess}}
+	err = j.Validate()
+	requireErrors(t, err, "Periodic and parameterized jobs may not have dependencies")
+
+	j.Periodic = nil
+	err = j.Validate()
+	if err != nil {
+		require.NotContains(t, err.Error(), "ependenc")
+	}
+}
+
+func TestJobDependency_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &JobDependency{JobID: "upstream"}
+	d.Canonicalize()
+	require.Equal(t, JobDependencyConditionSuccess, d.Condition)
+}
+
 func TestJob_ValidateScaling(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1685,6 +1746,32 @@ func TestTask_Validate_Resources(t *testing.T) {
 			},
 			err: "MemoryMaxMB value (10) should be larger than MemoryMB value (200",
 		},
+		{
+			name: "numa affinity",
+			res: &Resources{
+				Cores:    2,
+				MemoryMB: 200,
+				NUMA:     &NUMA{Affinity: NUMAAffinityRequire},
+			},
+		},
+		{
+			name: "numa without cores",
+			res: &Resources{
+				CPU:      100,
+				MemoryMB: 200,
+				NUMA:     &NUMA{Affinity: NUMAAffinityRequire},
+			},
+			err: "Task can only ask for 'numa' affinity along with 'cores' resource.",
+		},
+		{
+			name: "unknown numa affinity",
+			res: &Resources{
+				Cores:    2,
+				MemoryMB: 200,
+				NUMA:     &NUMA{Affinity: "strict"},
+			},
+			err: `Unknown NUMA affinity "strict"`,
+		},
 	}
 
 	for i := range cases {
@@ -2479,6 +2566,13 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equals(b))
 	})
 
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Name: "a", Type: LogSinkTypeTCP}}}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Name: "a", Type: LogSinkTypeUDP}}}
+		require.False(t, a.Equals(b))
+		require.True(t, a.Equals(a.Copy()))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -2486,6 +2580,149 @@ func TestLogConfig_Equals(t *testing.T) {
 	})
 }
 
+func TestLogConfig_Validate_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := []*LogSink{
+		{Name: "local", Type: LogSinkTypeSyslog, BufferSize: 1},
+		{Name: "remote", Type: LogSinkTypeSyslog, Address: "tcp://10.0.0.1:514", Facility: "local3", BufferSize: 1},
+		{Name: "tcp", Type: LogSinkTypeTCP, Address: "10.0.0.1:5170", BufferSize: 1},
+		{Name: "udp", Type: LogSinkTypeUDP, Address: "[::1]:5170", BufferSize: 1},
+		{Name: "unix", Type: LogSinkTypeUnix, Address: "/run/collector.sock", BufferSize: 1},
+	}
+	l := DefaultLogConfig()
+	l.Sinks = valid
+	require.NoError(t, l.Validate())
+
+	cases := []struct {
+		name string
+		sink *LogSink
+		err  string
+	}{
+		{
+			name: "missing name",
+			sink: &LogSink{Type: LogSinkTypeTCP, Address: "10.0.0.1:5170", BufferSize: 1},
+			err:  "Missing sink name",
+		},
+		{
+			name: "invalid type",
+			sink: &LogSink{Name: "a", Type: "kafka", BufferSize: 1},
+			err:  `invalid sink type "kafka"`,
+		},
+		{
+			name: "buffer size",
+			sink: &LogSink{Name: "a", Type: LogSinkTypeSyslog},
+			err:  "minimum buffer size is 1; got 0",
+		},
+		{
+			name: "syslog facility",
+			sink: &LogSink{Name: "a", Type: LogSinkTypeSyslog, Facility: "local9", BufferSize: 1},
+			err:  `invalid syslog facility "local9"`,
+		},
+		{
+			name: "syslog scheme",
+			sink: &LogSink{Name: "a", Type: LogSinkTypeSyslog, Address: "http://10.0.0.1", BufferSize: 1},
+			err:  "must use the tcp, udp, unix or unixgram scheme",
+		},
+		{
+			name: "tcp address",
+			sink: &LogSink{Name: "a", Type: LogSinkTypeTCP, Address: "10.0.0.1", BufferSize: 1},
+			err:  `invalid tcp address "10.0.0.1"`,
+		},
+		{
+			name: "unix address",
+			sink: &LogSink{Name: "a", Type: LogSinkTypeUnix, BufferSize: 1},
+			err:  "Missing unix socket path",
+		},
+		{
+			name: "tag",
+			sink: &LogSink{Name: "a", Type: LogSinkTypeUDP, Address: "10.0.0.1:5170", Tag: "web", BufferSize: 1},
+			err:  "facility and tag are only valid for syslog sinks",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := DefaultLogConfig()
+			l.Sinks = []*LogSink{tc.sink}
+			err := l.Validate()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	// Sink names must be unique
+	l = DefaultLogConfig()
+	l.Sinks = []*LogSink{valid[2], valid[2]}
+	err := l.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Log sink "tcp" defined more than once`)
+}
+
+func TestLogSink_CheckAllowed(t *testing.T) {
+	ci.Parallel(t)
+
+	addresses := []string{"10.0.0.5:514", "logs.example.com:*"}
+	sockets := []string{"/run/collector/*.sock"}
+
+	cases := []struct {
+		name string
+		sink *LogSink
+		err  string
+	}{
+		{
+			name: "allowed address",
+			sink: &LogSink{Name: "s", Type: "tcp", Address: "logs.example.com:5170"},
+		},
+		{
+			name: "disallowed address",
+			sink: &LogSink{Name: "s", Type: "udp", Address: "169.254.169.254:80"},
+			err:  `log sink "s": destination udp "169.254.169.254:80" is not allowed by the client`,
+		},
+		{
+			name: "allowed syslog address",
+			sink: &LogSink{Name: "s", Type: "syslog", Address: "udp://10.0.0.5:514"},
+		},
+		{
+			name: "local syslog",
+			sink: &LogSink{Name: "s", Type: "syslog"},
+		},
+		{
+			name: "allowed socket",
+			sink: &LogSink{Name: "s", Type: "unix", Address: "/run/collector/logs.sock"},
+		},
+		{
+			name: "socket escaping allowed directory",
+			sink: &LogSink{Name: "s", Type: "unix", Address: "/run/collector/../docker.sock"},
+			err:  `log sink "s": destination unix "/run/docker.sock" is not allowed by the client`,
+		},
+		{
+			name: "disallowed syslog socket",
+			sink: &LogSink{Name: "s", Type: "syslog", Address: "unix:///var/run/docker.sock"},
+			err:  `log sink "s": destination unix "/var/run/docker.sock" is not allowed by the client`,
+		},
+		{
+			name: "socket matching an address",
+			sink: &LogSink{Name: "s", Type: "unix", Address: "10.0.0.5:514"},
+			err:  `log sink "s": destination unix "10.0.0.5:514" is not allowed by the client`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sink.CheckAllowed(addresses, sockets)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+
+	// Nothing is allowed without an allowlist
+	err := (&LogSink{Name: "s", Type: "unix", Address: "/run/collector/logs.sock"}).CheckAllowed(nil, nil)
+	require.Error(t, err)
+}
+
 func TestTask_Validate_CSIPluginConfig(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2836,6 +3073,30 @@ func TestConstraint_Validate(t *testing.T) {
 		require.Error(t, err, "requires an RTarget")
 	}
 
+	// Perform job affinity validation
+	for _, o := range []string{ConstraintJobAffinity, ConstraintJobAntiAffinity} {
+		c.Operand = o
+		c.LTarget = "${meta.rack}"
+		c.RTarget = "job=cache,namespace=*"
+		require.NoError(t, c.Validate())
+
+		c.RTarget = "namespace=default"
+		err = c.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must select a job or job meta")
+
+		c.LTarget = "rack"
+		c.RTarget = "meta.tier=storage"
+		err = c.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be a node attribute")
+
+		c.LTarget = ""
+		err = c.Validate()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires an LTarget node attribute")
+	}
+
 	// Perform LTarget validation
 	c.Operand = ConstraintRegex
 	c.RTarget = "foo"
@@ -2922,6 +3183,40 @@ func TestAffinity_Validate(t *testing.T) {
 			},
 			err: fmt.Errorf("Regular expression failed to compile"),
 		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintJobAffinity,
+				LTarget: "${node.unique.id}",
+				RTarget: "job=cache",
+				Weight:  -50,
+			},
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintJobAffinity,
+				LTarget: "${node.unique.id}",
+				RTarget: "app=cache",
+				Weight:  50,
+			},
+			err: fmt.Errorf("invalid job selector key \"app\""),
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintJobAffinity,
+				RTarget: "job=cache",
+				Weight:  50,
+			},
+			err: fmt.Errorf("Job affinity requires an LTarget node attribute"),
+		},
+		{
+			affinity: &Affinity{
+				Operand: ConstraintJobAntiAffinity,
+				LTarget: "${node.unique.id}",
+				RTarget: "job=cache",
+				Weight:  50,
+			},
+			err: fmt.Errorf("Unknown affinity operator \"job_anti_affinity\""),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -2937,6 +3232,73 @@ func TestAffinity_Validate(t *testing.T) {
 	}
 }
 
+func TestJobSelector(t *testing.T) {
+	ci.Parallel(t)
+
+	_, err := ParseJobSelector("job")
+	require.EqualError(t, err, `invalid job selector term "job": must be of the form key=value`)
+
+	_, err = ParseJobSelector("meta.=storage")
+	require.EqualError(t, err, `invalid job selector key "meta."`)
+
+	sel, err := ParseJobSelector("job=cache, meta.tier=storage")
+	require.NoError(t, err)
+	require.Equal(t, &JobSelector{
+		JobID: "cache",
+		Meta:  map[string]string{"tier": "storage"},
+	}, sel)
+
+	job := &Job{
+		ID:        "cache",
+		Namespace: DefaultNamespace,
+		Meta:      map[string]string{"tier": "storage", "owner": "ops"},
+	}
+	sel.Namespace = DefaultNamespace
+	require.True(t, sel.Matches(job))
+
+	sel.Namespace = "other"
+	require.False(t, sel.Matches(job))
+
+	sel.Namespace = AllNamespacesSentinel
+	require.True(t, sel.Matches(job))
+
+	sel.Meta["tier"] = "web"
+	require.False(t, sel.Matches(job))
+}
+
+func TestJob_JobAffinityNamespaces(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	require.Empty(t, job.JobAffinityNamespaces())
+
+	job.Constraints = append(job.Constraints, &Constraint{
+		LTarget: "${node.unique.id}",
+		RTarget: "job=cache,namespace=" + job.Namespace,
+		Operand: ConstraintJobAntiAffinity,
+	})
+	job.Affinities = append(job.Affinities, &Affinity{
+		LTarget: "${meta.rack}",
+		RTarget: "job=cache,namespace=other",
+		Operand: ConstraintJobAffinity,
+		Weight:  50,
+	})
+	tg := job.TaskGroups[0]
+	tg.Constraints = append(tg.Constraints, &Constraint{
+		LTarget: "${node.unique.id}",
+		RTarget: "meta.tier=batch,namespace=*",
+		Operand: ConstraintJobAntiAffinity,
+	})
+	tg.Tasks[0].Affinities = append(tg.Tasks[0].Affinities, &Affinity{
+		LTarget: "${node.unique.id}",
+		RTarget: "job=db,namespace=other",
+		Operand: ConstraintJobAffinity,
+		Weight:  50,
+	})
+
+	require.Equal(t, []string{"other", AllNamespacesSentinel}, job.JobAffinityNamespaces())
+}
+
 func TestUpdateStrategy_Validate(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2963,6 +3325,104 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 	)
 }
 
+func TestAnalysisStrategy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	u := DefaultUpdateStrategy.Copy()
+	u.Analysis = &AnalysisStrategy{
+		Source:       AnalysisSourcePrometheus,
+		Interval:     0,
+		BakeTime:     -1,
+		FailureLimit: -1,
+		Metrics: []*AnalysisMetric{
+			{Name: "errors", Query: "errors{alloc_id=~\"{{ .AllocIDs }\"}"},
+			{Name: "errors", Query: "errors", Max: helper.Float64ToPtr(1)},
+		},
+	}
+
+	err := u.Validate()
+	requireErrors(t, err,
+		"Analysis requires a Canary count greater than zero",
+		"Analysis address must be set",
+		"Analysis interval must be greater than zero",
+		"Analysis bake time must be zero or greater",
+		"Analysis failure limit can not be less than zero",
+		"Analysis metric \"errors\" defined more than once",
+		"Invalid query",
+		"Either max or max increase must be set",
+	)
+
+	u.Canary = 1
+	u.Analysis = &AnalysisStrategy{
+		Source:   AnalysisSourceNomad,
+		Interval: time.Minute,
+		Metrics: []*AnalysisMetric{
+			{Name: "memory", Query: AnalysisMetricMemoryRSS, MaxIncrease: helper.Float64ToPtr(0.2)},
+		},
+	}
+	require.NoError(t, u.Validate())
+
+	u.Analysis.Metrics[0].Query = "memory"
+	requireErrors(t, u.Validate(), "Invalid query given")
+}
+
+func TestUpdateStrategy_Validate_Steps(t *testing.T) {
+	ci.Parallel(t)
+
+	u := DefaultUpdateStrategy.Copy()
+	u.Steps = []*RolloutStep{
+		{Percent: 50},
+		{Percent: 25, Pause: time.Minute, Manual: true},
+		{Percent: 150, Pause: -1},
+	}
+
+	err := u.Validate()
+	requireErrors(t, err,
+		"Rollout step 2 percent must be greater than the previous step",
+		"Pause can't be set on a manual step",
+		"Percent must be between 1 and 100",
+		"Pause must be zero or greater",
+	)
+
+	u.Steps = []*RolloutStep{
+		{Percent: 25, Pause: time.Minute},
+		{Percent: 50, Manual: true},
+		{Percent: 100},
+	}
+	require.NoError(t, u.Validate())
+}
+
+func TestDeploymentState_StepComplete(t *testing.T) {
+	ci.Parallel(t)
+
+	d := &DeploymentState{
+		DesiredTotal:    10,
+		DesiredCanaries: 1,
+		HealthyAllocs:   1,
+		RolloutSteps: []*RolloutStep{
+			{Percent: 25},
+			{Percent: 100},
+		},
+	}
+
+	// Canaries are promoted before the first step
+	target, ok := d.StepTarget()
+	require.True(t, ok)
+	require.Equal(t, 3, target)
+	require.False(t, d.StepComplete())
+
+	d.Promoted = true
+	require.False(t, d.StepComplete())
+	d.HealthyAllocs = 3
+	require.True(t, d.StepComplete())
+
+	d.CurrentStep = 2
+	require.Nil(t, d.ActiveStep())
+	_, ok = d.StepTarget()
+	require.False(t, ok)
+	require.False(t, d.StepComplete())
+}
+
 func TestResource_NetIndex(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4411,6 +4871,48 @@ func TestPlan_AppendPreemptedAllocAppendsAllocWithUpdatedAttrs(t *testing.T) {
 	assert.Equal(t, expectedAlloc, appendedAlloc)
 }
 
+func TestPlan_RemoveAlloc(t *testing.T) {
+	ci.Parallel(t)
+	plan := &Plan{
+		NodeUpdate:      make(map[string][]*Allocation),
+		NodeAllocation:  make(map[string][]*Allocation),
+		NodePreemptions: make(map[string][]*Allocation),
+	}
+
+	prev := MockAlloc()
+	other := MockAlloc()
+	other.NodeID = prev.NodeID
+	preempted := MockAlloc()
+	preempted.NodeID = prev.NodeID
+
+	alloc := MockAlloc()
+	alloc.NodeID = prev.NodeID
+	kept := MockAlloc()
+	kept.NodeID = prev.NodeID
+
+	plan.AppendStoppedAlloc(prev, "replaced", "", "")
+	plan.AppendStoppedAlloc(other, "stopped", "", "")
+	plan.AppendPreemptedAlloc(preempted, alloc.ID)
+	alloc.PreemptedAllocations = []string{preempted.ID}
+	plan.AppendAlloc(alloc, nil)
+	plan.AppendAlloc(kept, nil)
+
+	// Updates are removed regardless of their order
+	plan.RemoveUpdate(prev)
+	require.Len(t, plan.NodeUpdate[prev.NodeID], 1)
+	require.Equal(t, other.ID, plan.NodeUpdate[prev.NodeID][0].ID)
+
+	// Removing an allocation removes its preemptions
+	plan.RemoveAlloc(alloc)
+	require.Equal(t, []*Allocation{kept}, plan.NodeAllocation[alloc.NodeID])
+	require.NotContains(t, plan.NodePreemptions, alloc.NodeID)
+
+	plan.RemoveUpdate(other)
+	plan.RemoveAlloc(kept)
+	require.Empty(t, plan.NodeUpdate)
+	require.Empty(t, plan.NodeAllocation)
+}
+
 func TestAllocation_MsgPackTags(t *testing.T) {
 	ci.Parallel(t)
 	planType := reflect.TypeOf(Allocation{})
@@ -6458,6 +6960,47 @@ func TestNode_Copy(t *testing.T) {
 	require.Equal(node.Drivers, node2.Drivers)
 }
 
+func TestNodeMaintenanceWindow_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	w := &NodeMaintenanceWindow{
+		ID:    uuid.Generate(),
+		Start: now,
+		End:   now.Add(time.Hour),
+	}
+	require.NoError(t, w.Validate())
+	require.True(t, w.Started(now))
+	require.False(t, w.Ended(now))
+	require.True(t, w.Ended(now.Add(time.Hour)))
+
+	w.End = now
+	w.Deadline = -1
+	err := w.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must end after its start")
+	require.Contains(t, err.Error(), "deadline must not be negative")
+}
+
+func TestNodeCapacityReservation_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	r := &NodeCapacityReservation{
+		ID:       uuid.Generate(),
+		JobID:    "example",
+		MemoryMB: 1024,
+		Expires:  time.Now().Add(time.Hour),
+	}
+	require.NoError(t, r.Validate())
+	require.False(t, r.Expired(time.Now()))
+
+	err := (&NodeCapacityReservation{}).Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Missing capacity reservation job")
+	require.Contains(t, err.Error(), "must reserve cpu or memory")
+	require.Contains(t, err.Error(), "Missing capacity reservation expiry")
+}
+
 func TestNode_GetID(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6612,6 +7155,39 @@ func TestSpread_Validate(t *testing.T) {
 			err:  nil,
 			name: "Valid spread",
 		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   -1,
+			},
+			err:  fmt.Errorf("Spread max_skew must not be negative"),
+			name: "Invalid max skew",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${node.datacenter}",
+				Weight:    50,
+				MaxSkew:   1,
+				SpreadTarget: []*SpreadTarget{
+					{
+						Value:   "dc1",
+						Percent: 25,
+					},
+				},
+			},
+			err:  fmt.Errorf("Spread stanza with max_skew must not have targets"),
+			name: "Max skew with targets",
+		},
+		{
+			spread: &Spread{
+				Attribute: "${meta.rack}",
+				Weight:    50,
+				MaxSkew:   1,
+			},
+			err:  nil,
+			name: "Valid max skew",
+		},
 	}
 
 	for _, tc := range testCases {
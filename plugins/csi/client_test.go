@@ -1167,6 +1167,88 @@ func TestClient_RPC_ControllerListSnapshots(t *testing.T) {
 	}
 }
 
+func TestClient_RPC_ControllerExpandVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name        string
+		Request     *ControllerExpandVolumeRequest
+		Response    *csipbv1.ControllerExpandVolumeResponse
+		ResponseErr error
+		ExpectedErr error
+	}{
+		{
+			Name: "handles underlying grpc errors",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange:    &CapacityRange{RequiredBytes: 2000},
+			},
+			ResponseErr: status.Errorf(codes.Internal, "some grpc error"),
+			ExpectedErr: fmt.Errorf("controller plugin returned an internal error, check the plugin allocation logs for more information: rpc error: code = Internal desc = some grpc error"),
+		},
+		{
+			Name: "handles out of range errors",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange:    &CapacityRange{RequiredBytes: 2000},
+			},
+			ResponseErr: status.Errorf(codes.OutOfRange, "too big"),
+			ExpectedErr: fmt.Errorf("unsupported capacity_range for volume \"vol-1\": rpc error: code = OutOfRange desc = too big"),
+		},
+		{
+			Name:        "handles error missing volume ID",
+			Request:     &ControllerExpandVolumeRequest{},
+			ExpectedErr: errors.New("missing ExternalVolumeID"),
+		},
+		{
+			Name: "handles error missing capacity range",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+			},
+			ExpectedErr: errors.New("missing CapacityRange"),
+		},
+		{
+			Name: "handles error invalid capacity range",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange: &CapacityRange{
+					RequiredBytes: 2000,
+					LimitBytes:    1000,
+				},
+			},
+			ExpectedErr: errors.New("LimitBytes cannot be less than RequiredBytes"),
+		},
+		{
+			Name: "handles success",
+			Request: &ControllerExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				CapacityRange:    &CapacityRange{RequiredBytes: 2000},
+			},
+			Response: &csipbv1.ControllerExpandVolumeResponse{
+				CapacityBytes:         2048,
+				NodeExpansionRequired: true,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, cc, _, client := newTestClient(t)
+			defer client.Close()
+
+			cc.NextErr = tc.ResponseErr
+			cc.NextExpandVolumeResponse = tc.Response
+			resp, err := client.ControllerExpandVolume(context.TODO(), tc.Request)
+			if tc.ExpectedErr != nil {
+				require.EqualError(t, err, tc.ExpectedErr.Error())
+				return
+			}
+			require.NoError(t, err, tc.Name)
+			require.Equal(t, int64(2048), resp.CapacityBytes)
+			require.True(t, resp.NodeExpansionRequired)
+		})
+	}
+}
+
 func TestClient_RPC_NodeStageVolume(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1361,3 +1443,158 @@ func TestClient_RPC_NodeUnpublishVolume(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_RPC_NodeExpandVolume(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name        string
+		Request     *NodeExpandVolumeRequest
+		Response    *csipbv1.NodeExpandVolumeResponse
+		ResponseErr error
+		ExpectedErr error
+	}{
+		{
+			Name: "handles underlying grpc errors",
+			Request: &NodeExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				VolumePath:       "/dev/null",
+			},
+			ResponseErr: status.Errorf(codes.Internal, "some grpc error"),
+			ExpectedErr: fmt.Errorf("node plugin returned an internal error, check the plugin allocation logs for more information: rpc error: code = Internal desc = some grpc error"),
+		},
+		{
+			Name: "handles not found errors",
+			Request: &NodeExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				VolumePath:       "/dev/null",
+			},
+			ResponseErr: status.Errorf(codes.NotFound, "no such volume"),
+			ExpectedErr: fmt.Errorf("volume \"vol-1\" could not be found: rpc error: code = NotFound desc = no such volume"),
+		},
+		{
+			Name:        "Performs validation of the request args - ExternalID",
+			Request:     &NodeExpandVolumeRequest{VolumePath: "/dev/null"},
+			ExpectedErr: errors.New("validation error: missing ExternalVolumeID"),
+		},
+		{
+			Name:        "Performs validation of the request args - VolumePath",
+			Request:     &NodeExpandVolumeRequest{ExternalVolumeID: "vol-1"},
+			ExpectedErr: errors.New("validation error: missing VolumePath"),
+		},
+		{
+			Name: "handles success",
+			Request: &NodeExpandVolumeRequest{
+				ExternalVolumeID: "vol-1",
+				VolumePath:       "/dev/null",
+				CapacityRange:    &CapacityRange{RequiredBytes: 2000},
+			},
+			Response: &csipbv1.NodeExpandVolumeResponse{CapacityBytes: 2048},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, _, nc, client := newTestClient(t)
+			defer client.Close()
+
+			nc.NextErr = tc.ResponseErr
+			nc.NextExpandVolumeResponse = tc.Response
+
+			resp, err := client.NodeExpandVolume(context.TODO(), tc.Request)
+			if tc.ExpectedErr != nil {
+				require.EqualError(t, err, tc.ExpectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int64(2048), resp.CapacityBytes)
+		})
+	}
+}
+
+func TestClient_RPC_NodeGetVolumeStats(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		Name        string
+		Request     *NodeGetVolumeStatsRequest
+		Response    *csipbv1.NodeGetVolumeStatsResponse
+		ResponseErr error
+		ExpectedErr error
+		Expected    *NodeGetVolumeStatsResponse
+	}{
+		{
+			Name: "handles underlying grpc errors",
+			Request: &NodeGetVolumeStatsRequest{
+				ExternalVolumeID: "vol-1",
+				VolumePath:       "/dev/null",
+			},
+			ResponseErr: status.Errorf(codes.Internal, "some grpc error"),
+			ExpectedErr: fmt.Errorf("node plugin returned an internal error, check the plugin allocation logs for more information: rpc error: code = Internal desc = some grpc error"),
+		},
+		{
+			Name: "handles not found errors",
+			Request: &NodeGetVolumeStatsRequest{
+				ExternalVolumeID: "vol-1",
+				VolumePath:       "/dev/null",
+			},
+			ResponseErr: status.Errorf(codes.NotFound, "no such volume"),
+			ExpectedErr: fmt.Errorf("volume \"vol-1\" could not be found at path \"/dev/null\": rpc error: code = NotFound desc = no such volume"),
+		},
+		{
+			Name:        "Performs validation of the request args - ExternalID",
+			Request:     &NodeGetVolumeStatsRequest{VolumePath: "/dev/null"},
+			ExpectedErr: errors.New("validation error: missing ExternalVolumeID"),
+		},
+		{
+			Name:        "Performs validation of the request args - VolumePath",
+			Request:     &NodeGetVolumeStatsRequest{ExternalVolumeID: "vol-1"},
+			ExpectedErr: errors.New("validation error: missing VolumePath"),
+		},
+		{
+			Name: "handles success",
+			Request: &NodeGetVolumeStatsRequest{
+				ExternalVolumeID: "vol-1",
+				VolumePath:       "/dev/null",
+			},
+			Response: &csipbv1.NodeGetVolumeStatsResponse{
+				Usage: []*csipbv1.VolumeUsage{
+					{Available: 1024, Total: 2048, Used: 1024, Unit: csipbv1.VolumeUsage_BYTES},
+					{Available: 90, Total: 100, Used: 10, Unit: csipbv1.VolumeUsage_INODES},
+				},
+				VolumeCondition: &csipbv1.VolumeCondition{
+					Abnormal: true,
+					Message:  "read-only filesystem",
+				},
+			},
+			Expected: &NodeGetVolumeStatsResponse{
+				Usage: []*VolumeUsage{
+					{Available: 1024, Total: 2048, Used: 1024, Unit: VolumeUsageUnitBytes},
+					{Available: 90, Total: 100, Used: 10, Unit: VolumeUsageUnitInodes},
+				},
+				VolumeCondition: &VolumeCondition{
+					Abnormal: true,
+					Message:  "read-only filesystem",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, _, nc, client := newTestClient(t)
+			defer client.Close()
+
+			nc.NextErr = tc.ResponseErr
+			nc.NextVolumeStatsResponse = tc.Response
+
+			resp, err := client.NodeGetVolumeStats(context.TODO(), tc.Request)
+			if tc.ExpectedErr != nil {
+				require.EqualError(t, err, tc.ExpectedErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, resp)
+		})
+	}
+}
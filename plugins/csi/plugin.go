@@ -69,6 +69,10 @@ type CSIPlugin interface {
 	// in the external storage provider
 	ControllerListSnapshots(ctx context.Context, req *ControllerListSnapshotsRequest, opts ...grpc.CallOption) (*ControllerListSnapshotsResponse, error)
 
+	// ControllerExpandVolume is used to expand a remote volume in the
+	// external storage provider
+	ControllerExpandVolume(ctx context.Context, req *ControllerExpandVolumeRequest, opts ...grpc.CallOption) (*ControllerExpandVolumeResponse, error)
+
 	// NodeGetCapabilities is used to return the available capabilities from the
 	// Node Service.
 	NodeGetCapabilities(ctx context.Context) (*NodeCapabilitySet, error)
@@ -98,6 +102,16 @@ type CSIPlugin interface {
 	// for the given volume.
 	NodeUnpublishVolume(ctx context.Context, volumeID, targetPath string, opts ...grpc.CallOption) error
 
+	// NodeExpandVolume is used to expand the filesystem of a volume on the
+	// node after the volume has been expanded by the controller. If a volume
+	// has been published, the VolumePath should be set to the target path.
+	NodeExpandVolume(ctx context.Context, req *NodeExpandVolumeRequest, opts ...grpc.CallOption) (*NodeExpandVolumeResponse, error)
+
+	// NodeGetVolumeStats is used to return the usage and condition of a
+	// volume that has been published on the node. This RPC is only supported
+	// by plugins with the GET_VOLUME_STATS node capability.
+	NodeGetVolumeStats(ctx context.Context, req *NodeGetVolumeStatsRequest, opts ...grpc.CallOption) (*NodeGetVolumeStatsResponse, error)
+
 	// Shutdown the client and ensure any connections are cleaned up.
 	Close() error
 }
@@ -241,6 +255,118 @@ func (r *NodeStageVolumeRequest) Validate() error {
 	return nil
 }
 
+type NodeExpandVolumeRequest struct {
+	ExternalVolumeID  string
+	VolumePath        string
+	StagingTargetPath string
+	CapacityRange     *CapacityRange
+	VolumeCapability  *VolumeCapability
+}
+
+func (r *NodeExpandVolumeRequest) ToCSIRepresentation() *csipbv1.NodeExpandVolumeRequest {
+	if r == nil {
+		return nil
+	}
+	return &csipbv1.NodeExpandVolumeRequest{
+		VolumeId:          r.ExternalVolumeID,
+		VolumePath:        r.VolumePath,
+		StagingTargetPath: r.StagingTargetPath,
+		CapacityRange:     r.CapacityRange.ToCSIRepresentation(),
+		VolumeCapability:  r.VolumeCapability.ToCSIRepresentation(),
+	}
+}
+
+func (r *NodeExpandVolumeRequest) Validate() error {
+	if r.ExternalVolumeID == "" {
+		return errors.New("missing ExternalVolumeID")
+	}
+	if r.VolumePath == "" {
+		return errors.New("missing VolumePath")
+	}
+	return nil
+}
+
+type NodeExpandVolumeResponse struct {
+	CapacityBytes int64
+}
+
+type NodeGetVolumeStatsRequest struct {
+	ExternalVolumeID  string
+	VolumePath        string
+	StagingTargetPath string
+}
+
+func (r *NodeGetVolumeStatsRequest) ToCSIRepresentation() *csipbv1.NodeGetVolumeStatsRequest {
+	if r == nil {
+		return nil
+	}
+	return &csipbv1.NodeGetVolumeStatsRequest{
+		VolumeId:          r.ExternalVolumeID,
+		VolumePath:        r.VolumePath,
+		StagingTargetPath: r.StagingTargetPath,
+	}
+}
+
+func (r *NodeGetVolumeStatsRequest) Validate() error {
+	if r.ExternalVolumeID == "" {
+		return errors.New("missing ExternalVolumeID")
+	}
+	if r.VolumePath == "" {
+		return errors.New("missing VolumePath")
+	}
+	return nil
+}
+
+type NodeGetVolumeStatsResponse struct {
+	Usage           []*VolumeUsage
+	VolumeCondition *VolumeCondition
+}
+
+func NewNodeGetVolumeStatsResponse(resp *csipbv1.NodeGetVolumeStatsResponse) *NodeGetVolumeStatsResponse {
+	if resp == nil {
+		return nil
+	}
+
+	out := &NodeGetVolumeStatsResponse{}
+	for _, usage := range resp.GetUsage() {
+		if usage == nil {
+			continue
+		}
+		out.Usage = append(out.Usage, &VolumeUsage{
+			Available: usage.GetAvailable(),
+			Total:     usage.GetTotal(),
+			Used:      usage.GetUsed(),
+			Unit:      VolumeUsageUnit(usage.GetUnit()),
+		})
+	}
+	if cond := resp.GetVolumeCondition(); cond != nil {
+		out.VolumeCondition = &VolumeCondition{
+			Abnormal: cond.GetAbnormal(),
+			Message:  cond.GetMessage(),
+		}
+	}
+	return out
+}
+
+// VolumeUsageUnit is the unit in which a VolumeUsage is measured.
+type VolumeUsageUnit csipbv1.VolumeUsage_Unit
+
+var (
+	VolumeUsageUnitUnknown = VolumeUsageUnit(csipbv1.VolumeUsage_UNKNOWN)
+	VolumeUsageUnitBytes   = VolumeUsageUnit(csipbv1.VolumeUsage_BYTES)
+	VolumeUsageUnitInodes  = VolumeUsageUnit(csipbv1.VolumeUsage_INODES)
+)
+
+// VolumeUsage is the usage of a published volume, as reported by the node
+// plugin. All values are in the given Unit and a value of zero indicates
+// that the plugin did not report it.
+type VolumeUsage struct {
+	Available int64
+	Total     int64
+	Used      int64
+	Unit      VolumeUsageUnit
+}
+
 type PluginCapabilitySet struct {
 	hasControllerService bool
 	hasTopologies        bool
@@ -622,6 +748,48 @@ func (r *ControllerDeleteVolumeRequest) Validate() error {
 	return nil
 }
 
+type ControllerExpandVolumeRequest struct {
+	ExternalVolumeID string
+	CapacityRange    *CapacityRange
+	Secrets          structs.CSISecrets
+	VolumeCapability *VolumeCapability
+}
+
+func (r *ControllerExpandVolumeRequest) ToCSIRepresentation() *csipbv1.ControllerExpandVolumeRequest {
+	if r == nil {
+		return nil
+	}
+	return &csipbv1.ControllerExpandVolumeRequest{
+		VolumeId:         r.ExternalVolumeID,
+		CapacityRange:    r.CapacityRange.ToCSIRepresentation(),
+		Secrets:          r.Secrets,
+		VolumeCapability: r.VolumeCapability.ToCSIRepresentation(),
+	}
+}
+
+func (r *ControllerExpandVolumeRequest) Validate() error {
+	if r.ExternalVolumeID == "" {
+		return errors.New("missing ExternalVolumeID")
+	}
+	if r.CapacityRange == nil {
+		return errors.New("missing CapacityRange")
+	}
+	if r.CapacityRange.LimitBytes == 0 && r.CapacityRange.RequiredBytes == 0 {
+		return errors.New(
+			"one of LimitBytes or RequiredBytes must be set if CapacityRange is set")
+	}
+	if r.CapacityRange.LimitBytes != 0 &&
+		r.CapacityRange.LimitBytes < r.CapacityRange.RequiredBytes {
+		return errors.New("LimitBytes cannot be less than RequiredBytes")
+	}
+	return nil
+}
+
+type ControllerExpandVolumeResponse struct {
+	CapacityBytes         int64
+	NodeExpansionRequired bool
+}
+
 type ControllerListVolumesRequest struct {
 	MaxEntries    int32
 	StartingToken string
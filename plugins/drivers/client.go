@@ -74,6 +74,8 @@ func (d *driverPluginClient) Capabilities() (*Capabilities, error) {
 
 		caps.MountConfigs = MountConfigSupport(resp.Capabilities.MountConfigs)
 		caps.RemoteTasks = resp.Capabilities.RemoteTasks
+		caps.Checkpoint = resp.Capabilities.Checkpoint
+		caps.Resize = resp.Capabilities.Resize
 	}
 
 	return caps, nil
@@ -497,3 +499,64 @@ func (d *driverPluginClient) DestroyNetwork(allocID string, spec *NetworkIsolati
 
 	return nil
 }
+
+var _ CheckpointDriver = (*driverPluginClient)(nil)
+
+// CheckpointTask dumps the state of the task to the directory, stopping it
+func (d *driverPluginClient) CheckpointTask(taskID string, dir string) error {
+	req := &proto.CheckpointTaskRequest{
+		TaskId: taskID,
+		Dir:    dir,
+	}
+
+	_, err := d.client.CheckpointTask(d.doneCtx, req)
+	if err != nil {
+		return grpcutils.HandleGrpcErr(err, d.doneCtx)
+	}
+
+	return nil
+}
+
+// RestoreTask starts the task from the checkpoint in the directory
+func (d *driverPluginClient) RestoreTask(c *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error) {
+	req := &proto.RestoreTaskRequest{
+		Task: taskConfigToProto(c),
+		Dir:  dir,
+	}
+
+	resp, err := d.client.RestoreTask(d.doneCtx, req)
+	if err != nil {
+		return nil, nil, grpcutils.HandleGrpcErr(err, d.doneCtx)
+	}
+
+	var net *DriverNetwork
+	if resp.NetworkOverride != nil {
+		net = &DriverNetwork{
+			PortMap:       map[string]int{},
+			IP:            resp.NetworkOverride.Addr,
+			AutoAdvertise: resp.NetworkOverride.AutoAdvertise,
+		}
+		for k, v := range resp.NetworkOverride.PortMap {
+			net.PortMap[k] = int(v)
+		}
+	}
+
+	return taskHandleFromProto(resp.Handle), net, nil
+}
+
+var _ ResizeTaskDriver = (*driverPluginClient)(nil)
+
+// ResizeTask updates the resources enforced for the running task
+func (d *driverPluginClient) ResizeTask(taskID string, resources *Resources) error {
+	req := &proto.ResizeTaskRequest{
+		TaskId:    taskID,
+		Resources: ResourcesToProto(resources),
+	}
+
+	_, err := d.client.ResizeTask(d.doneCtx, req)
+	if err != nil {
+		return grpcutils.HandleGrpcErr(err, d.doneCtx)
+	}
+
+	return nil
+}
@@ -94,6 +94,32 @@ type DriverNetworkManager interface {
 	DestroyNetwork(allocID string, spec *NetworkIsolationSpec) error
 }
 
+// CheckpointDriver is the interface implemented by drivers which can
+// checkpoint the state of a running task to disk and restore it, possibly on
+// another node. This only needs to be implemented if the driver sets the
+// Checkpoint capability.
+type CheckpointDriver interface {
+	// CheckpointTask dumps the state of the running task to the directory
+	// and stops the task. The task can be destroyed once the checkpoint is
+	// written.
+	CheckpointTask(taskID string, dir string) error
+
+	// RestoreTask starts the task from the checkpoint in the directory. It
+	// otherwise behaves like StartTask.
+	RestoreTask(config *TaskConfig, dir string) (*TaskHandle, *DriverNetwork, error)
+}
+
+// ResizeTaskDriver is the interface implemented by drivers which can update
+// the resources enforced for a running task without restarting it. This only
+// needs to be implemented if the driver sets the Resize capability.
+type ResizeTaskDriver interface {
+	// ResizeTask enforces the new resources for the running task. If the
+	// resources can't be enforced, such as when lowering the memory limit
+	// below the memory used, an error is returned and the task keeps its
+	// previous resources.
+	ResizeTask(taskID string, resources *Resources) error
+}
+
 // DriverSignalTaskNotSupported can be embedded by drivers which don't support
 // the SignalTask RPC. This satisfies the SignalTask func requirement for the
 // DriverPlugin interface.
@@ -171,6 +197,16 @@ type Capabilities struct {
 	// adjust behavior such as propogating task handles between allocations
 	// to avoid downtime when a client is lost.
 	RemoteTasks bool
+
+	// Checkpoint indicates the driver can checkpoint running tasks and
+	// restore them from a checkpoint. Drivers setting this capability must
+	// implement the CheckpointDriver interface.
+	Checkpoint bool
+
+	// Resize indicates the driver can update the resources of running tasks
+	// in-place. Drivers setting this capability must implement the
+	// ResizeTaskDriver interface.
+	Resize bool
 }
 
 func (c *Capabilities) HasNetIsolationMode(m NetIsolationMode) bool {
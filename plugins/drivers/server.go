@@ -45,6 +45,8 @@ func (b *driverPluginServer) Capabilities(ctx context.Context, req *proto.Capabi
 			MustCreateNetwork:     caps.MustInitiateNetwork,
 			NetworkIsolationModes: []proto.NetworkIsolationSpec_NetworkIsolationMode{},
 			RemoteTasks:           caps.RemoteTasks,
+			Checkpoint:            caps.Checkpoint,
+			Resize:                caps.Resize,
 		},
 	}
 
@@ -416,3 +418,61 @@ func (b *driverPluginServer) DestroyNetwork(ctx context.Context, req *proto.Dest
 
 	return &proto.DestroyNetworkResponse{}, nil
 }
+
+func (b *driverPluginServer) CheckpointTask(ctx context.Context, req *proto.CheckpointTaskRequest) (*proto.CheckpointTaskResponse, error) {
+	cd, ok := b.impl.(CheckpointDriver)
+	if !ok {
+		return nil, fmt.Errorf("CheckpointTask RPC not supported by driver")
+	}
+
+	if err := cd.CheckpointTask(req.TaskId, req.Dir); err != nil {
+		return nil, err
+	}
+
+	return &proto.CheckpointTaskResponse{}, nil
+}
+
+func (b *driverPluginServer) RestoreTask(ctx context.Context, req *proto.RestoreTaskRequest) (*proto.RestoreTaskResponse, error) {
+	cd, ok := b.impl.(CheckpointDriver)
+	if !ok {
+		return nil, fmt.Errorf("RestoreTask RPC not supported by driver")
+	}
+
+	handle, net, err := cd.RestoreTask(taskConfigFromProto(req.Task), req.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pbNet *proto.NetworkOverride
+	if net != nil {
+		pbNet = &proto.NetworkOverride{
+			PortMap:       map[string]int32{},
+			Addr:          net.IP,
+			AutoAdvertise: net.AutoAdvertise,
+		}
+		for k, v := range net.PortMap {
+			if v > math.MaxInt32 {
+				return nil, fmt.Errorf("port map out of bounds")
+			}
+			pbNet.PortMap[k] = int32(v)
+		}
+	}
+
+	return &proto.RestoreTaskResponse{
+		Handle:          taskHandleToProto(handle),
+		NetworkOverride: pbNet,
+	}, nil
+}
+
+func (b *driverPluginServer) ResizeTask(ctx context.Context, req *proto.ResizeTaskRequest) (*proto.ResizeTaskResponse, error) {
+	rd, ok := b.impl.(ResizeTaskDriver)
+	if !ok {
+		return nil, fmt.Errorf("ResizeTask RPC not supported by driver")
+	}
+
+	if err := rd.ResizeTask(req.TaskId, ResourcesFromProto(req.Resources)); err != nil {
+		return nil, err
+	}
+
+	return &proto.ResizeTaskResponse{}, nil
+}
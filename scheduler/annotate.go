@@ -178,7 +178,7 @@ FieldsLoop:
 	}
 
 	// Object changes that can be done in-place are log configs, services,
-	// constraints.
+	// constraints, and CPU and memory resources.
 
 	if !destructive {
 	ObjectsLoop:
@@ -186,6 +186,12 @@ FieldsLoop:
 			switch oDiff.Name {
 			case "LogConfig", "Service", "Constraint":
 				continue
+			case "Resources":
+				if resourcesDiffInplace(oDiff) {
+					continue
+				}
+				destructive = true
+				break ObjectsLoop
 			default:
 				destructive = true
 				break ObjectsLoop
@@ -199,3 +205,19 @@ FieldsLoop:
 		diff.Annotations = append(diff.Annotations, AnnotationForcesInplaceUpdate)
 	}
 }
+
+// resourcesDiffInplace returns true if the resources diff only edits the CPU
+// and memory resources, which are updated in-place.
+func resourcesDiffInplace(diff *structs.ObjectDiff) bool {
+	if diff.Type != structs.DiffTypeEdited || len(diff.Objects) != 0 {
+		return false
+	}
+	for _, fDiff := range diff.Fields {
+		switch fDiff.Name {
+		case "CPU", "MemoryMB", "MemoryMaxMB":
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package scheduler
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// JobDependenciesPendingDesc is the status description of evaluations of
+	// jobs that wait on their dependencies.
+	JobDependenciesPendingDesc = "job dependencies are not satisfied"
+
+	// jobDependencyStatusMissing is the status of a dependency on a job that
+	// doesn't exist.
+	jobDependencyStatusMissing = "missing"
+)
+
+// JobDependenciesStatus returns the status of each dependency of the job.
+// The upstream jobs are added to the watch set.
+func JobDependenciesStatus(ws memdb.WatchSet, state State, job *structs.Job) ([]*structs.JobDependencyStatus, error) {
+	statuses := make([]*structs.JobDependencyStatus, 0, len(job.DependsOn))
+	for _, d := range job.DependsOn {
+		status, err := jobDependencyStatus(ws, state, job.Namespace, d)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// JobDependenciesSatisfied returns whether all of the dependencies of the job
+// are satisfied.
+func JobDependenciesSatisfied(state State, job *structs.Job) (bool, error) {
+	statuses, err := JobDependenciesStatus(nil, state, job)
+	if err != nil {
+		return false, err
+	}
+	for _, status := range statuses {
+		if !status.Satisfied {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// JobDependenciesPending returns whether the placement of the job waits on
+// its dependencies, which is the case until they are satisfied for a version
+// of the job without allocations. Once the job has allocations of its current
+// version, it is scheduled as usual.
+func JobDependenciesPending(state State, job *structs.Job) (bool, error) {
+	if job.Stopped() || len(job.DependsOn) == 0 {
+		return false, nil
+	}
+
+	allocs, err := state.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to get allocations of job %q: %v", job.ID, err)
+	}
+	for _, alloc := range allocs {
+		if alloc.Job != nil && alloc.Job.JobModifyIndex == job.JobModifyIndex {
+			return false, nil
+		}
+	}
+
+	satisfied, err := JobDependenciesSatisfied(state, job)
+	if err != nil {
+		return false, err
+	}
+	return !satisfied, nil
+}
+
+// jobDependencyStatus returns the status of a dependency. A dependency on a
+// periodic or parameterized job is on all of its children, of which there
+// must be at least one.
+func jobDependencyStatus(ws memdb.WatchSet, state State, namespace string, d *structs.JobDependency) (*structs.JobDependencyStatus, error) {
+	status := &structs.JobDependencyStatus{
+		JobID:     d.JobID,
+		Condition: d.Condition,
+	}
+
+	upstream, err := state.JobByID(ws, namespace, d.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %q: %v", d.JobID, err)
+	}
+	if upstream == nil {
+		status.Status = jobDependencyStatusMissing
+		status.Description = "job not found"
+		return status, nil
+	}
+	status.Status = upstream.Status
+
+	if !upstream.IsPeriodic() && !upstream.IsParameterized() {
+		status.Satisfied, status.Description, err = jobCompleted(state, upstream, d.Condition)
+		return status, err
+	}
+
+	iter, err := state.JobsByIDPrefix(ws, namespace, upstream.ID+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children of job %q: %v", d.JobID, err)
+	}
+	children := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		child := raw.(*structs.Job)
+		if child.ParentID != upstream.ID {
+			continue
+		}
+		children++
+
+		satisfied, desc, err := jobCompleted(state, child, d.Condition)
+		if err != nil {
+			return nil, err
+		}
+		if !satisfied {
+			status.Status = child.Status
+			status.Description = fmt.Sprintf("child job %q: %s", child.ID, desc)
+			return status, nil
+		}
+	}
+	if children == 0 {
+		status.Description = "job has no children"
+		return status, nil
+	}
+
+	status.Status = structs.JobStatusDead
+	status.Satisfied = true
+	return status, nil
+}
+
+// jobCompleted returns whether the job meets the completion condition, along
+// with the reason if it doesn't. Only the latest allocations are considered
+// for success, so allocations replaced by a successful reschedule don't fail
+// the condition.
+func jobCompleted(state State, job *structs.Job, condition string) (bool, string, error) {
+	if job.Status != structs.JobStatusDead {
+		return false, "job is " + job.Status, nil
+	}
+	if condition == structs.JobDependencyConditionComplete {
+		return true, "", nil
+	}
+	if job.Stop {
+		return false, "job was stopped", nil
+	}
+
+	allocs, err := state.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get allocations of job %q: %v", job.ID, err)
+	}
+	succeeded := false
+	for _, alloc := range allocs {
+		if alloc.NextAllocation != "" || alloc.Job == nil || alloc.Job.JobModifyIndex != job.JobModifyIndex {
+			continue
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusComplete {
+			return false, fmt.Sprintf("allocation %q is %s", alloc.ID, alloc.ClientStatus), nil
+		}
+		succeeded = true
+	}
+	if !succeeded {
+		return false, "job has no completed allocations", nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testDependencyUpstream upserts a dead batch job with an allocation of the
+// given client status.
+func testDependencyUpstream(t *testing.T, store *state.StateStore, index uint64, clientStatus string) *structs.Job {
+	job := mock.BatchJob()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, index, job))
+	job, err := store.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.DesiredStatus = structs.AllocDesiredStatusRun
+	alloc.ClientStatus = clientStatus
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, index+1, []*structs.Allocation{alloc}))
+
+	job, err = store.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, structs.JobStatusDead, job.Status)
+	return job
+}
+
+func TestJobDependenciesStatus(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	succeeded := testDependencyUpstream(t, store, 100, structs.AllocClientStatusComplete)
+	failed := testDependencyUpstream(t, store, 200, structs.AllocClientStatusFailed)
+
+	running := mock.Job()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 300, running))
+
+	cases := []struct {
+		name      string
+		dep       *structs.JobDependency
+		status    string
+		satisfied bool
+	}{
+		{"missing", &structs.JobDependency{JobID: "missing", Condition: structs.JobDependencyConditionSuccess}, "missing", false},
+		{"running", &structs.JobDependency{JobID: running.ID, Condition: structs.JobDependencyConditionComplete}, structs.JobStatusPending, false},
+		{"succeeded", &structs.JobDependency{JobID: succeeded.ID, Condition: structs.JobDependencyConditionSuccess}, structs.JobStatusDead, true},
+		{"failed success", &structs.JobDependency{JobID: failed.ID, Condition: structs.JobDependencyConditionSuccess}, structs.JobStatusDead, false},
+		{"failed complete", &structs.JobDependency{JobID: failed.ID, Condition: structs.JobDependencyConditionComplete}, structs.JobStatusDead, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			job.DependsOn = []*structs.JobDependency{tc.dep}
+
+			statuses, err := JobDependenciesStatus(nil, store, job)
+			require.NoError(t, err)
+			require.Len(t, statuses, 1)
+			require.Equal(t, tc.status, statuses[0].Status)
+			require.Equal(t, tc.satisfied, statuses[0].Satisfied)
+			if !tc.satisfied {
+				require.NotEmpty(t, statuses[0].Description)
+			}
+
+			satisfied, err := JobDependenciesSatisfied(store, job)
+			require.NoError(t, err)
+			require.Equal(t, tc.satisfied, satisfied)
+		})
+	}
+}
+
+func TestJobDependenciesStatus_Children(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	parent := mock.BatchJob()
+	parent.ParameterizedJob = &structs.ParameterizedJobConfig{}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 100, parent))
+
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{
+		{JobID: parent.ID, Condition: structs.JobDependencyConditionSuccess},
+	}
+
+	// A parameterized job without children doesn't satisfy dependencies
+	statuses, err := JobDependenciesStatus(nil, store, job)
+	require.NoError(t, err)
+	require.False(t, statuses[0].Satisfied)
+	require.Equal(t, "job has no children", statuses[0].Description)
+
+	child := parent.Copy()
+	child.ID = parent.ID + "/dispatch-1"
+	child.ParentID = parent.ID
+	child.Dispatched = true
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, child))
+	child, err = store.JobByID(nil, child.Namespace, child.ID)
+	require.NoError(t, err)
+
+	statuses, err = JobDependenciesStatus(nil, store, job)
+	require.NoError(t, err)
+	require.False(t, statuses[0].Satisfied)
+	require.Contains(t, statuses[0].Description, child.ID)
+
+	alloc := mock.Alloc()
+	alloc.Job = child
+	alloc.JobID = child.ID
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 400, []*structs.Allocation{alloc}))
+
+	statuses, err = JobDependenciesStatus(nil, store, job)
+	require.NoError(t, err)
+	require.True(t, statuses[0].Satisfied)
+}
+
+func TestJobDependenciesPending(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	running := mock.Job()
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 100, running))
+
+	job := mock.Job()
+	job.DependsOn = []*structs.JobDependency{
+		{JobID: running.ID, Condition: structs.JobDependencyConditionSuccess},
+	}
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, job))
+	job, err := store.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+
+	pending, err := JobDependenciesPending(store, job)
+	require.NoError(t, err)
+	require.True(t, pending)
+
+	// Stopped jobs don't wait on their dependencies
+	stopped := job.Copy()
+	stopped.Stop = true
+	pending, err = JobDependenciesPending(store, stopped)
+	require.NoError(t, err)
+	require.False(t, pending)
+
+	// Nor do jobs already placed
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 300, []*structs.Allocation{alloc}))
+
+	pending, err = JobDependenciesPending(store, job)
+	require.NoError(t, err)
+	require.False(t, pending)
+}
@@ -27,6 +27,7 @@ const (
 	FilterConstraintCSIVolumeGCdAllocationTemplate = "CSI volume %s has exhausted its available writer claims and is claimed by a garbage collected allocation %s; waiting for claim to be released"
 	FilterConstraintDrivers                        = "missing drivers"
 	FilterConstraintDevices                        = "missing devices"
+	FilterConstraintLogSinks                       = "log sinks not allowed"
 	FilterConstraintsCSIPluginTopology             = "did not meet topology requirement"
 )
 
@@ -449,6 +450,64 @@ func (c *NetworkChecker) hasNetwork(option *structs.Node) bool {
 	return false
 }
 
+// LogSinkChecker is a FeasibilityChecker which returns whether a node allows
+// the tasks of a task group to forward their logs to their log sinks.
+type LogSinkChecker struct {
+	ctx   Context
+	sinks []*structs.LogSink
+}
+
+func NewLogSinkChecker(ctx Context) *LogSinkChecker {
+	return &LogSinkChecker{ctx: ctx}
+}
+
+// SetTaskGroup sets the task group whose log sinks are checked.
+func (c *LogSinkChecker) SetTaskGroup(tg *structs.TaskGroup) {
+	c.sinks = nil
+	for _, task := range tg.Tasks {
+		if task.LogConfig != nil {
+			c.sinks = append(c.sinks, task.LogConfig.Sinks...)
+		}
+	}
+}
+
+func (c *LogSinkChecker) Feasible(option *structs.Node) bool {
+	if c.allowsSinks(option) {
+		return true
+	}
+
+	c.ctx.Metrics().FilterNode(option, FilterConstraintLogSinks)
+	return false
+}
+
+// allowsSinks returns whether the log_sinks configuration fingerprinted by
+// the node allows the sinks.
+func (c *LogSinkChecker) allowsSinks(option *structs.Node) bool {
+	if len(c.sinks) == 0 {
+		return true
+	}
+
+	addresses := splitNodeAttr(option.Attributes[structs.NodeAttrLogSinksAllowedAddresses])
+	sockets := splitNodeAttr(option.Attributes[structs.NodeAttrLogSinksAllowedSockets])
+	for _, sink := range c.sinks {
+		if option.Attributes[structs.NodeAttrLogSinksPrefix+sink.Type] != "true" {
+			return false
+		}
+		if err := sink.CheckAllowed(addresses, sockets); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// splitNodeAttr splits the comma separated values of a node attribute.
+func splitNodeAttr(attr string) []string {
+	if attr == "" {
+		return nil
+	}
+	return strings.Split(attr, ",")
+}
+
 // DriverChecker is a FeasibilityChecker which returns whether a node has the
 // drivers necessary to scheduler a task group.
 type DriverChecker struct {
@@ -806,7 +865,8 @@ func resolveTarget(target string, node *structs.Node) (interface{}, bool) {
 func checkConstraint(ctx Context, operand string, lVal, rVal interface{}, lFound, rFound bool) bool {
 	// Check for constraints not handled by this checker.
 	switch operand {
-	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty:
+	case structs.ConstraintDistinctHosts, structs.ConstraintDistinctProperty,
+		structs.ConstraintJobAffinity, structs.ConstraintJobAntiAffinity:
 		return true
 	default:
 		break
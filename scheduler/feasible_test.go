@@ -353,7 +353,7 @@ func TestCSIVolumeChecker(t *testing.T) {
 		{Segments: map[string]string{"rack": "R1"}},
 		{Segments: map[string]string{"rack": "R2"}},
 	}
-	err := state.UpsertCSIVolume(index, []*structs.CSIVolume{vol})
+	err := state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol})
 	require.NoError(t, err)
 	index++
 
@@ -364,14 +364,14 @@ func TestCSIVolumeChecker(t *testing.T) {
 	vol2.Namespace = structs.DefaultNamespace
 	vol2.AccessMode = structs.CSIVolumeAccessModeMultiNodeSingleWriter
 	vol2.AttachmentMode = structs.CSIVolumeAttachmentModeFilesystem
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{vol2})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol2})
 	require.NoError(t, err)
 	index++
 
 	vid3 := "volume-id[0]"
 	vol3 := vol.Copy()
 	vol3.ID = vid3
-	err = state.UpsertCSIVolume(index, []*structs.CSIVolume{vol3})
+	err = state.UpsertCSIVolume(structs.MsgTypeTestSetup, index, []*structs.CSIVolume{vol3})
 	require.NoError(t, err)
 	index++
 
@@ -650,6 +650,71 @@ func TestNetworkChecker(t *testing.T) {
 	}
 }
 
+func TestLogSinkChecker(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+
+	// A node without log sinks enabled, one only allowing local syslog and
+	// one allowing forwarding to the collector
+	disabled := mock.Node()
+	local := mock.Node()
+	local.Attributes["nomad.log_sinks.syslog"] = "true"
+	collector := mock.Node()
+	collector.Attributes["nomad.log_sinks.syslog"] = "true"
+	collector.Attributes["nomad.log_sinks.tcp"] = "true"
+	collector.Attributes["nomad.log_sinks.udp"] = "true"
+	collector.Attributes["nomad.log_sinks.unix"] = "true"
+	collector.Attributes["nomad.log_sinks.allowed_addresses"] = "10.0.0.5:514,logs.example.com:*"
+	collector.Attributes["nomad.log_sinks.allowed_sockets"] = "/run/collector/*.sock"
+	nodes := []*structs.Node{disabled, local, collector}
+
+	cases := []struct {
+		name   string
+		sinks  []*structs.LogSink
+		result []bool
+	}{
+		{
+			name:   "no sinks",
+			result: []bool{true, true, true},
+		},
+		{
+			name:   "local syslog",
+			sinks:  []*structs.LogSink{{Name: "s", Type: "syslog"}},
+			result: []bool{false, true, true},
+		},
+		{
+			name: "allowed destinations",
+			sinks: []*structs.LogSink{
+				{Name: "a", Type: "tcp", Address: "logs.example.com:5170"},
+				{Name: "b", Type: "syslog", Address: "udp://10.0.0.5:514"},
+				{Name: "c", Type: "unix", Address: "/run/collector/logs.sock"},
+			},
+			result: []bool{false, false, true},
+		},
+		{
+			name: "disallowed destination",
+			sinks: []*structs.LogSink{
+				{Name: "a", Type: "tcp", Address: "logs.example.com:5170"},
+				{Name: "b", Type: "udp", Address: "169.254.169.254:80"},
+			},
+			result: []bool{false, false, false},
+		},
+	}
+
+	checker := NewLogSinkChecker(ctx)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tg := mock.Job().TaskGroups[0]
+			tg.Tasks[0].LogConfig.Sinks = c.sinks
+			checker.SetTaskGroup(tg)
+			for i, node := range nodes {
+				require.Equal(t, c.result[i], checker.Feasible(node), "node %d", i)
+			}
+		})
+	}
+}
+
 func TestNetworkChecker_bridge_upgrade_path(t *testing.T) {
 	ci.Parallel(t)
 
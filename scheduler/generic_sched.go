@@ -111,6 +111,10 @@ type GenericScheduler struct {
 
 	deployment *structs.Deployment
 
+	// dependenciesPending is whether the placement of the job's current
+	// version waits on its dependencies
+	dependenciesPending bool
+
 	blocked        *structs.Evaluation
 	failedTGAllocs map[string]*structs.AllocMetric
 	queuedAllocs   map[string]int
@@ -164,7 +168,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerRebalance, structs.EvalTriggerJobDependency:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -208,9 +213,16 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		return s.planner.ReblockEval(newEval)
 	}
 
+	// Record that the placement of the job waits on its dependencies, so
+	// it's evaluated again once they are satisfied
+	desc := ""
+	if s.dependenciesPending {
+		desc = JobDependenciesPendingDesc
+	}
+
 	// Update the status to complete
 	return setStatus(s.logger, s.planner, s.eval, nil, s.blocked,
-		s.failedTGAllocs, structs.EvalStatusComplete, "", s.queuedAllocs,
+		s.failedTGAllocs, structs.EvalStatusComplete, desc, s.queuedAllocs,
 		s.deployment.GetID())
 }
 
@@ -375,10 +387,21 @@ func (s *GenericScheduler) computeJobAllocs() error {
 	// nodes to lost, but only if the scheduler has already marked them
 	updateNonTerminalAllocsToLost(s.plan, tainted, allocs)
 
+	// Hold the placement of the job's current version until its dependencies
+	// are satisfied, while still stopping and replacing its allocations
+	s.dependenciesPending, err = JobDependenciesPending(s.state, s.job)
+	if err != nil {
+		return err
+	}
+	if s.dependenciesPending {
+		s.logger.Debug("job dependencies are not satisfied")
+	}
+
 	reconciler := NewAllocReconciler(s.logger,
 		genericAllocUpdateFn(s.ctx, s.stack, s.eval.ID),
 		s.batch, s.eval.JobID, s.job, s.deployment, allocs, tainted, s.eval.ID,
 		s.eval.Priority, s.planner.ServersMeetMinimumVersion(minVersionMaxClientDisconnect, true))
+	reconciler.dependenciesPending = s.dependenciesPending
 
 	results := reconciler.Compute()
 	s.logger.Debug("reconciled current state with desired state", "results", log.Fmt("%#v", results))
@@ -514,6 +537,10 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 	// Capture current time to use as the start time for any rescheduled allocations
 	now := time.Now()
 
+	// Track the placements of gang scheduled task groups so they can be
+	// removed from the plan if their gang can't be fully placed
+	gangs := make(map[string][]gangPlacement)
+
 	// Have to handle destructive changes first as we need to discount their
 	// resources. To understand this imagine the resources were reduced and the
 	// count was scaled up.
@@ -653,6 +680,14 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 				// Track the placement
 				s.plan.AppendAlloc(alloc, downgradedJob)
 
+				if s.job.Gang || tg.Gang {
+					placement := gangPlacement{alloc: alloc}
+					if stopPrevAlloc {
+						placement.stopped = prevAllocation
+					}
+					gangs[tg.Name] = append(gangs[tg.Name], placement)
+				}
+
 			} else {
 				// Lazy initialize the failed map
 				if s.failedTGAllocs == nil {
@@ -675,9 +710,69 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 		}
 	}
 
+	s.removeFailedGangs(gangs)
 	return nil
 }
 
+// gangPlacement is an allocation placed as part of a gang.
+type gangPlacement struct {
+	alloc *structs.Allocation
+
+	// stopped is the previous allocation stopped by the placement
+	stopped *structs.Allocation
+}
+
+// removeFailedGangs removes the placements of gangs with task groups which
+// failed to be placed from the plan, so that a gang's allocations are only
+// placed once all of them can be. The placements of a job level gang are
+// removed if any of the job's task groups failed to be placed, and those of a
+// task group level gang if the task group failed to be placed. The removed
+// placements are tracked as failed so that a blocked eval is created for them.
+func (s *GenericScheduler) removeFailedGangs(gangs map[string][]gangPlacement) {
+	if len(gangs) == 0 {
+		return
+	}
+
+	var failed []string
+	for name := range s.failedTGAllocs {
+		failed = append(failed, name)
+	}
+	sort.Strings(failed)
+
+	for name, placements := range gangs {
+		_, tgFailed := s.failedTGAllocs[name]
+		if !tgFailed && !(s.job.Gang && len(failed) > 0) {
+			continue
+		}
+
+		for _, p := range placements {
+			s.plan.RemoveAlloc(p.alloc)
+			if p.stopped != nil {
+				s.plan.RemoveUpdate(p.stopped)
+			}
+		}
+		delete(gangs, name)
+
+		if tgFailed {
+			s.failedTGAllocs[name].CoalescedFailures += len(placements)
+			continue
+		}
+
+		// Report the task groups that caused the placements to be removed,
+		// using the metrics of one of the placements
+		metric := placements[0].alloc.Metrics.Copy()
+		metric.CoalescedFailures = len(placements) - 1
+		metric.GangFailures = failed
+		s.failedTGAllocs[name] = metric
+	}
+
+	// The plan must be applied atomically for the remaining gangs to be
+	// placed together
+	if len(gangs) > 0 {
+		s.plan.AllAtOnce = true
+	}
+}
+
 // propagateTaskState copies task handles from previous allocations to
 // replacement allocations when the previous allocation is being drained or was
 // lost. Remote task drivers rely on this to reconnect to remote tasks when the
@@ -729,6 +824,12 @@ func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs
 		if prevAllocation.ClientStatus == structs.AllocClientStatusFailed {
 			penaltyNodes[prevAllocation.NodeID] = struct{}{}
 		}
+
+		// If alloc is migrated off a node that is still eligible, such as
+		// by the rebalancer, penalize the node so it isn't placed back.
+		if prevAllocation.DesiredTransition.ShouldMigrate() {
+			penaltyNodes[prevAllocation.NodeID] = struct{}{}
+		}
 		if prevAllocation.RescheduleTracker != nil {
 			for _, reschedEvent := range prevAllocation.RescheduleTracker.Events {
 				penaltyNodes[reschedEvent.PrevNodeID] = struct{}{}
@@ -221,6 +221,82 @@ func TestServiceSched_JobRegister_MemoryMaxHonored(t *testing.T) {
 	}
 }
 
+func TestServiceSched_JobRegister_DependenciesPending(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create a down node and some ready nodes
+	down := mock.Node()
+	down.Status = structs.NodeStatusDown
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), down))
+	var nodes []*structs.Node
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job waiting on a running job
+	upstream := mock.Job()
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), upstream))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 3
+	job.DependsOn = []*structs.JobDependency{
+		{JobID: upstream.ID, Condition: structs.JobDependencyConditionSuccess},
+	}
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+	job, err := h.State.JobByID(nil, job.Namespace, job.ID)
+	require.NoError(t, err)
+
+	// Create allocations of a previous version, one of which is lost
+	old := job.Copy()
+	old.JobModifyIndex--
+	var allocs []*structs.Allocation
+	for i, nodeID := range []string{down.ID, nodes[0].ID} {
+		alloc := mock.Alloc()
+		alloc.Job = old
+		alloc.JobID = job.ID
+		alloc.NodeID = nodeID
+		alloc.Name = structs.AllocName(job.ID, job.TaskGroups[0].Name, uint(i))
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		NodeID:      down.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// The lost allocation is stopped and replaced, but the job isn't scaled
+	// up nor updated until its dependencies are satisfied
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.Len(t, plan.NodeUpdate[down.ID], 1)
+	require.Equal(t, allocs[0].ID, plan.NodeUpdate[down.ID][0].ID)
+
+	var placed []*structs.Allocation
+	for _, allocs := range plan.NodeAllocation {
+		placed = append(placed, allocs...)
+	}
+	require.Len(t, placed, 1)
+	require.Equal(t, allocs[0].ID, placed[0].PreviousAllocation)
+
+	require.Len(t, h.Evals, 1)
+	require.Equal(t, structs.EvalStatusComplete, h.Evals[0].Status)
+	require.Equal(t, JobDependenciesPendingDesc, h.Evals[0].StatusDescription)
+}
+
 func TestServiceSched_JobRegister_StickyAllocs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -267,7 +343,7 @@ func TestServiceSched_JobRegister_StickyAllocs(t *testing.T) {
 
 	// Update the job to force a rolling upgrade
 	updated := job.Copy()
-	updated.TaskGroups[0].Tasks[0].Resources.CPU += 10
+	updated.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
 	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), updated))
 
 	// Create a mock evaluation to handle the update
@@ -2439,6 +2515,64 @@ func TestServiceSched_JobModify_InPlace(t *testing.T) {
 	}
 }
 
+func TestServiceSched_JobModify_InPlace_Resize(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	alloc := mock.AllocForNode(node)
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.Name = "my-job.web[0]"
+	require.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{alloc}))
+
+	process := func(job *structs.Job) *structs.Evaluation {
+		require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    50,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+		require.NoError(t, h.Process(NewServiceScheduler, eval))
+		return h.Evals[len(h.Evals)-1]
+	}
+
+	// Resources that fit on the node are updated in-place
+	job2 := job.Copy()
+	job2.TaskGroups[0].Tasks[0].Resources.CPU = 1000
+	job2.TaskGroups[0].Tasks[0].Resources.MemoryMB = 512
+	process(job2)
+	require.Len(t, h.Plans, 1)
+	plan := h.Plans[0]
+	require.Empty(t, plan.NodeUpdate[node.ID])
+	require.Len(t, plan.NodeAllocation[node.ID], 1)
+	updated := plan.NodeAllocation[node.ID][0]
+	require.Equal(t, alloc.ID, updated.ID)
+	require.Equal(t, int64(1000), updated.AllocatedResources.Tasks["web"].Cpu.CpuShares)
+	require.Equal(t, int64(512), updated.AllocatedResources.Tasks["web"].Memory.MemoryMB)
+
+	// Resources that don't fit on the node require a destructive update,
+	// which can't be placed
+	job3 := job.Copy()
+	job3.TaskGroups[0].Tasks[0].Resources.CPU = 5000
+	eval := process(job3)
+	require.Contains(t, eval.FailedTGAllocs, "web")
+	for _, plan := range h.Plans[1:] {
+		require.Empty(t, plan.NodeAllocation[node.ID])
+	}
+}
+
 // TestServiceSched_JobModify_InPlace08 asserts that inplace updates of
 // allocations created with Nomad 0.8 do not cause panics.
 //
@@ -4925,6 +5059,121 @@ func TestBatchSched_ScaleDown_SameName(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestBatchSched_Gang(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		nodes  int
+		placed int
+	}{
+		{name: "all placed", nodes: 3, placed: 3},
+		{name: "partially placeable", nodes: 2, placed: 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := NewHarness(t)
+
+			for i := 0; i < tc.nodes; i++ {
+				node := mock.Node()
+				require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			// Create a gang scheduled job which needs a node per allocation
+			job := mock.BatchJob()
+			job.TaskGroups[0].Count = 3
+			job.TaskGroups[0].Gang = true
+			job.TaskGroups[0].Constraints = append(job.TaskGroups[0].Constraints,
+				&structs.Constraint{Operand: structs.ConstraintDistinctHosts})
+			require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    job.Priority,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			// Process the evaluation
+			require.NoError(t, h.Process(NewBatchScheduler, eval))
+			h.AssertEvalStatus(t, structs.EvalStatusComplete)
+
+			out, err := h.State.AllocsByJob(nil, job.Namespace, job.ID, false)
+			require.NoError(t, err)
+			require.Len(t, out, tc.placed)
+
+			tgName := job.TaskGroups[0].Name
+			if tc.placed > 0 {
+				// The plan must be applied atomically
+				require.Len(t, h.Plans, 1)
+				require.True(t, h.Plans[0].AllAtOnce)
+				require.Empty(t, h.Evals[0].FailedTGAllocs)
+				return
+			}
+
+			// No placements are made and the gang is blocked
+			require.Empty(t, h.Plans)
+			require.Len(t, h.CreateEvals, 1)
+			require.Equal(t, structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+
+			metrics, ok := h.Evals[0].FailedTGAllocs[tgName]
+			require.True(t, ok)
+			require.Equal(t, 2, metrics.CoalescedFailures)
+			require.Equal(t, 3, h.Evals[0].QueuedAllocations[tgName])
+		})
+	}
+}
+
+func TestBatchSched_Gang_Job(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	node := mock.Node()
+	require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+
+	// Create a gang scheduled job with a task group that can't be placed
+	job := mock.BatchJob()
+	job.Gang = true
+	workers := job.TaskGroups[0].Copy()
+	workers.Name = "workers"
+	workers.Constraints = append(workers.Constraints, &structs.Constraint{
+		LTarget: "${node.class}",
+		RTarget: "gpu",
+		Operand: "=",
+	})
+	job.TaskGroups = append(job.TaskGroups, workers)
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewBatchScheduler, eval))
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+
+	// The placeable task group isn't placed either
+	require.Empty(t, h.Plans)
+	require.Len(t, h.CreateEvals, 1)
+
+	failed := h.Evals[0].FailedTGAllocs
+	require.Len(t, failed, 2)
+	require.Empty(t, failed["workers"].GangFailures)
+	require.Equal(t, []string{"workers"}, failed[job.TaskGroups[0].Name].GangFailures)
+	require.Equal(t, job.TaskGroups[0].Count-1, failed[job.TaskGroups[0].Name].CoalescedFailures)
+}
+
 func TestGenericSched_AllocFit_Lifecycle(t *testing.T) {
 	ci.Parallel(t)
 
@@ -6268,7 +6517,7 @@ func TestServiceSched_CSIVolumesPerAlloc(t *testing.T) {
 	// once its been fixed
 	shared.AccessMode = structs.CSIVolumeAccessModeMultiNodeReader
 
-	require.NoError(h.State.UpsertCSIVolume(
+	require.NoError(h.State.UpsertCSIVolume(structs.MsgTypeTestSetup,
 		h.NextIndex(), []*structs.CSIVolume{shared, vol0, vol1, vol2}))
 
 	// Create a job that uses both
@@ -6361,7 +6610,7 @@ func TestServiceSched_CSIVolumesPerAlloc(t *testing.T) {
 	vol4.ID = "volume-unique[3]"
 	vol5 := vol0.Copy()
 	vol5.ID = "volume-unique[4]"
-	require.NoError(h.State.UpsertCSIVolume(
+	require.NoError(h.State.UpsertCSIVolume(structs.MsgTypeTestSetup,
 		h.NextIndex(), []*structs.CSIVolume{vol4, vol5}))
 
 	// Process again with failure fixed. It should create a new plan
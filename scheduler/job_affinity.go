@@ -0,0 +1,338 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobTopology is the set of values of a node attribute across the nodes
+// running allocations of the jobs selected by a job affinity or anti-affinity.
+type jobTopology struct {
+	// attribute is the node attribute, such as ${meta.rack}, the topology is
+	// built from
+	attribute string
+
+	// values is the set of attribute values of the nodes running the
+	// selected jobs' allocations
+	values map[string]struct{}
+
+	// errorBuilding marks whether there was an error when building the
+	// topology
+	errorBuilding error
+}
+
+// contains returns whether the node's attribute value is in the topology, and
+// whether the node has the attribute at all.
+func (t *jobTopology) contains(n *structs.Node) (contains, found bool) {
+	val, ok := getProperty(n, t.attribute)
+	if !ok {
+		return false, false
+	}
+	_, contains = t.values[val]
+	return contains, true
+}
+
+// jobTopologies memoizes the topologies of the job affinities and
+// anti-affinities of the job being scheduled. The topologies are built from
+// the state snapshot once per job since allocations of other jobs can't change
+// during the evaluation.
+type jobTopologies struct {
+	ctx    Context
+	job    *structs.Job
+	logger log.Logger
+
+	topologies map[string]*jobTopology
+}
+
+func newJobTopologies(ctx Context) *jobTopologies {
+	return &jobTopologies{
+		ctx:        ctx,
+		logger:     ctx.Logger().Named("job_topology"),
+		topologies: make(map[string]*jobTopology),
+	}
+}
+
+// SetJob sets the job being scheduled and clears the memoized topologies.
+func (t *jobTopologies) SetJob(job *structs.Job) {
+	t.job = job
+	t.topologies = make(map[string]*jobTopology)
+}
+
+// get returns the topology for the given node attribute and job selector.
+func (t *jobTopologies) get(attribute, selector string) *jobTopology {
+	key := attribute + "\x00" + selector
+	if topo, ok := t.topologies[key]; ok {
+		return topo
+	}
+
+	topo := &jobTopology{
+		attribute: attribute,
+		values:    make(map[string]struct{}),
+	}
+	if err := t.build(topo, selector); err != nil {
+		topo.errorBuilding = err
+		t.logger.Error("failed to build job topology", "attribute", attribute, "selector", selector, "error", err)
+	}
+	t.topologies[key] = topo
+	return topo
+}
+
+func (t *jobTopologies) build(topo *jobTopology, selector string) error {
+	sel, err := structs.ParseJobSelector(selector)
+	if err != nil {
+		return err
+	}
+	if sel.Namespace == "" {
+		sel.Namespace = t.job.Namespace
+	}
+
+	jobs, err := t.selectJobs(sel)
+	if err != nil {
+		return err
+	}
+
+	ws := memdb.NewWatchSet()
+	seen := make(map[string]struct{})
+	for _, job := range jobs {
+		allocs, err := t.ctx.State().AllocsByJob(ws, job.Namespace, job.ID, false)
+		if err != nil {
+			return fmt.Errorf("failed to get allocations of job %q: %v", job.ID, err)
+		}
+
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() {
+				continue
+			}
+			if _, ok := seen[alloc.NodeID]; ok {
+				continue
+			}
+			seen[alloc.NodeID] = struct{}{}
+
+			node, err := t.ctx.State().NodeByID(ws, alloc.NodeID)
+			if err != nil {
+				return fmt.Errorf("failed to lookup node ID %q: %v", alloc.NodeID, err)
+			}
+			if val, ok := getProperty(node, topo.attribute); ok {
+				topo.values[val] = struct{}{}
+			}
+		}
+	}
+	return nil
+}
+
+// selectJobs returns the jobs matched by the selector, excluding the job being
+// scheduled whose placements are governed by distinct_hosts and
+// distinct_property instead.
+func (t *jobTopologies) selectJobs(sel *structs.JobSelector) ([]*structs.Job, error) {
+	ws := memdb.NewWatchSet()
+	var jobs []*structs.Job
+	add := func(job *structs.Job) {
+		if job == nil || (job.Namespace == t.job.Namespace && job.ID == t.job.ID) {
+			return
+		}
+		if sel.Matches(job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	// Hot path for selecting a single job
+	if sel.JobID != "" && sel.Namespace != structs.AllNamespacesSentinel {
+		job, err := t.ctx.State().JobByID(ws, sel.Namespace, sel.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup job %q: %v", sel.JobID, err)
+		}
+		add(job)
+		return jobs, nil
+	}
+
+	var iter memdb.ResultIterator
+	var err error
+	if sel.Namespace == structs.AllNamespacesSentinel {
+		iter, err = t.ctx.State().Jobs(ws)
+	} else {
+		iter, err = t.ctx.State().JobsByNamespace(ws, sel.Namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		add(raw.(*structs.Job))
+	}
+	return jobs, nil
+}
+
+// InterJobConstraintIterator is a FeasibleIterator which filters nodes based
+// on the job_affinity and job_anti_affinity constraints of the job and task
+// group. A job affinity constraint only allows nodes sharing the value of the
+// constraint's node attribute with a node running an allocation of the
+// selected jobs, while a job anti-affinity constraint excludes those nodes.
+type InterJobConstraintIterator struct {
+	ctx        Context
+	source     FeasibleIterator
+	topologies *jobTopologies
+
+	jobConstraints []*structs.Constraint
+	constraints    []*structs.Constraint
+}
+
+// NewInterJobConstraintIterator creates an InterJobConstraintIterator from a
+// source.
+func NewInterJobConstraintIterator(ctx Context, source FeasibleIterator) *InterJobConstraintIterator {
+	return &InterJobConstraintIterator{
+		ctx:        ctx,
+		source:     source,
+		topologies: newJobTopologies(ctx),
+	}
+}
+
+func (iter *InterJobConstraintIterator) SetJob(job *structs.Job) {
+	iter.topologies.SetJob(job)
+	iter.jobConstraints = jobAffinityConstraints(job.Constraints)
+}
+
+func (iter *InterJobConstraintIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.constraints = append(jobAffinityConstraints(tg.Constraints), iter.jobConstraints...)
+}
+
+func (iter *InterJobConstraintIterator) Next() *structs.Node {
+	for {
+		// Get the next option from the source
+		option := iter.source.Next()
+
+		// Hot path if there is nothing to check
+		if option == nil || len(iter.constraints) == 0 {
+			return option
+		}
+
+		if !iter.satisfiesConstraints(option) {
+			continue
+		}
+		return option
+	}
+}
+
+// satisfiesConstraints returns whether the option satisfies all of the
+// job affinity constraints. If not it will be filtered.
+func (iter *InterJobConstraintIterator) satisfiesConstraints(option *structs.Node) bool {
+	for _, c := range iter.constraints {
+		topo := iter.topologies.get(c.LTarget, c.RTarget)
+		if topo.errorBuilding != nil {
+			iter.ctx.Metrics().FilterNode(option, topo.errorBuilding.Error())
+			return false
+		}
+
+		contains, found := topo.contains(option)
+		satisfied := found && contains
+		if c.Operand == structs.ConstraintJobAntiAffinity {
+			satisfied = !contains
+		}
+		if !satisfied {
+			iter.ctx.Metrics().FilterNode(option, c.String())
+			return false
+		}
+	}
+	return true
+}
+
+func (iter *InterJobConstraintIterator) Reset() {
+	iter.source.Reset()
+}
+
+// jobAffinityConstraints returns the job affinity and anti-affinity
+// constraints.
+func jobAffinityConstraints(constraints []*structs.Constraint) []*structs.Constraint {
+	var out []*structs.Constraint
+	for _, c := range constraints {
+		switch c.Operand {
+		case structs.ConstraintJobAffinity, structs.ConstraintJobAntiAffinity:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// InterJobAffinityIterator is a RankIterator that applies a weighted score to
+// nodes sharing the value of a node attribute with nodes running allocations
+// of the jobs selected by the job_affinity affinities of the job, task group
+// and tasks. Negative weights are used for anti-affinity.
+type InterJobAffinityIterator struct {
+	ctx        Context
+	source     RankIterator
+	topologies *jobTopologies
+
+	jobAffinities []*structs.Affinity
+	affinities    []*structs.Affinity
+}
+
+// NewInterJobAffinityIterator is used to create an InterJobAffinityIterator
+// from a source.
+func NewInterJobAffinityIterator(ctx Context, source RankIterator) *InterJobAffinityIterator {
+	return &InterJobAffinityIterator{
+		ctx:        ctx,
+		source:     source,
+		topologies: newJobTopologies(ctx),
+	}
+}
+
+func (iter *InterJobAffinityIterator) SetJob(job *structs.Job) {
+	iter.topologies.SetJob(job)
+	iter.jobAffinities = jobAffinities(job.Affinities)
+}
+
+func (iter *InterJobAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.affinities = append(jobAffinities(tg.Affinities), iter.jobAffinities...)
+	for _, task := range tg.Tasks {
+		iter.affinities = append(iter.affinities, jobAffinities(task.Affinities)...)
+	}
+}
+
+func (iter *InterJobAffinityIterator) hasAffinities() bool {
+	return len(iter.affinities) > 0
+}
+
+func (iter *InterJobAffinityIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil || !iter.hasAffinities() {
+		return option
+	}
+
+	sumWeight := 0.0
+	totalAffinityScore := 0.0
+	for _, affinity := range iter.affinities {
+		sumWeight += math.Abs(float64(affinity.Weight))
+
+		topo := iter.topologies.get(affinity.LTarget, affinity.RTarget)
+		if topo.errorBuilding != nil {
+			continue
+		}
+		if contains, _ := topo.contains(option.Node); contains {
+			totalAffinityScore += float64(affinity.Weight)
+		}
+	}
+
+	if totalAffinityScore != 0.0 {
+		normScore := totalAffinityScore / sumWeight
+		option.Scores = append(option.Scores, normScore)
+		iter.ctx.Metrics().ScoreNode(option.Node, "job-affinity", normScore)
+	}
+	return option
+}
+
+func (iter *InterJobAffinityIterator) Reset() {
+	iter.source.Reset()
+}
+
+// jobAffinities returns the job_affinity affinities.
+func jobAffinities(affinities []*structs.Affinity) []*structs.Affinity {
+	var out []*structs.Affinity
+	for _, a := range affinities {
+		if a.Operand == structs.ConstraintJobAffinity {
+			out = append(out, a)
+		}
+	}
+	return out
+}
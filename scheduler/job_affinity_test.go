@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testJobTopology upserts four nodes, the first two in rack "a", the third in
+// rack "b" and the last without a rack, and a "cache" job running on the first
+// node. The cache job has a stopped allocation on the third node.
+func testJobTopology(t *testing.T, state *state.StateStore) []*structs.Node {
+	nodes := []*structs.Node{mock.Node(), mock.Node(), mock.Node(), mock.Node()}
+	nodes[0].Meta["rack"] = "a"
+	nodes[1].Meta["rack"] = "a"
+	nodes[2].Meta["rack"] = "b"
+	for i, n := range nodes {
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), n))
+	}
+
+	cache := mock.Job()
+	cache.ID = "cache"
+	cache.Meta = map[string]string{"tier": "storage"}
+	require.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 200, cache))
+
+	running := mock.Alloc()
+	running.Job = cache
+	running.JobID = cache.ID
+	running.NodeID = nodes[0].ID
+
+	stopped := mock.Alloc()
+	stopped.Job = cache
+	stopped.JobID = cache.ID
+	stopped.NodeID = nodes[2].ID
+	stopped.DesiredStatus = structs.AllocDesiredStatusStop
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 201, []*structs.Allocation{running, stopped}))
+	return nodes
+}
+
+func TestInterJobConstraintIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := testJobTopology(t, state)
+
+	cases := []struct {
+		name     string
+		operand  string
+		selector string
+		expected []*structs.Node
+	}{
+		{
+			name:     "affinity by job ID",
+			operand:  structs.ConstraintJobAffinity,
+			selector: "job=cache",
+			expected: []*structs.Node{nodes[0], nodes[1]},
+		},
+		{
+			name:     "anti-affinity by job ID",
+			operand:  structs.ConstraintJobAntiAffinity,
+			selector: "job=cache",
+			expected: []*structs.Node{nodes[2], nodes[3]},
+		},
+		{
+			name:     "affinity by job meta",
+			operand:  structs.ConstraintJobAffinity,
+			selector: "meta.tier=storage,namespace=*",
+			expected: []*structs.Node{nodes[0], nodes[1]},
+		},
+		{
+			name:     "affinity in other namespace",
+			operand:  structs.ConstraintJobAffinity,
+			selector: "job=cache,namespace=other",
+			expected: nil,
+		},
+		{
+			name:     "anti-affinity without matching jobs",
+			operand:  structs.ConstraintJobAntiAffinity,
+			selector: "meta.tier=web",
+			expected: nodes,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.Job()
+			tg := job.TaskGroups[0]
+			tg.Constraints = append(tg.Constraints, &structs.Constraint{
+				Operand: tc.operand,
+				LTarget: "${meta.rack}",
+				RTarget: tc.selector,
+			})
+
+			static := NewStaticIterator(ctx, nodes)
+			iter := NewInterJobConstraintIterator(ctx, static)
+			iter.SetJob(job)
+			iter.SetTaskGroup(tg)
+
+			out := collectFeasible(iter)
+			require.ElementsMatch(t, tc.expected, out)
+		})
+	}
+}
+
+func TestInterJobConstraintIterator_ExcludesOwnJob(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := testJobTopology(t, state)
+
+	// The cache job's own allocations don't count towards its anti-affinity
+	job, err := state.JobByID(nil, structs.DefaultNamespace, "cache")
+	require.NoError(t, err)
+	job = job.Copy()
+	job.Constraints = append(job.Constraints, &structs.Constraint{
+		Operand: structs.ConstraintJobAntiAffinity,
+		LTarget: "${node.unique.id}",
+		RTarget: "meta.tier=storage",
+	})
+
+	static := NewStaticIterator(ctx, nodes)
+	iter := NewInterJobConstraintIterator(ctx, static)
+	iter.SetJob(job)
+	iter.SetTaskGroup(job.TaskGroups[0])
+
+	out := collectFeasible(iter)
+	require.ElementsMatch(t, nodes, out)
+}
+
+func TestInterJobAffinityIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	nodes := testJobTopology(t, state)
+
+	ranked := make([]*RankedNode, len(nodes))
+	for i, n := range nodes {
+		ranked[i] = &RankedNode{Node: n}
+	}
+
+	job := mock.Job()
+	job.Affinities = []*structs.Affinity{
+		{
+			Operand: structs.ConstraintJobAffinity,
+			LTarget: "${meta.rack}",
+			RTarget: "job=cache",
+			Weight:  50,
+		},
+	}
+	tg := job.TaskGroups[0]
+	tg.Affinities = []*structs.Affinity{
+		{
+			Operand: structs.ConstraintJobAffinity,
+			LTarget: "${node.unique.id}",
+			RTarget: "job=cache",
+			Weight:  -50,
+		},
+		{
+			Operand: "=",
+			LTarget: "${node.datacenter}",
+			RTarget: "dc1",
+			Weight:  100,
+		},
+	}
+
+	static := NewStaticRankIterator(ctx, ranked)
+	iter := NewInterJobAffinityIterator(ctx, static)
+	iter.SetJob(job)
+	iter.SetTaskGroup(tg)
+	require.True(t, iter.hasAffinities())
+
+	scoreNorm := NewScoreNormalizationIterator(ctx, iter)
+	out := collectRanked(scoreNorm)
+	require.Len(t, out, len(nodes))
+
+	// The node affinity isn't scored by this iterator, and the first node
+	// matches both job affinities
+	scores := make(map[string]float64)
+	for _, rn := range out {
+		scores[rn.Node.ID] = rn.FinalScore
+	}
+	require.Equal(t, 0.0, scores[nodes[0].ID])
+	require.Equal(t, 0.5, scores[nodes[1].ID])
+	require.Equal(t, 0.0, scores[nodes[2].ID])
+	require.Equal(t, 0.0, scores[nodes[3].ID])
+}
@@ -0,0 +1,108 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// capacityReservationAllocName is the name of the task of the allocation
+	// holding the reserved capacity of a node.
+	capacityReservationAllocName = "capacity-reservation"
+
+	// nodeMaintenanceHorizon is how long before the start of a maintenance
+	// window placements on its node are penalized.
+	nodeMaintenanceHorizon = 24 * time.Hour
+)
+
+// capacityReservationAlloc returns an allocation holding the capacity of the
+// node reserved for jobs other than the given one, or nil if there is none.
+// The capacity reserved for a job is reduced by the resources of its proposed
+// allocations on the node so it isn't counted twice once the job is placed.
+func capacityReservationAlloc(node *structs.Node, proposed []*structs.Allocation, jobID structs.NamespacedID, now time.Time) *structs.Allocation {
+	var cpu, memory int64
+	for _, r := range node.CapacityReservations {
+		if r.Expired(now) || (r.Namespace == jobID.Namespace && r.JobID == jobID.ID) {
+			continue
+		}
+
+		reservedCPU, reservedMemory := r.CPU, r.MemoryMB
+		for _, alloc := range proposed {
+			if alloc.Namespace != r.Namespace || alloc.JobID != r.JobID || alloc.ClientTerminalStatus() {
+				continue
+			}
+			used := alloc.ComparableResources().Flattened
+			reservedCPU -= used.Cpu.CpuShares
+			reservedMemory -= used.Memory.MemoryMB
+		}
+
+		if reservedCPU > 0 {
+			cpu += reservedCPU
+		}
+		if reservedMemory > 0 {
+			memory += reservedMemory
+		}
+	}
+
+	if cpu == 0 && memory == 0 {
+		return nil
+	}
+	return &structs.Allocation{
+		AllocatedResources: &structs.AllocatedResources{
+			Tasks: map[string]*structs.AllocatedTaskResources{
+				capacityReservationAllocName: {
+					Cpu:    structs.AllocatedCpuResources{CpuShares: cpu},
+					Memory: structs.AllocatedMemoryResources{MemoryMB: memory},
+				},
+			},
+		},
+	}
+}
+
+// NodeMaintenanceIterator is used to penalize placing service and batch jobs
+// on nodes with a maintenance window starting within the horizon or in
+// progress, as their allocations would be stopped by the drain at the start of
+// the window.
+type NodeMaintenanceIterator struct {
+	ctx     Context
+	source  RankIterator
+	enabled bool
+}
+
+// NewNodeMaintenanceIterator is used to create a NodeMaintenanceIterator that
+// penalizes nodes with maintenance windows.
+func NewNodeMaintenanceIterator(ctx Context, source RankIterator) *NodeMaintenanceIterator {
+	return &NodeMaintenanceIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *NodeMaintenanceIterator) SetJob(job *structs.Job) {
+	iter.enabled = job.Type == structs.JobTypeService || job.Type == structs.JobTypeBatch
+}
+
+func (iter *NodeMaintenanceIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil || !iter.enabled {
+		return option
+	}
+
+	now := time.Now()
+	horizon := now.Add(nodeMaintenanceHorizon)
+	for _, w := range option.Node.MaintenanceWindows {
+		if w.Started(horizon) && !w.Ended(now) {
+			option.Scores = append(option.Scores, -1)
+			iter.ctx.Metrics().ScoreNode(option.Node, "maintenance", -1)
+			return option
+		}
+	}
+
+	iter.ctx.Metrics().ScoreNode(option.Node, "maintenance", 0)
+	return option
+}
+
+func (iter *NodeMaintenanceIterator) Reset() {
+	iter.source.Reset()
+}
@@ -0,0 +1,241 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapacityReservationAlloc(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	node := mock.Node()
+	node.CapacityReservations = []*structs.NodeCapacityReservation{
+		{ID: "r1", Namespace: structs.DefaultNamespace, JobID: "upcoming", CPU: 1000, MemoryMB: 1024, Expires: now.Add(time.Hour)},
+		{ID: "r2", Namespace: structs.DefaultNamespace, JobID: "placing", CPU: 500, Expires: now.Add(time.Hour)},
+		{ID: "r3", Namespace: structs.DefaultNamespace, JobID: "expired", CPU: 500, Expires: now},
+	}
+
+	// Only the reservations of other jobs that haven't expired are held
+	placing := structs.NamespacedID{Namespace: structs.DefaultNamespace, ID: "placing"}
+	alloc := capacityReservationAlloc(node, nil, placing, now)
+	require.NotNil(t, alloc)
+	used := alloc.ComparableResources().Flattened
+	require.EqualValues(t, 1000, used.Cpu.CpuShares)
+	require.EqualValues(t, 1024, used.Memory.MemoryMB)
+
+	// Allocations of the reserving job use up its reservation
+	upcoming := mock.Alloc()
+	upcoming.JobID = "upcoming"
+	upcoming.AllocatedResources.Tasks["web"].Cpu.CpuShares = 400
+	upcoming.AllocatedResources.Tasks["web"].Memory.MemoryMB = 2048
+	alloc = capacityReservationAlloc(node, []*structs.Allocation{upcoming}, placing, now)
+	require.NotNil(t, alloc)
+	used = alloc.ComparableResources().Flattened
+	require.EqualValues(t, 600, used.Cpu.CpuShares)
+	require.EqualValues(t, 0, used.Memory.MemoryMB)
+
+	// Terminal allocations don't
+	upcoming.ClientStatus = structs.AllocClientStatusComplete
+	alloc = capacityReservationAlloc(node, []*structs.Allocation{upcoming}, placing, now)
+	require.EqualValues(t, 1000, alloc.ComparableResources().Flattened.Cpu.CpuShares)
+
+	// Nothing is held for the reserving job itself
+	node.CapacityReservations = node.CapacityReservations[1:]
+	require.Nil(t, capacityReservationAlloc(node, nil, placing, now))
+}
+
+func TestBinPackIterator_CapacityReservation(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	newNode := func() *RankedNode {
+		return &RankedNode{
+			Node: &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+			},
+		}
+	}
+	nodes := []*RankedNode{newNode(), newNode()}
+
+	// Reserve most of the first node for an upcoming job
+	nodes[0].Node.CapacityReservations = []*structs.NodeCapacityReservation{
+		{
+			ID:        uuid.Generate(),
+			Namespace: structs.DefaultNamespace,
+			JobID:     "upcoming",
+			MemoryMB:  1536,
+			Expires:   time.Now().Add(time.Hour),
+		},
+	}
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+
+	// Other jobs can't use the reserved capacity
+	job := mock.Job()
+	binp := NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nodes), false, 0, testSchedulerConfig)
+	binp.SetJob(job)
+	binp.SetTaskGroup(taskGroup)
+	out := collectRanked(binp)
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[1], out[0])
+
+	// The upcoming job can
+	job.ID = "upcoming"
+	binp = NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nodes), false, 0, testSchedulerConfig)
+	binp.SetJob(job)
+	binp.SetTaskGroup(taskGroup)
+	out = collectRanked(binp)
+	require.Len(t, out, 2)
+}
+
+func TestBinPackIterator_CapacityReservation_Preemption(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, tc := range []struct {
+		reservedMB int64
+		preempted  bool
+	}{
+		{reservedMB: 768, preempted: true},
+		{reservedMB: 1536, preempted: false},
+	} {
+		t.Run(fmt.Sprintf("reserved=%d", tc.reservedMB), func(t *testing.T) {
+			state, ctx := testContext(t)
+			node := &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares: 2048,
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+				},
+				CapacityReservations: []*structs.NodeCapacityReservation{
+					{
+						ID:        uuid.Generate(),
+						Namespace: structs.DefaultNamespace,
+						JobID:     "upcoming",
+						MemoryMB:  tc.reservedMB,
+						Expires:   time.Now().Add(time.Hour),
+					},
+				},
+			}
+			require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+			// Run a low priority allocation on the node
+			lowPrio := mock.Job()
+			lowPrio.Priority = 20
+			alloc := createAlloc(uuid.Generate(), lowPrio, &structs.Resources{CPU: 512, MemoryMB: 512})
+			alloc.NodeID = node.ID
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+			taskGroup := &structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{
+					{
+						Name: "web",
+						Resources: &structs.Resources{
+							CPU:      1024,
+							MemoryMB: 1024,
+						},
+					},
+				},
+			}
+
+			// The allocation is only preempted if that frees enough of the
+			// capacity that isn't reserved
+			job := mock.Job()
+			job.Priority = 100
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+			binp := NewBinPackIterator(ctx, static, true, job.Priority, testSchedulerConfig)
+			binp.SetJob(job)
+			binp.SetTaskGroup(taskGroup)
+			option := binp.Next()
+			if !tc.preempted {
+				require.Nil(t, option)
+				return
+			}
+			require.NotNil(t, option)
+			require.Len(t, option.PreemptedAllocs, 1)
+			require.Equal(t, alloc.ID, option.PreemptedAllocs[0].ID)
+		})
+	}
+}
+
+func TestNodeMaintenanceIterator(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	now := time.Now()
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+	nodes[0].Node.MaintenanceWindows = []*structs.NodeMaintenanceWindow{
+		{ID: "upcoming", Start: now.Add(time.Hour), End: now.Add(2 * time.Hour)},
+	}
+	nodes[1].Node.MaintenanceWindows = []*structs.NodeMaintenanceWindow{
+		{ID: "active", Start: now.Add(-time.Hour), End: now.Add(time.Hour)},
+	}
+	nodes[2].Node.MaintenanceWindows = []*structs.NodeMaintenanceWindow{
+		{ID: "ended", Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+	}
+	nodes[3].Node.MaintenanceWindows = []*structs.NodeMaintenanceWindow{
+		{ID: "distant", Start: now.Add(30 * 24 * time.Hour), End: now.Add(31 * 24 * time.Hour)},
+	}
+
+	for _, job := range []*structs.Job{mock.Job(), mock.BatchJob()} {
+		for _, node := range nodes {
+			node.Scores = nil
+		}
+		iter := NewNodeMaintenanceIterator(ctx, NewStaticRankIterator(ctx, nodes))
+		iter.SetJob(job)
+		out := collectRanked(iter)
+		require.Len(t, out, 5)
+		require.Equal(t, []float64{-1}, out[0].Scores)
+		require.Equal(t, []float64{-1}, out[1].Scores)
+		require.Empty(t, out[2].Scores)
+		require.Empty(t, out[3].Scores)
+		require.Empty(t, out[4].Scores)
+	}
+
+	// System jobs aren't penalized
+	for _, node := range nodes {
+		node.Scores = nil
+	}
+	iter := NewNodeMaintenanceIterator(ctx, NewStaticRankIterator(ctx, nodes))
+	iter.SetJob(mock.SystemJob())
+	out := collectRanked(iter)
+	require.Len(t, out, 5)
+	require.Empty(t, out[0].Scores)
+}
@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// selectCores selects count cores from the available cores of a node with the
+// given NUMA topology. Unless the affinity is none, the cores are placed on
+// the NUMA node with the fewest available cores that fit them, so larger NUMA
+// nodes are kept free for larger tasks, and whole physical cores are preferred
+// over hyperthreads of partially reserved ones. If no single NUMA node fits
+// the cores they are spread across the NUMA nodes with the most available
+// cores, unless the affinity requires a single NUMA node in which case no
+// cores are returned.
+//
+// The number of NUMA nodes spanned by the selected cores is returned alongside
+// them.
+func selectCores(numaNodes []*structs.NodeNumaNode, available cpuset.CPUSet, count int, affinity string) ([]uint16, int) {
+	if available.Size() < count {
+		return nil, 0
+	}
+	if len(numaNodes) == 0 || affinity == structs.NUMAAffinityNone {
+		return available.ToSlice()[0:count], 0
+	}
+
+	type candidate struct {
+		node *structs.NodeNumaNode
+		free cpuset.CPUSet
+	}
+	candidates := make([]candidate, 0, len(numaNodes))
+	for _, node := range numaNodes {
+		free := cpuset.New(node.Cores...).Intersection(available)
+		if free.Size() > 0 {
+			candidates = append(candidates, candidate{node, free})
+		}
+	}
+
+	// Best fit on a single NUMA node
+	var best *candidate
+	for i, c := range candidates {
+		if c.free.Size() >= count && (best == nil || c.free.Size() < best.free.Size()) {
+			best = &candidates[i]
+		}
+	}
+	if best != nil {
+		return orderBySiblings(best.node, best.free)[0:count], 1
+	}
+
+	if affinity == structs.NUMAAffinityRequire {
+		return nil, 0
+	}
+
+	// Spread across the NUMA nodes with the most available cores
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].free.Size() > candidates[j].free.Size()
+	})
+	cores := make([]uint16, 0, count)
+	spanned := 0
+	for _, c := range candidates {
+		if len(cores) == count {
+			break
+		}
+		for _, core := range orderBySiblings(c.node, c.free) {
+			if len(cores) == count {
+				break
+			}
+			cores = append(cores, core)
+		}
+		spanned++
+	}
+
+	// Cores missing from the topology are used last
+	if len(cores) < count {
+		rest := available.Difference(cpuset.New(cores...)).ToSlice()
+		cores = append(cores, rest[0:count-len(cores)]...)
+	}
+	return cores, spanned
+}
+
+// orderBySiblings orders the free cores of a NUMA node so that the cores of
+// fully free physical cores come first, keeping hyperthreads of the same
+// physical core together.
+func orderBySiblings(node *structs.NodeNumaNode, free cpuset.CPUSet) []uint16 {
+	if len(node.Siblings) == 0 {
+		return free.ToSlice()
+	}
+
+	var whole, partial []uint16
+	seen := cpuset.New()
+	for _, siblings := range node.Siblings {
+		group := cpuset.New(siblings...)
+		seen = seen.Union(group)
+
+		freeGroup := group.Intersection(free)
+		if freeGroup.Equals(group) {
+			whole = append(whole, freeGroup.ToSlice()...)
+		} else {
+			partial = append(partial, freeGroup.ToSlice()...)
+		}
+	}
+
+	cores := append(whole, partial...)
+	return append(cores, free.Difference(seen).ToSlice()...)
+}
@@ -0,0 +1,117 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/lib/cpuset"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testNumaNodes returns the topology of two NUMA nodes with two physical
+// cores of two hyperthreads each.
+func testNumaNodes() []*structs.NodeNumaNode {
+	return []*structs.NodeNumaNode{
+		{
+			ID:       0,
+			Cores:    []uint16{0, 1, 4, 5},
+			Siblings: [][]uint16{{0, 4}, {1, 5}},
+			MemoryMB: 4096,
+		},
+		{
+			ID:       1,
+			Cores:    []uint16{2, 3, 6, 7},
+			Siblings: [][]uint16{{2, 6}, {3, 7}},
+			MemoryMB: 4096,
+		},
+	}
+}
+
+func TestSelectCores(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name      string
+		numaNodes []*structs.NodeNumaNode
+		available cpuset.CPUSet
+		count     int
+		affinity  string
+		cores     []uint16
+		spanned   int
+	}{
+		{
+			name:      "no topology",
+			available: cpuset.New(0, 1, 2, 3),
+			count:     2,
+			affinity:  structs.NUMAAffinityRequire,
+			cores:     []uint16{0, 1},
+		},
+		{
+			name:      "affinity none",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(0, 1, 2, 3, 4, 5, 6, 7),
+			count:     3,
+			affinity:  structs.NUMAAffinityNone,
+			cores:     []uint16{0, 1, 2},
+		},
+		{
+			name:      "whole physical cores",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(0, 1, 2, 3, 4, 5, 6, 7),
+			count:     2,
+			affinity:  structs.NUMAAffinityPrefer,
+			cores:     []uint16{0, 4},
+			spanned:   1,
+		},
+		{
+			name:      "best fit",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(0, 1, 2, 3, 5, 6, 7),
+			count:     3,
+			affinity:  structs.NUMAAffinityPrefer,
+			cores:     []uint16{1, 5, 0},
+			spanned:   1,
+		},
+		{
+			name:      "partial physical cores last",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(2, 3, 7),
+			count:     2,
+			affinity:  structs.NUMAAffinityPrefer,
+			cores:     []uint16{3, 7},
+			spanned:   1,
+		},
+		{
+			name:      "prefer spreads",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(0, 1, 2, 3, 6),
+			count:     4,
+			affinity:  structs.NUMAAffinityPrefer,
+			cores:     []uint16{2, 6, 3, 0},
+			spanned:   2,
+		},
+		{
+			name:      "require fails",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(0, 1, 2, 3, 6),
+			count:     4,
+			affinity:  structs.NUMAAffinityRequire,
+		},
+		{
+			name:      "not enough cores",
+			numaNodes: testNumaNodes(),
+			available: cpuset.New(0, 1),
+			count:     4,
+			affinity:  structs.NUMAAffinityPrefer,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cores, spanned := selectCores(tc.numaNodes, tc.available, tc.count, tc.affinity)
+			require.Equal(t, tc.cores, cores)
+			require.Equal(t, tc.spanned, spanned)
+		})
+	}
+}
@@ -142,6 +142,14 @@ func (p *Preemptor) SetNode(node *structs.Node) {
 	p.nodeRemainingResources = nodeRemainingResources
 }
 
+// SetCapacityReservation sets the capacity of the node reserved for other
+// jobs, held by the given allocation. It can't be freed by preemption.
+func (p *Preemptor) SetCapacityReservation(reserved *structs.Allocation) {
+	if reserved != nil {
+		p.nodeRemainingResources.Subtract(reserved.ComparableResources())
+	}
+}
+
 // SetCandidates initializes the candidate set from which preemptions are chosen
 func (p *Preemptor) SetCandidates(allocs []*structs.Allocation) {
 	// Reset candidate set
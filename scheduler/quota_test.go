@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceSched_JobRegister_QuotaLimitReached(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		require.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a quota which only allows two of the job's allocations
+	qs := mock.QuotaSpec()
+	qs.Limits[0].RegionLimit.CPU = 1000
+	qs.Limits[0].SetHash()
+	qs.SetHash()
+	require.NoError(t, h.State.UpsertQuotaSpecs(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.QuotaSpec{qs}))
+
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	require.NoError(t, h.State.UpsertNamespaces(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	job.TaskGroups[0].Count = 3
+	require.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), job))
+
+	eval := &structs.Evaluation{
+		Namespace:   ns.Name,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Only the allocations within the quota are placed
+	require.Len(t, h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocs := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocs...)
+	}
+	require.Len(t, planned, 2)
+
+	// A blocked eval waiting on the quota is created
+	require.Len(t, h.CreateEvals, 1)
+	blocked := h.CreateEvals[0]
+	require.Equal(t, structs.EvalStatusBlocked, blocked.Status)
+	require.Equal(t, qs.Name, blocked.QuotaLimitReached)
+
+	// The exhausted quota dimension is reported
+	require.Len(t, h.Evals, 1)
+	metrics, ok := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.True(t, ok)
+	require.Equal(t, []string{"cpu exhausted (1500 needed > 1000 limit)"}, metrics.QuotaExhausted)
+}
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/hashicorp/nomad/lib/cpuset"
 
@@ -243,6 +244,9 @@ OUTER:
 		totalDeviceAffinityWeight := 0.0
 		sumMatchingAffinities := 0.0
 
+		// Track whether any task's reserved cores span NUMA nodes
+		numaSpanned := false
+
 		// Assign the resources for each task
 		total := &structs.AllocatedResources{
 			Tasks: make(map[string]*structs.AllocatedTaskResources,
@@ -359,6 +363,18 @@ OUTER:
 			if iter.memoryOversubscription {
 				taskResources.Memory.MemoryMaxMB = int64(task.Resources.MemoryMaxMB)
 			}
+			if io := task.Resources.DiskIO; io != nil {
+				taskResources.DiskIO = structs.AllocatedDiskIOResources{
+					ReadIOPS:  int64(io.ReadIOPS),
+					WriteIOPS: int64(io.WriteIOPS),
+					ReadMBps:  int64(io.ReadMBps),
+					WriteMBps: int64(io.WriteMBps),
+				}
+				if option.Node.NodeResources != nil {
+					taskResources.DiskIO.Device = option.Node.NodeResources.Disk.Device
+				}
+			}
+			taskResources.Bandwidth.EgressMbits = int64(task.Resources.EgressMbits)
 
 			// Check if we need a network resource
 			if len(task.Resources.Networks) > 0 {
@@ -482,8 +498,21 @@ OUTER:
 					continue OUTER
 				}
 
+				// Select the cores with regard to the NUMA topology of the node,
+				// marking the node as exhausted if a single NUMA node is required
+				// but none fits the cores
+				cores, spanned := selectCores(option.Node.NodeResources.Cpu.NumaNodes,
+					availableCPUSet, task.Resources.Cores, task.Resources.NUMAAffinity())
+				if cores == nil {
+					iter.ctx.Metrics().ExhaustedNode(option.Node, "numa")
+					continue OUTER
+				}
+				if spanned > 1 {
+					numaSpanned = true
+				}
+
 				// Set the task's reserved cores
-				taskResources.Cpu.ReservedCores = availableCPUSet.ToSlice()[0:task.Resources.Cores]
+				taskResources.Cpu.ReservedCores = cores
 				// Total CPU usage on the node is still tracked by CPUShares. Even though the task will have the entire
 				// core reserved, we still track overall usage by cpu shares.
 				taskResources.Cpu.CpuShares = option.Node.NodeResources.Cpu.SharesPerCore() * int64(task.Resources.Cores)
@@ -503,8 +532,15 @@ OUTER:
 		// Add the resources we are trying to fit
 		proposed = append(proposed, &structs.Allocation{AllocatedResources: total})
 
+		// Hold the capacity reserved on the node for other jobs
+		fitAllocs := proposed
+		reserved := capacityReservationAlloc(option.Node, current, iter.jobId, time.Now())
+		if reserved != nil {
+			fitAllocs = append(proposed[:len(proposed):len(proposed)], reserved)
+		}
+
 		// Check if these allocations fit, if they do not, simply skip this node
-		fit, dim, util, _ := structs.AllocsFit(option.Node, proposed, netIdx, false)
+		fit, dim, util, _ := structs.AllocsFit(option.Node, fitAllocs, netIdx, false)
 		netIdx.Release()
 		if !fit {
 			// Skip the node if evictions are not enabled
@@ -516,8 +552,10 @@ OUTER:
 			// If eviction is enabled and the node doesn't fit the alloc, check if
 			// any allocs can be preempted
 
-			// Initialize preemptor with candidate set
+			// Initialize preemptor with candidate set, the reserved
+			// capacity can't be preempted
 			preemptor.SetCandidates(current)
+			preemptor.SetCapacityReservation(reserved)
 
 			preemptedAllocs := preemptor.PreemptForTaskGroup(total)
 			allocsToPreempt = append(allocsToPreempt, preemptedAllocs...)
@@ -546,6 +584,12 @@ OUTER:
 			iter.ctx.Metrics().ScoreNode(option.Node, "devices", sumMatchingAffinities)
 		}
 
+		// Penalize nodes where reserved cores span NUMA nodes
+		if numaSpanned {
+			option.Scores = append(option.Scores, -1)
+			iter.ctx.Metrics().ScoreNode(option.Node, "numa", -1)
+		}
+
 		return option
 	}
 }
@@ -689,7 +733,7 @@ func NewNodeAffinityIterator(ctx Context, source RankIterator) *NodeAffinityIter
 }
 
 func (iter *NodeAffinityIterator) SetJob(job *structs.Job) {
-	iter.jobAffinities = job.Affinities
+	iter.jobAffinities = nodeAffinities(job.Affinities)
 }
 
 func (iter *NodeAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
@@ -700,13 +744,25 @@ func (iter *NodeAffinityIterator) SetTaskGroup(tg *structs.TaskGroup) {
 
 	// Merge task group affinities and task affinities
 	if tg.Affinities != nil {
-		iter.affinities = append(iter.affinities, tg.Affinities...)
+		iter.affinities = append(iter.affinities, nodeAffinities(tg.Affinities)...)
 	}
 	for _, task := range tg.Tasks {
 		if task.Affinities != nil {
-			iter.affinities = append(iter.affinities, task.Affinities...)
+			iter.affinities = append(iter.affinities, nodeAffinities(task.Affinities)...)
+		}
+	}
+}
+
+// nodeAffinities returns the affinities matched against node attributes,
+// excluding job affinities which are scored by the InterJobAffinityIterator.
+func nodeAffinities(affinities []*structs.Affinity) []*structs.Affinity {
+	var out []*structs.Affinity
+	for _, a := range affinities {
+		if a.Operand != structs.ConstraintJobAffinity {
+			out = append(out, a)
 		}
 	}
+	return out
 }
 
 func (iter *NodeAffinityIterator) Reset() {
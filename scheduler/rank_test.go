@@ -1279,6 +1279,97 @@ func TestBinPackIterator_ReservedCores(t *testing.T) {
 	require.Equal([]uint16{1}, out[0].TaskResources["web"].Cpu.ReservedCores)
 }
 
+func TestBinPackIterator_ReservedCores_NUMA(t *testing.T) {
+	cases := []struct {
+		affinity string
+		cores    []uint16
+	}{
+		{structs.NUMAAffinityPrefer, []uint16{1, 3}},
+		{structs.NUMAAffinityRequire, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.affinity, func(t *testing.T) {
+			state, ctx := testContext(t)
+
+			// Each NUMA node has a single core left
+			node := &structs.Node{
+				ID: uuid.Generate(),
+				NodeResources: &structs.NodeResources{
+					Cpu: structs.NodeCpuResources{
+						CpuShares:          4096,
+						TotalCpuCores:      4,
+						ReservableCpuCores: []uint16{0, 1, 2, 3},
+						NumaNodes: []*structs.NodeNumaNode{
+							{ID: 0, Cores: []uint16{0, 1}, MemoryMB: 2048},
+							{ID: 1, Cores: []uint16{2, 3}, MemoryMB: 2048},
+						},
+					},
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 4096,
+					},
+				},
+			}
+			static := NewStaticRankIterator(ctx, []*RankedNode{{Node: node}})
+
+			j := mock.Job()
+			alloc := &structs.Allocation{
+				Namespace: structs.DefaultNamespace,
+				ID:        uuid.Generate(),
+				EvalID:    uuid.Generate(),
+				NodeID:    node.ID,
+				JobID:     j.ID,
+				Job:       j,
+				AllocatedResources: &structs.AllocatedResources{
+					Tasks: map[string]*structs.AllocatedTaskResources{
+						"web": {
+							Cpu: structs.AllocatedCpuResources{
+								CpuShares:     2048,
+								ReservedCores: []uint16{0, 2},
+							},
+							Memory: structs.AllocatedMemoryResources{
+								MemoryMB: 1024,
+							},
+						},
+					},
+				},
+				DesiredStatus: structs.AllocDesiredStatusRun,
+				ClientStatus:  structs.AllocClientStatusPending,
+				TaskGroup:     "web",
+			}
+			require.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+			require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+			taskGroup := &structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{
+					{
+						Name: "web",
+						Resources: &structs.Resources{
+							Cores:    2,
+							MemoryMB: 1024,
+							NUMA:     &structs.NUMA{Affinity: tc.affinity},
+						},
+					},
+				},
+			}
+			binp := NewBinPackIterator(ctx, static, false, 0, testSchedulerConfig)
+			binp.SetTaskGroup(taskGroup)
+
+			out := collectRanked(NewScoreNormalizationIterator(ctx, binp))
+			if tc.cores == nil {
+				require.Empty(t, out)
+				require.Equal(t, 1, ctx.Metrics().DimensionExhausted["numa"])
+				return
+			}
+
+			require.Len(t, out, 1)
+			require.Equal(t, tc.cores, out[0].TaskResources["web"].Cpu.ReservedCores)
+			require.Contains(t, out[0].Scores, -1.0)
+		})
+	}
+}
+
 func TestBinPackIterator_ExistingAlloc(t *testing.T) {
 	state, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -0,0 +1,467 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// RebalanceReasonSpread is used when an allocation is migrated because
+	// its task group has more allocations on an attribute value than its
+	// spread stanzas target.
+	RebalanceReasonSpread = "spread"
+
+	// RebalanceReasonUnderutilized is used when an allocation is migrated off
+	// an underutilized node so the node can be emptied.
+	RebalanceReasonUnderutilized = "underutilized"
+)
+
+// RebalanceOptions configures the migrations a Rebalancer may make.
+type RebalanceOptions struct {
+	// MaxMigrations is the maximum number of allocations returned.
+	MaxMigrations int
+
+	// UtilizationThreshold is the fraction of a node's CPU or memory below
+	// which the node is considered underutilized. Zero disables
+	// consolidating underutilized nodes.
+	UtilizationThreshold float64
+}
+
+// RebalanceMigration is an allocation the Rebalancer would migrate.
+type RebalanceMigration struct {
+	Alloc       *structs.Allocation
+	Reason      string
+	Description string
+}
+
+// Rebalancer finds running service allocations that should be migrated
+// because they violate their task group's spread or run on an underutilized
+// node. It only returns allocations of stable jobs and respects the task
+// group's migrate stanza, so the allocations can be migrated by setting their
+// desired transition, just like the node drainer does.
+type Rebalancer struct {
+	logger log.Logger
+	state  State
+	opts   RebalanceOptions
+
+	// nodes are the ready nodes and their utilization
+	nodes       map[string]*structs.Node
+	utilization map[string]float64
+
+	// groups are the task groups that may be rebalanced keyed by the
+	// allocation IDs of the group
+	groups map[string]*rebalanceGroup
+
+	migrations []*RebalanceMigration
+	migrating  map[string]struct{}
+}
+
+// rebalanceGroup tracks the allocations of a task group and how many of them
+// may still be migrated.
+type rebalanceGroup struct {
+	job    *structs.Job
+	tg     *structs.TaskGroup
+	allocs []*structs.Allocation
+	budget int
+}
+
+// NewRebalancer returns a Rebalancer for the given state.
+func NewRebalancer(logger log.Logger, state State, opts RebalanceOptions) *Rebalancer {
+	return &Rebalancer{
+		logger:      logger.Named("rebalancer"),
+		state:       state,
+		opts:        opts,
+		nodes:       make(map[string]*structs.Node),
+		utilization: make(map[string]float64),
+		groups:      make(map[string]*rebalanceGroup),
+		migrating:   make(map[string]struct{}),
+	}
+}
+
+// Compute returns the allocations to migrate, allocations violating their
+// spread first.
+func (r *Rebalancer) Compute() ([]*RebalanceMigration, error) {
+	if r.opts.MaxMigrations <= 0 {
+		return nil, nil
+	}
+	if err := r.loadNodes(); err != nil {
+		return nil, err
+	}
+	if err := r.loadGroups(); err != nil {
+		return nil, err
+	}
+
+	r.computeSpread()
+	if err := r.computeUnderutilized(); err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("computed migrations", "nodes", len(r.nodes), "migrations", len(r.migrations))
+	return r.migrations, nil
+}
+
+// loadNodes stores the ready nodes and their utilization.
+func (r *Rebalancer) loadNodes() error {
+	iter, err := r.state.Nodes(nil)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !node.Ready() {
+			continue
+		}
+
+		allocs, err := r.state.AllocsByNodeTerminal(nil, node.ID, false)
+		if err != nil {
+			return err
+		}
+		r.nodes[node.ID] = node
+		r.utilization[node.ID] = nodeUtilization(node, allocs)
+	}
+	return nil
+}
+
+// nodeUtilization returns the larger of the fraction of the node's CPU and
+// memory used by the allocations.
+func nodeUtilization(node *structs.Node, allocs []*structs.Allocation) float64 {
+	capacity := node.ComparableResources()
+	capacity.Subtract(node.ComparableReservedResources())
+
+	used := &structs.ComparableResources{}
+	for _, alloc := range allocs {
+		if alloc.TerminalStatus() {
+			continue
+		}
+		used.Add(alloc.ComparableResources())
+	}
+
+	var cpu, mem float64
+	if shares := capacity.Flattened.Cpu.CpuShares; shares > 0 {
+		cpu = float64(used.Flattened.Cpu.CpuShares) / float64(shares)
+	}
+	if mb := capacity.Flattened.Memory.MemoryMB; mb > 0 {
+		mem = float64(used.Flattened.Memory.MemoryMB) / float64(mb)
+	}
+	return math.Max(cpu, mem)
+}
+
+// loadGroups stores the task groups that may be rebalanced. Only service jobs
+// without an active deployment are rebalanced, and only task groups whose
+// allocations are all running.
+func (r *Rebalancer) loadGroups() error {
+	iter, err := r.state.Jobs(nil)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.Type != structs.JobTypeService || job.Stopped() ||
+			job.IsPeriodic() || job.IsParameterized() {
+			continue
+		}
+
+		deployment, err := r.state.LatestDeploymentByJobID(nil, job.Namespace, job.ID)
+		if err != nil {
+			return err
+		}
+		if deployment != nil && deployment.Active() {
+			continue
+		}
+
+		allocs, err := r.state.AllocsByJob(nil, job.Namespace, job.ID, false)
+		if err != nil {
+			return err
+		}
+
+		byGroup := make(map[string][]*structs.Allocation)
+		for _, alloc := range allocs {
+			if alloc.TerminalStatus() || alloc.Job == nil || alloc.Job.Version != job.Version {
+				continue
+			}
+			byGroup[alloc.TaskGroup] = append(byGroup[alloc.TaskGroup], alloc)
+		}
+
+		for _, tg := range job.TaskGroups {
+			if g := newRebalanceGroup(job, tg, byGroup[tg.Name]); g != nil {
+				for _, alloc := range g.allocs {
+					r.groups[alloc.ID] = g
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// newRebalanceGroup returns the rebalanceGroup of the task group or nil if
+// none of its allocations may be migrated. As with draining, the number of
+// allocations migrated at once is limited by the migrate stanza's
+// max_parallel and only healthy allocations count towards the task group's
+// count.
+func newRebalanceGroup(job *structs.Job, tg *structs.TaskGroup, allocs []*structs.Allocation) *rebalanceGroup {
+	if tg.Migrate == nil || len(allocs) < tg.Count {
+		return nil
+	}
+
+	healthy := 0
+	var running []*structs.Allocation
+	for _, alloc := range allocs {
+		if alloc.DesiredTransition.ShouldMigrate() {
+			continue
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusRunning || !alloc.DeploymentStatus.IsHealthy() {
+			continue
+		}
+		healthy++
+		running = append(running, alloc)
+	}
+
+	budget := healthy - (tg.Count - tg.Migrate.MaxParallel)
+	if budget <= 0 {
+		return nil
+	}
+
+	// Prefer migrating the newest allocations
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].CreateIndex > running[j].CreateIndex
+	})
+
+	return &rebalanceGroup{
+		job:    job,
+		tg:     tg,
+		allocs: running,
+		budget: budget,
+	}
+}
+
+// migrate adds the allocation to the migrations, returning false if no more
+// allocations may be migrated.
+func (r *Rebalancer) migrate(alloc *structs.Allocation, reason, desc string) bool {
+	if len(r.migrations) >= r.opts.MaxMigrations {
+		return false
+	}
+
+	g := r.groups[alloc.ID]
+	if _, ok := r.migrating[alloc.ID]; ok || g == nil || g.budget <= 0 {
+		return true
+	}
+
+	g.budget--
+	r.migrating[alloc.ID] = struct{}{}
+	r.migrations = append(r.migrations, &RebalanceMigration{
+		Alloc:       alloc,
+		Reason:      reason,
+		Description: desc,
+	})
+	return true
+}
+
+// sortedGroups returns the distinct task groups in a stable order.
+func (r *Rebalancer) sortedGroups() []*rebalanceGroup {
+	seen := make(map[*rebalanceGroup]struct{})
+	var groups []*rebalanceGroup
+	for _, g := range r.groups {
+		if _, ok := seen[g]; ok {
+			continue
+		}
+		seen[g] = struct{}{}
+		groups = append(groups, g)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.job.Namespace != b.job.Namespace {
+			return a.job.Namespace < b.job.Namespace
+		}
+		if a.job.ID != b.job.ID {
+			return a.job.ID < b.job.ID
+		}
+		return a.tg.Name < b.tg.Name
+	})
+	return groups
+}
+
+// computeSpread migrates allocations on attribute values exceeding the
+// task group's spread targets, as long as another value is below its target.
+func (r *Rebalancer) computeSpread() {
+	for _, g := range r.sortedGroups() {
+		spreads := make([]*structs.Spread, 0, len(g.tg.Spreads)+len(g.job.Spreads))
+		spreads = append(spreads, g.tg.Spreads...)
+		spreads = append(spreads, g.job.Spreads...)
+
+		for _, spread := range spreads {
+			excess := r.spreadExcess(g, spread)
+			for _, alloc := range excess {
+				desc := fmt.Sprintf("allocation exceeds the spread target of %q", spread.Attribute)
+				if !r.migrate(alloc, RebalanceReasonSpread, desc) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// spreadExcess returns the allocations of the group to migrate to satisfy
+// the spread.
+func (r *Rebalancer) spreadExcess(g *rebalanceGroup, spread *structs.Spread) []*structs.Allocation {
+	// Count the allocations by the attribute value of their node. Allocations
+	// on nodes that aren't ready are migrated by other means.
+	used := make(map[string][]*structs.Allocation)
+	for _, alloc := range g.allocs {
+		node, ok := r.nodes[alloc.NodeID]
+		if !ok {
+			continue
+		}
+		if value, ok := getProperty(node, spread.Attribute); ok {
+			used[value] = append(used[value], alloc)
+		}
+	}
+
+	// Find the attribute values the allocations could be moved to
+	values := make(map[string]struct{})
+	for _, node := range r.nodes {
+		if !helper.SliceStringContains(g.job.Datacenters, node.Datacenter) {
+			continue
+		}
+		if value, ok := getProperty(node, spread.Attribute); ok {
+			values[value] = struct{}{}
+		}
+	}
+	if len(values) < 2 {
+		return nil
+	}
+
+	// Compute the maximum number of allocations per value, grouping values
+	// without a target under the implicit target
+	count := float64(g.tg.Count)
+	limits := make(map[string]float64)
+	if len(spread.SpreadTarget) == 0 {
+		for value := range values {
+			limits[value] = math.Ceil(count / float64(len(values)))
+		}
+	} else {
+		remaining := count
+		for _, st := range spread.SpreadTarget {
+			desired := float64(st.Percent) / 100 * count
+			limits[st.Value] = math.Ceil(desired)
+			remaining -= desired
+		}
+		limits[implicitTarget] = math.Ceil(math.Max(remaining, 0))
+	}
+
+	key := func(value string) string {
+		if _, ok := limits[value]; ok {
+			return value
+		}
+		return implicitTarget
+	}
+
+	counts := make(map[string]int)
+	for value, allocs := range used {
+		counts[key(value)] += len(allocs)
+	}
+
+	// Only move as many allocations as there is room for on values below
+	// their limit that nodes are available for
+	deficit := 0
+	available := make(map[string]struct{})
+	for value := range values {
+		available[key(value)] = struct{}{}
+	}
+	for k := range available {
+		if d := int(limits[k]) - counts[k]; d > 0 {
+			deficit += d
+		}
+	}
+
+	var out []*structs.Allocation
+	sortedValues := make([]string, 0, len(used))
+	for value := range used {
+		sortedValues = append(sortedValues, value)
+	}
+	sort.Strings(sortedValues)
+
+	over := make(map[string]int)
+	for k, n := range counts {
+		over[k] = n - int(limits[k])
+	}
+	for _, value := range sortedValues {
+		k := key(value)
+		for _, alloc := range used[value] {
+			if deficit <= 0 {
+				return out
+			}
+			if over[k] <= 0 {
+				break
+			}
+			over[k]--
+			deficit--
+			out = append(out, alloc)
+		}
+	}
+	return out
+}
+
+// computeUnderutilized migrates the allocations on the least utilized nodes
+// below the utilization threshold, as long as another node in the same
+// datacenter is more utilized. Allocations are only consolidated when the
+// scheduler binpacks, otherwise they would be spread again.
+func (r *Rebalancer) computeUnderutilized() error {
+	if r.opts.UtilizationThreshold <= 0 {
+		return nil
+	}
+
+	_, schedConfig, err := r.state.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if schedConfig.EffectiveSchedulerAlgorithm() != structs.SchedulerAlgorithmBinpack {
+		return nil
+	}
+
+	var candidates []*structs.Node
+	for id, node := range r.nodes {
+		util := r.utilization[id]
+		if util == 0 || util >= r.opts.UtilizationThreshold {
+			continue
+		}
+		for otherID, other := range r.nodes {
+			if other.Datacenter == node.Datacenter && r.utilization[otherID] > util {
+				candidates = append(candidates, node)
+				break
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if r.utilization[a.ID] != r.utilization[b.ID] {
+			return r.utilization[a.ID] < r.utilization[b.ID]
+		}
+		return a.ID < b.ID
+	})
+
+	for _, node := range candidates {
+		allocs, err := r.state.AllocsByNodeTerminal(nil, node.ID, false)
+		if err != nil {
+			return err
+		}
+		sort.Slice(allocs, func(i, j int) bool { return allocs[i].ID < allocs[j].ID })
+
+		desc := fmt.Sprintf("node utilization %.0f%% is below the threshold of %.0f%%",
+			r.utilization[node.ID]*100, r.opts.UtilizationThreshold*100)
+		for _, alloc := range allocs {
+			if !r.migrate(alloc, RebalanceReasonUnderutilized, desc) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// testRebalanceAllocs upserts the job and a healthy running allocation of it
+// on each of the given nodes.
+func testRebalanceAllocs(t *testing.T, store *state.StateStore, job *structs.Job, nodes ...*structs.Node) []*structs.Allocation {
+	require.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 200, job))
+
+	allocs := make([]*structs.Allocation, len(nodes))
+	for i, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+		alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}
+		allocs[i] = alloc
+	}
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 201, allocs))
+	return allocs
+}
+
+// testRebalanceSpread returns a state store with a job spread across racks
+// whose four allocations all run on the first of two racks.
+func testRebalanceSpread(t *testing.T, maxParallel int) (*state.StateStore, []*structs.Allocation) {
+	store := state.TestStateStore(t)
+
+	nodes := []*structs.Node{mock.Node(), mock.Node()}
+	nodes[0].Meta["rack"] = "a"
+	nodes[1].Meta["rack"] = "b"
+	for i, node := range nodes {
+		require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+	}
+
+	job := mock.Job()
+	job.Spreads = []*structs.Spread{{Attribute: "${meta.rack}", Weight: 100}}
+	job.TaskGroups[0].Count = 4
+	job.TaskGroups[0].Migrate.MaxParallel = maxParallel
+	allocs := testRebalanceAllocs(t, store, job, nodes[0], nodes[0], nodes[0], nodes[0])
+	return store, allocs
+}
+
+func TestRebalancer_Spread(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name        string
+		maxParallel int
+		migrations  int
+	}{
+		{"limited by max_parallel", 1, 1},
+		{"limited by spread", 4, 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, allocs := testRebalanceSpread(t, tc.maxParallel)
+
+			r := NewRebalancer(hclog.NewNullLogger(), store, RebalanceOptions{MaxMigrations: 10})
+			out, err := r.Compute()
+			require.NoError(t, err)
+			require.Len(t, out, tc.migrations)
+
+			ids := make([]string, len(allocs))
+			for i, alloc := range allocs {
+				ids[i] = alloc.ID
+			}
+			for _, m := range out {
+				require.Equal(t, RebalanceReasonSpread, m.Reason)
+				require.Contains(t, ids, m.Alloc.ID)
+			}
+		})
+	}
+}
+
+func TestRebalancer_MaxMigrations(t *testing.T) {
+	ci.Parallel(t)
+
+	store, _ := testRebalanceSpread(t, 4)
+	r := NewRebalancer(hclog.NewNullLogger(), store, RebalanceOptions{MaxMigrations: 1})
+	out, err := r.Compute()
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+}
+
+func TestRebalancer_Unhealthy(t *testing.T) {
+	ci.Parallel(t)
+
+	// An unhealthy allocation uses up max_parallel
+	store, allocs := testRebalanceSpread(t, 1)
+	unhealthy := allocs[0].Copy()
+	unhealthy.DeploymentStatus.Healthy = helper.BoolToPtr(false)
+	require.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 300, []*structs.Allocation{unhealthy}))
+
+	r := NewRebalancer(hclog.NewNullLogger(), store, RebalanceOptions{MaxMigrations: 10})
+	out, err := r.Compute()
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestRebalancer_ActiveDeployment(t *testing.T) {
+	ci.Parallel(t)
+
+	store, allocs := testRebalanceSpread(t, 4)
+	d := mock.Deployment()
+	d.JobID = allocs[0].JobID
+	require.NoError(t, store.UpsertDeployment(300, d))
+
+	r := NewRebalancer(hclog.NewNullLogger(), store, RebalanceOptions{MaxMigrations: 10})
+	out, err := r.Compute()
+	require.NoError(t, err)
+	require.Empty(t, out)
+}
+
+func TestRebalancer_Underutilized(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name      string
+		algorithm structs.SchedulerAlgorithm
+		threshold float64
+		migrated  bool
+	}{
+		{"binpack", structs.SchedulerAlgorithmBinpack, 0.2, true},
+		{"below utilization", structs.SchedulerAlgorithmBinpack, 0.1, false},
+		{"disabled", structs.SchedulerAlgorithmBinpack, 0, false},
+		{"spread", structs.SchedulerAlgorithmSpread, 0.2, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := state.TestStateStore(t)
+			require.NoError(t, store.SchedulerSetConfig(10, &structs.SchedulerConfiguration{
+				SchedulerAlgorithm: tc.algorithm,
+			}))
+
+			nodes := []*structs.Node{mock.Node(), mock.Node()}
+			for i, node := range nodes {
+				require.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+			}
+
+			// The first node runs a single allocation while the second is
+			// half utilized
+			small := mock.Job()
+			small.TaskGroups[0].Count = 1
+			allocs := testRebalanceAllocs(t, store, small, nodes[0])
+
+			large := mock.Job()
+			large.TaskGroups[0].Count = 4
+			testRebalanceAllocs(t, store, large, nodes[1], nodes[1], nodes[1], nodes[1])
+
+			r := NewRebalancer(hclog.NewNullLogger(), store, RebalanceOptions{
+				MaxMigrations:        10,
+				UtilizationThreshold: tc.threshold,
+			})
+			out, err := r.Compute()
+			require.NoError(t, err)
+
+			if !tc.migrated {
+				require.Empty(t, out)
+				return
+			}
+			require.Len(t, out, 1)
+			require.Equal(t, allocs[0].ID, out[0].Alloc.ID)
+			require.Equal(t, RebalanceReasonUnderutilized, out[0].Reason)
+		})
+	}
+}
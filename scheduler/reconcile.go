@@ -67,6 +67,11 @@ type allocReconciler struct {
 	// deploymentFailed marks whether the deployment is failed
 	deploymentFailed bool
 
+	// dependenciesPending marks whether the placement of the job's current
+	// version waits on its dependencies. Lost and failed allocations are
+	// still replaced.
+	dependenciesPending bool
+
 	// taintedNodes contains a map of nodes that are tainted
 	taintedNodes map[string]*structs.Node
 
@@ -470,10 +475,11 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 
 	// Determine how many non-canary allocs we can place
 	isCanarying = dstate != nil && dstate.DesiredCanaries != 0 && !dstate.Promoted
-	underProvisionedBy := a.computeUnderProvisionedBy(tg, untainted, destructive, migrate, isCanarying)
+	underProvisionedBy := a.computeUnderProvisionedBy(tg, dstate, untainted, destructive, migrate, isCanarying)
 
 	// Place if:
 	// * The deployment is not paused or failed
+	// * The job's dependencies are satisfied
 	// * Not placing any canaries
 	// * If there are any canaries that they have been promoted
 	// * There is no delayed stop_after_client_disconnect alloc, which delays scheduling for the whole group
@@ -489,7 +495,7 @@ func (a *allocReconciler) computeGroup(groupName string, all allocSet) bool {
 
 	// deploymentPlaceReady tracks whether the deployment is in a state where
 	// placements can be made without any other consideration.
-	deploymentPlaceReady := !a.deploymentPaused && !a.deploymentFailed && !isCanarying
+	deploymentPlaceReady := !a.deploymentPaused && !a.deploymentFailed && !isCanarying && !a.dependenciesPending
 
 	underProvisionedBy = a.computeReplacements(deploymentPlaceReady, desiredChanges, place, rescheduleNow, lost, underProvisionedBy)
 
@@ -522,6 +528,9 @@ func (a *allocReconciler) initializeDeploymentState(group string, tg *structs.Ta
 			dstate.AutoRevert = tg.Update.AutoRevert
 			dstate.AutoPromote = tg.Update.AutoPromote
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
+			for _, s := range tg.Update.Steps {
+				dstate.RolloutSteps = append(dstate.RolloutSteps, s.Copy())
+			}
 		}
 	}
 
@@ -541,7 +550,7 @@ func (a *allocReconciler) computeCanaries(tg *structs.TaskGroup, dstate *structs
 	destructive, canaries allocSet, desiredChanges *structs.DesiredUpdates, nameIndex *allocNameIndex) {
 	dstate.DesiredCanaries = tg.Update.Canary
 
-	if !a.deploymentPaused && !a.deploymentFailed {
+	if !a.deploymentPaused && !a.deploymentFailed && !a.dependenciesPending {
 		desiredChanges.Canary += uint64(tg.Update.Canary - len(canaries))
 		for _, name := range nameIndex.NextCanaries(uint(desiredChanges.Canary), canaries, destructive) {
 			a.result.place = append(a.result.place, allocPlaceResult{
@@ -635,7 +644,9 @@ func (a *allocReconciler) cancelUnneededCanaries(original allocSet, desiredChang
 // computeUnderProvisionedBy returns the number of allocs that still need to be
 // placed for a particular group. The inputs are the group definition, the untainted,
 // destructive, and migrate allocation sets, and whether we are in a canary state.
-func (a *allocReconciler) computeUnderProvisionedBy(group *structs.TaskGroup, untainted, destructive, migrate allocSet, isCanarying bool) int {
+// Rollouts with steps are limited to the allocations of their current step.
+func (a *allocReconciler) computeUnderProvisionedBy(group *structs.TaskGroup, dstate *structs.DeploymentState,
+	untainted, destructive, migrate allocSet, isCanarying bool) int {
 	// If no update strategy, nothing is migrating, and nothing is being replaced,
 	// allow as many as defined in group.Count
 	if group.Update.IsEmpty() || len(destructive)+len(migrate) == 0 {
@@ -644,6 +655,9 @@ func (a *allocReconciler) computeUnderProvisionedBy(group *structs.TaskGroup, un
 
 	// If the deployment is nil, allow MaxParallel placements
 	if a.deployment == nil {
+		if target, ok := dstate.StepTarget(); ok {
+			return helper.IntMin(group.Update.MaxParallel, target)
+		}
 		return group.Update.MaxParallel
 	}
 
@@ -667,6 +681,11 @@ func (a *allocReconciler) computeUnderProvisionedBy(group *structs.TaskGroup, un
 		}
 	}
 
+	// Don't update more allocations than the current rollout step allows
+	if target, ok := dstate.StepTarget(); ok {
+		underProvisionedBy = helper.IntMin(underProvisionedBy, target-len(partOf))
+	}
+
 	// The limit can be less than zero in the case that the job was changed such
 	// that it required destructive changes and the count was scaled up.
 	if underProvisionedBy < 0 {
@@ -1131,7 +1150,8 @@ func (a *allocReconciler) computeUpdates(group *structs.TaskGroup, untainted all
 
 	for _, alloc := range untainted {
 		ignoreChange, destructiveChange, inplaceAlloc := a.allocUpdateFn(alloc, a.job, group)
-		if ignoreChange {
+		if ignoreChange || a.dependenciesPending {
+			// Updates to the job's current version wait on its dependencies
 			ignore[alloc.ID] = alloc
 		} else if destructiveChange {
 			destructive[alloc.ID] = alloc
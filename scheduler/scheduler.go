@@ -90,6 +90,16 @@ type State interface {
 	// GetJobByID is used to lookup a job by ID
 	JobByID(ws memdb.WatchSet, namespace, id string) (*structs.Job, error)
 
+	// Jobs returns an iterator over all the jobs
+	Jobs(ws memdb.WatchSet) (memdb.ResultIterator, error)
+
+	// JobsByNamespace returns an iterator over all the jobs in a namespace
+	JobsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error)
+
+	// JobsByIDPrefix returns an iterator over the jobs in a namespace whose
+	// ID starts with the prefix
+	JobsByIDPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error)
+
 	// DeploymentsByJobID returns the deployments associated with the job
 	DeploymentsByJobID(ws memdb.WatchSet, namespace, jobID string, all bool) ([]*structs.Deployment, error)
 
@@ -111,6 +121,15 @@ type State interface {
 
 	// LatestIndex returns the greatest index value for all indexes.
 	LatestIndex() (uint64, error)
+
+	// NamespaceByName is used to lookup a namespace by name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
+	// QuotaSpecByName is used to lookup a quota specification by name
+	QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error)
+
+	// QuotaUsageByName is used to lookup the usage of a quota specification
+	QuotaUsageByName(ws memdb.WatchSet, name string) (*structs.QuotaUsage, error)
 }
 
 // Planner interface is used to submit a task allocation plan.
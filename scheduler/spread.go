@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"fmt"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -38,21 +40,35 @@ type SpreadIterator struct {
 	// existing allocs are computed once, and allocs from the plan are updated
 	// when Reset is called
 	groupPropertySets map[string][]*propertySet
+
+	// nodes is the set of base nodes, used to find the domains of spreads
+	// with a max skew
+	nodes []*structs.Node
+
+	// tgSpreadDomains is a map per task group with the domains of each
+	// spread attribute with a max skew
+	tgSpreadDomains map[string]map[string]spreadDomains
 }
 
 type spreadAttributeMap map[string]*spreadInfo
 
 type spreadInfo struct {
 	weight        int8
+	maxSkew       int
 	desiredCounts map[string]float64
 }
 
+// spreadDomains is the set of values of a spread attribute among the nodes
+// feasible for a task group, including values without any allocation.
+type spreadDomains map[string]struct{}
+
 func NewSpreadIterator(ctx Context, source RankIterator) *SpreadIterator {
 	iter := &SpreadIterator{
 		ctx:               ctx,
 		source:            source,
 		groupPropertySets: make(map[string][]*propertySet),
 		tgSpreadInfo:      make(map[string]spreadAttributeMap),
+		tgSpreadDomains:   make(map[string]map[string]spreadDomains),
 	}
 	return iter
 }
@@ -77,6 +93,14 @@ func (iter *SpreadIterator) SetJob(job *structs.Job) {
 	// versions of spread/properties to the new job version
 	iter.tgSpreadInfo = make(map[string]spreadAttributeMap)
 	iter.groupPropertySets = make(map[string][]*propertySet)
+	iter.tgSpreadDomains = make(map[string]map[string]spreadDomains)
+}
+
+// SetNodes sets the base nodes whose attribute values are the domains of
+// spreads with a max skew.
+func (iter *SpreadIterator) SetNodes(nodes []*structs.Node) {
+	iter.nodes = nodes
+	iter.tgSpreadDomains = make(map[string]map[string]spreadDomains)
 }
 
 func (iter *SpreadIterator) SetTaskGroup(tg *structs.TaskGroup) {
@@ -107,6 +131,10 @@ func (iter *SpreadIterator) SetTaskGroup(tg *structs.TaskGroup) {
 		iter.computeSpreadInfo(tg)
 	}
 
+	// Build the domains of spreads with a max skew
+	if _, ok := iter.tgSpreadDomains[tg.Name]; !ok {
+		iter.computeSpreadDomains(tg)
+	}
 }
 
 func (iter *SpreadIterator) hasSpreads() bool {
@@ -124,6 +152,13 @@ func (iter *SpreadIterator) Next() *RankedNode {
 
 		tgName := iter.tg.Name
 		propertySets := iter.groupPropertySets[tgName]
+
+		// Skip the node if placing on it would exceed the max skew of a spread
+		if reason := iter.exceedsMaxSkew(option.Node, propertySets); reason != "" {
+			iter.ctx.Metrics().FilterNode(option.Node, reason)
+			continue
+		}
+
 		// Iterate over each spread attribute's property set and add a weighted score
 		totalSpreadScore := 0.0
 		for _, pset := range propertySets {
@@ -188,6 +223,37 @@ func (iter *SpreadIterator) Next() *RankedNode {
 	}
 }
 
+// exceedsMaxSkew returns the reason the node is filtered when placing on it
+// would exceed the max skew of a spread, naming the attribute value which
+// blocked the placement. The skew is the count of the node's attribute value
+// after placing on it minus the lowest count among the spread's domains.
+func (iter *SpreadIterator) exceedsMaxSkew(option *structs.Node, propertySets []*propertySet) string {
+	tgName := iter.tg.Name
+	for _, pset := range propertySets {
+		spreadDetails := iter.tgSpreadInfo[tgName][pset.targetAttribute]
+		if spreadDetails == nil || spreadDetails.maxSkew == 0 {
+			continue
+		}
+
+		nValue, errorMsg, usedCount := pset.UsedCount(option, tgName)
+		if errorMsg != "" {
+			return fmt.Sprintf("spread max_skew: %s", errorMsg)
+		}
+
+		minCount := usedCount
+		combinedUseMap := pset.GetCombinedUseMap()
+		for domain := range iter.tgSpreadDomains[tgName][pset.targetAttribute] {
+			if count := combinedUseMap[domain]; count < minCount {
+				minCount = count
+			}
+		}
+		if usedCount+1-minCount > uint64(spreadDetails.maxSkew) {
+			return fmt.Sprintf("spread %s max_skew = %d: %q", pset.targetAttribute, spreadDetails.maxSkew, nValue)
+		}
+	}
+	return ""
+}
+
 // evenSpreadScoreBoost is a scoring helper that calculates the score
 // for the option when even spread is desired (all attribute values get equal preference)
 func evenSpreadScoreBoost(pset *propertySet, option *structs.Node) float64 {
@@ -253,7 +319,7 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	combinedSpreads = append(combinedSpreads, tg.Spreads...)
 	combinedSpreads = append(combinedSpreads, iter.jobSpreads...)
 	for _, spread := range combinedSpreads {
-		si := &spreadInfo{weight: spread.Weight, desiredCounts: make(map[string]float64)}
+		si := &spreadInfo{weight: spread.Weight, maxSkew: spread.MaxSkew, desiredCounts: make(map[string]float64)}
 		sumDesiredCounts := 0.0
 		for _, st := range spread.SpreadTarget {
 			desiredCount := (float64(st.Percent) / float64(100)) * float64(totalCount)
@@ -270,3 +336,45 @@ func (iter *SpreadIterator) computeSpreadInfo(tg *structs.TaskGroup) {
 	}
 	iter.tgSpreadInfo[tg.Name] = spreadInfos
 }
+
+// computeSpreadDomains computes and stores the domains of the spreads with a
+// max skew that apply to a specific task group. The domains are the values of
+// the spread attribute among the base nodes meeting the constraints and
+// drivers of the task group, so a value without allocations holds back the
+// others until it gets its share.
+func (iter *SpreadIterator) computeSpreadDomains(tg *structs.TaskGroup) {
+	domains := make(map[string]spreadDomains)
+	for attribute, si := range iter.tgSpreadInfo[tg.Name] {
+		if si.maxSkew > 0 {
+			domains[attribute] = make(spreadDomains)
+		}
+	}
+	iter.tgSpreadDomains[tg.Name] = domains
+	if len(domains) == 0 {
+		return
+	}
+
+	tgConstr := taskGroupConstraints(tg)
+	constraints := make([]*structs.Constraint, 0, len(iter.job.Constraints)+len(tgConstr.constraints))
+	constraints = append(constraints, iter.job.Constraints...)
+	constraints = append(constraints, tgConstr.constraints...)
+	constraintChecker := NewConstraintChecker(iter.ctx, constraints)
+	driverChecker := NewDriverChecker(iter.ctx, tgConstr.drivers)
+
+NODES:
+	for _, node := range iter.nodes {
+		if !driverChecker.hasDrivers(node) {
+			continue
+		}
+		for _, constraint := range constraints {
+			if !constraintChecker.meetsConstraint(constraint, node) {
+				continue NODES
+			}
+		}
+		for attribute, values := range domains {
+			if value, ok := getProperty(node, attribute); ok {
+				values[value] = struct{}{}
+			}
+		}
+	}
+}
@@ -558,6 +558,77 @@ func TestSpreadIterator_MaxPenalty(t *testing.T) {
 
 }
 
+func TestSpreadIterator_MaxSkew(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+
+	// Nodes across datacenters and racks, the last one in a datacenter
+	// without allocations
+	domains := [][]string{{"dc1", "r1"}, {"dc1", "r2"}, {"dc2", "r1"}, {"dc2", "r2"}, {"dc3", "r1"}}
+	var nodes []*RankedNode
+	var baseNodes []*structs.Node
+	for i, domain := range domains {
+		node := mock.Node()
+		node.Datacenter = domain[0]
+		node.Meta["rack"] = domain[1]
+		require.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, &RankedNode{Node: node})
+		baseNodes = append(baseNodes, node)
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Spreads = []*structs.Spread{
+		{Attribute: "${node.datacenter}", Weight: 50, MaxSkew: 1},
+		{Attribute: "${meta.rack}", Weight: 50, MaxSkew: 1},
+	}
+
+	// Existing allocations on dc1/r1 and dc2/r2, proposed on dc1/r2
+	var allocs []*structs.Allocation
+	for _, i := range []int{0, 3} {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = nodes[i].Node.ID
+		allocs = append(allocs, alloc)
+	}
+	require.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	proposed := mock.Alloc()
+	proposed.Job = job
+	proposed.JobID = job.ID
+	proposed.NodeID = nodes[1].Node.ID
+	ctx.plan.NodeAllocation[proposed.NodeID] = []*structs.Allocation{proposed}
+
+	selected := func(baseNodes []*structs.Node) []string {
+		static := NewStaticRankIterator(ctx, nodes)
+		spreadIter := NewSpreadIterator(ctx, static)
+		spreadIter.SetJob(job)
+		spreadIter.SetNodes(baseNodes)
+		spreadIter.SetTaskGroup(tg)
+
+		var out []string
+		for _, rn := range collectRanked(spreadIter) {
+			out = append(out, rn.Node.Datacenter+"/"+rn.Node.Meta["rack"])
+		}
+		return out
+	}
+
+	// Without dc3 the counts are dc1=2, dc2=1 and r1=1, r2=2, so only a
+	// placement on dc2/r1 keeps the skew of both spreads within 1
+	ctx.Reset()
+	require.Equal(t, []string{"dc2/r1", "dc3/r1"}, selected(baseNodes[:4]))
+
+	// dc3 has no allocations, which holds back dc1 and dc2
+	ctx.Reset()
+	require.Equal(t, []string{"dc3/r1"}, selected(baseNodes))
+
+	filtered := ctx.Metrics().ConstraintFiltered
+	require.Equal(t, 2, filtered[`spread ${node.datacenter} max_skew = 1: "dc1"`])
+	require.Equal(t, 2, filtered[`spread ${node.datacenter} max_skew = 1: "dc2"`])
+}
+
 func Test_evenSpreadScoreBoost(t *testing.T) {
 	ci.Parallel(t)
 
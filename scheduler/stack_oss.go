@@ -3,6 +3,132 @@
 
 package scheduler
 
-func NewQuotaIterator(_ Context, source FeasibleIterator) FeasibleIterator {
-	return source
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// QuotaIterator is a FeasibleIterator which returns no nodes if placing the
+// task group would exceed the limits of the quota attached to the namespace
+// of the job. It must be the last feasibility iterator, so that the nodes it
+// yields are only those which the task group could otherwise be placed on.
+type QuotaIterator struct {
+	ctx    Context
+	source FeasibleIterator
+
+	// quota is the name of the quota attached to the job's namespace, limit
+	// its limit in the local region and used its current usage. The limit is
+	// nil if the job is not subject to a quota.
+	quota string
+	limit *structs.QuotaLimit
+	used  *structs.QuotaLimit
+
+	tg *structs.TaskGroup
+
+	// checked marks whether the quota has been checked for the current
+	// selection, and exhausted whether it was exceeded.
+	checked   bool
+	exhausted bool
+}
+
+// NewQuotaIterator returns a QuotaIterator wrapping the source iterator.
+func NewQuotaIterator(ctx Context, source FeasibleIterator) FeasibleIterator {
+	return &QuotaIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *QuotaIterator) SetJob(job *structs.Job) {
+	iter.quota = ""
+	iter.limit = nil
+	iter.used = nil
+
+	state := iter.ctx.State()
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		iter.ctx.Logger().Error("failed to lookup namespace", "namespace", job.Namespace, "error", err)
+		return
+	}
+	if ns == nil || ns.Quota == "" {
+		return
+	}
+
+	spec, err := state.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		iter.ctx.Logger().Error("failed to lookup quota", "quota", ns.Quota, "error", err)
+		return
+	}
+	if spec == nil {
+		return
+	}
+	limit := spec.RegionLimit(state.Config().Region)
+	if limit == nil {
+		return
+	}
+
+	usage, err := state.QuotaUsageByName(nil, ns.Quota)
+	if err != nil {
+		iter.ctx.Logger().Error("failed to lookup quota usage", "quota", ns.Quota, "error", err)
+		return
+	}
+
+	used := limit.NewUsage()
+	if usage != nil {
+		if u, ok := usage.Used[limit.UsageKey()]; ok {
+			used = u
+		}
+	}
+
+	iter.quota = spec.Name
+	iter.limit = limit
+	iter.used = used
+}
+
+func (iter *QuotaIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.tg = tg
+	iter.checked = false
+	iter.exhausted = false
+}
+
+func (iter *QuotaIterator) Next() *structs.Node {
+	if iter.limit == nil || iter.tg == nil {
+		return iter.source.Next()
+	}
+
+	if !iter.checked {
+		iter.checked = true
+		iter.exhausted = !iter.fits()
+	}
+	if iter.exhausted {
+		return nil
+	}
+	return iter.source.Next()
+}
+
+// fits returns whether placing the task group, in addition to the current
+// usage and the allocations already planned, fits within the quota limit.
+// If not, the exhausted dimensions are recorded in the metrics and the
+// evaluation is marked as having reached the quota limit.
+func (iter *QuotaIterator) fits() bool {
+	used := iter.used.Copy()
+	state := iter.ctx.State()
+	existingFn := func(allocID string) (*structs.Allocation, error) {
+		return state.AllocByID(nil, allocID)
+	}
+	if err := used.AddPlan(iter.ctx.Plan(), existingFn); err != nil {
+		iter.ctx.Logger().Error("failed to compute planned quota usage", "quota", iter.quota, "error", err)
+		return false
+	}
+	used.AddTaskGroup(iter.tg)
+
+	ok, dimensions := iter.limit.Superset(used)
+	if !ok {
+		iter.ctx.Metrics().ExhaustQuota(dimensions)
+		iter.ctx.Eligibility().SetQuotaLimitReached(iter.quota)
+	}
+	return ok
+}
+
+func (iter *QuotaIterator) Reset() {
+	iter.source.Reset()
 }
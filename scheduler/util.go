@@ -576,16 +576,18 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 			return true
 		}
 
-		// Inspect the non-network resources
-		if ar, br := at.Resources, bt.Resources; ar.CPU != br.CPU {
+		// Inspect the non-network resources. CPU and memory changes are
+		// in-place updates, as the client resizes the running tasks. The
+		// in-place update is only made if the new resources fit on the node.
+		if ar, br := at.Resources, bt.Resources; ar.Cores != br.Cores {
 			return true
-		} else if ar.Cores != br.Cores {
+		} else if !ar.Devices.Equals(&br.Devices) {
 			return true
-		} else if ar.MemoryMB != br.MemoryMB {
+		} else if ar.NUMAAffinity() != br.NUMAAffinity() {
 			return true
-		} else if ar.MemoryMaxMB != br.MemoryMaxMB {
+		} else if !ar.DiskIO.Equals(br.DiskIO) {
 			return true
-		} else if !ar.Devices.Equals(&br.Devices) {
+		} else if ar.EgressMbits != br.EgressMbits {
 			return true
 		}
 	}
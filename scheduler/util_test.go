@@ -728,9 +728,12 @@ func TestTasksUpdated(t *testing.T) {
 	j10.TaskGroups[0].Tasks[0].Meta["baz"] = "boom"
 	require.True(t, tasksUpdated(j1, j10, name))
 
+	// CPU and memory changes are in-place updates
 	j11 := mock.Job()
 	j11.TaskGroups[0].Tasks[0].Resources.CPU = 1337
-	require.True(t, tasksUpdated(j1, j11, name))
+	j11.TaskGroups[0].Tasks[0].Resources.MemoryMB = 1024
+	j11.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB = 2048
+	require.False(t, tasksUpdated(j1, j11, name))
 
 	j11d1 := mock.Job()
 	j11d1.TaskGroups[0].Tasks[0].Resources.Devices = structs.ResourceDevices{
@@ -837,6 +840,21 @@ func TestTasksUpdated(t *testing.T) {
 	j28 := j27.Copy()
 	j28.TaskGroups[0].Tasks[0].CSIPluginConfig.Type = "monolith"
 	require.True(t, tasksUpdated(j27, j28, name))
+
+	// Change NUMA affinity
+	j29 := j20.Copy()
+	j29.TaskGroups[0].Tasks[0].Resources.NUMA = &structs.NUMA{Affinity: structs.NUMAAffinityRequire}
+	require.True(t, tasksUpdated(j20, j29, name))
+
+	// Change disk IO and egress bandwidth
+	j30 := mock.Job()
+	j31 := j30.Copy()
+	j31.TaskGroups[0].Tasks[0].Resources.DiskIO = &structs.DiskIO{ReadIOPS: 100}
+	require.True(t, tasksUpdated(j30, j31, name))
+
+	j32 := j30.Copy()
+	j32.TaskGroups[0].Tasks[0].Resources.EgressMbits = 100
+	require.True(t, tasksUpdated(j30, j32, name))
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {